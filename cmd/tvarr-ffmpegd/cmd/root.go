@@ -104,6 +104,13 @@ func setDaemonDefaults() {
 	// Env vars: TVARR_DAEMON_PROFILING_PPROF, TVARR_DAEMON_PROFILING_PPROF_PORT
 	daemonViper.SetDefault("daemon.profiling.pprof", false)
 	daemonViper.SetDefault("daemon.profiling.pprof_port", 6060)
+
+	// Tracing defaults - use daemon.tracing.* to avoid conflict with tvarr in all-in-one image
+	// Env vars: TVARR_DAEMON_TRACING_ENABLED, TVARR_DAEMON_TRACING_ENDPOINT, ...
+	daemonViper.SetDefault("daemon.tracing.enabled", false)
+	daemonViper.SetDefault("daemon.tracing.endpoint", "localhost:4317")
+	daemonViper.SetDefault("daemon.tracing.insecure", true)
+	daemonViper.SetDefault("daemon.tracing.sample_ratio", 1.0)
 }
 
 // initLogging configures the slog logger for the daemon.
@@ -12,7 +12,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmylchreest/tvarr/internal/config"
 	"github.com/jmylchreest/tvarr/internal/daemon"
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -80,6 +82,26 @@ func runServe(cmd *cobra.Command, _ []string) error {
 
 	v := GetDaemonViper()
 
+	// Export traces to an OTLP collector if enabled. Jobs and probes continue
+	// the coordinator's traces. Uses daemon.tracing.* to avoid conflict with
+	// tvarr in all-in-one image.
+	shutdownTracing, err := observability.InitTracing(context.Background(), config.TracingConfig{
+		Enabled:     v.GetBool("daemon.tracing.enabled"),
+		Endpoint:    v.GetString("daemon.tracing.endpoint"),
+		Insecure:    v.GetBool("daemon.tracing.insecure"),
+		SampleRatio: v.GetFloat64("daemon.tracing.sample_ratio"),
+	}, "tvarr-ffmpegd", versionInfo.Version)
+	if err != nil {
+		return fmt.Errorf("initializing tracing: %w", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warn("failed to flush traces", slog.String("error", err.Error()))
+		}
+	}()
+
 	// Start pprof server if enabled (check CLI flag first, then config/env)
 	// Uses daemon.profiling.* to avoid conflict with tvarr in all-in-one image
 	// Env vars: TVARR_DAEMON_PROFILING_PPROF, TVARR_DAEMON_PROFILING_PPROF_PORT
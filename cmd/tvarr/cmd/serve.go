@@ -8,23 +8,30 @@ import (
 	_ "net/http/pprof" //nolint:gosec // G108: Intentional pprof exposure for debugging
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"gorm.io/gorm"
 
+	"github.com/jmylchreest/tvarr/internal/auth"
 	"github.com/jmylchreest/tvarr/internal/config"
 	"github.com/jmylchreest/tvarr/internal/database"
 	"github.com/jmylchreest/tvarr/internal/database/migrations"
 	"github.com/jmylchreest/tvarr/internal/ffmpeg"
+	"github.com/jmylchreest/tvarr/internal/hdhomerun"
 	internalhttp "github.com/jmylchreest/tvarr/internal/http"
 	"github.com/jmylchreest/tvarr/internal/http/handlers"
+	"github.com/jmylchreest/tvarr/internal/http/middleware"
 	"github.com/jmylchreest/tvarr/internal/ingestor"
 	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/notify"
 	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/pipeline"
+	"github.com/jmylchreest/tvarr/internal/pipeline/stages/epgmatch"
 	"github.com/jmylchreest/tvarr/internal/relay"
 	"github.com/jmylchreest/tvarr/internal/repository"
 	"github.com/jmylchreest/tvarr/internal/scheduler"
@@ -68,6 +75,7 @@ func init() {
 	serveCmd.Flags().Duration("scheduler-sync-interval", time.Minute, "Interval for syncing schedules from database")
 	serveCmd.Flags().Int("scheduler-workers", 2, "Number of concurrent job workers")
 	serveCmd.Flags().String("logo-scan-schedule", scheduler.DefaultLogoScanSchedule, "Cron schedule for logo scan job (6-field: sec min hour dom month dow). 7-field with year also accepted for legacy. Empty to disable.")
+	serveCmd.Flags().String("account-check-schedule", scheduler.DefaultAccountCheckSchedule, "Cron schedule for checking Xtream source accounts (6-field: sec min hour dom month dow). Empty to disable.")
 	serveCmd.Flags().Duration("job-history-retention", 14*24*time.Hour, "Retention period for job history records (older records are deleted on startup)")
 
 	// gRPC server flags (for ffmpegd daemon registration)
@@ -78,6 +86,9 @@ func init() {
 	// Relay flags
 	serveCmd.Flags().Bool("prefer-remote-probe", false, "Prefer remote daemons for stream probing (ffprobe) even when local ffprobe is available")
 
+	// HDHomeRun flags
+	serveCmd.Flags().Bool("hdhomerun-discovery", false, "Enable SSDP and HDHomeRun UDP discovery of per-proxy emulated tuners")
+
 	// Profiling flags
 	serveCmd.Flags().Bool("pprof", false, "Enable pprof profiling server")
 	serveCmd.Flags().Int("pprof-port", 6060, "Port for pprof profiling server")
@@ -92,11 +103,13 @@ func init() {
 	mustBindPFlag("scheduler.sync_interval", serveCmd.Flags().Lookup("scheduler-sync-interval"))
 	mustBindPFlag("scheduler.workers", serveCmd.Flags().Lookup("scheduler-workers"))
 	mustBindPFlag("scheduler.logo_scan_schedule", serveCmd.Flags().Lookup("logo-scan-schedule"))
+	mustBindPFlag("scheduler.account_check_schedule", serveCmd.Flags().Lookup("account-check-schedule"))
 	mustBindPFlag("scheduler.job_history_retention", serveCmd.Flags().Lookup("job-history-retention"))
 	mustBindPFlag("grpc.enabled", serveCmd.Flags().Lookup("grpc-enabled"))
 	mustBindPFlag("grpc.port", serveCmd.Flags().Lookup("grpc-port"))
 	mustBindPFlag("grpc.auth_token", serveCmd.Flags().Lookup("grpc-auth-token"))
 	mustBindPFlag("relay.prefer_remote_probe", serveCmd.Flags().Lookup("prefer-remote-probe"))
+	mustBindPFlag("hdhomerun.discovery", serveCmd.Flags().Lookup("hdhomerun-discovery"))
 	mustBindPFlag("profiling.pprof", serveCmd.Flags().Lookup("pprof"))
 	mustBindPFlag("profiling.pprof_port", serveCmd.Flags().Lookup("pprof-port"))
 }
@@ -118,7 +131,7 @@ func runServe(_ *cobra.Command, _ []string) error {
 	observability.SetRequestLogging(viper.GetBool("logging.request_logging"))
 
 	// Initialize logs service and wrap the default slog handler
-	logsService := logs.New()
+	logsService := logs.New().WithRedactor(observability.RedactAttr)
 	wrappedHandler := logsService.WrapHandler(slog.Default().Handler())
 	slog.SetDefault(slog.New(wrappedHandler))
 
@@ -134,6 +147,29 @@ func runServe(_ *cobra.Command, _ []string) error {
 		slog.String("platform", versionInfo.Platform),
 	)
 
+	// Export traces to an OTLP collector if enabled
+	shutdownTracing, err := observability.InitTracing(context.Background(), config.TracingConfig{
+		Enabled:     viper.GetBool("tracing.enabled"),
+		Endpoint:    viper.GetString("tracing.endpoint"),
+		Insecure:    viper.GetBool("tracing.insecure"),
+		SampleRatio: viper.GetFloat64("tracing.sample_ratio"),
+	}, "tvarr", versionInfo.Version)
+	if err != nil {
+		return fmt.Errorf("initializing tracing: %w", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warn("failed to flush traces", slog.String("error", err.Error()))
+		}
+	}()
+	if viper.GetBool("tracing.enabled") {
+		logger.Info("tracing enabled",
+			slog.String("endpoint", viper.GetString("tracing.endpoint")),
+			slog.Float64("sample_ratio", viper.GetFloat64("tracing.sample_ratio")))
+	}
+
 	// Start pprof server if enabled (shutdown is handled after ctx is created below)
 	var pprofServer *http.Server
 	if viper.GetBool("profiling.pprof") {
@@ -184,6 +220,19 @@ func runServe(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("running migrations: %w", err)
 	}
 
+	// Persist logs to the database so they can be queried after a restart.
+	// Closed last, so logs from shutting down the other services are stored.
+	if viper.GetBool("logging.store.enabled") {
+		if err := logsService.StartStore(repository.NewLogEntryRepository(db.DB), logs.StoreConfig{
+			Level:      viper.GetString("logging.store.level"),
+			Retention:  viper.GetDuration("logging.store.retention"),
+			MaxEntries: viper.GetInt("logging.store.max_entries"),
+		}); err != nil {
+			return fmt.Errorf("starting log store: %w", err)
+		}
+		defer logsService.Close()
+	}
+
 	// Detect FFmpeg and log capabilities on startup
 	ffmpegDetector := ffmpeg.NewBinaryDetector()
 	ffmpegInfo, err := ffmpegDetector.Detect(context.Background())
@@ -225,6 +274,9 @@ func runServe(_ *cobra.Command, _ []string) error {
 	manualChannelRepo := repository.NewManualChannelRepository(db.DB)
 	epgSourceRepo := repository.NewEpgSourceRepository(db.DB)
 	epgProgramRepo := repository.NewEpgProgramRepository(db.DB)
+	epgChannelRepo := repository.NewEpgChannelRepository(db.DB)
+	vodRepo := repository.NewVodRepository(db.DB)
+	seriesRepo := repository.NewSeriesRepository(db.DB)
 	proxyRepo := repository.NewStreamProxyRepository(db.DB)
 	filterRepo := repository.NewFilterRepository(db.DB)
 	dataMappingRuleRepo := repository.NewDataMappingRuleRepository(db.DB)
@@ -233,6 +285,15 @@ func runServe(_ *cobra.Command, _ []string) error {
 	clientDetectionRuleRepo := repository.NewClientDetectionRuleRepository(db.DB)
 	encoderOverrideRepo := repository.NewEncoderOverrideRepository(db.DB)
 	jobRepo := repository.NewJobRepository(db.DB)
+	userRepo := repository.NewUserRepository(db.DB)
+	authSessionRepo := repository.NewAuthSessionRepository(db.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.DB)
+	viewerRepo := repository.NewViewerRepository(db.DB)
+	recordingRepo := repository.NewRecordingRepository(db.DB)
+	recordingRuleRepo := repository.NewRecordingRuleRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	viewingSessionRepo := repository.NewViewingSessionRepository(db.DB)
+	auditEventRepo := repository.NewAuditEventRepository(db.DB)
 
 	// Clean up old job history on startup if retention is configured
 	jobHistoryRetention := viper.GetDuration("scheduler.job_history_retention")
@@ -344,6 +405,15 @@ func runServe(_ *cobra.Command, _ []string) error {
 		logoService, // Logo caching enabled
 		ingestionGuardStateManager,
 		jobRepo, // Pending job checking for ingestion guard
+		lastKnownCodecRepo,
+		proxyRepo, // Channel alternates from deduplication
+		epgChannelRepo,
+		epgmatch.Thresholds{
+			Auto:      viper.GetFloat64("pipeline.epg_match_threshold"),
+			Candidate: viper.GetFloat64("pipeline.epg_match_candidate_threshold"),
+		},
+		vodRepo,    // Movie catalogues for VOD playlists
+		seriesRepo, // Series catalogues for VOD playlists
 		baseURL,
 	)
 
@@ -362,7 +432,8 @@ func runServe(_ *cobra.Command, _ []string) error {
 		WithLogger(logger).
 		WithProgressService(progressService).
 		WithEPGSourceRepo(epgSourceRepo).
-		WithEPGChecker(service.NewDefaultEPGChecker())
+		WithEPGChecker(service.NewDefaultEPGChecker()).
+		WithVodRepos(vodRepo, seriesRepo)
 
 	epgService := service.NewEpgService(
 		epgSourceRepo,
@@ -370,13 +441,20 @@ func runServe(_ *cobra.Command, _ []string) error {
 		epgHandlerFactory,
 		stateManager,
 	).WithLogger(logger).WithProgressService(progressService).
-		WithStreamSourceRepo(streamSourceRepo)
+		WithStreamSourceRepo(streamSourceRepo).
+		WithEpgChannelRepo(epgChannelRepo)
 
 	proxyService := service.NewProxyService(
 		proxyRepo,
 		pipelineFactory,
 	).WithLogger(logger).WithProgressService(progressService)
 
+	// Timeshift segments live under the storage directory unless an absolute path is configured
+	timeshiftDir := viper.GetString("relay.hls.timeshift.directory")
+	if !filepath.IsAbs(timeshiftDir) {
+		timeshiftDir = filepath.Join(viper.GetString("storage.base_dir"), timeshiftDir)
+	}
+
 	relayService := service.NewRelayService(
 		encodingProfileRepo,
 		lastKnownCodecRepo,
@@ -388,7 +466,89 @@ func runServe(_ *cobra.Command, _ []string) error {
 		TargetSegmentDuration: viper.GetFloat64("relay.hls.target_segment_duration"),
 		MaxSegments:           viper.GetInt("relay.hls.max_segments"),
 		PlaylistSegments:      viper.GetInt("relay.hls.playlist_segments"),
+		Timeshift: config.TimeshiftConfig{
+			Window:    viper.GetDuration("relay.hls.timeshift.window"),
+			Directory: timeshiftDir,
+		},
+	}).WithFailover(viper.GetBool("relay.failover")).
+		WithStreamURLResolver(streamHandlerFactory) // Portal links are issued at play time
+
+	// Initialize notifications. Failures reported through progress, the job
+	// executor, circuit breakers and the daemon registry are delivered to the
+	// configured targets.
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		notify.NewSender(notify.SMTPConfig{
+			Host:     viper.GetString("notifications.smtp.host"),
+			Port:     viper.GetInt("notifications.smtp.port"),
+			Username: viper.GetString("notifications.smtp.username"),
+			Password: viper.GetString("notifications.smtp.password"),
+			From:     viper.GetString("notifications.smtp.from"),
+			TLS:      viper.GetString("notifications.smtp.tls"),
+		}),
+	).WithLogger(logger).WithConfig(config.NotificationsConfig{
+		MaxAttempts:       viper.GetInt("notifications.max_attempts"),
+		RetryDelay:        viper.GetDuration("notifications.retry_delay"),
+		Cooldown:          viper.GetDuration("notifications.cooldown"),
+		DeliveryRetention: viper.GetDuration("notifications.delivery_retention"),
+	})
+	notificationService.Start()
+	defer notificationService.Close()
+	notificationService.WatchProgress(progressService)
+
+	// Initialize viewing analytics. Relay clients leaving a session are
+	// recorded as viewing sessions once the relay manager hook is set below.
+	viewingService := service.NewViewingService(viewingSessionRepo).
+		WithLogger(logger).
+		WithConfig(config.AnalyticsConfig{
+			Retention: viper.GetDuration("analytics.retention"),
+		})
+	viewingService.Start()
+	defer viewingService.Close()
+
+	// Initialize viewer accounts. With stream auth enabled, playlists, tuners and
+	// relay URLs require a viewer token (or a signed URL issued to a viewer).
+	streamAuthEnabled := viper.GetBool("stream_auth.enabled")
+	viewerService := service.NewViewerService(viewerRepo).WithLogger(logger)
+	if streamAuthEnabled {
+		var signingKey []byte
+		if configured := viper.GetString("stream_auth.signing_key"); configured != "" {
+			signingKey = []byte(configured)
+		} else {
+			signingKey, err = auth.LoadOrCreateSigningKey(filepath.Join(viper.GetString("storage.base_dir"), "stream_signing.key"))
+			if err != nil {
+				return fmt.Errorf("loading stream signing key: %w", err)
+			}
+		}
+		viewerService.WithSigning(signingKey, viper.GetBool("stream_auth.sign_urls"), viper.GetDuration("stream_auth.signed_url_ttl"))
+		relayService.WithStreamAuthenticator(viewerService)
+		logger.Info("stream authentication enabled",
+			slog.Bool("signed_urls", viper.GetBool("stream_auth.sign_urls")))
+	}
+
+	// Initialize DVR recordings. Recordings join the channel's relay session as
+	// a client, so they share the upstream connection with live viewers.
+	recordingService := service.NewRecordingService(
+		recordingRepo,
+		channelRepo,
+		epgProgramRepo,
+		jobRepo,
+		sandbox,
+	).WithLogger(logger).WithRelay(relayService).WithConfig(config.RecordingConfig{
+		Directory:     viper.GetString("recording.directory"),
+		PaddingBefore: viper.GetDuration("recording.padding_before"),
+		PaddingAfter:  viper.GetDuration("recording.padding_after"),
+		Format:        viper.GetString("recording.format"),
 	})
+	defer recordingService.Close()
+	recordingRuleService := service.NewRecordingRuleService(
+		recordingRuleRepo,
+		recordingService,
+		channelRepo,
+		epgProgramRepo,
+		streamSourceRepo,
+		epgSourceRepo,
+	).WithLogger(logger)
 
 	encodingProfileService := service.NewEncodingProfileService(encodingProfileRepo).
 		WithLogger(logger)
@@ -407,6 +567,59 @@ func runServe(_ *cobra.Command, _ []string) error {
 		logger.Warn("failed to refresh encoder overrides cache", slog.String("error", err.Error()))
 	}
 
+	// Audit trail of configuration changes. Repositories are used for reads
+	// since they return nil for missing entities; writes go through the
+	// services where they validate or refresh caches.
+	auditService := service.NewAuditService(auditEventRepo).WithLogger(logger).
+		Register(models.AuditEntityStreamSource, service.AuditEntityFuncs[models.StreamSource]{
+			Get:       streamSourceRepo.GetByID,
+			Create:    sourceService.Create,
+			Update:    sourceService.Update,
+			Relations: []string{"channels"},
+			Volatile: []string{
+				"status", "last_ingestion_at", "last_error", "channel_count", "vod_count", "series_count",
+				"account_status", "account_expires_at", "account_max_connections",
+				"account_active_connections", "account_checked_at",
+			},
+		}).
+		Register(models.AuditEntityEpgSource, service.AuditEntityFuncs[models.EpgSource]{
+			Get:      epgSourceRepo.GetByID,
+			Create:   epgService.Create,
+			Update:   epgService.Update,
+			Volatile: []string{"status", "last_ingestion_at", "last_error", "program_count", "detected_timezone"},
+		}).
+		Register(models.AuditEntityStreamProxy, proxyService.AuditEntity()).
+		Register(models.AuditEntityFilter, service.AuditEntityFuncs[models.Filter]{
+			Get:         filterRepo.GetByID,
+			Create:      filterRepo.Create,
+			Update:      filterRepo.Update,
+			SoftDeleted: true,
+		}).
+		Register(models.AuditEntityDataMappingRule, service.AuditEntityFuncs[models.DataMappingRule]{
+			Get:         dataMappingRuleRepo.GetByID,
+			Create:      dataMappingRuleRepo.Create,
+			Update:      dataMappingRuleRepo.Update,
+			SoftDeleted: true,
+		}).
+		Register(models.AuditEntityClientDetectionRule, service.AuditEntityFuncs[models.ClientDetectionRule]{
+			Get:         clientDetectionRuleRepo.GetByID,
+			Create:      clientDetectionService.Create,
+			Update:      clientDetectionService.Update,
+			Relations:   []string{"encoding_profile"},
+			SoftDeleted: true,
+		}).
+		Register(models.AuditEntityEncodingProfile, service.AuditEntityFuncs[models.EncodingProfile]{
+			Get:    encodingProfileRepo.GetByID,
+			Create: encodingProfileService.Create,
+			Update: encodingProfileService.Update,
+		}).
+		Register(models.AuditEntityEncoderOverride, service.AuditEntityFuncs[models.EncoderOverride]{
+			Get:         encoderOverrideRepo.GetByID,
+			Create:      encoderOverrideService.Create,
+			Update:      encoderOverrideService.Update,
+			SoftDeleted: true,
+		})
+
 	logger.Info("core services initialized")
 
 	// Initialize backup service (needed for both scheduler and HTTP handler)
@@ -433,6 +646,15 @@ func runServe(_ *cobra.Command, _ []string) error {
 		})
 	}
 
+	accountCheckSchedule := viper.GetString("scheduler.account_check_schedule")
+	if accountCheckSchedule != "" {
+		internalJobs = append(internalJobs, scheduler.InternalJobConfig{
+			JobType:      models.JobTypeAccountCheck,
+			TargetName:   "Xtream Account Check",
+			CronSchedule: accountCheckSchedule,
+		})
+	}
+
 	// Note: Backup job is added dynamically after scheduler starts to use DB settings
 	// See the code block after sched.Start(ctx) below
 
@@ -452,6 +674,7 @@ func runServe(_ *cobra.Command, _ []string) error {
 
 	// Create job executor with handlers
 	executor := scheduler.NewExecutor(jobRepo).WithLogger(logger)
+	executor.OnJobFinished(notificationService.HandleJobFinished)
 
 	// Register stream ingestion handler with auto-regeneration
 	streamIngestionHandler := scheduler.NewStreamIngestionHandler(sourceService).
@@ -459,9 +682,10 @@ func runServe(_ *cobra.Command, _ []string) error {
 		WithLogger(logger)
 	executor.RegisterHandler(models.JobTypeStreamIngestion, streamIngestionHandler)
 
-	// Register EPG ingestion handler with auto-regeneration
+	// Register EPG ingestion handler with auto-regeneration and series recording rules
 	epgIngestionHandler := scheduler.NewEpgIngestionHandler(epgService).
 		WithAutoRegeneration(autoRegenService).
+		WithRecordingRules(recordingRuleService).
 		WithLogger(logger)
 	executor.RegisterHandler(models.JobTypeEpgIngestion, epgIngestionHandler)
 
@@ -487,6 +711,17 @@ func runServe(_ *cobra.Command, _ []string) error {
 	backupJobHandler := scheduler.NewBackupJobHandler(&backupServiceAdapter{backupService}).WithLogger(logger)
 	executor.RegisterHandler(models.JobTypeBackup, backupJobHandler)
 
+	// Register Xtream account check handler. External connections reported by
+	// the provider count against relay connection limits.
+	accountService := service.NewXtreamAccountService(streamSourceRepo).
+		WithLogger(logger).
+		WithConnectionTracker(relayService).
+		WithExpiryWarning(viper.GetDuration("scheduler.account_expiry_warning"))
+	executor.RegisterHandler(models.JobTypeAccountCheck, scheduler.NewAccountCheckHandler(accountService))
+
+	// Register recording handler
+	executor.RegisterHandler(models.JobTypeRecording, scheduler.NewRecordingJobHandler(recordingService))
+
 	// Create job runner
 	runner := scheduler.NewRunner(jobRepo, executor).
 		WithLogger(logger).
@@ -508,6 +743,32 @@ func runServe(_ *cobra.Command, _ []string) error {
 	}
 	server := internalhttp.NewServer(serverConfig, logger, version.Version)
 
+	// Initialize authentication. The huma middleware must be added before any
+	// operation is registered; raw chi API routes are wrapped via apiRouter.
+	authEnabled := viper.GetBool("auth.enabled")
+	authService := service.NewAuthService(userRepo, authSessionRepo, apiKeyRepo).
+		WithLogger(logger).
+		WithSessionTTL(viper.GetDuration("auth.session_ttl"))
+	var apiRouter chi.Router = server.Router()
+	if authEnabled {
+		adminUsername := viper.GetString("auth.admin_username")
+		generated, err := authService.EnsureAdminUser(context.Background(), adminUsername, viper.GetString("auth.admin_password"))
+		if err != nil {
+			return fmt.Errorf("initializing admin user: %w", err)
+		}
+		if generated != "" {
+			logger.Warn("generated initial admin password; change it after first login",
+				slog.String("username", adminUsername),
+				slog.String("password", generated))
+		}
+		server.API().UseMiddleware(middleware.NewHumaAuth(server.API(), authService, logger))
+		apiRouter = server.Router().With(middleware.RequireAuth(authService, logger))
+		logger.Info("API authentication enabled",
+			slog.String("api_key_header", auth.APIKeyHeader))
+	} else {
+		logger.Warn("API authentication is disabled; anyone who can reach the server can change its configuration (set auth.enabled to protect it)")
+	}
+
 	// Register OpenAPI docs handler with system theme detection (dark/light)
 	docsHandler := handlers.NewDocsHandler("tvarr API", "/openapi.yaml", handlers.WithSystemTheme())
 	server.Router().Get("/docs", docsHandler.ServeHTTP)
@@ -518,8 +779,30 @@ func runServe(_ *cobra.Command, _ []string) error {
 
 	// Register output file server for serving M3U and XMLTV files at /proxy/{id}.m3u and /proxy/{id}.xmltv
 	outputHandler := handlers.NewOutputHandler(sandbox).WithLogger(logger)
+	if streamAuthEnabled {
+		outputHandler.WithViewerAuth(viewerService)
+	}
 	outputHandler.RegisterFileServer(server.Router())
 
+	// Register HDHomeRun tuner emulation at /hdhr/{id}/discover.json, lineup.json, etc.
+	hdhomerunHandler := handlers.NewHDHomeRunHandler(proxyService, sandbox).
+		WithLogger(logger).
+		WithBaseURL(urlutil.NormalizeBaseURL(viper.GetString("server.base_url"))).
+		WithDefaultTunerCount(viper.GetInt("hdhomerun.default_tuner_count"))
+	if streamAuthEnabled {
+		hdhomerunHandler.WithViewerAuth(viewerService)
+	}
+	hdhomerunHandler.RegisterChiRoutes(server.Router())
+
+	// Register Xtream Codes compatible output at /player_api.php, /get.php, /xmltv.php and /live/...
+	xtreamOutputHandler := handlers.NewXtreamOutputHandler(proxyService, epgProgramRepo, sandbox).
+		WithLogger(logger).
+		WithBaseURL(urlutil.NormalizeBaseURL(viper.GetString("server.base_url")))
+	if streamAuthEnabled {
+		xtreamOutputHandler.WithViewerAuth(viewerService)
+	}
+	xtreamOutputHandler.RegisterChiRoutes(server.Router())
+
 	// Register static handler as NotFound fallback for SPA routing
 	// This ensures specific routes (like /logos/*) are matched first
 	staticHandler := handlers.NewStaticHandler()
@@ -529,34 +812,68 @@ func runServe(_ *cobra.Command, _ []string) error {
 	healthHandler := handlers.NewHealthHandler(version.Version).WithDB(db.DB)
 	healthHandler.Register(server.API())
 
+	authHandler := handlers.NewAuthHandler(authService, authEnabled)
+	authHandler.Register(server.API())
+
+	viewerHandler := handlers.NewViewerHandler(viewerService)
+	viewerHandler.Register(server.API())
+
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	notificationHandler.Register(server.API())
+
+	viewingHandler := handlers.NewViewingHandler(viewingService)
+	viewingHandler.Register(server.API())
+
+	auditHandler := handlers.NewAuditHandler(auditService)
+	auditHandler.Register(server.API())
+
+	recordingHandler := handlers.NewRecordingHandler(recordingService)
+	recordingHandler.Register(server.API())
+	recordingHandler.RegisterChiRoutes(apiRouter)
+
+	recordingRuleHandler := handlers.NewRecordingRuleHandler(recordingRuleService)
+	recordingRuleHandler.Register(server.API())
+
+	epgMatchHandler := handlers.NewEpgMatchHandler(
+		service.NewEpgMatchService(epgChannelRepo).WithLogger(logger),
+	)
+	epgMatchHandler.Register(server.API())
+
 	streamSourceHandler := handlers.NewStreamSourceHandler(sourceService).
 		WithScheduleSyncer(sched).
-		WithProxyUsageChecker(proxyRepo)
+		WithProxyUsageChecker(proxyRepo).
+		WithAuditRecorder(auditService)
 	streamSourceHandler.Register(server.API())
 
 	epgSourceHandler := handlers.NewEpgSourceHandler(epgService).
 		WithScheduleSyncer(sched).
-		WithProxyUsageChecker(proxyRepo)
+		WithProxyUsageChecker(proxyRepo).
+		WithAuditRecorder(auditService)
 	epgSourceHandler.Register(server.API())
 
 	unifiedSourcesHandler := handlers.NewUnifiedSourcesHandler(sourceService, epgService)
 	unifiedSourcesHandler.Register(server.API())
 
-	proxyHandler := handlers.NewStreamProxyHandler(proxyService).WithScheduleSyncer(sched)
+	proxyHandler := handlers.NewStreamProxyHandler(proxyService).
+		WithScheduleSyncer(sched).
+		WithAuditRecorder(auditService)
 	proxyHandler.Register(server.API())
 
 	expressionHandler := handlers.NewExpressionHandler(channelRepo, epgProgramRepo)
 	expressionHandler.Register(server.API())
 
-	filterHandler := handlers.NewFilterHandler(filterRepo).WithProxyUsageChecker(proxyRepo)
+	filterHandler := handlers.NewFilterHandler(filterRepo).
+		WithProxyUsageChecker(proxyRepo).
+		WithAuditRecorder(auditService)
 	filterHandler.Register(server.API())
 
-	dataMappingRuleHandler := handlers.NewDataMappingRuleHandler(dataMappingRuleRepo)
+	dataMappingRuleHandler := handlers.NewDataMappingRuleHandler(dataMappingRuleRepo).
+		WithAuditRecorder(auditService)
 	dataMappingRuleHandler.Register(server.API())
 
 	progressHandler := handlers.NewProgressHandler(progressService)
 	progressHandler.Register(server.API())
-	progressHandler.RegisterSSE(server.Router())
+	progressHandler.RegisterSSE(apiRouter)
 
 	featureHandler := handlers.NewFeatureHandler()
 	featureHandler.Register(server.API())
@@ -576,7 +893,8 @@ func runServe(_ *cobra.Command, _ []string) error {
 	logoHandler.Register(server.API())
 
 	encodingProfileHandler := handlers.NewEncodingProfileHandler(encodingProfileService).
-		WithProxyUsageChecker(proxyRepo)
+		WithProxyUsageChecker(proxyRepo).
+		WithAuditRecorder(auditService)
 	encodingProfileHandler.Register(server.API())
 
 	relayStreamHandler := handlers.NewRelayStreamHandler(relayService).
@@ -584,11 +902,14 @@ func runServe(_ *cobra.Command, _ []string) error {
 		WithClientDetectionService(clientDetectionService)
 	relayStreamHandler.Register(server.API())
 	relayStreamHandler.RegisterChiRoutes(server.Router())
+	relayStreamHandler.RegisterPreviewRoutes(apiRouter)
 
-	clientDetectionRuleHandler := handlers.NewClientDetectionRuleHandler(clientDetectionService)
+	clientDetectionRuleHandler := handlers.NewClientDetectionRuleHandler(clientDetectionService).
+		WithAuditRecorder(auditService)
 	clientDetectionRuleHandler.Register(server.API())
 
-	encoderOverrideHandler := handlers.NewEncoderOverrideHandler(encoderOverrideService)
+	encoderOverrideHandler := handlers.NewEncoderOverrideHandler(encoderOverrideService).
+		WithAuditRecorder(auditService)
 	encoderOverrideHandler.Register(server.API())
 
 	channelHandler := handlers.NewChannelHandler(db.DB).WithLogger(logger)
@@ -608,7 +929,7 @@ func runServe(_ *cobra.Command, _ []string) error {
 
 	logsHandler := handlers.NewLogsHandler(logsService)
 	logsHandler.Register(server.API())
-	logsHandler.RegisterSSE(server.Router())
+	logsHandler.RegisterSSE(apiRouter)
 
 	systemHandler := handlers.NewSystemHandler(relayService)
 	systemHandler.Register(server.API())
@@ -634,7 +955,8 @@ func runServe(_ *cobra.Command, _ []string) error {
 		encodingProfileRepo,
 	).WithLogger(logger)
 
-	exportHandler := handlers.NewExportHandler(exportService, importService)
+	exportHandler := handlers.NewExportHandler(exportService, importService).
+		WithAuditRecorder(auditService)
 	exportHandler.Register(server.API())
 
 	// Register backup/restore handlers (backupService created earlier for scheduler)
@@ -643,7 +965,7 @@ func runServe(_ *cobra.Command, _ []string) error {
 	backupHandler := handlers.NewBackupHandler(backupService).
 		WithScheduleUpdater(scheduleUpdater)
 	backupHandler.Register(server.API())
-	backupHandler.RegisterChiRoutes(server.Router())
+	backupHandler.RegisterChiRoutes(apiRouter)
 
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -670,6 +992,23 @@ func runServe(_ *cobra.Command, _ []string) error {
 		}()
 	}
 
+	// Start HDHomeRun network discovery so media servers find proxy tuners automatically.
+	// Discovered tuners carry no viewer token, so discovery is off with stream auth.
+	if viper.GetBool("hdhomerun.discovery") && streamAuthEnabled {
+		logger.Warn("hdhomerun discovery is disabled while stream authentication is enabled; add tuners manually with /hdhr/{proxyId}/{token}/")
+	} else if viper.GetBool("hdhomerun.discovery") {
+		discoverer := hdhomerun.NewDiscoverer(hdhomerun.DiscoveryConfig{
+			BaseURL:    urlutil.NormalizeBaseURL(viper.GetString("server.base_url")),
+			HTTPPort:   serverPort,
+			ServerName: "tvarr/" + version.Version,
+		}, hdhomerunHandler.Devices).WithLogger(logger)
+		if err := discoverer.Start(ctx); err != nil {
+			logger.Warn("failed to start hdhomerun discovery", slog.String("error", err.Error()))
+		} else {
+			defer discoverer.Stop()
+		}
+	}
+
 	// Start database stats monitor (logs every 30 minutes for SQLite)
 	db.StartStatsMonitor(ctx)
 
@@ -705,6 +1044,13 @@ func runServe(_ *cobra.Command, _ []string) error {
 			slog.String("schedule", logoScanSchedule))
 	}
 
+	// Check Xtream accounts on startup so connection limits are current
+	if accountCheckSchedule != "" {
+		if _, err := sched.ScheduleImmediate(ctx, models.JobTypeAccountCheck, models.ULID{}, "Xtream Account Check"); err != nil {
+			logger.Warn("failed to schedule initial account check job", slog.Any("error", err))
+		}
+	}
+
 	// Catch up on any missed scheduled runs if enabled
 	if viper.GetBool("scheduler.catchup_missed_runs") {
 		if _, _, err := sched.CatchupMissedRuns(ctx); err != nil {
@@ -712,6 +1058,15 @@ func runServe(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	// Resume recordings interrupted by a restart, or finalise those whose window has passed
+	if resumed, finalised, err := recordingService.RecoverInterrupted(ctx); err != nil {
+		logger.Warn("failed to recover interrupted recordings", slog.Any("error", err))
+	} else if resumed > 0 || finalised > 0 {
+		logger.Info("recovered interrupted recordings",
+			slog.Int("resumed", resumed),
+			slog.Int("finalised", finalised))
+	}
+
 	// Start job runner (workers begin polling for jobs)
 	if err := runner.Start(ctx); err != nil {
 		return fmt.Errorf("starting runner: %w", err)
@@ -732,7 +1087,8 @@ func runServe(_ *cobra.Command, _ []string) error {
 	}
 
 	// Create daemon registry and gRPC server
-	daemonRegistry := relay.NewDaemonRegistry(logger)
+	daemonRegistry := relay.NewDaemonRegistry(logger).
+		WithStateChangeHook(notificationService.HandleDaemonStateChange)
 	grpcServer := relay.NewGRPCServer(logger, grpcConfig, daemonRegistry)
 
 	if err := grpcServer.Start(ctx); err != nil {
@@ -777,6 +1133,21 @@ func runServe(_ *cobra.Command, _ []string) error {
 		viper.GetBool("relay.prefer_remote_probe"), // prefer remote probing
 	)
 
+	// Set after the relay service's final manager is created, like metrics below.
+	relayService.OnCircuitStateChange(notificationService.HandleCircuitStateChange)
+	if viper.GetBool("analytics.enabled") {
+		relayService.OnViewingEnded(viewingService.HandleViewingEnded)
+	}
+
+	// Serve Prometheus metrics at /metrics; like the API, scrapes need an API key when auth is enabled.
+	// Registered after the relay service's final manager is created.
+	if viper.GetBool("metrics.enabled") {
+		relayService.RegisterMetrics(observability.Metrics)
+		daemonRegistry.RegisterMetrics(observability.Metrics)
+		logoService.RegisterMetrics(observability.Metrics)
+		apiRouter.Get("/metrics", observability.Metrics.Handler().ServeHTTP)
+	}
+
 	// Load logo index in background so it doesn't block server startup.
 	// With large logo caches (10k+ files), the filesystem scan + pruning can
 	// take minutes, which would delay HTTP readiness and trigger K8s liveness
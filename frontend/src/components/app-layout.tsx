@@ -21,6 +21,11 @@ export function AppLayout({ children }: AppLayoutProps) {
   const pageTitle = getPageTitle(pathname);
   const operationType = getOperationType(pathname);
 
+  // The login page is rendered without the application chrome
+  if (pathname?.startsWith('/login')) {
+    return <>{children}</>;
+  }
+
   // Show loading state only during INITIAL check (not on navigation)
   if (!hasInitialCheckCompleted && isChecking) {
     return (
@@ -97,6 +97,17 @@ function transformEpgSourceResponse(source: any): EpgSourceResponse {
   };
 }
 
+/**
+ * Sends the browser to the login page, remembering the current location.
+ */
+function redirectToLogin() {
+  if (typeof window === 'undefined' || window.location.pathname.startsWith('/login')) {
+    return;
+  }
+  const next = encodeURIComponent(window.location.pathname + window.location.search);
+  window.location.href = `/login/?next=${next}`;
+}
+
 class ApiClient {
   private baseUrl: string;
   private debug = Debug.createLogger('ApiClient');
@@ -156,6 +167,11 @@ class ApiClient {
           // Response is not JSON, use status text
         }
 
+        // Authentication is enabled and the session is missing or expired
+        if (response.status === 401 && !endpoint.startsWith('/api/v1/auth/')) {
+          redirectToLogin();
+        }
+
         throw new ApiError(errorMessage, response.status, errorData);
       }
 
@@ -187,6 +203,18 @@ class ApiClient {
     }
   }
 
+  // Auth API
+  async login(username: string, password: string): Promise<void> {
+    await this.request('/api/v1/auth/login', {
+      method: 'POST',
+      body: JSON.stringify({ username, password }),
+    });
+  }
+
+  async logout(): Promise<void> {
+    await this.request('/api/v1/auth/logout', { method: 'POST' });
+  }
+
   // Stream Sources API
   async getStreamSources(params?: {
     page?: number;
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPassword_RoundTrip(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "pbkdf2-sha256$600000$"))
+	assert.True(t, VerifyPassword(hash, "correct horse"))
+	assert.False(t, VerifyPassword(hash, "correct horsf"))
+}
+
+func TestHashPassword_SaltsEachHash(t *testing.T) {
+	a, err := HashPassword("password123")
+	require.NoError(t, err)
+	b, err := HashPassword("password123")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+}
+
+func TestHashPassword_TooShort(t *testing.T) {
+	_, err := HashPassword("short")
+	assert.ErrorIs(t, err, ErrPasswordTooShort)
+}
+
+func TestVerifyPassword_MalformedHash(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"plaintext",
+		"bcrypt$10$abc$def",
+		"pbkdf2-sha256$notanumber$abc$def",
+		"pbkdf2-sha256$1000$!!!$def",
+		"pbkdf2-sha256$1000$abc$",
+	} {
+		assert.False(t, VerifyPassword(encoded, "anything"), encoded)
+	}
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, IsAPIKey(key))
+	assert.Len(t, APIKeyDisplayPrefix(key), len(APIKeyPrefix)+apiKeyDisplayLength)
+	assert.True(t, strings.HasPrefix(key, APIKeyDisplayPrefix(key)))
+
+	other, err := GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestGenerateSessionToken_IsNotAPIKey(t *testing.T) {
+	token, err := GenerateSessionToken()
+	require.NoError(t, err)
+	assert.False(t, IsAPIKey(token))
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t, HashToken("abc"), HashToken("abc"))
+	assert.NotEqual(t, HashToken("abc"), HashToken("abd"))
+	assert.Len(t, HashToken("abc"), 64)
+}
+
+func TestBearerToken(t *testing.T) {
+	assert.Equal(t, "abc", BearerToken("Bearer abc"))
+	assert.Equal(t, "abc", BearerToken("bearer  abc"))
+	assert.Empty(t, BearerToken("Basic abc"))
+	assert.Empty(t, BearerToken("Bearer"))
+	assert.Empty(t, BearerToken(""))
+}
+
+func TestSessionTokenFromCookieHeader(t *testing.T) {
+	assert.Equal(t, "tok", SessionTokenFromCookieHeader("tvarr_session=tok"))
+	assert.Equal(t, "tok", SessionTokenFromCookieHeader("theme=dark; tvarr_session=tok; other=1"))
+	assert.Empty(t, SessionTokenFromCookieHeader("theme=dark"))
+	assert.Empty(t, SessionTokenFromCookieHeader(""))
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes(" Read, write,read,, ")
+	require.NoError(t, err)
+	assert.Equal(t, Scopes{ScopeRead, ScopeWrite}, scopes)
+	assert.Equal(t, "read,write", scopes.String())
+
+	_, err = ParseScopes("read,superuser")
+	assert.Error(t, err)
+}
+
+func TestScopes_Allows(t *testing.T) {
+	tests := []struct {
+		granted  Scopes
+		required Scope
+		want     bool
+	}{
+		{Scopes{ScopeRead}, ScopeRead, true},
+		{Scopes{ScopeRead}, ScopeWrite, false},
+		{Scopes{ScopeWrite}, ScopeRead, true},
+		{Scopes{ScopeWrite}, ScopeAdmin, false},
+		{Scopes{ScopeAdmin}, ScopeWrite, true},
+		{Scopes{}, ScopeRead, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.granted.Allows(tt.required), "%v allows %s", tt.granted, tt.required)
+	}
+}
+
+func TestRequiredScope(t *testing.T) {
+	assert.Equal(t, ScopeRead, RequiredScope("GET", "/api/v1/proxies"))
+	assert.Equal(t, ScopeWrite, RequiredScope("POST", "/api/v1/proxies"))
+	assert.Equal(t, ScopeWrite, RequiredScope("DELETE", "/api/v1/proxies/{id}"))
+	assert.Equal(t, ScopeAdmin, RequiredScope("GET", "/api/v1/backups"))
+	assert.Equal(t, ScopeAdmin, RequiredScope("GET", "/api/v1/auth/api-keys"))
+	assert.Equal(t, ScopeAdmin, RequiredScope("POST", "/api/v1/auth/users/{id}/password"))
+	assert.Equal(t, ScopeRead, RequiredScope("GET", "/api/v1/auth/me"))
+	assert.Equal(t, ScopeAdmin, RequiredScope("GET", "/api/v1/viewers"))
+	assert.Equal(t, ScopeAdmin, RequiredScope("GET", "/api/v1/notifications/targets"))
+	assert.Equal(t, ScopeAdmin, RequiredScope("GET", "/api/v1/analytics/top-channels"))
+	assert.Equal(t, ScopeAdmin, RequiredScope("GET", "/api/v1/audit/events"))
+}
+
+func TestPrincipalContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	p := &Principal{Kind: PrincipalKindAPIKey, Username: "admin", APIKeyName: "sonarr"}
+	got, ok := PrincipalFromContext(WithPrincipal(context.Background(), p))
+	require.True(t, ok)
+	assert.Same(t, p, got)
+	assert.Equal(t, "api-key:sonarr", got.Name())
+}
+
+func TestStreamToken_RoundTrip(t *testing.T) {
+	key := []byte("test-signing-key")
+	now := time.Now()
+	token := SignStreamToken(key, "viewer1", "proxy1", "channel1", now.Add(time.Hour))
+
+	assert.True(t, IsSignedStreamToken(token))
+	viewerID, err := VerifyStreamToken(key, token, "proxy1", "channel1", now)
+	require.NoError(t, err)
+	assert.Equal(t, "viewer1", viewerID)
+}
+
+func TestStreamToken_Rejected(t *testing.T) {
+	key := []byte("test-signing-key")
+	now := time.Now()
+	token := SignStreamToken(key, "viewer1", "proxy1", "channel1", now.Add(time.Hour))
+
+	_, err := VerifyStreamToken(key, token, "proxy1", "channel2", now)
+	assert.ErrorIs(t, err, ErrInvalidStreamToken, "other channel")
+
+	_, err = VerifyStreamToken(key, token, "proxy2", "channel1", now)
+	assert.ErrorIs(t, err, ErrInvalidStreamToken, "other proxy")
+
+	_, err = VerifyStreamToken([]byte("other-key"), token, "proxy1", "channel1", now)
+	assert.ErrorIs(t, err, ErrInvalidStreamToken, "other key")
+
+	tampered := strings.Replace(token, "viewer1", "viewer2", 1)
+	_, err = VerifyStreamToken(key, tampered, "proxy1", "channel1", now)
+	assert.ErrorIs(t, err, ErrInvalidStreamToken, "tampered viewer")
+
+	_, err = VerifyStreamToken(key, token, "proxy1", "channel1", now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrStreamTokenExpired)
+
+	for _, malformed := range []string{"", "plain-viewer-token", "s1.", "s1.viewer1.notanumber.sig", "s1.viewer1.123"} {
+		_, err = VerifyStreamToken(key, malformed, "proxy1", "channel1", now)
+		assert.ErrorIs(t, err, ErrInvalidStreamToken, malformed)
+	}
+}
+
+func TestLoadOrCreateSigningKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "stream_signing.key")
+
+	key, err := LoadOrCreateSigningKey(path)
+	require.NoError(t, err)
+	assert.Len(t, key, signingKeyBytes)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	again, err := LoadOrCreateSigningKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	require.NoError(t, os.WriteFile(path, []byte("not base64!"), 0o600))
+	_, err = LoadOrCreateSigningKey(path)
+	assert.Error(t, err)
+}
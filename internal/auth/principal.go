@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// Authentication errors returned by authenticators.
+var (
+	// ErrUnauthenticated is returned when a request carries no valid credentials.
+	ErrUnauthenticated = errors.New("authentication required")
+
+	// ErrForbidden is returned when a principal lacks the scope for an operation.
+	ErrForbidden = errors.New("insufficient scope")
+)
+
+// OperationPublicMetadata is the huma operation metadata key that exempts an
+// /api/v1 operation from authentication (e.g. the login endpoint).
+const OperationPublicMetadata = "auth.public"
+
+// PrincipalKind identifies how a principal authenticated.
+type PrincipalKind string
+
+const (
+	// PrincipalKindSession is a user authenticated by a UI session cookie.
+	PrincipalKindSession PrincipalKind = "session"
+	// PrincipalKindAPIKey is an API key used for automation.
+	PrincipalKindAPIKey PrincipalKind = "api_key"
+)
+
+// Principal is the authenticated identity behind a request.
+type Principal struct {
+	// Kind is how the principal authenticated.
+	Kind PrincipalKind
+	// UserID is the ID of the user (session) or the user that owns the API key.
+	UserID string
+	// Username is the user's login name.
+	Username string
+	// APIKeyID is the ID of the API key, when Kind is PrincipalKindAPIKey.
+	APIKeyID string
+	// APIKeyName is the name of the API key, when Kind is PrincipalKindAPIKey.
+	APIKeyName string
+	// SessionID is the ID of the session, when Kind is PrincipalKindSession.
+	SessionID string
+	// Scopes are the permissions granted to the principal.
+	Scopes Scopes
+}
+
+// Name returns a human-readable identifier for logs and audit records.
+func (p *Principal) Name() string {
+	if p.Kind == PrincipalKindAPIKey {
+		return "api-key:" + p.APIKeyName
+	}
+	return p.Username
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a context carrying the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored on the context, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return p, ok && p != nil
+}
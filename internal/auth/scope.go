@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Scope is a permission level granted to a principal.
+// Scopes are hierarchical: admin implies write, and write implies read.
+type Scope string
+
+const (
+	// ScopeRead allows read-only API operations (GET/HEAD).
+	ScopeRead Scope = "read"
+	// ScopeWrite allows operations that change configuration or trigger work.
+	ScopeWrite Scope = "write"
+	// ScopeAdmin allows user, API key, viewer, notification and backup management.
+	ScopeAdmin Scope = "admin"
+)
+
+// adminPathPrefixes are API paths that require ScopeAdmin regardless of method.
+var adminPathPrefixes = []string{
+	"/api/v1/auth/users",
+	"/api/v1/auth/api-keys",
+	"/api/v1/backups",
+	"/api/v1/viewers",
+	"/api/v1/notifications",
+	"/api/v1/analytics",
+	"/api/v1/audit",
+}
+
+// rank orders scopes so a higher scope satisfies a lower requirement.
+func (s Scope) rank() int {
+	switch s {
+	case ScopeRead:
+		return 1
+	case ScopeWrite:
+		return 2
+	case ScopeAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// IsValid reports whether s is a known scope.
+func (s Scope) IsValid() bool {
+	return s.rank() > 0
+}
+
+// Scopes is a set of scopes granted to a principal.
+type Scopes []Scope
+
+// AllScopes returns every scope. Interactive user sessions are granted all scopes.
+func AllScopes() Scopes {
+	return Scopes{ScopeRead, ScopeWrite, ScopeAdmin}
+}
+
+// ParseScopes parses a comma-separated scope list.
+func ParseScopes(s string) (Scopes, error) {
+	var scopes Scopes
+	for part := range strings.SplitSeq(s, ",") {
+		part = strings.TrimSpace(strings.ToLower(part))
+		if part == "" {
+			continue
+		}
+		scope := Scope(part)
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("invalid scope %q: must be one of read, write, admin", part)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// String returns the scopes as a comma-separated list.
+func (s Scopes) String() string {
+	parts := make([]string, len(s))
+	for i, scope := range s {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Allows reports whether the scopes satisfy the required scope.
+func (s Scopes) Allows(required Scope) bool {
+	for _, scope := range s {
+		if scope.rank() >= required.rank() {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiredScope returns the scope needed to call an API operation.
+// path should be the operation's route template (e.g. "/api/v1/backups/{filename}/restore").
+func RequiredScope(method, path string) Scope {
+	for _, prefix := range adminPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return ScopeAdmin
+		}
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	default:
+		return ScopeWrite
+	}
+}
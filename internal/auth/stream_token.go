@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamTokenParam is the query parameter carrying a viewer's stream credential
+// on playlist and relay URLs. The credential is either the viewer's token or a
+// signed stream token from SignStreamToken.
+const StreamTokenParam = "token"
+
+// signedStreamTokenPrefix marks signed stream tokens and versions their format.
+const signedStreamTokenPrefix = "s1."
+
+// signingKeyBytes is the size of generated stream signing keys.
+const signingKeyBytes = 32
+
+// Stream token errors.
+var (
+	// ErrInvalidStreamToken is returned when a signed stream token is malformed
+	// or its signature does not match.
+	ErrInvalidStreamToken = errors.New("invalid stream token")
+
+	// ErrStreamTokenExpired is returned when a signed stream token has expired.
+	ErrStreamTokenExpired = errors.New("stream token expired")
+)
+
+// SignStreamToken returns a token that authorises viewerID to play one channel
+// of one proxy until expires. The token has the form
+// "s1.<viewerID>.<expiry>.<signature>" where the signature is an HMAC-SHA256
+// over the viewer, proxy, channel and expiry.
+func SignStreamToken(key []byte, viewerID, proxyID, channelID string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	sig := streamTokenSignature(key, viewerID, proxyID, channelID, exp)
+	return signedStreamTokenPrefix + viewerID + "." + exp + "." + sig
+}
+
+// IsSignedStreamToken reports whether a stream credential is a signed stream token
+// rather than a viewer token.
+func IsSignedStreamToken(token string) bool {
+	return strings.HasPrefix(token, signedStreamTokenPrefix)
+}
+
+// VerifyStreamToken checks a signed stream token for the given proxy and channel
+// and returns the viewer ID it was issued to.
+func VerifyStreamToken(key []byte, token, proxyID, channelID string, now time.Time) (string, error) {
+	rest, ok := strings.CutPrefix(token, signedStreamTokenPrefix)
+	if !ok {
+		return "", ErrInvalidStreamToken
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", ErrInvalidStreamToken
+	}
+	viewerID, exp, sig := parts[0], parts[1], parts[2]
+
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return "", ErrInvalidStreamToken
+	}
+
+	want := streamTokenSignature(key, viewerID, proxyID, channelID, exp)
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return "", ErrInvalidStreamToken
+	}
+	if !now.Before(time.Unix(expUnix, 0)) {
+		return "", ErrStreamTokenExpired
+	}
+	return viewerID, nil
+}
+
+// streamTokenSignature computes the URL-safe signature of a stream token.
+func streamTokenSignature(key []byte, viewerID, proxyID, channelID, exp string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join([]string{viewerID, proxyID, channelID, exp}, "|")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// LoadOrCreateSigningKey reads a signing key from path, creating it with a new
+// random key if it does not exist, so signed URLs survive restarts.
+func LoadOrCreateSigningKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if decodeErr != nil || len(key) == 0 {
+			return nil, fmt.Errorf("invalid signing key in %s", path)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+
+	key := make([]byte, signingKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("creating signing key directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("writing signing key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Credential transport names.
+const (
+	// SessionCookieName is the cookie carrying the UI session token.
+	SessionCookieName = "tvarr_session"
+
+	// APIKeyHeader is the header carrying an API key.
+	// API keys may also be sent as "Authorization: Bearer <key>".
+	APIKeyHeader = "X-API-Key"
+
+	// APIKeyPrefix marks tokens as tvarr API keys so they are recognisable in secret scanners.
+	APIKeyPrefix = "tvarr_"
+
+	// apiKeyDisplayLength is the number of key characters (after the prefix) kept for display.
+	apiKeyDisplayLength = 8
+
+	tokenBytes = 32
+)
+
+// GenerateSessionToken returns a new random session token.
+func GenerateSessionToken() (string, error) {
+	return randomToken()
+}
+
+// GenerateAPIKey returns a new random API key.
+func GenerateAPIKey() (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + token, nil
+}
+
+// IsAPIKey reports whether a token has the API key format.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// APIKeyDisplayPrefix returns the non-secret leading part of an API key,
+// used to identify keys in listings without storing them.
+func APIKeyDisplayPrefix(key string) string {
+	n := len(APIKeyPrefix) + apiKeyDisplayLength
+	if len(key) < n {
+		return key
+	}
+	return key[:n]
+}
+
+// HashToken returns the hex SHA-256 digest of a session token or API key.
+// Tokens are high-entropy, so a fast unsalted hash is sufficient for lookup.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header value.
+func BearerToken(authorization string) string {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// SessionTokenFromCookieHeader extracts the session token from a Cookie header value.
+func SessionTokenFromCookieHeader(cookieHeader string) string {
+	if cookieHeader == "" {
+		return ""
+	}
+	// http.Request.Cookie parses leniently, skipping unrelated malformed cookies.
+	req := &http.Request{Header: http.Header{"Cookie": {cookieHeader}}}
+	c, err := req.Cookie(SessionCookieName)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// randomToken returns a URL-safe token with tokenBytes of entropy.
+func randomToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
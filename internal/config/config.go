@@ -33,6 +33,8 @@ const (
 	defaultLogoTimeout           = 30 * time.Second
 	defaultLogoRetryAttempts     = 3
 	defaultLogoCircuitBreaker    = "logos"
+	defaultEpgMatchThreshold     = 0.9
+	defaultEpgMatchCandidate     = 0.6
 	defaultMaxConcurrentStreams  = 10
 	defaultCircuitBreakerThresh  = 3
 	defaultCircuitBreakerTimeout = 30 * time.Second
@@ -41,19 +43,41 @@ const (
 	defaultHLSSegmentDuration    = 4.0 // seconds, cut on every keyframe
 	defaultHLSMaxSegments        = 30  // segments in ring buffer (2+ minutes at 4s/segment)
 	defaultHLSPlaylistSegments   = 5   // segments in playlist for new clients
+	defaultHDHomeRunTunerCount   = 4   // tuners advertised for proxies without a stream limit
+	defaultAuthSessionTTL        = 7 * 24 * time.Hour
+	defaultSignedStreamURLTTL    = 24 * time.Hour
+	defaultRecordingPadBefore    = time.Minute
+	defaultRecordingPadAfter     = 5 * time.Minute
+	defaultNotifyAttempts        = 3
+	defaultNotifyRetryDelay      = 10 * time.Second
+	defaultNotifyCooldown        = 15 * time.Minute
+	defaultNotifyRetention       = 7 * 24 * time.Hour
+	defaultSMTPPort              = 587
+	defaultAnalyticsRetention    = 90 * 24 * time.Hour
+	defaultLogStoreRetention     = 14 * 24 * time.Hour
+	defaultLogStoreMaxEntries    = 1_000_000
+	maxTimeshiftWindow           = 24 * time.Hour
 )
 
 // Config holds all configuration for the application.
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`
-	Database  DatabaseConfig  `mapstructure:"database"`
-	Storage   StorageConfig   `mapstructure:"storage"`
-	Logging   LoggingConfig   `mapstructure:"logging"`
-	Ingestion IngestionConfig `mapstructure:"ingestion"`
-	Pipeline  PipelineConfig  `mapstructure:"pipeline"`
-	Relay     RelayConfig     `mapstructure:"relay"`
-	FFmpeg    FFmpegConfig    `mapstructure:"ffmpeg"`
-	Backup    BackupConfig    `mapstructure:"backup"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Storage       StorageConfig       `mapstructure:"storage"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	Ingestion     IngestionConfig     `mapstructure:"ingestion"`
+	Pipeline      PipelineConfig      `mapstructure:"pipeline"`
+	Relay         RelayConfig         `mapstructure:"relay"`
+	FFmpeg        FFmpegConfig        `mapstructure:"ffmpeg"`
+	Backup        BackupConfig        `mapstructure:"backup"`
+	HDHomeRun     HDHomeRunConfig     `mapstructure:"hdhomerun"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	StreamAuth    StreamAuthConfig    `mapstructure:"stream_auth"`
+	Recording     RecordingConfig     `mapstructure:"recording"`
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	Analytics     AnalyticsConfig     `mapstructure:"analytics"`
 }
 
 // ServerConfig holds HTTP server configuration.
@@ -95,6 +119,19 @@ type LoggingConfig struct {
 	Format     string `mapstructure:"format"` // json, text
 	AddSource  bool   `mapstructure:"add_source"`
 	TimeFormat string `mapstructure:"time_format"`
+	// Store persists logs so they can be queried after a restart.
+	Store LogStoreConfig `mapstructure:"store"`
+}
+
+// LogStoreConfig holds persistent log store configuration.
+type LogStoreConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Level is the minimum level stored: trace, debug, info, warn or error.
+	Level string `mapstructure:"level"`
+	// Retention is how long entries are kept; 0 keeps them until MaxEntries is reached.
+	Retention time.Duration `mapstructure:"retention"`
+	// MaxEntries is the most entries kept, deleting the oldest first; 0 is unlimited.
+	MaxEntries int `mapstructure:"max_entries"`
 }
 
 // IngestionConfig holds source ingestion configuration.
@@ -116,6 +153,12 @@ type PipelineConfig struct {
 	LogoTimeout        time.Duration `mapstructure:"logo_timeout"`         // Timeout for individual logo downloads (default 30s)
 	LogoRetryAttempts  int           `mapstructure:"logo_retry_attempts"`  // Number of retry attempts for failed logo downloads (default 3)
 	LogoCircuitBreaker string        `mapstructure:"logo_circuit_breaker"` // Circuit breaker namespace for logos (default "logos")
+	// EpgMatchThreshold is the name similarity (0-1) at or above which proxies with
+	// auto_match_epg assign a channel's tvg-id automatically.
+	EpgMatchThreshold float64 `mapstructure:"epg_match_threshold"`
+	// EpgMatchCandidateThreshold is the similarity at or above which weaker
+	// matches are recorded for confirmation through the API.
+	EpgMatchCandidateThreshold float64 `mapstructure:"epg_match_candidate_threshold"`
 }
 
 // RelayConfig holds stream relay configuration.
@@ -128,6 +171,9 @@ type RelayConfig struct {
 	StreamTimeout           time.Duration `mapstructure:"stream_timeout"`
 	Buffer                  BufferConfig  `mapstructure:"buffer"`
 	HLS                     HLSConfig     `mapstructure:"hls"`
+	// Failover switches a relay session to the same channel (by tvg-id) in the
+	// proxy's other stream sources when its upstream fails.
+	Failover bool `mapstructure:"failover"`
 }
 
 // HLSConfig holds HLS streaming configuration.
@@ -140,6 +186,17 @@ type HLSConfig struct {
 	// PlaylistSegments is the number of segments to include in playlists for new clients.
 	// More segments = more buffer before live edge.
 	PlaylistSegments int `mapstructure:"playlist_segments"`
+	// Timeshift configures the disk-backed pause/rewind window.
+	Timeshift TimeshiftConfig `mapstructure:"timeshift"`
+}
+
+// TimeshiftConfig holds disk-backed live timeshift configuration.
+type TimeshiftConfig struct {
+	// Window is how far back viewers can rewind live HLS and DASH output.
+	// Segments older than the in-memory buffer are kept on disk. 0 disables timeshift.
+	Window time.Duration `mapstructure:"window"`
+	// Directory is where timeshift segments are written, relative to storage.base_dir.
+	Directory string `mapstructure:"directory"`
 }
 
 // BufferConfig holds elementary stream buffer configuration.
@@ -170,6 +227,107 @@ type BackupScheduleConfig struct {
 	Retention int    `mapstructure:"retention"` // Number of backups to keep
 }
 
+// HDHomeRunConfig holds HDHomeRun tuner emulation configuration.
+type HDHomeRunConfig struct {
+	// Discovery enables SSDP and HDHomeRun UDP discovery so media servers find tuners automatically.
+	Discovery bool `mapstructure:"discovery"`
+	// DefaultTunerCount is the tuner count advertised for proxies with unlimited concurrent streams.
+	DefaultTunerCount int `mapstructure:"default_tuner_count"`
+}
+
+// MetricsConfig holds Prometheus metrics configuration.
+type MetricsConfig struct {
+	// Enabled serves metrics in the Prometheus text format at /metrics.
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration.
+type TracingConfig struct {
+	// Enabled exports traces to an OTLP collector.
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the host:port of the collector's OTLP gRPC receiver.
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure connects to the collector without TLS.
+	Insecure bool `mapstructure:"insecure"`
+	// SampleRatio is the fraction of new traces that are sampled, from 0 to 1.
+	// Traces continued from an incoming request follow the caller's decision.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// AuthConfig holds admin API authentication configuration.
+type AuthConfig struct {
+	// Enabled requires a session or API key for all /api/v1 operations.
+	Enabled bool `mapstructure:"enabled"`
+	// AdminUsername is the username of the initial admin, created when no users exist.
+	AdminUsername string `mapstructure:"admin_username"`
+	// AdminPassword is the password of the initial admin.
+	// When empty, a random password is generated and logged once.
+	AdminPassword string `mapstructure:"admin_password"`
+	// SessionTTL is how long a UI login session stays valid.
+	SessionTTL time.Duration `mapstructure:"session_ttl"`
+}
+
+// StreamAuthConfig holds per-viewer playback authentication configuration.
+type StreamAuthConfig struct {
+	// Enabled requires a viewer credential on playlist, tuner and stream URLs.
+	Enabled bool `mapstructure:"enabled"`
+	// SignURLs embeds signed, expiring per-channel tokens in playlists instead of
+	// the viewer's token.
+	SignURLs bool `mapstructure:"sign_urls"`
+	// SignedURLTTL is how long a signed stream URL stays valid.
+	SignedURLTTL time.Duration `mapstructure:"signed_url_ttl"`
+	// SigningKey is the HMAC key for signed URLs. When empty, a key is generated
+	// and stored in the storage directory.
+	SigningKey string `mapstructure:"signing_key"`
+}
+
+// RecordingConfig holds DVR recording configuration.
+type RecordingConfig struct {
+	// Directory is where recordings are written, relative to storage.base_dir.
+	Directory string `mapstructure:"directory"`
+	// PaddingBefore is the default time recorded before a programme starts.
+	PaddingBefore time.Duration `mapstructure:"padding_before"`
+	// PaddingAfter is the default time recorded after a programme ends.
+	PaddingAfter time.Duration `mapstructure:"padding_after"`
+	// Format is the default container for new recordings: mpegts or fmp4.
+	Format string `mapstructure:"format"`
+}
+
+// NotificationsConfig holds notification delivery configuration. Targets are
+// managed through the API.
+type NotificationsConfig struct {
+	// MaxAttempts is the number of times a delivery is attempted before it is logged as failed.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryDelay is the delay before the first retry; it doubles for each later retry.
+	RetryDelay time.Duration `mapstructure:"retry_delay"`
+	// Cooldown suppresses repeats of an event with the same type and subject.
+	Cooldown time.Duration `mapstructure:"cooldown"`
+	// DeliveryRetention is how long the delivery log is kept.
+	DeliveryRetention time.Duration `mapstructure:"delivery_retention"`
+	// SMTP is the mail server used by email targets.
+	SMTP SMTPConfig `mapstructure:"smtp"`
+}
+
+// SMTPConfig holds the mail server used to send email notifications.
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// From is the sender address.
+	From string `mapstructure:"from"`
+	// TLS is how the connection is secured: starttls, tls (implicit, usually port 465) or none.
+	TLS string `mapstructure:"tls"`
+}
+
+// AnalyticsConfig holds viewing session analytics configuration.
+type AnalyticsConfig struct {
+	// Enabled records a viewing session each time a client leaves a relay.
+	Enabled bool `mapstructure:"enabled"`
+	// Retention is how long viewing sessions are kept; 0 keeps them forever.
+	Retention time.Duration `mapstructure:"retention"`
+}
+
 // Load reads configuration from file and environment variables.
 // Environment variables take precedence over file configuration.
 // Environment variables are prefixed with TVARR_ and use underscores for nesting.
@@ -251,6 +409,10 @@ func SetDefaults(v *viper.Viper) {
 	v.SetDefault("logging.format", "json")
 	v.SetDefault("logging.add_source", true)
 	v.SetDefault("logging.time_format", time.RFC3339)
+	v.SetDefault("logging.store.enabled", false)
+	v.SetDefault("logging.store.level", "info")
+	v.SetDefault("logging.store.retention", defaultLogStoreRetention)
+	v.SetDefault("logging.store.max_entries", defaultLogStoreMaxEntries)
 
 	// Ingestion defaults
 	v.SetDefault("ingestion.channel_batch_size", defaultChannelBatchSize)
@@ -262,6 +424,7 @@ func SetDefaults(v *viper.Viper) {
 
 	// Scheduler defaults
 	v.SetDefault("scheduler.catchup_missed_runs", true)
+	v.SetDefault("scheduler.account_expiry_warning", 7*24*time.Hour)
 
 	// Pipeline defaults
 	v.SetDefault("pipeline.stream_batch_size", defaultChannelBatchSize)
@@ -271,6 +434,8 @@ func SetDefaults(v *viper.Viper) {
 	v.SetDefault("pipeline.logo_timeout", defaultLogoTimeout)
 	v.SetDefault("pipeline.logo_retry_attempts", defaultLogoRetryAttempts)
 	v.SetDefault("pipeline.logo_circuit_breaker", defaultLogoCircuitBreaker)
+	v.SetDefault("pipeline.epg_match_threshold", defaultEpgMatchThreshold)
+	v.SetDefault("pipeline.epg_match_candidate_threshold", defaultEpgMatchCandidate)
 
 	// Relay defaults
 	v.SetDefault("relay.enabled", false)
@@ -279,9 +444,12 @@ func SetDefaults(v *viper.Viper) {
 	v.SetDefault("relay.circuit_breaker_timeout", defaultCircuitBreakerTimeout)
 	v.SetDefault("relay.connection_pool_size", defaultConnectionPoolSize)
 	v.SetDefault("relay.stream_timeout", defaultStreamTimeout)
+	v.SetDefault("relay.failover", true)
 	v.SetDefault("relay.hls.target_segment_duration", defaultHLSSegmentDuration)
 	v.SetDefault("relay.hls.max_segments", defaultHLSMaxSegments)
 	v.SetDefault("relay.hls.playlist_segments", defaultHLSPlaylistSegments)
+	v.SetDefault("relay.hls.timeshift.window", 0)
+	v.SetDefault("relay.hls.timeshift.directory", "timeshift")
 
 	// FFmpeg defaults
 	v.SetDefault("ffmpeg.binary_path", "")
@@ -294,6 +462,53 @@ func SetDefaults(v *viper.Viper) {
 	v.SetDefault("backup.schedule.enabled", true)       // Enabled by default
 	v.SetDefault("backup.schedule.cron", "0 0 2 * * *") // Daily at 2 AM (6-field cron)
 	v.SetDefault("backup.schedule.retention", 7)        // Keep last 7 backups
+
+	// HDHomeRun defaults
+	v.SetDefault("hdhomerun.discovery", false)
+	v.SetDefault("hdhomerun.default_tuner_count", defaultHDHomeRunTunerCount)
+
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", true)
+
+	// Tracing defaults
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.endpoint", "localhost:4317")
+	v.SetDefault("tracing.insecure", true)
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	// Auth defaults
+	v.SetDefault("auth.enabled", false)
+	v.SetDefault("auth.admin_username", "admin")
+	v.SetDefault("auth.admin_password", "")
+	v.SetDefault("auth.session_ttl", defaultAuthSessionTTL)
+
+	// Stream auth defaults
+	v.SetDefault("stream_auth.enabled", false)
+	v.SetDefault("stream_auth.sign_urls", false)
+	v.SetDefault("stream_auth.signed_url_ttl", defaultSignedStreamURLTTL)
+	v.SetDefault("stream_auth.signing_key", "")
+
+	// Recording defaults
+	v.SetDefault("recording.directory", "recordings")
+	v.SetDefault("recording.padding_before", defaultRecordingPadBefore)
+	v.SetDefault("recording.padding_after", defaultRecordingPadAfter)
+	v.SetDefault("recording.format", "mpegts")
+
+	// Notification defaults
+	v.SetDefault("notifications.max_attempts", defaultNotifyAttempts)
+	v.SetDefault("notifications.retry_delay", defaultNotifyRetryDelay)
+	v.SetDefault("notifications.cooldown", defaultNotifyCooldown)
+	v.SetDefault("notifications.delivery_retention", defaultNotifyRetention)
+	v.SetDefault("notifications.smtp.host", "")
+	v.SetDefault("notifications.smtp.port", defaultSMTPPort)
+	v.SetDefault("notifications.smtp.username", "")
+	v.SetDefault("notifications.smtp.password", "")
+	v.SetDefault("notifications.smtp.from", "tvarr@localhost")
+	v.SetDefault("notifications.smtp.tls", "starttls")
+
+	// Analytics defaults
+	v.SetDefault("analytics.enabled", true)
+	v.SetDefault("analytics.retention", defaultAnalyticsRetention)
 }
 
 // Validate checks the configuration for errors.
@@ -337,6 +552,16 @@ func (c *Config) Validate() error {
 	if !validFormats[c.Logging.Format] {
 		return fmt.Errorf("logging.format must be one of: json, text")
 	}
+	if c.Logging.Store.Enabled {
+		switch c.Logging.Store.Level {
+		case "trace", "debug", "info", "warn", "error":
+		default:
+			return fmt.Errorf("logging.store.level must be one of: trace, debug, info, warn, error")
+		}
+		if c.Logging.Store.Retention < 0 || c.Logging.Store.MaxEntries < 0 {
+			return fmt.Errorf("logging.store.retention and logging.store.max_entries must not be negative")
+		}
+	}
 
 	// Ingestion validation
 	if c.Ingestion.ChannelBatchSize < 1 {
@@ -359,6 +584,12 @@ func (c *Config) Validate() error {
 	if c.Pipeline.LogoConcurrency > 100 {
 		return fmt.Errorf("pipeline.logo_concurrency seems unreasonably high (max 100)")
 	}
+	if c.Pipeline.EpgMatchThreshold <= 0 || c.Pipeline.EpgMatchThreshold > 1 {
+		return fmt.Errorf("pipeline.epg_match_threshold must be greater than 0 and at most 1")
+	}
+	if c.Pipeline.EpgMatchCandidateThreshold <= 0 || c.Pipeline.EpgMatchCandidateThreshold > c.Pipeline.EpgMatchThreshold {
+		return fmt.Errorf("pipeline.epg_match_candidate_threshold must be greater than 0 and at most pipeline.epg_match_threshold")
+	}
 
 	// Relay validation
 	if c.Relay.MaxConcurrentStreams < 1 {
@@ -376,6 +607,12 @@ func (c *Config) Validate() error {
 	if c.Relay.ConnectionPoolSize > 10000 {
 		return fmt.Errorf("relay.connection_pool_size seems unreasonably high (max 10000)")
 	}
+	if c.Relay.HLS.Timeshift.Window < 0 {
+		return fmt.Errorf("relay.hls.timeshift.window cannot be negative")
+	}
+	if c.Relay.HLS.Timeshift.Window > maxTimeshiftWindow {
+		return fmt.Errorf("relay.hls.timeshift.window seems unreasonably high (max 24h)")
+	}
 
 	// Backup validation
 	if c.Backup.Schedule.Retention < 1 {
@@ -385,6 +622,33 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("backup.schedule.retention seems unreasonably high (max 365)")
 	}
 
+	// Tracing validation
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+	}
+
+	// Recording validation
+	if c.Recording.Format != "mpegts" && c.Recording.Format != "fmp4" {
+		return fmt.Errorf("recording.format must be one of: mpegts, fmp4")
+	}
+	if c.Recording.PaddingBefore < 0 || c.Recording.PaddingAfter < 0 {
+		return fmt.Errorf("recording padding cannot be negative")
+	}
+
+	// Notification validation
+	if c.Notifications.MaxAttempts < 1 || c.Notifications.MaxAttempts > 10 {
+		return fmt.Errorf("notifications.max_attempts must be between 1 and 10")
+	}
+	switch c.Notifications.SMTP.TLS {
+	case "starttls", "tls", "none":
+	default:
+		return fmt.Errorf("notifications.smtp.tls must be one of: starttls, tls, none")
+	}
+
+	if c.Analytics.Retention < 0 {
+		return fmt.Errorf("analytics.retention must not be negative")
+	}
+
 	return nil
 }
 
@@ -28,7 +28,9 @@ func validTestConfig() *Config {
 			MaxConcurrent:    3,
 		},
 		Pipeline: PipelineConfig{
-			LogoConcurrency: 10,
+			LogoConcurrency:            10,
+			EpgMatchThreshold:          0.9,
+			EpgMatchCandidateThreshold: 0.6,
 		},
 		Relay: RelayConfig{
 			MaxConcurrentStreams:    10,
@@ -38,6 +40,11 @@ func validTestConfig() *Config {
 		Backup: BackupConfig{
 			Schedule: BackupScheduleConfig{Retention: 7},
 		},
+		Recording: RecordingConfig{Format: "mpegts"},
+		Notifications: NotificationsConfig{
+			MaxAttempts: 3,
+			SMTP:        SMTPConfig{TLS: "starttls"},
+		},
 	}
 }
 
@@ -66,6 +73,10 @@ func TestLoad_Defaults(t *testing.T) {
 	// Logging defaults
 	assert.Equal(t, "info", cfg.Logging.Level)
 	assert.Equal(t, "json", cfg.Logging.Format)
+	assert.False(t, cfg.Logging.Store.Enabled)
+	assert.Equal(t, "info", cfg.Logging.Store.Level)
+	assert.Equal(t, 14*24*time.Hour, cfg.Logging.Store.Retention)
+	assert.Equal(t, 1_000_000, cfg.Logging.Store.MaxEntries)
 
 	// Ingestion defaults
 	assert.Equal(t, 1000, cfg.Ingestion.ChannelBatchSize)
@@ -74,13 +85,28 @@ func TestLoad_Defaults(t *testing.T) {
 	// Pipeline defaults
 	assert.Equal(t, 1000, cfg.Pipeline.StreamBatchSize)
 	assert.True(t, cfg.Pipeline.EnableGCHints)
+	assert.Equal(t, 0.9, cfg.Pipeline.EpgMatchThreshold)
+	assert.Equal(t, 0.6, cfg.Pipeline.EpgMatchCandidateThreshold)
 
 	// Relay defaults
 	assert.False(t, cfg.Relay.Enabled)
 	assert.Equal(t, 10, cfg.Relay.MaxConcurrentStreams)
+	assert.True(t, cfg.Relay.Failover)
+	assert.Zero(t, cfg.Relay.HLS.Timeshift.Window)
+	assert.Equal(t, "timeshift", cfg.Relay.HLS.Timeshift.Directory)
 
 	// FFmpeg defaults
 	assert.False(t, cfg.FFmpeg.UseEmbedded)
+
+	// Recording defaults
+	assert.Equal(t, "recordings", cfg.Recording.Directory)
+	assert.Equal(t, time.Minute, cfg.Recording.PaddingBefore)
+	assert.Equal(t, 5*time.Minute, cfg.Recording.PaddingAfter)
+	assert.Equal(t, "mpegts", cfg.Recording.Format)
+
+	// Analytics defaults
+	assert.True(t, cfg.Analytics.Enabled)
+	assert.Equal(t, 90*24*time.Hour, cfg.Analytics.Retention)
 }
 
 func TestLoad_FromFile(t *testing.T) {
@@ -237,6 +263,29 @@ func TestValidate_InvalidLogFormat(t *testing.T) {
 	assert.Contains(t, err.Error(), "logging.format")
 }
 
+func TestValidate_InvalidLogStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*LogStoreConfig)
+		errContains string
+	}{
+		{"unknown level", func(c *LogStoreConfig) { c.Level = "verbose" }, "logging.store.level"},
+		{"negative retention", func(c *LogStoreConfig) { c.Retention = -time.Hour }, "logging.store.retention"},
+		{"negative max entries", func(c *LogStoreConfig) { c.MaxEntries = -1 }, "logging.store.max_entries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Logging.Store = LogStoreConfig{Enabled: true, Level: "info"}
+			tt.modify(&cfg.Logging.Store)
+			err := cfg.Validate()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
 func TestValidate_InvalidBatchSize(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -293,6 +342,9 @@ func TestValidate_PipelineConfig(t *testing.T) {
 	}{
 		{"zero logo concurrency", func(c *Config) { c.Pipeline.LogoConcurrency = 0 }, "logo_concurrency"},
 		{"too high logo concurrency", func(c *Config) { c.Pipeline.LogoConcurrency = 101 }, "logo_concurrency"},
+		{"zero epg match threshold", func(c *Config) { c.Pipeline.EpgMatchThreshold = 0 }, "epg_match_threshold"},
+		{"epg match threshold above 1", func(c *Config) { c.Pipeline.EpgMatchThreshold = 1.5 }, "epg_match_threshold"},
+		{"candidate above threshold", func(c *Config) { c.Pipeline.EpgMatchCandidateThreshold = 0.95 }, "epg_match_candidate_threshold"},
 	}
 
 	for _, tt := range tests {
@@ -327,6 +379,39 @@ func TestValidate_BackupConfig(t *testing.T) {
 	}
 }
 
+func TestValidate_TimeshiftWindow(t *testing.T) {
+	cfg := validTestConfig()
+	cfg.Relay.HLS.Timeshift.Window = 2 * time.Hour
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Relay.HLS.Timeshift.Window = -time.Minute
+	assert.ErrorContains(t, cfg.Validate(), "relay.hls.timeshift.window")
+
+	cfg.Relay.HLS.Timeshift.Window = 48 * time.Hour
+	assert.ErrorContains(t, cfg.Validate(), "relay.hls.timeshift.window")
+}
+
+func TestValidate_RecordingConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{"unknown format", func(c *Config) { c.Recording.Format = "mkv" }, "recording.format"},
+		{"negative padding", func(c *Config) { c.Recording.PaddingAfter = -time.Minute }, "padding"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
 func TestValidate_DatabaseConfig(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -370,6 +455,29 @@ func TestValidate_IngestionConfig(t *testing.T) {
 	}
 }
 
+func TestValidate_NotificationsConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*Config)
+		errContains string
+	}{
+		{"zero max attempts", func(c *Config) { c.Notifications.MaxAttempts = 0 }, "max_attempts"},
+		{"too many max attempts", func(c *Config) { c.Notifications.MaxAttempts = 11 }, "max_attempts"},
+		{"unknown smtp tls mode", func(c *Config) { c.Notifications.SMTP.TLS = "ssl" }, "smtp.tls"},
+		{"negative analytics retention", func(c *Config) { c.Analytics.Retention = -time.Hour }, "analytics.retention"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
 func TestServerConfig_Address(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -2,15 +2,18 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/version"
 	"github.com/jmylchreest/tvarr/pkg/ffmpeg"
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/proto"
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/types"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -123,6 +126,7 @@ func (c *RegistrationClient) Connect(ctx context.Context) error {
 	// Establish gRPC connection
 	conn, err := grpc.NewClient(c.config.CoordinatorURL,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(observability.GRPCClientHandler()),
 	)
 	if err != nil {
 		c.mu.Lock()
@@ -797,8 +801,15 @@ func (h *TranscodeStreamHandler) handleTranscodeStart(ctx context.Context, start
 	// Create new transcode job with proper binInfo
 	job := NewTranscodeJob(jobID, start, h.binInfo, h.logger)
 
-	// Start the job
-	ack, err := job.Start(ctx)
+	// Start the job, continuing the coordinator's trace
+	startCtx, span := observability.StartSpan(observability.ExtractTraceContext(ctx, start.TraceContext),
+		"ffmpegd.transcode.start", attribute.String("tvarr.job.id", jobID))
+	ack, err := job.Start(startCtx)
+	if err == nil && !ack.Success {
+		observability.EndSpan(span, errors.New(ack.Error))
+	} else {
+		observability.EndSpan(span, err)
+	}
 	if err != nil {
 		h.logger.Error("Failed to start transcode job",
 			slog.String("job_id", jobID),
@@ -1224,6 +1235,10 @@ func (h *TranscodeStreamHandler) handleProbeRequest(ctx context.Context, req *pr
 
 	start := time.Now()
 
+	ctx, span := observability.StartSpan(observability.ExtractTraceContext(ctx, req.TraceContext), "ffmpegd.probe")
+	var probeErr error
+	defer func() { observability.EndSpan(span, probeErr) }()
+
 	// Check if ffprobe is available
 	if h.binInfo == nil || h.binInfo.FFprobePath == "" {
 		h.sendProbeResponse(&proto.ProbeResponse{
@@ -1240,9 +1255,11 @@ func (h *TranscodeStreamHandler) handleProbeRequest(ctx context.Context, req *pr
 		prober = prober.WithTimeout(time.Duration(req.TimeoutMs) * time.Millisecond)
 	}
 
-	// Probe the stream
+	// Probe the stream, through the source's proxy and with its request headers
+	prober = prober.WithProxy(req.ProxyUrl).WithHeaders(ffmpeg.ParseHeaderLines(req.Headers))
 	info, err := prober.ProbeSimple(ctx, req.StreamUrl)
 	if err != nil {
+		probeErr = err
 		h.logger.Warn("Probe failed",
 			slog.String("stream_url", req.StreamUrl),
 			slog.String("error", err.Error()),
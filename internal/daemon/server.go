@@ -103,6 +103,7 @@ func (s *Server) Start(ctx context.Context) error {
 	s.grpcServer = grpc.NewServer(
 		grpc.UnaryInterceptor(s.unaryInterceptor),
 		grpc.StreamInterceptor(s.streamInterceptor),
+		grpc.StatsHandler(observability.GRPCServerHandler()),
 	)
 	proto.RegisterFFmpegDaemonServer(s.grpcServer, s)
 
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration030Auth adds the tables backing admin authentication:
+// local users, UI sessions and scoped API keys.
+func migration030Auth() Migration {
+	return Migration{
+		Version:     "030",
+		Description: "Add users, auth_sessions and api_keys tables for admin authentication",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(
+				&models.User{},
+				&models.AuthSession{},
+				&models.APIKey{},
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, table := range []string{"api_keys", "auth_sessions", "users"} {
+				if err := tx.Migrator().DropTable(table); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
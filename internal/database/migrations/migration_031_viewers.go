@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration031Viewers adds the viewers table for per-viewer stream credentials.
+func migration031Viewers() Migration {
+	return Migration{
+		Version:     "031",
+		Description: "Add viewers table for per-viewer stream credentials",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Viewer{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("viewers")
+		},
+	}
+}
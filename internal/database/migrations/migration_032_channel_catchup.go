@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration032ChannelCatchup adds catch-up archive columns to the channels table,
+// populated from Xtream tv_archive metadata and M3U catchup attributes.
+func migration032ChannelCatchup() Migration {
+	return Migration{
+		Version:     "032",
+		Description: "Add catch-up archive columns to channels table",
+		Up: func(tx *gorm.DB) error {
+			columns := []struct {
+				name       string
+				definition string
+			}{
+				{"catchup_days", "INTEGER DEFAULT 0"},
+				{"catchup_source", "VARCHAR(4096)"},
+				{"catchup_timezone", "VARCHAR(64)"},
+			}
+			for _, col := range columns {
+				if tx.Migrator().HasColumn("channels", col.name) {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE channels ADD COLUMN " + col.name + " " + col.definition).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the columns
+			// are harmless when left in place.
+			return nil
+		},
+	}
+}
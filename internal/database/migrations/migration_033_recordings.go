@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration033Recordings adds the recordings table for scheduled DVR recordings.
+func migration033Recordings() Migration {
+	return Migration{
+		Version:     "033",
+		Description: "Add recordings table for scheduled DVR recordings",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Recording{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("recordings")
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration034RecordingRules adds the recording_rules table for series
+// auto-record rules, and the columns recordings use to link back to their
+// rule and to suppress repeats of already-recorded episodes.
+func migration034RecordingRules() Migration {
+	return Migration{
+		Version:     "034",
+		Description: "Add recording_rules table and episode columns to recordings",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.RecordingRule{}); err != nil {
+				return err
+			}
+
+			columns := []struct {
+				name       string
+				definition string
+			}{
+				{"rule_id", "VARCHAR(26)"},
+				{"season_number", "INTEGER DEFAULT 0"},
+				{"episode_number", "INTEGER DEFAULT 0"},
+				{"episode_id", "VARCHAR(100)"},
+			}
+			for _, col := range columns {
+				if tx.Migrator().HasColumn("recordings", col.name) {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE recordings ADD COLUMN " + col.name + " " + col.definition).Error; err != nil {
+					return err
+				}
+			}
+
+			for _, index := range []string{"idx_recordings_rule_id", "idx_recordings_episode_id"} {
+				if tx.Migrator().HasIndex(&models.Recording{}, index) {
+					continue
+				}
+				if err := tx.Migrator().CreateIndex(&models.Recording{}, index); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// The recordings columns are left in place; SQLite cannot drop columns
+			// without recreating the table, and they are harmless.
+			return tx.Migrator().DropTable("recording_rules")
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration035ChannelDedup adds the per-proxy channel deduplication settings and
+// the proxy_channel_alternates table recording the channels each kept one replaced.
+func migration035ChannelDedup() Migration {
+	return Migration{
+		Version:     "035",
+		Description: "Add channel dedup settings to stream_proxies and proxy_channel_alternates table",
+		Up: func(tx *gorm.DB) error {
+			columns := []struct {
+				name       string
+				definition string
+			}{
+				{"dedup_mode", "VARCHAR(20) NOT NULL DEFAULT 'keep'"},
+				{"dedup_identity", "VARCHAR(20) NOT NULL DEFAULT 'tvg_id'"},
+				{"dedup_expression", "VARCHAR(1024)"},
+				{"dedup_preference", "VARCHAR(20) NOT NULL DEFAULT 'priority'"},
+			}
+			for _, col := range columns {
+				if tx.Migrator().HasColumn("stream_proxies", col.name) {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE stream_proxies ADD COLUMN " + col.name + " " + col.definition).Error; err != nil {
+					return err
+				}
+			}
+			return tx.AutoMigrate(&models.ProxyChannelAlternate{})
+		},
+		Down: func(tx *gorm.DB) error {
+			// The stream_proxies columns are left in place, as SQLite cannot drop
+			// columns without recreating the table.
+			return tx.Migrator().DropTable("proxy_channel_alternates")
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration036EpgChannelMatching adds the epg_channels table holding ingested
+// XMLTV channel display names, the epg_channel_matches table recording name
+// matches, and the per-proxy auto_match_epg setting.
+func migration036EpgChannelMatching() Migration {
+	return Migration{
+		Version:     "036",
+		Description: "Add epg_channels and epg_channel_matches tables and auto_match_epg to stream_proxies",
+		Up: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasColumn("stream_proxies", "auto_match_epg") {
+				if err := tx.Exec("ALTER TABLE stream_proxies ADD COLUMN auto_match_epg BOOLEAN DEFAULT FALSE").Error; err != nil {
+					return err
+				}
+			}
+			return tx.AutoMigrate(&models.EpgChannel{}, &models.EpgChannelMatch{})
+		},
+		Down: func(tx *gorm.DB) error {
+			// The stream_proxies column is left in place, as SQLite cannot drop
+			// columns without recreating the table.
+			if err := tx.Migrator().DropTable("epg_channel_matches"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable("epg_channels")
+		},
+	}
+}
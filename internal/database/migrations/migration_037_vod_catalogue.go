@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration037VodCatalogue adds the vod_items, series and series_episodes tables
+// holding Xtream video on demand catalogues, the per-source ingestion settings and
+// counts, and the per-proxy VOD and series playlist settings.
+func migration037VodCatalogue() Migration {
+	return Migration{
+		Version:     "037",
+		Description: "Add vod_items, series and series_episodes tables and VOD settings to stream_sources and stream_proxies",
+		Up: func(tx *gorm.DB) error {
+			columns := []struct {
+				table      string
+				name       string
+				definition string
+			}{
+				{"stream_sources", "ingest_vod", "BOOLEAN DEFAULT FALSE"},
+				{"stream_sources", "ingest_series", "BOOLEAN DEFAULT FALSE"},
+				{"stream_sources", "vod_count", "INTEGER DEFAULT 0"},
+				{"stream_sources", "series_count", "INTEGER DEFAULT 0"},
+				{"stream_proxies", "include_vod", "BOOLEAN DEFAULT FALSE"},
+				{"stream_proxies", "include_series", "BOOLEAN DEFAULT FALSE"},
+			}
+			for _, col := range columns {
+				if tx.Migrator().HasColumn(col.table, col.name) {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE " + col.table + " ADD COLUMN " + col.name + " " + col.definition).Error; err != nil {
+					return err
+				}
+			}
+			return tx.AutoMigrate(&models.VodItem{}, &models.Series{}, &models.SeriesEpisode{})
+		},
+		Down: func(tx *gorm.DB) error {
+			// The stream_sources and stream_proxies columns are left in place, as
+			// SQLite cannot drop columns without recreating the table.
+			for _, table := range []string{"series_episodes", "series", "vod_items"} {
+				if err := tx.Migrator().DropTable(table); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration038StalkerMacAddress adds the device MAC address used to authenticate
+// with Stalker portals to stream and EPG sources.
+func migration038StalkerMacAddress() Migration {
+	return Migration{
+		Version:     "038",
+		Description: "Add mac_address to stream_sources and epg_sources",
+		Up: func(tx *gorm.DB) error {
+			for _, table := range []string{"stream_sources", "epg_sources"} {
+				if tx.Migrator().HasColumn(table, "mac_address") {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN mac_address VARCHAR(17)").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the columns
+			// are harmless when left in place.
+			return nil
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration039SourceContentVersion adds the HTTP validators and content digest
+// of the last ingested content to stream and EPG sources, used to skip
+// ingesting unchanged sources.
+func migration039SourceContentVersion() Migration {
+	return Migration{
+		Version:     "039",
+		Description: "Add etag, last_modified and content_hash to stream_sources and epg_sources",
+		Up: func(tx *gorm.DB) error {
+			columns := []struct {
+				name       string
+				definition string
+			}{
+				{"etag", "VARCHAR(512)"},
+				{"last_modified", "VARCHAR(64)"},
+				{"content_hash", "VARCHAR(64)"},
+			}
+			for _, table := range []string{"stream_sources", "epg_sources"} {
+				for _, col := range columns {
+					if tx.Migrator().HasColumn(table, col.name) {
+						continue
+					}
+					if err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN " + col.name + " " + col.definition).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the columns
+			// are harmless when left in place.
+			return nil
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration040SourceProxyURL adds the upstream proxy URL to stream and EPG
+// sources.
+func migration040SourceProxyURL() Migration {
+	return Migration{
+		Version:     "040",
+		Description: "Add proxy_url to stream_sources and epg_sources",
+		Up: func(tx *gorm.DB) error {
+			for _, table := range []string{"stream_sources", "epg_sources"} {
+				if tx.Migrator().HasColumn(table, "proxy_url") {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN proxy_url VARCHAR(512)").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the columns
+			// are harmless when left in place.
+			return nil
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration041StreamHeaders adds custom request headers to stream sources and
+// the headers captured from M3U option lines to channels.
+func migration041StreamHeaders() Migration {
+	return Migration{
+		Version:     "041",
+		Description: "Add custom_headers to stream_sources and stream_headers to channels",
+		Up: func(tx *gorm.DB) error {
+			columns := []struct {
+				table string
+				name  string
+			}{
+				{"stream_sources", "custom_headers"},
+				{"channels", "stream_headers"},
+			}
+			for _, col := range columns {
+				if tx.Migrator().HasColumn(col.table, col.name) {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE " + col.table + " ADD COLUMN " + col.name + " TEXT").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the columns
+			// are harmless when left in place.
+			return nil
+		},
+	}
+}
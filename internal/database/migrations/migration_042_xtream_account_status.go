@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration042XtreamAccountStatus adds the account information polled from
+// Xtream servers to stream sources.
+func migration042XtreamAccountStatus() Migration {
+	return Migration{
+		Version:     "042",
+		Description: "Add Xtream account status columns to stream_sources",
+		Up: func(tx *gorm.DB) error {
+			columns := []struct {
+				name string
+				def  string
+			}{
+				{"account_status", "VARCHAR(32)"},
+				{"account_expires_at", "DATETIME"},
+				{"account_max_connections", "INTEGER DEFAULT 0"},
+				{"account_active_connections", "INTEGER DEFAULT 0"},
+				{"account_checked_at", "DATETIME"},
+			}
+			for _, col := range columns {
+				if tx.Migrator().HasColumn("stream_sources", col.name) {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE stream_sources ADD COLUMN " + col.name + " " + col.def).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the columns
+			// are harmless when left in place.
+			return nil
+		},
+	}
+}
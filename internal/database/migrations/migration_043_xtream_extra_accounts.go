@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration043XtreamExtraAccounts adds the extra accounts of Xtream sources,
+// whose connections are pooled with the primary account's.
+func migration043XtreamExtraAccounts() Migration {
+	return Migration{
+		Version:     "043",
+		Description: "Add extra_accounts to stream_sources",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn("stream_sources", "extra_accounts") {
+				return nil
+			}
+			return tx.Exec("ALTER TABLE stream_sources ADD COLUMN extra_accounts TEXT").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the column
+			// is harmless when left in place.
+			return nil
+		},
+	}
+}
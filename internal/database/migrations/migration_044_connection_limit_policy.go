@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// migration044ConnectionLimitPolicy adds the connection limit policy of stream
+// sources and proxies, and the proxy priority it ranks sessions by.
+func migration044ConnectionLimitPolicy() Migration {
+	return Migration{
+		Version:     "044",
+		Description: "Add connection_limit_policy to stream_sources and stream_proxies",
+		Up: func(tx *gorm.DB) error {
+			columns := []struct {
+				table string
+				name  string
+				def   string
+			}{
+				{"stream_sources", "connection_limit_policy", "VARCHAR(30)"},
+				{"stream_proxies", "connection_limit_policy", "VARCHAR(30)"},
+				{"stream_proxies", "priority", "INTEGER DEFAULT 0"},
+			}
+			for _, col := range columns {
+				if tx.Migrator().HasColumn(col.table, col.name) {
+					continue
+				}
+				if err := tx.Exec("ALTER TABLE " + col.table + " ADD COLUMN " + col.name + " " + col.def).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// SQLite cannot drop columns without recreating the table; the columns
+			// are harmless when left in place.
+			return nil
+		},
+	}
+}
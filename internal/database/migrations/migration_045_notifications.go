@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration045Notifications adds the notification target and delivery log tables.
+func migration045Notifications() Migration {
+	return Migration{
+		Version:     "045",
+		Description: "Add notification_targets and notification_deliveries tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.NotificationTarget{}, &models.NotificationDelivery{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("notification_deliveries", "notification_targets")
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration046ViewingSessions adds the viewing session table for channel analytics.
+func migration046ViewingSessions() Migration {
+	return Migration{
+		Version:     "046",
+		Description: "Add viewing_sessions table",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.ViewingSession{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("viewing_sessions")
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration047LogEntries adds the persistent log store table.
+func migration047LogEntries() Migration {
+	return Migration{
+		Version:     "047",
+		Description: "Add log_entries table",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.LogEntry{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("log_entries")
+		},
+	}
+}
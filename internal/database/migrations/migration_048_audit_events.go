@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration048AuditEvents adds the configuration audit trail table.
+func migration048AuditEvents() Migration {
+	return Migration{
+		Version:     "048",
+		Description: "Add audit_events table",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.AuditEvent{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("audit_events")
+		},
+	}
+}
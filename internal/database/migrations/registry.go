@@ -38,6 +38,25 @@ import (
 // - 027: Hard-delete duplicate stream grouping rules left by migration 015
 // - 028: Fix EPG category rule expressions: replace broken ?= with SET_IF_EMPTY keyword
 // - 029: Hard-delete Group * Channels stream mapping rules (superseded by EPG category inference)
+// - 030: Add users, auth_sessions and api_keys tables for admin authentication
+// - 031: Add viewers table for per-viewer stream credentials
+// - 032: Add catch-up archive columns to channels
+// - 033: Add recordings table for scheduled DVR recordings
+// - 034: Add recording_rules table and episode columns to recordings
+// - 035: Add channel dedup settings and proxy_channel_alternates table
+// - 036: Add epg_channels and epg_channel_matches tables and auto_match_epg to stream_proxies
+// - 037: Add vod_items, series and series_episodes tables and VOD settings to stream_sources and stream_proxies
+// - 038: Add mac_address to stream_sources and epg_sources
+// - 039: Add etag, last_modified and content_hash to stream_sources and epg_sources
+// - 040: Add proxy_url to stream_sources and epg_sources
+// - 041: Add custom_headers to stream_sources and stream_headers to channels
+// - 042: Add Xtream account status columns to stream_sources
+// - 043: Add extra_accounts to stream_sources
+// - 044: Add connection_limit_policy to stream_sources and stream_proxies
+// - 045: Add notification_targets and notification_deliveries tables
+// - 046: Add viewing_sessions table
+// - 047: Add log_entries table
+// - 048: Add audit_events table
 func AllMigrations() []Migration {
 	return []Migration{
 		migration001Schema(),
@@ -69,6 +88,25 @@ func AllMigrations() []Migration {
 		migration027DedupGroupingRules(),
 		migration028FixEpgCategoryExpressions(),
 		migration029RemoveGroupChannelRules(),
+		migration030Auth(),
+		migration031Viewers(),
+		migration032ChannelCatchup(),
+		migration033Recordings(),
+		migration034RecordingRules(),
+		migration035ChannelDedup(),
+		migration036EpgChannelMatching(),
+		migration037VodCatalogue(),
+		migration038StalkerMacAddress(),
+		migration039SourceContentVersion(),
+		migration040SourceProxyURL(),
+		migration041StreamHeaders(),
+		migration042XtreamAccountStatus(),
+		migration043XtreamExtraAccounts(),
+		migration044ConnectionLimitPolicy(),
+		migration045Notifications(),
+		migration046ViewingSessions(),
+		migration047LogEntries(),
+		migration048AuditEvents(),
 	}
 }
 
@@ -56,7 +56,26 @@ func TestAllMigrations_ReturnsExpectedCount(t *testing.T) {
 	// 027: Hard-delete duplicate stream grouping rules left by migration 015
 	// 028: Fix EPG category rule expressions: replace broken ?= with SET_IF_EMPTY keyword
 	// 029: Hard-delete Group * Channels stream mapping rules (superseded by EPG category inference)
-	assert.Len(t, migrations, 29)
+	// 030: Add users, auth_sessions and api_keys tables for admin authentication
+	// 031: Add viewers table for per-viewer stream credentials
+	// 032: Add catch-up archive columns to channels
+	// 033: Add recordings table for scheduled DVR recordings
+	// 034: Add recording_rules table and episode columns to recordings
+	// 035: Add channel dedup settings and proxy_channel_alternates table
+	// 036: Add epg_channels and epg_channel_matches tables and auto_match_epg to stream_proxies
+	// 037: Add vod_items, series and series_episodes tables and VOD settings to stream_sources and stream_proxies
+	// 038: Add mac_address to stream_sources and epg_sources
+	// 039: Add etag, last_modified and content_hash to stream_sources and epg_sources
+	// 040: Add proxy_url to stream_sources and epg_sources
+	// 041: Add custom_headers to stream_sources and stream_headers to channels
+	// 042: Add Xtream account status columns to stream_sources
+	// 043: Add extra_accounts to stream_sources
+	// 044: Add connection_limit_policy to stream_sources and stream_proxies
+	// 045: Add notification_targets and notification_deliveries tables
+	// 046: Add viewing_sessions table
+	// 047: Add log_entries table
+	// 048: Add audit_events table
+	assert.Len(t, migrations, 48)
 }
 
 func TestAllMigrations_VersionsAreUnique(t *testing.T) {
@@ -126,10 +145,10 @@ func TestMigrator_Status(t *testing.T) {
 	migrator := NewMigrator(db, nil)
 	migrator.RegisterAll(AllMigrations())
 
-	// Before running migrations (29 migrations total)
+	// Before running migrations (48 migrations total)
 	statuses, err := migrator.Status(ctx)
 	require.NoError(t, err)
-	assert.Len(t, statuses, 29)
+	assert.Len(t, statuses, 48)
 
 	for _, s := range statuses {
 		assert.False(t, s.Applied)
@@ -167,6 +186,127 @@ func TestMigrator_Down_RollsBackLastMigration(t *testing.T) {
 	assert.True(t, db.Migrator().HasTable("backup_settings"))
 	assert.True(t, db.Migrator().HasTable("ffmpegd_config"))
 	assert.True(t, db.Migrator().HasTable("encoder_overrides"))
+	assert.True(t, db.Migrator().HasTable("users"))
+	assert.True(t, db.Migrator().HasTable("auth_sessions"))
+	assert.True(t, db.Migrator().HasTable("api_keys"))
+	assert.True(t, db.Migrator().HasTable("viewers"))
+	assert.True(t, db.Migrator().HasTable("recordings"))
+	assert.True(t, db.Migrator().HasTable("recording_rules"))
+	assert.True(t, db.Migrator().HasTable("proxy_channel_alternates"))
+	assert.True(t, db.Migrator().HasTable("epg_channels"))
+	assert.True(t, db.Migrator().HasTable("epg_channel_matches"))
+	assert.True(t, db.Migrator().HasTable("vod_items"))
+	assert.True(t, db.Migrator().HasTable("series"))
+	assert.True(t, db.Migrator().HasTable("series_episodes"))
+	assert.True(t, db.Migrator().HasTable("notification_targets"))
+	assert.True(t, db.Migrator().HasTable("notification_deliveries"))
+	assert.True(t, db.Migrator().HasTable("viewing_sessions"))
+	assert.True(t, db.Migrator().HasTable("log_entries"))
+	assert.True(t, db.Migrator().HasTable("audit_events"))
+
+	// Roll back migration 048 (audit_events)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("audit_events"))
+
+	// Roll back migration 047 (log_entries)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("log_entries"))
+
+	// Roll back migration 046 (viewing_sessions)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("viewing_sessions"))
+
+	// Roll back migration 045 (notification tables)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("notification_targets"))
+	assert.False(t, db.Migrator().HasTable("notification_deliveries"))
+
+	// Roll back migration 044 (connection limit policy columns)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 043 (extra_accounts column is left in place)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 042 (account columns are left in place)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 041 (header columns are left in place)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 040 (proxy_url columns are left in place)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 039 (content version columns are left in place)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 038 (mac_address columns are left in place)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 037 (drops vod_items, series and series_episodes tables)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("vod_items"))
+	assert.False(t, db.Migrator().HasTable("series"))
+	assert.False(t, db.Migrator().HasTable("series_episodes"))
+
+	// Roll back migration 036 (drops epg_channels and epg_channel_matches tables)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("epg_channels"))
+	assert.False(t, db.Migrator().HasTable("epg_channel_matches"))
+
+	// Roll back migration 035 (drops proxy_channel_alternates table)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("proxy_channel_alternates"))
+
+	// Roll back migration 034 (recording rules)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("recording_rules"))
+
+	// Roll back migration 033 (drops recordings table)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("recordings"))
+
+	// Roll back migration 032 (catch-up columns are left in place)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	// Roll back migration 031 (viewers table)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("viewers"))
+
+	// Roll back migration 030 (auth tables)
+	err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	assert.False(t, db.Migrator().HasTable("users"))
+	assert.False(t, db.Migrator().HasTable("auth_sessions"))
+	assert.False(t, db.Migrator().HasTable("api_keys"))
 
 	// Roll back migration 029 (remove Group * Channels rules - no-op down)
 	err = migrator.Down(ctx)
@@ -417,10 +557,10 @@ func TestMigrator_Pending(t *testing.T) {
 	migrator := NewMigrator(db, nil)
 	migrator.RegisterAll(AllMigrations())
 
-	// All should be pending initially (29 migrations total)
+	// All should be pending initially (48 migrations total)
 	pending, err := migrator.Pending(ctx)
 	require.NoError(t, err)
-	assert.Len(t, pending, 29)
+	assert.Len(t, pending, 48)
 
 	// Run migrations
 	err = migrator.Up(ctx)
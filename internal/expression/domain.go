@@ -19,6 +19,13 @@ const (
 
 	// DomainClientDetection is for client detection rule expressions.
 	DomainClientDetection ExpressionDomain = "client_detection"
+
+	// DomainRecordingRule is for series recording rule expressions, which match
+	// EPG programmes together with the channel airing them.
+	DomainRecordingRule ExpressionDomain = "recording_rule"
+
+	// DomainVODFilter is for movie and series filtering expressions.
+	DomainVODFilter ExpressionDomain = "vod_filter"
 )
 
 // ParseExpressionDomain parses a domain string into an ExpressionDomain.
@@ -35,6 +42,10 @@ func ParseExpressionDomain(s string) (ExpressionDomain, bool) {
 		return DomainEPGMapping, true
 	case "client_detection", "client":
 		return DomainClientDetection, true
+	case "recording_rule", "recording":
+		return DomainRecordingRule, true
+	case "vod_filter", "vod":
+		return DomainVODFilter, true
 	default:
 		return "", false
 	}
@@ -105,6 +105,18 @@ func NewProgramEvalContext(fields map[string]string) *ProgramEvalContext {
 	}
 }
 
+// VodEvalContext provides field access for movie and series records.
+type VodEvalContext struct {
+	*BaseEvalContext
+}
+
+// NewVodEvalContext creates a new movie or series eval context.
+func NewVodEvalContext(fields map[string]string) *VodEvalContext {
+	return &VodEvalContext{
+		BaseEvalContext: newBaseEvalContext(fields, DefaultRegistry()),
+	}
+}
+
 // MapEvalContext is a simple map-based eval context without alias resolution.
 type MapEvalContext struct {
 	fields map[string]string
@@ -31,6 +31,7 @@ const (
 	DomainFilter  FieldDomain = "filter"  // Filter rule fields
 	DomainRule    FieldDomain = "rule"    // Data mapping rule fields
 	DomainRequest FieldDomain = "request" // HTTP request context fields (for client detection)
+	DomainVOD     FieldDomain = "vod"     // Movie and series fields
 )
 
 // String returns the string representation of the field domain.
@@ -179,6 +180,7 @@ func DefaultRegistry() *FieldRegistry {
 		defaultRegistry = NewFieldRegistry()
 		registerChannelFields(defaultRegistry)
 		registerEPGFields(defaultRegistry)
+		registerVODFields(defaultRegistry)
 		registerSourceMetadataFields(defaultRegistry)
 		registerRequestContextFields(defaultRegistry)
 	})
@@ -224,7 +226,7 @@ func registerChannelFields(r *FieldRegistry) {
 		Type:        FieldTypeString,
 		Description: "The group/category for the channel",
 		Aliases:     []string{"group", "category"},
-		Domains:     []FieldDomain{DomainStream, DomainFilter, DomainRule},
+		Domains:     []FieldDomain{DomainStream, DomainFilter, DomainRule, DomainVOD},
 	})
 
 	r.Register(&FieldDefinition{
@@ -280,7 +282,7 @@ func registerChannelFields(r *FieldRegistry) {
 		Type:        FieldTypeBoolean,
 		Description: "Whether the stream contains adult content",
 		Aliases:     []string{"adult"},
-		Domains:     []FieldDomain{DomainStream, DomainFilter},
+		Domains:     []FieldDomain{DomainStream, DomainFilter, DomainVOD},
 	})
 }
 
@@ -365,13 +367,56 @@ func registerEPGFields(r *FieldRegistry) {
 	})
 }
 
+// registerVODFields registers movie and series fields. The category and adult
+// flag reuse group_title and is_adult.
+func registerVODFields(r *FieldRegistry) {
+	r.Register(&FieldDefinition{
+		Name:        "vod_title",
+		Type:        FieldTypeString,
+		Description: "The title of the movie or series",
+		Aliases:     []string{"vod_name"},
+		Domains:     []FieldDomain{DomainVOD},
+	})
+
+	r.Register(&FieldDefinition{
+		Name:        "vod_type",
+		Type:        FieldTypeString,
+		Description: "The kind of content (movie, series)",
+		Domains:     []FieldDomain{DomainVOD},
+		ReadOnly:    true,
+	})
+
+	r.Register(&FieldDefinition{
+		Name:        "vod_genre",
+		Type:        FieldTypeString,
+		Description: "The genres of the series, as published by the provider",
+		Domains:     []FieldDomain{DomainVOD},
+	})
+
+	r.Register(&FieldDefinition{
+		Name:        "vod_year",
+		Type:        FieldTypeInteger,
+		Description: "The release year (0 if unknown)",
+		Aliases:     []string{"year"},
+		Domains:     []FieldDomain{DomainVOD},
+	})
+
+	r.Register(&FieldDefinition{
+		Name:        "vod_rating",
+		Type:        FieldTypeFloat,
+		Description: "The provider rating, usually out of 10 (0 if unrated)",
+		Aliases:     []string{"rating"},
+		Domains:     []FieldDomain{DomainVOD},
+	})
+}
+
 // registerSourceMetadataFields registers source metadata fields.
 func registerSourceMetadataFields(r *FieldRegistry) {
 	r.Register(&FieldDefinition{
 		Name:        "source_name",
 		Type:        FieldTypeString,
 		Description: "The name of the source that provided this data",
-		Domains:     []FieldDomain{DomainStream, DomainEPG, DomainFilter},
+		Domains:     []FieldDomain{DomainStream, DomainEPG, DomainFilter, DomainVOD},
 		ReadOnly:    true,
 	})
 
@@ -379,7 +424,7 @@ func registerSourceMetadataFields(r *FieldRegistry) {
 		Name:        "source_type",
 		Type:        FieldTypeString,
 		Description: "The type of source (m3u, xtream, xmltv)",
-		Domains:     []FieldDomain{DomainStream, DomainEPG, DomainFilter},
+		Domains:     []FieldDomain{DomainStream, DomainEPG, DomainFilter, DomainVOD},
 		ReadOnly:    true,
 	})
 
@@ -387,7 +432,7 @@ func registerSourceMetadataFields(r *FieldRegistry) {
 		Name:        "source_url",
 		Type:        FieldTypeString,
 		Description: "The URL of the source",
-		Domains:     []FieldDomain{DomainStream, DomainEPG, DomainFilter},
+		Domains:     []FieldDomain{DomainStream, DomainEPG, DomainFilter, DomainVOD},
 		ReadOnly:    true,
 	})
 }
@@ -140,6 +140,10 @@ func (v *Validator) getValidFieldsForDomains(domains []ExpressionDomain) map[str
 			fieldDomains = []FieldDomain{DomainEPG, DomainFilter, DomainRule}
 		case DomainClientDetection:
 			fieldDomains = []FieldDomain{DomainRequest}
+		case DomainRecordingRule:
+			fieldDomains = []FieldDomain{DomainStream, DomainEPG, DomainFilter}
+		case DomainVODFilter:
+			fieldDomains = []FieldDomain{DomainVOD}
 		default:
 			fieldDomains = []FieldDomain{DomainStream, DomainEPG, DomainFilter, DomainRule}
 		}
@@ -102,6 +102,40 @@ func TestValidator_EPGDomain(t *testing.T) {
 	assert.True(t, result.IsValid)
 }
 
+func TestValidator_RecordingRuleDomain(t *testing.T) {
+	v := NewValidator(nil)
+
+	// Programme and channel fields can be combined in recording rules
+	result := v.Validate(`programme_title matches "^Match of the Day" AND channel_name contains "BBC One"`, DomainRecordingRule)
+	assert.True(t, result.IsValid)
+
+	result = v.Validate(`programme_season equals "3" AND source_name equals "Provider"`, DomainRecordingRule)
+	assert.True(t, result.IsValid)
+
+	// Request fields are not available
+	result = v.Validate(`user_agent contains "VLC"`, DomainRecordingRule)
+	assert.False(t, result.IsValid)
+
+	domain, ok := ParseExpressionDomain("recording")
+	assert.True(t, ok)
+	assert.Equal(t, DomainRecordingRule, domain)
+}
+
+func TestValidator_VODFilterDomain(t *testing.T) {
+	v := NewValidator(nil)
+
+	result := v.Validate(`vod_type equals "movie" AND year >= 2000 AND group_title contains "Action"`, DomainVODFilter)
+	assert.True(t, result.IsValid)
+
+	// Channel fields are not available
+	result = v.Validate(`channel_name contains "HD"`, DomainVODFilter)
+	assert.False(t, result.IsValid)
+
+	domain, ok := ParseExpressionDomain("vod")
+	assert.True(t, ok)
+	assert.Equal(t, DomainVODFilter, domain)
+}
+
 func TestValidator_FieldAlias(t *testing.T) {
 	v := NewValidator(nil)
 
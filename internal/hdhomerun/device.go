@@ -0,0 +1,172 @@
+// Package hdhomerun implements HDHomeRun tuner emulation for tvarr.
+// It provides the JSON/XML wire formats that Plex, Jellyfin and Emby expect
+// from a network tuner, plus optional SSDP and HDHomeRun UDP discovery so the
+// media servers can find the emulated tuners without manual configuration.
+package hdhomerun
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Fixed device identity values reported to media servers.
+// These mirror a real HDHomeRun CONNECT so clients enable their tuner support.
+const (
+	Manufacturer    = "Silicondust"
+	ModelNumber     = "HDTC-2US"
+	FirmwareName    = "hdhomeruntc_atsc"
+	FirmwareVersion = "20200101"
+	DeviceAuth      = "tvarr"
+)
+
+// Device describes a single emulated HDHomeRun tuner.
+type Device struct {
+	// DeviceID is the 32-bit HDHomeRun device identifier.
+	DeviceID uint32
+	// FriendlyName is the name shown in media server UIs.
+	FriendlyName string
+	// BaseURL is the absolute URL under which the device endpoints are served.
+	BaseURL string
+	// TunerCount is the number of concurrent streams the device advertises.
+	TunerCount int
+}
+
+// DeviceIDString returns the device ID formatted as 8 uppercase hex digits.
+func (d *Device) DeviceIDString() string {
+	return fmt.Sprintf("%08X", d.DeviceID)
+}
+
+// LineupURL returns the absolute URL of the device lineup.
+func (d *Device) LineupURL() string {
+	return d.BaseURL + "/lineup.json"
+}
+
+// DeviceXMLURL returns the absolute URL of the UPnP device description.
+func (d *Device) DeviceXMLURL() string {
+	return d.BaseURL + "/device.xml"
+}
+
+// UDN returns the UPnP unique device name for the device.
+func (d *Device) UDN() string {
+	return "uuid:" + strings.ToLower(d.DeviceIDString())
+}
+
+// DeviceIDFromString derives a stable device ID from an arbitrary identifier
+// (typically a proxy ULID), so a proxy keeps the same tuner identity across restarts.
+func DeviceIDFromString(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	id := h.Sum32()
+	// 0xFFFFFFFF is the discovery wildcard and must never be used as an ID.
+	if id == wildcardDeviceID {
+		id--
+	}
+	return id
+}
+
+// DiscoverResponse is the discover.json payload.
+type DiscoverResponse struct {
+	FriendlyName    string `json:"FriendlyName"`
+	Manufacturer    string `json:"Manufacturer"`
+	ModelNumber     string `json:"ModelNumber"`
+	FirmwareName    string `json:"FirmwareName"`
+	FirmwareVersion string `json:"FirmwareVersion"`
+	DeviceID        string `json:"DeviceID"`
+	DeviceAuth      string `json:"DeviceAuth"`
+	BaseURL         string `json:"BaseURL"`
+	LineupURL       string `json:"LineupURL"`
+	TunerCount      int    `json:"TunerCount"`
+}
+
+// NewDiscoverResponse builds the discover.json payload for a device.
+func NewDiscoverResponse(d *Device) DiscoverResponse {
+	return DiscoverResponse{
+		FriendlyName:    d.FriendlyName,
+		Manufacturer:    Manufacturer,
+		ModelNumber:     ModelNumber,
+		FirmwareName:    FirmwareName,
+		FirmwareVersion: FirmwareVersion,
+		DeviceID:        d.DeviceIDString(),
+		DeviceAuth:      DeviceAuth,
+		BaseURL:         d.BaseURL,
+		LineupURL:       d.LineupURL(),
+		TunerCount:      d.TunerCount,
+	}
+}
+
+// LineupEntry is a single channel in lineup.json.
+type LineupEntry struct {
+	GuideNumber string `json:"GuideNumber"`
+	GuideName   string `json:"GuideName"`
+	URL         string `json:"URL"`
+}
+
+// LineupStatus is the lineup_status.json payload.
+// tvarr never scans, so the status always reports an idle, scannable cable lineup.
+type LineupStatus struct {
+	ScanInProgress int      `json:"ScanInProgress"`
+	ScanPossible   int      `json:"ScanPossible"`
+	Source         string   `json:"Source"`
+	SourceList     []string `json:"SourceList"`
+}
+
+// NewLineupStatus returns the static lineup status reported by emulated tuners.
+func NewLineupStatus() LineupStatus {
+	return LineupStatus{
+		ScanInProgress: 0,
+		ScanPossible:   1,
+		Source:         "Cable",
+		SourceList:     []string{"Cable"},
+	}
+}
+
+// DeviceDescription is the UPnP device.xml document.
+type DeviceDescription struct {
+	XMLName     xml.Name          `xml:"urn:schemas-upnp-org:device-1-0 root"`
+	URLBase     string            `xml:"URLBase"`
+	SpecVersion upnpSpecVersion   `xml:"specVersion"`
+	Device      upnpDeviceElement `xml:"device"`
+}
+
+type upnpSpecVersion struct {
+	Major int `xml:"major"`
+	Minor int `xml:"minor"`
+}
+
+type upnpDeviceElement struct {
+	DeviceType   string `xml:"deviceType"`
+	FriendlyName string `xml:"friendlyName"`
+	Manufacturer string `xml:"manufacturer"`
+	ModelName    string `xml:"modelName"`
+	ModelNumber  string `xml:"modelNumber"`
+	SerialNumber string `xml:"serialNumber"`
+	UDN          string `xml:"UDN"`
+}
+
+// NewDeviceDescription builds the device.xml document for a device.
+func NewDeviceDescription(d *Device) DeviceDescription {
+	return DeviceDescription{
+		URLBase:     d.BaseURL,
+		SpecVersion: upnpSpecVersion{Major: 1, Minor: 0},
+		Device: upnpDeviceElement{
+			DeviceType:   ssdpDeviceType,
+			FriendlyName: d.FriendlyName,
+			Manufacturer: Manufacturer,
+			ModelName:    ModelNumber,
+			ModelNumber:  ModelNumber,
+			SerialNumber: d.DeviceIDString(),
+			UDN:          d.UDN(),
+		},
+	}
+}
+
+// MarshalDeviceXML renders the device.xml document including the XML header.
+func MarshalDeviceXML(d *Device) ([]byte, error) {
+	body, err := xml.MarshalIndent(NewDeviceDescription(d), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling device description: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
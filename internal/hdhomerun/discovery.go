@@ -0,0 +1,425 @@
+package hdhomerun
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HDHomeRun UDP discovery protocol constants (see libhdhomerun hdhomerun_pkt.h).
+const (
+	// DiscoveryPort is the UDP port HDHomeRun clients broadcast discovery requests to.
+	DiscoveryPort = 65001
+
+	typeDiscoverReq uint16 = 0x0002
+	typeDiscoverRpy uint16 = 0x0003
+
+	tagDeviceType    byte = 0x01
+	tagDeviceID      byte = 0x02
+	tagTunerCount    byte = 0x10
+	tagLineupURL     byte = 0x27
+	tagBaseURL       byte = 0x2A
+	tagDeviceAuthStr byte = 0x2B
+
+	deviceTypeTuner    uint32 = 0x00000001
+	deviceTypeWildcard uint32 = 0xFFFFFFFF
+	wildcardDeviceID   uint32 = 0xFFFFFFFF
+
+	maxPacketSize = 1460
+)
+
+// SSDP constants.
+const (
+	ssdpAddr       = "239.255.255.250:1900"
+	ssdpDeviceType = "urn:schemas-upnp-org:device:MediaServer:1"
+	ssdpMaxAge     = 1800
+)
+
+// DeviceProvider returns the devices that should currently answer discovery.
+// baseURL is the server root (scheme://host:port) reachable by the requesting client;
+// implementations append their own per-device path to it.
+type DeviceProvider func(ctx context.Context, baseURL string) ([]Device, error)
+
+// DiscoveryConfig configures the discovery responders.
+type DiscoveryConfig struct {
+	// BaseURL is the externally reachable server root. When empty, the base URL
+	// is derived from the local address used to reach the requesting client.
+	BaseURL string
+	// HTTPPort is the HTTP server port used when deriving the base URL.
+	HTTPPort int
+	// ServerName is reported in the SSDP SERVER header.
+	ServerName string
+}
+
+// Discoverer answers SSDP M-SEARCH and HDHomeRun UDP discovery requests
+// for the devices returned by its DeviceProvider.
+type Discoverer struct {
+	config   DiscoveryConfig
+	provider DeviceProvider
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	conns []net.PacketConn
+	wg    sync.WaitGroup
+}
+
+// NewDiscoverer creates a new discovery responder.
+func NewDiscoverer(config DiscoveryConfig, provider DeviceProvider) *Discoverer {
+	if config.ServerName == "" {
+		config.ServerName = "tvarr"
+	}
+	return &Discoverer{
+		config:   config,
+		provider: provider,
+		logger:   slog.Default(),
+	}
+}
+
+// WithLogger sets the logger for the discoverer.
+func (d *Discoverer) WithLogger(logger *slog.Logger) *Discoverer {
+	if logger != nil {
+		d.logger = logger
+	}
+	return d
+}
+
+// Start opens the discovery sockets and begins answering requests.
+// Either responder failing to bind is logged but not fatal, so a host that already
+// runs a real HDHomeRun or another SSDP stack can still use the other method.
+func (d *Discoverer) Start(ctx context.Context) error {
+	var started int
+
+	udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: DiscoveryPort})
+	if err != nil {
+		d.logger.Warn("hdhomerun discovery listener unavailable",
+			slog.Int("port", DiscoveryPort),
+			slog.String("error", err.Error()))
+	} else {
+		d.track(udpConn)
+		d.wg.Go(func() { d.serveHDHomeRun(ctx, udpConn) })
+		started++
+	}
+
+	group, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return fmt.Errorf("resolving SSDP address: %w", err)
+	}
+	ssdpConn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		d.logger.Warn("ssdp discovery listener unavailable",
+			slog.String("address", ssdpAddr),
+			slog.String("error", err.Error()))
+	} else {
+		d.track(ssdpConn)
+		d.wg.Go(func() { d.serveSSDP(ctx, ssdpConn) })
+		started++
+	}
+
+	if started == 0 {
+		return errors.New("no discovery listeners could be started")
+	}
+
+	go func() {
+		<-ctx.Done()
+		d.Stop()
+	}()
+
+	d.logger.Info("hdhomerun discovery started", slog.Int("listeners", started))
+	return nil
+}
+
+// Stop closes all discovery sockets and waits for the responders to exit.
+func (d *Discoverer) Stop() {
+	d.mu.Lock()
+	conns := d.conns
+	d.conns = nil
+	d.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.Close()
+	}
+	d.wg.Wait()
+}
+
+func (d *Discoverer) track(c net.PacketConn) {
+	d.mu.Lock()
+	d.conns = append(d.conns, c)
+	d.mu.Unlock()
+}
+
+// serveHDHomeRun answers HDHomeRun discover requests broadcast on UDP 65001.
+func (d *Discoverer) serveHDHomeRun(ctx context.Context, conn *net.UDPConn) {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			d.logger.Debug("hdhomerun discovery read failed", slog.String("error", err.Error()))
+			continue
+		}
+
+		req, err := ParseDiscoverRequest(buf[:n])
+		if err != nil {
+			d.logger.Debug("ignoring malformed hdhomerun discovery packet",
+				slog.String("remote_addr", addr.String()),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		devices, err := d.provider(ctx, d.baseURLFor(addr))
+		if err != nil {
+			d.logger.Warn("failed to list hdhomerun devices", slog.String("error", err.Error()))
+			continue
+		}
+
+		for i := range devices {
+			if !req.Matches(devices[i].DeviceID) {
+				continue
+			}
+			if _, err := conn.WriteToUDP(EncodeDiscoverReply(&devices[i]), addr); err != nil {
+				d.logger.Debug("hdhomerun discovery reply failed",
+					slog.String("remote_addr", addr.String()),
+					slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// serveSSDP answers SSDP M-SEARCH requests with a unicast reply per device.
+func (d *Discoverer) serveSSDP(ctx context.Context, conn *net.UDPConn) {
+	buf := make([]byte, 8192)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			d.logger.Debug("ssdp read failed", slog.String("error", err.Error()))
+			continue
+		}
+
+		st, ok := parseMSearch(buf[:n])
+		if !ok {
+			continue
+		}
+
+		devices, err := d.provider(ctx, d.baseURLFor(addr))
+		if err != nil {
+			d.logger.Warn("failed to list hdhomerun devices", slog.String("error", err.Error()))
+			continue
+		}
+
+		// Replies must be sent from a unicast socket, not the multicast listener.
+		reply, err := net.DialUDP("udp4", nil, addr)
+		if err != nil {
+			continue
+		}
+		for i := range devices {
+			target, match := ssdpSearchTarget(st, &devices[i])
+			if !match {
+				continue
+			}
+			_, _ = reply.Write(EncodeSSDPResponse(&devices[i], target, d.config.ServerName))
+		}
+		_ = reply.Close()
+	}
+}
+
+// baseURLFor returns the server root a client at addr should use.
+func (d *Discoverer) baseURLFor(addr *net.UDPAddr) string {
+	if d.config.BaseURL != "" {
+		return strings.TrimSuffix(d.config.BaseURL, "/")
+	}
+
+	host := "localhost"
+	// Dialing UDP sends nothing; it only asks the kernel which local address routes to the client.
+	if conn, err := net.DialUDP("udp4", nil, addr); err == nil {
+		if local, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+			host = local.IP.String()
+		}
+		_ = conn.Close()
+	}
+	return fmt.Sprintf("http://%s", net.JoinHostPort(host, fmt.Sprintf("%d", d.config.HTTPPort)))
+}
+
+// DiscoverRequest is a decoded HDHomeRun discover request.
+type DiscoverRequest struct {
+	DeviceType uint32
+	DeviceID   uint32
+}
+
+// Matches reports whether a tuner with the given ID should answer the request.
+func (r DiscoverRequest) Matches(deviceID uint32) bool {
+	if r.DeviceType != deviceTypeTuner && r.DeviceType != deviceTypeWildcard {
+		return false
+	}
+	return r.DeviceID == wildcardDeviceID || r.DeviceID == deviceID
+}
+
+// ParseDiscoverRequest decodes and validates an HDHomeRun discover request packet.
+// Missing device type or ID tags are treated as wildcards.
+func ParseDiscoverRequest(pkt []byte) (DiscoverRequest, error) {
+	req := DiscoverRequest{DeviceType: deviceTypeWildcard, DeviceID: wildcardDeviceID}
+
+	pktType, payload, err := decodePacket(pkt)
+	if err != nil {
+		return req, err
+	}
+	if pktType != typeDiscoverReq {
+		return req, fmt.Errorf("unexpected packet type 0x%04x", pktType)
+	}
+
+	for len(payload) > 0 {
+		tag := payload[0]
+		length, n := decodeVarLen(payload[1:])
+		if n == 0 || len(payload) < 1+n+length {
+			return req, errors.New("truncated tag")
+		}
+		value := payload[1+n : 1+n+length]
+		payload = payload[1+n+length:]
+
+		switch tag {
+		case tagDeviceType:
+			if len(value) == 4 {
+				req.DeviceType = binary.BigEndian.Uint32(value)
+			}
+		case tagDeviceID:
+			if len(value) == 4 {
+				req.DeviceID = binary.BigEndian.Uint32(value)
+			}
+		}
+	}
+
+	return req, nil
+}
+
+// EncodeDiscoverReply builds an HDHomeRun discover reply packet for a device.
+func EncodeDiscoverReply(d *Device) []byte {
+	var payload bytes.Buffer
+
+	u32 := make([]byte, 4)
+	binary.BigEndian.PutUint32(u32, deviceTypeTuner)
+	writeTLV(&payload, tagDeviceType, u32)
+
+	u32 = make([]byte, 4)
+	binary.BigEndian.PutUint32(u32, d.DeviceID)
+	writeTLV(&payload, tagDeviceID, u32)
+
+	tuners := min(max(d.TunerCount, 1), 255)
+	writeTLV(&payload, tagTunerCount, []byte{byte(tuners)})
+	writeTLV(&payload, tagDeviceAuthStr, []byte(DeviceAuth))
+	writeTLV(&payload, tagBaseURL, []byte(d.BaseURL))
+	writeTLV(&payload, tagLineupURL, []byte(d.LineupURL()))
+
+	return encodePacket(typeDiscoverRpy, payload.Bytes())
+}
+
+// encodePacket frames a payload as: type (BE16), length (BE16), payload, CRC32 (LE).
+func encodePacket(pktType uint16, payload []byte) []byte {
+	pkt := make([]byte, 4, 4+len(payload)+4)
+	binary.BigEndian.PutUint16(pkt[0:2], pktType)
+	binary.BigEndian.PutUint16(pkt[2:4], uint16(len(payload)))
+	pkt = append(pkt, payload...)
+	return binary.LittleEndian.AppendUint32(pkt, crc32.ChecksumIEEE(pkt))
+}
+
+// decodePacket validates the framing and CRC of a packet and returns its type and payload.
+func decodePacket(pkt []byte) (uint16, []byte, error) {
+	if len(pkt) < 8 {
+		return 0, nil, errors.New("packet too short")
+	}
+	length := int(binary.BigEndian.Uint16(pkt[2:4]))
+	if len(pkt) != 4+length+4 {
+		return 0, nil, errors.New("packet length mismatch")
+	}
+	body := pkt[:4+length]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(pkt[4+length:]) {
+		return 0, nil, errors.New("packet CRC mismatch")
+	}
+	return binary.BigEndian.Uint16(pkt[0:2]), pkt[4 : 4+length], nil
+}
+
+// writeTLV appends a tag/length/value triple using HDHomeRun variable-length encoding.
+func writeTLV(buf *bytes.Buffer, tag byte, value []byte) {
+	buf.WriteByte(tag)
+	if len(value) <= 127 {
+		buf.WriteByte(byte(len(value)))
+	} else {
+		buf.WriteByte(byte(len(value)&0x7F) | 0x80)
+		buf.WriteByte(byte(len(value) >> 7))
+	}
+	buf.Write(value)
+}
+
+// decodeVarLen decodes a 1 or 2 byte length, returning the length and bytes consumed (0 on error).
+func decodeVarLen(b []byte) (int, int) {
+	if len(b) == 0 {
+		return 0, 0
+	}
+	if b[0]&0x80 == 0 {
+		return int(b[0]), 1
+	}
+	if len(b) < 2 {
+		return 0, 0
+	}
+	return int(b[0]&0x7F) | int(b[1])<<7, 2
+}
+
+// parseMSearch returns the search target of an SSDP M-SEARCH request.
+func parseMSearch(pkt []byte) (string, bool) {
+	lines := strings.Split(string(pkt), "\r\n")
+	if len(lines) == 0 || !strings.HasPrefix(strings.ToUpper(lines[0]), "M-SEARCH ") {
+		return "", false
+	}
+	for _, line := range lines[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "ST") {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// ssdpSearchTarget returns the ST value to answer with, and whether the device matches the search.
+func ssdpSearchTarget(st string, d *Device) (string, bool) {
+	switch st {
+	case "ssdp:all":
+		return ssdpDeviceType, true
+	case "upnp:rootdevice", ssdpDeviceType:
+		return st, true
+	case d.UDN():
+		return st, true
+	default:
+		return "", false
+	}
+}
+
+// EncodeSSDPResponse builds the unicast M-SEARCH response for a device.
+func EncodeSSDPResponse(d *Device, st, serverName string) []byte {
+	usn := d.UDN()
+	if st != d.UDN() {
+		usn += "::" + st
+	}
+
+	var b strings.Builder
+	b.WriteString("HTTP/1.1 200 OK\r\n")
+	fmt.Fprintf(&b, "CACHE-CONTROL: max-age=%d\r\n", ssdpMaxAge)
+	fmt.Fprintf(&b, "DATE: %s\r\n", time.Now().UTC().Format(time.RFC1123))
+	b.WriteString("EXT:\r\n")
+	fmt.Fprintf(&b, "LOCATION: %s\r\n", d.DeviceXMLURL())
+	fmt.Fprintf(&b, "SERVER: %s UPnP/1.0 HDHomeRun/1.0\r\n", serverName)
+	fmt.Fprintf(&b, "ST: %s\r\n", st)
+	fmt.Fprintf(&b, "USN: %s\r\n", usn)
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
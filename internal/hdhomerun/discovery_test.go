@@ -0,0 +1,174 @@
+package hdhomerun
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDevice() *Device {
+	return &Device{
+		DeviceID:     0x1234ABCD,
+		FriendlyName: "tvarr Test",
+		BaseURL:      "http://192.168.1.10:8080/hdhr/01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		TunerCount:   2,
+	}
+}
+
+func buildRequest(t *testing.T, tags map[byte]uint32) []byte {
+	t.Helper()
+	var payload bytes.Buffer
+	for tag, v := range tags {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, v)
+		writeTLV(&payload, tag, b)
+	}
+	return encodePacket(typeDiscoverReq, payload.Bytes())
+}
+
+func TestDeviceIDFromString(t *testing.T) {
+	a := DeviceIDFromString("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	b := DeviceIDFromString("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	c := DeviceIDFromString("01BX5ZZKBKACTAV9WEVGEMMVRZ")
+
+	assert.Equal(t, a, b, "device ID must be stable for the same input")
+	assert.NotEqual(t, a, c)
+	assert.NotEqual(t, wildcardDeviceID, a)
+}
+
+func TestNewDiscoverResponse(t *testing.T) {
+	d := testDevice()
+	resp := NewDiscoverResponse(d)
+
+	assert.Equal(t, "1234ABCD", resp.DeviceID)
+	assert.Equal(t, d.BaseURL, resp.BaseURL)
+	assert.Equal(t, d.BaseURL+"/lineup.json", resp.LineupURL)
+	assert.Equal(t, 2, resp.TunerCount)
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"TunerCount":2`)
+	assert.Contains(t, string(data), `"ModelNumber":"HDTC-2US"`)
+}
+
+func TestMarshalDeviceXML(t *testing.T) {
+	data, err := MarshalDeviceXML(testDevice())
+	require.NoError(t, err)
+
+	xml := string(data)
+	assert.True(t, strings.HasPrefix(xml, "<?xml"))
+	assert.Contains(t, xml, `xmlns="urn:schemas-upnp-org:device-1-0"`)
+	assert.Contains(t, xml, "<UDN>uuid:1234abcd</UDN>")
+	assert.Contains(t, xml, "<friendlyName>tvarr Test</friendlyName>")
+}
+
+func TestParseDiscoverRequest(t *testing.T) {
+	t.Run("wildcard request matches any tuner", func(t *testing.T) {
+		pkt := buildRequest(t, map[byte]uint32{
+			tagDeviceType: deviceTypeTuner,
+			tagDeviceID:   wildcardDeviceID,
+		})
+		req, err := ParseDiscoverRequest(pkt)
+		require.NoError(t, err)
+		assert.True(t, req.Matches(0x1234ABCD))
+	})
+
+	t.Run("specific device ID only matches that device", func(t *testing.T) {
+		pkt := buildRequest(t, map[byte]uint32{tagDeviceID: 0x1234ABCD})
+		req, err := ParseDiscoverRequest(pkt)
+		require.NoError(t, err)
+		assert.True(t, req.Matches(0x1234ABCD))
+		assert.False(t, req.Matches(0x11111111))
+	})
+
+	t.Run("non-tuner device type is ignored", func(t *testing.T) {
+		pkt := buildRequest(t, map[byte]uint32{tagDeviceType: 0x00000005})
+		req, err := ParseDiscoverRequest(pkt)
+		require.NoError(t, err)
+		assert.False(t, req.Matches(0x1234ABCD))
+	})
+
+	t.Run("corrupt CRC is rejected", func(t *testing.T) {
+		pkt := buildRequest(t, map[byte]uint32{tagDeviceType: deviceTypeTuner})
+		pkt[len(pkt)-1] ^= 0xFF
+		_, err := ParseDiscoverRequest(pkt)
+		assert.Error(t, err)
+	})
+
+	t.Run("reply packets are rejected", func(t *testing.T) {
+		_, err := ParseDiscoverRequest(EncodeDiscoverReply(testDevice()))
+		assert.Error(t, err)
+	})
+}
+
+func TestEncodeDiscoverReply(t *testing.T) {
+	d := testDevice()
+	pktType, payload, err := decodePacket(EncodeDiscoverReply(d))
+	require.NoError(t, err)
+	assert.Equal(t, typeDiscoverRpy, pktType)
+
+	tags := make(map[byte][]byte)
+	for len(payload) > 0 {
+		length, n := decodeVarLen(payload[1:])
+		require.NotZero(t, n)
+		tags[payload[0]] = payload[1+n : 1+n+length]
+		payload = payload[1+n+length:]
+	}
+
+	assert.Equal(t, deviceTypeTuner, binary.BigEndian.Uint32(tags[tagDeviceType]))
+	assert.Equal(t, d.DeviceID, binary.BigEndian.Uint32(tags[tagDeviceID]))
+	assert.Equal(t, []byte{2}, tags[tagTunerCount])
+	assert.Equal(t, d.BaseURL, string(tags[tagBaseURL]))
+	assert.Equal(t, d.LineupURL(), string(tags[tagLineupURL]))
+}
+
+func TestWriteTLV_LongValue(t *testing.T) {
+	var buf bytes.Buffer
+	value := bytes.Repeat([]byte("a"), 200)
+	writeTLV(&buf, tagBaseURL, value)
+
+	out := buf.Bytes()
+	length, n := decodeVarLen(out[1:])
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 200, length)
+	assert.Equal(t, value, out[1+n:])
+}
+
+func TestParseMSearch(t *testing.T) {
+	req := "M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 2\r\nST: ssdp:all\r\n\r\n"
+	st, ok := parseMSearch([]byte(req))
+	assert.True(t, ok)
+	assert.Equal(t, "ssdp:all", st)
+
+	_, ok = parseMSearch([]byte("NOTIFY * HTTP/1.1\r\nNT: upnp:rootdevice\r\n\r\n"))
+	assert.False(t, ok)
+}
+
+func TestSSDPSearchTarget(t *testing.T) {
+	d := testDevice()
+
+	st, ok := ssdpSearchTarget("ssdp:all", d)
+	assert.True(t, ok)
+	assert.Equal(t, ssdpDeviceType, st)
+
+	_, ok = ssdpSearchTarget(d.UDN(), d)
+	assert.True(t, ok)
+
+	_, ok = ssdpSearchTarget("urn:schemas-upnp-org:device:Printer:1", d)
+	assert.False(t, ok)
+}
+
+func TestEncodeSSDPResponse(t *testing.T) {
+	d := testDevice()
+	resp := string(EncodeSSDPResponse(d, "upnp:rootdevice", "tvarr/1.0"))
+
+	assert.True(t, strings.HasPrefix(resp, "HTTP/1.1 200 OK\r\n"))
+	assert.Contains(t, resp, "LOCATION: "+d.BaseURL+"/device.xml\r\n")
+	assert.Contains(t, resp, "USN: uuid:1234abcd::upnp:rootdevice\r\n")
+	assert.True(t, strings.HasSuffix(resp, "\r\n\r\n"))
+}
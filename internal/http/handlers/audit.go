@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// AuditRecorder records configuration changes made through the API.
+type AuditRecorder interface {
+	// Capture returns the entity's current state, to pass to Record once it has changed.
+	Capture(ctx context.Context, entityType models.AuditEntityType, id models.ULID) map[string]any
+	// Record records a change to an entity, given its state before the change.
+	Record(ctx context.Context, entityType models.AuditEntityType, action models.AuditAction, id models.ULID, before map[string]any)
+}
+
+// auditTrail records changes through an optional AuditRecorder, doing
+// nothing when none is set.
+type auditTrail struct {
+	recorder AuditRecorder
+}
+
+// capture returns the entity's current state, or nil when auditing is off.
+func (a auditTrail) capture(ctx context.Context, entityType models.AuditEntityType, id models.ULID) map[string]any {
+	if a.recorder == nil {
+		return nil
+	}
+	return a.recorder.Capture(ctx, entityType, id)
+}
+
+// record records a change to an entity.
+func (a auditTrail) record(ctx context.Context, entityType models.AuditEntityType, action models.AuditAction, id models.ULID, before map[string]any) {
+	if a.recorder == nil {
+		return
+	}
+	a.recorder.Record(ctx, entityType, action, id, before)
+}
+
+// captureReorder captures the states of reordered entities, for recordReorder.
+func (a auditTrail) captureReorder(ctx context.Context, entityType models.AuditEntityType, reorders []repository.ReorderRequest) []map[string]any {
+	if a.recorder == nil {
+		return nil
+	}
+	befores := make([]map[string]any, len(reorders))
+	for i, r := range reorders {
+		befores[i] = a.recorder.Capture(ctx, entityType, r.ID)
+	}
+	return befores
+}
+
+// recordReorder records an update for each reordered entity whose priority changed.
+func (a auditTrail) recordReorder(ctx context.Context, entityType models.AuditEntityType, reorders []repository.ReorderRequest, befores []map[string]any) {
+	if a.recorder == nil {
+		return
+	}
+	for i, r := range reorders {
+		a.recorder.Record(ctx, entityType, models.AuditActionUpdate, r.ID, befores[i])
+	}
+}
+
+// AuditHandler handles audit trail API endpoints.
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler.
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// Register registers the audit routes with the API.
+func (h *AuditHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listAuditEvents",
+		Method:      "GET",
+		Path:        "/api/v1/audit/events",
+		Summary:     "List audit events",
+		Description: "Returns configuration changes matching the filters, newest first",
+		Tags:        []string{"Audit"},
+	}, h.List)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getAuditEvent",
+		Method:      "GET",
+		Path:        "/api/v1/audit/events/{id}",
+		Summary:     "Get audit event",
+		Description: "Returns an audit event with the entity's state before and after the change",
+		Tags:        []string{"Audit"},
+	}, h.GetByID)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restoreAuditEvent",
+		Method:      "POST",
+		Path:        "/api/v1/audit/events/{id}/restore",
+		Summary:     "Restore entity revision",
+		Description: "Reverts the entity to the state recorded by the event, recreating it if it was deleted. Secrets are not recorded, so the entity keeps its current secrets.",
+		Tags:        []string{"Audit"},
+	}, h.Restore)
+}
+
+// AuditChangeResponse is a changed field in an audit event.
+type AuditChangeResponse struct {
+	From any `json:"from" doc:"Value before the change"`
+	To   any `json:"to" doc:"Value after the change"`
+}
+
+// AuditEventResponse represents an audit event in API responses.
+type AuditEventResponse struct {
+	ID           models.ULID                    `json:"id"`
+	Timestamp    time.Time                      `json:"timestamp"`
+	ActorType    string                         `json:"actor_type" doc:"How the actor authenticated: session, api_key or anonymous"`
+	ActorID      string                         `json:"actor_id,omitempty"`
+	ActorName    string                         `json:"actor_name"`
+	EntityType   string                         `json:"entity_type"`
+	EntityID     models.ULID                    `json:"entity_id"`
+	EntityName   string                         `json:"entity_name,omitempty"`
+	Action       string                         `json:"action" doc:"create, update, delete or restore"`
+	Before       map[string]any                 `json:"before,omitempty" doc:"Entity state before the change, with secrets redacted"`
+	After        map[string]any                 `json:"after,omitempty" doc:"Entity state after the change, with secrets redacted"`
+	Changes      map[string]AuditChangeResponse `json:"changes,omitempty" doc:"Fields changed by an update or restore"`
+	RestoredFrom *models.ULID                   `json:"restored_from,omitempty" doc:"Event whose state a restore reverted to"`
+}
+
+// AuditEventFromModel converts a model to a response, including the
+// recorded states when withStates is set.
+func AuditEventFromModel(e *models.AuditEvent, withStates bool) AuditEventResponse {
+	resp := AuditEventResponse{
+		ID:           e.ID,
+		Timestamp:    e.CreatedAt,
+		ActorType:    e.ActorType,
+		ActorID:      e.ActorID,
+		ActorName:    e.ActorName,
+		EntityType:   string(e.EntityType),
+		EntityID:     e.EntityID,
+		EntityName:   e.EntityName,
+		Action:       string(e.Action),
+		RestoredFrom: e.RestoredFrom,
+	}
+	if e.Changes != "" {
+		_ = json.Unmarshal([]byte(e.Changes), &resp.Changes)
+	}
+	if withStates {
+		if e.Before != "" {
+			_ = json.Unmarshal([]byte(e.Before), &resp.Before)
+		}
+		if e.After != "" {
+			_ = json.Unmarshal([]byte(e.After), &resp.After)
+		}
+	}
+	return resp
+}
+
+// ListAuditEventsInput is the input for listing audit events.
+type ListAuditEventsInput struct {
+	EntityType string `query:"entity_type" required:"false" enum:"stream_source,epg_source,stream_proxy,filter,data_mapping_rule,client_detection_rule,encoding_profile,encoder_override," doc:"Filter by entity type"`
+	EntityID   string `query:"entity_id" doc:"Filter by entity ID (ULID)"`
+	Action     string `query:"action" required:"false" enum:"create,update,delete,restore," doc:"Filter by action"`
+	Actor      string `query:"actor" doc:"Filter by actor name or ID"`
+	Since      string `query:"since" doc:"Only events at or after this time (RFC3339)"`
+	Until      string `query:"until" doc:"Only events before this time (RFC3339)"`
+	States     bool   `query:"states" default:"false" doc:"Include the before and after states of each event"`
+	Offset     int    `query:"offset" default:"0" minimum:"0" doc:"Offset for pagination"`
+	Limit      int    `query:"limit" default:"50" minimum:"1" maximum:"500" doc:"Limit for pagination"`
+}
+
+// ListAuditEventsOutput is the output for listing audit events.
+type ListAuditEventsOutput struct {
+	Body struct {
+		Events     []AuditEventResponse `json:"events"`
+		Pagination PaginationMeta       `json:"pagination"`
+	}
+}
+
+// List returns audit events matching the filters.
+func (h *AuditHandler) List(ctx context.Context, input *ListAuditEventsInput) (*ListAuditEventsOutput, error) {
+	filter := repository.AuditEventFilter{
+		EntityType: models.AuditEntityType(input.EntityType),
+		Action:     models.AuditAction(input.Action),
+		Actor:      input.Actor,
+	}
+	if input.EntityID != "" {
+		id, err := models.ParseULID(input.EntityID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("invalid entity_id format", err)
+		}
+		filter.EntityID = &id
+	}
+	var err error
+	if input.Since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, input.Since); err != nil {
+			return nil, huma.Error400BadRequest("invalid since time, expected RFC3339", err)
+		}
+	}
+	if input.Until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, input.Until); err != nil {
+			return nil, huma.Error400BadRequest("invalid until time, expected RFC3339", err)
+		}
+	}
+
+	events, total, err := h.auditService.List(ctx, filter, input.Offset, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list audit events", err)
+	}
+
+	resp := &ListAuditEventsOutput{}
+	resp.Body.Events = make([]AuditEventResponse, len(events))
+	for i, e := range events {
+		resp.Body.Events[i] = AuditEventFromModel(e, input.States)
+	}
+
+	totalPages := total / int64(input.Limit)
+	if total%int64(input.Limit) > 0 {
+		totalPages++
+	}
+	resp.Body.Pagination = PaginationMeta{
+		CurrentPage: (input.Offset / input.Limit) + 1,
+		PageSize:    input.Limit,
+		TotalItems:  total,
+		TotalPages:  totalPages,
+	}
+	return resp, nil
+}
+
+// GetAuditEventInput is the input for getting an audit event.
+type GetAuditEventInput struct {
+	ID string `path:"id" doc:"Audit event ID (ULID)"`
+}
+
+// GetAuditEventOutput is the output for getting an audit event.
+type GetAuditEventOutput struct {
+	Body AuditEventResponse
+}
+
+// GetByID returns an audit event with its recorded states.
+func (h *AuditHandler) GetByID(ctx context.Context, input *GetAuditEventInput) (*GetAuditEventOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid ID format", err)
+	}
+
+	event, err := h.auditService.GetByID(ctx, id)
+	if err != nil {
+		return nil, auditServiceError("failed to get audit event", err)
+	}
+	return &GetAuditEventOutput{Body: AuditEventFromModel(event, true)}, nil
+}
+
+// RestoreAuditEventInput is the input for restoring an entity revision.
+type RestoreAuditEventInput struct {
+	ID string `path:"id" doc:"ID (ULID) of the audit event whose state to restore"`
+}
+
+// RestoreAuditEventOutput is the output for restoring an entity revision.
+type RestoreAuditEventOutput struct {
+	Body AuditEventResponse
+}
+
+// Restore reverts an entity to the state recorded by an audit event and
+// returns the audit event recording the restore.
+func (h *AuditHandler) Restore(ctx context.Context, input *RestoreAuditEventInput) (*RestoreAuditEventOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid ID format", err)
+	}
+
+	event, err := h.auditService.Restore(ctx, id)
+	if err != nil {
+		return nil, auditServiceError("failed to restore entity", err)
+	}
+	return &RestoreAuditEventOutput{Body: AuditEventFromModel(event, true)}, nil
+}
+
+// auditServiceError maps audit service errors to HTTP errors.
+func auditServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	switch {
+	case errors.Is(err, service.ErrAuditEventNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, service.ErrAuditRestoreUnsupported),
+		errors.Is(err, service.ErrAuditRestoreNoState):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, service.ErrClientDetectionRuleCannotEditSystem),
+		errors.Is(err, service.ErrEncoderOverrideCannotEditSystem),
+		errors.Is(err, service.ErrEncodingProfileCannotEditSystem):
+		return huma.Error403Forbidden(err.Error())
+	case errors.As(err, &ve):
+		return huma.Error400BadRequest(ve.Error())
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
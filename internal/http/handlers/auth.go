@@ -0,0 +1,485 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/auth"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// AuthHandler handles login, session, user and API key endpoints.
+type AuthHandler struct {
+	authService *service.AuthService
+	enabled     bool
+}
+
+// NewAuthHandler creates a new auth handler.
+// enabled reports whether authentication is enforced on the API.
+func NewAuthHandler(authService *service.AuthService, enabled bool) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		enabled:     enabled,
+	}
+}
+
+// Register registers the auth routes with the API.
+func (h *AuthHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "login",
+		Method:      "POST",
+		Path:        "/api/v1/auth/login",
+		Summary:     "Log in",
+		Description: "Verifies a username and password and starts a UI session. The session token is returned in an HttpOnly cookie.",
+		Tags:        []string{"Auth"},
+		Metadata:    map[string]any{auth.OperationPublicMetadata: true},
+	}, h.Login)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "logout",
+		Method:      "POST",
+		Path:        "/api/v1/auth/logout",
+		Summary:     "Log out",
+		Description: "Ends the current UI session and clears the session cookie",
+		Tags:        []string{"Auth"},
+		Metadata:    map[string]any{auth.OperationPublicMetadata: true},
+	}, h.Logout)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getCurrentPrincipal",
+		Method:      "GET",
+		Path:        "/api/v1/auth/me",
+		Summary:     "Get current identity",
+		Description: "Returns whether authentication is enabled and the identity of the caller",
+		Tags:        []string{"Auth"},
+	}, h.Me)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "listUsers",
+		Method:      "GET",
+		Path:        "/api/v1/auth/users",
+		Summary:     "List users",
+		Description: "Returns all users",
+		Tags:        []string{"Auth"},
+	}, h.ListUsers)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "createUser",
+		Method:        "POST",
+		Path:          "/api/v1/auth/users",
+		Summary:       "Create user",
+		Description:   "Creates a new user",
+		Tags:          []string{"Auth"},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateUser)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "setUserPassword",
+		Method:      "PUT",
+		Path:        "/api/v1/auth/users/{id}/password",
+		Summary:     "Set user password",
+		Description: "Replaces a user's password and ends all of their sessions",
+		Tags:        []string{"Auth"},
+	}, h.SetPassword)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "deleteUser",
+		Method:        "DELETE",
+		Path:          "/api/v1/auth/users/{id}",
+		Summary:       "Delete user",
+		Description:   "Deletes a user and their sessions. The last user cannot be deleted.",
+		Tags:          []string{"Auth"},
+		DefaultStatus: http.StatusNoContent,
+	}, h.DeleteUser)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "listAPIKeys",
+		Method:      "GET",
+		Path:        "/api/v1/auth/api-keys",
+		Summary:     "List API keys",
+		Description: "Returns all API keys. Keys are identified by their prefix; the full key is never returned.",
+		Tags:        []string{"Auth"},
+	}, h.ListAPIKeys)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "createAPIKey",
+		Method:        "POST",
+		Path:          "/api/v1/auth/api-keys",
+		Summary:       "Create API key",
+		Description:   "Creates a scoped API key owned by the caller. The key is only returned in this response.",
+		Tags:          []string{"Auth"},
+		DefaultStatus: http.StatusCreated,
+	}, h.CreateAPIKey)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "revokeAPIKey",
+		Method:        "DELETE",
+		Path:          "/api/v1/auth/api-keys/{id}",
+		Summary:       "Revoke API key",
+		Description:   "Deletes an API key so it can no longer be used",
+		Tags:          []string{"Auth"},
+		DefaultStatus: http.StatusNoContent,
+	}, h.RevokeAPIKey)
+}
+
+// UserResponse represents a user in API responses.
+type UserResponse struct {
+	ID          models.ULID `json:"id"`
+	Username    string      `json:"username"`
+	IsActive    bool        `json:"is_active"`
+	LastLoginAt *time.Time  `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// UserFromModel converts a model to a response.
+func UserFromModel(u *models.User) UserResponse {
+	return UserResponse{
+		ID:          u.ID,
+		Username:    u.Username,
+		IsActive:    models.BoolVal(u.IsActive),
+		LastLoginAt: u.LastLoginAt,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+// APIKeyResponse represents an API key in API responses.
+type APIKeyResponse struct {
+	ID         models.ULID `json:"id"`
+	Name       string      `json:"name"`
+	Prefix     string      `json:"prefix" doc:"Non-secret leading part of the key"`
+	UserID     models.ULID `json:"user_id"`
+	Scopes     []string    `json:"scopes"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// APIKeyFromModel converts a model to a response.
+func APIKeyFromModel(k *models.APIKey) APIKeyResponse {
+	scopes := make([]string, 0)
+	for s := range strings.SplitSeq(k.Scopes, ",") {
+		if s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		UserID:     k.UserID,
+		Scopes:     scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// LoginInput is the input for logging in.
+type LoginInput struct {
+	UserAgent      string `header:"User-Agent"`
+	ForwardedFor   string `header:"X-Forwarded-For"`
+	ForwardedProto string `header:"X-Forwarded-Proto"`
+	Body           struct {
+		Username string `json:"username" minLength:"1" doc:"Username"`
+		Password string `json:"password" minLength:"1" doc:"Password"`
+	}
+}
+
+// LoginOutput is the output for logging in.
+type LoginOutput struct {
+	SetCookie http.Cookie `header:"Set-Cookie"`
+	Body      struct {
+		User      UserResponse `json:"user"`
+		ExpiresAt time.Time    `json:"expires_at"`
+	}
+}
+
+// Login verifies credentials and starts a session.
+func (h *AuthHandler) Login(ctx context.Context, input *LoginInput) (*LoginOutput, error) {
+	token, session, err := h.authService.Login(ctx, input.Body.Username, input.Body.Password, input.UserAgent, input.ForwardedFor)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			return nil, huma.Error401Unauthorized(err.Error())
+		}
+		return nil, huma.Error500InternalServerError("failed to log in", err)
+	}
+
+	resp := &LoginOutput{}
+	resp.SetCookie = h.sessionCookie(token, int(h.authService.SessionTTL().Seconds()), input.ForwardedProto)
+	resp.Body.User = UserFromModel(session.User)
+	resp.Body.ExpiresAt = session.ExpiresAt
+	return resp, nil
+}
+
+// LogoutInput is the input for logging out.
+type LogoutInput struct {
+	Session        string `cookie:"tvarr_session"`
+	ForwardedProto string `header:"X-Forwarded-Proto"`
+}
+
+// LogoutOutput is the output for logging out.
+type LogoutOutput struct {
+	SetCookie http.Cookie `header:"Set-Cookie"`
+}
+
+// Logout ends the caller's session.
+func (h *AuthHandler) Logout(ctx context.Context, input *LogoutInput) (*LogoutOutput, error) {
+	if err := h.authService.Logout(ctx, input.Session); err != nil {
+		return nil, huma.Error500InternalServerError("failed to log out", err)
+	}
+	return &LogoutOutput{SetCookie: h.sessionCookie("", -1, input.ForwardedProto)}, nil
+}
+
+// sessionCookie builds the session cookie. A negative maxAge deletes it.
+func (h *AuthHandler) sessionCookie(value string, maxAge int, forwardedProto string) http.Cookie {
+	return http.Cookie{
+		Name:     auth.SessionCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.EqualFold(forwardedProto, "https"),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// MeInput is the input for getting the current identity.
+type MeInput struct{}
+
+// MeOutput is the output for getting the current identity.
+type MeOutput struct {
+	Body struct {
+		AuthEnabled   bool     `json:"auth_enabled" doc:"Whether authentication is enforced"`
+		Authenticated bool     `json:"authenticated"`
+		Kind          string   `json:"kind,omitempty" doc:"How the caller authenticated (session or api_key)"`
+		Username      string   `json:"username,omitempty"`
+		APIKeyName    string   `json:"api_key_name,omitempty"`
+		Scopes        []string `json:"scopes,omitempty"`
+	}
+}
+
+// Me returns the caller's identity.
+func (h *AuthHandler) Me(ctx context.Context, _ *MeInput) (*MeOutput, error) {
+	resp := &MeOutput{}
+	resp.Body.AuthEnabled = h.enabled
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return resp, nil
+	}
+	resp.Body.Authenticated = true
+	resp.Body.Kind = string(principal.Kind)
+	resp.Body.Username = principal.Username
+	resp.Body.APIKeyName = principal.APIKeyName
+	for _, s := range principal.Scopes {
+		resp.Body.Scopes = append(resp.Body.Scopes, string(s))
+	}
+	return resp, nil
+}
+
+// ListUsersInput is the input for listing users.
+type ListUsersInput struct{}
+
+// ListUsersOutput is the output for listing users.
+type ListUsersOutput struct {
+	Body struct {
+		Users []UserResponse `json:"users"`
+	}
+}
+
+// ListUsers returns all users.
+func (h *AuthHandler) ListUsers(ctx context.Context, _ *ListUsersInput) (*ListUsersOutput, error) {
+	users, err := h.authService.ListUsers(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list users", err)
+	}
+
+	resp := &ListUsersOutput{}
+	resp.Body.Users = make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		resp.Body.Users = append(resp.Body.Users, UserFromModel(u))
+	}
+	return resp, nil
+}
+
+// CreateUserInput is the input for creating a user.
+type CreateUserInput struct {
+	Body struct {
+		Username string `json:"username" minLength:"1" maxLength:"100" doc:"Login name"`
+		Password string `json:"password" doc:"Password (at least 8 characters)"`
+	}
+}
+
+// CreateUserOutput is the output for creating a user.
+type CreateUserOutput struct {
+	Body UserResponse
+}
+
+// CreateUser creates a new user.
+func (h *AuthHandler) CreateUser(ctx context.Context, input *CreateUserInput) (*CreateUserOutput, error) {
+	user, err := h.authService.CreateUser(ctx, input.Body.Username, input.Body.Password)
+	if err != nil {
+		return nil, authServiceError("failed to create user", err)
+	}
+	return &CreateUserOutput{Body: UserFromModel(user)}, nil
+}
+
+// SetPasswordInput is the input for setting a user's password.
+type SetPasswordInput struct {
+	ID   string `path:"id" doc:"User ID (ULID)"`
+	Body struct {
+		Password string `json:"password" doc:"New password (at least 8 characters)"`
+	}
+}
+
+// SetPasswordOutput is the output for setting a user's password.
+type SetPasswordOutput struct{}
+
+// SetPassword replaces a user's password.
+func (h *AuthHandler) SetPassword(ctx context.Context, input *SetPasswordInput) (*SetPasswordOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid user ID format", err)
+	}
+	if err := h.authService.SetPassword(ctx, id, input.Body.Password); err != nil {
+		return nil, authServiceError("failed to set password", err)
+	}
+	return &SetPasswordOutput{}, nil
+}
+
+// DeleteUserInput is the input for deleting a user.
+type DeleteUserInput struct {
+	ID string `path:"id" doc:"User ID (ULID)"`
+}
+
+// DeleteUserOutput is the output for deleting a user.
+type DeleteUserOutput struct{}
+
+// DeleteUser deletes a user.
+func (h *AuthHandler) DeleteUser(ctx context.Context, input *DeleteUserInput) (*DeleteUserOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid user ID format", err)
+	}
+	if err := h.authService.DeleteUser(ctx, id); err != nil {
+		return nil, authServiceError("failed to delete user", err)
+	}
+	return &DeleteUserOutput{}, nil
+}
+
+// ListAPIKeysInput is the input for listing API keys.
+type ListAPIKeysInput struct{}
+
+// ListAPIKeysOutput is the output for listing API keys.
+type ListAPIKeysOutput struct {
+	Body struct {
+		APIKeys []APIKeyResponse `json:"api_keys"`
+	}
+}
+
+// ListAPIKeys returns all API keys.
+func (h *AuthHandler) ListAPIKeys(ctx context.Context, _ *ListAPIKeysInput) (*ListAPIKeysOutput, error) {
+	keys, err := h.authService.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list API keys", err)
+	}
+
+	resp := &ListAPIKeysOutput{}
+	resp.Body.APIKeys = make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp.Body.APIKeys = append(resp.Body.APIKeys, APIKeyFromModel(k))
+	}
+	return resp, nil
+}
+
+// CreateAPIKeyInput is the input for creating an API key.
+type CreateAPIKeyInput struct {
+	Body struct {
+		Name      string     `json:"name" minLength:"1" maxLength:"255" doc:"Label for the key"`
+		Scopes    []string   `json:"scopes" minItems:"1" doc:"Granted scopes: read, write, admin"`
+		ExpiresAt *time.Time `json:"expires_at,omitempty" doc:"When the key stops being valid (omit for no expiry)"`
+	}
+}
+
+// CreateAPIKeyOutput is the output for creating an API key.
+type CreateAPIKeyOutput struct {
+	Body struct {
+		APIKeyResponse
+		Key string `json:"key" doc:"The API key. It is only returned once and cannot be recovered."`
+	}
+}
+
+// CreateAPIKey creates an API key owned by the caller.
+func (h *AuthHandler) CreateAPIKey(ctx context.Context, input *CreateAPIKeyInput) (*CreateAPIKeyOutput, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, huma.Error400BadRequest("API keys can only be created when authentication is enabled")
+	}
+	userID, err := models.ParseULID(principal.UserID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("invalid principal", err)
+	}
+
+	scopes, err := auth.ParseScopes(strings.Join(input.Body.Scopes, ","))
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+	// A principal cannot mint a key more powerful than itself.
+	for _, s := range scopes {
+		if !principal.Scopes.Allows(s) {
+			return nil, huma.Error403Forbidden("cannot grant scope " + string(s))
+		}
+	}
+
+	key, apiKey, err := h.authService.CreateAPIKey(ctx, userID, input.Body.Name, scopes, input.Body.ExpiresAt)
+	if err != nil {
+		return nil, authServiceError("failed to create API key", err)
+	}
+
+	resp := &CreateAPIKeyOutput{}
+	resp.Body.APIKeyResponse = APIKeyFromModel(apiKey)
+	resp.Body.Key = key
+	return resp, nil
+}
+
+// RevokeAPIKeyInput is the input for revoking an API key.
+type RevokeAPIKeyInput struct {
+	ID string `path:"id" doc:"API key ID (ULID)"`
+}
+
+// RevokeAPIKeyOutput is the output for revoking an API key.
+type RevokeAPIKeyOutput struct{}
+
+// RevokeAPIKey deletes an API key.
+func (h *AuthHandler) RevokeAPIKey(ctx context.Context, input *RevokeAPIKeyInput) (*RevokeAPIKeyOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid API key ID format", err)
+	}
+	if err := h.authService.RevokeAPIKey(ctx, id); err != nil {
+		return nil, authServiceError("failed to revoke API key", err)
+	}
+	return &RevokeAPIKeyOutput{}, nil
+}
+
+// authServiceError maps auth service errors to HTTP errors.
+func authServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrAPIKeyNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, service.ErrUserExists), errors.Is(err, service.ErrCannotDeleteLastUser):
+		return huma.Error409Conflict(err.Error())
+	case errors.Is(err, auth.ErrPasswordTooShort):
+		return huma.Error400BadRequest(err.Error())
+	case errors.As(err, &ve):
+		return huma.Error400BadRequest(ve.Error())
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
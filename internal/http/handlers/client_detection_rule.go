@@ -15,7 +15,8 @@ import (
 
 // ClientDetectionRuleHandler handles client detection rule API endpoints.
 type ClientDetectionRuleHandler struct {
-	svc *service.ClientDetectionService
+	svc   *service.ClientDetectionService
+	audit auditTrail
 }
 
 // NewClientDetectionRuleHandler creates a new client detection rule handler.
@@ -23,6 +24,12 @@ func NewClientDetectionRuleHandler(svc *service.ClientDetectionService) *ClientD
 	return &ClientDetectionRuleHandler{svc: svc}
 }
 
+// WithAuditRecorder sets the recorder for client detection rule changes.
+func (h *ClientDetectionRuleHandler) WithAuditRecorder(recorder AuditRecorder) *ClientDetectionRuleHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // Register registers the client detection rule routes with the API.
 func (h *ClientDetectionRuleHandler) Register(api huma.API) {
 	huma.Register(api, huma.Operation{
@@ -296,6 +303,7 @@ func (h *ClientDetectionRuleHandler) Create(ctx context.Context, input *CreateCl
 		}
 		return nil, huma.Error500InternalServerError("failed to create client detection rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityClientDetectionRule, models.AuditActionCreate, rule.ID, nil)
 
 	return &CreateClientDetectionRuleOutput{
 		Body: ClientDetectionRuleFromModel(rule),
@@ -344,6 +352,7 @@ func (h *ClientDetectionRuleHandler) Update(ctx context.Context, input *UpdateCl
 		}
 		return nil, huma.Error500InternalServerError("failed to get client detection rule", err)
 	}
+	before := h.audit.capture(ctx, models.AuditEntityClientDetectionRule, id)
 
 	// System rules can only have is_enabled toggled
 	if rule.IsSystem {
@@ -423,6 +432,7 @@ func (h *ClientDetectionRuleHandler) Update(ctx context.Context, input *UpdateCl
 		}
 		return nil, huma.Error500InternalServerError("failed to update client detection rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityClientDetectionRule, models.AuditActionUpdate, id, before)
 
 	return &UpdateClientDetectionRuleOutput{
 		Body: ClientDetectionRuleFromModel(rule),
@@ -448,6 +458,7 @@ func (h *ClientDetectionRuleHandler) Delete(ctx context.Context, input *DeleteCl
 		return nil, huma.Error400BadRequest("invalid ID format", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityClientDetectionRule, id)
 	if err := h.svc.Delete(ctx, id); err != nil {
 		if errors.Is(err, service.ErrClientDetectionRuleNotFound) {
 			return nil, huma.Error404NotFound(fmt.Sprintf("client detection rule %s not found", input.ID))
@@ -457,6 +468,7 @@ func (h *ClientDetectionRuleHandler) Delete(ctx context.Context, input *DeleteCl
 		}
 		return nil, huma.Error500InternalServerError("failed to delete client detection rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityClientDetectionRule, models.AuditActionDelete, id, before)
 
 	return &DeleteClientDetectionRuleOutput{
 		Body: struct {
@@ -484,6 +496,7 @@ func (h *ClientDetectionRuleHandler) Toggle(ctx context.Context, input *ToggleCl
 		return nil, huma.Error400BadRequest("invalid ID format", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityClientDetectionRule, id)
 	rule, err := h.svc.ToggleEnabled(ctx, id)
 	if err != nil {
 		if errors.Is(err, service.ErrClientDetectionRuleNotFound) {
@@ -491,6 +504,7 @@ func (h *ClientDetectionRuleHandler) Toggle(ctx context.Context, input *ToggleCl
 		}
 		return nil, huma.Error500InternalServerError("failed to toggle client detection rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityClientDetectionRule, models.AuditActionUpdate, id, before)
 
 	return &ToggleClientDetectionRuleOutput{
 		Body: ClientDetectionRuleFromModel(rule),
@@ -535,9 +549,11 @@ func (h *ClientDetectionRuleHandler) Reorder(ctx context.Context, input *Reorder
 		})
 	}
 
+	befores := h.audit.captureReorder(ctx, models.AuditEntityClientDetectionRule, reorders)
 	if err := h.svc.Reorder(ctx, reorders); err != nil {
 		return nil, huma.Error500InternalServerError("failed to reorder client detection rules", err)
 	}
+	h.audit.recordReorder(ctx, models.AuditEntityClientDetectionRule, reorders, befores)
 
 	return &ReorderClientDetectionRulesOutput{
 		Body: struct {
@@ -11,7 +11,8 @@ import (
 
 // DataMappingRuleHandler handles data mapping rule API endpoints.
 type DataMappingRuleHandler struct {
-	repo repository.DataMappingRuleRepository
+	repo  repository.DataMappingRuleRepository
+	audit auditTrail
 }
 
 // NewDataMappingRuleHandler creates a new data mapping rule handler.
@@ -19,6 +20,12 @@ func NewDataMappingRuleHandler(repo repository.DataMappingRuleRepository) *DataM
 	return &DataMappingRuleHandler{repo: repo}
 }
 
+// WithAuditRecorder sets the recorder for data mapping rule changes.
+func (h *DataMappingRuleHandler) WithAuditRecorder(recorder AuditRecorder) *DataMappingRuleHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // Register registers the data mapping rule routes with the API.
 func (h *DataMappingRuleHandler) Register(api huma.API) {
 	huma.Register(api, huma.Operation{
@@ -255,6 +262,7 @@ func (h *DataMappingRuleHandler) Create(ctx context.Context, input *CreateDataMa
 	if err := h.repo.Create(ctx, rule); err != nil {
 		return nil, huma.Error500InternalServerError("failed to create data mapping rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityDataMappingRule, models.AuditActionCreate, rule.ID, nil)
 
 	return &CreateDataMappingRuleOutput{
 		Body: DataMappingRuleFromModel(rule),
@@ -304,6 +312,7 @@ func (h *DataMappingRuleHandler) Update(ctx context.Context, input *UpdateDataMa
 	if rule.IsSystem {
 		return nil, huma.Error403Forbidden("system rules cannot be updated via PUT - use PATCH to toggle enabled status")
 	}
+	before := h.audit.capture(ctx, models.AuditEntityDataMappingRule, id)
 
 	// Apply updates for non-system rules
 	if input.Body.Name != nil {
@@ -342,6 +351,7 @@ func (h *DataMappingRuleHandler) Update(ctx context.Context, input *UpdateDataMa
 	if err := h.repo.Update(ctx, rule); err != nil {
 		return nil, huma.Error500InternalServerError("failed to update data mapping rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityDataMappingRule, models.AuditActionUpdate, id, before)
 
 	return &UpdateDataMappingRuleOutput{
 		Body: DataMappingRuleFromModel(rule),
@@ -381,6 +391,8 @@ func (h *DataMappingRuleHandler) Patch(ctx context.Context, input *PatchDataMapp
 		return nil, huma.Error404NotFound(fmt.Sprintf("data mapping rule %s not found", input.ID))
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityDataMappingRule, id)
+
 	// Update is_enabled
 	if input.Body.IsEnabled != nil {
 		rule.IsEnabled = input.Body.IsEnabled
@@ -394,6 +406,7 @@ func (h *DataMappingRuleHandler) Patch(ctx context.Context, input *PatchDataMapp
 	if err := h.repo.Update(ctx, rule); err != nil {
 		return nil, huma.Error500InternalServerError("failed to update data mapping rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityDataMappingRule, models.AuditActionUpdate, id, before)
 
 	return &PatchDataMappingRuleOutput{
 		Body: DataMappingRuleFromModel(rule),
@@ -432,9 +445,11 @@ func (h *DataMappingRuleHandler) Delete(ctx context.Context, input *DeleteDataMa
 		return nil, huma.Error403Forbidden("system rules cannot be deleted")
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityDataMappingRule, id)
 	if err := h.repo.Delete(ctx, id); err != nil {
 		return nil, huma.Error500InternalServerError("failed to delete data mapping rule", err)
 	}
+	h.audit.record(ctx, models.AuditEntityDataMappingRule, models.AuditActionDelete, id, before)
 
 	return &DeleteDataMappingRuleOutput{
 		Body: struct {
@@ -485,10 +500,12 @@ func (h *DataMappingRuleHandler) Reorder(ctx context.Context, input *ReorderData
 			return nil, huma.Error404NotFound(fmt.Sprintf("data mapping rule %s not found", item.ID))
 		}
 
+		before := h.audit.capture(ctx, models.AuditEntityDataMappingRule, id)
 		rule.Priority = item.Priority
 		if err := h.repo.Update(ctx, rule); err != nil {
 			return nil, huma.Error500InternalServerError("failed to update data mapping rule priority", err)
 		}
+		h.audit.record(ctx, models.AuditEntityDataMappingRule, models.AuditActionUpdate, id, before)
 	}
 
 	return &ReorderDataMappingRulesOutput{
@@ -13,7 +13,8 @@ import (
 
 // EncoderOverrideHandler handles encoder override API endpoints.
 type EncoderOverrideHandler struct {
-	svc *service.EncoderOverrideService
+	svc   *service.EncoderOverrideService
+	audit auditTrail
 }
 
 // NewEncoderOverrideHandler creates a new encoder override handler.
@@ -21,6 +22,12 @@ func NewEncoderOverrideHandler(svc *service.EncoderOverrideService) *EncoderOver
 	return &EncoderOverrideHandler{svc: svc}
 }
 
+// WithAuditRecorder sets the recorder for encoder override changes.
+func (h *EncoderOverrideHandler) WithAuditRecorder(recorder AuditRecorder) *EncoderOverrideHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // Register registers the encoder override routes with the API.
 func (h *EncoderOverrideHandler) Register(api huma.API) {
 	huma.Register(api, huma.Operation{
@@ -245,6 +252,7 @@ func (h *EncoderOverrideHandler) Create(ctx context.Context, input *CreateEncode
 		}
 		return nil, huma.Error500InternalServerError("failed to create encoder override", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncoderOverride, models.AuditActionCreate, override.ID, nil)
 
 	return &CreateEncoderOverrideOutput{
 		Body: EncoderOverrideFromModel(override),
@@ -289,6 +297,7 @@ func (h *EncoderOverrideHandler) Update(ctx context.Context, input *UpdateEncode
 		}
 		return nil, huma.Error500InternalServerError("failed to get encoder override", err)
 	}
+	before := h.audit.capture(ctx, models.AuditEntityEncoderOverride, id)
 
 	// System overrides can only have is_enabled toggled
 	if override.IsSystem {
@@ -343,6 +352,7 @@ func (h *EncoderOverrideHandler) Update(ctx context.Context, input *UpdateEncode
 		}
 		return nil, huma.Error500InternalServerError("failed to update encoder override", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncoderOverride, models.AuditActionUpdate, id, before)
 
 	return &UpdateEncoderOverrideOutput{
 		Body: EncoderOverrideFromModel(override),
@@ -368,6 +378,7 @@ func (h *EncoderOverrideHandler) Delete(ctx context.Context, input *DeleteEncode
 		return nil, huma.Error400BadRequest("invalid ID format", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityEncoderOverride, id)
 	if err := h.svc.Delete(ctx, id); err != nil {
 		if errors.Is(err, service.ErrEncoderOverrideNotFound) {
 			return nil, huma.Error404NotFound(fmt.Sprintf("encoder override %s not found", input.ID))
@@ -377,6 +388,7 @@ func (h *EncoderOverrideHandler) Delete(ctx context.Context, input *DeleteEncode
 		}
 		return nil, huma.Error500InternalServerError("failed to delete encoder override", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncoderOverride, models.AuditActionDelete, id, before)
 
 	return &DeleteEncoderOverrideOutput{
 		Body: struct {
@@ -404,6 +416,7 @@ func (h *EncoderOverrideHandler) Toggle(ctx context.Context, input *ToggleEncode
 		return nil, huma.Error400BadRequest("invalid ID format", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityEncoderOverride, id)
 	override, err := h.svc.ToggleEnabled(ctx, id)
 	if err != nil {
 		if errors.Is(err, service.ErrEncoderOverrideNotFound) {
@@ -411,6 +424,7 @@ func (h *EncoderOverrideHandler) Toggle(ctx context.Context, input *ToggleEncode
 		}
 		return nil, huma.Error500InternalServerError("failed to toggle encoder override", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncoderOverride, models.AuditActionUpdate, id, before)
 
 	return &ToggleEncoderOverrideOutput{
 		Body: EncoderOverrideFromModel(override),
@@ -449,9 +463,11 @@ func (h *EncoderOverrideHandler) Reorder(ctx context.Context, input *ReorderEnco
 		})
 	}
 
+	befores := h.audit.captureReorder(ctx, models.AuditEntityEncoderOverride, reorders)
 	if err := h.svc.Reorder(ctx, reorders); err != nil {
 		return nil, huma.Error500InternalServerError("failed to reorder encoder overrides", err)
 	}
+	h.audit.recordReorder(ctx, models.AuditEntityEncoderOverride, reorders, befores)
 
 	return &ReorderEncoderOverridesOutput{
 		Body: struct {
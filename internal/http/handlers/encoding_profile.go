@@ -15,6 +15,7 @@ import (
 type EncodingProfileHandler struct {
 	service           *service.EncodingProfileService
 	proxyUsageChecker ProxyUsageChecker
+	audit             auditTrail
 }
 
 // NewEncodingProfileHandler creates a new encoding profile handler.
@@ -28,6 +29,12 @@ func (h *EncodingProfileHandler) WithProxyUsageChecker(checker ProxyUsageChecker
 	return h
 }
 
+// WithAuditRecorder sets the recorder for encoding profile changes.
+func (h *EncodingProfileHandler) WithAuditRecorder(recorder AuditRecorder) *EncodingProfileHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // Register registers the encoding profile routes with the API.
 func (h *EncodingProfileHandler) Register(api huma.API) {
 	huma.Register(api, huma.Operation{
@@ -300,6 +307,7 @@ func (h *EncodingProfileHandler) Create(ctx context.Context, input *CreateEncodi
 	if err := h.service.Create(ctx, profile); err != nil {
 		return nil, huma.Error500InternalServerError("failed to create encoding profile", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncodingProfile, models.AuditActionCreate, profile.ID, nil)
 
 	return &CreateEncodingProfileOutput{Body: EncodingProfileFromModel(profile)}, nil
 }
@@ -344,6 +352,7 @@ func (h *EncodingProfileHandler) Update(ctx context.Context, input *UpdateEncodi
 		}
 		return nil, huma.Error500InternalServerError("failed to get encoding profile", err)
 	}
+	before := h.audit.capture(ctx, models.AuditEntityEncodingProfile, id)
 
 	// Apply updates
 	if input.Body.Name != "" {
@@ -384,6 +393,7 @@ func (h *EncodingProfileHandler) Update(ctx context.Context, input *UpdateEncodi
 		}
 		return nil, huma.Error500InternalServerError("failed to update encoding profile", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncodingProfile, models.AuditActionUpdate, id, before)
 
 	// Refetch to get updated timestamps
 	updated, err := h.service.GetByID(ctx, id)
@@ -426,6 +436,7 @@ func (h *EncodingProfileHandler) Delete(ctx context.Context, input *DeleteEncodi
 		}
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityEncodingProfile, id)
 	if err := h.service.Delete(ctx, id); err != nil {
 		if errors.Is(err, models.ErrEncodingProfileNotFound) {
 			return nil, huma.Error404NotFound("encoding profile not found")
@@ -435,6 +446,7 @@ func (h *EncodingProfileHandler) Delete(ctx context.Context, input *DeleteEncodi
 		}
 		return nil, huma.Error500InternalServerError("failed to delete encoding profile", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncodingProfile, models.AuditActionDelete, id, before)
 
 	resp := &DeleteEncodingProfileOutput{}
 	resp.Body.Success = true
@@ -458,12 +470,24 @@ func (h *EncodingProfileHandler) SetDefault(ctx context.Context, input *SetDefau
 		return nil, huma.Error400BadRequest("invalid profile ID", err)
 	}
 
+	// Setting a default also clears the previous one, so both changes are recorded.
+	var previousID models.ULID
+	if previous, err := h.service.GetDefault(ctx); err == nil && previous != nil && previous.ID != id {
+		previousID = previous.ID
+	}
+	previousBefore := h.audit.capture(ctx, models.AuditEntityEncodingProfile, previousID)
+	before := h.audit.capture(ctx, models.AuditEntityEncodingProfile, id)
+
 	if err := h.service.SetDefault(ctx, id); err != nil {
 		if errors.Is(err, models.ErrEncodingProfileNotFound) {
 			return nil, huma.Error404NotFound("encoding profile not found")
 		}
 		return nil, huma.Error500InternalServerError("failed to set default encoding profile", err)
 	}
+	if !previousID.IsZero() {
+		h.audit.record(ctx, models.AuditEntityEncodingProfile, models.AuditActionUpdate, previousID, previousBefore)
+	}
+	h.audit.record(ctx, models.AuditEntityEncodingProfile, models.AuditActionUpdate, id, before)
 
 	profile, err := h.service.GetByID(ctx, id)
 	if err != nil {
@@ -490,6 +514,7 @@ func (h *EncodingProfileHandler) ToggleEnabled(ctx context.Context, input *Toggl
 		return nil, huma.Error400BadRequest("invalid profile ID", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityEncodingProfile, id)
 	profile, err := h.service.ToggleEnabled(ctx, id)
 	if err != nil {
 		if errors.Is(err, models.ErrEncodingProfileNotFound) {
@@ -497,6 +522,7 @@ func (h *EncodingProfileHandler) ToggleEnabled(ctx context.Context, input *Toggl
 		}
 		return nil, huma.Error500InternalServerError("failed to toggle encoding profile", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncodingProfile, models.AuditActionUpdate, id, before)
 
 	return &ToggleEnabledEncodingProfileOutput{Body: EncodingProfileFromModel(profile)}, nil
 }
@@ -528,6 +554,7 @@ func (h *EncodingProfileHandler) Clone(ctx context.Context, input *CloneEncoding
 		}
 		return nil, huma.Error500InternalServerError("failed to clone encoding profile", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEncodingProfile, models.AuditActionCreate, clone.ID, nil)
 
 	return &CloneEncodingProfileOutput{Body: EncodingProfileFromModel(clone)}, nil
 }
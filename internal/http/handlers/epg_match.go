@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// EpgMatchHandler handles EPG channel match endpoints.
+type EpgMatchHandler struct {
+	matchService *service.EpgMatchService
+}
+
+// NewEpgMatchHandler creates a new EPG match handler.
+func NewEpgMatchHandler(matchService *service.EpgMatchService) *EpgMatchHandler {
+	return &EpgMatchHandler{matchService: matchService}
+}
+
+// Register registers the EPG match routes with the API.
+func (h *EpgMatchHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listEpgMatches",
+		Method:      "GET",
+		Path:        "/api/v1/epg-matches",
+		Summary:     "List EPG channel matches",
+		Description: "Returns channels matched to EPG channels by name, lowest confidence first. Defaults to pending matches awaiting confirmation.",
+		Tags:        []string{"EPG"},
+	}, h.List)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "confirmEpgMatch",
+		Method:      "POST",
+		Path:        "/api/v1/epg-matches/{id}/confirm",
+		Summary:     "Confirm EPG channel match",
+		Description: "Confirms a match so its tvg-id is always assigned to the channel, optionally correcting the tvg-id. Takes effect when proxies are next generated.",
+		Tags:        []string{"EPG"},
+	}, h.Confirm)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "rejectEpgMatch",
+		Method:      "POST",
+		Path:        "/api/v1/epg-matches/{id}/reject",
+		Summary:     "Reject EPG channel match",
+		Description: "Rejects a match; the channel is no longer matched by name. Takes effect when proxies are next generated.",
+		Tags:        []string{"EPG"},
+	}, h.Reject)
+}
+
+// EpgMatchResponse represents an EPG channel match in API responses.
+type EpgMatchResponse struct {
+	ID             models.ULID           `json:"id"`
+	ChannelID      models.ULID           `json:"channel_id"`
+	ChannelName    string                `json:"channel_name"`
+	EpgSourceID    models.ULID           `json:"epg_source_id"`
+	TvgID          string                `json:"tvg_id"`
+	EpgDisplayName string                `json:"epg_display_name,omitempty"`
+	Confidence     float64               `json:"confidence" doc:"Name similarity from 0 to 1"`
+	Status         models.EpgMatchStatus `json:"status"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// EpgMatchFromModel converts a model to a response.
+func EpgMatchFromModel(m *models.EpgChannelMatch) EpgMatchResponse {
+	return EpgMatchResponse{
+		ID:             m.ID,
+		ChannelID:      m.ChannelID,
+		ChannelName:    m.ChannelName,
+		EpgSourceID:    m.EpgSourceID,
+		TvgID:          m.TvgID,
+		EpgDisplayName: m.EpgDisplayName,
+		Confidence:     m.Confidence,
+		Status:         m.Status,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// ListEpgMatchesInput is the input for listing EPG channel matches.
+type ListEpgMatchesInput struct {
+	Status string `query:"status" default:"pending" enum:"auto,pending,confirmed,rejected,all" doc:"Match status to list"`
+}
+
+// ListEpgMatchesOutput is the output for listing EPG channel matches.
+type ListEpgMatchesOutput struct {
+	Body struct {
+		Matches []EpgMatchResponse `json:"matches"`
+	}
+}
+
+// List returns EPG channel matches.
+func (h *EpgMatchHandler) List(ctx context.Context, input *ListEpgMatchesInput) (*ListEpgMatchesOutput, error) {
+	status := models.EpgMatchStatus(input.Status)
+	if input.Status == "all" {
+		status = ""
+	}
+	matches, err := h.matchService.List(ctx, status)
+	if err != nil {
+		return nil, epgMatchServiceError("failed to list EPG channel matches", err)
+	}
+
+	resp := &ListEpgMatchesOutput{}
+	resp.Body.Matches = make([]EpgMatchResponse, 0, len(matches))
+	for _, m := range matches {
+		resp.Body.Matches = append(resp.Body.Matches, EpgMatchFromModel(m))
+	}
+	return resp, nil
+}
+
+// ConfirmEpgMatchInput is the input for confirming an EPG channel match.
+type ConfirmEpgMatchInput struct {
+	ID   string `path:"id" doc:"EPG channel match ID (ULID)"`
+	Body struct {
+		TvgID string `json:"tvg_id,omitempty" maxLength:"255" doc:"Tvg-id to assign instead of the matched one"`
+	}
+}
+
+// EpgMatchOutput is the output for EPG channel match decisions.
+type EpgMatchOutput struct {
+	Body EpgMatchResponse
+}
+
+// Confirm confirms an EPG channel match.
+func (h *EpgMatchHandler) Confirm(ctx context.Context, input *ConfirmEpgMatchInput) (*EpgMatchOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid EPG channel match ID format", err)
+	}
+	match, err := h.matchService.Confirm(ctx, id, input.Body.TvgID)
+	if err != nil {
+		return nil, epgMatchServiceError("failed to confirm EPG channel match", err)
+	}
+	return &EpgMatchOutput{Body: EpgMatchFromModel(match)}, nil
+}
+
+// RejectEpgMatchInput is the input for rejecting an EPG channel match.
+type RejectEpgMatchInput struct {
+	ID string `path:"id" doc:"EPG channel match ID (ULID)"`
+}
+
+// Reject rejects an EPG channel match.
+func (h *EpgMatchHandler) Reject(ctx context.Context, input *RejectEpgMatchInput) (*EpgMatchOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid EPG channel match ID format", err)
+	}
+	match, err := h.matchService.Reject(ctx, id)
+	if err != nil {
+		return nil, epgMatchServiceError("failed to reject EPG channel match", err)
+	}
+	return &EpgMatchOutput{Body: EpgMatchFromModel(match)}, nil
+}
+
+// epgMatchServiceError maps EPG match service errors to HTTP errors.
+func epgMatchServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	switch {
+	case errors.Is(err, service.ErrEpgMatchNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.As(err, &ve):
+		return huma.Error400BadRequest(ve.Error())
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
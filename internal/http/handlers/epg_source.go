@@ -17,6 +17,7 @@ type EpgSourceHandler struct {
 	epgService        *service.EpgService
 	scheduleSyncer    ScheduleSyncer
 	proxyUsageChecker ProxyUsageChecker
+	audit             auditTrail
 }
 
 // NewEpgSourceHandler creates a new EPG source handler.
@@ -38,6 +39,12 @@ func (h *EpgSourceHandler) WithProxyUsageChecker(checker ProxyUsageChecker) *Epg
 	return h
 }
 
+// WithAuditRecorder sets the recorder for EPG source changes.
+func (h *EpgSourceHandler) WithAuditRecorder(recorder AuditRecorder) *EpgSourceHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // syncSchedules triggers an immediate sync if a syncer is configured.
 func (h *EpgSourceHandler) syncSchedules(ctx context.Context) {
 	if h.scheduleSyncer != nil {
@@ -180,7 +187,10 @@ func (h *EpgSourceHandler) Create(ctx context.Context, input *CreateEpgSourceInp
 			errors.Is(err, models.ErrURLRequired) ||
 			errors.Is(err, models.ErrInvalidURL) ||
 			errors.Is(err, models.ErrInvalidEpgSourceType) ||
-			errors.Is(err, models.ErrXtreamCredentialsRequired) {
+			errors.Is(err, models.ErrXtreamCredentialsRequired) ||
+			errors.Is(err, models.ErrMacAddressRequired) ||
+			errors.Is(err, models.ErrInvalidMacAddress) ||
+			errors.Is(err, models.ErrInvalidProxyURL) {
 			return nil, huma.Error400BadRequest(err.Error())
 		}
 		// Check for unique constraint violation (duplicate name)
@@ -190,6 +200,7 @@ func (h *EpgSourceHandler) Create(ctx context.Context, input *CreateEpgSourceInp
 		}
 		return nil, huma.Error500InternalServerError("failed to create EPG source", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEpgSource, models.AuditActionCreate, source.ID, nil)
 
 	// Trigger immediate schedule sync if source has a cron schedule
 	if source.CronSchedule != "" {
@@ -227,11 +238,13 @@ func (h *EpgSourceHandler) Update(ctx context.Context, input *UpdateEpgSourceInp
 		return nil, huma.Error500InternalServerError("failed to get EPG source", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityEpgSource, id)
 	input.Body.ApplyToModel(source)
 
 	if err := h.epgService.Update(ctx, source); err != nil {
 		return nil, huma.Error500InternalServerError("failed to update EPG source", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEpgSource, models.AuditActionUpdate, id, before)
 
 	// Trigger immediate schedule sync (schedule may have changed)
 	h.syncSchedules(ctx)
@@ -269,12 +282,14 @@ func (h *EpgSourceHandler) Delete(ctx context.Context, input *DeleteEpgSourceInp
 		}
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityEpgSource, id)
 	if err := h.epgService.Delete(ctx, id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, huma.Error404NotFound(fmt.Sprintf("EPG source %s not found", input.ID))
 		}
 		return nil, huma.Error500InternalServerError("failed to delete EPG source", err)
 	}
+	h.audit.record(ctx, models.AuditEntityEpgSource, models.AuditActionDelete, id, before)
 
 	// Trigger immediate schedule sync (removed source's schedule needs cleanup)
 	h.syncSchedules(ctx)
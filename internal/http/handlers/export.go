@@ -15,6 +15,7 @@ import (
 type ExportHandler struct {
 	exportService *service.ExportService
 	importService *service.ImportService
+	audit         auditTrail
 }
 
 // NewExportHandler creates a new export handler.
@@ -25,6 +26,29 @@ func NewExportHandler(exportService *service.ExportService, importService *servi
 	}
 }
 
+// WithAuditRecorder sets the recorder for entities created or overwritten by imports.
+func (h *ExportHandler) WithAuditRecorder(recorder AuditRecorder) *ExportHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
+// recordImport records the entities an import created or overwrote. Imports
+// only report which entities they overwrote once done, so overwrites are
+// recorded with the new state alone.
+func (h *ExportHandler) recordImport(ctx context.Context, entityType models.AuditEntityType, result *models.ImportResult) {
+	for _, item := range result.ImportedItems {
+		id, err := models.ParseULID(item.ID)
+		if err != nil {
+			continue
+		}
+		action := models.AuditActionCreate
+		if item.Action == "overwritten" {
+			action = models.AuditActionUpdate
+		}
+		h.audit.record(ctx, entityType, action, id, nil)
+	}
+}
+
 // Register registers the export routes with the API.
 func (h *ExportHandler) Register(api huma.API) {
 	// Export endpoints
@@ -401,6 +425,8 @@ func (h *ExportHandler) ImportFilters(ctx context.Context, input *ImportFiltersI
 		return nil, huma.Error500InternalServerError("import failed", err)
 	}
 
+	h.recordImport(ctx, models.AuditEntityFilter, result)
+
 	return &ImportResultOutput{Body: *result}, nil
 }
 
@@ -422,6 +448,8 @@ func (h *ExportHandler) ImportDataMappingRules(ctx context.Context, input *Impor
 		return nil, huma.Error500InternalServerError("import failed", err)
 	}
 
+	h.recordImport(ctx, models.AuditEntityDataMappingRule, result)
+
 	return &ImportResultOutput{Body: *result}, nil
 }
 
@@ -443,6 +471,8 @@ func (h *ExportHandler) ImportClientDetectionRules(ctx context.Context, input *I
 		return nil, huma.Error500InternalServerError("import failed", err)
 	}
 
+	h.recordImport(ctx, models.AuditEntityClientDetectionRule, result)
+
 	return &ImportResultOutput{Body: *result}, nil
 }
 
@@ -464,6 +494,8 @@ func (h *ExportHandler) ImportEncodingProfiles(ctx context.Context, input *Impor
 		return nil, huma.Error500InternalServerError("import failed", err)
 	}
 
+	h.recordImport(ctx, models.AuditEntityEncodingProfile, result)
+
 	return &ImportResultOutput{Body: *result}, nil
 }
 
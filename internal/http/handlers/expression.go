@@ -49,6 +49,15 @@ func (h *ExpressionHandler) Register(api huma.API) {
 		Tags:        []string{"Expressions"},
 	}, h.GetFilterFieldsEPG)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "getFilterFieldsVOD",
+		Method:      "GET",
+		Path:        "/api/v1/filters/fields/vod",
+		Summary:     "Get available VOD filter fields",
+		Description: "Returns all fields available for movie and series filtering expressions",
+		Tags:        []string{"Expressions"},
+	}, h.GetFilterFieldsVOD)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getDataMappingFieldsStream",
 		Method:      "GET",
@@ -104,6 +113,7 @@ func (h *ExpressionHandler) Register(api huma.API) {
 ## Domain Types
 - **stream_filter** or **stream**: Stream source filtering (channel_name, group_title, stream_url, etc.)
 - **epg_filter** or **epg**: EPG source filtering (programme_title, programme_description, start_time, etc.)
+- **vod_filter** or **vod**: Movie and series filtering (vod_title, vod_type, vod_year, group_title, etc.)
 - **stream_mapping**: Stream data transformation mapping
 - **epg_mapping**: EPG data transformation mapping
 
@@ -144,7 +154,7 @@ providing intelligent suggestions for typos and unknown fields.`,
 // ValidateExpressionInput is the input for validating an expression.
 type ValidateExpressionInput struct {
 	// Domain query parameter - comma-separated list of domains
-	Domain string `query:"domain" doc:"Comma-separated list of domains to validate against (stream_filter, epg_filter, stream_mapping, epg_mapping). Defaults to stream_filter,epg_filter if not specified." required:"false"`
+	Domain string `query:"domain" doc:"Comma-separated list of domains to validate against (stream_filter, epg_filter, stream_mapping, epg_mapping, recording_rule, vod_filter). Defaults to stream_filter,epg_filter if not specified." required:"false"`
 	Body   ValidateExpressionRequest
 }
 
@@ -233,7 +243,7 @@ type FieldResponse struct {
 	Description string   `json:"description" doc:"Field description"`
 	Aliases     []string `json:"aliases,omitempty" doc:"Alternative field names"`
 	ReadOnly    bool     `json:"read_only" doc:"Whether the field is read-only"`
-	SourceType  string   `json:"source_type" doc:"Source type (stream or epg)"`
+	SourceType  string   `json:"source_type" doc:"Source type (stream, epg or vod)"`
 }
 
 // FieldsOutput is the output for field listing endpoints.
@@ -274,6 +284,11 @@ func (h *ExpressionHandler) GetFilterFieldsEPG(ctx context.Context, input *struc
 	return h.getFieldsForDomain(expression.DomainEPG, "epg"), nil
 }
 
+// GetFilterFieldsVOD returns fields available for movie and series filtering.
+func (h *ExpressionHandler) GetFilterFieldsVOD(ctx context.Context, input *struct{}) (*FieldsOutput, error) {
+	return h.getFieldsForDomain(expression.DomainVOD, "vod"), nil
+}
+
 // GetDataMappingFieldsStream returns fields available for stream data mapping.
 func (h *ExpressionHandler) GetDataMappingFieldsStream(ctx context.Context, input *struct{}) (*FieldsOutput, error) {
 	return h.getFieldsForDomain(expression.DomainStream, "stream"), nil
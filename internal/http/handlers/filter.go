@@ -14,6 +14,7 @@ import (
 type FilterHandler struct {
 	repo              repository.FilterRepository
 	proxyUsageChecker ProxyUsageChecker
+	audit             auditTrail
 }
 
 // NewFilterHandler creates a new filter handler.
@@ -27,6 +28,12 @@ func (h *FilterHandler) WithProxyUsageChecker(checker ProxyUsageChecker) *Filter
 	return h
 }
 
+// WithAuditRecorder sets the recorder for filter changes.
+func (h *FilterHandler) WithAuditRecorder(recorder AuditRecorder) *FilterHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // Register registers the filter routes with the API.
 func (h *FilterHandler) Register(api huma.API) {
 	huma.Register(api, huma.Operation{
@@ -82,7 +89,7 @@ type FilterResponse struct {
 	ID          string  `json:"id" doc:"Filter ID (ULID)"`
 	Name        string  `json:"name" doc:"Filter name"`
 	Description string  `json:"description,omitempty" doc:"Filter description"`
-	SourceType  string  `json:"source_type" doc:"Source type (stream or epg)"`
+	SourceType  string  `json:"source_type" doc:"Source type (stream, epg or vod)"`
 	Action      string  `json:"action" doc:"Filter action (include or exclude)"`
 	Expression  string  `json:"expression" doc:"Filter expression"`
 	IsSystem    bool    `json:"is_system" doc:"Whether this is a system-provided filter (cannot be edited/deleted)"`
@@ -113,7 +120,7 @@ func FilterFromModel(f *models.Filter) FilterResponse {
 
 // ListFiltersInput is the input for listing filters.
 type ListFiltersInput struct {
-	SourceType string `query:"source_type" doc:"Filter by source type (stream or epg)" required:"false"`
+	SourceType string `query:"source_type" doc:"Filter by source type (stream, epg or vod)" required:"false"`
 }
 
 // ListFiltersOutput is the output for listing filters.
@@ -183,7 +190,7 @@ func (h *FilterHandler) GetByID(ctx context.Context, input *GetFilterInput) (*Ge
 type CreateFilterRequest struct {
 	Name        string  `json:"name" doc:"Filter name" minLength:"1" maxLength:"255"`
 	Description string  `json:"description,omitempty" doc:"Filter description" maxLength:"1024"`
-	SourceType  string  `json:"source_type" doc:"Source type (stream or epg)" enum:"stream,epg"`
+	SourceType  string  `json:"source_type" doc:"Source type (stream, epg or vod)" enum:"stream,epg,vod"`
 	Action      string  `json:"action" doc:"Filter action (include or exclude)" enum:"include,exclude"`
 	Expression  string  `json:"expression" doc:"Filter expression" minLength:"1"`
 	SourceID    *string `json:"source_id,omitempty" doc:"Source ID to restrict filter to (optional)"`
@@ -220,6 +227,7 @@ func (h *FilterHandler) Create(ctx context.Context, input *CreateFilterInput) (*
 	if err := h.repo.Create(ctx, filter); err != nil {
 		return nil, huma.Error500InternalServerError("failed to create filter", err)
 	}
+	h.audit.record(ctx, models.AuditEntityFilter, models.AuditActionCreate, filter.ID, nil)
 
 	return &CreateFilterOutput{
 		Body: FilterFromModel(filter),
@@ -230,7 +238,7 @@ func (h *FilterHandler) Create(ctx context.Context, input *CreateFilterInput) (*
 type UpdateFilterRequest struct {
 	Name        *string `json:"name,omitempty" doc:"Filter name" maxLength:"255"`
 	Description *string `json:"description,omitempty" doc:"Filter description" maxLength:"1024"`
-	SourceType  *string `json:"source_type,omitempty" doc:"Source type (stream or epg)" enum:"stream,epg"`
+	SourceType  *string `json:"source_type,omitempty" doc:"Source type (stream, epg or vod)" enum:"stream,epg,vod"`
 	Action      *string `json:"action,omitempty" doc:"Filter action (include or exclude)" enum:"include,exclude"`
 	Expression  *string `json:"expression,omitempty" doc:"Filter expression"`
 	SourceID    *string `json:"source_id,omitempty" doc:"Source ID to restrict filter to (null to make global)"`
@@ -267,6 +275,7 @@ func (h *FilterHandler) Update(ctx context.Context, input *UpdateFilterInput) (*
 	if filter.IsSystem {
 		return nil, huma.Error403Forbidden("system filters cannot be modified")
 	}
+	before := h.audit.capture(ctx, models.AuditEntityFilter, id)
 
 	// Apply updates for non-system filters
 	if input.Body.Name != nil {
@@ -299,6 +308,7 @@ func (h *FilterHandler) Update(ctx context.Context, input *UpdateFilterInput) (*
 	if err := h.repo.Update(ctx, filter); err != nil {
 		return nil, huma.Error500InternalServerError("failed to update filter", err)
 	}
+	h.audit.record(ctx, models.AuditEntityFilter, models.AuditActionUpdate, id, before)
 
 	return &UpdateFilterOutput{
 		Body: FilterFromModel(filter),
@@ -350,9 +360,11 @@ func (h *FilterHandler) Delete(ctx context.Context, input *DeleteFilterInput) (*
 		}
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityFilter, id)
 	if err := h.repo.Delete(ctx, id); err != nil {
 		return nil, huma.Error500InternalServerError("failed to delete filter", err)
 	}
+	h.audit.record(ctx, models.AuditEntityFilter, models.AuditActionDelete, id, before)
 
 	return &DeleteFilterOutput{
 		Body: struct {
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmylchreest/tvarr/internal/hdhomerun"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
+	"github.com/jmylchreest/tvarr/internal/storage"
+)
+
+// hdhomerunPathPrefix is the route prefix under which each proxy's emulated tuner lives.
+const hdhomerunPathPrefix = "/hdhr"
+
+// HDHomeRunHandler serves HDHomeRun-compatible tuner endpoints for each stream proxy,
+// so Plex, Jellyfin and Emby can add a proxy as a network tuner.
+type HDHomeRunHandler struct {
+	proxyService      *service.ProxyService
+	sandbox           *storage.Sandbox
+	baseURL           string
+	defaultTunerCount int
+	viewers           ViewerAuthenticator
+	logger            *slog.Logger
+}
+
+// NewHDHomeRunHandler creates a new HDHomeRun handler.
+func NewHDHomeRunHandler(proxyService *service.ProxyService, sandbox *storage.Sandbox) *HDHomeRunHandler {
+	return &HDHomeRunHandler{
+		proxyService: proxyService,
+		sandbox:      sandbox,
+		logger:       slog.Default(),
+	}
+}
+
+// WithLogger sets the logger for the handler.
+func (h *HDHomeRunHandler) WithLogger(logger *slog.Logger) *HDHomeRunHandler {
+	if logger != nil {
+		h.logger = logger
+	}
+	return h
+}
+
+// WithBaseURL sets the externally reachable base URL advertised to media servers.
+// When empty, the base URL is derived from each request.
+func (h *HDHomeRunHandler) WithBaseURL(baseURL string) *HDHomeRunHandler {
+	h.baseURL = strings.TrimSuffix(baseURL, "/")
+	return h
+}
+
+// WithDefaultTunerCount sets the tuner count advertised for proxies without a
+// stream limit, the hdhomerun.default_tuner_count setting.
+func (h *HDHomeRunHandler) WithDefaultTunerCount(count int) *HDHomeRunHandler {
+	if count > 0 {
+		h.defaultTunerCount = count
+	}
+	return h
+}
+
+// WithViewerAuth requires a viewer token on tuner requests and embeds the viewer's
+// stream credential in lineup URLs. Media servers cannot send query parameters, so
+// the token is carried in the path: /hdhr/{proxyID}/{token}/discover.json.
+func (h *HDHomeRunHandler) WithViewerAuth(viewers ViewerAuthenticator) *HDHomeRunHandler {
+	h.viewers = viewers
+	return h
+}
+
+// RegisterChiRoutes registers the HDHomeRun device routes.
+// Routes (each also available under /hdhr/{proxyID}/{token}/ for viewer authentication):
+//   - GET /hdhr/{proxyID}/discover.json - Device information
+//   - GET /hdhr/{proxyID}/lineup.json - Channel lineup
+//   - GET /hdhr/{proxyID}/lineup_status.json - Channel scan status
+//   - GET /hdhr/{proxyID}/device.xml - UPnP device description
+//   - POST /hdhr/{proxyID}/lineup.post - Channel scan trigger (no-op)
+func (h *HDHomeRunHandler) RegisterChiRoutes(router chi.Router) {
+	deviceRoutes := func(r chi.Router) {
+		r.Get("/discover.json", h.serveDiscover)
+		r.Get("/lineup.json", h.serveLineup)
+		r.Get("/lineup_status.json", h.serveLineupStatus)
+		r.Get("/device.xml", h.serveDeviceXML)
+		r.Post("/lineup.post", h.serveLineupPost)
+	}
+	router.Route(hdhomerunPathPrefix+"/{proxyID}", func(r chi.Router) {
+		deviceRoutes(r)
+		r.Route("/{token}", deviceRoutes)
+	})
+}
+
+// serveDiscover handles GET /hdhr/{proxyID}/discover.json.
+func (h *HDHomeRunHandler) serveDiscover(w http.ResponseWriter, r *http.Request) {
+	device, _, _, ok := h.resolveDevice(w, r)
+	if !ok {
+		return
+	}
+	writeHDHomeRunJSON(w, hdhomerun.NewDiscoverResponse(device))
+}
+
+// serveLineup handles GET /hdhr/{proxyID}/lineup.json.
+// The lineup is read from the proxy's generated M3U so it always matches the published playlist.
+func (h *HDHomeRunHandler) serveLineup(w http.ResponseWriter, r *http.Request) {
+	device, proxy, viewer, ok := h.resolveDevice(w, r)
+	if !ok {
+		return
+	}
+
+	lineup, err := h.buildLineup(proxy.ID, h.serverBaseURL(r), viewer)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			http.Error(w, fmt.Sprintf("lineup not generated for proxy %s", proxy.ID), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to build hdhomerun lineup",
+			slog.String("proxy_id", proxy.ID.String()),
+			slog.String("device_id", device.DeviceIDString()),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to build lineup", http.StatusInternalServerError)
+		return
+	}
+
+	writeHDHomeRunJSON(w, lineup)
+}
+
+// serveLineupStatus handles GET /hdhr/{proxyID}/lineup_status.json.
+func (h *HDHomeRunHandler) serveLineupStatus(w http.ResponseWriter, r *http.Request) {
+	if _, _, _, ok := h.resolveDevice(w, r); !ok {
+		return
+	}
+	writeHDHomeRunJSON(w, hdhomerun.NewLineupStatus())
+}
+
+// serveDeviceXML handles GET /hdhr/{proxyID}/device.xml.
+func (h *HDHomeRunHandler) serveDeviceXML(w http.ResponseWriter, r *http.Request) {
+	device, _, _, ok := h.resolveDevice(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := hdhomerun.MarshalDeviceXML(device)
+	if err != nil {
+		h.logger.Error("failed to render hdhomerun device.xml", slog.String("error", err.Error()))
+		http.Error(w, "failed to render device description", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// serveLineupPost handles POST /hdhr/{proxyID}/lineup.post.
+// Media servers use this to start a channel scan; the lineup is driven by proxy
+// generation instead, so the request is acknowledged without doing anything.
+func (h *HDHomeRunHandler) serveLineupPost(w http.ResponseWriter, r *http.Request) {
+	if _, _, _, ok := h.resolveDevice(w, r); !ok {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// resolveDevice looks up the proxy from the route, authenticates the viewer when
+// viewer authentication is enabled, and builds the device description.
+// It writes an error response and returns false if the proxy cannot be served.
+func (h *HDHomeRunHandler) resolveDevice(w http.ResponseWriter, r *http.Request) (*hdhomerun.Device, *models.StreamProxy, *models.Viewer, bool) {
+	proxyIDStr := chi.URLParam(r, "proxyID")
+	proxyID, err := models.ParseULID(proxyIDStr)
+	if err != nil {
+		http.Error(w, "invalid proxy ID format", http.StatusBadRequest)
+		return nil, nil, nil, false
+	}
+
+	token := chi.URLParam(r, "token")
+	viewer, ok := authenticateViewer(w, r, h.viewers, token, h.logger)
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	proxy, err := h.proxyService.GetByID(r.Context(), proxyID)
+	if err != nil {
+		h.logger.Error("failed to get proxy for hdhomerun device",
+			slog.String("proxy_id", proxyIDStr),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to get proxy", http.StatusInternalServerError)
+		return nil, nil, nil, false
+	}
+	if proxy == nil || !models.BoolVal(proxy.IsActive) {
+		http.Error(w, fmt.Sprintf("proxy %s not found", proxyIDStr), http.StatusNotFound)
+		return nil, nil, nil, false
+	}
+
+	device := h.deviceForProxy(proxy, h.serverBaseURL(r))
+	if viewer != nil {
+		// Keep the token in the advertised base URL so follow-up requests carry it.
+		device.BaseURL += "/" + url.PathEscape(token)
+	}
+	return &device, proxy, viewer, true
+}
+
+// Devices returns the emulated tuners for all active, generated proxies.
+// It implements hdhomerun.DeviceProvider for network discovery. With viewer
+// authentication no tuners are advertised: discovery is unauthenticated, and a
+// tuner found without a viewer token in its base URL could not be used.
+func (h *HDHomeRunHandler) Devices(ctx context.Context, baseURL string) ([]hdhomerun.Device, error) {
+	if h.viewers != nil {
+		return nil, nil
+	}
+
+	proxies, err := h.proxyService.GetActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.baseURL != "" {
+		baseURL = h.baseURL
+	}
+
+	devices := make([]hdhomerun.Device, 0, len(proxies))
+	for _, proxy := range proxies {
+		// Proxies that have never generated have no lineup to offer.
+		if proxy.LastGeneratedAt == nil {
+			continue
+		}
+		devices = append(devices, h.deviceForProxy(proxy, baseURL))
+	}
+	return devices, nil
+}
+
+// deviceForProxy builds the emulated device for a proxy.
+func (h *HDHomeRunHandler) deviceForProxy(proxy *models.StreamProxy, baseURL string) hdhomerun.Device {
+	tuners := proxy.MaxConcurrentStreams
+	if tuners <= 0 {
+		tuners = h.defaultTunerCount
+	}
+
+	return hdhomerun.Device{
+		DeviceID:     hdhomerun.DeviceIDFromString(proxy.ID.String()),
+		FriendlyName: "tvarr " + proxy.Name,
+		BaseURL:      fmt.Sprintf("%s%s/%s", baseURL, hdhomerunPathPrefix, proxy.ID),
+		TunerCount:   tuners,
+	}
+}
+
+// buildLineup converts the proxy's generated M3U into HDHomeRun lineup entries.
+// Stream URLs that point at the relay route are rebased onto serverBaseURL so media
+// servers reach tvarr on the same address they used for discovery, and carry the
+// viewer's stream credential when viewer is non-nil.
+func (h *HDHomeRunHandler) buildLineup(proxyID models.ULID, serverBaseURL string, viewer *models.Viewer) ([]hdhomerun.LineupEntry, error) {
+	entries, err := readGeneratedM3U(h.sandbox, proxyID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineup := make([]hdhomerun.LineupEntry, 0, len(entries))
+	for i, entry := range entries {
+		guideNumber := strconv.Itoa(i + 1)
+		if entry.ChannelNumber > 0 {
+			guideNumber = strconv.Itoa(entry.ChannelNumber)
+		}
+		guideName := entry.Title
+		if guideName == "" {
+			guideName = entry.TvgName
+		}
+		lineup = append(lineup, hdhomerun.LineupEntry{
+			GuideNumber: guideNumber,
+			GuideName:   guideName,
+			URL:         withStreamCredential(rebaseRelayURL(entry.URL, serverBaseURL), h.viewers, viewer),
+		})
+	}
+
+	return lineup, nil
+}
+
+// serverBaseURL returns the server root advertised to the media server.
+func (h *HDHomeRunHandler) serverBaseURL(r *http.Request) string {
+	return requestBaseURL(r, h.baseURL)
+}
+
+// writeHDHomeRunJSON writes a JSON response in the form HDHomeRun clients expect.
+func writeHDHomeRunJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -209,7 +209,7 @@ func (h *JobHandler) GetByID(ctx context.Context, input *GetJobInput) (*GetJobOu
 
 // ListJobsByTypeInput is the input for listing jobs by type.
 type ListJobsByTypeInput struct {
-	Type string `path:"type" doc:"Job type (stream_ingestion, epg_ingestion, proxy_generation, logo_cleanup)" enum:"stream_ingestion,epg_ingestion,proxy_generation,logo_cleanup"`
+	Type string `path:"type" doc:"Job type (stream_ingestion, epg_ingestion, proxy_generation, logo_cleanup, account_check)" enum:"stream_ingestion,epg_ingestion,proxy_generation,logo_cleanup,account_check"`
 }
 
 // ListJobsByTypeOutput is the output for listing jobs by type.
@@ -291,7 +291,7 @@ func (h *JobHandler) ListRunning(ctx context.Context, input *ListRunningJobsInpu
 
 // GetJobHistoryInput is the input for getting job history.
 type GetJobHistoryInput struct {
-	Type   string `query:"type" doc:"Filter by job type (optional)" enum:"stream_ingestion,epg_ingestion,proxy_generation,logo_cleanup,"`
+	Type   string `query:"type" doc:"Filter by job type (optional)" enum:"stream_ingestion,epg_ingestion,proxy_generation,logo_cleanup,account_check,"`
 	Offset int    `query:"offset" default:"0" minimum:"0" doc:"Offset for pagination"`
 	Limit  int    `query:"limit" default:"50" minimum:"1" maximum:"1000" doc:"Limit for pagination"`
 }
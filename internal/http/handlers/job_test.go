@@ -279,6 +279,10 @@ func (m *mockStreamSourceRepoForJob) UpdateLastIngestion(ctx context.Context, id
 	return nil
 }
 
+func (m *mockStreamSourceRepoForJob) UpdateAccountStatus(ctx context.Context, source *models.StreamSource) error {
+	return nil
+}
+
 // mockEpgSourceRepoForJob implements repository.EpgSourceRepository for testing.
 type mockEpgSourceRepoForJob struct {
 	sources map[models.ULID]*models.EpgSource
@@ -441,6 +445,14 @@ func (m *mockProxyRepoForJob) GetProxyNamesByFilterID(ctx context.Context, filte
 	return nil, nil
 }
 
+func (m *mockProxyRepoForJob) SetChannelAlternates(ctx context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error {
+	return nil
+}
+
+func (m *mockProxyRepoForJob) GetChannelAlternates(ctx context.Context, proxyID, channelID models.ULID) ([]*models.ProxyChannelAlternate, error) {
+	return nil, nil
+}
+
 func (m *mockProxyRepoForJob) GetProxyNamesByStreamSourceID(ctx context.Context, sourceID models.ULID) ([]string, error) {
 	return nil, nil
 }
@@ -126,8 +126,45 @@ type GetRecentLogsOutput struct {
 	Body GetRecentLogsBody
 }
 
+// QueryLogsInput is the input for querying logs.
+type QueryLogsInput struct {
+	Since     string `query:"since" doc:"Only logs at or after this time (RFC3339)"`
+	Until     string `query:"until" doc:"Only logs before this time (RFC3339)"`
+	Level     string `query:"level" required:"false" enum:"trace,debug,info,warn,error," doc:"Minimum log level"`
+	Module    string `query:"module" doc:"Filter by module name"`
+	RequestID string `query:"request_id" doc:"Filter by HTTP request ID"`
+	SessionID string `query:"session_id" doc:"Filter by relay session ID"`
+	ChannelID string `query:"channel_id" doc:"Filter by channel ID"`
+	Q         string `query:"q" doc:"Case-insensitive text to search for in messages and fields"`
+	Offset    int    `query:"offset" default:"0" minimum:"0" doc:"Offset for pagination"`
+	Limit     int    `query:"limit" default:"100" minimum:"1" maximum:"1000" doc:"Limit for pagination"`
+}
+
+// QueryLogsBody is the response body for querying logs.
+type QueryLogsBody struct {
+	Logs       []LogEntryResponse `json:"logs"`
+	Pagination PaginationMeta     `json:"pagination"`
+	// Persistent is true when logs were queried from the log store rather
+	// than the in-memory buffer of recent logs.
+	Persistent bool `json:"persistent"`
+}
+
+// QueryLogsOutput is the output for querying logs.
+type QueryLogsOutput struct {
+	Body QueryLogsBody
+}
+
 // Register registers the logs routes with the API.
 func (h *LogsHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "queryLogs",
+		Method:      "GET",
+		Path:        "/api/v1/logs",
+		Summary:     "Query logs",
+		Description: "Returns logs matching the filters, newest first, from the log store when enabled or the in-memory buffer otherwise",
+		Tags:        []string{"Logs"},
+	}, h.QueryLogs)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getLogStats",
 		Method:      "GET",
@@ -220,6 +257,58 @@ func (h *LogsHandler) GetRecentLogs(ctx context.Context, input *GetRecentLogsInp
 	return output, nil
 }
 
+// QueryLogs returns logs matching the filters, newest first.
+func (h *LogsHandler) QueryLogs(ctx context.Context, input *QueryLogsInput) (*QueryLogsOutput, error) {
+	query := logs.Query{
+		Level:     input.Level,
+		Module:    input.Module,
+		RequestID: input.RequestID,
+		SessionID: input.SessionID,
+		ChannelID: input.ChannelID,
+		Search:    input.Q,
+		Offset:    input.Offset,
+		Limit:     input.Limit,
+	}
+	var err error
+	if input.Since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, input.Since); err != nil {
+			return nil, huma.Error400BadRequest("invalid since time, expected RFC3339", err)
+		}
+	}
+	if input.Until != "" {
+		if query.Until, err = time.Parse(time.RFC3339, input.Until); err != nil {
+			return nil, huma.Error400BadRequest("invalid until time, expected RFC3339", err)
+		}
+	}
+
+	entries, total, err := h.service.Query(ctx, query)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to query logs", err)
+	}
+
+	output := &QueryLogsOutput{
+		Body: QueryLogsBody{
+			Logs:       make([]LogEntryResponse, len(entries)),
+			Persistent: h.service.Persistent(),
+		},
+	}
+	for i, entry := range entries {
+		output.Body.Logs[i] = LogEntryFromService(entry)
+	}
+
+	totalPages := total / int64(input.Limit)
+	if total%int64(input.Limit) > 0 {
+		totalPages++
+	}
+	output.Body.Pagination = PaginationMeta{
+		CurrentPage: (input.Offset / input.Limit) + 1,
+		PageSize:    input.Limit,
+		TotalItems:  total,
+		TotalPages:  totalPages,
+	}
+	return output, nil
+}
+
 // handleSSEStream is the raw HTTP handler for SSE streaming.
 func (h *LogsHandler) handleSSEStream(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers for cross-origin requests (frontend on different port)
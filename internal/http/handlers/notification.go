@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// NotificationHandler handles notification target and delivery log endpoints.
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler.
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// Register registers the notification routes with the API.
+func (h *NotificationHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listNotificationTargets",
+		Method:      "GET",
+		Path:        "/api/v1/notifications/targets",
+		Summary:     "List notification targets",
+		Description: "Returns all notification targets",
+		Tags:        []string{"Notifications"},
+	}, h.List)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getNotificationTarget",
+		Method:      "GET",
+		Path:        "/api/v1/notifications/targets/{id}",
+		Summary:     "Get notification target",
+		Description: "Returns a notification target by ID",
+		Tags:        []string{"Notifications"},
+	}, h.GetByID)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "createNotificationTarget",
+		Method:        "POST",
+		Path:          "/api/v1/notifications/targets",
+		Summary:       "Create notification target",
+		Description:   "Creates a webhook, ntfy, Gotify or email notification target",
+		Tags:          []string{"Notifications"},
+		DefaultStatus: http.StatusCreated,
+	}, h.Create)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "updateNotificationTarget",
+		Method:      "PUT",
+		Path:        "/api/v1/notifications/targets/{id}",
+		Summary:     "Update notification target",
+		Description: "Updates a notification target. Omitting secret keeps the current secret",
+		Tags:        []string{"Notifications"},
+	}, h.Update)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "deleteNotificationTarget",
+		Method:        "DELETE",
+		Path:          "/api/v1/notifications/targets/{id}",
+		Summary:       "Delete notification target",
+		Description:   "Deletes a notification target and its delivery log",
+		Tags:          []string{"Notifications"},
+		DefaultStatus: http.StatusNoContent,
+	}, h.Delete)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "testNotificationTarget",
+		Method:      "POST",
+		Path:        "/api/v1/notifications/targets/{id}/test",
+		Summary:     "Test notification target",
+		Description: "Sends a test event to the target and returns the delivery outcome",
+		Tags:        []string{"Notifications"},
+	}, h.Test)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "listNotificationDeliveries",
+		Method:      "GET",
+		Path:        "/api/v1/notifications/deliveries",
+		Summary:     "List notification deliveries",
+		Description: "Returns the delivery log, newest first",
+		Tags:        []string{"Notifications"},
+	}, h.GetDeliveries)
+}
+
+// NotificationTargetResponse represents a notification target in API responses.
+// Secrets are never returned.
+type NotificationTargetResponse struct {
+	ID        models.ULID                   `json:"id"`
+	Name      string                        `json:"name"`
+	Type      models.NotificationTargetType `json:"type"`
+	Enabled   bool                          `json:"enabled"`
+	Events    []string                      `json:"events" doc:"Subscribed event types; empty means all events"`
+	URL       string                        `json:"url,omitempty"`
+	HasSecret bool                          `json:"has_secret" doc:"Whether a signing secret or access token is set"`
+	EmailTo   []string                      `json:"email_to,omitempty"`
+	CreatedAt time.Time                     `json:"created_at"`
+	UpdatedAt time.Time                     `json:"updated_at"`
+}
+
+// NotificationTargetFromModel converts a model to a response.
+func NotificationTargetFromModel(t *models.NotificationTarget) NotificationTargetResponse {
+	events := make([]string, 0, len(t.EventList()))
+	for _, e := range t.EventList() {
+		events = append(events, string(e))
+	}
+	return NotificationTargetResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Type:      t.Type,
+		Enabled:   t.IsEnabled(),
+		Events:    events,
+		URL:       t.URL,
+		HasSecret: t.Secret != "",
+		EmailTo:   t.Recipients(),
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// ListNotificationTargetsInput is the input for listing notification targets.
+type ListNotificationTargetsInput struct{}
+
+// ListNotificationTargetsOutput is the output for listing notification targets.
+type ListNotificationTargetsOutput struct {
+	Body struct {
+		Targets []NotificationTargetResponse `json:"targets"`
+	}
+}
+
+// List returns all notification targets.
+func (h *NotificationHandler) List(ctx context.Context, _ *ListNotificationTargetsInput) (*ListNotificationTargetsOutput, error) {
+	targets, err := h.notificationService.List(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list notification targets", err)
+	}
+
+	resp := &ListNotificationTargetsOutput{}
+	resp.Body.Targets = make([]NotificationTargetResponse, 0, len(targets))
+	for _, t := range targets {
+		resp.Body.Targets = append(resp.Body.Targets, NotificationTargetFromModel(t))
+	}
+	return resp, nil
+}
+
+// GetNotificationTargetInput is the input for getting a notification target.
+type GetNotificationTargetInput struct {
+	ID string `path:"id" doc:"Notification target ID (ULID)"`
+}
+
+// GetNotificationTargetOutput is the output for getting a notification target.
+type GetNotificationTargetOutput struct {
+	Body NotificationTargetResponse
+}
+
+// GetByID returns a notification target by ID.
+func (h *NotificationHandler) GetByID(ctx context.Context, input *GetNotificationTargetInput) (*GetNotificationTargetOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid notification target ID format", err)
+	}
+	target, err := h.notificationService.GetByID(ctx, id)
+	if err != nil {
+		return nil, notificationServiceError("failed to get notification target", err)
+	}
+	return &GetNotificationTargetOutput{Body: NotificationTargetFromModel(target)}, nil
+}
+
+// NotificationTargetBody is the editable part of a notification target.
+type NotificationTargetBody struct {
+	Name    string   `json:"name" minLength:"1" maxLength:"100" doc:"Unique target name"`
+	Type    string   `json:"type" enum:"webhook,ntfy,gotify,email" doc:"Delivery method"`
+	Enabled *bool    `json:"enabled,omitempty" doc:"Whether notifications are delivered (default true)"`
+	Events  []string `json:"events,omitempty" enum:"ingestion.failed,proxy.generation_failed,backup.failed,job.failed,circuit_breaker.opened,circuit_breaker.closed,daemon.offline,daemon.recovered" doc:"Subscribed event types; omit for all events"`
+	URL     string   `json:"url,omitempty" maxLength:"2048" doc:"Webhook URL, ntfy topic URL or Gotify server URL"`
+	Secret  *string  `json:"secret,omitempty" maxLength:"255" doc:"Webhook signing secret, ntfy access token or Gotify application token"`
+	EmailTo []string `json:"email_to,omitempty" doc:"Email recipients"`
+}
+
+// apply copies the body onto a target. A nil secret keeps the current one.
+func (b *NotificationTargetBody) apply(target *models.NotificationTarget) {
+	target.Name = b.Name
+	target.Type = models.NotificationTargetType(b.Type)
+	if b.Enabled != nil {
+		target.Enabled = b.Enabled
+	}
+	target.Events = strings.Join(b.Events, ",")
+	target.URL = b.URL
+	if b.Secret != nil {
+		target.Secret = *b.Secret
+	}
+	target.EmailTo = strings.Join(b.EmailTo, ",")
+}
+
+// CreateNotificationTargetInput is the input for creating a notification target.
+type CreateNotificationTargetInput struct {
+	Body NotificationTargetBody
+}
+
+// CreateNotificationTargetOutput is the output for creating a notification target.
+type CreateNotificationTargetOutput struct {
+	Body NotificationTargetResponse
+}
+
+// Create creates a new notification target.
+func (h *NotificationHandler) Create(ctx context.Context, input *CreateNotificationTargetInput) (*CreateNotificationTargetOutput, error) {
+	target := &models.NotificationTarget{}
+	input.Body.apply(target)
+	if err := h.notificationService.Create(ctx, target); err != nil {
+		return nil, notificationServiceError("failed to create notification target", err)
+	}
+	return &CreateNotificationTargetOutput{Body: NotificationTargetFromModel(target)}, nil
+}
+
+// UpdateNotificationTargetInput is the input for updating a notification target.
+type UpdateNotificationTargetInput struct {
+	ID   string `path:"id" doc:"Notification target ID (ULID)"`
+	Body NotificationTargetBody
+}
+
+// UpdateNotificationTargetOutput is the output for updating a notification target.
+type UpdateNotificationTargetOutput struct {
+	Body NotificationTargetResponse
+}
+
+// Update replaces a notification target's editable fields.
+func (h *NotificationHandler) Update(ctx context.Context, input *UpdateNotificationTargetInput) (*UpdateNotificationTargetOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid notification target ID format", err)
+	}
+	target, err := h.notificationService.GetByID(ctx, id)
+	if err != nil {
+		return nil, notificationServiceError("failed to get notification target", err)
+	}
+
+	input.Body.apply(target)
+	if err := h.notificationService.Update(ctx, target); err != nil {
+		return nil, notificationServiceError("failed to update notification target", err)
+	}
+	return &UpdateNotificationTargetOutput{Body: NotificationTargetFromModel(target)}, nil
+}
+
+// DeleteNotificationTargetInput is the input for deleting a notification target.
+type DeleteNotificationTargetInput struct {
+	ID string `path:"id" doc:"Notification target ID (ULID)"`
+}
+
+// DeleteNotificationTargetOutput is the output for deleting a notification target.
+type DeleteNotificationTargetOutput struct{}
+
+// Delete deletes a notification target.
+func (h *NotificationHandler) Delete(ctx context.Context, input *DeleteNotificationTargetInput) (*DeleteNotificationTargetOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid notification target ID format", err)
+	}
+	if err := h.notificationService.Delete(ctx, id); err != nil {
+		return nil, notificationServiceError("failed to delete notification target", err)
+	}
+	return &DeleteNotificationTargetOutput{}, nil
+}
+
+// NotificationDeliveryResponse represents a delivery log entry in API responses.
+type NotificationDeliveryResponse struct {
+	ID         models.ULID                       `json:"id"`
+	TargetID   models.ULID                       `json:"target_id"`
+	TargetName string                            `json:"target_name"`
+	EventID    string                            `json:"event_id"`
+	EventType  models.NotificationEventType      `json:"event_type"`
+	Title      string                            `json:"title"`
+	Status     models.NotificationDeliveryStatus `json:"status"`
+	Attempts   int                               `json:"attempts"`
+	StatusCode int                               `json:"status_code,omitempty"`
+	Error      string                            `json:"error,omitempty"`
+	DurationMs int64                             `json:"duration_ms"`
+	CreatedAt  time.Time                         `json:"created_at"`
+}
+
+// NotificationDeliveryFromModel converts a model to a response.
+func NotificationDeliveryFromModel(d *models.NotificationDelivery) NotificationDeliveryResponse {
+	return NotificationDeliveryResponse{
+		ID:         d.ID,
+		TargetID:   d.TargetID,
+		TargetName: d.TargetName,
+		EventID:    d.EventID,
+		EventType:  d.EventType,
+		Title:      d.Title,
+		Status:     d.Status,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		DurationMs: d.DurationMs,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+// TestNotificationTargetInput is the input for testing a notification target.
+type TestNotificationTargetInput struct {
+	ID string `path:"id" doc:"Notification target ID (ULID)"`
+}
+
+// TestNotificationTargetOutput is the output for testing a notification target.
+type TestNotificationTargetOutput struct {
+	Body NotificationDeliveryResponse
+}
+
+// Test sends a test event to a notification target.
+func (h *NotificationHandler) Test(ctx context.Context, input *TestNotificationTargetInput) (*TestNotificationTargetOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid notification target ID format", err)
+	}
+	delivery, err := h.notificationService.Test(ctx, id)
+	if err != nil {
+		return nil, notificationServiceError("failed to test notification target", err)
+	}
+	return &TestNotificationTargetOutput{Body: NotificationDeliveryFromModel(delivery)}, nil
+}
+
+// ListNotificationDeliveriesInput is the input for listing notification deliveries.
+type ListNotificationDeliveriesInput struct {
+	TargetID string `query:"target_id" doc:"Filter by notification target ID (optional)"`
+	Offset   int    `query:"offset" default:"0" minimum:"0" doc:"Offset for pagination"`
+	Limit    int    `query:"limit" default:"50" minimum:"1" maximum:"1000" doc:"Limit for pagination"`
+}
+
+// ListNotificationDeliveriesOutput is the output for listing notification deliveries.
+type ListNotificationDeliveriesOutput struct {
+	Body struct {
+		Deliveries []NotificationDeliveryResponse `json:"deliveries"`
+		Pagination PaginationMeta                 `json:"pagination"`
+	}
+}
+
+// GetDeliveries returns the notification delivery log.
+func (h *NotificationHandler) GetDeliveries(ctx context.Context, input *ListNotificationDeliveriesInput) (*ListNotificationDeliveriesOutput, error) {
+	var targetID *models.ULID
+	if input.TargetID != "" {
+		id, err := models.ParseULID(input.TargetID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("invalid notification target ID format", err)
+		}
+		targetID = &id
+	}
+
+	deliveries, total, err := h.notificationService.GetDeliveries(ctx, targetID, input.Offset, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list notification deliveries", err)
+	}
+
+	resp := &ListNotificationDeliveriesOutput{}
+	resp.Body.Deliveries = make([]NotificationDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp.Body.Deliveries = append(resp.Body.Deliveries, NotificationDeliveryFromModel(d))
+	}
+
+	totalPages := total / int64(input.Limit)
+	if total%int64(input.Limit) > 0 {
+		totalPages++
+	}
+	resp.Body.Pagination = PaginationMeta{
+		CurrentPage: (input.Offset / input.Limit) + 1,
+		PageSize:    input.Limit,
+		TotalItems:  total,
+		TotalPages:  totalPages,
+	}
+	return resp, nil
+}
+
+// notificationServiceError maps notification service errors to HTTP errors.
+func notificationServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	switch {
+	case errors.Is(err, service.ErrNotificationTargetNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, service.ErrNotificationTargetExists):
+		return huma.Error409Conflict(err.Error())
+	case errors.As(err, &ve):
+		return huma.Error400BadRequest(ve.Error())
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
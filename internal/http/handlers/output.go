@@ -2,22 +2,40 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jmylchreest/tvarr/internal/auth"
 	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
 	"github.com/jmylchreest/tvarr/internal/storage"
+	"github.com/jmylchreest/tvarr/pkg/m3u"
 )
 
+// ViewerAuthenticator authenticates viewers on playback routes (playlists, tuners
+// and Xtream output) and issues the credential embedded in the relay URLs handed to them.
+type ViewerAuthenticator interface {
+	// AuthenticateViewer resolves a viewer token.
+	AuthenticateViewer(ctx context.Context, token string) (*models.Viewer, error)
+	// StreamCredential returns the credential to embed in a viewer's relay URL for a channel.
+	StreamCredential(viewer *models.Viewer, proxyID, channelID models.ULID) string
+}
+
 // OutputHandler handles serving generated M3U and XMLTV output files.
 type OutputHandler struct {
 	sandbox *storage.Sandbox
+	viewers ViewerAuthenticator
 	logger  *slog.Logger
 }
 
@@ -37,13 +55,24 @@ func (h *OutputHandler) WithLogger(logger *slog.Logger) *OutputHandler {
 	return h
 }
 
+// WithViewerAuth requires a viewer token (?token=) on playlist requests and embeds
+// the viewer's stream credential in every relay URL of the served playlist.
+func (h *OutputHandler) WithViewerAuth(viewers ViewerAuthenticator) *OutputHandler {
+	h.viewers = viewers
+	return h
+}
+
 // RegisterFileServer registers the file server routes for M3U and XMLTV files.
 // Routes:
 //   - GET /proxy/{id}.m3u - Serve M3U playlist
 //   - GET /proxy/{id}.xmltv - Serve XMLTV file
+//   - GET /proxy/{id}.vod.m3u - Serve movie playlist
+//   - GET /proxy/{id}.series.m3u - Serve series playlist
 func (h *OutputHandler) RegisterFileServer(router *chi.Mux) {
 	router.Get("/proxy/{proxyID}.m3u", h.serveM3U)
 	router.Get("/proxy/{proxyID}.xmltv", h.serveXMLTV)
+	router.Get("/proxy/{proxyID}.vod.m3u", h.serveVodM3U(".vod.m3u"))
+	router.Get("/proxy/{proxyID}.series.m3u", h.serveVodM3U(".series.m3u"))
 }
 
 // GetM3UInput is the input for getting the M3U file.
@@ -78,6 +107,11 @@ func (h *OutputHandler) serveM3U(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	viewer, ok := authenticateViewer(w, r, h.viewers, r.URL.Query().Get(auth.StreamTokenParam), h.logger)
+	if !ok {
+		return
+	}
+
 	data, err := h.readOutputFile(proxyID, ".m3u")
 	if err != nil {
 		// Use errors.Is to properly detect wrapped os.ErrNotExist
@@ -93,6 +127,12 @@ func (h *OutputHandler) serveM3U(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if viewer != nil {
+		data = rewriteM3UStreamURLs(data, func(streamURL string) string {
+			return withStreamCredential(streamURL, h.viewers, viewer)
+		})
+	}
+
 	w.Header().Set("Content-Type", "audio/x-mpegurl")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.m3u\"", proxyID))
 	w.WriteHeader(http.StatusOK)
@@ -109,6 +149,10 @@ func (h *OutputHandler) serveXMLTV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := authenticateViewer(w, r, h.viewers, r.URL.Query().Get(auth.StreamTokenParam), h.logger); !ok {
+		return
+	}
+
 	data, err := h.readOutputFile(proxyID, ".xml")
 	if err != nil {
 		// Use errors.Is to properly detect wrapped os.ErrNotExist
@@ -130,6 +174,43 @@ func (h *OutputHandler) serveXMLTV(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(data)
 }
 
+// serveVodM3U returns a handler for a VOD playlist with the given extension.
+// VOD entries point at the upstream streams, so no stream credential is added.
+func (h *OutputHandler) serveVodM3U(ext string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		proxyID := chi.URLParam(r, "proxyID")
+
+		// Validate ULID format
+		if _, err := models.ParseULID(proxyID); err != nil {
+			http.Error(w, "invalid proxy ID format", http.StatusBadRequest)
+			return
+		}
+
+		if _, ok := authenticateViewer(w, r, h.viewers, r.URL.Query().Get(auth.StreamTokenParam), h.logger); !ok {
+			return
+		}
+
+		data, err := h.readOutputFile(proxyID, ext)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				http.Error(w, fmt.Sprintf("VOD playlist not found for proxy %s", proxyID), http.StatusNotFound)
+				return
+			}
+			h.logger.Error("failed to read VOD playlist",
+				slog.String("proxy_id", proxyID),
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "failed to read VOD playlist", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "audio/x-mpegurl")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s%s\"", proxyID, ext))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	}
+}
+
 // readOutputFile reads an output file from the sandbox.
 func (h *OutputHandler) readOutputFile(proxyID, ext string) ([]byte, error) {
 	// Sanitize the proxy ID to prevent path traversal
@@ -142,3 +223,155 @@ func (h *OutputHandler) readOutputFile(proxyID, ext string) ([]byte, error) {
 	filename := filepath.Join("output", proxyID+ext)
 	return h.sandbox.ReadFile(filename)
 }
+
+// readGeneratedM3U parses the generated M3U for a proxy into its entries.
+// Returns an error wrapping os.ErrNotExist if the proxy has not been generated.
+func readGeneratedM3U(sandbox *storage.Sandbox, proxyID models.ULID) ([]*m3u.Entry, error) {
+	return readGeneratedPlaylist(sandbox, proxyID, ".m3u")
+}
+
+// readGeneratedPlaylist parses a generated playlist for a proxy, such as the
+// movie playlist (".vod.m3u"), into its entries. Returns an error wrapping
+// os.ErrNotExist if the playlist has not been generated.
+func readGeneratedPlaylist(sandbox *storage.Sandbox, proxyID models.ULID, ext string) ([]*m3u.Entry, error) {
+	data, err := sandbox.ReadFile(filepath.Join("output", proxyID.String()+ext))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*m3u.Entry
+	parser := &m3u.Parser{
+		OnEntry: func(entry *m3u.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		},
+	}
+	if err := parser.Parse(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("parsing generated M3U: %w", err)
+	}
+
+	return entries, nil
+}
+
+// requestBaseURL returns the externally visible server root for a request,
+// preferring the configured base URL and honouring X-Forwarded-* headers otherwise.
+func requestBaseURL(r *http.Request, configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := r.Host
+	if fwdHost := r.Header.Get("X-Forwarded-Host"); fwdHost != "" {
+		host = fwdHost
+	}
+	return fmt.Sprintf("%s://%s", scheme, host)
+}
+
+// rebaseRelayURL replaces the scheme and host of a /proxy/ relay URL with serverBaseURL.
+// Non-relay URLs (e.g. proxies generated without a base URL) are returned unchanged.
+func rebaseRelayURL(streamURL, serverBaseURL string) string {
+	u, err := url.Parse(streamURL)
+	if err != nil || !strings.HasPrefix(u.Path, "/proxy/") {
+		return streamURL
+	}
+	rebased := serverBaseURL + u.Path
+	if u.RawQuery != "" {
+		rebased += "?" + u.RawQuery
+	}
+	return rebased
+}
+
+// authenticateViewer checks a viewer token on a playback request when viewer
+// authentication is enabled (viewers is non-nil). It writes a 401 response and
+// returns false if the token is rejected. With viewer authentication disabled it
+// returns a nil viewer and true.
+func authenticateViewer(w http.ResponseWriter, r *http.Request, viewers ViewerAuthenticator, token string, logger *slog.Logger) (*models.Viewer, bool) {
+	if viewers == nil {
+		return nil, true
+	}
+
+	viewer, err := viewers.AuthenticateViewer(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, service.ErrStreamUnauthorized) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return nil, false
+		}
+		logger.Error("failed to authenticate viewer",
+			slog.String("path", r.URL.Path),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to authenticate viewer", http.StatusInternalServerError)
+		return nil, false
+	}
+	return viewer, true
+}
+
+// withStreamCredential adds the viewer's stream credential to a
+// /proxy/{proxyId}/{channelId} relay or catch-up URL. Other URLs are returned unchanged.
+// The query is appended rather than re-encoded so catch-up template placeholders
+// such as {utc} survive.
+func withStreamCredential(streamURL string, viewers ViewerAuthenticator, viewer *models.Viewer) string {
+	if viewers == nil || viewer == nil {
+		return streamURL
+	}
+
+	u, err := url.Parse(streamURL)
+	if err != nil {
+		return streamURL
+	}
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	isCatchup := len(parts) == 4 && parts[3] == "catchup"
+	if (len(parts) != 3 && !isCatchup) || parts[0] != "proxy" {
+		return streamURL
+	}
+	proxyID, err := models.ParseULID(parts[1])
+	if err != nil {
+		return streamURL
+	}
+	channelID, err := models.ParseULID(parts[2])
+	if err != nil {
+		return streamURL
+	}
+
+	sep := "?"
+	if u.RawQuery != "" {
+		sep = "&"
+	}
+	return streamURL + sep + auth.StreamTokenParam + "=" + url.QueryEscape(viewers.StreamCredential(viewer, proxyID, channelID))
+}
+
+// catchupSourceAttrRegex matches the catchup-source attribute of an EXTINF line.
+var catchupSourceAttrRegex = regexp.MustCompile(`catchup-source="([^"]*)"`)
+
+// rewriteM3UStreamURLs applies fn to every URL line of an M3U playlist and to
+// catchup-source attributes, leaving other directives and comments untouched.
+func rewriteM3UStreamURLs(data []byte, fn func(string) string) []byte {
+	var out bytes.Buffer
+	out.Grow(len(data) + len(data)/8)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#EXTINF"):
+			line = catchupSourceAttrRegex.ReplaceAllStringFunc(line, func(attr string) string {
+				value := catchupSourceAttrRegex.FindStringSubmatch(attr)[1]
+				return `catchup-source="` + fn(value) + `"`
+			})
+		case trimmed != "" && !strings.HasPrefix(trimmed, "#"):
+			line = fn(trimmed)
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
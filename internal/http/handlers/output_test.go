@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeViewerAuth issues "cred-<channelID>" as the stream credential.
+type fakeViewerAuth struct{}
+
+func (fakeViewerAuth) AuthenticateViewer(_ context.Context, _ string) (*models.Viewer, error) {
+	return &models.Viewer{}, nil
+}
+
+func (fakeViewerAuth) StreamCredential(_ *models.Viewer, _, channelID models.ULID) string {
+	return "cred-" + channelID.String()
+}
+
+func TestWithStreamCredential(t *testing.T) {
+	proxyID := models.NewULID()
+	channelID := models.NewULID()
+	relayURL := "http://tvarr:8080/proxy/" + proxyID.String() + "/" + channelID.String()
+	viewer := &models.Viewer{}
+
+	got := withStreamCredential(relayURL, fakeViewerAuth{}, viewer)
+	assert.Equal(t, relayURL+"?token=cred-"+channelID.String(), got)
+
+	got = withStreamCredential(relayURL+"?format=hls", fakeViewerAuth{}, viewer)
+	assert.Equal(t, relayURL+"?format=hls&token=cred-"+channelID.String(), got)
+
+	// Direct upstream URLs and disabled auth are left alone.
+	assert.Equal(t, "http://upstream/live/1.ts", withStreamCredential("http://upstream/live/1.ts", fakeViewerAuth{}, viewer))
+	assert.Equal(t, relayURL, withStreamCredential(relayURL, nil, viewer))
+	assert.Equal(t, relayURL, withStreamCredential(relayURL, fakeViewerAuth{}, nil))
+
+	// Catch-up templates keep their placeholders.
+	catchup := relayURL + "/catchup?start={utc}&duration={duration}"
+	assert.Equal(t, catchup+"&token=cred-"+channelID.String(), withStreamCredential(catchup, fakeViewerAuth{}, viewer))
+}
+
+func TestRewriteM3UStreamURLs(t *testing.T) {
+	data := []byte("#EXTM3U\n#EXTINF:-1 tvg-id=\"a\",A\nhttp://a/1\n\n#EXTINF:-1,B\n  http://b/2  \n")
+
+	got := rewriteM3UStreamURLs(data, func(u string) string { return u + "?x=1" })
+
+	assert.Equal(t, "#EXTM3U\n#EXTINF:-1 tvg-id=\"a\",A\nhttp://a/1?x=1\n\n#EXTINF:-1,B\nhttp://b/2?x=1\n", string(got))
+}
+
+func TestRewriteM3UStreamURLs_CatchupSource(t *testing.T) {
+	data := []byte("#EXTM3U\n#EXTINF:-1 catchup=\"default\" catchup-source=\"http://a/1/catchup?start={utc}\",A\nhttp://a/1\n")
+
+	got := rewriteM3UStreamURLs(data, func(u string) string { return u + "&x=1" })
+
+	assert.Equal(t, "#EXTM3U\n#EXTINF:-1 catchup=\"default\" catchup-source=\"http://a/1/catchup?start={utc}&x=1\",A\nhttp://a/1&x=1\n", string(got))
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// recordingFilenameUnsafe matches characters replaced in download filenames.
+var recordingFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._ -]+`)
+
+// RecordingHandler handles DVR recording endpoints.
+type RecordingHandler struct {
+	recordingService *service.RecordingService
+}
+
+// NewRecordingHandler creates a new recording handler.
+func NewRecordingHandler(recordingService *service.RecordingService) *RecordingHandler {
+	return &RecordingHandler{recordingService: recordingService}
+}
+
+// Register registers the recording routes with the API.
+func (h *RecordingHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listRecordings",
+		Method:      "GET",
+		Path:        "/api/v1/recordings",
+		Summary:     "List recordings",
+		Description: "Returns scheduled, in-progress and finished recordings, newest first",
+		Tags:        []string{"Recordings"},
+	}, h.List)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getRecording",
+		Method:      "GET",
+		Path:        "/api/v1/recordings/{id}",
+		Summary:     "Get recording",
+		Description: "Returns a recording by ID",
+		Tags:        []string{"Recordings"},
+	}, h.GetByID)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "createRecording",
+		Method:        "POST",
+		Path:          "/api/v1/recordings",
+		Summary:       "Schedule recording",
+		Description:   "Schedules a recording of an EPG programme, or of a channel between two times. Returns 409 if the recording would exceed the source's concurrent stream limit.",
+		Tags:          []string{"Recordings"},
+		DefaultStatus: http.StatusCreated,
+	}, h.Create)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "stopRecording",
+		Method:      "POST",
+		Path:        "/api/v1/recordings/{id}/stop",
+		Summary:     "Stop recording",
+		Description: "Cancels a scheduled recording or ends one in progress, keeping anything already captured",
+		Tags:        []string{"Recordings"},
+	}, h.Stop)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "deleteRecording",
+		Method:        "DELETE",
+		Path:          "/api/v1/recordings/{id}",
+		Summary:       "Delete recording",
+		Description:   "Stops the recording if needed and deletes it and its file",
+		Tags:          []string{"Recordings"},
+		DefaultStatus: http.StatusNoContent,
+	}, h.Delete)
+}
+
+// RegisterChiRoutes registers Chi-specific routes for recording downloads.
+func (h *RecordingHandler) RegisterChiRoutes(r chi.Router) {
+	r.Get("/api/v1/recordings/{id}/download", h.Download)
+}
+
+// RecordingResponse represents a recording in API responses.
+type RecordingResponse struct {
+	ID            models.ULID            `json:"id"`
+	ChannelID     models.ULID            `json:"channel_id"`
+	ChannelName   string                 `json:"channel_name"`
+	ProgramID     *models.ULID           `json:"program_id,omitempty"`
+	RuleID        *models.ULID           `json:"rule_id,omitempty"`
+	Title         string                 `json:"title"`
+	SubTitle      string                 `json:"sub_title,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	SeasonNumber  int                    `json:"season_number,omitempty"`
+	EpisodeNumber int                    `json:"episode_number,omitempty"`
+	StartTime     time.Time              `json:"start_time"`
+	EndTime       time.Time              `json:"end_time"`
+	PaddingBefore int                    `json:"padding_before" doc:"Seconds recorded before start_time"`
+	PaddingAfter  int                    `json:"padding_after" doc:"Seconds recorded after end_time"`
+	Format        models.RecordingFormat `json:"format"`
+	Status        models.RecordingStatus `json:"status"`
+	FileSize      int64                  `json:"file_size"`
+	LastError     string                 `json:"last_error,omitempty"`
+	StartedAt     *time.Time             `json:"started_at,omitempty"`
+	CompletedAt   *time.Time             `json:"completed_at,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// RecordingFromModel converts a model to a response.
+func RecordingFromModel(r *models.Recording) RecordingResponse {
+	return RecordingResponse{
+		ID:            r.ID,
+		ChannelID:     r.ChannelID,
+		ChannelName:   r.ChannelName,
+		ProgramID:     r.ProgramID,
+		RuleID:        r.RuleID,
+		Title:         r.Title,
+		SubTitle:      r.SubTitle,
+		Description:   r.Description,
+		SeasonNumber:  r.SeasonNumber,
+		EpisodeNumber: r.EpisodeNumber,
+		StartTime:     r.StartTime,
+		EndTime:       r.EndTime,
+		PaddingBefore: r.PaddingBefore,
+		PaddingAfter:  r.PaddingAfter,
+		Format:        r.Format,
+		Status:        r.Status,
+		FileSize:      r.FileSize,
+		LastError:     r.LastError,
+		StartedAt:     r.StartedAt,
+		CompletedAt:   r.CompletedAt,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+// ListRecordingsInput is the input for listing recordings.
+type ListRecordingsInput struct{}
+
+// ListRecordingsOutput is the output for listing recordings.
+type ListRecordingsOutput struct {
+	Body struct {
+		Recordings []RecordingResponse `json:"recordings"`
+	}
+}
+
+// List returns all recordings.
+func (h *RecordingHandler) List(ctx context.Context, _ *ListRecordingsInput) (*ListRecordingsOutput, error) {
+	recordings, err := h.recordingService.List(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list recordings", err)
+	}
+
+	resp := &ListRecordingsOutput{}
+	resp.Body.Recordings = make([]RecordingResponse, 0, len(recordings))
+	for _, r := range recordings {
+		resp.Body.Recordings = append(resp.Body.Recordings, RecordingFromModel(r))
+	}
+	return resp, nil
+}
+
+// GetRecordingInput is the input for getting a recording.
+type GetRecordingInput struct {
+	ID string `path:"id" doc:"Recording ID (ULID)"`
+}
+
+// GetRecordingOutput is the output for getting a recording.
+type GetRecordingOutput struct {
+	Body RecordingResponse
+}
+
+// GetByID returns a recording by ID.
+func (h *RecordingHandler) GetByID(ctx context.Context, input *GetRecordingInput) (*GetRecordingOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid recording ID format", err)
+	}
+	recording, err := h.recordingService.GetByID(ctx, id)
+	if err != nil {
+		return nil, recordingServiceError("failed to get recording", err)
+	}
+	return &GetRecordingOutput{Body: RecordingFromModel(recording)}, nil
+}
+
+// CreateRecordingInput is the input for scheduling a recording.
+type CreateRecordingInput struct {
+	Body struct {
+		ChannelID     string     `json:"channel_id" doc:"Channel to record (ULID)"`
+		ProgramID     string     `json:"program_id,omitempty" doc:"EPG programme to record (ULID); sets title and times"`
+		Title         string     `json:"title,omitempty" maxLength:"512" doc:"Title for a manual recording (required without program_id)"`
+		StartTime     *time.Time `json:"start_time,omitempty" doc:"Start of a manual recording (required without program_id)"`
+		EndTime       *time.Time `json:"end_time,omitempty" doc:"End of a manual recording (required without program_id)"`
+		PaddingBefore *int       `json:"padding_before,omitempty" minimum:"0" doc:"Seconds to record before the start (default from recording.padding_before)"`
+		PaddingAfter  *int       `json:"padding_after,omitempty" minimum:"0" doc:"Seconds to record after the end (default from recording.padding_after)"`
+		Format        string     `json:"format,omitempty" enum:"mpegts,fmp4" doc:"Container format (default from recording.format)"`
+	}
+}
+
+// CreateRecordingOutput is the output for scheduling a recording.
+type CreateRecordingOutput struct {
+	Body RecordingResponse
+}
+
+// Create schedules a new recording.
+func (h *RecordingHandler) Create(ctx context.Context, input *CreateRecordingInput) (*CreateRecordingOutput, error) {
+	channelID, err := models.ParseULID(input.Body.ChannelID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid channel ID format", err)
+	}
+
+	req := service.ScheduleRecordingRequest{
+		ChannelID: channelID,
+		Title:     input.Body.Title,
+		Format:    models.RecordingFormat(input.Body.Format),
+	}
+	if input.Body.ProgramID != "" {
+		programID, err := models.ParseULID(input.Body.ProgramID)
+		if err != nil {
+			return nil, huma.Error400BadRequest("invalid program ID format", err)
+		}
+		req.ProgramID = &programID
+	} else {
+		if input.Body.StartTime == nil || input.Body.EndTime == nil {
+			return nil, huma.Error400BadRequest("start_time and end_time are required without program_id")
+		}
+		req.Start = *input.Body.StartTime
+		req.End = *input.Body.EndTime
+	}
+	if input.Body.PaddingBefore != nil {
+		d := time.Duration(*input.Body.PaddingBefore) * time.Second
+		req.PaddingBefore = &d
+	}
+	if input.Body.PaddingAfter != nil {
+		d := time.Duration(*input.Body.PaddingAfter) * time.Second
+		req.PaddingAfter = &d
+	}
+
+	recording, err := h.recordingService.Schedule(ctx, req)
+	if err != nil {
+		return nil, recordingServiceError("failed to schedule recording", err)
+	}
+	return &CreateRecordingOutput{Body: RecordingFromModel(recording)}, nil
+}
+
+// StopRecordingInput is the input for stopping a recording.
+type StopRecordingInput struct {
+	ID string `path:"id" doc:"Recording ID (ULID)"`
+}
+
+// StopRecordingOutput is the output for stopping a recording.
+type StopRecordingOutput struct {
+	Body RecordingResponse
+}
+
+// Stop cancels or ends a recording.
+func (h *RecordingHandler) Stop(ctx context.Context, input *StopRecordingInput) (*StopRecordingOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid recording ID format", err)
+	}
+	recording, err := h.recordingService.Stop(ctx, id)
+	if err != nil {
+		return nil, recordingServiceError("failed to stop recording", err)
+	}
+	return &StopRecordingOutput{Body: RecordingFromModel(recording)}, nil
+}
+
+// DeleteRecordingInput is the input for deleting a recording.
+type DeleteRecordingInput struct {
+	ID string `path:"id" doc:"Recording ID (ULID)"`
+}
+
+// DeleteRecordingOutput is the output for deleting a recording.
+type DeleteRecordingOutput struct{}
+
+// Delete deletes a recording and its file.
+func (h *RecordingHandler) Delete(ctx context.Context, input *DeleteRecordingInput) (*DeleteRecordingOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid recording ID format", err)
+	}
+	if err := h.recordingService.Delete(ctx, id); err != nil {
+		return nil, recordingServiceError("failed to delete recording", err)
+	}
+	return &DeleteRecordingOutput{}, nil
+}
+
+// Download streams a recording file. Range requests are supported, so
+// finished recordings can be played directly from this URL.
+func (h *RecordingHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := models.ParseULID(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid recording ID format", http.StatusBadRequest)
+		return
+	}
+
+	recording, file, err := h.recordingService.Open(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRecordingNotFound), errors.Is(err, service.ErrRecordingFileUnavailable):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "failed to open recording", http.StatusInternalServerError)
+		}
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "failed to stat recording file", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", recording.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, recordingFilename(recording)))
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+// recordingFilename returns a download filename such as "Evening News 2026-03-01 2000.ts".
+func recordingFilename(r *models.Recording) string {
+	name := strings.TrimSpace(recordingFilenameUnsafe.ReplaceAllString(r.Title, ""))
+	if name == "" {
+		name = r.ID.String()
+	}
+	return fmt.Sprintf("%s %s%s", name, r.StartTime.UTC().Format("2006-01-02 1504"), r.Format.Extension())
+}
+
+// recordingServiceError maps recording service errors to HTTP errors.
+func recordingServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	switch {
+	case errors.Is(err, service.ErrRecordingNotFound),
+		errors.Is(err, service.ErrChannelNotFound),
+		errors.Is(err, service.ErrProgramNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, service.ErrRecordingConflict),
+		errors.Is(err, service.ErrRecordingExists),
+		errors.Is(err, service.ErrRecordingNotActive):
+		return huma.Error409Conflict(err.Error())
+	case errors.As(err, &ve):
+		return huma.Error400BadRequest(ve.Error())
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
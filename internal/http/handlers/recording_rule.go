@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// RecordingRuleHandler handles series recording rule endpoints.
+type RecordingRuleHandler struct {
+	ruleService *service.RecordingRuleService
+}
+
+// NewRecordingRuleHandler creates a new recording rule handler.
+func NewRecordingRuleHandler(ruleService *service.RecordingRuleService) *RecordingRuleHandler {
+	return &RecordingRuleHandler{ruleService: ruleService}
+}
+
+// Register registers the recording rule routes with the API.
+func (h *RecordingRuleHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listRecordingRules",
+		Method:      "GET",
+		Path:        "/api/v1/recording-rules",
+		Summary:     "List recording rules",
+		Description: "Returns all series recording rules",
+		Tags:        []string{"Recordings"},
+	}, h.List)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getRecordingRule",
+		Method:      "GET",
+		Path:        "/api/v1/recording-rules/{id}",
+		Summary:     "Get recording rule",
+		Description: "Returns a recording rule by ID",
+		Tags:        []string{"Recordings"},
+	}, h.GetByID)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "createRecordingRule",
+		Method:        "POST",
+		Path:          "/api/v1/recording-rules",
+		Summary:       "Create recording rule",
+		Description:   "Creates a rule that records upcoming EPG programmes matching an expression. Rules are evaluated after each EPG ingestion.",
+		Tags:          []string{"Recordings"},
+		DefaultStatus: http.StatusCreated,
+	}, h.Create)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "updateRecordingRule",
+		Method:      "PUT",
+		Path:        "/api/v1/recording-rules/{id}",
+		Summary:     "Update recording rule",
+		Description: "Updates a recording rule. Recordings it already scheduled are unchanged.",
+		Tags:        []string{"Recordings"},
+	}, h.Update)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "deleteRecordingRule",
+		Method:        "DELETE",
+		Path:          "/api/v1/recording-rules/{id}",
+		Summary:       "Delete recording rule",
+		Description:   "Deletes a recording rule. Recordings it scheduled are kept.",
+		Tags:          []string{"Recordings"},
+		DefaultStatus: http.StatusNoContent,
+	}, h.Delete)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "applyRecordingRules",
+		Method:      "POST",
+		Path:        "/api/v1/recording-rules/apply",
+		Summary:     "Apply recording rules",
+		Description: "Evaluates enabled rules against the current EPG data now, instead of waiting for the next EPG ingestion",
+		Tags:        []string{"Recordings"},
+	}, h.Apply)
+}
+
+// RecordingRuleResponse represents a recording rule in API responses.
+type RecordingRuleResponse struct {
+	ID            models.ULID            `json:"id"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description,omitempty"`
+	Expression    string                 `json:"expression"`
+	IsEnabled     bool                   `json:"is_enabled"`
+	PaddingBefore *int                   `json:"padding_before,omitempty" doc:"Seconds recorded before each programme (default from recording.padding_before)"`
+	PaddingAfter  *int                   `json:"padding_after,omitempty" doc:"Seconds recorded after each programme (default from recording.padding_after)"`
+	Format        models.RecordingFormat `json:"format,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// RecordingRuleFromModel converts a model to a response.
+func RecordingRuleFromModel(r *models.RecordingRule) RecordingRuleResponse {
+	return RecordingRuleResponse{
+		ID:            r.ID,
+		Name:          r.Name,
+		Description:   r.Description,
+		Expression:    r.Expression,
+		IsEnabled:     models.BoolVal(r.IsEnabled),
+		PaddingBefore: r.PaddingBefore,
+		PaddingAfter:  r.PaddingAfter,
+		Format:        r.Format,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
+
+// RecordingRuleBody is the editable fields of a recording rule.
+type RecordingRuleBody struct {
+	Name          string `json:"name" minLength:"1" maxLength:"255"`
+	Description   string `json:"description,omitempty" maxLength:"1024"`
+	Expression    string `json:"expression" minLength:"1" doc:"Expression over programme and channel fields, e.g. programme_title matches \"^Match of the Day\" AND channel_name contains \"BBC One\""`
+	IsEnabled     *bool  `json:"is_enabled,omitempty" doc:"Whether the rule schedules recordings (default true)"`
+	PaddingBefore *int   `json:"padding_before,omitempty" minimum:"0" doc:"Seconds recorded before each programme (default from recording.padding_before)"`
+	PaddingAfter  *int   `json:"padding_after,omitempty" minimum:"0" doc:"Seconds recorded after each programme (default from recording.padding_after)"`
+	Format        string `json:"format,omitempty" enum:"mpegts,fmp4" doc:"Container format (default from recording.format)"`
+}
+
+// apply copies the body onto a rule.
+func (b *RecordingRuleBody) apply(rule *models.RecordingRule) {
+	rule.Name = b.Name
+	rule.Description = b.Description
+	rule.Expression = b.Expression
+	if b.IsEnabled != nil {
+		rule.IsEnabled = b.IsEnabled
+	}
+	rule.PaddingBefore = b.PaddingBefore
+	rule.PaddingAfter = b.PaddingAfter
+	rule.Format = models.RecordingFormat(b.Format)
+}
+
+// ListRecordingRulesInput is the input for listing recording rules.
+type ListRecordingRulesInput struct{}
+
+// ListRecordingRulesOutput is the output for listing recording rules.
+type ListRecordingRulesOutput struct {
+	Body struct {
+		Rules []RecordingRuleResponse `json:"rules"`
+	}
+}
+
+// List returns all recording rules.
+func (h *RecordingRuleHandler) List(ctx context.Context, _ *ListRecordingRulesInput) (*ListRecordingRulesOutput, error) {
+	rules, err := h.ruleService.List(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list recording rules", err)
+	}
+
+	resp := &ListRecordingRulesOutput{}
+	resp.Body.Rules = make([]RecordingRuleResponse, 0, len(rules))
+	for _, r := range rules {
+		resp.Body.Rules = append(resp.Body.Rules, RecordingRuleFromModel(r))
+	}
+	return resp, nil
+}
+
+// GetRecordingRuleInput is the input for getting a recording rule.
+type GetRecordingRuleInput struct {
+	ID string `path:"id" doc:"Recording rule ID (ULID)"`
+}
+
+// GetRecordingRuleOutput is the output for getting a recording rule.
+type GetRecordingRuleOutput struct {
+	Body RecordingRuleResponse
+}
+
+// GetByID returns a recording rule by ID.
+func (h *RecordingRuleHandler) GetByID(ctx context.Context, input *GetRecordingRuleInput) (*GetRecordingRuleOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid recording rule ID format", err)
+	}
+	rule, err := h.ruleService.GetByID(ctx, id)
+	if err != nil {
+		return nil, recordingRuleServiceError("failed to get recording rule", err)
+	}
+	return &GetRecordingRuleOutput{Body: RecordingRuleFromModel(rule)}, nil
+}
+
+// CreateRecordingRuleInput is the input for creating a recording rule.
+type CreateRecordingRuleInput struct {
+	Body RecordingRuleBody
+}
+
+// CreateRecordingRuleOutput is the output for creating a recording rule.
+type CreateRecordingRuleOutput struct {
+	Body RecordingRuleResponse
+}
+
+// Create creates a new recording rule.
+func (h *RecordingRuleHandler) Create(ctx context.Context, input *CreateRecordingRuleInput) (*CreateRecordingRuleOutput, error) {
+	rule := &models.RecordingRule{}
+	input.Body.apply(rule)
+	if err := h.ruleService.Create(ctx, rule); err != nil {
+		return nil, recordingRuleServiceError("failed to create recording rule", err)
+	}
+	return &CreateRecordingRuleOutput{Body: RecordingRuleFromModel(rule)}, nil
+}
+
+// UpdateRecordingRuleInput is the input for updating a recording rule.
+type UpdateRecordingRuleInput struct {
+	ID   string `path:"id" doc:"Recording rule ID (ULID)"`
+	Body RecordingRuleBody
+}
+
+// UpdateRecordingRuleOutput is the output for updating a recording rule.
+type UpdateRecordingRuleOutput struct {
+	Body RecordingRuleResponse
+}
+
+// Update replaces a recording rule's editable fields.
+func (h *RecordingRuleHandler) Update(ctx context.Context, input *UpdateRecordingRuleInput) (*UpdateRecordingRuleOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid recording rule ID format", err)
+	}
+	rule, err := h.ruleService.GetByID(ctx, id)
+	if err != nil {
+		return nil, recordingRuleServiceError("failed to get recording rule", err)
+	}
+
+	input.Body.apply(rule)
+	if err := h.ruleService.Update(ctx, rule); err != nil {
+		return nil, recordingRuleServiceError("failed to update recording rule", err)
+	}
+	return &UpdateRecordingRuleOutput{Body: RecordingRuleFromModel(rule)}, nil
+}
+
+// DeleteRecordingRuleInput is the input for deleting a recording rule.
+type DeleteRecordingRuleInput struct {
+	ID string `path:"id" doc:"Recording rule ID (ULID)"`
+}
+
+// DeleteRecordingRuleOutput is the output for deleting a recording rule.
+type DeleteRecordingRuleOutput struct{}
+
+// Delete deletes a recording rule.
+func (h *RecordingRuleHandler) Delete(ctx context.Context, input *DeleteRecordingRuleInput) (*DeleteRecordingRuleOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid recording rule ID format", err)
+	}
+	if err := h.ruleService.Delete(ctx, id); err != nil {
+		return nil, recordingRuleServiceError("failed to delete recording rule", err)
+	}
+	return &DeleteRecordingRuleOutput{}, nil
+}
+
+// ApplyRecordingRulesInput is the input for applying recording rules.
+type ApplyRecordingRulesInput struct{}
+
+// ApplyRecordingRulesOutput is the output for applying recording rules.
+type ApplyRecordingRulesOutput struct {
+	Body struct {
+		Scheduled int `json:"scheduled" doc:"Number of recordings scheduled"`
+	}
+}
+
+// Apply evaluates enabled rules against the current EPG data.
+func (h *RecordingRuleHandler) Apply(ctx context.Context, _ *ApplyRecordingRulesInput) (*ApplyRecordingRulesOutput, error) {
+	scheduled, err := h.ruleService.ApplyAll(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to apply recording rules", err)
+	}
+	resp := &ApplyRecordingRulesOutput{}
+	resp.Body.Scheduled = scheduled
+	return resp, nil
+}
+
+// recordingRuleServiceError maps recording rule service errors to HTTP errors.
+func recordingRuleServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	switch {
+	case errors.Is(err, service.ErrRecordingRuleNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.As(err, &ve):
+		return huma.Error400BadRequest(ve.Error())
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
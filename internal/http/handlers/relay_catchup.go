@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/internal/service"
+	"github.com/jmylchreest/tvarr/internal/version"
+)
+
+// Catch-up query parameters. The catchup-source template in generated playlists
+// fills these from the player's {utc} and {duration} placeholders.
+const (
+	// catchupParamStart is the programme start as a Unix timestamp.
+	catchupParamStart = "start"
+	// catchupParamDuration is the programme duration in seconds.
+	catchupParamDuration = "duration"
+)
+
+// catchupForwardHeaders are request headers passed through to the archive upstream.
+var catchupForwardHeaders = []string{"Range", "User-Agent", "Accept"}
+
+// catchupResponseHeaders are upstream response headers passed back to the client.
+var catchupResponseHeaders = []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges"}
+
+// StreamChannelCatchupInput is the input for catch-up archive playback.
+type StreamChannelCatchupInput struct {
+	ProxyID   string `path:"proxyId" doc:"Stream Proxy ID (ULID)"`
+	ChannelID string `path:"channelId" doc:"Channel ID (ULID)"`
+	Start     int64  `query:"start" doc:"Programme start as a Unix timestamp"`
+	Duration  int64  `query:"duration" doc:"Programme duration in seconds"`
+	Token     string `query:"token" doc:"Viewer token or signed stream token (required when stream authentication is enabled)"`
+}
+
+// catchupDocsHandler is a no-op handler for documentation-only registration.
+// The actual request handling is done by handleCatchup.
+func (h *RelayStreamHandler) catchupDocsHandler(ctx context.Context, input *StreamChannelCatchupInput) (*huma.StreamResponse, error) {
+	return nil, huma.Error500InternalServerError("this endpoint is handled by raw Chi handlers", nil)
+}
+
+// handleCatchup plays a programme from the channel's catch-up archive.
+// Direct-mode proxies redirect to the archive URL; smart-mode proxies proxy it,
+// so the source URL and its credentials are never exposed to the client.
+func (h *RelayStreamHandler) handleCatchup(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startUnix, err := strconv.ParseInt(query.Get(catchupParamStart), 10, 64)
+	if err != nil {
+		http.Error(w, "start must be a Unix timestamp", http.StatusBadRequest)
+		return
+	}
+	durationSecs, err := strconv.ParseInt(query.Get(catchupParamDuration), 10, 64)
+	if err != nil {
+		http.Error(w, "duration must be a number of seconds", http.StatusBadRequest)
+		return
+	}
+
+	info, ok := h.resolveStreamInfo(w, r)
+	if !ok {
+		return
+	}
+
+	start := time.Unix(startUnix, 0)
+	duration := time.Duration(durationSecs) * time.Second
+	archiveURL, err := h.relayService.CatchupURL(info.Channel, start, duration, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCatchupUnavailable):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrCatchupOutOfRange):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "failed to build catch-up URL", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("Catch-up playback",
+		"proxy_id", info.Proxy.ID,
+		"channel_id", info.Channel.ID,
+		"start", start.UTC(),
+		"duration", duration,
+	)
+
+	if info.Proxy.ProxyMode == models.StreamProxyModeDirect && !needsUpstreamHeaders(info.Channel) {
+		w.Header().Set("Location", archiveURL)
+		setStreamHeaders(w, "direct", "redirect")
+		w.WriteHeader(http.StatusFound)
+		return
+	}
+
+	h.proxyCatchupStream(w, r, archiveURL, service.ChannelUpstreamOptions(info.Channel))
+}
+
+// proxyCatchupStream copies the archive stream from upstream to the client,
+// connecting through the source's proxy and sending the stream's headers.
+func (h *RelayStreamHandler) proxyCatchupStream(w http.ResponseWriter, r *http.Request, archiveURL string, opts relay.UpstreamOptions) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, archiveURL, nil)
+	if err != nil {
+		http.Error(w, "invalid catch-up URL", http.StatusInternalServerError)
+		return
+	}
+	for _, name := range catchupForwardHeaders {
+		if v := r.Header.Get(name); v != "" {
+			req.Header.Set(name, v)
+		}
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", "tvarr/"+version.Version)
+	}
+
+	resp, err := h.relayService.GetHTTPClient(opts).Do(req)
+	if err != nil {
+		h.logger.Warn("Catch-up upstream request failed", "error", err)
+		http.Error(w, "catch-up upstream unavailable", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		h.logger.Warn("Catch-up upstream returned error", "status", resp.StatusCode)
+		http.Error(w, "catch-up upstream error: "+resp.Status, http.StatusBadGateway)
+		return
+	}
+
+	for _, name := range catchupResponseHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			w.Header().Set(name, v)
+		}
+	}
+	setCORSHeaders(w)
+	setStreamHeaders(w, "smart", "proxy")
+	w.WriteHeader(resp.StatusCode)
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		h.logger.Debug("Catch-up stream ended", "error", err)
+	}
+}
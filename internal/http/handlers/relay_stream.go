@@ -2,16 +2,19 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
+	"github.com/jmylchreest/tvarr/internal/auth"
 	"github.com/jmylchreest/tvarr/internal/ffmpeg"
 	"github.com/jmylchreest/tvarr/internal/models"
 	"github.com/jmylchreest/tvarr/internal/relay"
@@ -62,6 +65,12 @@ func setCORSHeaders(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Range")
 }
 
+// needsUpstreamHeaders reports whether a channel's stream requires request
+// headers, which a redirect cannot pass on to the client.
+func needsUpstreamHeaders(channel *models.Channel) bool {
+	return len(channel.RequestHeaders()) > 0
+}
+
 // Register registers the relay stream routes with the API (Huma routes).
 // Note: The /proxy/{proxyId}/{channelId} streaming endpoints are registered
 // via RegisterChiRoutes for raw HTTP handler access (needed for 302 redirects, CORS).
@@ -130,10 +139,22 @@ func (h *RelayStreamHandler) RegisterChiRoutes(router chi.Router) {
 	router.Get("/proxy/{proxyId}/{channelId}", h.handleRawStream)
 	router.Options("/proxy/{proxyId}/{channelId}", h.handleRawStreamOptions)
 
+	// Catch-up archive playback: /proxy/{proxyId}/{channelId}/catchup?start=&duration=
+	router.Get("/proxy/{proxyId}/{channelId}/catchup", h.handleCatchup)
+	router.Options("/proxy/{proxyId}/{channelId}/catchup", h.handleRawStreamOptions)
+
+	// CORS preflight for channel previews carries no credentials
+	router.Options("/proxy/{channelId}", h.handleRawStreamOptions)
+}
+
+// RegisterPreviewRoutes registers the admin channel preview route. Register it on
+// the admin API router so that previews need an admin login when API
+// authentication is enabled; otherwise, with stream authentication enabled, they
+// need a viewer token.
+func (h *RelayStreamHandler) RegisterPreviewRoutes(router chi.Router) {
 	// Channel preview streaming: /proxy/{channelId}
 	// Uses zero-transcode smart delivery (passthrough/repackage only)
 	router.Get("/proxy/{channelId}", h.handleChannelPreview)
-	router.Options("/proxy/{channelId}", h.handleRawStreamOptions)
 }
 
 // proxyStreamDocsHandler is a no-op handler for documentation-only registrations.
@@ -199,6 +220,7 @@ func (h *RelayStreamHandler) registerProxyStreamDocs(api huma.API) {
 				},
 			},
 			"400": {Description: "Invalid proxy or channel ID format"},
+			"401": {Description: "Missing or invalid viewer credential (stream authentication enabled)"},
 			"404": {Description: "Stream proxy or channel not found"},
 			"500": {Description: "Internal server error"},
 			"502": {Description: "Upstream server error"},
@@ -225,6 +247,29 @@ func (h *RelayStreamHandler) registerProxyStreamDocs(api huma.API) {
 			},
 		},
 	}, h.proxyStreamOptionsDocsHandler)
+
+	// GET /proxy/{proxyId}/{channelId}/catchup - Play a programme from the catch-up archive
+	huma.Register(api, huma.Operation{
+		OperationID: "streamChannelCatchup",
+		Method:      "GET",
+		Path:        "/proxy/{proxyId}/{channelId}/catchup",
+		Summary:     "Stream a programme from the catch-up archive",
+		Description: `Plays a past programme from the source's catch-up archive. Generated playlists
+advertise this endpoint in the catchup-source attribute for channels with an archive.
+
+In direct mode the client is redirected to the source's archive URL; in smart mode the
+archive stream is proxied through tvarr.`,
+		Tags: []string{"Stream Proxy"},
+		Responses: map[string]*huma.Response{
+			"200": {Description: "Archive stream content (smart mode)"},
+			"302": {Description: "Redirect to the source archive URL (direct mode)"},
+			"400": {Description: "Invalid IDs, start or duration, or programme outside the catch-up window"},
+			"401": {Description: "Missing or invalid viewer credential (stream authentication enabled)"},
+			"404": {Description: "Stream proxy or channel not found, or channel has no catch-up archive"},
+			"502": {Description: "Upstream server error"},
+		},
+		SkipValidateBody: true,
+	}, h.catchupDocsHandler)
 }
 
 // handleRawStreamOptions handles CORS preflight requests for the stream endpoint.
@@ -278,50 +323,81 @@ func (h *RelayStreamHandler) handleRawStream(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ctx := r.Context()
+	streamInfo, ok := h.resolveStreamInfo(w, r)
+	if !ok {
+		return
+	}
+
+	// Dispatch based on proxy mode
+	switch streamInfo.Proxy.ProxyMode {
+	case models.StreamProxyModeDirect:
+		if needsUpstreamHeaders(streamInfo.Channel) {
+			h.logger.Debug("Direct mode: stream requires request headers, serving through smart mode",
+				"proxy_id", streamInfo.Proxy.ID,
+				"channel_id", streamInfo.Channel.ID,
+			)
+			h.handleRawSmartMode(w, r, streamInfo)
+			return
+		}
+		h.handleRawDirectMode(w, r, streamInfo)
 
+	case models.StreamProxyModeSmart:
+		h.handleRawSmartMode(w, r, streamInfo)
+
+	default:
+		h.logger.Error("Unknown proxy mode",
+			"proxy_id", streamInfo.Proxy.ID,
+			"mode", streamInfo.Proxy.ProxyMode,
+		)
+		http.Error(w, fmt.Sprintf("unknown proxy mode: %s (valid modes: direct, smart)", streamInfo.Proxy.ProxyMode), http.StatusInternalServerError)
+	}
+}
+
+// resolveStreamInfo parses the proxy and channel IDs from the route, authenticates
+// the viewer credential and loads the stream info.
+// It writes an error response and returns false if the stream cannot be served.
+func (h *RelayStreamHandler) resolveStreamInfo(w http.ResponseWriter, r *http.Request) (*service.StreamInfo, bool) {
 	proxyIDStr := chi.URLParam(r, "proxyId")
 	channelIDStr := chi.URLParam(r, "channelId")
 
 	proxyID, err := models.ParseULID(proxyIDStr)
 	if err != nil {
 		http.Error(w, "invalid proxy ID format", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	channelID, err := models.ParseULID(channelIDStr)
 	if err != nil {
 		http.Error(w, "invalid channel ID format", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	// Get stream info (proxy, channel, optional profile)
-	streamInfo, err := h.relayService.GetStreamInfo(ctx, proxyID, channelID)
+	credential := r.URL.Query().Get(auth.StreamTokenParam)
+	streamInfo, err := h.relayService.GetStreamInfo(r.Context(), proxyID, channelID, credential)
 	if err != nil {
+		if errors.Is(err, service.ErrStreamUnauthorized) {
+			h.logger.Debug("Rejected unauthenticated stream request",
+				"proxy_id", proxyIDStr,
+				"channel_id", channelIDStr,
+				"remote_addr", r.RemoteAddr,
+			)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return nil, false
+		}
+		if errors.Is(err, service.ErrStreamUnavailable) {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return nil, false
+		}
 		h.logger.Error("Failed to get stream info",
 			"proxy_id", proxyIDStr,
 			"channel_id", channelIDStr,
 			"error", err,
 		)
 		http.Error(w, fmt.Sprintf("stream not found: %v", err), http.StatusNotFound)
-		return
-	}
-
-	// Dispatch based on proxy mode
-	switch streamInfo.Proxy.ProxyMode {
-	case models.StreamProxyModeDirect:
-		h.handleRawDirectMode(w, r, streamInfo)
-
-	case models.StreamProxyModeSmart:
-		h.handleRawSmartMode(w, r, streamInfo)
-
-	default:
-		h.logger.Error("Unknown proxy mode",
-			"proxy_id", proxyIDStr,
-			"mode", streamInfo.Proxy.ProxyMode,
-		)
-		http.Error(w, fmt.Sprintf("unknown proxy mode: %s (valid modes: direct, smart)", streamInfo.Proxy.ProxyMode), http.StatusInternalServerError)
+		return nil, false
 	}
+	return streamInfo, true
 }
 
 // handleChannelPreview handles channel preview streaming at /proxy/{channelId}.
@@ -335,6 +411,24 @@ func (h *RelayStreamHandler) handleChannelPreview(w http.ResponseWriter, r *http
 
 	ctx := r.Context()
 
+	// Admins are authenticated by the router; anyone else needs a viewer token
+	// when stream authentication is enabled.
+	if _, ok := auth.PrincipalFromContext(ctx); !ok {
+		if err := h.relayService.AuthenticatePreview(ctx, r.URL.Query().Get(auth.StreamTokenParam)); err != nil {
+			if errors.Is(err, service.ErrStreamUnauthorized) {
+				h.logger.Debug("Rejected unauthenticated preview request",
+					"channel_id", chi.URLParam(r, "channelId"),
+					"remote_addr", r.RemoteAddr,
+				)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			h.logger.Error("Failed to authenticate preview request", "error", err)
+			http.Error(w, "authentication failed", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	channelIDStr := chi.URLParam(r, "channelId")
 
 	channelID, err := models.ParseULID(channelIDStr)
@@ -350,6 +444,10 @@ func (h *RelayStreamHandler) handleChannelPreview(w http.ResponseWriter, r *http
 			"channel_id", channelIDStr,
 			"error", err,
 		)
+		if errors.Is(err, service.ErrStreamUnavailable) {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
 		http.Error(w, fmt.Sprintf("channel not found: %v", err), http.StatusNotFound)
 		return
 	}
@@ -357,7 +455,7 @@ func (h *RelayStreamHandler) handleChannelPreview(w http.ResponseWriter, r *http
 	streamURL := channel.StreamURL
 
 	// Classify the source stream
-	classification := relay.ClassificationResult(h.relayService.ClassifyStream(ctx, streamURL))
+	classification := relay.ClassificationResult(h.relayService.ClassifyStream(ctx, streamURL, service.ChannelUpstreamOptions(channel)))
 
 	// Determine client's desired format from query param or Accept header
 	clientFormat := h.resolveClientFormat(r, classification)
@@ -473,9 +571,10 @@ func (h *RelayStreamHandler) handleRawDirectMode(w http.ResponseWriter, r *http.
 func (h *RelayStreamHandler) handleRawSmartMode(w http.ResponseWriter, r *http.Request, info *service.StreamInfo) {
 	ctx := r.Context()
 	streamURL := info.Channel.StreamURL
+	upstreamOpts := service.ChannelUpstreamOptions(info.Channel)
 
 	// Classify the source stream
-	classification := relay.ClassificationResult(h.relayService.ClassifyStream(ctx, streamURL))
+	classification := relay.ClassificationResult(h.relayService.ClassifyStream(ctx, streamURL, upstreamOpts))
 
 	// Detect client capabilities (codecs, formats)
 	clientCaps := h.detectClientCapabilities(r)
@@ -501,7 +600,7 @@ func (h *RelayStreamHandler) handleRawSmartMode(w http.ResponseWriter, r *http.R
 	// Uses intelligent probing that respects connection limits and reuses session data
 	var sourceCodecs []string
 	var sourceVideoCodec, sourceAudioCodec string
-	if codecInfo := h.relayService.GetOrProbeCodecInfo(ctx, info.Channel.ID, streamURL); codecInfo != nil {
+	if codecInfo := h.relayService.GetOrProbeCodecInfo(ctx, info.Channel.ID, streamURL, upstreamOpts); codecInfo != nil {
 		sourceVideoCodec = codecInfo.VideoCodec
 		sourceAudioCodec = codecInfo.AudioCodec
 		if sourceVideoCodec != "" {
@@ -560,6 +659,9 @@ func (h *RelayStreamHandler) handleRawSmartMode(w http.ResponseWriter, r *http.R
 	// This determines what codecs to transcode TO (if transcoding is needed)
 	targetVariant := h.computeTargetVariant(info, clientCaps, sourceVideoCodec, sourceAudioCodec)
 
+	// Identify the client so its viewing is recorded when it leaves the session
+	r = r.WithContext(relay.WithViewingInfo(ctx, newViewingInfo(r, info, clientCaps, routingResult.Decision)))
+
 	// Dispatch based on routing decision
 	switch routingResult.Decision {
 	case relay.RoutePassthrough:
@@ -578,6 +680,65 @@ func (h *RelayStreamHandler) handleRawSmartMode(w http.ResponseWriter, r *http.R
 	}
 }
 
+// newViewingInfo describes the client of a proxy stream request for viewing analytics.
+func newViewingInfo(r *http.Request, info *service.StreamInfo, caps relay.ClientCapabilities, decision relay.RoutingDecision) *relay.ViewingInfo {
+	viewing := &relay.ViewingInfo{
+		ClientIP:   requestClientIP(r),
+		ClientRule: caps.MatchedRuleName,
+		Route:      decision.String(),
+	}
+	if info.Viewer != nil {
+		viewing.ViewerID = &info.Viewer.ID
+		viewing.ViewerName = info.Viewer.Name
+	}
+	if info.Proxy != nil {
+		viewing.ProxyID = info.Proxy.ID
+		viewing.ProxyName = info.Proxy.Name
+	}
+	if decision == relay.RouteTranscode && info.EncodingProfile != nil {
+		viewing.ProfileName = info.EncodingProfile.Name
+	}
+	return viewing
+}
+
+// requestClientIP returns the client's IP address, preferring the first
+// X-Forwarded-For entry set by a reverse proxy.
+func requestClientIP(r *http.Request) string {
+	remoteAddr := r.RemoteAddr
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		remoteAddr = strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+	// Strip port from remote address (e.g., "192.168.1.100:54321" -> "192.168.1.100")
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// countingResponseWriter counts the bytes written to a response.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	written uint64
+}
+
+func (cw *countingResponseWriter) Write(b []byte) (int, error) {
+	n, err := cw.ResponseWriter.Write(b)
+	cw.written += uint64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying writer does.
+func (cw *countingResponseWriter) Flush() {
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (cw *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
 // resolveClientFormat determines the client's desired output format.
 // It checks the ?format= query parameter first, then uses client detection
 // based on User-Agent, Accept headers, and X-Tvarr-Player header.
@@ -877,8 +1038,15 @@ func (h *RelayStreamHandler) handleSmartTranscode(w http.ResponseWriter, r *http
 	// and initialize multi-format output before FFmpeg starts to ensure segments are captured.
 	needsMultiFormat := clientFormat == relay.FormatValueHLS || clientFormat == relay.FormatValueDASH
 
-	// Start or join the relay session with the encoding profile
-	session, err := h.relayService.StartRelayWithProfile(ctx, info.Channel.ID, profile)
+	// Start or join the relay session with the encoding profile. Proxy streams can
+	// fail over to the same channel in the proxy's other sources.
+	var session *relay.RelaySession
+	var err error
+	if info.Proxy != nil {
+		session, err = h.relayService.StartProxyRelayWithProfile(ctx, info.Proxy.ID, info.Channel.ID, profile)
+	} else {
+		session, err = h.relayService.StartRelayWithProfile(ctx, info.Channel.ID, profile)
+	}
 	if err != nil {
 		errAttrs := []any{
 			"channel_id", info.Channel.ID,
@@ -887,6 +1055,11 @@ func (h *RelayStreamHandler) handleSmartTranscode(w http.ResponseWriter, r *http
 		if info.Proxy != nil {
 			errAttrs = append([]any{"proxy_id", info.Proxy.ID}, errAttrs...)
 		}
+		if errors.Is(err, relay.ErrConnectionLimitReached) {
+			h.logger.Warn("Refused relay session at source connection limit", errAttrs...)
+			http.Error(w, "source connection limit reached", http.StatusServiceUnavailable)
+			return
+		}
 		h.logger.Error("Failed to start relay session for smart transcode", errAttrs...)
 		http.Error(w, "failed to start relay session", http.StatusInternalServerError)
 		return
@@ -937,15 +1110,7 @@ func (h *RelayStreamHandler) handleMultiFormatOutput(w http.ResponseWriter, r *h
 	// Generate a client ID for tracking HLS/DASH clients
 	// Use IP (without port) + User-Agent hash to identify unique clients
 	// This handles multiple TCP connections from same client (e.g., mpv parallel segment fetches)
-	remoteAddr := r.RemoteAddr
-	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
-		remoteAddr = strings.Split(fwd, ",")[0]
-	}
-	// Strip port from remote address (e.g., "192.168.1.100:54321" -> "192.168.1.100")
-	clientIP := remoteAddr
-	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
-		clientIP = host
-	}
+	clientIP := requestClientIP(r)
 	// Create a short hash of User-Agent to distinguish different clients from same IP
 	// (e.g., multiple browser tabs, different players)
 	userAgent := r.UserAgent()
@@ -995,6 +1160,19 @@ func (h *RelayStreamHandler) handleMultiFormatOutput(w http.ResponseWriter, r *h
 	// and create the processor on-demand
 	var handler relay.OutputHandler
 
+	// Segments are served per request, so count what each request sends
+	// towards the client's total
+	var tracked interface {
+		UpdateClientBytes(clientID string, bytes uint64)
+	}
+	cw := &countingResponseWriter{ResponseWriter: w}
+	w = cw
+	defer func() {
+		if tracked != nil && cw.written > 0 {
+			tracked.UpdateClientBytes(clientID, cw.written)
+		}
+	}()
+
 	// Determine effective format with priority:
 	// 1. URL query param override (formatOverride)
 	// 2. Specific HLS sub-format from client detection (preferredFormat like "hls-fmp4")
@@ -1030,6 +1208,7 @@ func (h *RelayStreamHandler) handleMultiFormatOutput(w http.ResponseWriter, r *h
 		}
 		// Register client for tracking (will update existing or create new)
 		_ = processor.RegisterClient(clientID, w, r)
+		tracked = processor
 		handler = relay.NewHLSHandlerWithVariant(processor, clientVariant.String())
 
 	case relay.FormatValueFMP4, relay.FormatValueHLSFMP4:
@@ -1046,6 +1225,7 @@ func (h *RelayStreamHandler) handleMultiFormatOutput(w http.ResponseWriter, r *h
 		}
 		// Register client for tracking (will update existing or create new)
 		_ = fmp4Processor.RegisterClient(clientID, w, r)
+		tracked = fmp4Processor
 		handler = relay.NewHLSHandlerWithVariant(fmp4Processor, clientVariant.String())
 
 		// For init segment requests, call processor directly to enable client tracking
@@ -1073,6 +1253,7 @@ func (h *RelayStreamHandler) handleMultiFormatOutput(w http.ResponseWriter, r *h
 		}
 		// Register client for tracking (will update existing or create new)
 		_ = processor.RegisterClient(clientID, w, r)
+		tracked = processor
 		handler = relay.NewDASHHandler(processor)
 
 	default:
@@ -1089,6 +1270,7 @@ func (h *RelayStreamHandler) handleMultiFormatOutput(w http.ResponseWriter, r *h
 		}
 		// Register client for tracking (will update existing or create new)
 		_ = processor.RegisterClient(clientID, w, r)
+		tracked = processor
 		handler = relay.NewHLSHandlerWithVariant(processor, clientVariant.String())
 	}
 
@@ -1171,6 +1353,7 @@ func (h *RelayStreamHandler) handleMultiFormatOutput(w http.ResponseWriter, r *h
 }
 
 // buildBaseURL constructs the base URL for playlist segment references.
+// The result may carry a query string; segment parameters are appended after it.
 func (h *RelayStreamHandler) buildBaseURL(r *http.Request) string {
 	scheme := "http"
 	if r.TLS != nil {
@@ -1187,7 +1370,13 @@ func (h *RelayStreamHandler) buildBaseURL(r *http.Request) string {
 		host = fwdHost
 	}
 
-	return fmt.Sprintf("%s://%s%s", scheme, host, r.URL.Path)
+	baseURL := fmt.Sprintf("%s://%s%s", scheme, host, r.URL.Path)
+
+	// Carry the viewer credential into segment URLs so they are authorised too.
+	if credential := r.URL.Query().Get(auth.StreamTokenParam); credential != "" {
+		baseURL += "?" + auth.StreamTokenParam + "=" + url.QueryEscape(credential)
+	}
+	return baseURL
 }
 
 // streamMPEGTSFromRelay streams MPEG-TS data directly from a relay session.
@@ -1294,6 +1483,7 @@ type ProbeStreamOutput struct {
 // Returns full track information including all discovered video, audio, and subtitle tracks.
 func (h *RelayStreamHandler) ProbeStream(ctx context.Context, input *ProbeStreamInput) (*ProbeStreamOutput, error) {
 	var streamURL string
+	var upstreamOpts relay.UpstreamOptions
 	var channelIDStr string
 
 	// Determine the stream URL - either from direct URL or channel lookup
@@ -1310,6 +1500,7 @@ func (h *RelayStreamHandler) ProbeStream(ctx context.Context, input *ProbeStream
 		}
 
 		streamURL = channel.StreamURL
+		upstreamOpts = service.ChannelUpstreamOptions(channel)
 		channelIDStr = input.Body.ChannelID
 	} else if input.Body.URL != "" {
 		streamURL = input.Body.URL
@@ -1318,7 +1509,7 @@ func (h *RelayStreamHandler) ProbeStream(ctx context.Context, input *ProbeStream
 	}
 
 	// Use ProbeStreamFull to get all track information
-	streamInfo, err := h.relayService.ProbeStreamFull(ctx, streamURL)
+	streamInfo, err := h.relayService.ProbeStreamFull(ctx, streamURL, upstreamOpts)
 	if err != nil {
 		return nil, huma.Error500InternalServerError("failed to probe stream", err)
 	}
@@ -1326,7 +1517,7 @@ func (h *RelayStreamHandler) ProbeStream(ctx context.Context, input *ProbeStream
 	// Also cache the result (ProbeStreamFull doesn't cache, so call ProbeStream for caching)
 	// This is done asynchronously to not delay the response
 	go func() {
-		_, _ = h.relayService.ProbeStream(context.Background(), streamURL)
+		_, _ = h.relayService.ProbeStream(context.Background(), streamURL, upstreamOpts)
 	}()
 
 	return &ProbeStreamOutput{
@@ -1401,7 +1592,7 @@ type ClassifyStreamOutput struct {
 
 // ClassifyStream classifies a stream URL to determine processing mode.
 func (h *RelayStreamHandler) ClassifyStream(ctx context.Context, input *ClassifyStreamInput) (*ClassifyStreamOutput, error) {
-	result := h.relayService.ClassifyStream(ctx, input.Body.URL)
+	result := h.relayService.ClassifyStream(ctx, input.Body.URL, relay.UpstreamOptions{})
 
 	return &ClassifyStreamOutput{
 		Body: struct {
@@ -1501,6 +1692,7 @@ func (h *RelayStreamHandler) ClearCodecCache(ctx context.Context, input *ClearCo
 type StreamChannelByProxyInput struct {
 	ProxyID   string `path:"proxyId" doc:"Stream Proxy ID (ULID)"`
 	ChannelID string `path:"channelId" doc:"Channel ID (ULID)"`
+	Token     string `query:"token" doc:"Viewer token or signed stream token (required when stream authentication is enabled)"`
 }
 
 // StreamChannelByProxyOptionsInput is the input for CORS preflight requests.
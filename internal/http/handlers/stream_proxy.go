@@ -20,6 +20,7 @@ type StreamProxyHandler struct {
 	baseURL        string
 	logger         *slog.Logger
 	scheduleSyncer ScheduleSyncer
+	audit          auditTrail
 }
 
 // buildOrderMapFromIDs creates an order map from array indices.
@@ -80,6 +81,12 @@ func (h *StreamProxyHandler) WithScheduleSyncer(syncer ScheduleSyncer) *StreamPr
 	return h
 }
 
+// WithAuditRecorder sets the recorder for stream proxy changes.
+func (h *StreamProxyHandler) WithAuditRecorder(recorder AuditRecorder) *StreamProxyHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // syncSchedules triggers an immediate sync if a syncer is configured.
 func (h *StreamProxyHandler) syncSchedules(ctx context.Context) {
 	if h.scheduleSyncer != nil {
@@ -235,8 +242,10 @@ func (h *StreamProxyHandler) Create(ctx context.Context, input *CreateStreamProx
 	proxy := input.Body.ToModel()
 
 	if err := h.proxyService.Create(ctx, proxy); err != nil {
-		return nil, huma.Error500InternalServerError("failed to create proxy", err)
+		return nil, proxyServiceError("failed to create proxy", err)
 	}
+	// Recorded on return, so the event includes the source and filter assignments.
+	defer h.audit.record(ctx, models.AuditEntityStreamProxy, models.AuditActionCreate, proxy.ID, nil)
 
 	// Set sources if provided (order derived from array index)
 	if len(input.Body.SourceIDs) > 0 {
@@ -304,10 +313,14 @@ func (h *StreamProxyHandler) Update(ctx context.Context, input *UpdateStreamProx
 		return nil, huma.Error500InternalServerError("failed to get proxy", err)
 	}
 
+	// Recorded on return, so the event includes the source and filter assignments.
+	before := h.audit.capture(ctx, models.AuditEntityStreamProxy, id)
+	defer h.audit.record(ctx, models.AuditEntityStreamProxy, models.AuditActionUpdate, id, before)
+
 	input.Body.ApplyToModel(proxy)
 
 	if err := h.proxyService.Update(ctx, proxy); err != nil {
-		return nil, huma.Error500InternalServerError("failed to update proxy", err)
+		return nil, proxyServiceError("failed to update proxy", err)
 	}
 
 	// Set sources if provided (order derived from array index)
@@ -363,12 +376,14 @@ func (h *StreamProxyHandler) Delete(ctx context.Context, input *DeleteStreamProx
 		return nil, huma.Error400BadRequest("invalid ID format", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityStreamProxy, id)
 	if err := h.proxyService.Delete(ctx, id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, huma.Error404NotFound(fmt.Sprintf("stream proxy %s not found", input.ID))
 		}
 		return nil, huma.Error500InternalServerError("failed to delete proxy", err)
 	}
+	h.audit.record(ctx, models.AuditEntityStreamProxy, models.AuditActionDelete, id, before)
 
 	// Trigger immediate schedule sync (removed proxy's schedule needs cleanup)
 	h.syncSchedules(ctx)
@@ -396,9 +411,11 @@ func (h *StreamProxyHandler) SetSources(ctx context.Context, input *SetProxySour
 		return nil, huma.Error400BadRequest("invalid ID format", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityStreamProxy, id)
 	if err := h.proxyService.SetSources(ctx, id, input.Body.SourceIDs, input.Body.Priorities); err != nil {
 		return nil, huma.Error500InternalServerError("failed to set sources", err)
 	}
+	h.audit.record(ctx, models.AuditEntityStreamProxy, models.AuditActionUpdate, id, before)
 
 	return &SetProxySourcesOutput{
 		Body: struct {
@@ -429,9 +446,11 @@ func (h *StreamProxyHandler) SetEpgSources(ctx context.Context, input *SetProxyE
 		return nil, huma.Error400BadRequest("invalid ID format", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityStreamProxy, id)
 	if err := h.proxyService.SetEpgSources(ctx, id, input.Body.EpgSourceIDs, input.Body.Priorities); err != nil {
 		return nil, huma.Error500InternalServerError("failed to set EPG sources", err)
 	}
+	h.audit.record(ctx, models.AuditEntityStreamProxy, models.AuditActionUpdate, id, before)
 
 	return &SetProxyEpgSourcesOutput{
 		Body: struct {
@@ -510,3 +529,12 @@ func (h *StreamProxyHandler) Generate(ctx context.Context, input *GenerateProxyI
 		},
 	}, nil
 }
+
+// proxyServiceError maps proxy service errors to HTTP errors.
+func proxyServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	if errors.As(err, &ve) {
+		return huma.Error400BadRequest(ve.Error())
+	}
+	return huma.Error500InternalServerError(msg, err)
+}
@@ -36,6 +36,7 @@ type StreamSourceHandler struct {
 	sourceService     *service.SourceService
 	scheduleSyncer    ScheduleSyncer
 	proxyUsageChecker ProxyUsageChecker
+	audit             auditTrail
 }
 
 // NewStreamSourceHandler creates a new stream source handler.
@@ -57,6 +58,12 @@ func (h *StreamSourceHandler) WithProxyUsageChecker(checker ProxyUsageChecker) *
 	return h
 }
 
+// WithAuditRecorder sets the recorder for stream source changes.
+func (h *StreamSourceHandler) WithAuditRecorder(recorder AuditRecorder) *StreamSourceHandler {
+	h.audit = auditTrail{recorder: recorder}
+	return h
+}
+
 // syncSchedules triggers an immediate sync if a syncer is configured.
 func (h *StreamSourceHandler) syncSchedules(ctx context.Context) {
 	if h.scheduleSyncer != nil {
@@ -200,7 +207,13 @@ func (h *StreamSourceHandler) Create(ctx context.Context, input *CreateStreamSou
 			errors.Is(err, models.ErrURLRequired) ||
 			errors.Is(err, models.ErrInvalidURL) ||
 			errors.Is(err, models.ErrInvalidSourceType) ||
-			errors.Is(err, models.ErrXtreamCredentialsRequired) {
+			errors.Is(err, models.ErrXtreamCredentialsRequired) ||
+			errors.Is(err, models.ErrMacAddressRequired) ||
+			errors.Is(err, models.ErrInvalidMacAddress) ||
+			errors.Is(err, models.ErrInvalidProxyURL) ||
+			errors.Is(err, models.ErrInvalidCustomHeaders) ||
+			errors.Is(err, models.ErrInvalidExtraAccounts) ||
+			errors.Is(err, models.ErrInvalidConnectionLimitPolicy) {
 			return nil, huma.Error400BadRequest(err.Error())
 		}
 		// Check for unique constraint violation (duplicate name)
@@ -210,6 +223,7 @@ func (h *StreamSourceHandler) Create(ctx context.Context, input *CreateStreamSou
 		}
 		return nil, huma.Error500InternalServerError("failed to create source", err)
 	}
+	h.audit.record(ctx, models.AuditEntityStreamSource, models.AuditActionCreate, source.ID, nil)
 
 	// Trigger immediate schedule sync if source has a cron schedule
 	if source.CronSchedule != "" {
@@ -247,11 +261,13 @@ func (h *StreamSourceHandler) Update(ctx context.Context, input *UpdateStreamSou
 		return nil, huma.Error500InternalServerError("failed to get source", err)
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityStreamSource, id)
 	input.Body.ApplyToModel(source)
 
 	if err := h.sourceService.Update(ctx, source); err != nil {
 		return nil, huma.Error500InternalServerError("failed to update source", err)
 	}
+	h.audit.record(ctx, models.AuditEntityStreamSource, models.AuditActionUpdate, id, before)
 
 	// Trigger immediate schedule sync (schedule may have changed)
 	h.syncSchedules(ctx)
@@ -289,12 +305,14 @@ func (h *StreamSourceHandler) Delete(ctx context.Context, input *DeleteStreamSou
 		}
 	}
 
+	before := h.audit.capture(ctx, models.AuditEntityStreamSource, id)
 	if err := h.sourceService.Delete(ctx, id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, huma.Error404NotFound(fmt.Sprintf("stream source %s not found", input.ID))
 		}
 		return nil, huma.Error500InternalServerError("failed to delete source", err)
 	}
+	h.audit.record(ctx, models.AuditEntityStreamSource, models.AuditActionDelete, id, before)
 
 	// Trigger immediate schedule sync (removed source's schedule needs cleanup)
 	h.syncSchedules(ctx)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/chi/v5"
+	"github.com/jmylchreest/tvarr/internal/auth"
 	"github.com/jmylchreest/tvarr/internal/models"
 	"github.com/jmylchreest/tvarr/internal/service"
 )
@@ -35,6 +36,8 @@ func (h *ThemeHandler) Register(api huma.API) {
 		Summary:     "List all themes",
 		Description: "Returns all available themes (built-in and custom)",
 		Tags:        []string{"Themes"},
+		// Themes are needed to style the login page, so they are always public.
+		Metadata: map[string]any{auth.OperationPublicMetadata: true},
 	}, h.ListThemes)
 }
 
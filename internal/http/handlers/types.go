@@ -30,7 +30,11 @@ type StreamSourceResponse struct {
 	Type                 models.SourceType   `json:"type"`
 	URL                  string              `json:"url"`
 	Username             string              `json:"username,omitempty"`
+	ExtraAccounts        []ExtraAccountInfo  `json:"extra_accounts,omitempty"`
+	MacAddress           string              `json:"mac_address,omitempty"`
 	UserAgent            string              `json:"user_agent,omitempty"`
+	ProxyURL             string              `json:"proxy_url,omitempty"`
+	CustomHeaders        map[string]string   `json:"custom_headers,omitempty"`
 	Enabled              bool                `json:"enabled"`
 	Priority             int                 `json:"priority"`
 	MaxConcurrentStreams int                 `json:"max_concurrent_streams"`
@@ -38,12 +42,85 @@ type StreamSourceResponse struct {
 	LastIngestionAt      *time.Time          `json:"last_ingestion_at,omitempty"`
 	LastError            string              `json:"last_error,omitempty"`
 	ChannelCount         int                 `json:"channel_count"`
+	IngestVod            bool                `json:"ingest_vod"`
+	IngestSeries         bool                `json:"ingest_series"`
+	VodCount             int                 `json:"vod_count"`
+	SeriesCount          int                 `json:"series_count"`
 	CronSchedule         string              `json:"cron_schedule,omitempty"`
 	NextScheduledUpdate  *time.Time          `json:"next_scheduled_update,omitempty"`
+	Account              *AccountResponse    `json:"account,omitempty" doc:"Account status last reported by an Xtream server"`
+
+	ConnectionLimitPolicy models.ConnectionLimitPolicy `json:"connection_limit_policy,omitempty"`
+}
+
+// ExtraAccountInfo represents an extra account of an Xtream source. Passwords
+// are never returned.
+type ExtraAccountInfo struct {
+	Username             string `json:"username"`
+	MaxConcurrentStreams int    `json:"max_concurrent_streams"`
+}
+
+// ExtraAccountRequest is an extra account of an Xtream source in requests.
+type ExtraAccountRequest struct {
+	Username             string `json:"username" doc:"Username of the account" minLength:"1" maxLength:"255"`
+	Password             string `json:"password,omitempty" doc:"Password of the account (on update, empty keeps the current password)" maxLength:"255"`
+	MaxConcurrentStreams int    `json:"max_concurrent_streams" doc:"Max concurrent streams with this account (0 = unlimited)" minimum:"0"`
+}
+
+// extraAccountsToModel converts extra account requests to model accounts. An
+// empty password keeps the password of the current account with that username.
+func extraAccountsToModel(requests []ExtraAccountRequest, current []models.XtreamAccount) []models.XtreamAccount {
+	passwords := make(map[string]string, len(current))
+	for _, account := range current {
+		passwords[account.Username] = account.Password
+	}
+	accounts := make([]models.XtreamAccount, 0, len(requests))
+	for _, r := range requests {
+		password := r.Password
+		if password == "" {
+			password = passwords[r.Username]
+		}
+		accounts = append(accounts, models.XtreamAccount{
+			Username:             r.Username,
+			Password:             password,
+			MaxConcurrentStreams: r.MaxConcurrentStreams,
+		})
+	}
+	return accounts
+}
+
+// AccountResponse represents the account status of an Xtream source.
+type AccountResponse struct {
+	Status            string     `json:"status,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	MaxConnections    int        `json:"max_connections"`
+	ActiveConnections int        `json:"active_connections"`
+	CheckedAt         time.Time  `json:"checked_at"`
 }
 
 // StreamSourceFromModel converts a model to a response.
 func StreamSourceFromModel(s *models.StreamSource) StreamSourceResponse {
+	var extraAccounts []ExtraAccountInfo
+	if accounts := s.Accounts(); len(accounts) > 1 {
+		for _, extra := range accounts[1:] {
+			extraAccounts = append(extraAccounts, ExtraAccountInfo{
+				Username:             extra.Username,
+				MaxConcurrentStreams: extra.MaxConcurrentStreams,
+			})
+		}
+	}
+
+	var account *AccountResponse
+	if s.AccountCheckedAt != nil {
+		account = &AccountResponse{
+			Status:            s.AccountStatus,
+			ExpiresAt:         s.AccountExpiresAt,
+			MaxConnections:    s.AccountMaxConnections,
+			ActiveConnections: s.AccountActiveConnections,
+			CheckedAt:         *s.AccountCheckedAt,
+		}
+	}
+
 	return StreamSourceResponse{
 		ID:                   s.ID,
 		CreatedAt:            s.CreatedAt,
@@ -52,7 +129,11 @@ func StreamSourceFromModel(s *models.StreamSource) StreamSourceResponse {
 		Type:                 s.Type,
 		URL:                  s.URL,
 		Username:             s.Username,
+		ExtraAccounts:        extraAccounts,
+		MacAddress:           s.MacAddress,
 		UserAgent:            s.UserAgent,
+		ProxyURL:             s.ProxyURL,
+		CustomHeaders:        s.Headers(),
 		Enabled:              models.BoolVal(s.Enabled),
 		Priority:             s.Priority,
 		MaxConcurrentStreams: s.MaxConcurrentStreams,
@@ -60,23 +141,38 @@ func StreamSourceFromModel(s *models.StreamSource) StreamSourceResponse {
 		LastIngestionAt:      s.LastIngestionAt,
 		LastError:            s.LastError,
 		ChannelCount:         s.ChannelCount,
+		IngestVod:            s.IngestVod,
+		IngestSeries:         s.IngestSeries,
+		VodCount:             s.VodCount,
+		SeriesCount:          s.SeriesCount,
 		CronSchedule:         s.CronSchedule,
 		NextScheduledUpdate:  scheduler.CalculateNextRun(s.CronSchedule),
+		Account:              account,
+
+		ConnectionLimitPolicy: s.ConnectionLimitPolicy,
 	}
 }
 
 // CreateStreamSourceRequest is the request body for creating a stream source.
 type CreateStreamSourceRequest struct {
-	Name                 string            `json:"name" doc:"User-friendly name for the source" minLength:"1" maxLength:"255"`
-	Type                 models.SourceType `json:"type" doc:"Source type: m3u or xtream" enum:"m3u,xtream"`
-	URL                  string            `json:"url" doc:"M3U playlist URL or Xtream server URL" minLength:"1" maxLength:"2048"`
-	Username             string            `json:"username,omitempty" doc:"Username for Xtream authentication" maxLength:"255"`
-	Password             string            `json:"password,omitempty" doc:"Password for Xtream authentication" maxLength:"255"`
-	UserAgent            string            `json:"user_agent,omitempty" doc:"Custom User-Agent header" maxLength:"512"`
-	Enabled              *bool             `json:"enabled,omitempty" doc:"Whether the source is enabled (default: true)"`
-	Priority             *int              `json:"priority,omitempty" doc:"Priority for channel merging (higher = preferred)"`
-	MaxConcurrentStreams *int              `json:"max_concurrent_streams,omitempty" doc:"Max concurrent streams from this source (0 = unlimited, default: 1)"`
-	CronSchedule         string            `json:"cron_schedule,omitempty" doc:"Cron schedule for automatic ingestion" maxLength:"100"`
+	Name                 string                `json:"name" doc:"User-friendly name for the source" minLength:"1" maxLength:"255"`
+	Type                 models.SourceType     `json:"type" doc:"Source type: m3u, xtream or stalker" enum:"m3u,xtream,stalker"`
+	URL                  string                `json:"url" doc:"M3U playlist URL, Xtream server URL or Stalker portal URL" minLength:"1" maxLength:"2048"`
+	Username             string                `json:"username,omitempty" doc:"Username for Xtream authentication" maxLength:"255"`
+	Password             string                `json:"password,omitempty" doc:"Password for Xtream authentication" maxLength:"255"`
+	ExtraAccounts        []ExtraAccountRequest `json:"extra_accounts,omitempty" doc:"Further accounts with the same Xtream provider, pooled with the primary account for concurrent streams"`
+	MacAddress           string                `json:"mac_address,omitempty" doc:"Device MAC address for Stalker authentication (e.g. 00:1A:79:12:34:56)" maxLength:"17"`
+	UserAgent            string                `json:"user_agent,omitempty" doc:"Custom User-Agent header" maxLength:"512"`
+	ProxyURL             string                `json:"proxy_url,omitempty" doc:"Upstream HTTP(S) or SOCKS5 proxy for all connections to this source (e.g. socks5://vpn:1080). Stream probes only support http:// proxies and are skipped with others" maxLength:"512"`
+	CustomHeaders        map[string]string     `json:"custom_headers,omitempty" doc:"Extra HTTP headers sent with every stream request (e.g. Referer, Origin, Cookie)"`
+	Enabled              *bool                 `json:"enabled,omitempty" doc:"Whether the source is enabled (default: true)"`
+	Priority             *int                  `json:"priority,omitempty" doc:"Priority for channel merging (higher = preferred)"`
+	MaxConcurrentStreams *int                  `json:"max_concurrent_streams,omitempty" doc:"Max concurrent streams from this source (0 = unlimited, default: 1)"`
+	IngestVod            *bool                 `json:"ingest_vod,omitempty" doc:"Ingest the movie catalogue (Xtream sources only, default: false)"`
+	IngestSeries         *bool                 `json:"ingest_series,omitempty" doc:"Ingest the series catalogue with episodes (Xtream sources only, default: false)"`
+	CronSchedule         string                `json:"cron_schedule,omitempty" doc:"Cron schedule for automatic ingestion" maxLength:"100"`
+
+	ConnectionLimitPolicy models.ConnectionLimitPolicy `json:"connection_limit_policy,omitempty" doc:"What a new relay session does when the source is at its limit: reject, preempt_idlest, preempt_lowest_priority or preempt_oldest (empty = leave it to the provider)"`
 }
 
 // ToModel converts the request to a model.
@@ -87,11 +183,16 @@ func (r *CreateStreamSourceRequest) ToModel() *models.StreamSource {
 		URL:                  r.URL,
 		Username:             r.Username,
 		Password:             r.Password,
+		MacAddress:           r.MacAddress,
 		UserAgent:            r.UserAgent,
+		ProxyURL:             r.ProxyURL,
+		CustomHeaders:        models.EncodeHeaders(r.CustomHeaders),
 		Enabled:              new(true),
 		Priority:             0,
 		MaxConcurrentStreams: 1, // Default: 1 concurrent stream
 		CronSchedule:         r.CronSchedule,
+
+		ConnectionLimitPolicy: r.ConnectionLimitPolicy,
 	}
 	if r.Enabled != nil {
 		source.Enabled = r.Enabled
@@ -102,21 +203,36 @@ func (r *CreateStreamSourceRequest) ToModel() *models.StreamSource {
 	if r.MaxConcurrentStreams != nil {
 		source.MaxConcurrentStreams = *r.MaxConcurrentStreams
 	}
+	if r.IngestVod != nil {
+		source.IngestVod = *r.IngestVod
+	}
+	if r.IngestSeries != nil {
+		source.IngestSeries = *r.IngestSeries
+	}
+	source.SetExtraAccounts(extraAccountsToModel(r.ExtraAccounts, nil))
 	return source
 }
 
 // UpdateStreamSourceRequest is the request body for updating a stream source.
 type UpdateStreamSourceRequest struct {
-	Name                 *string            `json:"name,omitempty" doc:"User-friendly name for the source" maxLength:"255"`
-	Type                 *models.SourceType `json:"type,omitempty" doc:"Source type: m3u or xtream" enum:"m3u,xtream"`
-	URL                  *string            `json:"url,omitempty" doc:"M3U playlist URL or Xtream server URL" maxLength:"2048"`
-	Username             *string            `json:"username,omitempty" doc:"Username for Xtream authentication" maxLength:"255"`
-	Password             *string            `json:"password,omitempty" doc:"Password for Xtream authentication" maxLength:"255"`
-	UserAgent            *string            `json:"user_agent,omitempty" doc:"Custom User-Agent header" maxLength:"512"`
-	Enabled              *bool              `json:"enabled,omitempty" doc:"Whether the source is enabled"`
-	Priority             *int               `json:"priority,omitempty" doc:"Priority for channel merging"`
-	MaxConcurrentStreams *int               `json:"max_concurrent_streams,omitempty" doc:"Max concurrent streams from this source (0 = unlimited)"`
-	CronSchedule         *string            `json:"cron_schedule,omitempty" doc:"Cron schedule for automatic ingestion" maxLength:"100"`
+	Name                 *string               `json:"name,omitempty" doc:"User-friendly name for the source" maxLength:"255"`
+	Type                 *models.SourceType    `json:"type,omitempty" doc:"Source type: m3u, xtream or stalker" enum:"m3u,xtream,stalker"`
+	URL                  *string               `json:"url,omitempty" doc:"M3U playlist URL, Xtream server URL or Stalker portal URL" maxLength:"2048"`
+	Username             *string               `json:"username,omitempty" doc:"Username for Xtream authentication" maxLength:"255"`
+	Password             *string               `json:"password,omitempty" doc:"Password for Xtream authentication" maxLength:"255"`
+	ExtraAccounts        []ExtraAccountRequest `json:"extra_accounts,omitempty" doc:"Further accounts with the same Xtream provider, replacing the current ones (empty array to clear)"`
+	MacAddress           *string               `json:"mac_address,omitempty" doc:"Device MAC address for Stalker authentication" maxLength:"17"`
+	UserAgent            *string               `json:"user_agent,omitempty" doc:"Custom User-Agent header" maxLength:"512"`
+	ProxyURL             *string               `json:"proxy_url,omitempty" doc:"Upstream HTTP(S) or SOCKS5 proxy for all connections to this source (empty to connect directly). Stream probes only support http:// proxies and are skipped with others" maxLength:"512"`
+	CustomHeaders        map[string]string     `json:"custom_headers,omitempty" doc:"Extra HTTP headers sent with every stream request (empty object to clear)"`
+	Enabled              *bool                 `json:"enabled,omitempty" doc:"Whether the source is enabled"`
+	Priority             *int                  `json:"priority,omitempty" doc:"Priority for channel merging"`
+	MaxConcurrentStreams *int                  `json:"max_concurrent_streams,omitempty" doc:"Max concurrent streams from this source (0 = unlimited)"`
+	IngestVod            *bool                 `json:"ingest_vod,omitempty" doc:"Ingest the movie catalogue (Xtream sources only)"`
+	IngestSeries         *bool                 `json:"ingest_series,omitempty" doc:"Ingest the series catalogue with episodes (Xtream sources only)"`
+	CronSchedule         *string               `json:"cron_schedule,omitempty" doc:"Cron schedule for automatic ingestion" maxLength:"100"`
+
+	ConnectionLimitPolicy *models.ConnectionLimitPolicy `json:"connection_limit_policy,omitempty" doc:"What a new relay session does when the source is at its limit: reject, preempt_idlest, preempt_lowest_priority or preempt_oldest (empty to leave it to the provider)"`
 }
 
 // ApplyToModel applies the update request to an existing model.
@@ -136,9 +252,21 @@ func (r *UpdateStreamSourceRequest) ApplyToModel(s *models.StreamSource) {
 	if r.Password != nil {
 		s.Password = *r.Password
 	}
+	if r.ExtraAccounts != nil {
+		s.SetExtraAccounts(extraAccountsToModel(r.ExtraAccounts, s.Accounts()))
+	}
+	if r.MacAddress != nil {
+		s.MacAddress = *r.MacAddress
+	}
 	if r.UserAgent != nil {
 		s.UserAgent = *r.UserAgent
 	}
+	if r.ProxyURL != nil {
+		s.ProxyURL = *r.ProxyURL
+	}
+	if r.CustomHeaders != nil {
+		s.SetHeaders(r.CustomHeaders)
+	}
 	if r.Enabled != nil {
 		s.Enabled = r.Enabled
 	}
@@ -148,6 +276,15 @@ func (r *UpdateStreamSourceRequest) ApplyToModel(s *models.StreamSource) {
 	if r.MaxConcurrentStreams != nil {
 		s.MaxConcurrentStreams = *r.MaxConcurrentStreams
 	}
+	if r.ConnectionLimitPolicy != nil {
+		s.ConnectionLimitPolicy = *r.ConnectionLimitPolicy
+	}
+	if r.IngestVod != nil {
+		s.IngestVod = *r.IngestVod
+	}
+	if r.IngestSeries != nil {
+		s.IngestSeries = *r.IngestSeries
+	}
 	if r.CronSchedule != nil {
 		s.CronSchedule = *r.CronSchedule
 	}
@@ -164,8 +301,10 @@ type EpgSourceResponse struct {
 	Type                models.EpgSourceType   `json:"type"`
 	URL                 string                 `json:"url"`
 	Username            string                 `json:"username,omitempty"`
+	MacAddress          string                 `json:"mac_address,omitempty"`
 	ApiMethod           models.XtreamApiMethod `json:"api_method,omitempty"`
 	UserAgent           string                 `json:"user_agent,omitempty"`
+	ProxyURL            string                 `json:"proxy_url,omitempty"`
 	DetectedTimezone    string                 `json:"detected_timezone,omitempty"`
 	EpgShift            int                    `json:"epg_shift"`
 	Enabled             bool                   `json:"enabled"`
@@ -189,8 +328,10 @@ func EpgSourceFromModel(s *models.EpgSource) EpgSourceResponse {
 		Type:                s.Type,
 		URL:                 s.URL,
 		Username:            s.Username,
+		MacAddress:          s.MacAddress,
 		ApiMethod:           s.ApiMethod,
 		UserAgent:           s.UserAgent,
+		ProxyURL:            s.ProxyURL,
 		DetectedTimezone:    s.DetectedTimezone,
 		EpgShift:            s.EpgShift,
 		Enabled:             models.BoolVal(s.Enabled),
@@ -208,12 +349,14 @@ func EpgSourceFromModel(s *models.EpgSource) EpgSourceResponse {
 // CreateEpgSourceRequest is the request body for creating an EPG source.
 type CreateEpgSourceRequest struct {
 	Name          string                 `json:"name" doc:"User-friendly name for the source" minLength:"1" maxLength:"255"`
-	Type          models.EpgSourceType   `json:"type" doc:"Source type: xmltv or xtream" enum:"xmltv,xtream"`
-	URL           string                 `json:"url" doc:"XMLTV URL or Xtream server URL" minLength:"1" maxLength:"2048"`
+	Type          models.EpgSourceType   `json:"type" doc:"Source type: xmltv, xtream or stalker" enum:"xmltv,xtream,stalker"`
+	URL           string                 `json:"url" doc:"XMLTV URL, Xtream server URL or Stalker portal URL" minLength:"1" maxLength:"2048"`
 	Username      string                 `json:"username,omitempty" doc:"Username for Xtream authentication" maxLength:"255"`
 	Password      string                 `json:"password,omitempty" doc:"Password for Xtream authentication" maxLength:"255"`
+	MacAddress    string                 `json:"mac_address,omitempty" doc:"Device MAC address for Stalker authentication (e.g. 00:1A:79:12:34:56)" maxLength:"17"`
 	ApiMethod     models.XtreamApiMethod `json:"api_method,omitempty" doc:"API method for Xtream sources: stream_id (richer data, ~6 days) or bulk_xmltv (faster, ~2 days)" enum:"stream_id,bulk_xmltv"`
 	UserAgent     string                 `json:"user_agent,omitempty" doc:"Custom User-Agent header" maxLength:"512"`
+	ProxyURL      string                 `json:"proxy_url,omitempty" doc:"Upstream HTTP(S) or SOCKS5 proxy for all connections to this source (e.g. socks5://vpn:1080)" maxLength:"512"`
 	EpgShift      *int                   `json:"epg_shift,omitempty" doc:"Manual time shift in hours to adjust EPG times (default: 0)" minimum:"-12" maximum:"12"`
 	Enabled       *bool                  `json:"enabled,omitempty" doc:"Whether the source is enabled (default: true)"`
 	Priority      *int                   `json:"priority,omitempty" doc:"Priority for program merging (higher = preferred)"`
@@ -229,8 +372,10 @@ func (r *CreateEpgSourceRequest) ToModel() *models.EpgSource {
 		URL:           r.URL,
 		Username:      r.Username,
 		Password:      r.Password,
+		MacAddress:    r.MacAddress,
 		ApiMethod:     r.ApiMethod,
 		UserAgent:     r.UserAgent,
+		ProxyURL:      r.ProxyURL,
 		EpgShift:      0,
 		Enabled:       new(true),
 		Priority:      0,
@@ -256,12 +401,14 @@ func (r *CreateEpgSourceRequest) ToModel() *models.EpgSource {
 // Note: DetectedTimezone is read-only (auto-detected during ingestion)
 type UpdateEpgSourceRequest struct {
 	Name          *string                 `json:"name,omitempty" doc:"User-friendly name for the source" maxLength:"255"`
-	Type          *models.EpgSourceType   `json:"type,omitempty" doc:"Source type: xmltv or xtream" enum:"xmltv,xtream"`
-	URL           *string                 `json:"url,omitempty" doc:"XMLTV URL or Xtream server URL" maxLength:"2048"`
+	Type          *models.EpgSourceType   `json:"type,omitempty" doc:"Source type: xmltv, xtream or stalker" enum:"xmltv,xtream,stalker"`
+	URL           *string                 `json:"url,omitempty" doc:"XMLTV URL, Xtream server URL or Stalker portal URL" maxLength:"2048"`
 	Username      *string                 `json:"username,omitempty" doc:"Username for Xtream authentication" maxLength:"255"`
 	Password      *string                 `json:"password,omitempty" doc:"Password for Xtream authentication" maxLength:"255"`
+	MacAddress    *string                 `json:"mac_address,omitempty" doc:"Device MAC address for Stalker authentication" maxLength:"17"`
 	ApiMethod     *models.XtreamApiMethod `json:"api_method,omitempty" doc:"API method for Xtream sources: stream_id or bulk_xmltv" enum:"stream_id,bulk_xmltv"`
 	UserAgent     *string                 `json:"user_agent,omitempty" doc:"Custom User-Agent header" maxLength:"512"`
+	ProxyURL      *string                 `json:"proxy_url,omitempty" doc:"Upstream HTTP(S) or SOCKS5 proxy for all connections to this source (empty to connect directly)" maxLength:"512"`
 	EpgShift      *int                    `json:"epg_shift,omitempty" doc:"Manual time shift in hours to adjust EPG times" minimum:"-12" maximum:"12"`
 	Enabled       *bool                   `json:"enabled,omitempty" doc:"Whether the source is enabled"`
 	Priority      *int                    `json:"priority,omitempty" doc:"Priority for program merging"`
@@ -287,12 +434,18 @@ func (r *UpdateEpgSourceRequest) ApplyToModel(s *models.EpgSource) {
 	if r.Password != nil {
 		s.Password = *r.Password
 	}
+	if r.MacAddress != nil {
+		s.MacAddress = *r.MacAddress
+	}
 	if r.ApiMethod != nil {
 		s.ApiMethod = *r.ApiMethod
 	}
 	if r.UserAgent != nil {
 		s.UserAgent = *r.UserAgent
 	}
+	if r.ProxyURL != nil {
+		s.ProxyURL = *r.ProxyURL
+	}
 	if r.EpgShift != nil {
 		s.EpgShift = *r.EpgShift
 	}
@@ -323,9 +476,17 @@ type StreamProxyResponse struct {
 	IsActive              bool                     `json:"is_active"`
 	AutoRegenerate        bool                     `json:"auto_regenerate"`
 	StartingChannelNumber int                      `json:"starting_channel_number"`
+	DedupMode             models.DedupMode         `json:"dedup_mode"`
+	DedupIdentity         models.DedupIdentity     `json:"dedup_identity"`
+	DedupExpression       string                   `json:"dedup_expression,omitempty"`
+	DedupPreference       models.DedupPreference   `json:"dedup_preference"`
+	AutoMatchEpg          bool                     `json:"auto_match_epg"`
+	IncludeVod            bool                     `json:"include_vod"`
+	IncludeSeries         bool                     `json:"include_series"`
 	UpstreamTimeout       int                      `json:"upstream_timeout,omitempty"`
 	BufferSize            int                      `json:"buffer_size,omitempty"`
 	MaxConcurrentStreams  int                      `json:"max_concurrent_streams,omitempty"`
+	Priority              int                      `json:"priority"`
 	CacheChannelLogos     bool                     `json:"cache_channel_logos"`
 	CacheProgramLogos     bool                     `json:"cache_program_logos"`
 	EncodingProfileID     *models.ULID             `json:"encoding_profile_id,omitempty"`
@@ -337,6 +498,10 @@ type StreamProxyResponse struct {
 	OutputPath            string                   `json:"output_path,omitempty"`
 	M3U8URL               string                   `json:"m3u8_url,omitempty"`
 	XMLTVURL              string                   `json:"xmltv_url,omitempty"`
+	VodURL                string                   `json:"vod_url,omitempty"`
+	SeriesURL             string                   `json:"series_url,omitempty"`
+
+	ConnectionLimitPolicy models.ConnectionLimitPolicy `json:"connection_limit_policy,omitempty"`
 }
 
 // StreamProxyFromModel converts a model to a response.
@@ -352,9 +517,18 @@ func StreamProxyFromModel(p *models.StreamProxy, baseURL string) StreamProxyResp
 		IsActive:              models.BoolVal(p.IsActive),
 		AutoRegenerate:        p.AutoRegenerate,
 		StartingChannelNumber: p.StartingChannelNumber,
+		DedupMode:             p.DedupMode,
+		DedupIdentity:         p.DedupIdentity,
+		DedupExpression:       p.DedupExpression,
+		DedupPreference:       p.DedupPreference,
+		AutoMatchEpg:          p.AutoMatchEpg,
+		IncludeVod:            p.IncludeVod,
+		IncludeSeries:         p.IncludeSeries,
 		UpstreamTimeout:       p.UpstreamTimeout,
 		BufferSize:            p.BufferSize,
 		MaxConcurrentStreams:  p.MaxConcurrentStreams,
+		ConnectionLimitPolicy: p.ConnectionLimitPolicy,
+		Priority:              p.Priority,
 		CacheChannelLogos:     p.CacheChannelLogos,
 		CacheProgramLogos:     p.CacheProgramLogos,
 		EncodingProfileID:     p.EncodingProfileID,
@@ -376,6 +550,12 @@ func StreamProxyFromModel(p *models.StreamProxy, baseURL string) StreamProxyResp
 			resp.M3U8URL = fmt.Sprintf("/proxy/%s.m3u", idStr)
 			resp.XMLTVURL = fmt.Sprintf("/proxy/%s.xmltv", idStr)
 		}
+		if p.IncludeVod {
+			resp.VodURL = fmt.Sprintf("%s/proxy/%s.vod.m3u", baseURL, idStr)
+		}
+		if p.IncludeSeries {
+			resp.SeriesURL = fmt.Sprintf("%s/proxy/%s.series.m3u", baseURL, idStr)
+		}
 	}
 
 	return resp
@@ -481,9 +661,18 @@ type CreateStreamProxyRequest struct {
 	StartingChannelNumber *int                           `json:"starting_channel_number,omitempty" doc:"Base channel number (default: 1)"`
 	NumberingMode         *models.NumberingMode          `json:"numbering_mode,omitempty" doc:"How to assign channel numbers: sequential, preserve, or group" enum:"sequential,preserve,group"`
 	GroupNumberingSize    *int                           `json:"group_numbering_size,omitempty" doc:"Size of each group range when using group numbering mode (default: 100)"`
+	DedupMode             *models.DedupMode              `json:"dedup_mode,omitempty" doc:"Whether channels duplicated across sources are kept or collapsed (default: keep)" enum:"keep,collapse"`
+	DedupIdentity         *models.DedupIdentity          `json:"dedup_identity,omitempty" doc:"How duplicates are recognised: tvg_id, name, or expression (default: tvg_id)" enum:"tvg_id,name,expression"`
+	DedupExpression       *string                        `json:"dedup_expression,omitempty" doc:"Expression whose regex captures identify duplicates (expression identity)" maxLength:"1024"`
+	DedupPreference       *models.DedupPreference        `json:"dedup_preference,omitempty" doc:"Which duplicate is kept: priority (source priority) or quality (probed resolution/bitrate)" enum:"priority,quality"`
+	AutoMatchEpg          *bool                          `json:"auto_match_epg,omitempty" doc:"Assign tvg-ids to channels with an empty or unknown tvg-id by matching names against EPG channels"`
+	IncludeVod            *bool                          `json:"include_vod,omitempty" doc:"Publish a movie playlist from the sources' VOD catalogues, filtered by the proxy's VOD filters"`
+	IncludeSeries         *bool                          `json:"include_series,omitempty" doc:"Publish a series playlist from the sources' series catalogues, filtered by the proxy's VOD filters"`
 	UpstreamTimeout       *int                           `json:"upstream_timeout,omitempty" doc:"Timeout in seconds for upstream connections"`
 	BufferSize            *int                           `json:"buffer_size,omitempty" doc:"Buffer size in bytes for proxy mode"`
 	MaxConcurrentStreams  *int                           `json:"max_concurrent_streams,omitempty" doc:"Max concurrent streams (0 = unlimited)"`
+	ConnectionLimitPolicy *models.ConnectionLimitPolicy  `json:"connection_limit_policy,omitempty" doc:"Overrides the sources' connection limit policy for streams of this proxy: reject, preempt_idlest, preempt_lowest_priority or preempt_oldest"`
+	Priority              *int                           `json:"priority,omitempty" doc:"Priority of the proxy's streams for the preempt_lowest_priority policy (higher = kept)"`
 	CacheChannelLogos     *bool                          `json:"cache_channel_logos,omitempty" doc:"Cache channel logos locally"`
 	CacheProgramLogos     *bool                          `json:"cache_program_logos,omitempty" doc:"Cache EPG program logos locally"`
 	EncodingProfileID     *models.ULID                   `json:"encoding_profile_id,omitempty" doc:"Fallback encoding profile when no client detection rule matches"`
@@ -505,6 +694,9 @@ func (r *CreateStreamProxyRequest) ToModel() *models.StreamProxy {
 		StartingChannelNumber: 1,
 		NumberingMode:         models.NumberingModePreserve, // Default
 		GroupNumberingSize:    100,                          // Default
+		DedupMode:             models.DedupModeKeep,
+		DedupIdentity:         models.DedupIdentityTvgID,
+		DedupPreference:       models.DedupPreferencePriority,
 		UpstreamTimeout:       30,
 		BufferSize:            8192,
 		MaxConcurrentStreams:  0,
@@ -530,6 +722,27 @@ func (r *CreateStreamProxyRequest) ToModel() *models.StreamProxy {
 	if r.GroupNumberingSize != nil {
 		proxy.GroupNumberingSize = *r.GroupNumberingSize
 	}
+	if r.DedupMode != nil && models.IsValidDedupMode(*r.DedupMode) {
+		proxy.DedupMode = *r.DedupMode
+	}
+	if r.DedupIdentity != nil && models.IsValidDedupIdentity(*r.DedupIdentity) {
+		proxy.DedupIdentity = *r.DedupIdentity
+	}
+	if r.DedupExpression != nil {
+		proxy.DedupExpression = *r.DedupExpression
+	}
+	if r.DedupPreference != nil && models.IsValidDedupPreference(*r.DedupPreference) {
+		proxy.DedupPreference = *r.DedupPreference
+	}
+	if r.AutoMatchEpg != nil {
+		proxy.AutoMatchEpg = *r.AutoMatchEpg
+	}
+	if r.IncludeVod != nil {
+		proxy.IncludeVod = *r.IncludeVod
+	}
+	if r.IncludeSeries != nil {
+		proxy.IncludeSeries = *r.IncludeSeries
+	}
 	if r.UpstreamTimeout != nil {
 		proxy.UpstreamTimeout = *r.UpstreamTimeout
 	}
@@ -539,6 +752,12 @@ func (r *CreateStreamProxyRequest) ToModel() *models.StreamProxy {
 	if r.MaxConcurrentStreams != nil {
 		proxy.MaxConcurrentStreams = *r.MaxConcurrentStreams
 	}
+	if r.ConnectionLimitPolicy != nil {
+		proxy.ConnectionLimitPolicy = *r.ConnectionLimitPolicy
+	}
+	if r.Priority != nil {
+		proxy.Priority = *r.Priority
+	}
 	if r.CacheChannelLogos != nil {
 		proxy.CacheChannelLogos = *r.CacheChannelLogos
 	}
@@ -561,9 +780,18 @@ type UpdateStreamProxyRequest struct {
 	StartingChannelNumber *int                           `json:"starting_channel_number,omitempty" doc:"Base channel number"`
 	NumberingMode         *models.NumberingMode          `json:"numbering_mode,omitempty" doc:"How to assign channel numbers: sequential, preserve, or group" enum:"sequential,preserve,group"`
 	GroupNumberingSize    *int                           `json:"group_numbering_size,omitempty" doc:"Size of each group range when using group numbering mode"`
+	DedupMode             *models.DedupMode              `json:"dedup_mode,omitempty" doc:"Whether channels duplicated across sources are kept or collapsed" enum:"keep,collapse"`
+	DedupIdentity         *models.DedupIdentity          `json:"dedup_identity,omitempty" doc:"How duplicates are recognised: tvg_id, name, or expression" enum:"tvg_id,name,expression"`
+	DedupExpression       *string                        `json:"dedup_expression,omitempty" doc:"Expression whose regex captures identify duplicates (expression identity)" maxLength:"1024"`
+	DedupPreference       *models.DedupPreference        `json:"dedup_preference,omitempty" doc:"Which duplicate is kept: priority (source priority) or quality (probed resolution/bitrate)" enum:"priority,quality"`
+	AutoMatchEpg          *bool                          `json:"auto_match_epg,omitempty" doc:"Assign tvg-ids to channels with an empty or unknown tvg-id by matching names against EPG channels"`
+	IncludeVod            *bool                          `json:"include_vod,omitempty" doc:"Publish a movie playlist from the sources' VOD catalogues, filtered by the proxy's VOD filters"`
+	IncludeSeries         *bool                          `json:"include_series,omitempty" doc:"Publish a series playlist from the sources' series catalogues, filtered by the proxy's VOD filters"`
 	UpstreamTimeout       *int                           `json:"upstream_timeout,omitempty" doc:"Timeout in seconds for upstream connections"`
 	BufferSize            *int                           `json:"buffer_size,omitempty" doc:"Buffer size in bytes for proxy mode"`
 	MaxConcurrentStreams  *int                           `json:"max_concurrent_streams,omitempty" doc:"Max concurrent streams (0 = unlimited)"`
+	ConnectionLimitPolicy *models.ConnectionLimitPolicy  `json:"connection_limit_policy,omitempty" doc:"Overrides the sources' connection limit policy for streams of this proxy: reject, preempt_idlest, preempt_lowest_priority or preempt_oldest"`
+	Priority              *int                           `json:"priority,omitempty" doc:"Priority of the proxy's streams for the preempt_lowest_priority policy (higher = kept)"`
 	CacheChannelLogos     *bool                          `json:"cache_channel_logos,omitempty" doc:"Cache channel logos locally"`
 	CacheProgramLogos     *bool                          `json:"cache_program_logos,omitempty" doc:"Cache EPG program logos locally"`
 	EncodingProfileID     *models.ULID                   `json:"encoding_profile_id,omitempty" doc:"Fallback encoding profile when no client detection rule matches"`
@@ -600,6 +828,27 @@ func (r *UpdateStreamProxyRequest) ApplyToModel(p *models.StreamProxy) {
 	if r.GroupNumberingSize != nil {
 		p.GroupNumberingSize = *r.GroupNumberingSize
 	}
+	if r.DedupMode != nil && models.IsValidDedupMode(*r.DedupMode) {
+		p.DedupMode = *r.DedupMode
+	}
+	if r.DedupIdentity != nil && models.IsValidDedupIdentity(*r.DedupIdentity) {
+		p.DedupIdentity = *r.DedupIdentity
+	}
+	if r.DedupExpression != nil {
+		p.DedupExpression = *r.DedupExpression
+	}
+	if r.DedupPreference != nil && models.IsValidDedupPreference(*r.DedupPreference) {
+		p.DedupPreference = *r.DedupPreference
+	}
+	if r.AutoMatchEpg != nil {
+		p.AutoMatchEpg = *r.AutoMatchEpg
+	}
+	if r.IncludeVod != nil {
+		p.IncludeVod = *r.IncludeVod
+	}
+	if r.IncludeSeries != nil {
+		p.IncludeSeries = *r.IncludeSeries
+	}
 	if r.UpstreamTimeout != nil {
 		p.UpstreamTimeout = *r.UpstreamTimeout
 	}
@@ -609,6 +858,12 @@ func (r *UpdateStreamProxyRequest) ApplyToModel(p *models.StreamProxy) {
 	if r.MaxConcurrentStreams != nil {
 		p.MaxConcurrentStreams = *r.MaxConcurrentStreams
 	}
+	if r.ConnectionLimitPolicy != nil {
+		p.ConnectionLimitPolicy = *r.ConnectionLimitPolicy
+	}
+	if r.Priority != nil {
+		p.Priority = *r.Priority
+	}
 	if r.CacheChannelLogos != nil {
 		p.CacheChannelLogos = *r.CacheChannelLogos
 	}
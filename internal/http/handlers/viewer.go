@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// ViewerHandler handles viewer account endpoints.
+type ViewerHandler struct {
+	viewerService *service.ViewerService
+}
+
+// NewViewerHandler creates a new viewer handler.
+func NewViewerHandler(viewerService *service.ViewerService) *ViewerHandler {
+	return &ViewerHandler{viewerService: viewerService}
+}
+
+// Register registers the viewer routes with the API.
+func (h *ViewerHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listViewers",
+		Method:      "GET",
+		Path:        "/api/v1/viewers",
+		Summary:     "List viewers",
+		Description: "Returns all playback viewers",
+		Tags:        []string{"Viewers"},
+	}, h.List)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getViewer",
+		Method:      "GET",
+		Path:        "/api/v1/viewers/{id}",
+		Summary:     "Get viewer",
+		Description: "Returns a viewer by ID",
+		Tags:        []string{"Viewers"},
+	}, h.GetByID)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "createViewer",
+		Method:        "POST",
+		Path:          "/api/v1/viewers",
+		Summary:       "Create viewer",
+		Description:   "Creates a playback viewer with a new stream token",
+		Tags:          []string{"Viewers"},
+		DefaultStatus: http.StatusCreated,
+	}, h.Create)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "updateViewer",
+		Method:      "PUT",
+		Path:        "/api/v1/viewers/{id}",
+		Summary:     "Update viewer",
+		Description: "Updates a viewer's name, description, status and expiry",
+		Tags:        []string{"Viewers"},
+	}, h.Update)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "regenerateViewerToken",
+		Method:      "POST",
+		Path:        "/api/v1/viewers/{id}/regenerate-token",
+		Summary:     "Regenerate viewer token",
+		Description: "Replaces the viewer's stream token, revoking every playlist and stream URL issued with the old one",
+		Tags:        []string{"Viewers"},
+	}, h.RegenerateToken)
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "deleteViewer",
+		Method:        "DELETE",
+		Path:          "/api/v1/viewers/{id}",
+		Summary:       "Delete viewer",
+		Description:   "Deletes a viewer, revoking all of its stream URLs",
+		Tags:          []string{"Viewers"},
+		DefaultStatus: http.StatusNoContent,
+	}, h.Delete)
+}
+
+// ViewerResponse represents a viewer in API responses.
+type ViewerResponse struct {
+	ID          models.ULID `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Token       string      `json:"token" doc:"Stream token; append ?token=<token> to playlist URLs, or use it as the Xtream password"`
+	IsActive    bool        `json:"is_active"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	LastSeenAt  *time.Time  `json:"last_seen_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// ViewerFromModel converts a model to a response.
+func ViewerFromModel(v *models.Viewer) ViewerResponse {
+	return ViewerResponse{
+		ID:          v.ID,
+		Name:        v.Name,
+		Description: v.Description,
+		Token:       v.Token,
+		IsActive:    models.BoolVal(v.IsActive),
+		ExpiresAt:   v.ExpiresAt,
+		LastSeenAt:  v.LastSeenAt,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
+
+// ListViewersInput is the input for listing viewers.
+type ListViewersInput struct{}
+
+// ListViewersOutput is the output for listing viewers.
+type ListViewersOutput struct {
+	Body struct {
+		Viewers []ViewerResponse `json:"viewers"`
+	}
+}
+
+// List returns all viewers.
+func (h *ViewerHandler) List(ctx context.Context, _ *ListViewersInput) (*ListViewersOutput, error) {
+	viewers, err := h.viewerService.List(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list viewers", err)
+	}
+
+	resp := &ListViewersOutput{}
+	resp.Body.Viewers = make([]ViewerResponse, 0, len(viewers))
+	for _, v := range viewers {
+		resp.Body.Viewers = append(resp.Body.Viewers, ViewerFromModel(v))
+	}
+	return resp, nil
+}
+
+// GetViewerInput is the input for getting a viewer.
+type GetViewerInput struct {
+	ID string `path:"id" doc:"Viewer ID (ULID)"`
+}
+
+// GetViewerOutput is the output for getting a viewer.
+type GetViewerOutput struct {
+	Body ViewerResponse
+}
+
+// GetByID returns a viewer by ID.
+func (h *ViewerHandler) GetByID(ctx context.Context, input *GetViewerInput) (*GetViewerOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid viewer ID format", err)
+	}
+	viewer, err := h.viewerService.GetByID(ctx, id)
+	if err != nil {
+		return nil, viewerServiceError("failed to get viewer", err)
+	}
+	return &GetViewerOutput{Body: ViewerFromModel(viewer)}, nil
+}
+
+// ViewerBody is the editable part of a viewer.
+type ViewerBody struct {
+	Name        string     `json:"name" minLength:"1" maxLength:"100" doc:"Unique viewer name"`
+	Description string     `json:"description,omitempty" maxLength:"500" doc:"Optional description"`
+	IsActive    *bool      `json:"is_active,omitempty" doc:"Whether the viewer may play streams (default true)"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" doc:"When the viewer's access ends (omit for no expiry)"`
+}
+
+// CreateViewerInput is the input for creating a viewer.
+type CreateViewerInput struct {
+	Body ViewerBody
+}
+
+// CreateViewerOutput is the output for creating a viewer.
+type CreateViewerOutput struct {
+	Body ViewerResponse
+}
+
+// Create creates a new viewer.
+func (h *ViewerHandler) Create(ctx context.Context, input *CreateViewerInput) (*CreateViewerOutput, error) {
+	viewer := &models.Viewer{
+		Name:        input.Body.Name,
+		Description: input.Body.Description,
+		IsActive:    input.Body.IsActive,
+		ExpiresAt:   input.Body.ExpiresAt,
+	}
+	if err := h.viewerService.Create(ctx, viewer); err != nil {
+		return nil, viewerServiceError("failed to create viewer", err)
+	}
+	return &CreateViewerOutput{Body: ViewerFromModel(viewer)}, nil
+}
+
+// UpdateViewerInput is the input for updating a viewer.
+type UpdateViewerInput struct {
+	ID   string `path:"id" doc:"Viewer ID (ULID)"`
+	Body ViewerBody
+}
+
+// UpdateViewerOutput is the output for updating a viewer.
+type UpdateViewerOutput struct {
+	Body ViewerResponse
+}
+
+// Update replaces a viewer's editable fields. Omitting expires_at clears the expiry.
+func (h *ViewerHandler) Update(ctx context.Context, input *UpdateViewerInput) (*UpdateViewerOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid viewer ID format", err)
+	}
+	viewer, err := h.viewerService.GetByID(ctx, id)
+	if err != nil {
+		return nil, viewerServiceError("failed to get viewer", err)
+	}
+
+	viewer.Name = input.Body.Name
+	viewer.Description = input.Body.Description
+	if input.Body.IsActive != nil {
+		viewer.IsActive = input.Body.IsActive
+	}
+	viewer.ExpiresAt = input.Body.ExpiresAt
+
+	if err := h.viewerService.Update(ctx, viewer); err != nil {
+		return nil, viewerServiceError("failed to update viewer", err)
+	}
+	return &UpdateViewerOutput{Body: ViewerFromModel(viewer)}, nil
+}
+
+// RegenerateViewerTokenInput is the input for regenerating a viewer's token.
+type RegenerateViewerTokenInput struct {
+	ID string `path:"id" doc:"Viewer ID (ULID)"`
+}
+
+// RegenerateViewerTokenOutput is the output for regenerating a viewer's token.
+type RegenerateViewerTokenOutput struct {
+	Body ViewerResponse
+}
+
+// RegenerateToken replaces a viewer's stream token.
+func (h *ViewerHandler) RegenerateToken(ctx context.Context, input *RegenerateViewerTokenInput) (*RegenerateViewerTokenOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid viewer ID format", err)
+	}
+	viewer, err := h.viewerService.RegenerateToken(ctx, id)
+	if err != nil {
+		return nil, viewerServiceError("failed to regenerate viewer token", err)
+	}
+	return &RegenerateViewerTokenOutput{Body: ViewerFromModel(viewer)}, nil
+}
+
+// DeleteViewerInput is the input for deleting a viewer.
+type DeleteViewerInput struct {
+	ID string `path:"id" doc:"Viewer ID (ULID)"`
+}
+
+// DeleteViewerOutput is the output for deleting a viewer.
+type DeleteViewerOutput struct{}
+
+// Delete deletes a viewer.
+func (h *ViewerHandler) Delete(ctx context.Context, input *DeleteViewerInput) (*DeleteViewerOutput, error) {
+	id, err := models.ParseULID(input.ID)
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid viewer ID format", err)
+	}
+	if err := h.viewerService.Delete(ctx, id); err != nil {
+		return nil, viewerServiceError("failed to delete viewer", err)
+	}
+	return &DeleteViewerOutput{}, nil
+}
+
+// viewerServiceError maps viewer service errors to HTTP errors.
+func viewerServiceError(msg string, err error) error {
+	var ve models.ValidationError
+	switch {
+	case errors.Is(err, service.ErrViewerNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, service.ErrViewerExists):
+		return huma.Error409Conflict(err.Error())
+	case errors.As(err, &ve):
+		return huma.Error400BadRequest(ve.Error())
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/jmylchreest/tvarr/internal/service"
+)
+
+// defaultAnalyticsPeriod is how far back analytics look when no start time is given.
+const defaultAnalyticsPeriod = 7 * 24 * time.Hour
+
+// ViewingHandler handles viewing session and analytics endpoints.
+type ViewingHandler struct {
+	viewingService *service.ViewingService
+}
+
+// NewViewingHandler creates a new viewing handler.
+func NewViewingHandler(viewingService *service.ViewingService) *ViewingHandler {
+	return &ViewingHandler{viewingService: viewingService}
+}
+
+// Register registers the analytics routes with the API.
+func (h *ViewingHandler) Register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "listViewingSessions",
+		Method:      "GET",
+		Path:        "/api/v1/analytics/sessions",
+		Summary:     "List viewing sessions",
+		Description: "Returns finished viewing sessions, most recent first",
+		Tags:        []string{"Analytics"},
+	}, h.GetSessions)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getTopChannels",
+		Method:      "GET",
+		Path:        "/api/v1/analytics/top-channels",
+		Summary:     "Get top channels",
+		Description: "Returns the channels with the most viewing time",
+		Tags:        []string{"Analytics"},
+	}, h.GetTopChannels)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getSourceConcurrency",
+		Method:      "GET",
+		Path:        "/api/v1/analytics/concurrency",
+		Summary:     "Get peak concurrency per source",
+		Description: "Returns the most viewing sessions open at once on each stream source",
+		Tags:        []string{"Analytics"},
+	}, h.GetConcurrency)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getTranscodeUsage",
+		Method:      "GET",
+		Path:        "/api/v1/analytics/transcoding",
+		Summary:     "Get transcode usage",
+		Description: "Returns transcoded viewing minutes per encoding profile",
+		Tags:        []string{"Analytics"},
+	}, h.GetTranscoding)
+}
+
+// AnalyticsPeriodInput is the reporting period shared by analytics endpoints.
+type AnalyticsPeriodInput struct {
+	Since string `query:"since" doc:"Start of the period (RFC3339), defaults to 7 days ago"`
+	Until string `query:"until" doc:"End of the period (RFC3339), defaults to now"`
+}
+
+// period parses the reporting period.
+func (p AnalyticsPeriodInput) period() (since, until time.Time, err error) {
+	until = time.Now()
+	if p.Until != "" {
+		if until, err = time.Parse(time.RFC3339, p.Until); err != nil {
+			return since, until, huma.Error400BadRequest("invalid until time, expected RFC3339", err)
+		}
+	}
+	since = until.Add(-defaultAnalyticsPeriod)
+	if p.Since != "" {
+		if since, err = time.Parse(time.RFC3339, p.Since); err != nil {
+			return since, until, huma.Error400BadRequest("invalid since time, expected RFC3339", err)
+		}
+	}
+	if !since.Before(until) {
+		return since, until, huma.Error400BadRequest("since must be before until")
+	}
+	return since, until, nil
+}
+
+// AnalyticsPeriod is the reporting period of an analytics response.
+type AnalyticsPeriod struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+}
+
+// ViewingSessionResponse represents a viewing session in API responses.
+type ViewingSessionResponse struct {
+	ID                  models.ULID  `json:"id"`
+	ViewerID            *models.ULID `json:"viewer_id,omitempty"`
+	ViewerName          string       `json:"viewer_name,omitempty"`
+	ClientIP            string       `json:"client_ip"`
+	UserAgent           string       `json:"user_agent,omitempty"`
+	ClientRule          string       `json:"client_rule,omitempty"`
+	ChannelID           models.ULID  `json:"channel_id"`
+	ChannelName         string       `json:"channel_name"`
+	ProxyID             models.ULID  `json:"proxy_id"`
+	ProxyName           string       `json:"proxy_name"`
+	SourceID            models.ULID  `json:"source_id"`
+	SourceName          string       `json:"source_name"`
+	DeliveryRoute       string       `json:"delivery_route"`
+	OutputFormat        string       `json:"output_format"`
+	CodecVariant        string       `json:"codec_variant,omitempty"`
+	EncodingProfileName string       `json:"encoding_profile_name,omitempty"`
+	StartedAt           time.Time    `json:"started_at"`
+	EndedAt             time.Time    `json:"ended_at"`
+	DurationMs          int64        `json:"duration_ms"`
+	BytesSent           int64        `json:"bytes_sent"`
+}
+
+// ViewingSessionFromModel converts a model to a response.
+func ViewingSessionFromModel(s *models.ViewingSession) ViewingSessionResponse {
+	return ViewingSessionResponse{
+		ID:                  s.ID,
+		ViewerID:            s.ViewerID,
+		ViewerName:          s.ViewerName,
+		ClientIP:            s.ClientIP,
+		UserAgent:           s.UserAgent,
+		ClientRule:          s.ClientRule,
+		ChannelID:           s.ChannelID,
+		ChannelName:         s.ChannelName,
+		ProxyID:             s.ProxyID,
+		ProxyName:           s.ProxyName,
+		SourceID:            s.SourceID,
+		SourceName:          s.SourceName,
+		DeliveryRoute:       s.DeliveryRoute,
+		OutputFormat:        s.OutputFormat,
+		CodecVariant:        s.CodecVariant,
+		EncodingProfileName: s.EncodingProfileName,
+		StartedAt:           s.StartedAt,
+		EndedAt:             s.EndedAt,
+		DurationMs:          s.DurationMs,
+		BytesSent:           s.BytesSent,
+	}
+}
+
+// ListViewingSessionsInput is the input for listing viewing sessions.
+type ListViewingSessionsInput struct {
+	AnalyticsPeriodInput
+	ChannelID string `query:"channel_id" doc:"Filter by channel ID (optional)"`
+	ProxyID   string `query:"proxy_id" doc:"Filter by proxy ID (optional)"`
+	ViewerID  string `query:"viewer_id" doc:"Filter by viewer ID (optional)"`
+	Offset    int    `query:"offset" default:"0" minimum:"0" doc:"Offset for pagination"`
+	Limit     int    `query:"limit" default:"50" minimum:"1" maximum:"1000" doc:"Limit for pagination"`
+}
+
+// ListViewingSessionsOutput is the output for listing viewing sessions.
+type ListViewingSessionsOutput struct {
+	Body struct {
+		Sessions   []ViewingSessionResponse `json:"sessions"`
+		Pagination PaginationMeta           `json:"pagination"`
+	}
+}
+
+// GetSessions returns viewing sessions started in the period.
+func (h *ViewingHandler) GetSessions(ctx context.Context, input *ListViewingSessionsInput) (*ListViewingSessionsOutput, error) {
+	since, until, err := input.period()
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.ViewingSessionFilter{Since: since, Until: until}
+	for _, f := range []struct {
+		value string
+		dst   **models.ULID
+		name  string
+	}{
+		{input.ChannelID, &filter.ChannelID, "channel"},
+		{input.ProxyID, &filter.ProxyID, "proxy"},
+		{input.ViewerID, &filter.ViewerID, "viewer"},
+	} {
+		if f.value == "" {
+			continue
+		}
+		id, err := models.ParseULID(f.value)
+		if err != nil {
+			return nil, huma.Error400BadRequest("invalid "+f.name+" ID format", err)
+		}
+		*f.dst = &id
+	}
+
+	sessions, total, err := h.viewingService.GetSessions(ctx, filter, input.Offset, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list viewing sessions", err)
+	}
+
+	resp := &ListViewingSessionsOutput{}
+	resp.Body.Sessions = make([]ViewingSessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp.Body.Sessions = append(resp.Body.Sessions, ViewingSessionFromModel(s))
+	}
+
+	totalPages := total / int64(input.Limit)
+	if total%int64(input.Limit) > 0 {
+		totalPages++
+	}
+	resp.Body.Pagination = PaginationMeta{
+		CurrentPage: (input.Offset / input.Limit) + 1,
+		PageSize:    input.Limit,
+		TotalItems:  total,
+		TotalPages:  totalPages,
+	}
+	return resp, nil
+}
+
+// GetTopChannelsInput is the input for getting top channels.
+type GetTopChannelsInput struct {
+	AnalyticsPeriodInput
+	Limit int `query:"limit" default:"10" minimum:"1" maximum:"100" doc:"Number of channels to return"`
+}
+
+// GetTopChannelsOutput is the output for getting top channels.
+type GetTopChannelsOutput struct {
+	Body struct {
+		Period   AnalyticsPeriod               `json:"period"`
+		Channels []*models.ChannelViewingStats `json:"channels"`
+	}
+}
+
+// GetTopChannels returns the channels with the most viewing time in the period.
+func (h *ViewingHandler) GetTopChannels(ctx context.Context, input *GetTopChannelsInput) (*GetTopChannelsOutput, error) {
+	since, until, err := input.period()
+	if err != nil {
+		return nil, err
+	}
+	channels, err := h.viewingService.TopChannels(ctx, since, until, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get top channels", err)
+	}
+
+	resp := &GetTopChannelsOutput{}
+	resp.Body.Period = AnalyticsPeriod{Since: since, Until: until}
+	resp.Body.Channels = channels
+	if resp.Body.Channels == nil {
+		resp.Body.Channels = []*models.ChannelViewingStats{}
+	}
+	return resp, nil
+}
+
+// GetConcurrencyOutput is the output for getting peak concurrency per source.
+type GetConcurrencyOutput struct {
+	Body struct {
+		Period  AnalyticsPeriod                  `json:"period"`
+		Sources []*models.SourceConcurrencyStats `json:"sources"`
+	}
+}
+
+// GetConcurrency returns the peak number of concurrent viewings on each source in the period.
+func (h *ViewingHandler) GetConcurrency(ctx context.Context, input *AnalyticsPeriodInput) (*GetConcurrencyOutput, error) {
+	since, until, err := input.period()
+	if err != nil {
+		return nil, err
+	}
+	sources, err := h.viewingService.PeakConcurrency(ctx, since, until)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get source concurrency", err)
+	}
+
+	resp := &GetConcurrencyOutput{}
+	resp.Body.Period = AnalyticsPeriod{Since: since, Until: until}
+	resp.Body.Sources = sources
+	return resp, nil
+}
+
+// ProfileTranscodeResponse is the transcode usage of an encoding profile.
+type ProfileTranscodeResponse struct {
+	EncodingProfileName string  `json:"encoding_profile_name"`
+	Viewings            int64   `json:"viewings"`
+	TranscodeMinutes    float64 `json:"transcode_minutes"`
+}
+
+// GetTranscodingOutput is the output for getting transcode usage.
+type GetTranscodingOutput struct {
+	Body struct {
+		Period           AnalyticsPeriod            `json:"period"`
+		Profiles         []ProfileTranscodeResponse `json:"profiles"`
+		TranscodeMinutes float64                    `json:"transcode_minutes"`
+	}
+}
+
+// GetTranscoding returns transcoded viewing minutes per encoding profile in the period.
+func (h *ViewingHandler) GetTranscoding(ctx context.Context, input *AnalyticsPeriodInput) (*GetTranscodingOutput, error) {
+	since, until, err := input.period()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := h.viewingService.TranscodeStats(ctx, since, until)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get transcode usage", err)
+	}
+
+	resp := &GetTranscodingOutput{}
+	resp.Body.Period = AnalyticsPeriod{Since: since, Until: until}
+	resp.Body.Profiles = make([]ProfileTranscodeResponse, 0, len(stats))
+	for _, s := range stats {
+		minutes := time.Duration(s.DurationMs * int64(time.Millisecond)).Minutes()
+		resp.Body.Profiles = append(resp.Body.Profiles, ProfileTranscodeResponse{
+			EncodingProfileName: s.EncodingProfileName,
+			Viewings:            s.Viewings,
+			TranscodeMinutes:    minutes,
+		})
+		resp.Body.TranscodeMinutes += minutes
+	}
+	return resp, nil
+}
@@ -0,0 +1,681 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/jmylchreest/tvarr/internal/service"
+	"github.com/jmylchreest/tvarr/internal/storage"
+	"github.com/jmylchreest/tvarr/pkg/m3u"
+	"github.com/jmylchreest/tvarr/pkg/xtream"
+)
+
+// Xtream API actions served by player_api.php.
+const (
+	xtreamActionLiveCategories   = "get_live_categories"
+	xtreamActionLiveStreams      = "get_live_streams"
+	xtreamActionShortEPG         = "get_short_epg"
+	xtreamActionSimpleDataTable  = "get_simple_data_table"
+	xtreamActionVODCategories    = "get_vod_categories"
+	xtreamActionVODStreams       = "get_vod_streams"
+	xtreamActionVODInfo          = "get_vod_info"
+	xtreamActionSeriesCategories = "get_series_categories"
+	xtreamActionSeries           = "get_series"
+	xtreamActionSeriesInfo       = "get_series_info"
+)
+
+const (
+	// xtreamTimeFormat is the timestamp layout used in Xtream EPG and server info.
+	xtreamTimeFormat = "2006-01-02 15:04:05"
+	// xtreamTimeshiftFormat is the programme start layout used in Xtream catch-up URLs.
+	xtreamTimeshiftFormat = "2006-01-02:15-04"
+	// xtreamDefaultShortEPGLimit is the number of programs returned by get_short_epg without a limit.
+	xtreamDefaultShortEPGLimit = 4
+	// xtreamSimpleDataTableWindow is how far either side of now get_simple_data_table looks.
+	xtreamSimpleDataTableWindow = 7 * 24 * time.Hour
+	// xtreamUncategorized is the category name used for channels without a group.
+	xtreamUncategorized = "Uncategorized"
+)
+
+// XtreamOutputHandler exposes generated stream proxies through an Xtream Codes
+// compatible API (player_api.php, get.php, xmltv.php and /live/, /movie/ and
+// /series/ stream URLs), so set-top apps that only speak Xtream can consume
+// tvarr output.
+//
+// Each proxy is a single account: the username is the proxy name (or ID) and
+// the password is the proxy ID, matching the access model of /proxy/{id}.m3u.
+// With viewer authentication enabled, the password is a viewer token instead.
+type XtreamOutputHandler struct {
+	proxyService   *service.ProxyService
+	epgProgramRepo repository.EpgProgramRepository
+	sandbox        *storage.Sandbox
+	viewers        ViewerAuthenticator
+	baseURL        string
+	logger         *slog.Logger
+}
+
+// NewXtreamOutputHandler creates a new Xtream output handler.
+func NewXtreamOutputHandler(
+	proxyService *service.ProxyService,
+	epgProgramRepo repository.EpgProgramRepository,
+	sandbox *storage.Sandbox,
+) *XtreamOutputHandler {
+	return &XtreamOutputHandler{
+		proxyService:   proxyService,
+		epgProgramRepo: epgProgramRepo,
+		sandbox:        sandbox,
+		logger:         slog.Default(),
+	}
+}
+
+// WithLogger sets the logger for the handler.
+func (h *XtreamOutputHandler) WithLogger(logger *slog.Logger) *XtreamOutputHandler {
+	if logger != nil {
+		h.logger = logger
+	}
+	return h
+}
+
+// WithBaseURL sets the externally reachable base URL advertised to clients.
+// When empty, the base URL is derived from each request.
+func (h *XtreamOutputHandler) WithBaseURL(baseURL string) *XtreamOutputHandler {
+	h.baseURL = strings.TrimSuffix(baseURL, "/")
+	return h
+}
+
+// WithViewerAuth makes the Xtream password a viewer token: the username still selects
+// the proxy, and stream redirects carry the viewer's stream credential.
+func (h *XtreamOutputHandler) WithViewerAuth(viewers ViewerAuthenticator) *XtreamOutputHandler {
+	h.viewers = viewers
+	return h
+}
+
+// RegisterChiRoutes registers the Xtream-compatible routes.
+// Routes:
+//   - GET /player_api.php - Account info, categories, streams and EPG
+//   - GET /get.php - M3U playlist with Xtream-style stream URLs
+//   - GET /xmltv.php - XMLTV guide
+//   - GET /live/{username}/{password}/{stream} - Live stream (redirects to the relay)
+//   - GET /movie/{username}/{password}/{stream} - Movie (redirects to the provider)
+//   - GET /series/{username}/{password}/{stream} - Series episode (redirects to the provider)
+//   - GET /timeshift/{username}/{password}/{duration}/{start}/{stream} - Catch-up (redirects to the relay)
+//   - GET /streaming/timeshift.php - Catch-up, query form of /timeshift/
+func (h *XtreamOutputHandler) RegisterChiRoutes(router chi.Router) {
+	router.Get("/player_api.php", h.servePlayerAPI)
+	router.Get("/get.php", h.serveGetPHP)
+	router.Get("/xmltv.php", h.serveXMLTV)
+	router.Get("/live/{username}/{password}/{stream}", h.serveLiveStream)
+	router.Get("/movie/{username}/{password}/{stream}", h.serveMovie)
+	router.Get("/series/{username}/{password}/{stream}", h.serveEpisode)
+	router.Get("/timeshift/{username}/{password}/{duration}/{start}/{stream}", h.serveTimeshiftPath)
+	router.Get("/streaming/timeshift.php", h.serveTimeshiftQuery)
+}
+
+// servePlayerAPI handles GET /player_api.php.
+func (h *XtreamOutputHandler) servePlayerAPI(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	username := query.Get("username")
+	password := query.Get("password")
+
+	proxy, viewer, ok := h.authenticate(w, r, username, password)
+	if !ok {
+		return
+	}
+
+	action := query.Get("action")
+	switch action {
+	case "":
+		writeXtreamJSON(w, http.StatusOK, h.authInfo(r, proxy, viewer, username, password))
+		return
+	case xtreamActionVODCategories, xtreamActionVODStreams, xtreamActionVODInfo,
+		xtreamActionSeriesCategories, xtreamActionSeries, xtreamActionSeriesInfo:
+		h.serveVodAction(w, r, proxy, action)
+		return
+	}
+
+	catalog, ok := h.loadCatalog(w, proxy)
+	if !ok {
+		return
+	}
+
+	switch action {
+	case xtreamActionLiveCategories:
+		writeXtreamJSON(w, http.StatusOK, catalog.categories)
+	case xtreamActionLiveStreams:
+		writeXtreamJSON(w, http.StatusOK, catalog.liveStreams(query.Get("category_id")))
+	case xtreamActionShortEPG, xtreamActionSimpleDataTable:
+		h.serveEPG(w, r, catalog, action)
+	default:
+		writeXtreamJSON(w, http.StatusOK, []any{})
+	}
+}
+
+// serveEPG handles the get_short_epg and get_simple_data_table actions.
+func (h *XtreamOutputHandler) serveEPG(w http.ResponseWriter, r *http.Request, catalog *xtreamCatalog, action string) {
+	query := r.URL.Query()
+	streamID, err := strconv.Atoi(query.Get("stream_id"))
+	if err != nil {
+		http.Error(w, "invalid stream_id", http.StatusBadRequest)
+		return
+	}
+
+	ch, found := catalog.byStreamID[streamID]
+	if !found {
+		http.Error(w, fmt.Sprintf("stream %d not found", streamID), http.StatusNotFound)
+		return
+	}
+
+	resp := xtream.EPGResponse{EPGListings: []xtream.EPGListing{}}
+	if ch.entry.TvgID == "" {
+		writeXtreamJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	now := time.Now()
+	var programs []*models.EpgProgram
+	if action == xtreamActionShortEPG {
+		limit := xtreamDefaultShortEPGLimit
+		if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+			limit = l
+		}
+		programs, err = h.epgProgramRepo.GetByChannelIDWithLimit(r.Context(), ch.entry.TvgID, limit)
+	} else {
+		programs, err = h.epgProgramRepo.GetByChannelID(r.Context(), ch.entry.TvgID,
+			now.Add(-xtreamSimpleDataTableWindow), now.Add(xtreamSimpleDataTableWindow))
+	}
+	if err != nil {
+		h.logger.Error("failed to get xtream epg",
+			slog.Int("stream_id", streamID),
+			slog.String("channel_id", ch.entry.TvgID),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to get EPG", http.StatusInternalServerError)
+		return
+	}
+
+	for _, program := range programs {
+		resp.EPGListings = append(resp.EPGListings, programToXtreamListing(program, streamID, now))
+	}
+	writeXtreamJSON(w, http.StatusOK, resp)
+}
+
+// serveGetPHP handles GET /get.php, returning the proxy playlist with
+// Xtream-style /live/ URLs so clients authenticate every stream request.
+func (h *XtreamOutputHandler) serveGetPHP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	username := query.Get("username")
+	password := query.Get("password")
+
+	proxy, viewer, ok := h.authenticate(w, r, username, password)
+	if !ok {
+		return
+	}
+
+	catalog, ok := h.loadCatalog(w, proxy)
+	if !ok {
+		return
+	}
+
+	ext := "ts"
+	if output := strings.ToLower(query.Get("output")); output == "m3u8" || output == "hls" {
+		ext = "m3u8"
+	}
+	plain := query.Get("type") == "m3u"
+	baseURL := requestBaseURL(r, h.baseURL)
+
+	w.Header().Set("Content-Type", "audio/x-mpegurl")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"tv_channels.m3u\"")
+	w.WriteHeader(http.StatusOK)
+
+	writer := m3u.NewWriter(w)
+	if err := writer.WriteHeader(); err != nil {
+		return
+	}
+	for _, ch := range catalog.channels {
+		entry := &m3u.Entry{
+			Duration: -1,
+			Title:    ch.entry.Title,
+			URL:      xtreamLiveURL(baseURL, username, password, ch.streamID, ext),
+		}
+		if !plain {
+			entry.TvgID = ch.entry.TvgID
+			entry.TvgName = ch.entry.TvgName
+			entry.TvgLogo = ch.entry.TvgLogo
+			entry.GroupTitle = ch.entry.GroupTitle
+			entry.ChannelNumber = ch.entry.ChannelNumber
+			if ch.hasCatchup() {
+				entry.Catchup = ch.entry.Catchup
+				entry.CatchupDays = ch.entry.CatchupDays
+				entry.CatchupSource = withStreamCredential(rebaseRelayURL(ch.entry.CatchupSource, baseURL), h.viewers, viewer)
+			}
+		}
+		if err := writer.WriteEntry(entry); err != nil {
+			h.logger.Debug("xtream playlist write aborted", slog.String("error", err.Error()))
+			return
+		}
+	}
+}
+
+// serveXMLTV handles GET /xmltv.php, serving the proxy's generated XMLTV.
+func (h *XtreamOutputHandler) serveXMLTV(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	proxy, _, ok := h.authenticate(w, r, query.Get("username"), query.Get("password"))
+	if !ok {
+		return
+	}
+
+	data, err := h.sandbox.ReadFile(filepath.Join("output", proxy.ID.String()+".xml"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			http.Error(w, fmt.Sprintf("XMLTV not found for proxy %s", proxy.ID), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to read XMLTV file",
+			slog.String("proxy_id", proxy.ID.String()),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to read XMLTV file", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// serveLiveStream handles GET /live/{username}/{password}/{stream}.
+// The stream ID is resolved against the generated playlist and the client is
+// redirected to the relay route, so all relay features (client detection,
+// transcoding, connection limits) apply unchanged.
+func (h *XtreamOutputHandler) serveLiveStream(w http.ResponseWriter, r *http.Request) {
+	username, _ := url.PathUnescape(chi.URLParam(r, "username"))
+	password, _ := url.PathUnescape(chi.URLParam(r, "password"))
+
+	proxy, viewer, ok := h.authenticate(w, r, username, password)
+	if !ok {
+		return
+	}
+
+	streamParam := chi.URLParam(r, "stream")
+	idPart, ext, _ := strings.Cut(streamParam, ".")
+	streamID, err := strconv.Atoi(idPart)
+	if err != nil {
+		http.Error(w, "invalid stream ID", http.StatusBadRequest)
+		return
+	}
+
+	catalog, ok := h.loadCatalog(w, proxy)
+	if !ok {
+		return
+	}
+
+	ch, found := catalog.byStreamID[streamID]
+	if !found {
+		http.Error(w, fmt.Sprintf("stream %d not found", streamID), http.StatusNotFound)
+		return
+	}
+
+	target := withStreamCredential(rebaseRelayURL(ch.entry.URL, requestBaseURL(r, h.baseURL)), h.viewers, viewer)
+	if format := xtreamExtensionFormat(ext); format != "" && strings.Contains(target, "/proxy/") {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + relay.QueryParamFormat + "=" + format
+	}
+
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// serveTimeshiftPath handles GET /timeshift/{username}/{password}/{duration}/{start}/{stream}.
+func (h *XtreamOutputHandler) serveTimeshiftPath(w http.ResponseWriter, r *http.Request) {
+	username, _ := url.PathUnescape(chi.URLParam(r, "username"))
+	password, _ := url.PathUnescape(chi.URLParam(r, "password"))
+	h.serveTimeshift(w, r, username, password,
+		chi.URLParam(r, "stream"), chi.URLParam(r, "start"), chi.URLParam(r, "duration"))
+}
+
+// serveTimeshiftQuery handles GET /streaming/timeshift.php.
+func (h *XtreamOutputHandler) serveTimeshiftQuery(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	h.serveTimeshift(w, r, query.Get("username"), query.Get("password"),
+		query.Get("stream"), query.Get("start"), query.Get("duration"))
+}
+
+// serveTimeshift redirects an Xtream catch-up request to the relay catch-up route.
+// start uses the Xtream YYYY-MM-DD:HH-MM layout in the advertised server timezone
+// (UTC) and duration is in minutes.
+func (h *XtreamOutputHandler) serveTimeshift(w http.ResponseWriter, r *http.Request, username, password, streamParam, startParam, durationParam string) {
+	proxy, viewer, ok := h.authenticate(w, r, username, password)
+	if !ok {
+		return
+	}
+
+	idPart, _, _ := strings.Cut(streamParam, ".")
+	streamID, err := strconv.Atoi(idPart)
+	if err != nil {
+		http.Error(w, "invalid stream ID", http.StatusBadRequest)
+		return
+	}
+	start, err := time.ParseInLocation(xtreamTimeshiftFormat, startParam, time.UTC)
+	if err != nil {
+		http.Error(w, "invalid start, expected YYYY-MM-DD:HH-MM", http.StatusBadRequest)
+		return
+	}
+	minutes, err := strconv.Atoi(durationParam)
+	if err != nil || minutes <= 0 {
+		http.Error(w, "invalid duration", http.StatusBadRequest)
+		return
+	}
+
+	catalog, ok := h.loadCatalog(w, proxy)
+	if !ok {
+		return
+	}
+	ch, found := catalog.byStreamID[streamID]
+	if !found {
+		http.Error(w, fmt.Sprintf("stream %d not found", streamID), http.StatusNotFound)
+		return
+	}
+	if !ch.hasCatchup() {
+		http.Error(w, fmt.Sprintf("stream %d has no catch-up archive", streamID), http.StatusNotFound)
+		return
+	}
+
+	target, err := url.Parse(rebaseRelayURL(ch.entry.CatchupSource, requestBaseURL(r, h.baseURL)))
+	if err != nil {
+		http.Error(w, "invalid catch-up URL", http.StatusInternalServerError)
+		return
+	}
+	target.RawQuery = url.Values{
+		catchupParamStart:    {strconv.FormatInt(start.Unix(), 10)},
+		catchupParamDuration: {strconv.Itoa(minutes * 60)},
+	}.Encode()
+
+	http.Redirect(w, r, withStreamCredential(target.String(), h.viewers, viewer), http.StatusFound)
+}
+
+// authenticate resolves the proxy for an Xtream username/password pair, and the
+// viewer whose token is the password when viewer authentication is enabled.
+// It writes an Xtream-style auth failure and returns false if the credentials are rejected.
+func (h *XtreamOutputHandler) authenticate(w http.ResponseWriter, r *http.Request, username, password string) (*models.StreamProxy, *models.Viewer, bool) {
+	if username == "" || password == "" {
+		writeXtreamAuthFailure(w)
+		return nil, nil, false
+	}
+
+	proxy, err := h.proxyService.GetByName(r.Context(), username)
+	if err == nil && proxy == nil {
+		if id, parseErr := models.ParseULID(username); parseErr == nil {
+			proxy, err = h.proxyService.GetByID(r.Context(), id)
+		}
+	}
+	if err != nil {
+		h.logger.Error("failed to get proxy for xtream login",
+			slog.String("username", username),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to get proxy", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if proxy == nil || !models.BoolVal(proxy.IsActive) {
+		writeXtreamAuthFailure(w)
+		return nil, nil, false
+	}
+
+	if h.viewers == nil {
+		if !strings.EqualFold(password, proxy.ID.String()) {
+			writeXtreamAuthFailure(w)
+			return nil, nil, false
+		}
+		return proxy, nil, true
+	}
+
+	viewer, err := h.viewers.AuthenticateViewer(r.Context(), password)
+	if err != nil {
+		if !errors.Is(err, service.ErrStreamUnauthorized) {
+			h.logger.Error("failed to authenticate xtream viewer",
+				slog.String("username", username),
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "failed to authenticate viewer", http.StatusInternalServerError)
+			return nil, nil, false
+		}
+		writeXtreamAuthFailure(w)
+		return nil, nil, false
+	}
+	return proxy, viewer, true
+}
+
+// loadCatalog reads the proxy's generated playlist into an Xtream catalog.
+// It writes an error response and returns false if the playlist is unavailable.
+func (h *XtreamOutputHandler) loadCatalog(w http.ResponseWriter, proxy *models.StreamProxy) (*xtreamCatalog, bool) {
+	entries, err := readGeneratedM3U(h.sandbox, proxy.ID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			http.Error(w, fmt.Sprintf("playlist not generated for proxy %s", proxy.ID), http.StatusNotFound)
+			return nil, false
+		}
+		h.logger.Error("failed to read generated playlist for xtream",
+			slog.String("proxy_id", proxy.ID.String()),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to read playlist", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	var added int64
+	if proxy.LastGeneratedAt != nil {
+		added = proxy.LastGeneratedAt.Unix()
+	}
+	return buildXtreamCatalog(entries, added), true
+}
+
+// authInfo builds the player_api.php login response for a proxy account.
+func (h *XtreamOutputHandler) authInfo(r *http.Request, proxy *models.StreamProxy, viewer *models.Viewer, username, password string) xtream.AuthInfo {
+	now := time.Now().UTC()
+
+	serverURL, _ := url.Parse(requestBaseURL(r, h.baseURL))
+	scheme := "http"
+	host := r.Host
+	if serverURL != nil && serverURL.Host != "" {
+		scheme = serverURL.Scheme
+		host = serverURL.Host
+	}
+	hostname, portStr, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+		portStr = "80"
+		if scheme == "https" {
+			portStr = "443"
+		}
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	info := xtream.AuthInfo{
+		UserInfo: xtream.UserInfo{
+			Username:             username,
+			Password:             password,
+			Auth:                 1,
+			Status:               "Active",
+			CreatedAt:            xtream.FlexInt(proxy.CreatedAt.Unix()),
+			MaxConnections:       xtream.FlexInt(proxy.MaxConcurrentStreams),
+			AllowedOutputFormats: []string{"m3u8", "ts"},
+		},
+		ServerInfo: xtream.ServerInfo{
+			URL:            hostname,
+			Port:           xtream.FlexInt(port),
+			ServerProtocol: scheme,
+			Timezone:       "UTC",
+			TimestampNow:   xtream.FlexInt(now.Unix()),
+			TimeNow:        now.Format(xtreamTimeFormat),
+		},
+	}
+	if scheme == "https" {
+		info.ServerInfo.HTTPSPort = xtream.FlexInt(port)
+	}
+	if viewer != nil && viewer.ExpiresAt != nil {
+		info.UserInfo.ExpDate = xtream.FlexInt(viewer.ExpiresAt.Unix())
+	}
+	return info
+}
+
+// xtreamChannel is a playlist entry with its assigned Xtream identifiers.
+type xtreamChannel struct {
+	streamID   int
+	categoryID int
+	entry      *m3u.Entry
+}
+
+// hasCatchup reports whether the channel advertises a tvarr catch-up route.
+func (ch *xtreamChannel) hasCatchup() bool {
+	return ch.entry.CatchupDays > 0 && ch.entry.CatchupSource != ""
+}
+
+// xtreamCatalog is the Xtream view of a generated proxy playlist.
+type xtreamCatalog struct {
+	categories []xtream.Category
+	channels   []*xtreamChannel
+	byStreamID map[int]*xtreamChannel
+	added      int64
+}
+
+// buildXtreamCatalog assigns Xtream category and stream IDs to playlist entries.
+// Stream IDs are the channel numbers assigned by the numbering stage, so they stay
+// stable across regenerations; entries without a free number get one after the highest.
+// Category IDs follow the order in which groups first appear in the playlist.
+func buildXtreamCatalog(entries []*m3u.Entry, added int64) *xtreamCatalog {
+	catalog := &xtreamCatalog{
+		categories: []xtream.Category{},
+		channels:   make([]*xtreamChannel, 0, len(entries)),
+		byStreamID: make(map[int]*xtreamChannel, len(entries)),
+		added:      added,
+	}
+
+	categories := newXtreamCategories()
+	maxStreamID := 0
+	for _, entry := range entries {
+		ch := &xtreamChannel{categoryID: categories.id(entry.GroupTitle), entry: entry}
+		if n := entry.ChannelNumber; n > 0 {
+			if _, taken := catalog.byStreamID[n]; !taken {
+				ch.streamID = n
+				catalog.byStreamID[n] = ch
+				maxStreamID = max(maxStreamID, n)
+			}
+		}
+		catalog.channels = append(catalog.channels, ch)
+	}
+
+	for _, ch := range catalog.channels {
+		if ch.streamID == 0 {
+			maxStreamID++
+			ch.streamID = maxStreamID
+			catalog.byStreamID[ch.streamID] = ch
+		}
+	}
+	catalog.categories = categories.list
+
+	return catalog
+}
+
+// liveStreams returns the get_live_streams payload, optionally filtered by category.
+func (c *xtreamCatalog) liveStreams(categoryID string) []xtream.Stream {
+	streams := make([]xtream.Stream, 0, len(c.channels))
+	for i, ch := range c.channels {
+		catID := strconv.Itoa(ch.categoryID)
+		if categoryID != "" && categoryID != catID {
+			continue
+		}
+		name := ch.entry.Title
+		if name == "" {
+			name = ch.entry.TvgName
+		}
+		stream := xtream.Stream{
+			Num:          xtream.FlexInt(i + 1),
+			Name:         name,
+			StreamType:   "live",
+			StreamID:     xtream.FlexInt(ch.streamID),
+			StreamIcon:   ch.entry.TvgLogo,
+			EPGChannelID: ch.entry.TvgID,
+			Added:        xtream.FlexInt(c.added),
+			CategoryID:   xtream.FlexString(catID),
+			CategoryIDs:  []xtream.FlexInt{xtream.FlexInt(ch.categoryID)},
+		}
+		if ch.hasCatchup() {
+			stream.TVArchive = 1
+			stream.TVArchiveDays = xtream.FlexInt(ch.entry.CatchupDays)
+		}
+		streams = append(streams, stream)
+	}
+	return streams
+}
+
+// programToXtreamListing converts an EPG program to an Xtream EPG listing.
+// Titles and descriptions are base64 encoded, as Xtream clients expect.
+func programToXtreamListing(program *models.EpgProgram, streamID int, now time.Time) xtream.EPGListing {
+	var nowPlaying xtream.FlexInt
+	if !now.Before(program.Start) && now.Before(program.Stop) {
+		nowPlaying = 1
+	}
+
+	return xtream.EPGListing{
+		ID:             xtream.FlexString(program.ID.String()),
+		EPGId:          xtream.FlexString(strconv.Itoa(streamID)),
+		Title:          base64.StdEncoding.EncodeToString([]byte(program.Title)),
+		Lang:           program.Language,
+		Start:          program.Start.UTC().Format(xtreamTimeFormat),
+		End:            program.Stop.UTC().Format(xtreamTimeFormat),
+		Description:    base64.StdEncoding.EncodeToString([]byte(program.Description)),
+		ChannelID:      program.ChannelID,
+		StartTimestamp: xtream.FlexInt(program.Start.Unix()),
+		StopTimestamp:  xtream.FlexInt(program.Stop.Unix()),
+		NowPlaying:     nowPlaying,
+	}
+}
+
+// xtreamLiveURL builds the Xtream-style live stream URL for a channel.
+func xtreamLiveURL(baseURL, username, password string, streamID int, ext string) string {
+	return fmt.Sprintf("%s/live/%s/%s/%d.%s",
+		baseURL, url.PathEscape(username), url.PathEscape(password), streamID, ext)
+}
+
+// xtreamExtensionFormat maps an Xtream stream extension to a relay ?format= value.
+func xtreamExtensionFormat(ext string) string {
+	switch strings.ToLower(ext) {
+	case "ts":
+		return relay.FormatValueMPEGTS
+	case "m3u8":
+		return relay.FormatValueHLS
+	default:
+		return ""
+	}
+}
+
+// writeXtreamJSON writes a JSON response.
+func writeXtreamJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeXtreamAuthFailure writes the response Xtream clients recognise as a failed login.
+func writeXtreamAuthFailure(w http.ResponseWriter) {
+	writeXtreamJSON(w, http.StatusUnauthorized, map[string]any{
+		"user_info": map[string]any{"auth": 0},
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/pkg/m3u"
+	"github.com/jmylchreest/tvarr/pkg/xtream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildXtreamCatalog(t *testing.T) {
+	entries := []*m3u.Entry{
+		{Title: "News One", GroupTitle: "News", ChannelNumber: 101, TvgID: "news1"},
+		{Title: "Sports One", GroupTitle: "Sports", ChannelNumber: 200},
+		{Title: "News Two", GroupTitle: "News", ChannelNumber: 101},
+		{Title: "Misc"},
+	}
+
+	catalog := buildXtreamCatalog(entries, 1700000000)
+
+	require.Len(t, catalog.categories, 3)
+	assert.Equal(t, xtream.FlexString("1"), catalog.categories[0].CategoryID)
+	assert.Equal(t, "News", catalog.categories[0].CategoryName)
+	assert.Equal(t, "Sports", catalog.categories[1].CategoryName)
+	assert.Equal(t, xtreamUncategorized, catalog.categories[2].CategoryName)
+
+	require.Len(t, catalog.channels, 4)
+	assert.Equal(t, 101, catalog.channels[0].streamID)
+	assert.Equal(t, 200, catalog.channels[1].streamID)
+	// Duplicate and missing channel numbers are allocated after the highest number.
+	assert.Equal(t, 201, catalog.channels[2].streamID)
+	assert.Equal(t, 202, catalog.channels[3].streamID)
+
+	for _, ch := range catalog.channels {
+		assert.Same(t, ch, catalog.byStreamID[ch.streamID])
+	}
+}
+
+func TestXtreamCatalog_LiveStreams(t *testing.T) {
+	entries := []*m3u.Entry{
+		{Title: "News One", GroupTitle: "News", ChannelNumber: 1, TvgID: "news1", TvgLogo: "http://logo/1.png"},
+		{Title: "Sports One", GroupTitle: "Sports", ChannelNumber: 2},
+	}
+	catalog := buildXtreamCatalog(entries, 1700000000)
+
+	all := catalog.liveStreams("")
+	require.Len(t, all, 2)
+	assert.Equal(t, "News One", all[0].Name)
+	assert.Equal(t, "live", all[0].StreamType)
+	assert.Equal(t, xtream.FlexInt(1), all[0].StreamID)
+	assert.Equal(t, "news1", all[0].EPGChannelID)
+	assert.Equal(t, "http://logo/1.png", all[0].StreamIcon)
+	assert.Equal(t, xtream.FlexInt(1700000000), all[0].Added)
+
+	sports := catalog.liveStreams("2")
+	require.Len(t, sports, 1)
+	assert.Equal(t, "Sports One", sports[0].Name)
+	assert.Equal(t, xtream.FlexString("2"), sports[0].CategoryID)
+}
+
+func TestProgramToXtreamListing(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC)
+	program := &models.EpgProgram{
+		BaseModel:   models.BaseModel{ID: models.NewULID()},
+		ChannelID:   "news1",
+		Start:       now.Add(-30 * time.Minute),
+		Stop:        now.Add(30 * time.Minute),
+		Title:       "Evening News",
+		Description: "Headlines",
+		Language:    "en",
+	}
+
+	listing := programToXtreamListing(program, 101, now)
+
+	title, err := base64.StdEncoding.DecodeString(listing.Title)
+	require.NoError(t, err)
+	assert.Equal(t, "Evening News", string(title))
+	desc, err := base64.StdEncoding.DecodeString(listing.Description)
+	require.NoError(t, err)
+	assert.Equal(t, "Headlines", string(desc))
+
+	assert.Equal(t, "2025-01-01 12:00:00", listing.Start)
+	assert.Equal(t, "2025-01-01 13:00:00", listing.End)
+	assert.Equal(t, xtream.FlexInt(program.Start.Unix()), listing.StartTimestamp)
+	assert.Equal(t, xtream.FlexString("101"), listing.EPGId)
+	assert.Equal(t, xtream.FlexInt(1), listing.NowPlaying)
+
+	later := programToXtreamListing(program, 101, now.Add(time.Hour))
+	assert.Equal(t, xtream.FlexInt(0), later.NowPlaying)
+}
+
+func TestXtreamLiveURL(t *testing.T) {
+	assert.Equal(t, "http://tvarr:8080/live/My%20Proxy/secret/101.ts",
+		xtreamLiveURL("http://tvarr:8080", "My Proxy", "secret", 101, "ts"))
+}
+
+func TestXtreamExtensionFormat(t *testing.T) {
+	assert.Equal(t, relay.FormatValueMPEGTS, xtreamExtensionFormat("ts"))
+	assert.Equal(t, relay.FormatValueHLS, xtreamExtensionFormat("M3U8"))
+	assert.Empty(t, xtreamExtensionFormat(""))
+}
@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/stages/generatevod"
+	"github.com/jmylchreest/tvarr/pkg/m3u"
+	"github.com/jmylchreest/tvarr/pkg/xtream"
+)
+
+// xtreamDefaultContainer is the extension advertised for VOD streams of unknown type.
+const xtreamDefaultContainer = "mp4"
+
+// xtreamMovie is a movie playlist entry with its assigned Xtream identifiers.
+type xtreamMovie struct {
+	streamID   int
+	categoryID int
+	entry      *m3u.Entry
+}
+
+// xtreamSeries is a series rebuilt from its episode playlist entries.
+type xtreamSeries struct {
+	seriesID   int
+	categoryID int
+	name       string
+	// first is the first episode entry, which carries the series details.
+	first    *m3u.Entry
+	episodes []*xtreamEpisode
+}
+
+// xtreamEpisode is an episode playlist entry with its assigned Xtream identifier.
+type xtreamEpisode struct {
+	id      int
+	season  int
+	episode int
+	entry   *m3u.Entry
+}
+
+// xtreamVodCatalog is the Xtream view of a proxy's generated movie and series playlists.
+type xtreamVodCatalog struct {
+	movieCategories  []xtream.Category
+	movies           []*xtreamMovie
+	moviesByID       map[int]*xtreamMovie
+	seriesCategories []xtream.Category
+	series           []*xtreamSeries
+	seriesByID       map[int]*xtreamSeries
+	episodesByID     map[int]*xtreamEpisode
+	added            int64
+}
+
+// xtreamIDs allocates Xtream IDs, preferring the provider's numeric ID so they
+// stay stable across regenerations. Entries whose ID is missing or taken by an
+// earlier entry get one after the highest.
+type xtreamIDs struct {
+	taken map[int]bool
+	max   int
+}
+
+func newXtreamIDs() *xtreamIDs {
+	return &xtreamIDs{taken: make(map[int]bool)}
+}
+
+// claim takes the provider ID if it is a free positive number, returning 0 otherwise.
+func (a *xtreamIDs) claim(providerID string) int {
+	id, err := strconv.Atoi(providerID)
+	if err != nil || id <= 0 || a.taken[id] {
+		return 0
+	}
+	a.taken[id] = true
+	a.max = max(a.max, id)
+	return id
+}
+
+// next returns an ID after the highest taken.
+func (a *xtreamIDs) next() int {
+	a.max++
+	a.taken[a.max] = true
+	return a.max
+}
+
+// xtreamCategories assigns category IDs in order of first appearance.
+type xtreamCategories struct {
+	list []xtream.Category
+	ids  map[string]int
+}
+
+func newXtreamCategories() *xtreamCategories {
+	return &xtreamCategories{list: []xtream.Category{}, ids: make(map[string]int)}
+}
+
+// id returns the ID of the named category, adding it if new.
+func (c *xtreamCategories) id(name string) int {
+	if name == "" {
+		name = xtreamUncategorized
+	}
+	if id, ok := c.ids[name]; ok {
+		return id
+	}
+	id := len(c.list) + 1
+	c.ids[name] = id
+	c.list = append(c.list, xtream.Category{
+		CategoryID:   xtream.FlexString(strconv.Itoa(id)),
+		CategoryName: name,
+	})
+	return id
+}
+
+// buildXtreamVodCatalog builds the Xtream VOD catalogue from the generated
+// movie and series playlist entries. Series are rebuilt from their episodes,
+// which the series playlist lists one per entry.
+func buildXtreamVodCatalog(movieEntries, episodeEntries []*m3u.Entry, added int64) *xtreamVodCatalog {
+	catalog := &xtreamVodCatalog{
+		moviesByID:   make(map[int]*xtreamMovie, len(movieEntries)),
+		seriesByID:   make(map[int]*xtreamSeries),
+		episodesByID: make(map[int]*xtreamEpisode, len(episodeEntries)),
+		added:        added,
+	}
+
+	movieCategories := newXtreamCategories()
+	movieIDs := newXtreamIDs()
+	for _, entry := range movieEntries {
+		movie := &xtreamMovie{
+			streamID:   movieIDs.claim(entry.Extra[generatevod.AttrID]),
+			categoryID: movieCategories.id(entry.GroupTitle),
+			entry:      entry,
+		}
+		catalog.movies = append(catalog.movies, movie)
+	}
+	for _, movie := range catalog.movies {
+		if movie.streamID == 0 {
+			movie.streamID = movieIDs.next()
+		}
+		catalog.moviesByID[movie.streamID] = movie
+	}
+	catalog.movieCategories = movieCategories.list
+
+	// Episodes of a series are listed together; the series name (group title)
+	// keeps series with the same provider ID on different sources apart.
+	seriesCategories := newXtreamCategories()
+	seriesIDs := newXtreamIDs()
+	episodeIDs := newXtreamIDs()
+	seriesKeys := make(map[string]*xtreamSeries)
+	seriesProviderIDs := make(map[*xtreamSeries]string)
+	var episodes []*xtreamEpisode
+	for _, entry := range episodeEntries {
+		providerID := entry.Extra[generatevod.AttrSeriesID]
+		key := providerID + "\x00" + entry.GroupTitle
+		series, ok := seriesKeys[key]
+		if !ok {
+			series = &xtreamSeries{
+				categoryID: seriesCategories.id(entry.Extra[generatevod.AttrCategory]),
+				name:       entry.GroupTitle,
+				first:      entry,
+			}
+			seriesKeys[key] = series
+			seriesProviderIDs[series] = providerID
+			catalog.series = append(catalog.series, series)
+		}
+
+		season, _ := strconv.Atoi(entry.Extra[generatevod.AttrSeason])
+		num, _ := strconv.Atoi(entry.Extra[generatevod.AttrEpisode])
+		ep := &xtreamEpisode{
+			id:      episodeIDs.claim(entry.Extra[generatevod.AttrID]),
+			season:  season,
+			episode: num,
+			entry:   entry,
+		}
+		series.episodes = append(series.episodes, ep)
+		episodes = append(episodes, ep)
+	}
+	for _, series := range catalog.series {
+		series.seriesID = seriesIDs.claim(seriesProviderIDs[series])
+	}
+	for _, series := range catalog.series {
+		if series.seriesID == 0 {
+			series.seriesID = seriesIDs.next()
+		}
+		catalog.seriesByID[series.seriesID] = series
+	}
+	for _, ep := range episodes {
+		if ep.id == 0 {
+			ep.id = episodeIDs.next()
+		}
+		catalog.episodesByID[ep.id] = ep
+	}
+	catalog.seriesCategories = seriesCategories.list
+
+	return catalog
+}
+
+// vodStreams returns the get_vod_streams payload, optionally filtered by category.
+func (c *xtreamVodCatalog) vodStreams(categoryID string) []xtream.VODStream {
+	streams := make([]xtream.VODStream, 0, len(c.movies))
+	for i, movie := range c.movies {
+		catID := strconv.Itoa(movie.categoryID)
+		if categoryID != "" && categoryID != catID {
+			continue
+		}
+		streams = append(streams, c.vodStream(i+1, movie))
+	}
+	return streams
+}
+
+// vodStream converts a movie to its Xtream listing.
+func (c *xtreamVodCatalog) vodStream(num int, movie *xtreamMovie) xtream.VODStream {
+	stream := xtream.VODStream{
+		Num:                xtream.FlexInt(num),
+		Name:               movie.entry.Title,
+		StreamType:         "movie",
+		StreamID:           xtream.FlexInt(movie.streamID),
+		StreamIcon:         movie.entry.TvgLogo,
+		Rating:             xtream.FlexFloat(entryFloat(movie.entry, generatevod.AttrRating)),
+		Added:              xtream.FlexInt(c.added),
+		CategoryID:         xtream.FlexString(strconv.Itoa(movie.categoryID)),
+		CategoryIDs:        []xtream.FlexInt{xtream.FlexInt(movie.categoryID)},
+		ContainerExtension: vodContainer(movie.entry),
+	}
+	if movie.entry.Extra[generatevod.AttrAdult] == "1" {
+		stream.IsAdult = 1
+	}
+	return stream
+}
+
+// vodInfo returns the get_vod_info payload for a movie.
+func (c *xtreamVodCatalog) vodInfo(movie *xtreamMovie) xtream.VODInfo {
+	return xtream.VODInfo{
+		Info: xtream.VODInfoDetails{
+			MovieImage:  movie.entry.TvgLogo,
+			Rating:      xtream.FlexFloat(entryFloat(movie.entry, generatevod.AttrRating)),
+			ReleaseDate: movie.entry.Extra[generatevod.AttrYear],
+		},
+		MovieData: c.vodStream(0, movie),
+	}
+}
+
+// seriesList returns the get_series payload, optionally filtered by category.
+func (c *xtreamVodCatalog) seriesList(categoryID string) []xtream.Series {
+	list := make([]xtream.Series, 0, len(c.series))
+	for i, series := range c.series {
+		catID := strconv.Itoa(series.categoryID)
+		if categoryID != "" && categoryID != catID {
+			continue
+		}
+		list = append(list, xtream.Series{
+			Num:          xtream.FlexInt(i + 1),
+			Name:         series.name,
+			SeriesID:     xtream.FlexInt(series.seriesID),
+			Cover:        series.first.TvgLogo,
+			Genre:        series.first.Extra[generatevod.AttrGenre],
+			ReleaseDate:  series.first.Extra[generatevod.AttrYear],
+			LastModified: xtream.FlexInt(c.added),
+			Rating:       xtream.FlexFloat(entryFloat(series.first, generatevod.AttrRating)),
+			BackdropPath: []string{},
+			CategoryID:   xtream.FlexString(catID),
+			CategoryIDs:  []xtream.FlexInt{xtream.FlexInt(series.categoryID)},
+		})
+	}
+	return list
+}
+
+// seriesInfo returns the get_series_info payload, with episodes grouped by season.
+func (c *xtreamVodCatalog) seriesInfo(series *xtreamSeries) xtream.SeriesInfo {
+	info := xtream.SeriesInfo{
+		Seasons: []xtream.SeasonInfo{},
+		Info: xtream.SeriesInfoDetails{
+			Name:         series.name,
+			Cover:        series.first.TvgLogo,
+			Genre:        series.first.Extra[generatevod.AttrGenre],
+			ReleaseDate:  series.first.Extra[generatevod.AttrYear],
+			LastModified: xtream.FlexInt(c.added),
+			Rating:       xtream.FlexFloat(entryFloat(series.first, generatevod.AttrRating)),
+			BackdropPath: []string{},
+			CategoryID:   xtream.FlexString(strconv.Itoa(series.categoryID)),
+			CategoryIDs:  []xtream.FlexInt{xtream.FlexInt(series.categoryID)},
+		},
+		Episodes: make(map[string][]xtream.Episode),
+	}
+
+	var seasons []int
+	for _, ep := range series.episodes {
+		key := strconv.Itoa(ep.season)
+		if _, ok := info.Episodes[key]; !ok {
+			seasons = append(seasons, ep.season)
+		}
+		title := ep.entry.Extra[generatevod.AttrEpisodeTitle]
+		if title == "" {
+			title = ep.entry.Title
+		}
+		info.Episodes[key] = append(info.Episodes[key], xtream.Episode{
+			ID:                 xtream.FlexInt(ep.id),
+			EpisodeNum:         xtream.FlexInt(ep.episode),
+			Title:              title,
+			ContainerExtension: vodContainer(ep.entry),
+			Added:              xtream.FlexInt(c.added),
+			Season:             xtream.FlexInt(ep.season),
+		})
+	}
+	slices.Sort(seasons)
+	for _, season := range seasons {
+		info.Seasons = append(info.Seasons, xtream.SeasonInfo{
+			ID:           season,
+			Name:         fmt.Sprintf("Season %d", season),
+			SeasonNumber: season,
+			EpisodeCount: len(info.Episodes[strconv.Itoa(season)]),
+			Cover:        series.first.TvgLogo,
+		})
+	}
+	return info
+}
+
+// serveVodAction handles the VOD and series player_api.php actions.
+func (h *XtreamOutputHandler) serveVodAction(w http.ResponseWriter, r *http.Request, proxy *models.StreamProxy, action string) {
+	catalog, ok := h.loadVodCatalog(w, proxy)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	switch action {
+	case xtreamActionVODCategories:
+		writeXtreamJSON(w, http.StatusOK, catalog.movieCategories)
+	case xtreamActionVODStreams:
+		writeXtreamJSON(w, http.StatusOK, catalog.vodStreams(query.Get("category_id")))
+	case xtreamActionVODInfo:
+		id, _ := strconv.Atoi(query.Get("vod_id"))
+		movie, found := catalog.moviesByID[id]
+		if !found {
+			http.Error(w, fmt.Sprintf("movie %s not found", query.Get("vod_id")), http.StatusNotFound)
+			return
+		}
+		writeXtreamJSON(w, http.StatusOK, catalog.vodInfo(movie))
+	case xtreamActionSeriesCategories:
+		writeXtreamJSON(w, http.StatusOK, catalog.seriesCategories)
+	case xtreamActionSeries:
+		writeXtreamJSON(w, http.StatusOK, catalog.seriesList(query.Get("category_id")))
+	case xtreamActionSeriesInfo:
+		id, _ := strconv.Atoi(query.Get("series_id"))
+		series, found := catalog.seriesByID[id]
+		if !found {
+			http.Error(w, fmt.Sprintf("series %s not found", query.Get("series_id")), http.StatusNotFound)
+			return
+		}
+		writeXtreamJSON(w, http.StatusOK, catalog.seriesInfo(series))
+	}
+}
+
+// serveMovie handles GET /movie/{username}/{password}/{stream}, redirecting to
+// the provider's stream like the movie playlist does.
+func (h *XtreamOutputHandler) serveMovie(w http.ResponseWriter, r *http.Request) {
+	h.serveVodStream(w, r, func(catalog *xtreamVodCatalog, id int) *m3u.Entry {
+		if movie, ok := catalog.moviesByID[id]; ok {
+			return movie.entry
+		}
+		return nil
+	})
+}
+
+// serveEpisode handles GET /series/{username}/{password}/{stream}, redirecting
+// to the provider's stream like the series playlist does.
+func (h *XtreamOutputHandler) serveEpisode(w http.ResponseWriter, r *http.Request) {
+	h.serveVodStream(w, r, func(catalog *xtreamVodCatalog, id int) *m3u.Entry {
+		if ep, ok := catalog.episodesByID[id]; ok {
+			return ep.entry
+		}
+		return nil
+	})
+}
+
+// serveVodStream resolves a movie or episode stream ID and redirects to its URL.
+func (h *XtreamOutputHandler) serveVodStream(w http.ResponseWriter, r *http.Request, lookup func(*xtreamVodCatalog, int) *m3u.Entry) {
+	username, _ := url.PathUnescape(chi.URLParam(r, "username"))
+	password, _ := url.PathUnescape(chi.URLParam(r, "password"))
+
+	proxy, _, ok := h.authenticate(w, r, username, password)
+	if !ok {
+		return
+	}
+
+	idPart, _, _ := strings.Cut(chi.URLParam(r, "stream"), ".")
+	streamID, err := strconv.Atoi(idPart)
+	if err != nil {
+		http.Error(w, "invalid stream ID", http.StatusBadRequest)
+		return
+	}
+
+	catalog, ok := h.loadVodCatalog(w, proxy)
+	if !ok {
+		return
+	}
+	entry := lookup(catalog, streamID)
+	if entry == nil {
+		http.Error(w, fmt.Sprintf("stream %d not found", streamID), http.StatusNotFound)
+		return
+	}
+
+	http.Redirect(w, r, entry.URL, http.StatusFound)
+}
+
+// loadVodCatalog reads the proxy's generated movie and series playlists into
+// an Xtream catalogue. A proxy without VOD output has an empty catalogue.
+// It writes an error response and returns false if a playlist cannot be read.
+func (h *XtreamOutputHandler) loadVodCatalog(w http.ResponseWriter, proxy *models.StreamProxy) (*xtreamVodCatalog, bool) {
+	var playlists [2][]*m3u.Entry
+	for i, ext := range []string{".vod.m3u", ".series.m3u"} {
+		entries, err := readGeneratedPlaylist(h.sandbox, proxy.ID, ext)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			h.logger.Error("failed to read generated VOD playlist for xtream",
+				slog.String("proxy_id", proxy.ID.String()),
+				slog.String("playlist", ext),
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "failed to read VOD playlist", http.StatusInternalServerError)
+			return nil, false
+		}
+		playlists[i] = entries
+	}
+
+	var added int64
+	if proxy.LastGeneratedAt != nil {
+		added = proxy.LastGeneratedAt.Unix()
+	}
+	return buildXtreamVodCatalog(playlists[0], playlists[1], added), true
+}
+
+// entryFloat returns a numeric extra attribute of an entry, or 0.
+func entryFloat(entry *m3u.Entry, attr string) float64 {
+	f, _ := strconv.ParseFloat(entry.Extra[attr], 64)
+	return f
+}
+
+// vodContainer returns the container extension of a VOD entry, falling back
+// to the extension of its URL.
+func vodContainer(entry *m3u.Entry) string {
+	if ext := entry.Extra[generatevod.AttrContainer]; ext != "" {
+		return ext
+	}
+	if u, err := url.Parse(entry.URL); err == nil {
+		if ext := strings.TrimPrefix(path.Ext(u.Path), "."); ext != "" {
+			return ext
+		}
+	}
+	return xtreamDefaultContainer
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/jmylchreest/tvarr/pkg/m3u"
+	"github.com/jmylchreest/tvarr/pkg/xtream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildXtreamVodCatalog_Movies(t *testing.T) {
+	movies := []*m3u.Entry{
+		{Title: "Heat", GroupTitle: "Action", TvgLogo: "http://img/heat.jpg", URL: "http://up/movie/u/p/10.mkv",
+			Extra: map[string]string{"tvarr-id": "10", "tvarr-container": "mkv", "tvarr-rating": "8.3", "tvarr-year": "1995"}},
+		{Title: "Frozen", GroupTitle: "Kids", URL: "http://up/movie/u/p/10.mp4",
+			Extra: map[string]string{"tvarr-id": "10"}},
+		{Title: "Untitled", URL: "http://up/stream"},
+	}
+
+	catalog := buildXtreamVodCatalog(movies, nil, 1700000000)
+
+	require.Len(t, catalog.movieCategories, 3)
+	assert.Equal(t, "Action", catalog.movieCategories[0].CategoryName)
+	assert.Equal(t, xtreamUncategorized, catalog.movieCategories[2].CategoryName)
+
+	// Provider IDs are kept; duplicate and missing ones are allocated after the highest.
+	require.Len(t, catalog.movies, 3)
+	assert.Equal(t, 10, catalog.movies[0].streamID)
+	assert.Equal(t, 11, catalog.movies[1].streamID)
+	assert.Equal(t, 12, catalog.movies[2].streamID)
+
+	streams := catalog.vodStreams("")
+	require.Len(t, streams, 3)
+	assert.Equal(t, "Heat", streams[0].Name)
+	assert.Equal(t, "movie", streams[0].StreamType)
+	assert.Equal(t, xtream.FlexInt(10), streams[0].StreamID)
+	assert.Equal(t, xtream.FlexFloat(8.3), streams[0].Rating)
+	assert.Equal(t, "mkv", streams[0].ContainerExtension)
+	assert.Equal(t, "mp4", streams[1].ContainerExtension, "falls back to the URL extension")
+	assert.Equal(t, xtreamDefaultContainer, streams[2].ContainerExtension)
+	assert.Equal(t, xtream.FlexInt(1700000000), streams[0].Added)
+
+	kids := catalog.vodStreams("2")
+	require.Len(t, kids, 1)
+	assert.Equal(t, "Frozen", kids[0].Name)
+
+	info := catalog.vodInfo(catalog.moviesByID[10])
+	assert.Equal(t, "1995", info.Info.ReleaseDate)
+	assert.Equal(t, "http://img/heat.jpg", info.Info.MovieImage)
+	assert.Equal(t, xtream.FlexInt(10), info.MovieData.StreamID)
+}
+
+func TestBuildXtreamVodCatalog_Series(t *testing.T) {
+	episode := func(seriesID, name, category, id, season, num, title string) *m3u.Entry {
+		return &m3u.Entry{
+			Title:      name + " episode",
+			GroupTitle: name,
+			TvgLogo:    "http://img/" + seriesID + ".jpg",
+			URL:        "http://up/series/u/p/" + id + ".mp4",
+			Extra: map[string]string{
+				"tvarr-series-id":     seriesID,
+				"tvarr-category":      category,
+				"tvarr-id":            id,
+				"tvarr-season":        season,
+				"tvarr-episode":       num,
+				"tvarr-episode-title": title,
+				"tvarr-genre":         "Crime",
+			},
+		}
+	}
+	episodes := []*m3u.Entry{
+		episode("70", "The Wire", "Drama", "701", "1", "1", "The Target"),
+		episode("70", "The Wire", "Drama", "702", "1", "2", "The Detail"),
+		episode("70", "The Wire", "Drama", "801", "2", "1", "Ebb Tide"),
+		// Same provider series ID on another source.
+		episode("70", "Lost", "Adventure", "701", "1", "1", "Pilot"),
+	}
+
+	catalog := buildXtreamVodCatalog(nil, episodes, 1700000000)
+
+	require.Len(t, catalog.seriesCategories, 2)
+	assert.Equal(t, "Drama", catalog.seriesCategories[0].CategoryName)
+
+	list := catalog.seriesList("")
+	require.Len(t, list, 2)
+	assert.Equal(t, "The Wire", list[0].Name)
+	assert.Equal(t, xtream.FlexInt(70), list[0].SeriesID)
+	assert.Equal(t, "Crime", list[0].Genre)
+	assert.Equal(t, "Lost", list[1].Name)
+	assert.Equal(t, xtream.FlexInt(71), list[1].SeriesID)
+	assert.Len(t, catalog.seriesList("2"), 1)
+
+	info := catalog.seriesInfo(catalog.seriesByID[70])
+	assert.Equal(t, "The Wire", info.Info.Name)
+	require.Len(t, info.Seasons, 2)
+	assert.Equal(t, 1, info.Seasons[0].SeasonNumber)
+	assert.Equal(t, 2, info.Seasons[0].EpisodeCount)
+	require.Len(t, info.Episodes["1"], 2)
+	assert.Equal(t, "The Detail", info.Episodes["1"][1].Title)
+	assert.Equal(t, xtream.FlexInt(702), info.Episodes["1"][1].ID)
+	assert.Equal(t, "mp4", info.Episodes["1"][1].ContainerExtension)
+
+	// Episode IDs are unique across series.
+	lost := catalog.seriesInfo(catalog.seriesByID[71])
+	assert.Equal(t, xtream.FlexInt(802), lost.Episodes["1"][0].ID)
+	assert.Equal(t, "http://up/series/u/p/701.mp4", catalog.episodesByID[802].entry.URL)
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/jmylchreest/tvarr/internal/auth"
+)
+
+// apiPathPrefix is the path prefix of operations protected by authentication.
+const apiPathPrefix = "/api/v1"
+
+// Authenticator resolves request credentials to a principal.
+type Authenticator interface {
+	// AuthenticateSession resolves a UI session token.
+	AuthenticateSession(ctx context.Context, token string) (*auth.Principal, error)
+	// AuthenticateAPIKey resolves an API key.
+	AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
+
+// NewHumaAuth returns a huma middleware that requires a valid session or API key
+// for every /api/v1 operation, and enforces the scope required by the operation.
+// Operations with the auth.OperationPublicMetadata metadata flag are skipped.
+// The middleware must be added before operations are registered.
+func NewHumaAuth(api huma.API, a Authenticator, logger *slog.Logger) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		if op == nil || !strings.HasPrefix(op.Path, apiPathPrefix) || isPublicOperation(op) {
+			next(ctx)
+			return
+		}
+
+		principal, err := authenticateRequest(
+			ctx.Context(), a,
+			ctx.Header("Authorization"),
+			ctx.Header(auth.APIKeyHeader),
+			ctx.Header("Cookie"),
+		)
+		if err == nil && !principal.Scopes.Allows(auth.RequiredScope(op.Method, op.Path)) {
+			err = auth.ErrForbidden
+		}
+		if err != nil {
+			status, message := authErrorStatus(err)
+			if status == http.StatusInternalServerError {
+				logger.ErrorContext(ctx.Context(), "authentication failed",
+					slog.String("path", op.Path),
+					slog.String("error", err.Error()),
+				)
+			}
+			_ = huma.WriteErr(api, ctx, status, message)
+			return
+		}
+
+		next(huma.WithContext(ctx, auth.WithPrincipal(ctx.Context(), principal)))
+	}
+}
+
+// RequireAuth returns a chi-compatible middleware with the same semantics as
+// NewHumaAuth, for raw routes that are not registered through huma
+// (file downloads, uploads and SSE streams).
+func RequireAuth(a Authenticator, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticateRequest(
+				r.Context(), a,
+				r.Header.Get("Authorization"),
+				r.Header.Get(auth.APIKeyHeader),
+				r.Header.Get("Cookie"),
+			)
+			if err == nil && !principal.Scopes.Allows(auth.RequiredScope(r.Method, r.URL.Path)) {
+				err = auth.ErrForbidden
+			}
+			if err != nil {
+				status, message := authErrorStatus(err)
+				if status == http.StatusInternalServerError {
+					logger.ErrorContext(r.Context(), "authentication failed",
+						slog.String("path", r.URL.Path),
+						slog.String("error", err.Error()),
+					)
+				}
+				http.Error(w, message, status)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// authenticateRequest resolves the credentials carried by a request.
+// An X-API-Key header, or a bearer token with the API key prefix, is treated as
+// an API key; any other bearer token or the session cookie as a session token.
+func authenticateRequest(ctx context.Context, a Authenticator, authorization, apiKeyHeader, cookieHeader string) (*auth.Principal, error) {
+	if apiKeyHeader != "" {
+		return a.AuthenticateAPIKey(ctx, apiKeyHeader)
+	}
+	if token := auth.BearerToken(authorization); token != "" {
+		if auth.IsAPIKey(token) {
+			return a.AuthenticateAPIKey(ctx, token)
+		}
+		return a.AuthenticateSession(ctx, token)
+	}
+	if token := auth.SessionTokenFromCookieHeader(cookieHeader); token != "" {
+		return a.AuthenticateSession(ctx, token)
+	}
+	return nil, auth.ErrUnauthenticated
+}
+
+// authErrorStatus maps an authentication error to an HTTP status and message.
+func authErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return http.StatusUnauthorized, auth.ErrUnauthenticated.Error()
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden, auth.ErrForbidden.Error()
+	default:
+		return http.StatusInternalServerError, "authentication failed"
+	}
+}
+
+// isPublicOperation reports whether an operation is exempt from authentication.
+func isPublicOperation(op *huma.Operation) bool {
+	public, _ := op.Metadata[auth.OperationPublicMetadata].(bool)
+	return public
+}
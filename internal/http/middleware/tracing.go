@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing is a middleware that starts a span for each request, continuing any
+// trace context sent by the client. Once chi has routed the request, the span
+// is named after the route pattern rather than the raw path, so that requests
+// for different channels and proxies group together.
+func Tracing(next http.Handler) http.Handler {
+	named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if pattern := routePattern(r); pattern != "" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(spanName(r))
+			span.SetAttributes(semconv.HTTPRoute(pattern))
+		}
+	})
+	return otelhttp.NewHandler(named, "http.request",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return spanName(r)
+		}),
+	)
+}
+
+// spanName returns the method and, once routed, the route pattern of a request.
+func spanName(r *http.Request) string {
+	if pattern := routePattern(r); pattern != "" {
+		return r.Method + " " + pattern
+	}
+	return r.Method
+}
+
+// routePattern returns the chi route pattern a request matched, or "" before routing.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
@@ -57,6 +57,7 @@ func NewServer(config ServerConfig, logger *slog.Logger, version string) *Server
 	// Apply middleware
 	router.Use(chimiddleware.RealIP)
 	router.Use(middleware.RequestID)
+	router.Use(middleware.Tracing)
 	router.Use(middleware.NewLoggingMiddleware(logger))
 	router.Use(middleware.Recovery(logger))
 	router.Use(middleware.CORS())
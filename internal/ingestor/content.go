@@ -0,0 +1,79 @@
+package ingestor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jmylchreest/tvarr/internal/urlutil"
+)
+
+// ErrSourceUnchanged is returned by handlers when a source's content is the same
+// as at its last successful ingestion. Nothing is yielded in that case, and the
+// previously ingested data remains current.
+var ErrSourceUnchanged = errors.New("source content unchanged")
+
+// contentVersion identifies the content a source was last ingested from.
+type contentVersion struct {
+	urlutil.Validators
+	Hash string
+}
+
+// fetchIfChanged fetches a source's content unless it is unchanged since prev:
+// either the server or file reports it not modified, or the body has the same
+// SHA-256 digest. The body is spooled to a temporary file while it is hashed so
+// unchanged content is never parsed; the returned reader removes the file when
+// closed.
+func fetchIfChanged(ctx context.Context, fetcher *urlutil.ResourceFetcher, u string, prev contentVersion) (io.ReadCloser, contentVersion, error) {
+	body, validators, err := fetcher.FetchIfModified(ctx, u, prev.Validators)
+	if errors.Is(err, urlutil.ErrNotModified) {
+		return nil, prev, ErrSourceUnchanged
+	}
+	if err != nil {
+		return nil, contentVersion{}, err
+	}
+	defer body.Close()
+
+	spool, err := os.CreateTemp("", "tvarr-ingest-*")
+	if err != nil {
+		return nil, contentVersion{}, fmt.Errorf("creating spool file: %w", err)
+	}
+	file := &spoolFile{File: spool}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(spool, hasher), body); err != nil {
+		_ = file.Close()
+		return nil, contentVersion{}, fmt.Errorf("downloading content: %w", err)
+	}
+
+	current := contentVersion{Validators: validators, Hash: hex.EncodeToString(hasher.Sum(nil))}
+	if prev.Hash != "" && prev.Hash == current.Hash {
+		_ = file.Close()
+		return nil, current, ErrSourceUnchanged
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, contentVersion{}, fmt.Errorf("rewinding spool file: %w", err)
+	}
+
+	return file, current, nil
+}
+
+// spoolFile is a temporary file that is removed when closed.
+type spoolFile struct {
+	*os.File
+}
+
+// Close closes and removes the file.
+func (f *spoolFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -1,6 +1,7 @@
 package ingestor
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -25,6 +26,7 @@ func NewHandlerFactory() *HandlerFactory {
 	// Register default handlers that don't require external dependencies
 	f.Register(NewM3UHandler())
 	f.Register(NewXtreamHandler())
+	f.Register(NewStalkerHandler())
 
 	return f
 }
@@ -61,6 +63,21 @@ func (f *HandlerFactory) GetForSource(source *models.StreamSource) (SourceHandle
 	return f.Get(source.Type)
 }
 
+// ResolveStreamURL returns a playable URL for a stored channel stream URL of a
+// source. URLs of sources whose handler is not a StreamResolver are returned
+// unchanged.
+func (f *HandlerFactory) ResolveStreamURL(ctx context.Context, source *models.StreamSource, streamURL string) (string, error) {
+	handler, err := f.GetForSource(source)
+	if err != nil {
+		return streamURL, nil
+	}
+	resolver, ok := handler.(StreamResolver)
+	if !ok {
+		return streamURL, nil
+	}
+	return resolver.ResolveStreamURL(ctx, source, streamURL)
+}
+
 // SupportedTypes returns all registered source types.
 func (f *HandlerFactory) SupportedTypes() []models.SourceType {
 	f.mu.RLock()
@@ -88,6 +105,7 @@ func NewEpgHandlerFactory() *EpgHandlerFactory {
 	// Register default handlers
 	f.Register(NewXMLTVHandler())
 	f.Register(NewXtreamEpgHandler())
+	f.Register(NewStalkerEpgHandler())
 
 	return f
 }
@@ -10,10 +10,10 @@ import (
 func TestHandlerFactory_NewFactory(t *testing.T) {
 	f := NewHandlerFactory()
 
-	// Should have M3U and Xtream handlers registered by default
+	// Should have M3U, Xtream and Stalker handlers registered by default
 	types := f.SupportedTypes()
-	if len(types) != 2 {
-		t.Errorf("expected 2 handlers, got %d", len(types))
+	if len(types) != 3 {
+		t.Errorf("expected 3 handlers, got %d", len(types))
 	}
 
 	// Verify M3U handler
@@ -133,10 +133,10 @@ func TestHandlerFactory_SupportedTypes(t *testing.T) {
 func TestEpgHandlerFactory_NewFactory(t *testing.T) {
 	f := NewEpgHandlerFactory()
 
-	// Should have XMLTV and Xtream handlers registered by default
+	// Should have XMLTV, Xtream and Stalker handlers registered by default
 	types := f.SupportedTypes()
-	if len(types) != 2 {
-		t.Errorf("expected 2 EPG handlers, got %d", len(types))
+	if len(types) != 3 {
+		t.Errorf("expected 3 EPG handlers, got %d", len(types))
 	}
 
 	// Verify XMLTV handler
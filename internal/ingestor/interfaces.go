@@ -22,6 +22,14 @@ type SourceHandler interface {
 	Validate(source *models.StreamSource) error
 }
 
+// StreamResolver is implemented by stream handlers whose channel stream URLs are
+// not directly playable, such as portals that issue short-lived links. The
+// stored URL is resolved to a playable one immediately before playback.
+type StreamResolver interface {
+	// ResolveStreamURL returns a playable URL for a stored channel stream URL.
+	ResolveStreamURL(ctx context.Context, source *models.StreamSource, streamURL string) (string, error)
+}
+
 // ChannelCallback is called for each channel during ingestion.
 // Returning an error stops the ingestion process.
 type ChannelCallback func(channel *models.Channel) error
@@ -44,6 +52,40 @@ type EpgHandler interface {
 // Returning an error stops the ingestion process.
 type ProgramCallback func(program *models.EpgProgram) error
 
+// EpgChannelCallback is called for each channel definition during EPG ingestion.
+// Returning an error stops the ingestion process.
+type EpgChannelCallback func(channel *models.EpgChannel) error
+
+// EpgChannelHandler is implemented by EPG handlers that can also yield the
+// source's channel definitions, which are used to match stream channels by name.
+type EpgChannelHandler interface {
+	EpgHandler
+
+	// IngestWithChannels behaves like Ingest, additionally calling onChannel for
+	// each channel definition. A nil onChannel is ignored.
+	IngestWithChannels(ctx context.Context, source *models.EpgSource, onChannel EpgChannelCallback, callback ProgramCallback) error
+}
+
+// VodCallback is called for each movie during VOD ingestion.
+// Returning an error stops the ingestion process.
+type VodCallback func(item *models.VodItem) error
+
+// SeriesCallback is called for each series during VOD ingestion.
+// Returning an error stops the ingestion process.
+type SeriesCallback func(series *models.Series) error
+
+// VodHandler is implemented by stream handlers whose sources also publish video
+// on demand catalogues of movies and series.
+type VodHandler interface {
+	// IngestMovies yields the source's movies via the callback.
+	IngestMovies(ctx context.Context, source *models.StreamSource, callback VodCallback) error
+
+	// IngestSeries yields the source's series via the callback. Episodes take one
+	// request per series, so they are only fetched for series for which
+	// needEpisodes returns true; other series are yielded with nil Episodes.
+	IngestSeries(ctx context.Context, source *models.StreamSource, needEpisodes func(*models.Series) bool, callback SeriesCallback) error
+}
+
 // Fetcher defines how to retrieve source content.
 type Fetcher interface {
 	// Fetch retrieves content from a URL and returns a reader.
@@ -3,6 +3,7 @@ package ingestor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -83,9 +84,17 @@ func (h *M3UHandler) Ingest(ctx context.Context, source *models.StreamSource, ca
 
 	cfg := httpclient.DefaultConfig()
 	cfg.Timeout = defaultM3UTimeout
+	cfg.ProxyURL = source.ProxyURL
 	fetcher := urlutil.NewResourceFetcherWithBreaker(cfg, breaker)
 
-	body, err := fetcher.Fetch(ctx, source.URL)
+	prev := contentVersion{
+		Validators: urlutil.Validators{ETag: source.ETag, LastModified: source.LastModified},
+		Hash:       source.ContentHash,
+	}
+	body, version, err := fetchIfChanged(ctx, fetcher, source.URL, prev)
+	if errors.Is(err, ErrSourceUnchanged) {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("fetching M3U: %w", err)
 	}
@@ -111,6 +120,10 @@ func (h *M3UHandler) Ingest(ctx context.Context, source *models.StreamSource, ca
 		return fmt.Errorf("parsing M3U: %w", err)
 	}
 
+	source.ETag = version.ETag
+	source.LastModified = version.LastModified
+	source.ContentHash = version.Hash
+
 	return nil
 }
 
@@ -129,6 +142,18 @@ func (h *M3UHandler) entryToChannel(entry *m3u.Entry, sourceID models.ULID) *mod
 
 	channel.ExtID = h.generateExtID(entry)
 
+	if entry.CatchupDays > 0 {
+		if catchupSource := m3u.ResolveCatchupSource(entry.Catchup, entry.CatchupSource, entry.URL); catchupSource != "" {
+			channel.CatchupDays = entry.CatchupDays
+			channel.CatchupSource = catchupSource
+		}
+	}
+
+	// Headers that could not be sent as-is are dropped rather than failing the stream
+	if models.ValidateHeaders(entry.Headers) == nil {
+		channel.StreamHeaders = models.EncodeHeaders(entry.Headers)
+	}
+
 	if len(entry.Extra) > 0 {
 		if extraJSON, err := json.Marshal(entry.Extra); err == nil {
 			channel.Extra = string(extraJSON)
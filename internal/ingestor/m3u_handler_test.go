@@ -183,6 +183,57 @@ http://stream.example.com/sports2.m3u8
 	}
 }
 
+func TestM3UHandler_Ingest_Unchanged(t *testing.T) {
+	m3uContent := "#EXTM3U\n#EXTINF:-1 tvg-id=\"ch1\",Channel 1\nhttp://stream.example.com/1.m3u8\n"
+	etag := `"v1"`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(m3uContent))
+	}))
+	defer server.Close()
+
+	h := NewM3UHandler()
+	source := &models.StreamSource{
+		BaseModel: models.BaseModel{ID: models.NewULID()},
+		Name:      "Test Source",
+		Type:      models.SourceTypeM3U,
+		URL:       server.URL,
+	}
+
+	var count int
+	callback := func(ch *models.Channel) error {
+		count++
+		return nil
+	}
+
+	if err := h.Ingest(context.Background(), source, callback); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 || source.ETag != etag || source.ContentHash == "" {
+		t.Fatalf("expected 1 channel and a recorded version, got %d channels, etag %q, hash %q", count, source.ETag, source.ContentHash)
+	}
+
+	// The server reports the playlist not modified
+	if err := h.Ingest(context.Background(), source, callback); !errors.Is(err, ErrSourceUnchanged) {
+		t.Errorf("expected ErrSourceUnchanged for 304, got %v", err)
+	}
+
+	// Without validators, the identical body is detected by its digest
+	source.ETag = ""
+	if err := h.Ingest(context.Background(), source, callback); !errors.Is(err, ErrSourceUnchanged) {
+		t.Errorf("expected ErrSourceUnchanged for identical content, got %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("expected no channels from unchanged content, got %d more", count-1)
+	}
+}
+
 func TestM3UHandler_Ingest_CallbackError(t *testing.T) {
 	m3uContent := `#EXTM3U
 #EXTINF:-1,Channel 1
@@ -465,6 +516,39 @@ http://example.com/stream.m3u8
 	}
 }
 
+func TestM3UHandler_StreamHeaders(t *testing.T) {
+	m3uContent := `#EXTM3U
+#EXTINF:-1 tvg-id="ch1",Protected
+#EXTVLCOPT:http-referrer=https://example.com/
+http://example.com/protected.m3u8
+#EXTINF:-1 tvg-id="ch2",Broken
+#EXTHTTP:{"Bad Header":"x"}
+http://example.com/broken.m3u8
+`
+
+	h := NewM3UHandler()
+	var channels []*models.Channel
+	err := h.IngestFromReader(context.Background(), strings.NewReader(m3uContent), models.NewULID(), func(ch *models.Channel) error {
+		channels = append(channels, ch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(channels))
+	}
+
+	if referer := channels[0].RequestHeaders().Get("Referer"); referer != "https://example.com/" {
+		t.Errorf("expected Referer header, got %q", referer)
+	}
+
+	// Headers that cannot be sent are dropped
+	if channels[1].StreamHeaders != "" {
+		t.Errorf("expected no stream headers, got %q", channels[1].StreamHeaders)
+	}
+}
+
 func TestExtractNameFromURL(t *testing.T) {
 	tests := []struct {
 		url      string
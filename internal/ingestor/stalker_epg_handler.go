@@ -0,0 +1,163 @@
+package ingestor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// StalkerEpgHandler handles EPG ingestion from Stalker / Ministra portals.
+type StalkerEpgHandler struct {
+	DaysToFetch int
+	logger      *slog.Logger
+}
+
+// NewStalkerEpgHandler creates a new Stalker EPG handler with default settings.
+func NewStalkerEpgHandler() *StalkerEpgHandler {
+	return &StalkerEpgHandler{
+		DaysToFetch: defaultDaysToFetch,
+	}
+}
+
+// WithDaysToFetch sets the number of days of EPG data to fetch.
+func (h *StalkerEpgHandler) WithDaysToFetch(days int) *StalkerEpgHandler {
+	h.DaysToFetch = days
+	return h
+}
+
+// WithLogger sets a structured logger for the handler.
+func (h *StalkerEpgHandler) WithLogger(logger *slog.Logger) *StalkerEpgHandler {
+	h.logger = logger
+	return h
+}
+
+// Type returns the EPG source type this handler supports.
+func (h *StalkerEpgHandler) Type() models.EpgSourceType {
+	return models.EpgSourceTypeStalker
+}
+
+// Validate checks if the EPG source configuration is valid for Stalker.
+func (h *StalkerEpgHandler) Validate(source *models.EpgSource) error {
+	if source == nil {
+		return fmt.Errorf("source is nil")
+	}
+	if source.Type != models.EpgSourceTypeStalker {
+		return fmt.Errorf("invalid source type: expected %s, got %s", models.EpgSourceTypeStalker, source.Type)
+	}
+	if source.URL == "" {
+		return fmt.Errorf("URL is required for Stalker EPG sources")
+	}
+	if !strings.HasPrefix(source.URL, httpSchemePrefix) && !strings.HasPrefix(source.URL, httpsSchemePrefix) {
+		return fmt.Errorf("URL must be an HTTP(S) URL")
+	}
+	if source.MacAddress == "" {
+		return fmt.Errorf("MAC address is required for Stalker EPG sources")
+	}
+	return nil
+}
+
+// Ingest fetches EPG data from the portal and yields programs via the callback.
+func (h *StalkerEpgHandler) Ingest(ctx context.Context, source *models.EpgSource, callback ProgramCallback) error {
+	return h.IngestWithChannels(ctx, source, nil, callback)
+}
+
+// IngestWithChannels fetches the portal's channels and EPG, yielding each
+// channel via onChannel and programs via the callback. Programs are keyed by the
+// same channel IDs the Stalker stream handler assigns as tvg-id.
+func (h *StalkerEpgHandler) IngestWithChannels(ctx context.Context, source *models.EpgSource, onChannel EpgChannelCallback, callback ProgramCallback) error {
+	if err := h.Validate(source); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	client := newStalkerClient(source.URL, source.MacAddress, source.UserAgent, source.ProxyURL, "stalker-epg")
+
+	channels, err := client.GetAllChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch channels: %w", err)
+	}
+
+	tvgIDs := make(map[string]string, len(channels))
+	for i := range channels {
+		ch := &channels[i]
+		tvgID := stalkerTvgID(ch)
+		tvgIDs[ch.ID.String()] = tvgID
+
+		if onChannel == nil {
+			continue
+		}
+		epgChannel := &models.EpgChannel{
+			SourceID:  source.ID,
+			ChannelID: tvgID,
+			Icon:      stalkerLogo(ch.Logo),
+		}
+		epgChannel.SetNames([]string{ch.Name})
+		if err := onChannel(epgChannel); err != nil {
+			return err
+		}
+	}
+
+	days := h.DaysToFetch
+	if days <= 0 {
+		days = defaultDaysToFetch
+	}
+	epg, err := client.GetEPGInfo(ctx, days*24)
+	if err != nil {
+		return fmt.Errorf("failed to fetch EPG: %w", err)
+	}
+
+	if source.EpgShift != 0 {
+		LogTimezoneNormalization(h.logger, source.Name, source.DetectedTimezone, source.EpgShift)
+	}
+
+	var unknown int
+	for chID, entries := range epg {
+		tvgID, ok := tvgIDs[chID]
+		if !ok {
+			unknown++
+			continue
+		}
+
+		for i := range entries {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			entry := &entries[i]
+			program := &models.EpgProgram{
+				SourceID:    source.ID,
+				ChannelID:   tvgID,
+				Title:       entry.Name,
+				Description: entry.Description,
+				Category:    entry.Category,
+				// Portal timestamps are Unix times, so only the user shift applies.
+				Start: NormalizeProgramTime(entry.StartTime(), 0, source.EpgShift),
+				Stop:  NormalizeProgramTime(entry.StopTime(), 0, source.EpgShift),
+			}
+
+			if err := program.Validate(); err != nil {
+				continue
+			}
+
+			if err := callback(program); err != nil {
+				return fmt.Errorf("callback error: %w", err)
+			}
+		}
+	}
+
+	if unknown > 0 && h.logger != nil {
+		h.logger.Debug("skipped EPG for channels not in the portal channel list",
+			slog.String("source_name", source.Name),
+			slog.Int("channels", unknown),
+		)
+	}
+
+	return nil
+}
+
+// Ensure StalkerEpgHandler implements EpgChannelHandler.
+var _ EpgChannelHandler = (*StalkerEpgHandler)(nil)
@@ -0,0 +1,76 @@
+package ingestor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+func TestStalkerEpgHandler_Validate(t *testing.T) {
+	h := NewStalkerEpgHandler()
+
+	valid := &models.EpgSource{Type: models.EpgSourceTypeStalker, URL: "http://example.com/c/", MacAddress: "00:1A:79:00:00:01"}
+	if err := h.Validate(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	invalid := []*models.EpgSource{
+		nil,
+		{Type: models.EpgSourceTypeXMLTV, URL: "http://example.com/c/", MacAddress: "00:1A:79:00:00:01"},
+		{Type: models.EpgSourceTypeStalker, URL: "ftp://example.com/c/", MacAddress: "00:1A:79:00:00:01"},
+		{Type: models.EpgSourceTypeStalker, URL: "http://example.com/c/"},
+	}
+	for i, source := range invalid {
+		if err := h.Validate(source); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestStalkerEpgHandler_IngestWithChannels(t *testing.T) {
+	server, _ := newStalkerTestPortal(t)
+	source := &models.EpgSource{
+		BaseModel:  models.BaseModel{ID: models.NewULID()},
+		Name:       "portal",
+		Type:       models.EpgSourceTypeStalker,
+		URL:        server.URL + "/c/",
+		MacAddress: "00:1A:79:00:00:01",
+		EpgShift:   1,
+	}
+
+	var channels []*models.EpgChannel
+	programs := make(map[string]*models.EpgProgram)
+	err := NewStalkerEpgHandler().IngestWithChannels(context.Background(), source,
+		func(ch *models.EpgChannel) error {
+			channels = append(channels, ch)
+			return nil
+		},
+		func(p *models.EpgProgram) error {
+			programs[p.ChannelID] = p
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(channels) != 3 || channels[0].ChannelID != "news1.uk" || channels[0].DisplayName != "News One" {
+		t.Errorf("unexpected channels: %+v", channels)
+	}
+
+	// Programs use the stream handler's tvg-ids; unknown portal channels are skipped
+	if len(programs) != 2 {
+		t.Fatalf("expected programs for 2 channels, got %d", len(programs))
+	}
+	news := programs["news1.uk"]
+	if news == nil || news.Title != "Headlines" || news.Category != "News" {
+		t.Fatalf("unexpected program: %+v", news)
+	}
+	if want := time.Unix(1700000000, 0).UTC().Add(time.Hour); !news.Start.Equal(want) {
+		t.Errorf("expected shifted start %v, got %v", want, news.Start)
+	}
+	if programs["11"] == nil {
+		t.Error("expected program keyed by portal channel ID")
+	}
+}
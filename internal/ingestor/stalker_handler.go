@@ -0,0 +1,243 @@
+package ingestor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/pkg/httpclient"
+	"github.com/jmylchreest/tvarr/pkg/stalker"
+)
+
+// Stalker handler configuration defaults.
+const (
+	defaultStalkerTimeout = 2 * time.Minute
+
+	// stalkerLinkTTL is how long a resolved link is reused. Starting a stream
+	// resolves its URL more than once in quick succession; links are reused
+	// briefly so the portal is asked once, well within their lifetime.
+	stalkerLinkTTL = 30 * time.Second
+)
+
+// stalkerLink is a resolved stream link.
+type stalkerLink struct {
+	url     string
+	expires time.Time
+}
+
+// StalkerHandler handles ingestion of Stalker / Ministra portal sources.
+//
+// Portal links expire, so channels are stored with the portal's channel
+// command as their stream URL, and ResolveStreamURL exchanges it for a
+// playable link when the channel is played.
+type StalkerHandler struct {
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	clients map[string]*stalker.Client
+	links   map[string]stalkerLink
+}
+
+// NewStalkerHandler creates a new Stalker handler with default settings.
+func NewStalkerHandler() *StalkerHandler {
+	return &StalkerHandler{
+		clients: make(map[string]*stalker.Client),
+		links:   make(map[string]stalkerLink),
+	}
+}
+
+// WithLogger sets a structured logger for the handler.
+func (h *StalkerHandler) WithLogger(logger *slog.Logger) *StalkerHandler {
+	h.logger = logger
+	return h
+}
+
+// Type returns the source type this handler supports.
+func (h *StalkerHandler) Type() models.SourceType {
+	return models.SourceTypeStalker
+}
+
+// Validate checks if the source configuration is valid for Stalker ingestion.
+func (h *StalkerHandler) Validate(source *models.StreamSource) error {
+	if source == nil {
+		return fmt.Errorf("source is nil")
+	}
+	if source.Type != models.SourceTypeStalker {
+		return fmt.Errorf("source type must be stalker, got %s", source.Type)
+	}
+	if source.URL == "" {
+		return fmt.Errorf("source URL is required")
+	}
+	if source.MacAddress == "" {
+		return fmt.Errorf("MAC address is required for Stalker sources")
+	}
+	return nil
+}
+
+// Ingest fetches channels from the portal, calling the callback for each channel.
+func (h *StalkerHandler) Ingest(ctx context.Context, source *models.StreamSource, callback ChannelCallback) error {
+	if err := h.Validate(source); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	client := h.client(source)
+
+	genres, err := client.GetGenres(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching genres: %w", err)
+	}
+	genreMap := make(map[string]string, len(genres))
+	for _, genre := range genres {
+		genreMap[genre.ID.String()] = genre.Title
+	}
+
+	channels, err := client.GetAllChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching channels: %w", err)
+	}
+
+	var skipped int
+	for i := range channels {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		ch := &channels[i]
+		channel := &models.Channel{
+			SourceID:      source.ID,
+			ExtID:         ch.ID.String(),
+			TvgID:         stalkerTvgID(ch),
+			TvgName:       ch.Name,
+			TvgLogo:       stalkerLogo(ch.Logo),
+			GroupTitle:    genreMap[ch.GenreID.String()],
+			ChannelName:   ch.Name,
+			ChannelNumber: int(ch.Number.Int()),
+			StreamURL:     ch.Cmd,
+			StreamType:    streamTypeLive,
+			IsAdult:       ch.IsAdult(),
+		}
+
+		if err := channel.Validate(); err != nil {
+			skipped++
+			continue
+		}
+
+		if err := callback(channel); err != nil {
+			return fmt.Errorf("callback error: %w", err)
+		}
+	}
+
+	if skipped > 0 && h.logger != nil {
+		h.logger.Warn("skipped invalid channels during ingestion",
+			slog.Int("skipped", skipped),
+			slog.Int("total_channels", len(channels)),
+			slog.String("source_id", source.ID.String()),
+		)
+	}
+
+	return nil
+}
+
+// ResolveStreamURL exchanges a channel command stored as a stream URL for a
+// playable link from the portal.
+func (h *StalkerHandler) ResolveStreamURL(ctx context.Context, source *models.StreamSource, streamURL string) (string, error) {
+	if err := h.Validate(source); err != nil {
+		return "", fmt.Errorf("validation failed: %w", err)
+	}
+
+	key := source.ID.String() + "|" + streamURL
+	now := time.Now()
+
+	h.mu.Lock()
+	for k, link := range h.links {
+		if now.After(link.expires) {
+			delete(h.links, k)
+		}
+	}
+	link, ok := h.links[key]
+	h.mu.Unlock()
+	if ok {
+		return link.url, nil
+	}
+
+	resolved, err := h.client(source).CreateLink(ctx, streamURL)
+	if err != nil {
+		return "", fmt.Errorf("creating stream link: %w", err)
+	}
+
+	h.mu.Lock()
+	h.links[key] = stalkerLink{url: resolved, expires: now.Add(stalkerLinkTTL)}
+	h.mu.Unlock()
+
+	return resolved, nil
+}
+
+// client returns the portal client for a source. Clients are kept so their
+// session token is reused; changing the source's portal URL, MAC address or
+// connection settings starts a new session.
+func (h *StalkerHandler) client(source *models.StreamSource) *stalker.Client {
+	key := strings.Join([]string{source.ID.String(), source.URL, source.MacAddress, source.UserAgent, source.ProxyURL}, "|")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if client, ok := h.clients[key]; ok {
+		return client
+	}
+	for k := range h.clients {
+		if strings.HasPrefix(k, source.ID.String()+"|") {
+			delete(h.clients, k)
+		}
+	}
+
+	client := newStalkerClient(source.URL, source.MacAddress, source.UserAgent, source.ProxyURL, "stalker-ingestion")
+	h.clients[key] = client
+	return client
+}
+
+// newStalkerClient creates a portal client sharing a circuit breaker per
+// portal host. A non-empty proxyURL routes portal requests through that proxy.
+func newStalkerClient(portalURL, mac, userAgent, proxyURL, defaultBreaker string) *stalker.Client {
+	breaker := httpclient.DefaultManager.GetOrCreate(circuitBreakerNameFromURL(portalURL, defaultBreaker))
+
+	cfg := httpclient.DefaultConfig()
+	cfg.Timeout = defaultStalkerTimeout
+	cfg.ProxyURL = proxyURL
+	httpClient := httpclient.NewWithBreaker(cfg, breaker)
+
+	opts := []stalker.ClientOption{stalker.WithHTTPClient(httpClient.StandardClient())}
+	if userAgent != "" {
+		opts = append(opts, stalker.WithUserAgent(userAgent))
+	}
+	return stalker.NewClient(portalURL, mac, opts...)
+}
+
+// stalkerTvgID returns the EPG channel ID of a portal channel: its XMLTV ID if
+// the portal publishes one, otherwise the portal channel ID, which is what the
+// portal's own EPG is keyed by.
+func stalkerTvgID(ch *stalker.Channel) string {
+	if ch.XMLTVID != "" {
+		return ch.XMLTVID
+	}
+	return ch.ID.String()
+}
+
+// stalkerLogo returns a channel logo if it is an absolute URL. Portals may
+// publish bare file names relative to a theme directory, which are dropped.
+func stalkerLogo(logo string) string {
+	if strings.HasPrefix(logo, httpSchemePrefix) || strings.HasPrefix(logo, httpsSchemePrefix) {
+		return logo
+	}
+	return ""
+}
+
+// Ensure StalkerHandler implements SourceHandler and StreamResolver.
+var (
+	_ SourceHandler  = (*StalkerHandler)(nil)
+	_ StreamResolver = (*StalkerHandler)(nil)
+)
@@ -0,0 +1,185 @@
+package ingestor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// newStalkerTestPortal starts a portal serving two channels and their EPG. The
+// returned counter tracks create_link requests.
+func newStalkerTestPortal(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var links atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/portal.php" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("action") {
+		case "handshake":
+			w.Write([]byte(`{"js":{"token":"abc"}}`))
+		case "get_profile":
+			w.Write([]byte(`{"js":{"id":1}}`))
+		case "get_genres":
+			w.Write([]byte(`{"js":[{"id":"1","title":"News"},{"id":"2","title":"Sport"}]}`))
+		case "get_all_channels":
+			w.Write([]byte(`{"js":{"data":[
+				{"id":"10","name":"News One","number":"1","cmd":"ffrt http://localhost/ch/10_","logo":"http://logos/10.png","tv_genre_id":"1","xmltv_id":"news1.uk"},
+				{"id":"11","name":"Sport One","number":"2","cmd":"ffrt http://localhost/ch/11_","logo":"11.png","tv_genre_id":"2","censored":1},
+				{"id":"12","name":"Broken","number":"3","cmd":""}
+			]}}`))
+		case "create_link":
+			n := links.Add(1)
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Query().Get("cmd"), "ffrt http://localhost/ch/"), "_")
+			w.Write([]byte(`{"js":{"cmd":"ffmpeg http://cdn.example.com/` + id + `.ts?n=` + strconv.Itoa(int(n)) + `"}}`))
+		case "get_epg_info":
+			w.Write([]byte(`{"js":{"data":{
+				"10":[{"name":"Headlines","descr":"Today","category":"News","start_timestamp":1700000000,"stop_timestamp":1700003600}],
+				"11":[{"name":"Match","start_timestamp":1700000000,"stop_timestamp":1700007200}],
+				"99":[{"name":"Orphan","start_timestamp":1700000000,"stop_timestamp":1700003600}]
+			}}}`))
+		default:
+			t.Errorf("unexpected action: %s", r.URL.Query().Get("action"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &links
+}
+
+func TestStalkerHandler_Validate(t *testing.T) {
+	h := NewStalkerHandler()
+
+	tests := []struct {
+		name    string
+		source  *models.StreamSource
+		wantErr string
+	}{
+		{"nil source", nil, "source is nil"},
+		{"wrong type", &models.StreamSource{Type: models.SourceTypeM3U, URL: "http://example.com"}, "source type must be stalker"},
+		{"missing URL", &models.StreamSource{Type: models.SourceTypeStalker, MacAddress: "00:1A:79:00:00:01"}, "URL is required"},
+		{"missing MAC", &models.StreamSource{Type: models.SourceTypeStalker, URL: "http://example.com/c/"}, "MAC address is required"},
+		{"valid", &models.StreamSource{Type: models.SourceTypeStalker, URL: "http://example.com/c/", MacAddress: "00:1A:79:00:00:01"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.Validate(tt.source)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStalkerHandler_Ingest(t *testing.T) {
+	server, _ := newStalkerTestPortal(t)
+	source := &models.StreamSource{
+		BaseModel:  models.BaseModel{ID: models.NewULID()},
+		Type:       models.SourceTypeStalker,
+		URL:        server.URL + "/c/",
+		MacAddress: "00:1A:79:00:00:01",
+	}
+
+	var channels []*models.Channel
+	err := NewStalkerHandler().Ingest(context.Background(), source, func(ch *models.Channel) error {
+		channels = append(channels, ch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The channel without a command is skipped
+	if len(channels) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(channels))
+	}
+
+	news := channels[0]
+	if news.ExtID != "10" || news.TvgID != "news1.uk" || news.GroupTitle != "News" || news.ChannelNumber != 1 {
+		t.Errorf("unexpected channel: %+v", news)
+	}
+	if news.StreamURL != "ffrt http://localhost/ch/10_" {
+		t.Errorf("expected the channel command as stream URL, got %q", news.StreamURL)
+	}
+	if news.TvgLogo != "http://logos/10.png" {
+		t.Errorf("unexpected logo: %q", news.TvgLogo)
+	}
+
+	sport := channels[1]
+	if sport.TvgID != "11" || !sport.IsAdult || sport.TvgLogo != "" {
+		t.Errorf("unexpected channel: %+v", sport)
+	}
+}
+
+func TestStalkerHandler_ResolveStreamURL(t *testing.T) {
+	server, links := newStalkerTestPortal(t)
+	source := &models.StreamSource{
+		BaseModel:  models.BaseModel{ID: models.NewULID()},
+		Type:       models.SourceTypeStalker,
+		URL:        server.URL + "/c/",
+		MacAddress: "00:1A:79:00:00:01",
+	}
+	h := NewStalkerHandler()
+
+	streamURL, err := h.ResolveStreamURL(context.Background(), source, "ffrt http://localhost/ch/10_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if streamURL != "http://cdn.example.com/10.ts?n=1" {
+		t.Errorf("unexpected stream URL: %q", streamURL)
+	}
+
+	// A link resolved moments ago is reused
+	again, err := h.ResolveStreamURL(context.Background(), source, "ffrt http://localhost/ch/10_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again != streamURL || links.Load() != 1 {
+		t.Errorf("expected the cached link, got %q after %d requests", again, links.Load())
+	}
+
+	other, err := h.ResolveStreamURL(context.Background(), source, "ffrt http://localhost/ch/11_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other != "http://cdn.example.com/11.ts?n=2" {
+		t.Errorf("unexpected stream URL: %q", other)
+	}
+}
+
+func TestHandlerFactory_ResolveStreamURL(t *testing.T) {
+	server, _ := newStalkerTestPortal(t)
+	f := NewHandlerFactory()
+
+	m3u := &models.StreamSource{Type: models.SourceTypeM3U, URL: "http://example.com/list.m3u"}
+	streamURL, err := f.ResolveStreamURL(context.Background(), m3u, "http://example.com/live.ts")
+	if err != nil || streamURL != "http://example.com/live.ts" {
+		t.Errorf("expected M3U URL unchanged, got %q, %v", streamURL, err)
+	}
+
+	portal := &models.StreamSource{
+		BaseModel:  models.BaseModel{ID: models.NewULID()},
+		Type:       models.SourceTypeStalker,
+		URL:        server.URL + "/c/",
+		MacAddress: "00:1A:79:00:00:01",
+	}
+	streamURL, err = f.ResolveStreamURL(context.Background(), portal, "ffrt http://localhost/ch/11_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(streamURL, "http://cdn.example.com/11.ts") {
+		t.Errorf("unexpected stream URL: %q", streamURL)
+	}
+}
@@ -4,6 +4,7 @@ package ingestor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
@@ -85,6 +86,12 @@ func xmltvBreakerNameFromURL(sourceURL string) string {
 
 // Ingest processes an XMLTV EPG source and yields programs via the callback.
 func (h *XMLTVHandler) Ingest(ctx context.Context, source *models.EpgSource, callback ProgramCallback) error {
+	return h.IngestWithChannels(ctx, source, nil, callback)
+}
+
+// IngestWithChannels processes an XMLTV EPG source, yielding each <channel>
+// definition via onChannel and programs via the callback.
+func (h *XMLTVHandler) IngestWithChannels(ctx context.Context, source *models.EpgSource, onChannel EpgChannelCallback, callback ProgramCallback) error {
 	if err := h.Validate(source); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
@@ -94,9 +101,17 @@ func (h *XMLTVHandler) Ingest(ctx context.Context, source *models.EpgSource, cal
 
 	cfg := httpclient.DefaultConfig()
 	cfg.Timeout = defaultXMLTVTimeout
+	cfg.ProxyURL = source.ProxyURL
 	fetcher := urlutil.NewResourceFetcherWithBreaker(cfg, breaker)
 
-	reader, err := fetcher.Fetch(ctx, source.URL)
+	prev := contentVersion{
+		Validators: urlutil.Validators{ETag: source.ETag, LastModified: source.LastModified},
+		Hash:       source.ContentHash,
+	}
+	reader, version, err := fetchIfChanged(ctx, fetcher, source.URL, prev)
+	if errors.Is(err, ErrSourceUnchanged) {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to fetch XMLTV: %w", err)
 	}
@@ -109,7 +124,16 @@ func (h *XMLTVHandler) Ingest(ctx context.Context, source *models.EpgSource, cal
 	parser := &xmltv.Parser{
 		OnChannel: func(channel *xmltv.Channel) error {
 			h.channelMap[channel.ID] = channel.ID
-			return nil
+			if onChannel == nil {
+				return nil
+			}
+			epgChannel := &models.EpgChannel{
+				SourceID:  source.ID,
+				ChannelID: channel.ID,
+				Icon:      channel.Icon,
+			}
+			epgChannel.SetNames(channel.DisplayNames)
+			return onChannel(epgChannel)
 		},
 		OnProgramme: func(programme *xmltv.Programme) error {
 			select {
@@ -139,6 +163,10 @@ func (h *XMLTVHandler) Ingest(ctx context.Context, source *models.EpgSource, cal
 		return fmt.Errorf("failed to parse XMLTV: %w", err)
 	}
 
+	source.ETag = version.ETag
+	source.LastModified = version.LastModified
+	source.ContentHash = version.Hash
+
 	if h.timezoneDetected {
 		detectedTz := h.detectedTimezone
 		source.DetectedTimezone = formatTimezoneOffset(detectedTz)
@@ -262,5 +290,5 @@ func formatTimezoneOffset(offset string) string {
 	return FormatTimezoneOffset(offset)
 }
 
-// Ensure XMLTVHandler implements EpgHandler.
-var _ EpgHandler = (*XMLTVHandler)(nil)
+// Ensure XMLTVHandler implements EpgChannelHandler.
+var _ EpgChannelHandler = (*XMLTVHandler)(nil)
@@ -186,6 +186,57 @@ func TestXMLTVHandler_Ingest(t *testing.T) {
 	assert.Equal(t, "Another description", p2.Description)
 }
 
+func TestXMLTVHandler_IngestWithChannels(t *testing.T) {
+	xmltvData := `<?xml version="1.0" encoding="UTF-8"?>
+<tv>
+  <channel id="bbc1.uk">
+    <display-name>BBC One</display-name>
+    <display-name>BBC 1</display-name>
+    <icon src="http://example.com/bbc1.png"/>
+  </channel>
+  <channel id="itv.uk">
+    <display-name>ITV</display-name>
+  </channel>
+  <programme start="20240115180000 +0000" stop="20240115190000 +0000" channel="bbc1.uk">
+    <title>News</title>
+  </programme>
+</tv>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(xmltvData))
+	}))
+	defer server.Close()
+
+	handler := NewXMLTVHandler()
+	source := &models.EpgSource{
+		BaseModel: models.BaseModel{ID: models.NewULID()},
+		Type:      models.EpgSourceTypeXMLTV,
+		URL:       server.URL + "/epg.xml",
+	}
+
+	var channels []*models.EpgChannel
+	var programs int
+	err := handler.IngestWithChannels(context.Background(), source,
+		func(channel *models.EpgChannel) error {
+			channels = append(channels, channel)
+			return nil
+		},
+		func(*models.EpgProgram) error {
+			programs++
+			return nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, programs)
+	require.Len(t, channels, 2)
+	assert.Equal(t, source.ID, channels[0].SourceID)
+	assert.Equal(t, "bbc1.uk", channels[0].ChannelID)
+	assert.Equal(t, "BBC One", channels[0].DisplayName)
+	assert.Equal(t, []string{"BBC One", "BBC 1"}, channels[0].Names())
+	assert.Equal(t, "http://example.com/bbc1.png", channels[0].Icon)
+	assert.Equal(t, "itv.uk", channels[1].ChannelID)
+}
+
 func TestXMLTVHandler_Ingest_CallbackError(t *testing.T) {
 	xmltvData := `<?xml version="1.0" encoding="UTF-8"?>
 <tv>
@@ -346,6 +397,7 @@ func TestXMLTVHandler_Ingest_SkipsInvalidTimeRanges(t *testing.T) {
 
 func TestXMLTVHandler_ImplementsInterface(t *testing.T) {
 	var _ EpgHandler = (*XMLTVHandler)(nil)
+	var _ EpgChannelHandler = (*XMLTVHandler)(nil)
 }
 
 // T070: Unit test for XMLTV timezone handling in internal/ingestor/xmltv_handler_test.go.
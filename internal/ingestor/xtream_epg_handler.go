@@ -112,6 +112,7 @@ func (h *XtreamEpgHandler) Ingest(ctx context.Context, source *models.EpgSource,
 
 	cfg := httpclient.DefaultConfig()
 	cfg.Timeout = defaultEpgTimeout
+	cfg.ProxyURL = source.ProxyURL
 	httpClient := httpclient.NewWithBreaker(cfg, breaker)
 
 	client := xtream.NewClient(
@@ -94,35 +94,22 @@ func (h *XtreamHandler) Ingest(ctx context.Context, source *models.StreamSource,
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	breakerName := circuitBreakerNameFromURL(source.URL, "xtream-ingestion")
-	breaker := httpclient.DefaultManager.GetOrCreate(breakerName)
-
-	cfg := httpclient.DefaultConfig()
-	cfg.Timeout = defaultXtreamTimeout
-	httpClient := httpclient.NewWithBreaker(cfg, breaker)
-
-	client := xtream.NewClient(
-		source.URL,
-		source.Username,
-		source.Password,
-		xtream.WithHTTPClient(httpClient.StandardClient()),
-	)
+	client := h.newClient(source)
 
 	categories, err := client.GetLiveCategories(ctx)
 	if err != nil {
 		return fmt.Errorf("fetching categories: %w", err)
 	}
 
-	categoryMap := make(map[string]string)
-	for _, cat := range categories {
-		categoryMap[cat.CategoryID.String()] = cat.CategoryName
-	}
+	categoryMap := categoryNames(categories)
 
 	streams, err := client.GetLiveStreams(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("fetching live streams: %w", err)
 	}
 
+	archiveTimezone := h.archiveTimezone(ctx, client, streams, source.ID)
+
 	var skipped int
 	for _, stream := range streams {
 		select {
@@ -132,6 +119,11 @@ func (h *XtreamHandler) Ingest(ctx context.Context, source *models.StreamSource,
 		}
 
 		channel := h.streamToChannel(stream, source.ID, client, categoryMap)
+		if stream.HasArchive() {
+			channel.CatchupDays = int(stream.TVArchiveDays.Int())
+			channel.CatchupSource = client.GetTimeshiftURLTemplate(int(stream.StreamID.Int()))
+			channel.CatchupTimezone = archiveTimezone
+		}
 
 		if err := channel.Validate(); err != nil {
 			skipped++
@@ -154,6 +146,35 @@ func (h *XtreamHandler) Ingest(ctx context.Context, source *models.StreamSource,
 	return nil
 }
 
+// AccountInfo fetches the account information of an Xtream source: its status,
+// expiry and connection usage.
+func (h *XtreamHandler) AccountInfo(ctx context.Context, source *models.StreamSource) (*xtream.UserInfo, error) {
+	info, err := h.newClient(source).GetAuthInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching account info: %w", err)
+	}
+	return &info.UserInfo, nil
+}
+
+// newClient creates an Xtream API client for a source, sharing a circuit breaker
+// per upstream host.
+func (h *XtreamHandler) newClient(source *models.StreamSource) *xtream.Client {
+	breakerName := circuitBreakerNameFromURL(source.URL, "xtream-ingestion")
+	breaker := httpclient.DefaultManager.GetOrCreate(breakerName)
+
+	cfg := httpclient.DefaultConfig()
+	cfg.Timeout = defaultXtreamTimeout
+	cfg.ProxyURL = source.ProxyURL
+	httpClient := httpclient.NewWithBreaker(cfg, breaker)
+
+	return xtream.NewClient(
+		source.URL,
+		source.Username,
+		source.Password,
+		xtream.WithHTTPClient(httpClient.StandardClient()),
+	)
+}
+
 // streamToChannel converts an Xtream stream to a Channel model.
 func (h *XtreamHandler) streamToChannel(stream xtream.Stream, sourceID models.ULID, client *xtream.Client, categoryMap map[string]string) *models.Channel {
 	channel := &models.Channel{
@@ -172,3 +193,33 @@ func (h *XtreamHandler) streamToChannel(stream xtream.Stream, sourceID models.UL
 
 	return channel
 }
+
+// archiveTimezone returns the server timezone used to address catch-up archives,
+// or "" (UTC) if no stream has an archive or the server does not report a valid zone.
+func (h *XtreamHandler) archiveTimezone(ctx context.Context, client *xtream.Client, streams []xtream.Stream, sourceID models.ULID) string {
+	hasArchive := false
+	for i := range streams {
+		if streams[i].HasArchive() {
+			hasArchive = true
+			break
+		}
+	}
+	if !hasArchive {
+		return ""
+	}
+
+	info, err := client.GetAuthInfo(ctx)
+	if err != nil {
+		if h.logger != nil {
+			h.logger.Warn("failed to fetch server timezone for catch-up, assuming UTC",
+				slog.String("source_id", sourceID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+		return ""
+	}
+	if _, err := time.LoadLocation(info.ServerInfo.Timezone); err != nil {
+		return ""
+	}
+	return info.ServerInfo.Timezone
+}
@@ -216,6 +216,63 @@ func TestXtreamHandler_Ingest(t *testing.T) {
 	}
 }
 
+func TestXtreamHandler_Ingest_Catchup(t *testing.T) {
+	liveStreams := []xtream.Stream{
+		{StreamID: xtream.FlexInt(101), Name: "Archived", TVArchive: 1, TVArchiveDays: 7},
+		{StreamID: xtream.FlexInt(102), Name: "Live Only"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("action") {
+		case "":
+			json.NewEncoder(w).Encode(xtream.AuthInfo{ServerInfo: xtream.ServerInfo{Timezone: "Europe/London"}})
+		case "get_live_categories":
+			json.NewEncoder(w).Encode([]xtream.Category{})
+		case "get_live_streams":
+			json.NewEncoder(w).Encode(liveStreams)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	source := &models.StreamSource{
+		BaseModel: models.BaseModel{ID: models.NewULID()},
+		Type:      models.SourceTypeXtream,
+		URL:       server.URL,
+		Username:  "testuser",
+		Password:  "testpass",
+	}
+
+	var channels []*models.Channel
+	err := NewXtreamHandler().Ingest(context.Background(), source, func(ch *models.Channel) error {
+		channels = append(channels, ch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(channels))
+	}
+
+	archived := channels[0]
+	if archived.CatchupDays != 7 {
+		t.Errorf("expected CatchupDays 7, got %d", archived.CatchupDays)
+	}
+	wantSource := server.URL + "/streaming/timeshift.php?username=testuser&password=testpass&stream=101&start={Y}-{m}-{d}:{H}-{M}&duration={duration:60}"
+	if archived.CatchupSource != wantSource {
+		t.Errorf("expected CatchupSource %q, got %q", wantSource, archived.CatchupSource)
+	}
+	if archived.CatchupTimezone != "Europe/London" {
+		t.Errorf("expected CatchupTimezone 'Europe/London', got %q", archived.CatchupTimezone)
+	}
+
+	if channels[1].HasCatchup() {
+		t.Error("expected channel without tv_archive to have no catch-up")
+	}
+}
+
 func TestXtreamHandler_Ingest_CallbackError(t *testing.T) {
 	liveCategories := []xtream.Category{}
 	liveStreams := []xtream.Stream{
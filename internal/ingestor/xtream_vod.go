@@ -0,0 +1,229 @@
+package ingestor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/pkg/xtream"
+)
+
+// nameYearPattern matches a release year in parentheses at the end of a title,
+// as in "Heat (1995)".
+var nameYearPattern = regexp.MustCompile(`\(((?:19|20)\d{2})\)\s*$`)
+
+// IngestMovies fetches the source's movie catalogue, calling the callback for each movie.
+func (h *XtreamHandler) IngestMovies(ctx context.Context, source *models.StreamSource, callback VodCallback) error {
+	if err := h.Validate(source); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	client := h.newClient(source)
+
+	categories, err := client.GetVODCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching VOD categories: %w", err)
+	}
+	categoryMap := categoryNames(categories)
+
+	streams, err := client.GetVODStreams(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("fetching VOD streams: %w", err)
+	}
+
+	var skipped int
+	for _, stream := range streams {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		streamID := int(stream.StreamID.Int())
+		item := &models.VodItem{
+			SourceID:           source.ID,
+			ExtID:              strconv.Itoa(streamID),
+			Name:               stream.Name,
+			GroupTitle:         categoryMap[stream.CategoryID.String()],
+			Logo:               stream.StreamIcon,
+			StreamURL:          client.GetVODStreamURL(streamID, stream.ContainerExtension),
+			ContainerExtension: stream.ContainerExtension,
+			Rating:             stream.Rating.Float(),
+			Year:               releaseYear("", stream.Name),
+			IsAdult:            stream.IsAdult.Int() == 1,
+		}
+		if err := item.Validate(); err != nil {
+			skipped++
+			continue
+		}
+
+		if err := callback(item); err != nil {
+			return fmt.Errorf("callback error: %w", err)
+		}
+	}
+
+	if skipped > 0 && h.logger != nil {
+		h.logger.Warn("skipped invalid movies during ingestion",
+			slog.Int("skipped", skipped),
+			slog.Int("total_movies", len(streams)),
+			slog.String("source_id", source.ID.String()),
+		)
+	}
+
+	return nil
+}
+
+// IngestSeries fetches the source's series catalogue, calling the callback for
+// each series. Episodes are fetched for series where needEpisodes returns true
+// (all series if needEpisodes is nil). A series whose episodes cannot be fetched
+// is yielded without them and with LastModified cleared, so the next ingestion
+// tries again.
+func (h *XtreamHandler) IngestSeries(ctx context.Context, source *models.StreamSource, needEpisodes func(*models.Series) bool, callback SeriesCallback) error {
+	if err := h.Validate(source); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	client := h.newClient(source)
+
+	categories, err := client.GetSeriesCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching series categories: %w", err)
+	}
+	categoryMap := categoryNames(categories)
+
+	list, err := client.GetSeries(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("fetching series: %w", err)
+	}
+
+	var fetched, failed int
+	for _, entry := range list {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		seriesID := int(entry.SeriesID.Int())
+		series := &models.Series{
+			SourceID:     source.ID,
+			ExtID:        strconv.Itoa(seriesID),
+			Name:         entry.Name,
+			GroupTitle:   categoryMap[entry.CategoryID.String()],
+			Cover:        entry.Cover,
+			Genre:        entry.Genre,
+			Rating:       entry.Rating.Float(),
+			Year:         releaseYear(entry.ReleaseDate, entry.Name),
+			LastModified: entry.LastModified.Int(),
+		}
+		if err := series.Validate(); err != nil {
+			continue
+		}
+
+		if needEpisodes == nil || needEpisodes(series) {
+			info, err := client.GetSeriesInfo(ctx, seriesID)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				failed++
+				series.LastModified = 0
+				if h.logger != nil {
+					h.logger.Debug("failed to fetch series episodes",
+						slog.String("source_id", source.ID.String()),
+						slog.String("series", series.Name),
+						slog.String("error", err.Error()),
+					)
+				}
+			} else {
+				series.Episodes = seriesEpisodes(client, info)
+				fetched++
+			}
+		}
+
+		if err := callback(series); err != nil {
+			return fmt.Errorf("callback error: %w", err)
+		}
+	}
+
+	if h.logger != nil {
+		h.logger.Info("fetched series catalogue",
+			slog.String("source_id", source.ID.String()),
+			slog.Int("series", len(list)),
+			slog.Int("episodes_fetched", fetched),
+			slog.Int("episodes_failed", failed),
+		)
+	}
+
+	return nil
+}
+
+// seriesEpisodes converts the episodes of a series, ordered by season and
+// episode number.
+func seriesEpisodes(client *xtream.Client, info *xtream.SeriesInfo) []*models.SeriesEpisode {
+	seasons := make([]string, 0, len(info.Episodes))
+	for season := range info.Episodes {
+		seasons = append(seasons, season)
+	}
+	slices.SortFunc(seasons, func(a, b string) int {
+		ai, _ := strconv.Atoi(a)
+		bi, _ := strconv.Atoi(b)
+		return ai - bi
+	})
+
+	episodes := make([]*models.SeriesEpisode, 0)
+	for _, key := range seasons {
+		for _, ep := range info.Episodes[key] {
+			episodeID := int(ep.ID.Int())
+			if episodeID == 0 {
+				continue
+			}
+			season := int(ep.Season.Int())
+			if season == 0 {
+				season, _ = strconv.Atoi(key)
+			}
+			episodes = append(episodes, &models.SeriesEpisode{
+				ExtID:              strconv.Itoa(episodeID),
+				Season:             season,
+				EpisodeNum:         int(ep.EpisodeNum.Int()),
+				Title:              ep.Title,
+				StreamURL:          client.GetSeriesEpisodeURL(episodeID, ep.ContainerExtension),
+				ContainerExtension: ep.ContainerExtension,
+				Image:              ep.Info.MovieImage,
+			})
+		}
+	}
+	return episodes
+}
+
+// categoryNames maps category IDs to names.
+func categoryNames(categories []xtream.Category) map[string]string {
+	names := make(map[string]string, len(categories))
+	for _, cat := range categories {
+		names[cat.CategoryID.String()] = cat.CategoryName
+	}
+	return names
+}
+
+// releaseYear returns the year of a release date such as "2002-06-02", falling
+// back to a year in parentheses at the end of the title. It returns 0 if neither
+// has one.
+func releaseYear(releaseDate, name string) int {
+	if len(releaseDate) >= 4 {
+		if year, err := strconv.Atoi(releaseDate[:4]); err == nil && year >= 1900 {
+			return year
+		}
+	}
+	if m := nameYearPattern.FindStringSubmatch(name); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		return year
+	}
+	return 0
+}
+
+// Ensure XtreamHandler implements VodHandler.
+var _ VodHandler = (*XtreamHandler)(nil)
@@ -0,0 +1,173 @@
+package ingestor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// newVodTestServer serves a small Xtream VOD and series catalogue.
+func newVodTestServer(t *testing.T, seriesInfoCalls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("action") {
+		case "get_vod_categories":
+			w.Write([]byte(`[{"category_id":"1","category_name":"Action"}]`))
+		case "get_vod_streams":
+			w.Write([]byte(`[` +
+				`{"stream_id":10,"name":"Heat (1995)","category_id":"1","rating":"7.9","container_extension":"mkv"},` +
+				`{"stream_id":11,"name":"","category_id":"1"}]`))
+		case "get_series_categories":
+			w.Write([]byte(`[{"category_id":"2","category_name":"Drama"}]`))
+		case "get_series":
+			w.Write([]byte(`[` +
+				`{"series_id":7,"name":"The Wire","category_id":"2","genre":"Crime","releaseDate":"2002-06-02","last_modified":"100"},` +
+				`{"series_id":8,"name":"Lost","category_id":"2","last_modified":"200"}]`))
+		case "get_series_info":
+			*seriesInfoCalls++
+			if r.URL.Query().Get("series_id") == "8" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`{"episodes":{` +
+				`"2":[{"id":"721","episode_num":1,"title":"Ebb Tide","container_extension":"mp4"}],` +
+				`"1":[{"id":"701","episode_num":1,"title":"The Target","container_extension":"mp4","season":1}]}}`))
+		default:
+			t.Errorf("unexpected action: %s", r.URL.Query().Get("action"))
+		}
+	}))
+}
+
+func TestXtreamHandler_IngestMovies(t *testing.T) {
+	var calls int
+	server := newVodTestServer(t, &calls)
+	defer server.Close()
+
+	source := &models.StreamSource{
+		BaseModel: models.BaseModel{ID: models.NewULID()},
+		Type:      models.SourceTypeXtream,
+		URL:       server.URL,
+		Username:  "user",
+		Password:  "pass",
+	}
+
+	var movies []*models.VodItem
+	err := NewXtreamHandler().IngestMovies(context.Background(), source, func(item *models.VodItem) error {
+		movies = append(movies, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The movie without a name is skipped
+	if len(movies) != 1 {
+		t.Fatalf("expected 1 movie, got %d", len(movies))
+	}
+	heat := movies[0]
+	if heat.ExtID != "10" || heat.GroupTitle != "Action" || heat.Year != 1995 || heat.Rating != 7.9 {
+		t.Errorf("unexpected movie: %+v", heat)
+	}
+	if want := server.URL + "/movie/user/pass/10.mkv"; heat.StreamURL != want {
+		t.Errorf("expected stream URL %q, got %q", want, heat.StreamURL)
+	}
+}
+
+func TestXtreamHandler_IngestSeries(t *testing.T) {
+	var calls int
+	server := newVodTestServer(t, &calls)
+	defer server.Close()
+
+	source := &models.StreamSource{
+		BaseModel: models.BaseModel{ID: models.NewULID()},
+		Type:      models.SourceTypeXtream,
+		URL:       server.URL,
+		Username:  "user",
+		Password:  "pass",
+	}
+
+	var series []*models.Series
+	err := NewXtreamHandler().IngestSeries(context.Background(), source, nil, func(s *models.Series) error {
+		series = append(series, s)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(series))
+	}
+
+	wire := series[0]
+	if wire.GroupTitle != "Drama" || wire.Genre != "Crime" || wire.Year != 2002 || wire.LastModified != 100 {
+		t.Errorf("unexpected series: %+v", wire)
+	}
+	if len(wire.Episodes) != 2 {
+		t.Fatalf("expected 2 episodes, got %d", len(wire.Episodes))
+	}
+	// Seasons are ordered, and the season key fills in a missing season number
+	if wire.Episodes[0].Title != "The Target" || wire.Episodes[1].Season != 2 {
+		t.Errorf("unexpected episodes: %+v, %+v", wire.Episodes[0], wire.Episodes[1])
+	}
+	if want := server.URL + "/series/user/pass/701.mp4"; wire.Episodes[0].StreamURL != want {
+		t.Errorf("expected stream URL %q, got %q", want, wire.Episodes[0].StreamURL)
+	}
+
+	// A failed info request leaves the series without episodes, to be retried
+	lost := series[1]
+	if lost.Episodes != nil || lost.LastModified != 0 {
+		t.Errorf("expected failed series without episodes or last_modified, got %+v", lost)
+	}
+}
+
+func TestXtreamHandler_IngestSeries_SkipsUnchanged(t *testing.T) {
+	var calls int
+	server := newVodTestServer(t, &calls)
+	defer server.Close()
+
+	source := &models.StreamSource{
+		BaseModel: models.BaseModel{ID: models.NewULID()},
+		Type:      models.SourceTypeXtream,
+		URL:       server.URL,
+		Username:  "user",
+		Password:  "pass",
+	}
+
+	stored := map[string]int64{"7": 100, "8": 200}
+	var series []*models.Series
+	err := NewXtreamHandler().IngestSeries(context.Background(), source, func(s *models.Series) bool {
+		return stored[s.ExtID] != s.LastModified
+	}, func(s *models.Series) error {
+		series = append(series, s)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no series info requests, got %d", calls)
+	}
+	if len(series) != 2 || series[0].Episodes != nil {
+		t.Errorf("expected 2 series without episodes, got %+v", series)
+	}
+}
+
+func TestReleaseYear(t *testing.T) {
+	tests := []struct {
+		releaseDate string
+		name        string
+		want        int
+	}{
+		{"2002-06-02", "The Wire", 2002},
+		{"", "Heat (1995)", 1995},
+		{"", "Blade Runner 2049", 0},
+		{"unknown", "Alien", 0},
+	}
+	for _, tt := range tests {
+		if got := releaseYear(tt.releaseDate, tt.name); got != tt.want {
+			t.Errorf("releaseYear(%q, %q) = %d, want %d", tt.releaseDate, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// APIKey is a scoped credential for automation. Only a hash of the key is stored;
+// the plaintext key is shown once when the key is created.
+type APIKey struct {
+	BaseModel
+
+	// Name is a user-friendly label for the key.
+	Name string `gorm:"not null;size:255" json:"name"`
+
+	// UserID is the user that created the key.
+	UserID ULID `gorm:"type:varchar(26);not null;index" json:"user_id"`
+
+	// Prefix is the non-secret leading part of the key, used to identify it in listings.
+	Prefix string `gorm:"not null;size:32" json:"prefix"`
+
+	// KeyHash is the SHA-256 hash of the key.
+	KeyHash string `gorm:"uniqueIndex;not null;size:64" json:"-"`
+
+	// Scopes is the comma-separated list of granted scopes (read, write, admin).
+	Scopes string `gorm:"not null;size:255" json:"scopes"`
+
+	// ExpiresAt is when the key stops being valid (nil = never).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// LastUsedAt is the time the key was last used to authenticate.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// User is the relationship to the owning user.
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName returns the table name for APIKey.
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Validate checks if the API key is valid.
+func (k *APIKey) Validate() error {
+	if k.Name == "" {
+		return ValidationError{Field: "name", Message: "name is required"}
+	}
+	if k.UserID.IsZero() {
+		return ValidationError{Field: "user_id", Message: "user_id is required"}
+	}
+	if k.KeyHash == "" {
+		return ValidationError{Field: "key_hash", Message: "key_hash is required"}
+	}
+	if k.Scopes == "" {
+		return ValidationError{Field: "scopes", Message: "at least one scope is required"}
+	}
+	return nil
+}
+
+// IsExpired reports whether the key has expired at the given time.
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
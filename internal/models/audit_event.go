@@ -0,0 +1,94 @@
+package models
+
+// AuditEntityType identifies the kind of configuration entity an audit event is about.
+type AuditEntityType string
+
+const (
+	// AuditEntityStreamSource is a stream source.
+	AuditEntityStreamSource AuditEntityType = "stream_source"
+	// AuditEntityEpgSource is an EPG source.
+	AuditEntityEpgSource AuditEntityType = "epg_source"
+	// AuditEntityStreamProxy is a stream proxy, including its source and filter assignments.
+	AuditEntityStreamProxy AuditEntityType = "stream_proxy"
+	// AuditEntityFilter is a filter.
+	AuditEntityFilter AuditEntityType = "filter"
+	// AuditEntityDataMappingRule is a data mapping rule.
+	AuditEntityDataMappingRule AuditEntityType = "data_mapping_rule"
+	// AuditEntityClientDetectionRule is a client detection rule.
+	AuditEntityClientDetectionRule AuditEntityType = "client_detection_rule"
+	// AuditEntityEncodingProfile is an encoding profile.
+	AuditEntityEncodingProfile AuditEntityType = "encoding_profile"
+	// AuditEntityEncoderOverride is an encoder override.
+	AuditEntityEncoderOverride AuditEntityType = "encoder_override"
+)
+
+// AuditEntityTypes lists the entity types recorded in the audit trail.
+var AuditEntityTypes = []AuditEntityType{
+	AuditEntityStreamSource,
+	AuditEntityEpgSource,
+	AuditEntityStreamProxy,
+	AuditEntityFilter,
+	AuditEntityDataMappingRule,
+	AuditEntityClientDetectionRule,
+	AuditEntityEncodingProfile,
+	AuditEntityEncoderOverride,
+}
+
+// AuditAction identifies the change an audit event records.
+type AuditAction string
+
+const (
+	// AuditActionCreate records a new entity.
+	AuditActionCreate AuditAction = "create"
+	// AuditActionUpdate records a change to an existing entity.
+	AuditActionUpdate AuditAction = "update"
+	// AuditActionDelete records a deleted entity.
+	AuditActionDelete AuditAction = "delete"
+	// AuditActionRestore records an entity reverted to the state of an earlier event.
+	AuditActionRestore AuditAction = "restore"
+)
+
+// AuditEvent records one change to a configuration entity: who made it, and
+// the entity's state before and after. States are JSON objects with secrets
+// redacted; Changes holds only the top-level fields that differ.
+type AuditEvent struct {
+	BaseModel
+
+	// ActorType is how the actor authenticated: session, api_key or anonymous.
+	ActorType string `gorm:"size:20;not null" json:"actor_type"`
+
+	// ActorID is the user or API key ID, empty for anonymous actors.
+	ActorID string `gorm:"size:26;index" json:"actor_id,omitempty"`
+
+	// ActorName is the username or API key name at the time of the change.
+	ActorName string `gorm:"size:255;index" json:"actor_name"`
+
+	// EntityType is the kind of entity that changed.
+	EntityType AuditEntityType `gorm:"size:50;not null;index:idx_audit_entity" json:"entity_type"`
+
+	// EntityID is the ID of the entity that changed.
+	EntityID ULID `gorm:"type:varchar(26);not null;index:idx_audit_entity" json:"entity_id"`
+
+	// EntityName is the entity's name at the time of the change.
+	EntityName string `gorm:"size:255" json:"entity_name,omitempty"`
+
+	// Action is the kind of change.
+	Action AuditAction `gorm:"size:20;not null;index" json:"action"`
+
+	// Before is the JSON state before the change, empty for creates.
+	Before string `gorm:"type:text" json:"before,omitempty"`
+
+	// After is the JSON state after the change, empty for deletes.
+	After string `gorm:"type:text" json:"after,omitempty"`
+
+	// Changes is the JSON object of changed fields, each with "from" and "to" values.
+	Changes string `gorm:"type:text" json:"changes,omitempty"`
+
+	// RestoredFrom is the event whose state a restore reverted to.
+	RestoredFrom *ULID `gorm:"type:varchar(26)" json:"restored_from,omitempty"`
+}
+
+// TableName returns the table name for AuditEvent.
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// AuthSession is a logged-in UI session. Only a hash of the session token is stored.
+type AuthSession struct {
+	BaseModel
+
+	// UserID is the user that owns the session.
+	UserID ULID `gorm:"type:varchar(26);not null;index" json:"user_id"`
+
+	// TokenHash is the SHA-256 hash of the session token.
+	TokenHash string `gorm:"uniqueIndex;not null;size:64" json:"-"`
+
+	// ExpiresAt is when the session stops being valid.
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+
+	// UserAgent is the User-Agent of the client that logged in.
+	UserAgent string `gorm:"size:512" json:"user_agent,omitempty"`
+
+	// RemoteAddr is the address of the client that logged in.
+	RemoteAddr string `gorm:"size:255" json:"remote_addr,omitempty"`
+
+	// User is the relationship to the owning user.
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName returns the table name for AuthSession.
+func (AuthSession) TableName() string {
+	return "auth_sessions"
+}
+
+// IsExpired reports whether the session has expired at the given time.
+func (s *AuthSession) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
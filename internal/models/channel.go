@@ -1,6 +1,8 @@
 package models
 
 import (
+	"net/http"
+
 	"gorm.io/gorm"
 )
 
@@ -50,6 +52,21 @@ type Channel struct {
 	// IsAdult indicates whether this is adult content.
 	IsAdult bool `gorm:"default:false" json:"is_adult"`
 
+	// CatchupDays is how many days of archive the source keeps for this channel (0 = no catch-up).
+	CatchupDays int `gorm:"default:0" json:"catchup_days,omitempty"`
+
+	// CatchupSource is the upstream archive URL template, with placeholders
+	// expanded by m3u.ExpandCatchupSource.
+	CatchupSource string `gorm:"size:4096" json:"catchup_source,omitempty"`
+
+	// CatchupTimezone is the IANA timezone for the date placeholders of
+	// CatchupSource (empty = UTC).
+	CatchupTimezone string `gorm:"size:64" json:"catchup_timezone,omitempty"`
+
+	// StreamHeaders holds HTTP headers the stream requires, captured from the
+	// playlist's #EXTVLCOPT, #KODIPROP and #EXTHTTP lines, as a JSON object.
+	StreamHeaders string `gorm:"type:text" json:"stream_headers,omitempty"`
+
 	// Extra stores additional attributes from the M3U as JSON.
 	Extra string `gorm:"type:text" json:"extra,omitempty"`
 
@@ -67,6 +84,26 @@ func (c *Channel) GetSourceID() ULID {
 	return c.SourceID
 }
 
+// RequestHeaders returns the extra HTTP headers to send with the channel's
+// stream requests: those captured from the playlist, overridden by the custom
+// headers of its source when loaded. Returns nil if there are none.
+func (c *Channel) RequestHeaders() http.Header {
+	var header http.Header
+	add := func(headers map[string]string) {
+		for name, value := range headers {
+			if header == nil {
+				header = make(http.Header)
+			}
+			header.Set(name, value)
+		}
+	}
+	add(DecodeHeaders(c.StreamHeaders))
+	if c.Source != nil {
+		add(c.Source.Headers())
+	}
+	return header
+}
+
 // Validate performs basic validation on the channel.
 func (c *Channel) Validate() error {
 	if c.SourceID.IsZero() {
@@ -116,3 +153,8 @@ func hashString(s string) string {
 	}
 	return string(rune(h))
 }
+
+// HasCatchup reports whether the channel has a catch-up archive.
+func (c *Channel) HasCatchup() bool {
+	return c.CatchupDays > 0 && c.CatchupSource != ""
+}
@@ -140,3 +140,20 @@ func TestChannel_FullModel(t *testing.T) {
 	assert.Equal(t, sourceID, c.GetSourceID())
 	assert.NoError(t, c.Validate())
 }
+
+func TestChannel_RequestHeaders(t *testing.T) {
+	c := Channel{}
+	assert.Nil(t, c.RequestHeaders())
+
+	c.StreamHeaders = EncodeHeaders(map[string]string{
+		"Referer":    "https://playlist.example/",
+		"User-Agent": "PlaylistAgent",
+	})
+	c.Source = &StreamSource{}
+	c.Source.SetHeaders(map[string]string{"Referer": "https://source.example/"})
+
+	// Source custom headers override those from the playlist
+	header := c.RequestHeaders()
+	assert.Equal(t, "https://source.example/", header.Get("Referer"))
+	assert.Equal(t, "PlaylistAgent", header.Get("User-Agent"))
+}
@@ -0,0 +1,126 @@
+package models
+
+import "strings"
+
+// EpgChannel is a channel definition from an EPG source, such as an XMLTV
+// <channel> element. Its display names are used to match stream channels that
+// have an empty or unknown tvg-id.
+type EpgChannel struct {
+	BaseModel
+
+	// SourceID is the EPG source the channel was ingested from.
+	SourceID ULID `gorm:"type:varchar(26);not null;index" json:"source_id"`
+
+	// ChannelID is the EPG channel identifier (matches Channel.TvgID).
+	ChannelID string `gorm:"not null;size:255;index" json:"channel_id"`
+
+	// DisplayName is the first display name of the channel.
+	DisplayName string `gorm:"size:512" json:"display_name"`
+
+	// DisplayNames holds every display name of the channel, one per line.
+	DisplayNames string `gorm:"type:text" json:"-"`
+
+	// Icon is the channel logo URL, if the EPG provides one.
+	Icon string `gorm:"size:2048" json:"icon,omitempty"`
+}
+
+// TableName returns the table name for EpgChannel.
+func (EpgChannel) TableName() string {
+	return "epg_channels"
+}
+
+// SetNames sets the channel's display names; the first becomes DisplayName.
+func (c *EpgChannel) SetNames(names []string) {
+	c.DisplayNames = strings.Join(names, "\n")
+	if len(names) > 0 {
+		c.DisplayName = names[0]
+	}
+}
+
+// Names returns every display name of the channel.
+func (c *EpgChannel) Names() []string {
+	if c.DisplayNames == "" {
+		if c.DisplayName == "" {
+			return nil
+		}
+		return []string{c.DisplayName}
+	}
+	return strings.Split(c.DisplayNames, "\n")
+}
+
+// EpgMatchStatus is the state of an automatic EPG-to-channel match.
+type EpgMatchStatus string
+
+const (
+	// EpgMatchStatusAuto is a match above the confidence threshold, applied automatically.
+	EpgMatchStatusAuto EpgMatchStatus = "auto"
+	// EpgMatchStatusPending is a low-confidence match waiting to be confirmed or rejected.
+	EpgMatchStatusPending EpgMatchStatus = "pending"
+	// EpgMatchStatusConfirmed is a match confirmed by a user; it is always applied.
+	EpgMatchStatusConfirmed EpgMatchStatus = "confirmed"
+	// EpgMatchStatusRejected is a match rejected by a user; it is not applied and
+	// the channel is not matched again.
+	EpgMatchStatusRejected EpgMatchStatus = "rejected"
+)
+
+// IsValid reports whether s is a known match status.
+func (s EpgMatchStatus) IsValid() bool {
+	switch s {
+	case EpgMatchStatusAuto, EpgMatchStatusPending, EpgMatchStatusConfirmed, EpgMatchStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// Applied reports whether matches with this status set the channel's tvg-id.
+func (s EpgMatchStatus) Applied() bool {
+	return s == EpgMatchStatusAuto || s == EpgMatchStatusConfirmed
+}
+
+// EpgChannelMatch links a stream channel without a usable tvg-id to the EPG
+// channel its name matched. Channel IDs are stable across re-ingestion, so
+// confirmed and rejected matches persist.
+type EpgChannelMatch struct {
+	BaseModel
+
+	// ChannelID is the stream channel that was matched.
+	ChannelID ULID `gorm:"type:varchar(26);not null;uniqueIndex" json:"channel_id"`
+
+	// ChannelName is the stream channel's name when it was matched.
+	ChannelName string `gorm:"size:512" json:"channel_name"`
+
+	// EpgSourceID is the EPG source of the matched EPG channel.
+	EpgSourceID ULID `gorm:"type:varchar(26);not null" json:"epg_source_id"`
+
+	// TvgID is the matched EPG channel identifier, assigned as the channel's tvg-id.
+	TvgID string `gorm:"not null;size:255" json:"tvg_id"`
+
+	// EpgDisplayName is the EPG display name that matched.
+	EpgDisplayName string `gorm:"size:512" json:"epg_display_name"`
+
+	// Confidence is the name similarity, from 0 to 1.
+	Confidence float64 `gorm:"not null;default:0" json:"confidence"`
+
+	// Status is the state of the match.
+	Status EpgMatchStatus `gorm:"not null;size:20;index" json:"status"`
+}
+
+// TableName returns the table name for EpgChannelMatch.
+func (EpgChannelMatch) TableName() string {
+	return "epg_channel_matches"
+}
+
+// Validate checks if the match is valid.
+func (m *EpgChannelMatch) Validate() error {
+	if m.ChannelID.IsZero() {
+		return ErrChannelIDRequired
+	}
+	if m.TvgID == "" {
+		return ValidationError{Field: "tvg_id", Message: "tvg_id is required"}
+	}
+	if !m.Status.IsValid() {
+		return ValidationError{Field: "status", Message: "status must be auto, pending, confirmed or rejected"}
+	}
+	return nil
+}
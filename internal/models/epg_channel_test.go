@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpgChannel_Names(t *testing.T) {
+	ch := &EpgChannel{}
+	assert.Nil(t, ch.Names())
+
+	ch.SetNames([]string{"BBC One", "BBC One HD", "BBC1"})
+	assert.Equal(t, "BBC One", ch.DisplayName)
+	assert.Equal(t, []string{"BBC One", "BBC One HD", "BBC1"}, ch.Names())
+
+	// Rows with only a display name still report it
+	assert.Equal(t, []string{"ITV"}, (&EpgChannel{DisplayName: "ITV"}).Names())
+}
+
+func TestEpgChannelMatch_Validate(t *testing.T) {
+	valid := func() *EpgChannelMatch {
+		return &EpgChannelMatch{
+			ChannelID: NewULID(),
+			TvgID:     "bbc1.uk",
+			Status:    EpgMatchStatusPending,
+		}
+	}
+
+	assert.NoError(t, valid().Validate())
+
+	m := valid()
+	m.ChannelID = ULID{}
+	assert.True(t, errors.Is(m.Validate(), ErrChannelIDRequired))
+
+	m = valid()
+	m.TvgID = ""
+	assert.ErrorContains(t, m.Validate(), "tvg_id")
+
+	m = valid()
+	m.Status = "maybe"
+	assert.ErrorContains(t, m.Validate(), "status")
+}
+
+func TestEpgMatchStatus_Applied(t *testing.T) {
+	assert.True(t, EpgMatchStatusAuto.Applied())
+	assert.True(t, EpgMatchStatusConfirmed.Applied())
+	assert.False(t, EpgMatchStatusPending.Applied())
+	assert.False(t, EpgMatchStatusRejected.Applied())
+}
@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/jmylchreest/tvarr/pkg/httpclient"
 	"gorm.io/gorm"
 )
 
@@ -15,6 +16,8 @@ const (
 	EpgSourceTypeXMLTV EpgSourceType = "xmltv"
 	// EpgSourceTypeXtream represents an Xtream Codes API EPG source.
 	EpgSourceTypeXtream EpgSourceType = "xtream"
+	// EpgSourceTypeStalker represents a Stalker / Ministra portal EPG source.
+	EpgSourceTypeStalker EpgSourceType = "stalker"
 )
 
 // EpgSourceStatus represents the current status of an EPG source.
@@ -51,10 +54,10 @@ type EpgSource struct {
 	// Must be unique across all EPG sources.
 	Name string `gorm:"uniqueIndex;not null;size:255" json:"name"`
 
-	// Type indicates whether this is an XMLTV or Xtream source.
+	// Type indicates whether this is an XMLTV, Xtream or Stalker source.
 	Type EpgSourceType `gorm:"not null;size:20" json:"type"`
 
-	// URL is the XMLTV file URL or Xtream server base URL.
+	// URL is the XMLTV file URL, Xtream server base URL or Stalker portal URL.
 	URL string `gorm:"not null;size:2048" json:"url"`
 
 	// Username for Xtream authentication (optional for XMLTV).
@@ -63,6 +66,9 @@ type EpgSource struct {
 	// Password for Xtream authentication (optional for XMLTV).
 	Password string `gorm:"size:255" json:"password,omitempty"`
 
+	// MacAddress is the device MAC address for Stalker authentication.
+	MacAddress string `gorm:"size:17" json:"mac_address,omitempty"`
+
 	// ApiMethod specifies the API method for Xtream sources.
 	// Only applicable when Type is "xtream". Defaults to "stream_id".
 	ApiMethod XtreamApiMethod `gorm:"size:20;default:'stream_id'" json:"api_method,omitempty"`
@@ -70,6 +76,10 @@ type EpgSource struct {
 	// UserAgent to use when fetching the source (optional).
 	UserAgent string `gorm:"size:512" json:"user_agent,omitempty"`
 
+	// ProxyURL routes all upstream connections for this source through an
+	// HTTP(S) or SOCKS5 proxy, e.g. "socks5://vpn:1080" (optional).
+	ProxyURL string `gorm:"size:512" json:"proxy_url,omitempty"`
+
 	// DetectedTimezone is the timezone detected from the EPG data (e.g., "+00:00", "+01:00", "Europe/London").
 	// This is auto-detected during ingestion and updated on each ingest. Read-only for users.
 	DetectedTimezone string `gorm:"size:50" json:"detected_timezone,omitempty"`
@@ -103,6 +113,15 @@ type EpgSource struct {
 	// LastError contains the error message from the last failed ingestion.
 	LastError string `gorm:"size:4096" json:"last_error,omitempty"`
 
+	// ETag and LastModified are the validators of the content last ingested,
+	// sent with the next fetch so an unchanged source is not downloaded again.
+	ETag         string `gorm:"size:512" json:"-"`
+	LastModified string `gorm:"size:64" json:"-"`
+
+	// ContentHash is the SHA-256 digest of the content last ingested. Content
+	// with the same digest is not ingested again.
+	ContentHash string `gorm:"size:64" json:"-"`
+
 	// ProgramCount is the number of programs from the last ingestion.
 	ProgramCount int `gorm:"default:0" json:"program_count"`
 
@@ -129,6 +148,11 @@ func (s *EpgSource) IsXtream() bool {
 	return s.Type == EpgSourceTypeXtream
 }
 
+// IsStalker returns true if this is a Stalker portal source.
+func (s *EpgSource) IsStalker() bool {
+	return s.Type == EpgSourceTypeStalker
+}
+
 // MarkIngesting sets the source status to ingesting.
 func (s *EpgSource) MarkIngesting() {
 	s.Status = EpgSourceStatusIngesting
@@ -150,6 +174,15 @@ func (s *EpgSource) MarkFailed(err error) {
 	if err != nil {
 		s.LastError = err.Error()
 	}
+	s.ClearContentVersion()
+}
+
+// ClearContentVersion forgets the content last ingested, so the next ingestion
+// processes the source in full even if its content is unchanged.
+func (s *EpgSource) ClearContentVersion() {
+	s.ETag = ""
+	s.LastModified = ""
+	s.ContentHash = ""
 }
 
 // Sanitize trims whitespace from user-provided fields.
@@ -158,7 +191,9 @@ func (s *EpgSource) Sanitize() {
 	s.URL = strings.TrimSpace(s.URL)
 	s.Username = strings.TrimSpace(s.Username)
 	s.Password = strings.TrimSpace(s.Password)
+	s.MacAddress = strings.ToUpper(strings.TrimSpace(s.MacAddress))
 	s.UserAgent = strings.TrimSpace(s.UserAgent)
+	s.ProxyURL = strings.TrimSpace(s.ProxyURL)
 }
 
 // Validate performs basic validation on the EPG source.
@@ -176,12 +211,20 @@ func (s *EpgSource) Validate() error {
 	if _, err := url.Parse(s.URL); err != nil {
 		return ErrInvalidURL
 	}
-	if s.Type != EpgSourceTypeXMLTV && s.Type != EpgSourceTypeXtream {
+	if s.Type != EpgSourceTypeXMLTV && s.Type != EpgSourceTypeXtream && s.Type != EpgSourceTypeStalker {
 		return ErrInvalidEpgSourceType
 	}
 	if s.Type == EpgSourceTypeXtream && (s.Username == "" || s.Password == "") {
 		return ErrXtreamCredentialsRequired
 	}
+	if s.ProxyURL != "" {
+		if _, err := httpclient.ParseProxyURL(s.ProxyURL); err != nil {
+			return ErrInvalidProxyURL
+		}
+	}
+	if s.Type == EpgSourceTypeStalker {
+		return validateMacAddress(s.MacAddress)
+	}
 	return nil
 }
 
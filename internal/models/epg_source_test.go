@@ -46,6 +46,26 @@ func TestEpgSource_Validate(t *testing.T) {
 			source:  &EpgSource{Name: "Test", Type: EpgSourceTypeXtream, URL: "http://example.com", Username: "user", Password: "pass"},
 			wantErr: nil,
 		},
+		{
+			name:    "stalker without MAC address",
+			source:  &EpgSource{Name: "Test", Type: EpgSourceTypeStalker, URL: "http://example.com/c/"},
+			wantErr: ErrMacAddressRequired,
+		},
+		{
+			name:    "valid stalker source",
+			source:  &EpgSource{Name: "Test", Type: EpgSourceTypeStalker, URL: "http://example.com/c/", MacAddress: "00:1A:79:12:34:56"},
+			wantErr: nil,
+		},
+		{
+			name:    "valid HTTP proxy",
+			source:  &EpgSource{Name: "Test", Type: EpgSourceTypeXMLTV, URL: "http://example.com/epg.xml", ProxyURL: "http://proxy:3128"},
+			wantErr: nil,
+		},
+		{
+			name:    "proxy without host",
+			source:  &EpgSource{Name: "Test", Type: EpgSourceTypeXMLTV, URL: "http://example.com/epg.xml", ProxyURL: "socks5://"},
+			wantErr: ErrInvalidProxyURL,
+		},
 	}
 
 	for _, tt := range tests {
@@ -28,13 +28,32 @@ var (
 	ErrInvalidURL = errors.New("invalid URL format")
 
 	// ErrInvalidSourceType indicates an invalid source type.
-	ErrInvalidSourceType = errors.New("invalid source type: must be 'm3u' or 'xtream'")
+	ErrInvalidSourceType = errors.New("invalid source type: must be 'm3u', 'xtream', 'stalker' or 'manual'")
 
 	// ErrXtreamCredentialsRequired indicates missing Xtream credentials.
 	ErrXtreamCredentialsRequired = errors.New("username and password are required for xtream sources")
 
 	// ErrInvalidEpgSourceType indicates an invalid EPG source type.
-	ErrInvalidEpgSourceType = errors.New("invalid epg source type: must be 'xmltv' or 'xtream'")
+	ErrInvalidEpgSourceType = errors.New("invalid epg source type: must be 'xmltv', 'xtream' or 'stalker'")
+
+	// ErrMacAddressRequired indicates a missing Stalker MAC address.
+	ErrMacAddressRequired = errors.New("mac_address is required for stalker sources")
+
+	// ErrInvalidMacAddress indicates a malformed MAC address.
+	ErrInvalidMacAddress = errors.New("invalid mac_address: must be six colon-separated hex pairs")
+
+	// ErrInvalidProxyURL indicates an unusable upstream proxy URL.
+	ErrInvalidProxyURL = errors.New("invalid proxy_url: must be an http, https, socks5 or socks5h URL with a host")
+
+	// ErrInvalidCustomHeaders indicates custom headers with an invalid name or value.
+	ErrInvalidCustomHeaders = errors.New("invalid custom_headers: names must be HTTP tokens and values must not contain line breaks")
+
+	// ErrInvalidExtraAccounts indicates extra accounts on a non-Xtream source, or
+	// an account without a unique username, a password or a valid limit.
+	ErrInvalidExtraAccounts = errors.New("invalid extra_accounts: only Xtream sources can have extra accounts, each with a unique username, a password and a non-negative max_concurrent_streams")
+
+	// ErrInvalidConnectionLimitPolicy indicates an unknown connection limit policy.
+	ErrInvalidConnectionLimitPolicy = errors.New("invalid connection_limit_policy: must be reject, preempt_idlest, preempt_lowest_priority or preempt_oldest")
 
 	// ErrExpressionRequired indicates a required expression field is empty.
 	ErrExpressionRequired = errors.New("expression is required")
@@ -9,6 +9,9 @@ const (
 
 	// FilterSourceTypeEPG applies to EPG/program data.
 	FilterSourceTypeEPG FilterSourceType = "epg"
+
+	// FilterSourceTypeVOD applies to movie and series data.
+	FilterSourceTypeVOD FilterSourceType = "vod"
 )
 
 // FilterAction specifies the filter behavior.
@@ -35,7 +38,7 @@ type Filter struct {
 	// Description provides additional details about the filter.
 	Description string `gorm:"size:1024" json:"description,omitempty"`
 
-	// SourceType specifies whether this applies to streams or EPG.
+	// SourceType specifies whether this applies to streams, EPG or VOD content.
 	SourceType FilterSourceType `gorm:"size:20;not null;index" json:"source_type"`
 
 	// Action specifies whether to include or exclude matching records.
@@ -68,8 +71,8 @@ func (f *Filter) Validate() error {
 	if f.SourceType == "" {
 		return ValidationError{Field: "source_type", Message: "source_type is required"}
 	}
-	if f.SourceType != FilterSourceTypeStream && f.SourceType != FilterSourceTypeEPG {
-		return ValidationError{Field: "source_type", Message: "source_type must be 'stream' or 'epg'"}
+	if f.SourceType != FilterSourceTypeStream && f.SourceType != FilterSourceTypeEPG && f.SourceType != FilterSourceTypeVOD {
+		return ValidationError{Field: "source_type", Message: "source_type must be 'stream', 'epg' or 'vod'"}
 	}
 	if f.Action == "" {
 		f.Action = FilterActionInclude
@@ -40,6 +40,16 @@ func TestFilter_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid vod include filter",
+			filter: Filter{
+				Name:       "VOD Filter",
+				Expression: "vod_year >= 2000",
+				SourceType: FilterSourceTypeVOD,
+				Action:     FilterActionInclude,
+			},
+			wantErr: false,
+		},
 		{
 			name: "empty action defaults to include",
 			filter: Filter{
@@ -93,7 +103,7 @@ func TestFilter_Validate(t *testing.T) {
 			},
 			wantErr:     true,
 			errField:    "source_type",
-			errContains: "must be 'stream' or 'epg'",
+			errContains: "must be 'stream', 'epg' or 'vod'",
 		},
 		{
 			name: "invalid action",
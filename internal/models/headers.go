@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// EncodeHeaders encodes HTTP request headers as the JSON object stored in
+// header columns. Returns an empty string if there are none.
+func EncodeHeaders(headers map[string]string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// DecodeHeaders decodes a header column. Malformed values decode to nil.
+func DecodeHeaders(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(s), &headers); err != nil {
+		return nil
+	}
+	return headers
+}
+
+// ValidateHeaders checks that header names are HTTP tokens and values contain
+// no line breaks, so they cannot inject other headers.
+func ValidateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return ErrInvalidCustomHeaders
+		}
+	}
+	return nil
+}
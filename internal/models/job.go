@@ -20,6 +20,10 @@ const (
 	JobTypeLogoCleanup JobType = "logo_cleanup"
 	// JobTypeBackup represents a scheduled database backup job.
 	JobTypeBackup JobType = "backup"
+	// JobTypeRecording represents the start of a scheduled recording.
+	JobTypeRecording JobType = "recording"
+	// JobTypeAccountCheck represents a check of Xtream source accounts.
+	JobTypeAccountCheck JobType = "account_check"
 )
 
 // Job priority constants. Higher values are executed first.
@@ -33,6 +37,8 @@ const (
 	JobPriorityIngestion = 10
 	// JobPriorityGeneration is the priority for proxy generation jobs.
 	JobPriorityGeneration = JobPriorityDefault
+	// JobPriorityRecording is the priority for recording jobs, which must start on time.
+	JobPriorityRecording = 20
 )
 
 // JobStatus represents the current status of a job.
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// LogEntry is an application log record kept by the persistent log store.
+// Fields commonly used to trace a request or stream are stored in their own
+// indexed columns; the remaining attributes are stored as JSON.
+type LogEntry struct {
+	BaseModel
+
+	// Timestamp is when the record was logged.
+	Timestamp time.Time `gorm:"not null;index" json:"timestamp"`
+
+	// Level is the record level: trace, debug, info, warn or error.
+	Level string `gorm:"size:10;not null;index" json:"level"`
+
+	// Message is the log message.
+	Message string `gorm:"type:text" json:"message"`
+
+	// Module is the component that logged the record.
+	Module string `gorm:"size:100;index" json:"module,omitempty"`
+
+	// Target is the logger name, usually the same as Module.
+	Target string `gorm:"size:100" json:"target,omitempty"`
+
+	// File and Line are the source location, when source logging is enabled.
+	File string `gorm:"size:255" json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// RequestID is the HTTP request the record was logged for.
+	RequestID string `gorm:"size:64;index" json:"request_id,omitempty"`
+
+	// SessionID is the relay session the record was logged for.
+	SessionID string `gorm:"size:64;index" json:"session_id,omitempty"`
+
+	// ChannelID is the channel the record was logged for.
+	ChannelID string `gorm:"size:64;index" json:"channel_id,omitempty"`
+
+	// Fields is the JSON object of the record's other attributes.
+	Fields string `gorm:"type:text" json:"fields,omitempty"`
+
+	// Context is the JSON object of the record's correlation attributes.
+	Context string `gorm:"type:text" json:"context,omitempty"`
+}
+
+// TableName returns the table name for LogEntry.
+func (LogEntry) TableName() string {
+	return "log_entries"
+}
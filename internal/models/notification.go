@@ -0,0 +1,223 @@
+package models
+
+import (
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// NotificationTargetType identifies how notifications are delivered to a target.
+type NotificationTargetType string
+
+const (
+	// NotificationTargetWebhook posts events as signed JSON to a URL.
+	NotificationTargetWebhook NotificationTargetType = "webhook"
+	// NotificationTargetNtfy publishes events to an ntfy topic URL.
+	NotificationTargetNtfy NotificationTargetType = "ntfy"
+	// NotificationTargetGotify sends events to a Gotify server.
+	NotificationTargetGotify NotificationTargetType = "gotify"
+	// NotificationTargetEmail emails events through the configured SMTP server.
+	NotificationTargetEmail NotificationTargetType = "email"
+)
+
+// IsValid reports whether the target type is known.
+func (t NotificationTargetType) IsValid() bool {
+	switch t {
+	case NotificationTargetWebhook, NotificationTargetNtfy, NotificationTargetGotify, NotificationTargetEmail:
+		return true
+	}
+	return false
+}
+
+// NotificationEventType identifies an operational event that targets can subscribe to.
+type NotificationEventType string
+
+const (
+	// NotificationEventIngestionFailed is sent when a stream or EPG source fails to ingest.
+	NotificationEventIngestionFailed NotificationEventType = "ingestion.failed"
+	// NotificationEventProxyGenerationFailed is sent when a proxy fails to generate.
+	NotificationEventProxyGenerationFailed NotificationEventType = "proxy.generation_failed"
+	// NotificationEventBackupFailed is sent when a database backup fails.
+	NotificationEventBackupFailed NotificationEventType = "backup.failed"
+	// NotificationEventJobFailed is sent when a scheduled job fails and will not be retried.
+	NotificationEventJobFailed NotificationEventType = "job.failed"
+	// NotificationEventCircuitOpened is sent when an upstream circuit breaker opens.
+	NotificationEventCircuitOpened NotificationEventType = "circuit_breaker.opened"
+	// NotificationEventCircuitClosed is sent when an open upstream circuit breaker closes again.
+	NotificationEventCircuitClosed NotificationEventType = "circuit_breaker.closed"
+	// NotificationEventDaemonOffline is sent when an ffmpegd daemon stops sending heartbeats.
+	NotificationEventDaemonOffline NotificationEventType = "daemon.offline"
+	// NotificationEventDaemonRecovered is sent when an unhealthy ffmpegd daemon resumes heartbeats.
+	NotificationEventDaemonRecovered NotificationEventType = "daemon.recovered"
+	// NotificationEventTest is sent by the test endpoint, to every target regardless of subscriptions.
+	NotificationEventTest NotificationEventType = "test"
+)
+
+// NotificationEventTypes lists the event types targets can subscribe to.
+var NotificationEventTypes = []NotificationEventType{
+	NotificationEventIngestionFailed,
+	NotificationEventProxyGenerationFailed,
+	NotificationEventBackupFailed,
+	NotificationEventJobFailed,
+	NotificationEventCircuitOpened,
+	NotificationEventCircuitClosed,
+	NotificationEventDaemonOffline,
+	NotificationEventDaemonRecovered,
+}
+
+// NotificationTarget is a destination for operational event notifications.
+type NotificationTarget struct {
+	BaseModel
+
+	// Name is the unique target name.
+	Name string `gorm:"uniqueIndex;not null;size:100" json:"name"`
+
+	// Type is how notifications are delivered.
+	Type NotificationTargetType `gorm:"not null;size:20" json:"type"`
+
+	// Enabled indicates whether notifications are delivered to the target.
+	// Using pointer to distinguish between "not set" (nil->default true) and "explicitly false".
+	Enabled *bool `gorm:"default:true" json:"enabled"`
+
+	// Events is the comma-separated list of subscribed event types (empty = all events).
+	Events string `gorm:"size:1000" json:"events"`
+
+	// URL is the webhook URL, ntfy topic URL or Gotify server URL.
+	URL string `gorm:"size:2048" json:"url,omitempty"`
+
+	// Secret is the webhook HMAC signing key, ntfy access token or Gotify application token.
+	Secret string `gorm:"size:255" json:"-"`
+
+	// EmailTo is the comma-separated list of email recipients.
+	EmailTo string `gorm:"size:1000" json:"email_to,omitempty"`
+}
+
+// TableName returns the table name for NotificationTarget.
+func (NotificationTarget) TableName() string {
+	return "notification_targets"
+}
+
+// Validate checks if the notification target is valid.
+func (t *NotificationTarget) Validate() error {
+	if t.Name == "" {
+		return ValidationError{Field: "name", Message: "name is required"}
+	}
+	if !t.Type.IsValid() {
+		return ValidationError{Field: "type", Message: "type must be one of: webhook, ntfy, gotify, email"}
+	}
+	for _, event := range t.EventList() {
+		if !slices.Contains(NotificationEventTypes, event) {
+			return ValidationError{Field: "events", Message: "unknown event type " + string(event)}
+		}
+	}
+
+	if t.Type == NotificationTargetEmail {
+		recipients := t.Recipients()
+		if len(recipients) == 0 {
+			return ValidationError{Field: "email_to", Message: "at least one recipient is required"}
+		}
+		for _, addr := range recipients {
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return ValidationError{Field: "email_to", Message: "invalid email address " + addr}
+			}
+		}
+		return nil
+	}
+
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ValidationError{Field: "url", Message: "url must be an http or https URL"}
+	}
+	if t.Type == NotificationTargetGotify && t.Secret == "" {
+		return ValidationError{Field: "secret", Message: "a Gotify application token is required"}
+	}
+	return nil
+}
+
+// IsEnabled reports whether notifications are delivered to the target.
+func (t *NotificationTarget) IsEnabled() bool {
+	return BoolVal(t.Enabled)
+}
+
+// EventList returns the subscribed event types. An empty list subscribes to all events.
+func (t *NotificationTarget) EventList() []NotificationEventType {
+	var events []NotificationEventType
+	for _, e := range splitList(t.Events) {
+		events = append(events, NotificationEventType(e))
+	}
+	return events
+}
+
+// Subscribes reports whether the target receives events of the given type.
+// Test events are always delivered.
+func (t *NotificationTarget) Subscribes(event NotificationEventType) bool {
+	events := t.EventList()
+	return event == NotificationEventTest || len(events) == 0 || slices.Contains(events, event)
+}
+
+// Recipients returns the email recipients.
+func (t *NotificationTarget) Recipients() []string {
+	return splitList(t.EmailTo)
+}
+
+// splitList splits a comma-separated list, trimming whitespace and dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// NotificationDeliveryStatus is the outcome of delivering an event to a target.
+type NotificationDeliveryStatus string
+
+const (
+	// NotificationDelivered indicates the target accepted the event.
+	NotificationDelivered NotificationDeliveryStatus = "delivered"
+	// NotificationFailed indicates every delivery attempt failed.
+	NotificationFailed NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery records the outcome of delivering an event to a target.
+type NotificationDelivery struct {
+	BaseModel
+
+	// TargetID is the target the event was delivered to.
+	TargetID ULID `gorm:"type:varchar(26);not null;index" json:"target_id"`
+
+	// TargetName is the name of the target at delivery time.
+	TargetName string `gorm:"size:100" json:"target_name"`
+
+	// EventID identifies the event; it is the same for every target the event was sent to.
+	EventID string `gorm:"size:26;index" json:"event_id"`
+
+	// EventType is the type of the delivered event.
+	EventType NotificationEventType `gorm:"not null;size:50;index" json:"event_type"`
+
+	// Title is the event title.
+	Title string `gorm:"size:255" json:"title"`
+
+	// Status is the outcome of the delivery.
+	Status NotificationDeliveryStatus `gorm:"not null;size:20;index" json:"status"`
+
+	// Attempts is the number of delivery attempts made.
+	Attempts int `json:"attempts"`
+
+	// StatusCode is the HTTP status of the last attempt (0 for email or connection errors).
+	StatusCode int `json:"status_code,omitempty"`
+
+	// Error is the error of the last failed attempt.
+	Error string `gorm:"size:1000" json:"error,omitempty"`
+
+	// DurationMs is the time taken across all attempts, including retry delays.
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// TableName returns the table name for NotificationDelivery.
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationTarget_Validate(t *testing.T) {
+	valid := func() *NotificationTarget {
+		return &NotificationTarget{
+			Name: "ops",
+			Type: NotificationTargetWebhook,
+			URL:  "https://hooks.example.com/tvarr",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(n *NotificationTarget)
+		wantErr string
+	}{
+		{"valid", func(*NotificationTarget) {}, ""},
+		{"missing name", func(n *NotificationTarget) { n.Name = "" }, "name"},
+		{"invalid type", func(n *NotificationTarget) { n.Type = "slack" }, "type"},
+		{"subscribed events", func(n *NotificationTarget) { n.Events = "ingestion.failed, daemon.offline" }, ""},
+		{"unknown event", func(n *NotificationTarget) { n.Events = "ingestion.failed,stream.started" }, "events"},
+		{"test event not subscribable", func(n *NotificationTarget) { n.Events = "test" }, "events"},
+		{"missing url", func(n *NotificationTarget) { n.URL = "" }, "url"},
+		{"non-http url", func(n *NotificationTarget) { n.URL = "ftp://example.com" }, "url"},
+		{"ntfy without token", func(n *NotificationTarget) { n.Type = NotificationTargetNtfy }, ""},
+		{"gotify without token", func(n *NotificationTarget) { n.Type = NotificationTargetGotify }, "secret"},
+		{"gotify with token", func(n *NotificationTarget) { n.Type = NotificationTargetGotify; n.Secret = "token" }, ""},
+		{"email", func(n *NotificationTarget) {
+			n.Type = NotificationTargetEmail
+			n.URL = ""
+			n.EmailTo = "ops@example.com, Admin <admin@example.com>"
+		}, ""},
+		{"email without recipients", func(n *NotificationTarget) { n.Type = NotificationTargetEmail }, "email_to"},
+		{"email invalid recipient", func(n *NotificationTarget) {
+			n.Type = NotificationTargetEmail
+			n.EmailTo = "ops@example.com,not-an-address"
+		}, "email_to"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := valid()
+			tt.modify(n)
+			err := n.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var ve ValidationError
+			if assert.ErrorAs(t, err, &ve) {
+				assert.Equal(t, tt.wantErr, ve.Field)
+			}
+		})
+	}
+}
+
+func TestNotificationTarget_Subscribes(t *testing.T) {
+	all := &NotificationTarget{}
+	assert.True(t, all.Subscribes(NotificationEventIngestionFailed))
+	assert.True(t, all.Subscribes(NotificationEventDaemonOffline))
+
+	some := &NotificationTarget{Events: "backup.failed, job.failed"}
+	assert.True(t, some.Subscribes(NotificationEventBackupFailed))
+	assert.True(t, some.Subscribes(NotificationEventJobFailed))
+	assert.False(t, some.Subscribes(NotificationEventIngestionFailed))
+	assert.True(t, some.Subscribes(NotificationEventTest))
+}
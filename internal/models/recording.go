@@ -0,0 +1,188 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// RecordingStatus represents the lifecycle state of a recording.
+type RecordingStatus string
+
+const (
+	// RecordingStatusScheduled means the recording is waiting for its start time.
+	RecordingStatusScheduled RecordingStatus = "scheduled"
+	// RecordingStatusRecording means the recorder is currently capturing the channel.
+	RecordingStatusRecording RecordingStatus = "recording"
+	// RecordingStatusCompleted means the recording window ended and a file was written.
+	RecordingStatusCompleted RecordingStatus = "completed"
+	// RecordingStatusFailed means nothing could be captured.
+	RecordingStatusFailed RecordingStatus = "failed"
+	// RecordingStatusCancelled means the recording was stopped before its window ended.
+	RecordingStatusCancelled RecordingStatus = "cancelled"
+)
+
+// RecordingFormat is the container a recording is written in.
+type RecordingFormat string
+
+const (
+	// RecordingFormatMPEGTS writes a continuous MPEG transport stream (.ts).
+	RecordingFormatMPEGTS RecordingFormat = "mpegts"
+	// RecordingFormatFMP4 writes a fragmented MP4 file (.mp4).
+	RecordingFormatFMP4 RecordingFormat = "fmp4"
+)
+
+// IsValid reports whether f is a supported recording format.
+func (f RecordingFormat) IsValid() bool {
+	return f == RecordingFormatMPEGTS || f == RecordingFormatFMP4
+}
+
+// Extension returns the file extension for the format, including the dot.
+func (f RecordingFormat) Extension() string {
+	if f == RecordingFormatFMP4 {
+		return ".mp4"
+	}
+	return ".ts"
+}
+
+// ContentType returns the MIME type for files in this format.
+func (f RecordingFormat) ContentType() string {
+	if f == RecordingFormatFMP4 {
+		return "video/mp4"
+	}
+	return "video/mp2t"
+}
+
+// Recording is a scheduled or finished capture of a channel, usually for one EPG programme.
+type Recording struct {
+	BaseModel
+
+	// ChannelID is the channel being recorded. It is not a foreign key: recordings
+	// outlive channels that disappear on re-ingestion.
+	ChannelID ULID `gorm:"type:varchar(26);not null;index" json:"channel_id"`
+
+	// ChannelName is the channel name at scheduling time.
+	ChannelName string `gorm:"size:512" json:"channel_name"`
+
+	// SourceID is the stream source of the channel at scheduling time.
+	// It is used to detect conflicts against the source's MaxConcurrentStreams.
+	SourceID ULID `gorm:"type:varchar(26);index" json:"source_id"`
+
+	// ProgramID is the EPG programme this recording was scheduled from (nil for manual recordings).
+	ProgramID *ULID `gorm:"type:varchar(26);index" json:"program_id,omitempty"`
+
+	// RuleID is the recording rule that scheduled this recording (nil if scheduled directly).
+	RuleID *ULID `gorm:"type:varchar(26);index" json:"rule_id,omitempty"`
+
+	// Title is the programme title.
+	Title string `gorm:"not null;size:512" json:"title"`
+
+	// SubTitle is the episode title or subtitle.
+	SubTitle string `gorm:"size:512" json:"sub_title,omitempty"`
+
+	// Description is the programme description.
+	Description string `gorm:"type:text" json:"description,omitempty"`
+
+	// SeasonNumber is the programme's season number (0 means unknown).
+	SeasonNumber int `gorm:"default:0" json:"season_number,omitempty"`
+
+	// EpisodeNumber is the programme's episode number (0 means unknown).
+	EpisodeNumber int `gorm:"default:0" json:"episode_number,omitempty"`
+
+	// EpisodeID is the EPG's unique programme identifier (e.g. dd_progid), used to
+	// recognise repeats of an episode that has already been recorded.
+	EpisodeID string `gorm:"size:100;index" json:"episode_id,omitempty"`
+
+	// StartTime is the programme start, excluding padding.
+	StartTime time.Time `gorm:"not null;index" json:"start_time"`
+
+	// EndTime is the programme end, excluding padding.
+	EndTime time.Time `gorm:"not null;index" json:"end_time"`
+
+	// PaddingBefore is the number of seconds recorded before StartTime.
+	PaddingBefore int `gorm:"default:0" json:"padding_before"`
+
+	// PaddingAfter is the number of seconds recorded after EndTime.
+	PaddingAfter int `gorm:"default:0" json:"padding_after"`
+
+	// Format is the container the recording is written in.
+	Format RecordingFormat `gorm:"not null;size:20;default:'mpegts'" json:"format"`
+
+	// Status is the current state of the recording.
+	Status RecordingStatus `gorm:"not null;size:20;default:'scheduled';index" json:"status"`
+
+	// FilePath is the recording file relative to the storage base directory.
+	FilePath string `gorm:"size:1024" json:"file_path,omitempty"`
+
+	// FileSize is the size of the recording file in bytes.
+	FileSize int64 `gorm:"default:0" json:"file_size"`
+
+	// LastError is the most recent capture error, if any.
+	LastError string `gorm:"size:4096" json:"last_error,omitempty"`
+
+	// StartedAt is when capture began.
+	StartedAt *time.Time `json:"started_at,omitempty"`
+
+	// CompletedAt is when capture finished.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName returns the table name for Recording.
+func (Recording) TableName() string {
+	return "recordings"
+}
+
+// Validate checks if the recording is valid.
+func (r *Recording) Validate() error {
+	if r.ChannelID.IsZero() {
+		return ValidationError{Field: "channel_id", Message: "channel_id is required"}
+	}
+	if r.Title == "" {
+		return ValidationError{Field: "title", Message: "title is required"}
+	}
+	if !r.EndTime.After(r.StartTime) {
+		return ValidationError{Field: "end_time", Message: "end_time must be after start_time"}
+	}
+	if r.PaddingBefore < 0 || r.PaddingAfter < 0 {
+		return ValidationError{Field: "padding", Message: "padding cannot be negative"}
+	}
+	if !r.Format.IsValid() {
+		return ValidationError{Field: "format", Message: "format must be mpegts or fmp4"}
+	}
+	return nil
+}
+
+// RecordStart returns when capture starts, including padding.
+func (r *Recording) RecordStart() time.Time {
+	return r.StartTime.Add(-time.Duration(r.PaddingBefore) * time.Second)
+}
+
+// RecordEnd returns when capture ends, including padding.
+func (r *Recording) RecordEnd() time.Time {
+	return r.EndTime.Add(time.Duration(r.PaddingAfter) * time.Second)
+}
+
+// IsActive reports whether the recording is scheduled or in progress.
+func (r *Recording) IsActive() bool {
+	return r.Status == RecordingStatusScheduled || r.Status == RecordingStatusRecording
+}
+
+// Overlaps reports whether the padded recording window overlaps [start, end).
+func (r *Recording) Overlaps(start, end time.Time) bool {
+	return r.RecordStart().Before(end) && start.Before(r.RecordEnd())
+}
+
+// IsSameEpisode reports whether r records the same episode as the programme p:
+// the same airing, the same EPG programme identifier, or the same title, season
+// and episode numbers. Programmes without any of these are never considered
+// repeats of a different airing.
+func (r *Recording) IsSameEpisode(p *EpgProgram) bool {
+	if r.ProgramID != nil && *r.ProgramID == p.ID {
+		return true
+	}
+	if p.ProgramID != "" && r.EpisodeID == p.ProgramID {
+		return true
+	}
+	return p.SeasonNumber > 0 && p.EpisodeNumber > 0 &&
+		r.SeasonNumber == p.SeasonNumber && r.EpisodeNumber == p.EpisodeNumber &&
+		strings.EqualFold(r.Title, p.Title)
+}
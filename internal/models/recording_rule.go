@@ -0,0 +1,53 @@
+package models
+
+// RecordingRule is a standing auto-record rule. After each EPG ingestion, upcoming
+// programmes are matched against Expression and matching airings are scheduled
+// as recordings, skipping episodes that have already been recorded or scheduled.
+type RecordingRule struct {
+	BaseModel
+
+	// Name is a human-readable name for the rule.
+	Name string `gorm:"size:255;not null" json:"name"`
+
+	// Description provides additional details about the rule.
+	Description string `gorm:"size:1024" json:"description,omitempty"`
+
+	// Expression selects the programmes to record. It can use EPG programme fields
+	// (programme_title, programme_season, ...) and fields of the channel airing them
+	// (channel_name, group_title, source_name, ...).
+	Expression string `gorm:"type:text;not null" json:"expression"`
+
+	// IsEnabled determines if the rule schedules new recordings.
+	IsEnabled *bool `gorm:"default:true" json:"is_enabled"`
+
+	// PaddingBefore overrides the default seconds recorded before each programme.
+	PaddingBefore *int `json:"padding_before,omitempty"`
+
+	// PaddingAfter overrides the default seconds recorded after each programme.
+	PaddingAfter *int `json:"padding_after,omitempty"`
+
+	// Format overrides the default recording container.
+	Format RecordingFormat `gorm:"size:20" json:"format,omitempty"`
+}
+
+// TableName returns the table name for RecordingRule.
+func (RecordingRule) TableName() string {
+	return "recording_rules"
+}
+
+// Validate checks if the rule is valid. The expression syntax is checked by the service.
+func (r *RecordingRule) Validate() error {
+	if r.Name == "" {
+		return ValidationError{Field: "name", Message: "name is required"}
+	}
+	if r.Expression == "" {
+		return ValidationError{Field: "expression", Message: "expression is required"}
+	}
+	if (r.PaddingBefore != nil && *r.PaddingBefore < 0) || (r.PaddingAfter != nil && *r.PaddingAfter < 0) {
+		return ValidationError{Field: "padding", Message: "padding cannot be negative"}
+	}
+	if r.Format != "" && !r.Format.IsValid() {
+		return ValidationError{Field: "format", Message: "format must be mpegts or fmp4"}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordingRule_TableName(t *testing.T) {
+	assert.Equal(t, "recording_rules", RecordingRule{}.TableName())
+}
+
+func TestRecordingRule_Validate(t *testing.T) {
+	negative := -1
+	valid := func() *RecordingRule {
+		return &RecordingRule{
+			Name:       "Match of the Day",
+			Expression: `programme_title matches "^Match of the Day"`,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(r *RecordingRule)
+		wantErr string
+	}{
+		{"valid", func(*RecordingRule) {}, ""},
+		{"missing name", func(r *RecordingRule) { r.Name = "" }, "name"},
+		{"missing expression", func(r *RecordingRule) { r.Expression = "" }, "expression"},
+		{"negative padding", func(r *RecordingRule) { r.PaddingAfter = &negative }, "padding"},
+		{"invalid format", func(r *RecordingRule) { r.Format = "mkv" }, "format"},
+		{"explicit format", func(r *RecordingRule) { r.Format = RecordingFormatFMP4 }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(r)
+			err := r.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var ve ValidationError
+			if assert.ErrorAs(t, err, &ve) {
+				assert.Equal(t, tt.wantErr, ve.Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecording_TableName(t *testing.T) {
+	assert.Equal(t, "recordings", Recording{}.TableName())
+}
+
+func TestRecording_Validate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	valid := func() *Recording {
+		return &Recording{
+			ChannelID: NewULID(),
+			Title:     "News",
+			StartTime: start,
+			EndTime:   start.Add(30 * time.Minute),
+			Format:    RecordingFormatMPEGTS,
+		}
+	}
+
+	assert.NoError(t, valid().Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(r *Recording)
+		field  string
+	}{
+		{"missing channel", func(r *Recording) { r.ChannelID = ULID{} }, "channel_id"},
+		{"missing title", func(r *Recording) { r.Title = "" }, "title"},
+		{"end before start", func(r *Recording) { r.EndTime = r.StartTime }, "end_time"},
+		{"negative padding", func(r *Recording) { r.PaddingAfter = -1 }, "padding"},
+		{"unknown format", func(r *Recording) { r.Format = "mkv" }, "format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := valid()
+			tt.mutate(rec)
+			err := rec.Validate()
+			var ve ValidationError
+			if assert.ErrorAs(t, err, &ve) {
+				assert.Equal(t, tt.field, ve.Field)
+			}
+		})
+	}
+}
+
+func TestRecording_Window(t *testing.T) {
+	start := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	rec := &Recording{
+		StartTime:     start,
+		EndTime:       start.Add(time.Hour),
+		PaddingBefore: 120,
+		PaddingAfter:  300,
+	}
+
+	assert.Equal(t, start.Add(-2*time.Minute), rec.RecordStart())
+	assert.Equal(t, start.Add(65*time.Minute), rec.RecordEnd())
+
+	assert.True(t, rec.Overlaps(start.Add(-time.Hour), start.Add(-time.Minute)))
+	assert.False(t, rec.Overlaps(start.Add(-time.Hour), start.Add(-2*time.Minute)))
+	assert.True(t, rec.Overlaps(start.Add(64*time.Minute), start.Add(2*time.Hour)))
+	assert.False(t, rec.Overlaps(start.Add(65*time.Minute), start.Add(2*time.Hour)))
+}
+
+func TestRecordingFormat(t *testing.T) {
+	assert.Equal(t, ".ts", RecordingFormatMPEGTS.Extension())
+	assert.Equal(t, ".mp4", RecordingFormatFMP4.Extension())
+	assert.Equal(t, "video/mp2t", RecordingFormatMPEGTS.ContentType())
+	assert.Equal(t, "video/mp4", RecordingFormatFMP4.ContentType())
+	assert.False(t, RecordingFormat("").IsValid())
+}
+
+func TestRecording_IsSameEpisode(t *testing.T) {
+	programID := NewULID()
+	rec := &Recording{
+		ProgramID:     &programID,
+		Title:         "Match of the Day",
+		SeasonNumber:  3,
+		EpisodeNumber: 7,
+		EpisodeID:     "EP0001",
+	}
+
+	tests := []struct {
+		name    string
+		program *EpgProgram
+		want    bool
+	}{
+		{"same airing", &EpgProgram{BaseModel: BaseModel{ID: programID}, Title: "Other"}, true},
+		{"same programme identifier", &EpgProgram{Title: "Renamed", ProgramID: "EP0001"}, true},
+		{"same season and episode", &EpgProgram{Title: "match of the day", SeasonNumber: 3, EpisodeNumber: 7}, true},
+		{"different episode", &EpgProgram{Title: "Match of the Day", SeasonNumber: 3, EpisodeNumber: 8}, false},
+		{"same numbers different title", &EpgProgram{Title: "Football Focus", SeasonNumber: 3, EpisodeNumber: 7}, false},
+		{"no episode information", &EpgProgram{Title: "Match of the Day"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rec.IsSameEpisode(tt.program))
+		})
+	}
+}
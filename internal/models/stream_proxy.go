@@ -61,6 +61,101 @@ func IsValidNumberingMode(mode NumberingMode) bool {
 	}
 }
 
+// DedupMode determines whether channels duplicated across sources are collapsed.
+type DedupMode string
+
+const (
+	// DedupModeKeep keeps every channel, including duplicates from other sources.
+	DedupModeKeep DedupMode = "keep"
+	// DedupModeCollapse keeps the best candidate of each duplicate group and records
+	// the others as its alternates.
+	DedupModeCollapse DedupMode = "collapse"
+)
+
+// IsValidDedupMode returns true if the mode is a valid dedup mode.
+func IsValidDedupMode(mode DedupMode) bool {
+	switch mode {
+	case DedupModeKeep, DedupModeCollapse:
+		return true
+	default:
+		return false
+	}
+}
+
+// DedupIdentity determines how channels are recognised as the same channel.
+type DedupIdentity string
+
+const (
+	// DedupIdentityTvgID groups channels with the same tvg-id.
+	DedupIdentityTvgID DedupIdentity = "tvg_id"
+	// DedupIdentityName groups channels by name, ignoring case, punctuation and
+	// quality suffixes such as HD or 4K.
+	DedupIdentityName DedupIdentity = "name"
+	// DedupIdentityExpression groups channels by the regex captures of DedupExpression.
+	DedupIdentityExpression DedupIdentity = "expression"
+)
+
+// IsValidDedupIdentity returns true if the identity is a valid dedup identity.
+func IsValidDedupIdentity(identity DedupIdentity) bool {
+	switch identity {
+	case DedupIdentityTvgID, DedupIdentityName, DedupIdentityExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+// DedupPreference determines which channel of a duplicate group is kept.
+type DedupPreference string
+
+const (
+	// DedupPreferencePriority keeps the channel from the highest priority source.
+	DedupPreferencePriority DedupPreference = "priority"
+	// DedupPreferenceQuality keeps the channel with the best probed resolution and
+	// bitrate, falling back to source priority for unprobed streams.
+	DedupPreferenceQuality DedupPreference = "quality"
+)
+
+// IsValidDedupPreference returns true if the preference is a valid dedup preference.
+func IsValidDedupPreference(preference DedupPreference) bool {
+	switch preference {
+	case DedupPreferencePriority, DedupPreferenceQuality:
+		return true
+	default:
+		return false
+	}
+}
+
+// ConnectionLimitPolicy determines what happens when a relay session needs a
+// connection to a source that is at its connection limit.
+type ConnectionLimitPolicy string
+
+const (
+	// ConnectionLimitPolicyReject starts the session on a failover alternate with
+	// a free connection, or refuses it.
+	ConnectionLimitPolicyReject ConnectionLimitPolicy = "reject"
+	// ConnectionLimitPolicyPreemptIdlest stops the source's session with the
+	// fewest clients, the longest idle first.
+	ConnectionLimitPolicyPreemptIdlest ConnectionLimitPolicy = "preempt_idlest"
+	// ConnectionLimitPolicyPreemptLowestPriority stops the source's session
+	// started for the lowest priority proxy, if it is lower than the new one's.
+	ConnectionLimitPolicyPreemptLowestPriority ConnectionLimitPolicy = "preempt_lowest_priority"
+	// ConnectionLimitPolicyPreemptOldest stops the source's longest running session.
+	ConnectionLimitPolicyPreemptOldest ConnectionLimitPolicy = "preempt_oldest"
+)
+
+// IsValidConnectionLimitPolicy returns true if the policy is empty or a valid
+// connection limit policy.
+func IsValidConnectionLimitPolicy(policy ConnectionLimitPolicy) bool {
+	switch policy {
+	case "", ConnectionLimitPolicyReject, ConnectionLimitPolicyPreemptIdlest,
+		ConnectionLimitPolicyPreemptLowestPriority, ConnectionLimitPolicyPreemptOldest:
+		return true
+	default:
+		return false
+	}
+}
+
 // StreamProxy represents a proxy configuration that combines sources,
 // applies filters and mappings, and generates output playlists.
 type StreamProxy struct {
@@ -93,6 +188,33 @@ type StreamProxy struct {
 	// Default is 100 (groups get numbers 100-199, 200-299, etc.).
 	GroupNumberingSize int `gorm:"default:100" json:"group_numbering_size"`
 
+	// DedupMode determines whether channels duplicated across sources are collapsed.
+	// Options: keep (default), collapse
+	DedupMode DedupMode `gorm:"not null;default:'keep';size:20" json:"dedup_mode"`
+
+	// DedupIdentity determines how duplicates are recognised when collapsing.
+	// Options: tvg_id (default), name, expression
+	DedupIdentity DedupIdentity `gorm:"not null;default:'tvg_id';size:20" json:"dedup_identity"`
+
+	// DedupExpression is the expression used with the expression identity. Channels
+	// whose regex captures are equal are duplicates; non-matching channels are kept.
+	DedupExpression string `gorm:"size:1024" json:"dedup_expression,omitempty"`
+
+	// DedupPreference determines which duplicate is kept when collapsing.
+	// Options: priority (default), quality
+	DedupPreference DedupPreference `gorm:"not null;default:'priority';size:20" json:"dedup_preference"`
+
+	// AutoMatchEpg assigns tvg-ids to channels with an empty or unknown tvg-id by
+	// matching their names against the display names of the proxy's EPG sources.
+	AutoMatchEpg bool `gorm:"default:false" json:"auto_match_epg"`
+
+	// IncludeVod publishes the movies of the proxy's sources as a separate VOD playlist.
+	IncludeVod bool `gorm:"default:false" json:"include_vod"`
+
+	// IncludeSeries publishes the series episodes of the proxy's sources as a
+	// separate series playlist.
+	IncludeSeries bool `gorm:"default:false" json:"include_series"`
+
 	// UpstreamTimeout is the timeout in seconds for upstream connections.
 	UpstreamTimeout int `gorm:"default:30" json:"upstream_timeout"`
 
@@ -102,6 +224,14 @@ type StreamProxy struct {
 	// MaxConcurrentStreams is the maximum concurrent streams (0 = unlimited).
 	MaxConcurrentStreams int `gorm:"default:0" json:"max_concurrent_streams"`
 
+	// ConnectionLimitPolicy overrides the connection limit policy of the sources
+	// of streams relayed for this proxy.
+	ConnectionLimitPolicy ConnectionLimitPolicy `gorm:"size:30" json:"connection_limit_policy,omitempty"`
+
+	// Priority ranks the proxy's relay sessions for the preempt_lowest_priority
+	// policy. Sessions of lower priority proxies are stopped first.
+	Priority int `gorm:"default:0" json:"priority"`
+
 	// HLSCollapse enables HLS collapse mode for proxy mode streaming.
 	// When enabled, multi-variant HLS streams are converted to continuous MPEG-TS.
 	HLSCollapse bool `gorm:"default:false" json:"hls_collapse"`
@@ -184,6 +314,12 @@ func (p *StreamProxy) Validate() error {
 	if p.Name == "" {
 		return ErrNameRequired
 	}
+	if p.DedupMode == DedupModeCollapse && p.DedupIdentity == DedupIdentityExpression && p.DedupExpression == "" {
+		return ValidationError{Field: "dedup_expression", Message: "dedup_expression is required for the expression identity"}
+	}
+	if !IsValidConnectionLimitPolicy(p.ConnectionLimitPolicy) {
+		return ValidationError{Field: "connection_limit_policy", Message: "connection_limit_policy must be reject, preempt_idlest, preempt_lowest_priority or preempt_oldest"}
+	}
 	return nil
 }
 
@@ -380,3 +516,48 @@ func (pmr *ProxyMappingRule) BeforeCreate(tx *gorm.DB) error {
 	}
 	return pmr.Validate()
 }
+
+// ProxyChannelAlternate records a duplicate channel that a proxy's deduplication
+// collapsed into the channel it kept. The relay fails over to alternates in Priority order.
+type ProxyChannelAlternate struct {
+	BaseModel
+
+	// ProxyID is the ID of the proxy.
+	ProxyID ULID `gorm:"not null;index:idx_proxy_channel_alternate" json:"proxy_id"`
+
+	// ChannelID is the ID of the channel kept in the proxy output.
+	ChannelID ULID `gorm:"not null;index:idx_proxy_channel_alternate" json:"channel_id"`
+
+	// AlternateChannelID is the ID of the duplicate channel that was collapsed.
+	AlternateChannelID ULID `gorm:"not null" json:"alternate_channel_id"`
+
+	// Priority orders the alternates of a channel (lower = tried first).
+	Priority int `gorm:"column:priority;default:0" json:"priority"`
+
+	// Proxy is the relationship to the parent proxy.
+	Proxy *StreamProxy `gorm:"foreignKey:ProxyID;constraint:OnDelete:CASCADE" json:"proxy,omitempty"`
+}
+
+// TableName returns the table name for ProxyChannelAlternate.
+func (ProxyChannelAlternate) TableName() string {
+	return "proxy_channel_alternates"
+}
+
+// Validate performs basic validation on the proxy channel alternate.
+func (pca *ProxyChannelAlternate) Validate() error {
+	if pca.ProxyID.IsZero() {
+		return ErrProxyIDRequired
+	}
+	if pca.ChannelID.IsZero() || pca.AlternateChannelID.IsZero() {
+		return ErrChannelIDRequired
+	}
+	return nil
+}
+
+// BeforeCreate is a GORM hook that validates and generates ULID.
+func (pca *ProxyChannelAlternate) BeforeCreate(tx *gorm.DB) error {
+	if err := pca.BaseModel.BeforeCreate(tx); err != nil {
+		return err
+	}
+	return pca.Validate()
+}
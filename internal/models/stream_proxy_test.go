@@ -48,6 +48,41 @@ func TestStreamProxy_Validate(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "collapse by expression without expression",
+			proxy: StreamProxy{
+				Name:          "Dedup Proxy",
+				DedupMode:     DedupModeCollapse,
+				DedupIdentity: DedupIdentityExpression,
+			},
+			wantErr: ValidationError{Field: "dedup_expression", Message: "dedup_expression is required for the expression identity"},
+		},
+		{
+			name: "keep by expression without expression",
+			proxy: StreamProxy{
+				Name:          "Dedup Proxy",
+				DedupMode:     DedupModeKeep,
+				DedupIdentity: DedupIdentityExpression,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "valid connection limit policy",
+			proxy: StreamProxy{
+				Name:                  "Family Proxy",
+				ConnectionLimitPolicy: ConnectionLimitPolicyPreemptLowestPriority,
+				Priority:              10,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unknown connection limit policy",
+			proxy: StreamProxy{
+				Name:                  "Family Proxy",
+				ConnectionLimitPolicy: "preempt_newest",
+			},
+			wantErr: ValidationError{Field: "connection_limit_policy", Message: "connection_limit_policy must be reject, preempt_idlest, preempt_lowest_priority or preempt_oldest"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -125,6 +160,21 @@ func TestIsValidProxyMode(t *testing.T) {
 	}
 }
 
+func TestIsValidDedupSettings(t *testing.T) {
+	assert.True(t, IsValidDedupMode(DedupModeKeep))
+	assert.True(t, IsValidDedupMode(DedupModeCollapse))
+	assert.False(t, IsValidDedupMode(DedupMode("merge")))
+
+	assert.True(t, IsValidDedupIdentity(DedupIdentityTvgID))
+	assert.True(t, IsValidDedupIdentity(DedupIdentityName))
+	assert.True(t, IsValidDedupIdentity(DedupIdentityExpression))
+	assert.False(t, IsValidDedupIdentity(DedupIdentity("tvg-id")))
+
+	assert.True(t, IsValidDedupPreference(DedupPreferencePriority))
+	assert.True(t, IsValidDedupPreference(DedupPreferenceQuality))
+	assert.False(t, IsValidDedupPreference(DedupPreference("")))
+}
+
 func TestStreamProxy_MarkGenerating(t *testing.T) {
 	proxy := StreamProxy{
 		Name:      "Test",
@@ -1,9 +1,13 @@
 package models
 
 import (
+	"encoding/json"
 	"net/url"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/jmylchreest/tvarr/pkg/httpclient"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +23,10 @@ const (
 	// Manual sources do not fetch from a URL; channels are defined statically
 	// in the manual_stream_channels table and materialized during ingestion.
 	SourceTypeManual SourceType = "manual"
+	// SourceTypeStalker represents a Stalker / Ministra portal source, which
+	// authenticates by MAC address. Channel stream URLs hold the portal's
+	// channel command and are resolved to short-lived links at play time.
+	SourceTypeStalker SourceType = "stalker"
 )
 
 // SourceStatus represents the current status of a source.
@@ -43,10 +51,10 @@ type StreamSource struct {
 	// Must be unique across all stream sources.
 	Name string `gorm:"uniqueIndex;not null;size:255" json:"name"`
 
-	// Type indicates whether this is an M3U or Xtream source.
+	// Type indicates whether this is an M3U, Xtream, Stalker or Manual source.
 	Type SourceType `gorm:"not null;size:20" json:"type"`
 
-	// URL is the M3U playlist URL or Xtream server base URL.
+	// URL is the M3U playlist URL, Xtream server base URL or Stalker portal URL.
 	URL string `gorm:"not null;size:2048" json:"url"`
 
 	// Username for Xtream authentication (optional for M3U).
@@ -55,9 +63,28 @@ type StreamSource struct {
 	// Password for Xtream authentication (optional for M3U).
 	Password string `gorm:"size:255" json:"password,omitempty"`
 
+	// ExtraAccounts holds further Xtream accounts with the same provider as a
+	// JSON array of XtreamAccount. Channels are ingested once with the primary
+	// account above, and relay sessions connect with whichever account has a
+	// free connection (Xtream only).
+	ExtraAccounts string `gorm:"type:text" json:"extra_accounts,omitempty"`
+
+	// MacAddress is the device MAC address for Stalker authentication
+	// (e.g. "00:1A:79:12:34:56").
+	MacAddress string `gorm:"size:17" json:"mac_address,omitempty"`
+
 	// UserAgent to use when fetching the source (optional).
 	UserAgent string `gorm:"size:512" json:"user_agent,omitempty"`
 
+	// ProxyURL routes all upstream connections for this source through an
+	// HTTP(S) or SOCKS5 proxy, e.g. "socks5://vpn:1080" (optional).
+	ProxyURL string `gorm:"size:512" json:"proxy_url,omitempty"`
+
+	// CustomHeaders holds extra HTTP headers sent with every stream request of
+	// this source, as a JSON object of header names to values. They override
+	// headers captured from the playlist.
+	CustomHeaders string `gorm:"type:text" json:"custom_headers,omitempty"`
+
 	// Enabled indicates whether this source should be included in ingestion.
 	// Using pointer to distinguish between "not set" (nil->default true) and "explicitly false".
 	Enabled *bool `gorm:"default:true" json:"enabled"`
@@ -72,6 +99,11 @@ type StreamSource struct {
 	// that would breach the source's connection limit.
 	MaxConcurrentStreams int `gorm:"default:1" json:"max_concurrent_streams"`
 
+	// ConnectionLimitPolicy decides what happens when a relay session needs a
+	// connection while the source is at its limit. Empty leaves the limit to the
+	// provider; a proxy's policy overrides it.
+	ConnectionLimitPolicy ConnectionLimitPolicy `gorm:"size:30" json:"connection_limit_policy,omitempty"`
+
 	// Status indicates the current ingestion status.
 	Status SourceStatus `gorm:"not null;default:'pending';size:20" json:"status"`
 
@@ -81,9 +113,50 @@ type StreamSource struct {
 	// LastError contains the error message from the last failed ingestion.
 	LastError string `gorm:"size:4096" json:"last_error,omitempty"`
 
+	// ETag and LastModified are the validators of the content last ingested,
+	// sent with the next fetch so an unchanged source is not downloaded again.
+	ETag         string `gorm:"size:512" json:"-"`
+	LastModified string `gorm:"size:64" json:"-"`
+
+	// ContentHash is the SHA-256 digest of the content last ingested. Content
+	// with the same digest is not ingested again.
+	ContentHash string `gorm:"size:64" json:"-"`
+
 	// ChannelCount is the number of channels from the last ingestion.
 	ChannelCount int `gorm:"default:0" json:"channel_count"`
 
+	// IngestVod fetches the source's movie catalogue during ingestion (Xtream only).
+	IngestVod bool `gorm:"default:false" json:"ingest_vod"`
+
+	// IngestSeries fetches the source's series catalogue and episodes during
+	// ingestion (Xtream only).
+	IngestSeries bool `gorm:"default:false" json:"ingest_series"`
+
+	// VodCount is the number of movies from the last ingestion.
+	VodCount int `gorm:"default:0" json:"vod_count"`
+
+	// SeriesCount is the number of series from the last ingestion.
+	SeriesCount int `gorm:"default:0" json:"series_count"`
+
+	// AccountStatus is the account status last reported by an Xtream server,
+	// e.g. "Active", "Expired" or "Banned" (Xtream only).
+	AccountStatus string `gorm:"size:32" json:"account_status,omitempty"`
+
+	// AccountExpiresAt is when the Xtream account expires. Nil if the account
+	// does not expire or has not been checked.
+	AccountExpiresAt *Time `json:"account_expires_at,omitempty"`
+
+	// AccountMaxConnections is the connection limit reported by the Xtream
+	// server. When set it also becomes MaxConcurrentStreams.
+	AccountMaxConnections int `gorm:"default:0" json:"account_max_connections"`
+
+	// AccountActiveConnections is the number of connections the Xtream server
+	// reported in use, by tvarr and by any other client of the account.
+	AccountActiveConnections int `gorm:"default:0" json:"account_active_connections"`
+
+	// AccountCheckedAt is when the Xtream account was last checked.
+	AccountCheckedAt *Time `json:"account_checked_at,omitempty"`
+
 	// CronSchedule for automatic ingestion (optional).
 	// Uses standard cron format: "0 */6 * * *" for every 6 hours.
 	CronSchedule string `gorm:"size:100" json:"cron_schedule,omitempty"`
@@ -92,11 +165,100 @@ type StreamSource struct {
 	Channels []Channel `gorm:"foreignKey:SourceID;constraint:OnDelete:CASCADE" json:"channels,omitempty"`
 }
 
+// XtreamAccount is one set of credentials for an Xtream source.
+type XtreamAccount struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// MaxConcurrentStreams is the account's connection limit (0 = unlimited).
+	MaxConcurrentStreams int `json:"max_concurrent_streams"`
+}
+
 // TableName returns the table name for StreamSource.
 func (StreamSource) TableName() string {
 	return "stream_sources"
 }
 
+// Headers returns the source's custom request headers.
+func (s *StreamSource) Headers() map[string]string {
+	return DecodeHeaders(s.CustomHeaders)
+}
+
+// SetHeaders sets the source's custom request headers.
+func (s *StreamSource) SetHeaders(headers map[string]string) {
+	s.CustomHeaders = EncodeHeaders(headers)
+}
+
+// Accounts returns the source's Xtream accounts, the primary account first.
+// Returns nil for sources without extra accounts.
+func (s *StreamSource) Accounts() []XtreamAccount {
+	extra := decodeAccounts(s.ExtraAccounts)
+	if len(extra) == 0 {
+		return nil
+	}
+	primary := XtreamAccount{Username: s.Username, Password: s.Password, MaxConcurrentStreams: s.MaxConcurrentStreams}
+	return append([]XtreamAccount{primary}, extra...)
+}
+
+// SetExtraAccounts sets the source's extra Xtream accounts.
+func (s *StreamSource) SetExtraAccounts(accounts []XtreamAccount) {
+	s.ExtraAccounts = ""
+	if len(accounts) == 0 {
+		return
+	}
+	if data, err := json.Marshal(accounts); err == nil {
+		s.ExtraAccounts = string(data)
+	}
+}
+
+// ConnectionLimit returns the number of concurrent streams the source allows
+// across all of its accounts (0 = unlimited).
+func (s *StreamSource) ConnectionLimit() int {
+	accounts := s.Accounts()
+	if len(accounts) == 0 {
+		return s.MaxConcurrentStreams
+	}
+	total := 0
+	for _, account := range accounts {
+		if account.MaxConcurrentStreams == 0 {
+			return 0
+		}
+		total += account.MaxConcurrentStreams
+	}
+	return total
+}
+
+// decodeAccounts decodes an extra accounts column. Malformed values decode to nil.
+func decodeAccounts(raw string) []XtreamAccount {
+	if raw == "" {
+		return nil
+	}
+	var accounts []XtreamAccount
+	if err := json.Unmarshal([]byte(raw), &accounts); err != nil {
+		return nil
+	}
+	return accounts
+}
+
+// validateExtraAccounts checks that extra accounts belong to an Xtream source
+// and each has a password, a non-negative limit and a username of its own.
+func (s *StreamSource) validateExtraAccounts() error {
+	if s.ExtraAccounts == "" {
+		return nil
+	}
+	var accounts []XtreamAccount
+	if s.Type != SourceTypeXtream || json.Unmarshal([]byte(s.ExtraAccounts), &accounts) != nil {
+		return ErrInvalidExtraAccounts
+	}
+	seen := map[string]bool{s.Username: true}
+	for _, account := range accounts {
+		if account.Username == "" || account.Password == "" || account.MaxConcurrentStreams < 0 || seen[account.Username] {
+			return ErrInvalidExtraAccounts
+		}
+		seen[account.Username] = true
+	}
+	return nil
+}
+
 // IsM3U returns true if this is an M3U source.
 func (s *StreamSource) IsM3U() bool {
 	return s.Type == SourceTypeM3U
@@ -107,6 +269,11 @@ func (s *StreamSource) IsXtream() bool {
 	return s.Type == SourceTypeXtream
 }
 
+// IsStalker returns true if this is a Stalker portal source.
+func (s *StreamSource) IsStalker() bool {
+	return s.Type == SourceTypeStalker
+}
+
 // IsManual returns true if this is a Manual source.
 func (s *StreamSource) IsManual() bool {
 	return s.Type == SourceTypeManual
@@ -133,6 +300,24 @@ func (s *StreamSource) MarkFailed(err error) {
 	if err != nil {
 		s.LastError = err.Error()
 	}
+	s.ClearContentVersion()
+}
+
+// ClearContentVersion forgets the content last ingested, so the next ingestion
+// processes the source in full even if its content is unchanged.
+func (s *StreamSource) ClearContentVersion() {
+	s.ETag = ""
+	s.LastModified = ""
+	s.ContentHash = ""
+}
+
+// AccountExpiresWithin reports whether the source's account expires within d
+// from now, including accounts that have already expired.
+func (s *StreamSource) AccountExpiresWithin(d time.Duration) bool {
+	if s.AccountExpiresAt == nil {
+		return false
+	}
+	return time.Until(*s.AccountExpiresAt) <= d
 }
 
 // Sanitize trims whitespace from user-provided fields.
@@ -141,7 +326,9 @@ func (s *StreamSource) Sanitize() {
 	s.URL = strings.TrimSpace(s.URL)
 	s.Username = strings.TrimSpace(s.Username)
 	s.Password = strings.TrimSpace(s.Password)
+	s.MacAddress = strings.ToUpper(strings.TrimSpace(s.MacAddress))
 	s.UserAgent = strings.TrimSpace(s.UserAgent)
+	s.ProxyURL = strings.TrimSpace(s.ProxyURL)
 }
 
 // Validate performs basic validation on the source.
@@ -162,12 +349,43 @@ func (s *StreamSource) Validate() error {
 			return ErrInvalidURL
 		}
 	}
-	if s.Type != SourceTypeM3U && s.Type != SourceTypeXtream && s.Type != SourceTypeManual {
+	if s.Type != SourceTypeM3U && s.Type != SourceTypeXtream && s.Type != SourceTypeManual && s.Type != SourceTypeStalker {
 		return ErrInvalidSourceType
 	}
 	if s.Type == SourceTypeXtream && (s.Username == "" || s.Password == "") {
 		return ErrXtreamCredentialsRequired
 	}
+	if s.ProxyURL != "" {
+		if _, err := httpclient.ParseProxyURL(s.ProxyURL); err != nil {
+			return ErrInvalidProxyURL
+		}
+	}
+	if err := ValidateHeaders(DecodeHeaders(s.CustomHeaders)); err != nil {
+		return err
+	}
+	if err := s.validateExtraAccounts(); err != nil {
+		return err
+	}
+	if !IsValidConnectionLimitPolicy(s.ConnectionLimitPolicy) {
+		return ErrInvalidConnectionLimitPolicy
+	}
+	if s.Type == SourceTypeStalker {
+		return validateMacAddress(s.MacAddress)
+	}
+	return nil
+}
+
+// macAddressPattern matches a MAC address in colon-separated form.
+var macAddressPattern = regexp.MustCompile(`^[0-9A-F]{2}(:[0-9A-F]{2}){5}$`)
+
+// validateMacAddress checks a sanitized (upper-case) Stalker MAC address.
+func validateMacAddress(mac string) error {
+	if mac == "" {
+		return ErrMacAddressRequired
+	}
+	if !macAddressPattern.MatchString(mac) {
+		return ErrInvalidMacAddress
+	}
 	return nil
 }
 
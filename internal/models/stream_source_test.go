@@ -86,7 +86,9 @@ func TestStreamSource_MarkSuccess(t *testing.T) {
 
 func TestStreamSource_MarkFailed(t *testing.T) {
 	s := StreamSource{
-		Status: SourceStatusIngesting,
+		Status:      SourceStatusIngesting,
+		ETag:        `"v1"`,
+		ContentHash: "abc",
 	}
 
 	testErr := assert.AnError
@@ -94,6 +96,9 @@ func TestStreamSource_MarkFailed(t *testing.T) {
 
 	assert.Equal(t, SourceStatusFailed, s.Status)
 	assert.Equal(t, testErr.Error(), s.LastError)
+	// A failed ingestion must not be skipped as unchanged next time
+	assert.Empty(t, s.ETag)
+	assert.Empty(t, s.ContentHash)
 }
 
 func TestStreamSource_MarkFailed_NilError(t *testing.T) {
@@ -177,6 +182,141 @@ func TestStreamSource_Validate(t *testing.T) {
 			},
 			wantErr: ErrXtreamCredentialsRequired,
 		},
+		{
+			name: "valid Stalker source",
+			source: StreamSource{
+				Name:       "Test Stalker",
+				Type:       SourceTypeStalker,
+				URL:        "http://portal.example.com/c/",
+				MacAddress: " 00:1a:79:12:34:56 ",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Stalker missing MAC address",
+			source: StreamSource{
+				Name: "Test Stalker",
+				Type: SourceTypeStalker,
+				URL:  "http://portal.example.com/c/",
+			},
+			wantErr: ErrMacAddressRequired,
+		},
+		{
+			name: "Stalker invalid MAC address",
+			source: StreamSource{
+				Name:       "Test Stalker",
+				Type:       SourceTypeStalker,
+				URL:        "http://portal.example.com/c/",
+				MacAddress: "00-1A-79-12-34-56",
+			},
+			wantErr: ErrInvalidMacAddress,
+		},
+		{
+			name: "valid SOCKS5 proxy",
+			source: StreamSource{
+				Name:     "Test M3U",
+				Type:     SourceTypeM3U,
+				URL:      "http://example.com/playlist.m3u",
+				ProxyURL: "socks5://vpn:1080",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unsupported proxy scheme",
+			source: StreamSource{
+				Name:     "Test M3U",
+				Type:     SourceTypeM3U,
+				URL:      "http://example.com/playlist.m3u",
+				ProxyURL: "ftp://vpn:21",
+			},
+			wantErr: ErrInvalidProxyURL,
+		},
+		{
+			name: "valid custom headers",
+			source: StreamSource{
+				Name:          "Test M3U",
+				Type:          SourceTypeM3U,
+				URL:           "http://example.com/playlist.m3u",
+				CustomHeaders: `{"Referer":"https://example.com/","Origin":"https://example.com"}`,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "custom header with line break",
+			source: StreamSource{
+				Name:          "Test M3U",
+				Type:          SourceTypeM3U,
+				URL:           "http://example.com/playlist.m3u",
+				CustomHeaders: `{"Referer":"https://example.com/\r\nX-Injected: 1"}`,
+			},
+			wantErr: ErrInvalidCustomHeaders,
+		},
+		{
+			name: "invalid custom header name",
+			source: StreamSource{
+				Name:          "Test M3U",
+				Type:          SourceTypeM3U,
+				URL:           "http://example.com/playlist.m3u",
+				CustomHeaders: `{"Bad Header":"x"}`,
+			},
+			wantErr: ErrInvalidCustomHeaders,
+		},
+		{
+			name: "valid extra accounts",
+			source: StreamSource{
+				Name:          "Test Xtream",
+				Type:          SourceTypeXtream,
+				URL:           "http://example.com",
+				Username:      "user",
+				Password:      "pass",
+				ExtraAccounts: `[{"username":"second","password":"secret","max_concurrent_streams":2}]`,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "extra account reusing the primary username",
+			source: StreamSource{
+				Name:          "Test Xtream",
+				Type:          SourceTypeXtream,
+				URL:           "http://example.com",
+				Username:      "user",
+				Password:      "pass",
+				ExtraAccounts: `[{"username":"user","password":"other"}]`,
+			},
+			wantErr: ErrInvalidExtraAccounts,
+		},
+		{
+			name: "extra account without password",
+			source: StreamSource{
+				Name:          "Test Xtream",
+				Type:          SourceTypeXtream,
+				URL:           "http://example.com",
+				Username:      "user",
+				Password:      "pass",
+				ExtraAccounts: `[{"username":"second"}]`,
+			},
+			wantErr: ErrInvalidExtraAccounts,
+		},
+		{
+			name: "unknown connection limit policy",
+			source: StreamSource{
+				Name:                  "Test M3U",
+				Type:                  SourceTypeM3U,
+				URL:                   "http://example.com/playlist.m3u",
+				ConnectionLimitPolicy: "preempt_everyone",
+			},
+			wantErr: ErrInvalidConnectionLimitPolicy,
+		},
+		{
+			name: "extra accounts on an M3U source",
+			source: StreamSource{
+				Name:          "Test M3U",
+				Type:          SourceTypeM3U,
+				URL:           "http://example.com/playlist.m3u",
+				ExtraAccounts: `[{"username":"second","password":"secret"}]`,
+			},
+			wantErr: ErrInvalidExtraAccounts,
+		},
 	}
 
 	for _, tt := range tests {
@@ -192,9 +332,31 @@ func TestStreamSource_Validate(t *testing.T) {
 	}
 }
 
+func TestStreamSource_Accounts(t *testing.T) {
+	s := StreamSource{Type: SourceTypeXtream, Username: "user", Password: "pass", MaxConcurrentStreams: 1}
+	assert.Nil(t, s.Accounts())
+	assert.Equal(t, 1, s.ConnectionLimit())
+
+	s.SetExtraAccounts([]XtreamAccount{{Username: "second", Password: "secret", MaxConcurrentStreams: 2}})
+	accounts := s.Accounts()
+	require.Len(t, accounts, 2)
+	assert.Equal(t, XtreamAccount{Username: "user", Password: "pass", MaxConcurrentStreams: 1}, accounts[0])
+	assert.Equal(t, "second", accounts[1].Username)
+	assert.Equal(t, 3, s.ConnectionLimit())
+
+	// An unlimited account makes the source unlimited
+	s.SetExtraAccounts([]XtreamAccount{{Username: "second", Password: "secret"}})
+	assert.Equal(t, 0, s.ConnectionLimit())
+
+	s.SetExtraAccounts(nil)
+	assert.Empty(t, s.ExtraAccounts)
+	assert.Nil(t, s.Accounts())
+}
+
 func TestSourceType_Constants(t *testing.T) {
 	assert.Equal(t, SourceType("m3u"), SourceTypeM3U)
 	assert.Equal(t, SourceType("xtream"), SourceTypeXtream)
+	assert.Equal(t, SourceType("stalker"), SourceTypeStalker)
 }
 
 func TestSourceStatus_Constants(t *testing.T) {
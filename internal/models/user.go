@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// User is a local administrator account for the web UI and API.
+type User struct {
+	BaseModel
+
+	// Username is the unique login name.
+	Username string `gorm:"uniqueIndex;not null;size:100" json:"username"`
+
+	// PasswordHash is the encoded password hash (see auth.HashPassword).
+	PasswordHash string `gorm:"not null;size:255" json:"-"`
+
+	// IsActive indicates whether the user may log in.
+	// Using pointer to distinguish between "not set" (nil->default true) and "explicitly false".
+	IsActive *bool `gorm:"default:true" json:"is_active"`
+
+	// LastLoginAt is the time of the last successful login.
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// TableName returns the table name for User.
+func (User) TableName() string {
+	return "users"
+}
+
+// Validate checks if the user is valid.
+func (u *User) Validate() error {
+	if u.Username == "" {
+		return ValidationError{Field: "username", Message: "username is required"}
+	}
+	if u.PasswordHash == "" {
+		return ValidationError{Field: "password", Message: "password is required"}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Viewer is a playback account. Viewers are separate from admin users: they can
+// fetch playlists and play streams, but cannot access the admin API.
+type Viewer struct {
+	BaseModel
+
+	// Name is the unique viewer name. It identifies the viewer in the admin UI and
+	// analytics only: Xtream clients log in with the proxy name as username and
+	// the viewer's Token as password.
+	Name string `gorm:"uniqueIndex;not null;size:100" json:"name"`
+
+	// Description is optional free text, e.g. who the viewer is.
+	Description string `gorm:"size:500" json:"description,omitempty"`
+
+	// Token is the viewer's stream credential. It is embedded in every playlist and
+	// stream URL handed to the viewer, so it is stored in plain text to allow those
+	// URLs to be shown again; rotating it revokes all previously issued URLs.
+	Token string `gorm:"uniqueIndex;not null;size:64" json:"token"`
+
+	// IsActive indicates whether the viewer may play streams.
+	// Using pointer to distinguish between "not set" (nil->default true) and "explicitly false".
+	IsActive *bool `gorm:"default:true" json:"is_active"`
+
+	// ExpiresAt is when the viewer's access ends (nil = never).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// LastSeenAt is the time the viewer last fetched a playlist or stream.
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+// TableName returns the table name for Viewer.
+func (Viewer) TableName() string {
+	return "viewers"
+}
+
+// Validate checks if the viewer is valid.
+func (v *Viewer) Validate() error {
+	if v.Name == "" {
+		return ValidationError{Field: "name", Message: "name is required"}
+	}
+	if v.Token == "" {
+		return ValidationError{Field: "token", Message: "token is required"}
+	}
+	return nil
+}
+
+// CanPlay reports whether the viewer is active and not expired at the given time.
+func (v *Viewer) CanPlay(now time.Time) bool {
+	if !BoolVal(v.IsActive) {
+		return false
+	}
+	return v.ExpiresAt == nil || now.Before(*v.ExpiresAt)
+}
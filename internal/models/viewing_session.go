@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// ViewingSession records one client's viewing of a channel through a proxy,
+// from when it joined the relay session to when it left.
+type ViewingSession struct {
+	BaseModel
+
+	// ViewerID is the authenticated viewer, when stream authentication is enabled.
+	ViewerID *ULID `gorm:"type:varchar(26);index" json:"viewer_id,omitempty"`
+
+	// ViewerName is the name of the viewer at viewing time.
+	ViewerName string `gorm:"size:100" json:"viewer_name,omitempty"`
+
+	// ClientIP is the client's IP address.
+	ClientIP string `gorm:"size:64" json:"client_ip"`
+
+	// UserAgent is the client's User-Agent header.
+	UserAgent string `gorm:"size:512" json:"user_agent,omitempty"`
+
+	// ClientRule is the client detection rule that matched the client.
+	ClientRule string `gorm:"size:255" json:"client_rule,omitempty"`
+
+	// ChannelID is the channel viewed.
+	ChannelID ULID `gorm:"type:varchar(26);not null;index" json:"channel_id"`
+
+	// ChannelName is the name of the channel at viewing time.
+	ChannelName string `gorm:"size:512" json:"channel_name"`
+
+	// ProxyID is the proxy the channel was viewed through.
+	ProxyID ULID `gorm:"type:varchar(26);not null;index" json:"proxy_id"`
+
+	// ProxyName is the name of the proxy at viewing time.
+	ProxyName string `gorm:"size:255" json:"proxy_name"`
+
+	// SourceID is the stream source the relay was ingesting from when the client left.
+	SourceID ULID `gorm:"type:varchar(26);index" json:"source_id"`
+
+	// SourceName is the name of the stream source.
+	SourceName string `gorm:"size:255" json:"source_name"`
+
+	// RelaySessionID is the relay session the client joined.
+	RelaySessionID string `gorm:"size:26" json:"relay_session_id"`
+
+	// DeliveryRoute is how the stream was delivered: passthrough, repackage or transcode.
+	DeliveryRoute string `gorm:"size:20;index" json:"delivery_route"`
+
+	// OutputFormat is the format sent to the client (hls-ts, hls-fmp4, dash, mpegts).
+	OutputFormat string `gorm:"size:20" json:"output_format"`
+
+	// CodecVariant is the codecs sent to the client, e.g. "h264/aac".
+	CodecVariant string `gorm:"size:50" json:"codec_variant,omitempty"`
+
+	// EncodingProfileName is the encoding profile used, when transcoding.
+	EncodingProfileName string `gorm:"size:255" json:"encoding_profile_name,omitempty"`
+
+	// StartedAt is when the client joined the relay session.
+	StartedAt time.Time `gorm:"not null;index" json:"started_at"`
+
+	// EndedAt is when the client left the relay session.
+	EndedAt time.Time `gorm:"not null;index" json:"ended_at"`
+
+	// DurationMs is the viewing duration in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+
+	// BytesSent is the bytes sent to the client.
+	BytesSent int64 `json:"bytes_sent"`
+}
+
+// TableName returns the table name for ViewingSession.
+func (ViewingSession) TableName() string {
+	return "viewing_sessions"
+}
+
+// ChannelViewingStats summarizes the viewings of a channel.
+type ChannelViewingStats struct {
+	ChannelID     ULID   `json:"channel_id"`
+	ChannelName   string `json:"channel_name"`
+	Viewings      int64  `json:"viewings"`
+	UniqueClients int64  `json:"unique_clients"`
+	DurationMs    int64  `json:"duration_ms"`
+	BytesSent     int64  `json:"bytes_sent"`
+}
+
+// ProfileTranscodeStats summarizes transcoded viewings of an encoding profile.
+type ProfileTranscodeStats struct {
+	EncodingProfileName string `json:"encoding_profile_name"`
+	Viewings            int64  `json:"viewings"`
+	DurationMs          int64  `json:"duration_ms"`
+}
+
+// SourceConcurrencyStats is the most viewing sessions open at once on a stream source.
+type SourceConcurrencyStats struct {
+	SourceID   ULID      `json:"source_id"`
+	SourceName string    `json:"source_name"`
+	Peak       int       `json:"peak"`
+	PeakAt     time.Time `json:"peak_at"`
+}
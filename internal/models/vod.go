@@ -0,0 +1,158 @@
+package models
+
+// VodType distinguishes the kinds of video on demand content.
+type VodType string
+
+const (
+	// VodTypeMovie is a movie.
+	VodTypeMovie VodType = "movie"
+	// VodTypeSeries is a TV series made of episodes.
+	VodTypeSeries VodType = "series"
+)
+
+// VodItem is a movie from a stream source's video on demand catalogue.
+type VodItem struct {
+	BaseModel
+
+	// SourceID is the stream source the movie was ingested from.
+	SourceID ULID `gorm:"type:varchar(26);not null;index;uniqueIndex:idx_vod_source_ext_id,priority:1" json:"source_id"`
+
+	// ExtID is the provider's stream ID, unique per source.
+	ExtID string `gorm:"size:255;uniqueIndex:idx_vod_source_ext_id,priority:2" json:"ext_id"`
+
+	// Name is the title of the movie.
+	Name string `gorm:"not null;size:512" json:"name"`
+
+	// GroupTitle is the provider category of the movie.
+	GroupTitle string `gorm:"size:255;index" json:"group_title,omitempty"`
+
+	// Logo is the URL of the movie poster.
+	Logo string `gorm:"size:2048" json:"logo,omitempty"`
+
+	// StreamURL is the upstream URL of the movie.
+	StreamURL string `gorm:"not null;size:4096" json:"stream_url"`
+
+	// ContainerExtension is the file extension of the stream (e.g. mp4, mkv).
+	ContainerExtension string `gorm:"size:20" json:"container_extension,omitempty"`
+
+	// Rating is the provider rating, usually out of 10 (0 = unrated).
+	Rating float64 `gorm:"default:0" json:"rating,omitempty"`
+
+	// Year is the release year if known (0 = unknown).
+	Year int `gorm:"default:0" json:"year,omitempty"`
+
+	// IsAdult indicates whether this is adult content.
+	IsAdult bool `gorm:"default:false" json:"is_adult"`
+}
+
+// TableName returns the table name for VodItem.
+func (VodItem) TableName() string {
+	return "vod_items"
+}
+
+// Validate performs basic validation on the movie.
+func (v *VodItem) Validate() error {
+	if v.SourceID.IsZero() {
+		return ErrSourceIDRequired
+	}
+	if v.Name == "" {
+		return ErrNameRequired
+	}
+	if v.StreamURL == "" {
+		return ErrStreamURLRequired
+	}
+	return nil
+}
+
+// Series is a TV series from a stream source's video on demand catalogue.
+type Series struct {
+	BaseModel
+
+	// SourceID is the stream source the series was ingested from.
+	SourceID ULID `gorm:"type:varchar(26);not null;index;uniqueIndex:idx_series_source_ext_id,priority:1" json:"source_id"`
+
+	// ExtID is the provider's series ID, unique per source.
+	ExtID string `gorm:"size:255;uniqueIndex:idx_series_source_ext_id,priority:2" json:"ext_id"`
+
+	// Name is the title of the series.
+	Name string `gorm:"not null;size:512" json:"name"`
+
+	// GroupTitle is the provider category of the series.
+	GroupTitle string `gorm:"size:255;index" json:"group_title,omitempty"`
+
+	// Cover is the URL of the series cover art.
+	Cover string `gorm:"size:2048" json:"cover,omitempty"`
+
+	// Genre is the provider's genre list, as published (e.g. "Crime, Drama").
+	Genre string `gorm:"size:255" json:"genre,omitempty"`
+
+	// Rating is the provider rating, usually out of 10 (0 = unrated).
+	Rating float64 `gorm:"default:0" json:"rating,omitempty"`
+
+	// Year is the first release year if known (0 = unknown).
+	Year int `gorm:"default:0" json:"year,omitempty"`
+
+	// LastModified is the provider's modification timestamp (Unix seconds). Episodes
+	// are only fetched again when it changes.
+	LastModified int64 `gorm:"default:0" json:"last_modified,omitempty"`
+
+	// Episodes are the episodes of the series. They are only populated when loaded
+	// explicitly or when fetched during ingestion.
+	Episodes []*SeriesEpisode `gorm:"-" json:"episodes,omitempty"`
+}
+
+// TableName returns the table name for Series.
+func (Series) TableName() string {
+	return "series"
+}
+
+// Validate performs basic validation on the series.
+func (s *Series) Validate() error {
+	if s.SourceID.IsZero() {
+		return ErrSourceIDRequired
+	}
+	if s.Name == "" {
+		return ErrNameRequired
+	}
+	if s.ExtID == "" {
+		return ValidationError{Field: "ext_id", Message: "ext_id is required"}
+	}
+	return nil
+}
+
+// SeriesEpisode is a single episode of a series.
+type SeriesEpisode struct {
+	BaseModel
+
+	// SeriesID is the series the episode belongs to.
+	SeriesID ULID `gorm:"type:varchar(26);not null;index" json:"series_id"`
+
+	// SourceID is the stream source the episode was ingested from.
+	SourceID ULID `gorm:"type:varchar(26);not null;index" json:"source_id"`
+
+	// ExtID is the provider's episode ID.
+	ExtID string `gorm:"size:255" json:"ext_id"`
+
+	// Season is the season number (0 = specials or unknown).
+	Season int `gorm:"default:0" json:"season"`
+
+	// EpisodeNum is the episode number within the season.
+	EpisodeNum int `gorm:"default:0" json:"episode_num"`
+
+	// Title is the title of the episode.
+	Title string `gorm:"size:512" json:"title"`
+
+	// StreamURL is the upstream URL of the episode.
+	StreamURL string `gorm:"not null;size:4096" json:"stream_url"`
+
+	// ContainerExtension is the file extension of the stream (e.g. mp4, mkv).
+	ContainerExtension string `gorm:"size:20" json:"container_extension,omitempty"`
+
+	// Image is the URL of the episode still, if the provider has one.
+	Image string `gorm:"size:2048" json:"image,omitempty"`
+}
+
+// TableName returns the table name for SeriesEpisode.
+func (SeriesEpisode) TableName() string {
+	return "series_episodes"
+}
@@ -0,0 +1,85 @@
+// Package notify formats operational events and delivers them to webhook,
+// push notification and email targets.
+package notify
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// Severity indicates how urgent an event is.
+type Severity string
+
+const (
+	// SeverityInfo is for events that need no action, such as recoveries.
+	SeverityInfo Severity = "info"
+	// SeverityWarning is for degraded operation that may recover by itself.
+	SeverityWarning Severity = "warning"
+	// SeverityError is for failures that need attention.
+	SeverityError Severity = "error"
+)
+
+// Event is an operational event delivered to notification targets.
+// Webhook targets receive it as JSON.
+type Event struct {
+	// ID uniquely identifies the event. Retries of a delivery carry the same ID.
+	ID string `json:"id"`
+	// Type is the event type targets subscribe to.
+	Type models.NotificationEventType `json:"type"`
+	// Severity indicates how urgent the event is.
+	Severity Severity `json:"severity"`
+	// Title is a one-line summary.
+	Title string `json:"title"`
+	// Message describes what happened.
+	Message string `json:"message"`
+	// Subject names what the event is about, such as a source or daemon.
+	// Repeats of an event with the same type and subject are suppressed for a cooldown.
+	Subject string `json:"subject,omitempty"`
+	// Data holds event-specific details.
+	Data map[string]any `json:"data,omitempty"`
+	// Timestamp is when the event occurred.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewEvent creates an event with a new ID, timestamped now.
+func NewEvent(eventType models.NotificationEventType, severity Severity, subject, title, message string) Event {
+	return Event{
+		ID:        models.NewULID().String(),
+		Type:      eventType,
+		Severity:  severity,
+		Title:     title,
+		Message:   message,
+		Subject:   subject,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// WithData returns the event with a detail added.
+func (e Event) WithData(key string, value any) Event {
+	data := make(map[string]any, len(e.Data)+1)
+	maps.Copy(data, e.Data)
+	data[key] = value
+	e.Data = data
+	return e
+}
+
+// Text returns the message followed by the event's details, one per line.
+func (e Event) Text() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "Event: %s\n", e.Type)
+	if e.Subject != "" {
+		fmt.Fprintf(&b, "Subject: %s\n", e.Subject)
+	}
+	for _, key := range slices.Sorted(maps.Keys(e.Data)) {
+		fmt.Fprintf(&b, "%s: %v\n", key, e.Data[key])
+	}
+	fmt.Fprintf(&b, "Time: %s\n", e.Timestamp.Format(time.RFC3339))
+	return b.String()
+}
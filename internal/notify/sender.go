@@ -0,0 +1,299 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/urlutil"
+	"github.com/jmylchreest/tvarr/internal/version"
+)
+
+// Webhook request headers.
+const (
+	// HeaderEvent carries the event type.
+	HeaderEvent = "X-Tvarr-Event"
+	// HeaderDelivery carries the event ID, which is the same for retries.
+	HeaderDelivery = "X-Tvarr-Delivery"
+	// HeaderTimestamp carries the Unix time the request was signed at.
+	HeaderTimestamp = "X-Tvarr-Timestamp"
+	// HeaderSignature carries the request signature, "sha256=<hex HMAC>".
+	HeaderSignature = "X-Tvarr-Signature"
+)
+
+// SMTP TLS modes.
+const (
+	// SMTPTLSStartTLS upgrades the connection with STARTTLS, failing if the server does not offer it.
+	SMTPTLSStartTLS = "starttls"
+	// SMTPTLSImplicit connects over TLS, usually on port 465.
+	SMTPTLSImplicit = "tls"
+	// SMTPTLSNone sends mail without TLS.
+	SMTPTLSNone = "none"
+)
+
+// sendTimeout bounds a single delivery attempt.
+const sendTimeout = 15 * time.Second
+
+// SMTPConfig holds the mail server used by email targets.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      string
+}
+
+// StatusError is returned when an HTTP target responds with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Retryable reports whether the request may succeed if repeated. Other client
+// errors, such as a wrong URL or token, fail the same way every time.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests
+}
+
+// Sender delivers events to notification targets.
+type Sender struct {
+	client *http.Client
+	smtp   SMTPConfig
+}
+
+// NewSender creates a sender that emails through the given SMTP server.
+func NewSender(smtpConfig SMTPConfig) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: sendTimeout},
+		smtp:   smtpConfig,
+	}
+}
+
+// Send makes one attempt to deliver an event to a target. It returns the HTTP
+// status of the response, or 0 when there was none.
+func (s *Sender) Send(ctx context.Context, target *models.NotificationTarget, event Event) (int, error) {
+	switch target.Type {
+	case models.NotificationTargetWebhook:
+		return s.sendWebhook(ctx, target, event)
+	case models.NotificationTargetNtfy:
+		return s.sendNtfy(ctx, target, event)
+	case models.NotificationTargetGotify:
+		return s.sendGotify(ctx, target, event)
+	case models.NotificationTargetEmail:
+		return 0, s.sendEmail(ctx, target, event)
+	default:
+		return 0, fmt.Errorf("unsupported notification target type %q", target.Type)
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret.
+// Receivers recompute it to check a webhook came from tvarr, and reject old
+// timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendWebhook posts the event as JSON, signed when the target has a secret.
+func (s *Sender) sendWebhook(ctx context.Context, target *models.NotificationTarget, event Event) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("encoding event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(event.Type))
+	req.Header.Set(HeaderDelivery, event.ID)
+	if target.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, "sha256="+Sign(target.Secret, timestamp, body))
+	}
+	return s.do(req)
+}
+
+// ntfyPriorities maps severities to ntfy priorities.
+var ntfyPriorities = map[Severity]string{
+	SeverityInfo:    "low",
+	SeverityWarning: "default",
+	SeverityError:   "high",
+}
+
+// ntfyTags maps severities to ntfy tags, which ntfy shows as emoji.
+var ntfyTags = map[Severity]string{
+	SeverityInfo:    "white_check_mark",
+	SeverityWarning: "warning",
+	SeverityError:   "rotating_light",
+}
+
+// sendNtfy publishes the event message to an ntfy topic URL.
+func (s *Sender) sendNtfy(ctx context.Context, target *models.NotificationTarget, event Event) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, strings.NewReader(event.Message))
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+	// Headers are ASCII; ntfy decodes RFC 2047 encoded titles
+	req.Header.Set("Title", mime.QEncoding.Encode("utf-8", event.Title))
+	req.Header.Set("Priority", ntfyPriorities[event.Severity])
+	req.Header.Set("Tags", ntfyTags[event.Severity])
+	if target.Secret != "" {
+		req.Header.Set("Authorization", "Bearer "+target.Secret)
+	}
+	return s.do(req)
+}
+
+// gotifyPriorities maps severities to Gotify priorities, where 8 and above
+// alert on Android.
+var gotifyPriorities = map[Severity]int{
+	SeverityInfo:    2,
+	SeverityWarning: 5,
+	SeverityError:   8,
+}
+
+// sendGotify creates a message through the Gotify server's message API.
+func (s *Sender) sendGotify(ctx context.Context, target *models.NotificationTarget, event Event) (int, error) {
+	body, err := json.Marshal(map[string]any{
+		"title":    event.Title,
+		"message":  event.Message,
+		"priority": gotifyPriorities[event.Severity],
+	})
+	if err != nil {
+		return 0, fmt.Errorf("encoding message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlutil.JoinPath(target.URL, "/message"), bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", target.Secret)
+	return s.do(req)
+}
+
+// do sends an HTTP request, treating any non-2xx status as a StatusError.
+func (s *Sender) do(req *http.Request) (int, error) {
+	req.Header.Set("User-Agent", version.UserAgent())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &StatusError{StatusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+// sendEmail mails the event to the target's recipients.
+func (s *Sender) sendEmail(ctx context.Context, target *models.NotificationTarget, event Event) error {
+	if s.smtp.Host == "" {
+		return errors.New("no SMTP server configured (notifications.smtp.host)")
+	}
+	recipients := target.Recipients()
+	msg := buildEmail(s.smtp.From, recipients, event)
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.smtp.Host, strconv.Itoa(s.smtp.Port))
+	tlsConfig := &tls.Config{ServerName: s.smtp.Host, MinVersion: tls.VersionTLS12}
+	var conn net.Conn
+	var err error
+	if s.smtp.TLS == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.smtp.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("starting SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if s.smtp.TLS == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starting TLS: %w", err)
+		}
+	}
+	if s.smtp.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)); err != nil {
+			return fmt.Errorf("authenticating: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.smtp.From); err != nil {
+		return fmt.Errorf("setting sender: %w", err)
+	}
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("adding recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("starting message: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("sending message: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildEmail formats an event as a plain text email.
+func buildEmail(from string, to []string, event Event) []byte {
+	var b strings.Builder
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", from)
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", "[tvarr] "+event.Title))
+	header("Date", event.Timestamp.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(event.Text(), "\n", "\r\n"))
+	return []byte(b.String())
+}
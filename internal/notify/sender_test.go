@@ -0,0 +1,178 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent() Event {
+	return NewEvent(models.NotificationEventIngestionFailed, SeverityError, "provider",
+		"Ingestion failed: provider", "connection refused").
+		WithData("source_type", "stream")
+}
+
+func TestSender_Webhook(t *testing.T) {
+	event := testEvent()
+	var gotBody []byte
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	target := &models.NotificationTarget{Type: models.NotificationTargetWebhook, URL: server.URL, Secret: "s3cret"}
+	status, err := NewSender(SMTPConfig{}).Send(context.Background(), target, event)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	assert.Equal(t, "application/json", gotHeader.Get("Content-Type"))
+	assert.Equal(t, string(models.NotificationEventIngestionFailed), gotHeader.Get(HeaderEvent))
+	assert.Equal(t, event.ID, gotHeader.Get(HeaderDelivery))
+	timestamp := gotHeader.Get(HeaderTimestamp)
+	require.NotEmpty(t, timestamp)
+	assert.Equal(t, "sha256="+Sign("s3cret", timestamp, gotBody), gotHeader.Get(HeaderSignature))
+
+	var decoded Event
+	require.NoError(t, json.Unmarshal(gotBody, &decoded))
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, "provider", decoded.Subject)
+	assert.Equal(t, "stream", decoded.Data["source_type"])
+}
+
+func TestSender_WebhookUnsigned(t *testing.T) {
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+	}))
+	defer server.Close()
+
+	target := &models.NotificationTarget{Type: models.NotificationTargetWebhook, URL: server.URL}
+	_, err := NewSender(SMTPConfig{}).Send(context.Background(), target, testEvent())
+	require.NoError(t, err)
+	assert.Empty(t, gotHeader.Get(HeaderSignature))
+	assert.Empty(t, gotHeader.Get(HeaderTimestamp))
+}
+
+func TestSender_Ntfy(t *testing.T) {
+	var gotBody string
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+	}))
+	defer server.Close()
+
+	target := &models.NotificationTarget{Type: models.NotificationTargetNtfy, URL: server.URL + "/tvarr", Secret: "tk_token"}
+	_, err := NewSender(SMTPConfig{}).Send(context.Background(), target, testEvent())
+	require.NoError(t, err)
+
+	assert.Equal(t, "connection refused", gotBody)
+	assert.Equal(t, "Ingestion failed: provider", decodeHeader(t, gotHeader.Get("Title")))
+	assert.Equal(t, "high", gotHeader.Get("Priority"))
+	assert.Equal(t, "rotating_light", gotHeader.Get("Tags"))
+	assert.Equal(t, "Bearer tk_token", gotHeader.Get("Authorization"))
+}
+
+func TestSender_Gotify(t *testing.T) {
+	var gotPath, gotKey string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("X-Gotify-Key")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+	}))
+	defer server.Close()
+
+	target := &models.NotificationTarget{Type: models.NotificationTargetGotify, URL: server.URL + "/gotify/", Secret: "app-token"}
+	_, err := NewSender(SMTPConfig{}).Send(context.Background(), target, testEvent())
+	require.NoError(t, err)
+
+	assert.Equal(t, "/gotify/message", gotPath)
+	assert.Equal(t, "app-token", gotKey)
+	assert.Equal(t, "Ingestion failed: provider", gotBody["title"])
+	assert.Equal(t, "connection refused", gotBody["message"])
+	assert.InDelta(t, 8, gotBody["priority"], 0)
+}
+
+func TestSender_StatusError(t *testing.T) {
+	tests := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusRequestTimeout, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			target := &models.NotificationTarget{Type: models.NotificationTargetWebhook, URL: server.URL}
+			status, err := NewSender(SMTPConfig{}).Send(context.Background(), target, testEvent())
+			assert.Equal(t, tt.status, status)
+
+			var statusErr *StatusError
+			require.ErrorAs(t, err, &statusErr)
+			assert.Equal(t, tt.retryable, statusErr.Retryable())
+		})
+	}
+}
+
+func TestSender_EmailWithoutSMTP(t *testing.T) {
+	target := &models.NotificationTarget{Type: models.NotificationTargetEmail, EmailTo: "ops@example.com"}
+	_, err := NewSender(SMTPConfig{}).Send(context.Background(), target, testEvent())
+	assert.ErrorContains(t, err, "notifications.smtp.host")
+}
+
+func TestBuildEmail(t *testing.T) {
+	event := testEvent()
+	msg := string(buildEmail("tvarr@example.com", []string{"a@example.com", "b@example.com"}, event))
+
+	headers, body, ok := strings.Cut(msg, "\r\n\r\n")
+	require.True(t, ok)
+	assert.Contains(t, headers, "From: tvarr@example.com\r\n")
+	assert.Contains(t, headers, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=utf-8\r\n")
+
+	subject := ""
+	for line := range strings.SplitSeq(headers, "\r\n") {
+		if v, ok := strings.CutPrefix(line, "Subject: "); ok {
+			subject = decodeHeader(t, v)
+		}
+	}
+	assert.Equal(t, "[tvarr] Ingestion failed: provider", subject)
+
+	assert.True(t, strings.HasPrefix(body, "connection refused\r\n\r\n"))
+	assert.Contains(t, body, "Event: ingestion.failed\r\n")
+	assert.Contains(t, body, "Subject: provider\r\n")
+	assert.Contains(t, body, "source_type: stream\r\n")
+	assert.NotContains(t, strings.ReplaceAll(body, "\r\n", ""), "\n")
+}
+
+func decodeHeader(t *testing.T, value string) string {
+	t.Helper()
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	require.NoError(t, err)
+	return decoded
+}
@@ -62,6 +62,23 @@ func sensitiveFieldRedactor() func(groups []string, a slog.Attr) slog.Attr {
 	)
 }
 
+// attrRedactor redacts sensitive fields for RedactAttr.
+var attrRedactor = sensitiveFieldRedactor()
+
+// RedactAttr applies the logger's sensitive field and URL parameter redaction
+// to an attribute, for handlers that capture records before the logger
+// formats them.
+func RedactAttr(a slog.Attr) slog.Attr {
+	a = attrRedactor(nil, a)
+	if a.Value.Kind() == slog.KindString {
+		str := a.Value.String()
+		if redacted := redactURLParams(str); redacted != str {
+			a = slog.String(a.Key, redacted)
+		}
+	}
+	return a
+}
+
 // redactURLParams redacts sensitive query parameters from URL strings.
 // This handles cases where passwords appear in URL query strings like:
 // http://example.com/api?username=foo&password=secret123
@@ -606,3 +606,14 @@ func TestURLParameterRedaction_PreservesNonSensitiveURL(t *testing.T) {
 	// No redaction should occur
 	assert.NotContains(t, output, "[REDACTED]")
 }
+
+func TestRedactAttr(t *testing.T) {
+	password := RedactAttr(slog.String("password", "secret123"))
+	assert.NotContains(t, password.Value.String(), "secret123")
+
+	url := RedactAttr(slog.String("url", "http://example.com/live?username=admin&password=secret123"))
+	assert.Equal(t, "http://example.com/live?username=admin&password=[REDACTED]", url.Value.String())
+
+	plain := RedactAttr(slog.String("channel", "News"))
+	assert.Equal(t, "News", plain.Value.String())
+}
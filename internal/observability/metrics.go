@@ -0,0 +1,184 @@
+package observability
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// DefaultDurationBuckets are histogram buckets in seconds for operations that
+// take from milliseconds to tens of minutes, such as jobs and pipeline stages.
+var DefaultDurationBuckets = []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800}
+
+// Metrics is the registry exposed at /metrics.
+var Metrics = NewMetricsRegistry()
+
+// Metrics recorded as events happen. Metrics sampled at scrape time are
+// registered by the components that own the state they report.
+var (
+	// JobDuration records how long scheduled jobs ran, by job type and outcome.
+	JobDuration = Metrics.NewHistogramVec("tvarr_job_duration_seconds",
+		"Duration of scheduled jobs by type and outcome.",
+		DefaultDurationBuckets, "type", "status")
+
+	// PipelineStageDuration records how long proxy generation pipeline stages ran.
+	PipelineStageDuration = Metrics.NewHistogramVec("tvarr_pipeline_stage_duration_seconds",
+		"Duration of proxy generation pipeline stages by stage and outcome.",
+		DefaultDurationBuckets, "stage", "status")
+
+	// IngestedRows counts the channels and programs stored by source ingestion.
+	IngestedRows = Metrics.NewCounterVec("tvarr_ingested_rows_total",
+		"Rows stored by source ingestion by kind (channels, programs) and source type.",
+		"kind", "source_type")
+)
+
+// MetricsRegistry holds metrics in a Prometheus registry. Label names are fixed
+// when a metric is created; callers must only use label values drawn from
+// bounded sets, such as types, states and formats.
+type MetricsRegistry struct {
+	registry *prometheus.Registry
+}
+
+// NewMetricsRegistry creates an empty metrics registry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{registry: prometheus.NewRegistry()}
+}
+
+// Write writes all metrics, ordered by name, in the Prometheus text format.
+func (r *MetricsRegistry) Write(w io.Writer) error {
+	families, err := r.registry.Gather()
+	if err != nil {
+		return err
+	}
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(w, family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns an HTTP handler that serves the registry's metrics in the
+// format the scraper negotiates, including OpenMetrics.
+func (r *MetricsRegistry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// CounterVec is a counter with fixed label names.
+type CounterVec struct {
+	vec *prometheus.CounterVec
+}
+
+// NewCounterVec creates and registers a counter, panicking if its name is
+// already registered.
+func (r *MetricsRegistry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	r.registry.MustRegister(vec)
+	return &CounterVec{vec: vec}
+}
+
+// Add adds v, which must not be negative, to the series of the label values.
+// It panics if the number of label values does not match the label names.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	counter := c.vec.WithLabelValues(labelValues...)
+	if v > 0 {
+		counter.Add(v)
+	}
+}
+
+// Inc adds one to the series of the label values.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// HistogramVec is a histogram with fixed label names.
+type HistogramVec struct {
+	vec *prometheus.HistogramVec
+}
+
+// NewHistogramVec creates and registers a histogram with the given upper bucket
+// bounds, which must be sorted in increasing order.
+func (r *MetricsRegistry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	r.registry.MustRegister(vec)
+	return &HistogramVec{vec: vec}
+}
+
+// Observe records v in the series of the label values.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(v)
+}
+
+// EmitFunc reports the value of one series of a metric collected at scrape time.
+type EmitFunc func(value float64, labelValues ...string)
+
+// CollectFunc reports the current series of a metric collected at scrape time.
+type CollectFunc func(emit EmitFunc)
+
+// funcCollector collects a gauge or counter whose series are reported by a
+// CollectFunc on each scrape.
+type funcCollector struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	labels    int
+	collect   CollectFunc
+}
+
+// NewGaugeFunc registers a gauge whose series are reported by collect on each scrape.
+func (r *MetricsRegistry) NewGaugeFunc(name, help string, labels []string, collect CollectFunc) {
+	r.registerFunc(name, help, prometheus.GaugeValue, labels, collect)
+}
+
+// NewCounterFunc registers a counter whose series are reported by collect on
+// each scrape. The reported values must never decrease.
+func (r *MetricsRegistry) NewCounterFunc(name, help string, labels []string, collect CollectFunc) {
+	r.registerFunc(name, help, prometheus.CounterValue, labels, collect)
+}
+
+func (r *MetricsRegistry) registerFunc(name, help string, valueType prometheus.ValueType, labels []string, collect CollectFunc) {
+	r.registry.MustRegister(&funcCollector{
+		desc:      prometheus.NewDesc(name, help, labels, nil),
+		valueType: valueType,
+		labels:    len(labels),
+		collect:   collect,
+	})
+}
+
+// Describe implements prometheus.Collector.
+func (f *funcCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- f.desc
+}
+
+// Collect implements prometheus.Collector.
+func (f *funcCollector) Collect(ch chan<- prometheus.Metric) {
+	type collected struct {
+		values []string
+		value  float64
+	}
+	series := make(map[string]*collected)
+	f.collect(func(value float64, labelValues ...string) {
+		if len(labelValues) != f.labels {
+			ch <- prometheus.NewInvalidMetric(f.desc,
+				fmt.Errorf("expected %d label values, got %d", f.labels, len(labelValues)))
+			return
+		}
+		key := strings.Join(labelValues, "\xff")
+		// Series reported more than once are summed
+		if s, ok := series[key]; ok {
+			s.value += value
+			return
+		}
+		series[key] = &collected{values: slices.Clone(labelValues), value: value}
+	})
+
+	// The registry orders the series when it gathers them
+	for _, s := range series {
+		ch <- prometheus.MustNewConstMetric(f.desc, f.valueType, s.value, s.values...)
+	}
+}
@@ -0,0 +1,106 @@
+package observability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsRegistry_Counter(t *testing.T) {
+	registry := NewMetricsRegistry()
+	rows := registry.NewCounterVec("test_rows_total", "Rows stored.", "kind")
+	rows.Add(10, "programs")
+	rows.Add(3, "channels")
+	rows.Inc("channels")
+	rows.Add(-5, "channels") // Counters never decrease
+
+	var buf strings.Builder
+	require.NoError(t, registry.Write(&buf))
+	assert.Equal(t, `# HELP test_rows_total Rows stored.
+# TYPE test_rows_total counter
+test_rows_total{kind="channels"} 4
+test_rows_total{kind="programs"} 10
+`, buf.String())
+}
+
+func TestMetricsRegistry_Histogram(t *testing.T) {
+	registry := NewMetricsRegistry()
+	durations := registry.NewHistogramVec("test_duration_seconds", "Durations.", []float64{1, 5}, "status")
+	durations.Observe(0.5, "completed")
+	durations.Observe(1, "completed")
+	durations.Observe(7, "completed")
+
+	var buf strings.Builder
+	require.NoError(t, registry.Write(&buf))
+	assert.Equal(t, `# HELP test_duration_seconds Durations.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{status="completed",le="1"} 2
+test_duration_seconds_bucket{status="completed",le="5"} 2
+test_duration_seconds_bucket{status="completed",le="+Inf"} 3
+test_duration_seconds_sum{status="completed"} 8.5
+test_duration_seconds_count{status="completed"} 3
+`, buf.String())
+}
+
+func TestMetricsRegistry_GaugeFunc(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.NewGaugeFunc("test_sessions", "Sessions.", nil, func(emit EmitFunc) {
+		emit(2)
+	})
+	registry.NewGaugeFunc("test_clients", "Clients\nby format.", []string{"format"}, func(emit EmitFunc) {
+		emit(1, "mpegts")
+		emit(2, "hls")
+		emit(3, "hls") // Summed with the previous series
+		emit(1, `odd"value`)
+	})
+
+	var buf strings.Builder
+	require.NoError(t, registry.Write(&buf))
+	assert.Equal(t, `# HELP test_clients Clients\nby format.
+# TYPE test_clients gauge
+test_clients{format="hls"} 5
+test_clients{format="mpegts"} 1
+test_clients{format="odd\"value"} 1
+# HELP test_sessions Sessions.
+# TYPE test_sessions gauge
+test_sessions 2
+`, buf.String())
+}
+
+func TestMetricsRegistry_Misuse(t *testing.T) {
+	registry := NewMetricsRegistry()
+	counter := registry.NewCounterVec("test_total", "Test.", "kind")
+
+	assert.Panics(t, func() { registry.NewCounterVec("test_total", "Duplicate.") })
+	assert.Panics(t, func() { counter.Inc() })
+	assert.Panics(t, func() { counter.Inc("a", "b") })
+}
+
+func TestMetricsRegistry_Handler(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.NewCounterVec("test_total", "Test.").Inc()
+
+	t.Run("text format", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+		assert.Contains(t, rec.Body.String(), "test_total 1\n")
+	})
+
+	t.Run("OpenMetrics", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+		rec := httptest.NewRecorder()
+		registry.Handler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "application/openmetrics-text"))
+		assert.Equal(t, "# HELP test Test.\n# TYPE test counter\ntest_total 1.0\n# EOF\n", rec.Body.String())
+	})
+}
@@ -0,0 +1,129 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmylchreest/tvarr/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/stats"
+)
+
+// instrumentationName is the name of the tracer used for tvarr's own spans.
+const instrumentationName = "github.com/jmylchreest/tvarr"
+
+// InitTracing installs the global tracer provider and W3C trace context
+// propagator. When tracing is enabled, spans are batched and exported to the
+// OTLP gRPC endpoint in cfg. The returned function flushes pending spans and
+// shuts the exporter down.
+func InitTracing(ctx context.Context, cfg config.TracingConfig, serviceName, serviceVersion string) (func(context.Context) error, error) {
+	// Propagate trace context even when not exporting, so that traces started
+	// by callers continue through to the ffmpegd daemons.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(serviceVersion),
+	))
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("creating trace resource: %w", err), exporter.Shutdown(ctx))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as a child of any span in ctx. Spans are no-ops when
+// tracing is disabled.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends a span, marking it as failed when err is not nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectTraceContext returns the trace context of ctx as string pairs, for
+// messages that carry it across process boundaries, such as the ffmpegd
+// Transcode stream. It returns nil when ctx has no span.
+func InjectTraceContext(ctx context.Context) map[string]string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// ExtractTraceContext returns ctx with the remote span context carried in
+// traceContext, as produced by InjectTraceContext, as its parent.
+func ExtractTraceContext(ctx context.Context, traceContext map[string]string) context.Context {
+	if len(traceContext) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(traceContext))
+}
+
+// DetachedContext returns a background context that carries only the span of
+// ctx, for work that continues after the request that started it completes.
+func DetachedContext(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+// grpcTraceFilter excludes the periodic Heartbeat call and the long-lived
+// Transcode stream from gRPC call spans. Jobs and probes sent over the stream
+// carry their own trace context instead.
+var grpcTraceFilter = filters.None(
+	filters.MethodName("Heartbeat"),
+	filters.MethodName("Transcode"),
+)
+
+// GRPCServerHandler returns a gRPC stats handler that traces incoming calls,
+// continuing the caller's trace.
+func GRPCServerHandler() stats.Handler {
+	return otelgrpc.NewServerHandler(otelgrpc.WithFilter(grpcTraceFilter))
+}
+
+// GRPCClientHandler returns a gRPC stats handler that traces outgoing calls
+// and propagates their trace context.
+func GRPCClientHandler() stats.Handler {
+	return otelgrpc.NewClientHandler(otelgrpc.WithFilter(grpcTraceFilter))
+}
@@ -0,0 +1,79 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that records ended spans in memory.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	_, err := InitTracing(context.Background(), config.TracingConfig{}, "tvarr", "test")
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestTraceContext_RoundTrip(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, parent := StartSpan(context.Background(), "relay.transcoder.start")
+	carrier := InjectTraceContext(ctx)
+	require.Contains(t, carrier, "traceparent")
+
+	// The daemon continues the trace from the message's trace context
+	_, child := StartSpan(ExtractTraceContext(context.Background(), carrier), "ffmpegd.transcode.start")
+	EndSpan(child, errors.New("ffmpeg exited"))
+	EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "ffmpegd.transcode.start", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.True(t, spans[0].Parent().IsRemote())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestTraceContext_NoSpan(t *testing.T) {
+	recordSpans(t)
+
+	assert.Nil(t, InjectTraceContext(context.Background()))
+
+	ctx := context.Background()
+	assert.Equal(t, ctx, ExtractTraceContext(ctx, nil))
+}
+
+func TestDetachedContext(t *testing.T) {
+	recorder := recordSpans(t)
+
+	requestCtx, cancel := context.WithCancel(context.Background())
+	requestCtx, span := StartSpan(requestCtx, "POST /api/v1/sources/{id}/ingest")
+	detached := DetachedContext(requestCtx)
+	cancel()
+	span.End()
+
+	// Background work outlives the request but stays in its trace
+	require.NoError(t, detached.Err())
+	_, work := StartSpan(detached, "ingest.stream_source")
+	work.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, span.SpanContext().SpanID(), spans[1].Parent().SpanID())
+}
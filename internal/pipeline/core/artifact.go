@@ -36,6 +36,12 @@ const (
 	// ProcessingStageTransformed indicates data after data mapping/transformation.
 	ProcessingStageTransformed ProcessingStage = "transformed"
 
+	// ProcessingStageEpgMatched indicates data after EPG name matching.
+	ProcessingStageEpgMatched ProcessingStage = "epg_matched"
+
+	// ProcessingStageDeduplicated indicates data after cross-source deduplication.
+	ProcessingStageDeduplicated ProcessingStage = "deduplicated"
+
 	// ProcessingStageNumbered indicates data after channel numbering.
 	ProcessingStageNumbered ProcessingStage = "numbered"
 
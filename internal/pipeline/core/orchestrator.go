@@ -10,9 +10,11 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/observability"
 )
 
 // activeExecutions tracks which proxies have pipelines running.
@@ -61,6 +63,17 @@ func (o *Orchestrator) SetEpgSources(sources []*models.EpgSource) {
 // Execute runs all stages in sequence.
 // Returns a Result with execution details and any errors.
 func (o *Orchestrator) Execute(ctx context.Context) (*Result, error) {
+	ctx, span := observability.StartSpan(ctx, "pipeline.execute",
+		attribute.String("tvarr.proxy.id", o.state.ProxyID.String()),
+		attribute.Int("tvarr.pipeline.stage_count", len(o.stages)),
+	)
+	result, err := o.execute(ctx)
+	observability.EndSpan(span, err)
+	return result, err
+}
+
+// execute runs the stages of Execute.
+func (o *Orchestrator) execute(ctx context.Context) (*Result, error) {
 	result := &Result{
 		Success:      false,
 		StageResults: make(map[string]*StageResult),
@@ -225,6 +238,11 @@ func (o *Orchestrator) Execute(ctx context.Context) (*Result, error) {
 func (o *Orchestrator) executeStage(ctx context.Context, index int, stage Stage) (*StageResult, error) {
 	stageStart := time.Now()
 
+	ctx, span := observability.StartSpan(ctx, "pipeline.stage "+stage.ID(),
+		attribute.String("tvarr.pipeline.stage", stage.ID()),
+		attribute.Int("tvarr.pipeline.stage_num", index+1),
+	)
+
 	o.logger.InfoContext(ctx, "executing stage",
 		slog.Int("stage_num", index+1),
 		slog.Int("total_stages", len(o.stages)),
@@ -242,6 +260,14 @@ func (o *Orchestrator) executeStage(ctx context.Context, index int, stage Stage)
 	}
 	stageResult.Duration = time.Since(stageStart)
 
+	status := "completed"
+	if err != nil {
+		status = "failed"
+	}
+	observability.PipelineStageDuration.Observe(stageResult.Duration.Seconds(), stage.ID(), status)
+	span.SetAttributes(attribute.Int("tvarr.pipeline.records_processed", stageResult.RecordsProcessed))
+	observability.EndSpan(span, err)
+
 	if err != nil {
 		o.logger.ErrorContext(ctx, "stage failed",
 			slog.String("stage_id", stage.ID()),
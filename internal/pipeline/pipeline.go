@@ -13,8 +13,11 @@ import (
 	"github.com/jmylchreest/tvarr/internal/ingestor"
 	"github.com/jmylchreest/tvarr/internal/pipeline/core"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/datamapping"
+	"github.com/jmylchreest/tvarr/internal/pipeline/stages/dedup"
+	"github.com/jmylchreest/tvarr/internal/pipeline/stages/epgmatch"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/filtering"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/generatem3u"
+	"github.com/jmylchreest/tvarr/internal/pipeline/stages/generatevod"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/generatexmltv"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/ingestionguard"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/loadchannels"
@@ -106,6 +109,10 @@ func NewFactory(deps *Dependencies) *Factory {
 // If stateManager is nil, ingestion guard stage is skipped.
 // If logoCacher is nil, logo caching stage is skipped.
 // If jobRepo is nil, pending job checking in the ingestion guard is disabled.
+// codecLookup and alternateStore are used by the dedup stage to rank duplicates by
+// probed quality and to persist alternates for relay failover; either may be nil.
+// movieStore and seriesStore provide VOD catalogues; if either is nil, VOD
+// playlist generation is skipped.
 // baseURL is used to construct fully qualified URLs for cached logos (e.g., "http://localhost:8080").
 func NewDefaultFactory(
 	channelRepo repository.ChannelRepository,
@@ -117,6 +124,12 @@ func NewDefaultFactory(
 	logoCacher logocaching.LogoCacher,
 	stateManager *ingestor.StateManager,
 	jobRepo repository.JobRepository,
+	codecLookup dedup.CodecLookup,
+	alternateStore dedup.AlternateStore,
+	epgMatchStore epgmatch.Store,
+	epgMatchThresholds epgmatch.Thresholds,
+	movieStore generatevod.MovieStore,
+	seriesStore generatevod.SeriesStore,
 	baseURL string,
 ) *Factory {
 	deps := &Dependencies{
@@ -147,6 +160,14 @@ func NewDefaultFactory(
 	factory.RegisterStage(loadchannels.NewConstructor())
 	factory.RegisterStage(datamapping.NewConstructor())
 	factory.RegisterStage(filtering.NewConstructor())
+	// Assign tvg-ids by name before deduplication, so tvg-id identities and
+	// program loading both see matched channels.
+	if epgMatchStore != nil {
+		factory.RegisterStage(epgmatch.NewConstructor(epgMatchStore, epgMatchThresholds))
+	}
+	// Collapse channels duplicated across sources before programs are loaded,
+	// so only programs for the kept channels are matched.
+	factory.RegisterStage(dedup.NewConstructor(codecLookup, alternateStore))
 	// Load programs AFTER filtering so only programs for surviving channels
 	// are loaded into memory. This prevents OOM when filters reduce a large
 	// channel set (e.g., 70K channels) down to a small subset. The ChannelMap
@@ -162,6 +183,12 @@ func NewDefaultFactory(
 
 	factory.RegisterStage(generatem3u.NewConstructor())
 	factory.RegisterStage(generatexmltv.NewConstructor())
+
+	// VOD playlists (optional - only if catalogue stores provided)
+	if movieStore != nil && seriesStore != nil {
+		factory.RegisterStage(generatevod.NewConstructor(movieStore, seriesStore))
+	}
+
 	factory.RegisterStage(publish.NewConstructor())
 
 	return factory
@@ -174,9 +201,12 @@ const (
 	StageIDLoadPrograms   = loadprograms.StageID
 	StageIDFiltering      = filtering.StageID
 	StageIDDataMapping    = datamapping.StageID
+	StageIDEpgMatch       = epgmatch.StageID
+	StageIDDedup          = dedup.StageID
 	StageIDNumbering      = numbering.StageID
 	StageIDLogoCaching    = logocaching.StageID
 	StageIDGenerateM3U    = generatem3u.StageID
 	StageIDGenerateXMLTV  = generatexmltv.StageID
+	StageIDGenerateVOD    = generatevod.StageID
 	StageIDPublish        = publish.StageID
 )
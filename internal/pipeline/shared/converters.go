@@ -19,6 +19,13 @@ func BuildProxyStreamURL(baseURL string, proxyID, channelID models.ULID) string
 	return fmt.Sprintf("%s/proxy/%s/%s", baseURL, proxyID.String(), channelID.String())
 }
 
+// BuildProxyCatchupURL builds the catch-up source template for a channel.
+// The {utc} and {duration} placeholders are filled in by the player.
+// Format: {baseURL}/proxy/{proxyId}/{channelId}/catchup?start={utc}&duration={duration}
+func BuildProxyCatchupURL(baseURL string, proxyID, channelID models.ULID) string {
+	return BuildProxyStreamURL(baseURL, proxyID, channelID) + "/catchup?start={utc}&duration={duration}"
+}
+
 // ChannelToM3UEntry converts a Channel model to an M3U Entry.
 // channelNum should be the final channel number (set by the numbering stage).
 func ChannelToM3UEntry(ch *models.Channel, channelNum int) *m3u.Entry {
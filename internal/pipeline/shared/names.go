@@ -0,0 +1,62 @@
+package shared
+
+import (
+	"strings"
+	"unicode"
+)
+
+// qualityTokens are name words that describe the feed rather than the channel.
+var qualityTokens = map[string]bool{
+	"sd": true, "hd": true, "fhd": true, "uhd": true, "qhd": true,
+	"4k": true, "8k": true, "hevc": true, "h264": true, "h265": true,
+	"480p": true, "576p": true, "720p": true, "1080p": true, "1080i": true, "2160p": true,
+	"50fps": true, "60fps": true, "hdr": true, "raw": true, "backup": true,
+}
+
+// NormalizeChannelName reduces a channel name to its identity: lowercase words
+// without punctuation, a leading uppercase country or provider prefix ("UK: ",
+// "US| ", "[DE] ") or quality markers, so "UK: BBC One HD" and "BBC One"
+// compare equal.
+func NormalizeChannelName(name string) string {
+	name = stripNamePrefix(strings.TrimSpace(name))
+	name = strings.ToLower(name)
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if !qualityTokens[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// stripNamePrefix removes a leading 2-4 letter uppercase prefix followed by ':'
+// or '|', or enclosed in brackets.
+func stripNamePrefix(name string) string {
+	if i := strings.IndexAny(name, ":|"); i >= 2 && i <= 4 && isUpperWord(name[:i]) {
+		return name[i+1:]
+	}
+	if len(name) > 0 && (name[0] == '[' || name[0] == '(') {
+		closing := "]"
+		if name[0] == '(' {
+			closing = ")"
+		}
+		if i := strings.Index(name, closing); i >= 3 && i <= 5 && isUpperWord(name[1:i]) {
+			return name[i+1:]
+		}
+	}
+	return name
+}
+
+// isUpperWord reports whether s consists only of uppercase letters.
+func isUpperWord(s string) bool {
+	for _, r := range s {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeChannelName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"BBC One", "bbc one"},
+		{"BBC One HD", "bbc one"},
+		{"UK: BBC One FHD", "bbc one"},
+		{"US| ESPN 4K", "espn"},
+		{"[DE] Das Erste HD", "das erste"},
+		{"(UK) ITV", "itv"},
+		{"Sky Sports F1 (1080p)", "sky sports f1"},
+		{"  Channel-4  ", "channel 4"},
+		{"Re:Play", "re play"},
+		{"(Live) News", "live news"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeChannelName(tt.name))
+		})
+	}
+}
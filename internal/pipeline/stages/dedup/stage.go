@@ -0,0 +1,336 @@
+// Package dedup implements the cross-source channel deduplication pipeline stage.
+//
+// Channels are grouped by an identity derived from the proxy's dedup settings:
+// the tvg-id, a normalised channel name, or the regex captures of an expression.
+// When the proxy collapses duplicates, each group keeps a single channel, chosen
+// by source priority or probed stream quality, and the others are recorded as
+// its alternates so the relay can fail over to them.
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/jmylchreest/tvarr/internal/expression"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/core"
+	"github.com/jmylchreest/tvarr/internal/pipeline/shared"
+)
+
+const (
+	// StageID is the unique identifier for this stage.
+	StageID = "dedup"
+	// StageName is the human-readable name for this stage.
+	StageName = "Channel Deduplication"
+)
+
+// CodecLookup provides probed codec information used to rank duplicates by quality.
+type CodecLookup interface {
+	// GetByStreamURL retrieves codec info by stream URL, or nil if not probed.
+	GetByStreamURL(ctx context.Context, streamURL string) (*models.LastKnownCodec, error)
+}
+
+// AlternateStore persists the alternates of collapsed channels.
+type AlternateStore interface {
+	// SetChannelAlternates replaces the channel alternates of a proxy.
+	SetChannelAlternates(ctx context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error
+}
+
+// Stage collapses channels duplicated across a proxy's sources.
+type Stage struct {
+	shared.BaseStage
+	codecs     CodecLookup
+	alternates AlternateStore
+	logger     *slog.Logger
+}
+
+// New creates a new dedup stage. Either dependency may be nil: without codecs,
+// quality preference falls back to source priority; without an alternate store,
+// alternates are not persisted.
+func New(codecs CodecLookup, alternates AlternateStore) *Stage {
+	return &Stage{
+		BaseStage:  shared.NewBaseStage(StageID, StageName),
+		codecs:     codecs,
+		alternates: alternates,
+	}
+}
+
+// NewConstructor returns a stage constructor for use with the factory.
+func NewConstructor(codecs CodecLookup, alternates AlternateStore) core.StageConstructor {
+	return func(deps *core.Dependencies) core.Stage {
+		s := New(codecs, alternates)
+		if deps.Logger != nil {
+			s.logger = deps.Logger.With("stage", StageID)
+		}
+		return s
+	}
+}
+
+// candidate is a channel in a duplicate group with its ranking inputs.
+type candidate struct {
+	channel    *models.Channel
+	sourceRank int // Index in state.Sources (lower = higher priority)
+	height     int
+	bitrate    int
+}
+
+// Execute groups duplicate channels and, in collapse mode, keeps the best of each group.
+func (s *Stage) Execute(ctx context.Context, state *core.State) (*core.StageResult, error) {
+	result := shared.NewResult()
+
+	proxy := state.Proxy
+	if proxy == nil || proxy.DedupMode != models.DedupModeCollapse {
+		// Clear alternates left over from when the proxy collapsed duplicates
+		s.storeAlternates(ctx, state, nil)
+		result.Message = "Duplicate channels kept"
+		return result, nil
+	}
+
+	if len(state.Channels) == 0 {
+		s.storeAlternates(ctx, state, nil)
+		s.log(ctx, slog.LevelInfo, "no channels to deduplicate, skipping")
+		result.Message = "No channels to deduplicate"
+		return result, nil
+	}
+
+	identity := proxy.DedupIdentity
+	if identity == "" {
+		identity = models.DedupIdentityTvgID
+	}
+	preference := proxy.DedupPreference
+	if preference == "" {
+		preference = models.DedupPreferencePriority
+	}
+
+	s.log(ctx, slog.LevelInfo, "starting channel deduplication",
+		slog.Int("channel_count", len(state.Channels)),
+		slog.String("identity", string(identity)),
+		slog.String("preference", string(preference)))
+
+	keyOf, err := s.identityFunc(identity, proxy.DedupExpression)
+	if err != nil {
+		return result, err
+	}
+
+	sourceRank := make(map[models.ULID]int, len(state.Sources))
+	for i, source := range state.Sources {
+		sourceRank[source.ID] = i
+	}
+
+	// Group channels by identity, remembering the order groups first appear in
+	keys := make([]string, len(state.Channels))
+	groups := make(map[string][]*candidate)
+	for i, ch := range state.Channels {
+		key := keyOf(ch)
+		keys[i] = key
+		if key == "" {
+			continue
+		}
+		rank, ok := sourceRank[ch.SourceID]
+		if !ok {
+			rank = len(state.Sources)
+		}
+		groups[key] = append(groups[key], &candidate{channel: ch, sourceRank: rank})
+	}
+
+	var alternates []*models.ProxyChannelAlternate
+	kept := make(map[string]*models.Channel, len(groups))
+	collapsedGroups := 0
+	for key, group := range groups {
+		if len(group) > 1 {
+			if preference == models.DedupPreferenceQuality {
+				s.probeQuality(ctx, group)
+			}
+			rankCandidates(group, preference)
+			collapsedGroups++
+
+			for i, alt := range group[1:] {
+				alternates = append(alternates, &models.ProxyChannelAlternate{
+					ChannelID:          group[0].channel.ID,
+					AlternateChannelID: alt.channel.ID,
+					Priority:           i,
+				})
+			}
+		}
+		kept[key] = group[0].channel
+	}
+
+	// Each group takes the position of its first member, whichever channel wins
+	channels := make([]*models.Channel, 0, len(state.Channels)-len(alternates))
+	for i, ch := range state.Channels {
+		key := keys[i]
+		if key == "" {
+			channels = append(channels, ch)
+			continue
+		}
+		if winner, ok := kept[key]; ok {
+			channels = append(channels, winner)
+			delete(kept, key)
+		}
+	}
+
+	removed := len(state.Channels) - len(channels)
+	state.Channels = channels
+
+	// Rebuild the channel map so EPG matching only sees kept channels
+	channelMap := make(map[string]*models.Channel, len(channels))
+	for _, ch := range channels {
+		if ch.TvgID != "" {
+			channelMap[ch.TvgID] = ch
+		}
+	}
+	state.ChannelMap = channelMap
+
+	s.storeAlternates(ctx, state, alternates)
+
+	result.RecordsProcessed = len(channels) + removed
+	result.RecordsModified = removed
+	result.Message = fmt.Sprintf("Collapsed %d duplicate channels into %d channels", removed+collapsedGroups, collapsedGroups)
+
+	s.log(ctx, slog.LevelInfo, "channel deduplication complete",
+		slog.Int("duplicate_groups", collapsedGroups),
+		slog.Int("channels_removed", removed),
+		slog.Int("channels_remaining", len(channels)))
+
+	artifact := core.NewArtifact(core.ArtifactTypeChannels, core.ProcessingStageDeduplicated, StageID).
+		WithRecordCount(len(channels)).
+		WithMetadata("identity", string(identity)).
+		WithMetadata("preference", string(preference)).
+		WithMetadata("duplicate_groups", collapsedGroups).
+		WithMetadata("channels_removed", removed)
+	result.Artifacts = append(result.Artifacts, artifact)
+
+	return result, nil
+}
+
+// identityFunc returns the function deriving a channel's identity key.
+// An empty key means the channel is never treated as a duplicate.
+func (s *Stage) identityFunc(identity models.DedupIdentity, expr string) (func(*models.Channel) string, error) {
+	switch identity {
+	case models.DedupIdentityName:
+		return func(ch *models.Channel) string {
+			return shared.NormalizeChannelName(ch.ChannelName)
+		}, nil
+
+	case models.DedupIdentityExpression:
+		if strings.TrimSpace(expr) == "" {
+			return nil, fmt.Errorf("dedup expression is required for the expression identity")
+		}
+		parsed, err := expression.PreprocessAndParse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing dedup expression: %w", err)
+		}
+		evaluator := expression.NewEvaluator()
+		evaluator.SetCaseSensitive(false)
+		return func(ch *models.Channel) string {
+			evalResult, err := evaluator.Evaluate(parsed, channelEvalContext(ch))
+			if err != nil || !evalResult.Matches {
+				return ""
+			}
+			return captureKey(evalResult.Captures)
+		}, nil
+
+	default:
+		return func(ch *models.Channel) string {
+			return strings.TrimSpace(ch.TvgID)
+		}, nil
+	}
+}
+
+// probeQuality fills in the probed resolution and bitrate of each candidate.
+func (s *Stage) probeQuality(ctx context.Context, group []*candidate) {
+	if s.codecs == nil {
+		return
+	}
+	for _, c := range group {
+		codec, err := s.codecs.GetByStreamURL(ctx, c.channel.StreamURL)
+		if err != nil {
+			s.log(ctx, slog.LevelDebug, "failed to look up probed codec",
+				slog.String("stream_url", c.channel.StreamURL),
+				slog.String("error", err.Error()))
+			continue
+		}
+		if codec == nil || !codec.IsValid() {
+			continue
+		}
+		c.height = codec.VideoHeight
+		c.bitrate = codec.VideoBitrate
+	}
+}
+
+// storeAlternates persists the proxy's alternates. Failures are logged, as the
+// generated playlist is still correct without them.
+func (s *Stage) storeAlternates(ctx context.Context, state *core.State, alternates []*models.ProxyChannelAlternate) {
+	if s.alternates == nil || state.ProxyID.IsZero() {
+		return
+	}
+	if err := s.alternates.SetChannelAlternates(ctx, state.ProxyID, alternates); err != nil {
+		s.log(ctx, slog.LevelWarn, "failed to store channel alternates",
+			slog.String("error", err.Error()))
+		state.Errors = append(state.Errors, fmt.Errorf("storing channel alternates: %w", err))
+	}
+}
+
+// log logs a message if the logger is set.
+func (s *Stage) log(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+	if s.logger != nil {
+		s.logger.Log(ctx, level, msg, attrs...)
+	}
+}
+
+// rankCandidates orders a duplicate group best first. Quality preference ranks by
+// probed resolution then bitrate; ties and unprobed streams fall back to source
+// priority, then to the order channels were loaded in.
+func rankCandidates(group []*candidate, preference models.DedupPreference) {
+	sort.SliceStable(group, func(i, j int) bool {
+		a, b := group[i], group[j]
+		if preference == models.DedupPreferenceQuality {
+			if a.height != b.height {
+				return a.height > b.height
+			}
+			if a.bitrate != b.bitrate {
+				return a.bitrate > b.bitrate
+			}
+		}
+		return a.sourceRank < b.sourceRank
+	})
+}
+
+// captureKey builds an identity key from regex captures: the capture groups if
+// the pattern has any, otherwise the full match.
+func captureKey(captures []string) string {
+	if len(captures) == 0 {
+		return ""
+	}
+	parts := captures
+	if len(captures) > 1 {
+		parts = captures[1:]
+	}
+	normalized := make([]string, len(parts))
+	for i, part := range parts {
+		normalized[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+	key := strings.Join(normalized, "\x00")
+	if strings.Trim(key, "\x00") == "" {
+		return ""
+	}
+	return key
+}
+
+// channelEvalContext creates an expression evaluation context for a channel.
+func channelEvalContext(ch *models.Channel) expression.FieldValueAccessor {
+	return expression.NewChannelEvalContext(map[string]string{
+		"channel_name": ch.ChannelName,
+		"tvg_id":       ch.TvgID,
+		"tvg_name":     ch.TvgName,
+		"tvg_logo":     ch.TvgLogo,
+		"group_title":  ch.GroupTitle,
+		"stream_url":   ch.StreamURL,
+	})
+}
+
+// Ensure Stage implements core.Stage.
+var _ core.Stage = (*Stage)(nil)
@@ -0,0 +1,195 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCodecLookup struct {
+	codecs map[string]*models.LastKnownCodec
+}
+
+func (m *mockCodecLookup) GetByStreamURL(_ context.Context, streamURL string) (*models.LastKnownCodec, error) {
+	return m.codecs[streamURL], nil
+}
+
+type mockAlternateStore struct {
+	proxyID    models.ULID
+	alternates []*models.ProxyChannelAlternate
+	calls      int
+}
+
+func (m *mockAlternateStore) SetChannelAlternates(_ context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error {
+	m.proxyID = proxyID
+	m.alternates = alternates
+	m.calls++
+	return nil
+}
+
+// testSetup creates a state with two sources, the first having the higher priority.
+func testSetup(proxy *models.StreamProxy) (*core.State, *models.StreamSource, *models.StreamSource) {
+	proxy.ID = models.NewULID()
+	primary := &models.StreamSource{BaseModel: models.BaseModel{ID: models.NewULID()}, Name: "primary"}
+	backup := &models.StreamSource{BaseModel: models.BaseModel{ID: models.NewULID()}, Name: "backup"}
+
+	state := core.NewState(proxy)
+	state.Sources = []*models.StreamSource{primary, backup}
+	return state, primary, backup
+}
+
+// testChannel creates a minimal channel for testing.
+func testChannel(source *models.StreamSource, name, tvgID string) *models.Channel {
+	return &models.Channel{
+		BaseModel:   models.BaseModel{ID: models.NewULID()},
+		SourceID:    source.ID,
+		ChannelName: name,
+		TvgID:       tvgID,
+		StreamURL:   "http://" + source.Name + ".example/" + name,
+	}
+}
+
+func TestStage_KeepMode(t *testing.T) {
+	store := &mockAlternateStore{}
+	stage := New(nil, store)
+
+	state, primary, backup := testSetup(&models.StreamProxy{DedupMode: models.DedupModeKeep})
+	state.Channels = []*models.Channel{
+		testChannel(primary, "BBC One", "bbc1.uk"),
+		testChannel(backup, "BBC One", "bbc1.uk"),
+	}
+
+	_, err := stage.Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	assert.Len(t, state.Channels, 2)
+	// Alternates from an earlier collapse are cleared
+	assert.Equal(t, 1, store.calls)
+	assert.Empty(t, store.alternates)
+}
+
+func TestStage_CollapseByTvgID(t *testing.T) {
+	store := &mockAlternateStore{}
+	stage := New(nil, store)
+
+	state, primary, backup := testSetup(&models.StreamProxy{
+		DedupMode:     models.DedupModeCollapse,
+		DedupIdentity: models.DedupIdentityTvgID,
+	})
+	backupBBC := testChannel(backup, "BBC One HD", "bbc1.uk")
+	itv := testChannel(primary, "ITV", "itv.uk")
+	primaryBBC := testChannel(primary, "BBC One", "bbc1.uk")
+	noID := testChannel(backup, "Local", "")
+	state.Channels = []*models.Channel{backupBBC, itv, primaryBBC, noID}
+	state.ChannelMap = map[string]*models.Channel{"bbc1.uk": backupBBC, "itv.uk": itv}
+
+	result, err := stage.Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	// The primary source wins and takes the group's first position
+	assert.Equal(t, []*models.Channel{primaryBBC, itv, noID}, state.Channels)
+	assert.Same(t, primaryBBC, state.ChannelMap["bbc1.uk"])
+	assert.Equal(t, 1, result.RecordsModified)
+
+	require.Len(t, store.alternates, 1)
+	assert.Equal(t, state.ProxyID, store.proxyID)
+	assert.Equal(t, primaryBBC.ID, store.alternates[0].ChannelID)
+	assert.Equal(t, backupBBC.ID, store.alternates[0].AlternateChannelID)
+}
+
+func TestStage_CollapseByName(t *testing.T) {
+	stage := New(nil, nil)
+
+	state, primary, backup := testSetup(&models.StreamProxy{
+		DedupMode:     models.DedupModeCollapse,
+		DedupIdentity: models.DedupIdentityName,
+	})
+	state.Channels = []*models.Channel{
+		testChannel(primary, "BBC One", "a"),
+		testChannel(backup, "UK: BBC One HD", "b"),
+		testChannel(backup, "BBC Two", "c"),
+	}
+
+	_, err := stage.Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	require.Len(t, state.Channels, 2)
+	assert.Equal(t, "BBC One", state.Channels[0].ChannelName)
+	assert.Equal(t, "BBC Two", state.Channels[1].ChannelName)
+}
+
+func TestStage_CollapseByExpression(t *testing.T) {
+	stage := New(nil, nil)
+
+	state, primary, backup := testSetup(&models.StreamProxy{
+		DedupMode:       models.DedupModeCollapse,
+		DedupIdentity:   models.DedupIdentityExpression,
+		DedupExpression: `channel_name matches "^Sky Sports ([a-z]+)"`,
+	})
+	state.Channels = []*models.Channel{
+		testChannel(primary, "Sky Sports Main Event", "a"),
+		testChannel(backup, "Sky Sports Main Event 4K", "b"),
+		testChannel(backup, "Sky Sports Premier League", "c"),
+		// Non-matching channels are never collapsed
+		testChannel(primary, "News", "d"),
+		testChannel(backup, "News", "e"),
+	}
+
+	_, err := stage.Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(state.Channels))
+	for _, ch := range state.Channels {
+		names = append(names, ch.ChannelName)
+	}
+	assert.Equal(t, []string{"Sky Sports Main Event", "Sky Sports Premier League", "News", "News"}, names)
+}
+
+func TestStage_CollapseByExpression_Invalid(t *testing.T) {
+	stage := New(nil, nil)
+
+	state, _, _ := testSetup(&models.StreamProxy{
+		DedupMode:       models.DedupModeCollapse,
+		DedupIdentity:   models.DedupIdentityExpression,
+		DedupExpression: `channel_name matches`,
+	})
+	state.Channels = []*models.Channel{{ChannelName: "BBC One"}}
+
+	_, err := stage.Execute(context.Background(), state)
+	assert.Error(t, err)
+}
+
+func TestStage_PreferQuality(t *testing.T) {
+	store := &mockAlternateStore{}
+	state, primary, backup := testSetup(&models.StreamProxy{
+		DedupMode:       models.DedupModeCollapse,
+		DedupIdentity:   models.DedupIdentityTvgID,
+		DedupPreference: models.DedupPreferenceQuality,
+	})
+	sd := testChannel(primary, "BBC One", "bbc1.uk")
+	hd := testChannel(backup, "BBC One HD", "bbc1.uk")
+	unprobed := testChannel(primary, "BBC One Backup", "bbc1.uk")
+	state.Channels = []*models.Channel{sd, hd, unprobed}
+
+	codecs := &mockCodecLookup{codecs: map[string]*models.LastKnownCodec{
+		sd.StreamURL: {VideoCodec: "h264", VideoHeight: 576, VideoBitrate: 2_000_000},
+		hd.StreamURL: {VideoCodec: "h264", VideoHeight: 1080, VideoBitrate: 6_000_000},
+	}}
+	stage := New(codecs, store)
+
+	_, err := stage.Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	require.Len(t, state.Channels, 1)
+	assert.Same(t, hd, state.Channels[0])
+
+	require.Len(t, store.alternates, 2)
+	assert.Equal(t, sd.ID, store.alternates[0].AlternateChannelID)
+	assert.Equal(t, 0, store.alternates[0].Priority)
+	assert.Equal(t, unprobed.ID, store.alternates[1].AlternateChannelID)
+	assert.Equal(t, 1, store.alternates[1].Priority)
+}
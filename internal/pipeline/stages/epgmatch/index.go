@@ -0,0 +1,133 @@
+package epgmatch
+
+import (
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/shared"
+)
+
+// entry is one display name of an EPG channel.
+type entry struct {
+	channel    *models.EpgChannel
+	name       string // Display name as published
+	normalized string
+	grams      []string
+	sourceRank int // Index in state.EpgSources (lower = higher priority)
+	pos        int // Index in index.entries, for stable tie-breaks
+}
+
+// match is the best entry found for a name and its similarity.
+type match struct {
+	entry *entry
+	score float64
+}
+
+// better reports whether m is preferred over other: a higher score wins, ties
+// go to the higher priority EPG source, then to the first indexed name.
+func (m match) better(other match) bool {
+	if m.entry == nil {
+		return false
+	}
+	if other.entry == nil || m.score != other.score {
+		return m.score > other.score
+	}
+	if m.entry.sourceRank != other.entry.sourceRank {
+		return m.entry.sourceRank < other.entry.sourceRank
+	}
+	return m.entry.pos < other.entry.pos
+}
+
+// index looks up EPG display names by exact normalised name and by trigram.
+type index struct {
+	entries []*entry
+	exact   map[string][]*entry
+	grams   map[string][]int // Trigram to entry indexes
+}
+
+// newIndex indexes every display name of the given EPG channels.
+func newIndex(channels []*models.EpgChannel, sourceRank map[models.ULID]int) *index {
+	idx := &index{
+		exact: make(map[string][]*entry),
+		grams: make(map[string][]int),
+	}
+	for _, ec := range channels {
+		rank, ok := sourceRank[ec.SourceID]
+		if !ok {
+			rank = len(sourceRank)
+		}
+		for _, name := range ec.Names() {
+			normalized := shared.NormalizeChannelName(name)
+			if normalized == "" {
+				continue
+			}
+			e := &entry{
+				channel:    ec,
+				name:       name,
+				normalized: normalized,
+				grams:      trigrams(normalized),
+				sourceRank: rank,
+				pos:        len(idx.entries),
+			}
+			for _, g := range e.grams {
+				idx.grams[g] = append(idx.grams[g], e.pos)
+			}
+			idx.entries = append(idx.entries, e)
+			idx.exact[normalized] = append(idx.exact[normalized], e)
+		}
+	}
+	return idx
+}
+
+// best returns the EPG display name most similar to name. Equal normalised
+// names score 1; otherwise the score is the Dice coefficient of the names'
+// trigram sets.
+func (idx *index) best(name string) match {
+	normalized := shared.NormalizeChannelName(name)
+	if normalized == "" {
+		return match{}
+	}
+
+	var result match
+	if exact := idx.exact[normalized]; len(exact) > 0 {
+		for _, e := range exact {
+			if m := (match{entry: e, score: 1}); m.better(result) {
+				result = m
+			}
+		}
+		return result
+	}
+
+	grams := trigrams(normalized)
+	counts := make(map[int]int)
+	for _, g := range grams {
+		for _, i := range idx.grams[g] {
+			counts[i]++
+		}
+	}
+	for i, count := range counts {
+		e := idx.entries[i]
+		score := 2 * float64(count) / float64(len(grams)+len(e.grams))
+		if m := (match{entry: e, score: score}); m.better(result) {
+			result = m
+		}
+	}
+	return result
+}
+
+// trigrams returns the distinct character trigrams of s, padded with spaces so
+// word boundaries count.
+func trigrams(s string) []string {
+	runes := []rune(" " + s + " ")
+	if len(runes) < 3 {
+		return nil
+	}
+	seen := make(map[string]bool, len(runes))
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		g := string(runes[i : i+3])
+		if !seen[g] {
+			seen[g] = true
+			grams = append(grams, g)
+		}
+	}
+	return grams
+}
@@ -0,0 +1,261 @@
+// Package epgmatch implements the EPG channel matching pipeline stage.
+//
+// Channels whose tvg-id is empty or unknown to the proxy's EPG sources are
+// matched by name against the display names of the EPG sources' channels.
+// Names are normalised (case, country prefixes and quality markers) and
+// compared exactly, then by trigram similarity. Matches above the automatic
+// threshold assign the tvg-id; weaker candidates are recorded as pending so
+// they can be confirmed or rejected through the API.
+package epgmatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/core"
+	"github.com/jmylchreest/tvarr/internal/pipeline/shared"
+)
+
+const (
+	// StageID is the unique identifier for this stage.
+	StageID = "epgmatch"
+	// StageName is the human-readable name for this stage.
+	StageName = "EPG Channel Matching"
+)
+
+// Default confidence thresholds.
+const (
+	DefaultAutoThreshold      = 0.9
+	DefaultCandidateThreshold = 0.6
+)
+
+// Store provides EPG channel definitions and persists name matches.
+type Store interface {
+	// GetBySourceIDs retrieves the channels of the given EPG sources.
+	GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID) ([]*models.EpgChannel, error)
+	// GetMatchesByChannelIDs retrieves the matches of the given stream channels.
+	GetMatchesByChannelIDs(ctx context.Context, channelIDs []models.ULID) ([]*models.EpgChannelMatch, error)
+	// SaveMatches creates or replaces matches, keyed by stream channel.
+	SaveMatches(ctx context.Context, matches []*models.EpgChannelMatch) error
+}
+
+// Thresholds are the confidence levels, from 0 to 1, at which name matches are
+// applied automatically (Auto) or recorded for confirmation (Candidate).
+type Thresholds struct {
+	Auto      float64
+	Candidate float64
+}
+
+// DefaultThresholds returns the default confidence thresholds.
+func DefaultThresholds() Thresholds {
+	return Thresholds{Auto: DefaultAutoThreshold, Candidate: DefaultCandidateThreshold}
+}
+
+// Stage assigns tvg-ids to channels by matching their names against EPG
+// channel display names.
+type Stage struct {
+	shared.BaseStage
+	store      Store
+	thresholds Thresholds
+	logger     *slog.Logger
+}
+
+// New creates a new EPG matching stage. Zero thresholds use the defaults.
+func New(store Store, thresholds Thresholds) *Stage {
+	if thresholds.Auto <= 0 {
+		thresholds.Auto = DefaultAutoThreshold
+	}
+	if thresholds.Candidate <= 0 {
+		thresholds.Candidate = min(DefaultCandidateThreshold, thresholds.Auto)
+	}
+	return &Stage{
+		BaseStage:  shared.NewBaseStage(StageID, StageName),
+		store:      store,
+		thresholds: thresholds,
+	}
+}
+
+// NewConstructor returns a stage constructor for use with the factory.
+func NewConstructor(store Store, thresholds Thresholds) core.StageConstructor {
+	return func(deps *core.Dependencies) core.Stage {
+		s := New(store, thresholds)
+		if deps.Logger != nil {
+			s.logger = deps.Logger.With("stage", StageID)
+		}
+		return s
+	}
+}
+
+// Execute matches channels without a usable tvg-id to EPG channels.
+func (s *Stage) Execute(ctx context.Context, state *core.State) (*core.StageResult, error) {
+	result := shared.NewResult()
+
+	if s.store == nil || state.Proxy == nil || !state.Proxy.AutoMatchEpg {
+		result.Message = "EPG matching disabled"
+		return result, nil
+	}
+	if len(state.Channels) == 0 || len(state.EpgSources) == 0 {
+		s.log(ctx, slog.LevelInfo, "no channels or EPG sources to match, skipping")
+		result.Message = "No channels or EPG sources to match"
+		return result, nil
+	}
+
+	sourceIDs := make([]models.ULID, len(state.EpgSources))
+	for i, source := range state.EpgSources {
+		sourceIDs[i] = source.ID
+	}
+	epgChannels, err := s.store.GetBySourceIDs(ctx, sourceIDs)
+	if err != nil {
+		// Matching is best effort; the proxy is still generated without it
+		s.log(ctx, slog.LevelWarn, "failed to load EPG channels", slog.String("error", err.Error()))
+		state.AddError(fmt.Errorf("loading EPG channels: %w", err))
+		result.Message = "EPG channels unavailable"
+		return result, nil
+	}
+	if len(epgChannels) == 0 {
+		s.log(ctx, slog.LevelInfo, "EPG sources have no channel definitions, skipping")
+		result.Message = "No EPG channel definitions to match"
+		return result, nil
+	}
+
+	known := make(map[string]bool, len(epgChannels))
+	for _, ec := range epgChannels {
+		known[ec.ChannelID] = true
+	}
+
+	var candidates []*models.Channel
+	for _, ch := range state.Channels {
+		if ch.TvgID == "" || !known[ch.TvgID] {
+			candidates = append(candidates, ch)
+		}
+	}
+	if len(candidates) == 0 {
+		result.Message = "All channels have a known tvg-id"
+		return result, nil
+	}
+
+	s.log(ctx, slog.LevelInfo, "starting EPG channel matching",
+		slog.Int("candidate_count", len(candidates)),
+		slog.Int("epg_channel_count", len(epgChannels)))
+
+	channelIDs := make([]models.ULID, len(candidates))
+	for i, ch := range candidates {
+		channelIDs[i] = ch.ID
+	}
+	existing := make(map[models.ULID]*models.EpgChannelMatch)
+	stored, err := s.store.GetMatchesByChannelIDs(ctx, channelIDs)
+	if err != nil {
+		s.log(ctx, slog.LevelWarn, "failed to load EPG channel matches", slog.String("error", err.Error()))
+		state.AddError(fmt.Errorf("loading EPG channel matches: %w", err))
+		result.Message = "EPG channel matches unavailable"
+		return result, nil
+	}
+	for _, m := range stored {
+		existing[m.ChannelID] = m
+	}
+
+	sourceRank := make(map[models.ULID]int, len(state.EpgSources))
+	for i, source := range state.EpgSources {
+		sourceRank[source.ID] = i
+	}
+	idx := newIndex(epgChannels, sourceRank)
+
+	var changed []*models.EpgChannelMatch
+	applied, pending := 0, 0
+	for _, ch := range candidates {
+		prev := existing[ch.ID]
+		if prev != nil {
+			switch prev.Status {
+			case models.EpgMatchStatusConfirmed:
+				applyTvgID(state, ch, prev.TvgID)
+				applied++
+				continue
+			case models.EpgMatchStatusRejected:
+				continue
+			}
+		}
+
+		best := idx.best(ch.ChannelName)
+		if ch.TvgName != "" {
+			if alt := idx.best(ch.TvgName); alt.better(best) {
+				best = alt
+			}
+		}
+		if best.entry == nil || best.score < s.thresholds.Candidate {
+			continue
+		}
+
+		status := models.EpgMatchStatusPending
+		if best.score >= s.thresholds.Auto {
+			status = models.EpgMatchStatusAuto
+			applyTvgID(state, ch, best.entry.channel.ChannelID)
+			applied++
+		} else {
+			pending++
+		}
+
+		if prev != nil && prev.Status == status && prev.TvgID == best.entry.channel.ChannelID &&
+			prev.Confidence == best.score && prev.ChannelName == ch.ChannelName {
+			continue
+		}
+		changed = append(changed, &models.EpgChannelMatch{
+			ChannelID:      ch.ID,
+			ChannelName:    ch.ChannelName,
+			EpgSourceID:    best.entry.channel.SourceID,
+			TvgID:          best.entry.channel.ChannelID,
+			EpgDisplayName: best.entry.name,
+			Confidence:     best.score,
+			Status:         status,
+		})
+	}
+
+	if len(changed) > 0 {
+		if err := s.store.SaveMatches(ctx, changed); err != nil {
+			s.log(ctx, slog.LevelWarn, "failed to store EPG channel matches", slog.String("error", err.Error()))
+			state.AddError(fmt.Errorf("storing EPG channel matches: %w", err))
+		}
+	}
+
+	result.RecordsProcessed = len(candidates)
+	result.RecordsModified = applied
+	result.Message = fmt.Sprintf("Matched %d of %d channels to EPG channels, %d awaiting confirmation",
+		applied, len(candidates), pending)
+
+	s.log(ctx, slog.LevelInfo, "EPG channel matching complete",
+		slog.Int("candidate_count", len(candidates)),
+		slog.Int("matched", applied),
+		slog.Int("pending", pending))
+
+	artifact := core.NewArtifact(core.ArtifactTypeChannels, core.ProcessingStageEpgMatched, StageID).
+		WithRecordCount(len(state.Channels)).
+		WithMetadata("candidates", len(candidates)).
+		WithMetadata("matched", applied).
+		WithMetadata("pending", pending)
+	result.Artifacts = append(result.Artifacts, artifact)
+
+	return result, nil
+}
+
+// applyTvgID sets a channel's tvg-id and makes it visible to programme loading.
+// A channel already holding the tvg-id in the channel map keeps it.
+func applyTvgID(state *core.State, ch *models.Channel, tvgID string) {
+	if ch.TvgID != "" && state.ChannelMap[ch.TvgID] == ch {
+		delete(state.ChannelMap, ch.TvgID)
+	}
+	ch.TvgID = tvgID
+	if _, ok := state.ChannelMap[tvgID]; !ok {
+		state.ChannelMap[tvgID] = ch
+	}
+}
+
+// log logs a message if the logger is set.
+func (s *Stage) log(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+	if s.logger != nil {
+		s.logger.Log(ctx, level, msg, attrs...)
+	}
+}
+
+// Ensure Stage implements core.Stage.
+var _ core.Stage = (*Stage)(nil)
@@ -0,0 +1,192 @@
+package epgmatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStore struct {
+	channels []*models.EpgChannel
+	matches  map[models.ULID]*models.EpgChannelMatch
+	saved    []*models.EpgChannelMatch
+}
+
+func (m *mockStore) GetBySourceIDs(_ context.Context, sourceIDs []models.ULID) ([]*models.EpgChannel, error) {
+	wanted := make(map[models.ULID]bool, len(sourceIDs))
+	for _, id := range sourceIDs {
+		wanted[id] = true
+	}
+	var result []*models.EpgChannel
+	for _, ch := range m.channels {
+		if wanted[ch.SourceID] {
+			result = append(result, ch)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStore) GetMatchesByChannelIDs(_ context.Context, channelIDs []models.ULID) ([]*models.EpgChannelMatch, error) {
+	var result []*models.EpgChannelMatch
+	for _, id := range channelIDs {
+		if match, ok := m.matches[id]; ok {
+			result = append(result, match)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockStore) SaveMatches(_ context.Context, matches []*models.EpgChannelMatch) error {
+	m.saved = append(m.saved, matches...)
+	return nil
+}
+
+// epgChannel creates an EPG channel with the given display names.
+func epgChannel(source *models.EpgSource, id string, names ...string) *models.EpgChannel {
+	ch := &models.EpgChannel{SourceID: source.ID, ChannelID: id}
+	ch.SetNames(names)
+	return ch
+}
+
+// testSetup creates a matching-enabled state with a single EPG source.
+func testSetup(channels ...*models.Channel) (*core.State, *models.EpgSource) {
+	proxy := &models.StreamProxy{AutoMatchEpg: true}
+	proxy.ID = models.NewULID()
+	source := &models.EpgSource{BaseModel: models.BaseModel{ID: models.NewULID()}, Name: "epg"}
+
+	state := core.NewState(proxy)
+	state.EpgSources = []*models.EpgSource{source}
+	state.Channels = channels
+	for _, ch := range channels {
+		if ch.TvgID != "" {
+			state.ChannelMap[ch.TvgID] = ch
+		}
+	}
+	return state, source
+}
+
+// testChannel creates a minimal channel for testing.
+func testChannel(name, tvgID string) *models.Channel {
+	return &models.Channel{
+		BaseModel:   models.BaseModel{ID: models.NewULID()},
+		ChannelName: name,
+		TvgID:       tvgID,
+	}
+}
+
+func TestStage_Disabled(t *testing.T) {
+	ch := testChannel("BBC One", "")
+	state, source := testSetup(ch)
+	state.Proxy.AutoMatchEpg = false
+	store := &mockStore{channels: []*models.EpgChannel{epgChannel(source, "bbc1.uk", "BBC One")}}
+
+	_, err := New(store, DefaultThresholds()).Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	assert.Empty(t, ch.TvgID)
+	assert.Empty(t, store.saved)
+}
+
+func TestStage_ExactMatch(t *testing.T) {
+	bbc := testChannel("UK: BBC One HD", "")
+	wrong := testChannel("ITV", "itv-wrong")
+	known := testChannel("Channel 4", "c4.uk")
+	state, source := testSetup(bbc, wrong, known)
+	store := &mockStore{channels: []*models.EpgChannel{
+		epgChannel(source, "bbc1.uk", "BBC One", "BBC 1"),
+		epgChannel(source, "itv.uk", "ITV"),
+		epgChannel(source, "c4.uk", "Channel 4"),
+	}}
+
+	result, err := New(store, DefaultThresholds()).Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bbc1.uk", bbc.TvgID)
+	assert.Equal(t, "itv.uk", wrong.TvgID)
+	assert.Same(t, bbc, state.ChannelMap["bbc1.uk"])
+	assert.Same(t, wrong, state.ChannelMap["itv.uk"])
+	assert.NotContains(t, state.ChannelMap, "itv-wrong")
+	assert.Equal(t, 2, result.RecordsModified)
+
+	require.Len(t, store.saved, 2)
+	assert.Equal(t, bbc.ID, store.saved[0].ChannelID)
+	assert.Equal(t, models.EpgMatchStatusAuto, store.saved[0].Status)
+	assert.Equal(t, "BBC One", store.saved[0].EpgDisplayName)
+	assert.InDelta(t, 1.0, store.saved[0].Confidence, 0.0001)
+}
+
+func TestStage_FuzzyCandidate(t *testing.T) {
+	ch := testChannel("Discovery", "")
+	state, source := testSetup(ch)
+	store := &mockStore{channels: []*models.EpgChannel{
+		epgChannel(source, "discovery.uk", "Discovery Channel"),
+		epgChannel(source, "bbc1.uk", "BBC One"),
+	}}
+
+	_, err := New(store, DefaultThresholds()).Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	// Below the automatic threshold the match awaits confirmation
+	assert.Empty(t, ch.TvgID)
+	require.Len(t, store.saved, 1)
+	assert.Equal(t, models.EpgMatchStatusPending, store.saved[0].Status)
+	assert.Equal(t, "discovery.uk", store.saved[0].TvgID)
+	assert.Greater(t, store.saved[0].Confidence, DefaultCandidateThreshold)
+	assert.Less(t, store.saved[0].Confidence, DefaultAutoThreshold)
+
+	// A lower automatic threshold applies it
+	store.saved = nil
+	_, err = New(store, Thresholds{Auto: 0.6, Candidate: 0.5}).Execute(context.Background(), state)
+	require.NoError(t, err)
+	assert.Equal(t, "discovery.uk", ch.TvgID)
+}
+
+func TestStage_StoredDecisions(t *testing.T) {
+	confirmed := testChannel("Sky Sport Main", "")
+	rejected := testChannel("BBC One", "")
+	state, source := testSetup(confirmed, rejected)
+	store := &mockStore{
+		channels: []*models.EpgChannel{
+			epgChannel(source, "skymain.uk", "Sky Sports Main Event"),
+			epgChannel(source, "bbc1.uk", "BBC One"),
+		},
+		matches: map[models.ULID]*models.EpgChannelMatch{
+			confirmed.ID: {ChannelID: confirmed.ID, TvgID: "skymain.uk", Status: models.EpgMatchStatusConfirmed},
+			rejected.ID:  {ChannelID: rejected.ID, TvgID: "bbc1.uk", Status: models.EpgMatchStatusRejected},
+		},
+	}
+
+	_, err := New(store, DefaultThresholds()).Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	assert.Equal(t, "skymain.uk", confirmed.TvgID)
+	assert.Empty(t, rejected.TvgID)
+	assert.Empty(t, store.saved)
+}
+
+func TestStage_PrefersHigherPriorityEpgSource(t *testing.T) {
+	ch := testChannel("BBC One", "")
+	state, primary := testSetup(ch)
+	secondary := &models.EpgSource{BaseModel: models.BaseModel{ID: models.NewULID()}, Name: "secondary"}
+	state.EpgSources = append(state.EpgSources, secondary)
+	store := &mockStore{channels: []*models.EpgChannel{
+		epgChannel(secondary, "BBCOne.uk", "BBC One"),
+		epgChannel(primary, "bbc1.uk", "BBC One"),
+	}}
+
+	_, err := New(store, DefaultThresholds()).Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bbc1.uk", ch.TvgID)
+	require.Len(t, store.saved, 1)
+	assert.Equal(t, primary.ID, store.saved[0].EpgSourceID)
+}
+
+func TestTrigrams(t *testing.T) {
+	assert.Equal(t, []string{" bb", "bbc", "bc ", "c 1", " 1 "}, trigrams("bbc 1"))
+	assert.Equal(t, []string{" a "}, trigrams("a"))
+}
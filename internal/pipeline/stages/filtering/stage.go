@@ -438,6 +438,9 @@ func (s *Stage) loadFiltersFromProxy(ctx context.Context, proxy *models.StreamPr
 			target = FilterTargetChannel
 		case models.FilterSourceTypeEPG:
 			target = FilterTargetProgram
+		case models.FilterSourceTypeVOD:
+			// Applied to the VOD outputs by the generatevod stage
+			continue
 		default:
 			s.log(ctx, slog.LevelWarn, "unknown filter source type",
 				slog.String("filter_id", f.ID.String()),
@@ -113,6 +113,14 @@ func (s *Stage) Execute(ctx context.Context, state *core.State) (*core.StageResu
 		// The streaming endpoint decides at request time whether to redirect, proxy, or relay.
 		if s.baseURL != "" {
 			entry.URL = shared.BuildProxyStreamURL(s.baseURL, state.ProxyID, ch.ID)
+
+			// Archive URLs are only advertised through the proxy, which resolves
+			// the source template and its timezone at playback time.
+			if ch.HasCatchup() {
+				entry.Catchup = m3u.CatchupModeDefault
+				entry.CatchupDays = ch.CatchupDays
+				entry.CatchupSource = shared.BuildProxyCatchupURL(s.baseURL, state.ProxyID, ch.ID)
+			}
 		}
 
 		if err := writer.WriteEntry(entry); err != nil {
@@ -197,6 +197,36 @@ func TestStage_ContextCancellation(t *testing.T) {
 	assert.ErrorIs(t, err, context.Canceled)
 }
 
+func TestStage_Execute_CatchupAttributes(t *testing.T) {
+	state := newTestState(t)
+	archived := &models.Channel{
+		BaseModel:     models.BaseModel{ID: models.NewULID()},
+		ChannelName:   "Archived",
+		StreamURL:     "http://upstream/live/1.ts",
+		CatchupDays:   3,
+		CatchupSource: "http://upstream/timeshift.php?stream=1&start={Y}-{m}-{d}:{H}-{M}",
+	}
+	state.Channels = []*models.Channel{
+		archived,
+		{ChannelName: "Live Only", StreamURL: "http://upstream/live/2.ts"},
+	}
+
+	stage := New()
+	stage.baseURL = "http://tvarr:8080"
+	_, err := stage.Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	m3uPath, _ := state.GetMetadata(MetadataKeyTempPath)
+	content, err := os.ReadFile(m3uPath.(string))
+	require.NoError(t, err)
+
+	// The upstream archive template is never exposed, only the proxy catch-up route.
+	catchupURL := "http://tvarr:8080/proxy/" + state.ProxyID.String() + "/" + archived.ID.String() + "/catchup?start={utc}&duration={duration}"
+	assert.Contains(t, string(content), `catchup="default" catchup-days="3" catchup-source="`+catchupURL+`"`)
+	assert.NotContains(t, string(content), "timeshift.php")
+	assert.Equal(t, 1, strings.Count(string(content), "catchup-source="))
+}
+
 func TestNewConstructor(t *testing.T) {
 	constructor := NewConstructor()
 	stage := constructor(nil)
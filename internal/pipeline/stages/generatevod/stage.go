@@ -0,0 +1,422 @@
+// Package generatevod implements the VOD playlist generation pipeline stage.
+//
+// Proxies that include movies or series get separate M3U playlists for each,
+// built from the VOD catalogues of the proxy's sources. The proxy's VOD filters
+// are applied in priority order with the same include/exclude semantics as
+// channel filters; series are filtered as a whole and listed one entry per
+// episode. Entries point directly at the upstream streams, and carry tvarr-*
+// attributes with the catalogue details the Xtream output needs.
+package generatevod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jmylchreest/tvarr/internal/expression"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/core"
+	"github.com/jmylchreest/tvarr/internal/pipeline/shared"
+	"github.com/jmylchreest/tvarr/pkg/m3u"
+)
+
+const (
+	// StageID is the unique identifier for this stage.
+	StageID = "generate_vod"
+	// StageName is the human-readable name for this stage.
+	StageName = "Generate VOD"
+	// MetadataKeyMoviesTempPath is the metadata key for the movie playlist temp file path.
+	MetadataKeyMoviesTempPath = "vod_movies_temp_path"
+	// MetadataKeySeriesTempPath is the metadata key for the series playlist temp file path.
+	MetadataKeySeriesTempPath = "vod_series_temp_path"
+)
+
+// Extra M3U attributes written on VOD playlist entries. Players ignore them;
+// the Xtream output reads them back to serve the proxy's VOD catalogue.
+const (
+	// AttrID is the provider's movie or episode ID.
+	AttrID = "tvarr-id"
+	// AttrSeriesID is the provider's series ID.
+	AttrSeriesID = "tvarr-series-id"
+	// AttrCategory is the provider category of a series. Episode entries use
+	// the series name as their group title.
+	AttrCategory = "tvarr-category"
+	// AttrSeason is the season number of an episode.
+	AttrSeason = "tvarr-season"
+	// AttrEpisode is the episode number within the season.
+	AttrEpisode = "tvarr-episode"
+	// AttrEpisodeTitle is the provider's title of an episode.
+	AttrEpisodeTitle = "tvarr-episode-title"
+	// AttrContainer is the file extension of the stream (e.g. mp4, mkv).
+	AttrContainer = "tvarr-container"
+	// AttrYear is the release year.
+	AttrYear = "tvarr-year"
+	// AttrRating is the provider rating, usually out of 10.
+	AttrRating = "tvarr-rating"
+	// AttrGenre is the provider's genre list of a series.
+	AttrGenre = "tvarr-genre"
+	// AttrAdult is set to 1 on adult movies.
+	AttrAdult = "tvarr-adult"
+)
+
+// MovieStore provides the movie catalogues of stream sources.
+type MovieStore interface {
+	// GetBySourceIDs streams the movies of the given sources.
+	GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID, callback func(*models.VodItem) error) error
+}
+
+// SeriesStore provides the series catalogues of stream sources.
+type SeriesStore interface {
+	// GetBySourceIDs streams the series of the given sources with their episodes.
+	GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID, callback func(*models.Series) error) error
+}
+
+// vodFilter is a compiled VOD filter.
+type vodFilter struct {
+	action    models.FilterAction
+	parsed    *expression.ParsedExpression
+	evaluator *expression.Evaluator
+}
+
+// Stage generates the movie and series playlists of a proxy.
+type Stage struct {
+	shared.BaseStage
+	movies MovieStore
+	series SeriesStore
+	logger *slog.Logger
+}
+
+// New creates a new VOD generation stage.
+func New(movies MovieStore, series SeriesStore) *Stage {
+	return &Stage{
+		BaseStage: shared.NewBaseStage(StageID, StageName),
+		movies:    movies,
+		series:    series,
+	}
+}
+
+// NewConstructor returns a stage constructor for use with the factory.
+func NewConstructor(movies MovieStore, series SeriesStore) core.StageConstructor {
+	return func(deps *core.Dependencies) core.Stage {
+		s := New(movies, series)
+		if deps.Logger != nil {
+			s.logger = deps.Logger.With("stage", StageID)
+		}
+		return s
+	}
+}
+
+// Execute generates the movie and series playlists enabled on the proxy.
+func (s *Stage) Execute(ctx context.Context, state *core.State) (*core.StageResult, error) {
+	result := shared.NewResult()
+
+	if state.Proxy == nil || (!state.Proxy.IncludeVod && !state.Proxy.IncludeSeries) {
+		result.Message = "VOD output disabled"
+		return result, nil
+	}
+	if len(state.Sources) == 0 {
+		result.Message = "No sources for VOD output"
+		return result, nil
+	}
+
+	filters, err := s.compileFilters(ctx, state.Proxy)
+	if err != nil {
+		return result, err
+	}
+
+	sourceIDs := make([]models.ULID, len(state.Sources))
+	sources := make(map[models.ULID]*models.StreamSource, len(state.Sources))
+	for i, source := range state.Sources {
+		sourceIDs[i] = source.ID
+		sources[source.ID] = source
+	}
+
+	if state.Proxy.IncludeVod && s.movies != nil {
+		path := filepath.Join(state.TempDir, fmt.Sprintf("%s.vod.m3u", state.ProxyID))
+		count, err := s.writePlaylist(ctx, path, func(writer *m3u.Writer) (int, error) {
+			return s.writeMovies(ctx, writer, sourceIDs, sources, filters)
+		})
+		if err != nil {
+			return result, fmt.Errorf("generating movie playlist: %w", err)
+		}
+		state.SetMetadata(MetadataKeyMoviesTempPath, path)
+		result.RecordsProcessed += count
+		result.Artifacts = append(result.Artifacts,
+			core.NewArtifact(core.ArtifactTypeM3U, core.ProcessingStageGenerated, StageID).
+				WithFilePath(path).
+				WithRecordCount(count))
+		s.log(ctx, slog.LevelInfo, "movie playlist generated",
+			slog.Int("movie_count", count),
+			slog.String("output_path", path))
+	}
+
+	if state.Proxy.IncludeSeries && s.series != nil {
+		path := filepath.Join(state.TempDir, fmt.Sprintf("%s.series.m3u", state.ProxyID))
+		count, err := s.writePlaylist(ctx, path, func(writer *m3u.Writer) (int, error) {
+			return s.writeSeries(ctx, writer, sourceIDs, sources, filters)
+		})
+		if err != nil {
+			return result, fmt.Errorf("generating series playlist: %w", err)
+		}
+		state.SetMetadata(MetadataKeySeriesTempPath, path)
+		result.RecordsProcessed += count
+		result.Artifacts = append(result.Artifacts,
+			core.NewArtifact(core.ArtifactTypeM3U, core.ProcessingStageGenerated, StageID).
+				WithFilePath(path).
+				WithRecordCount(count))
+		s.log(ctx, slog.LevelInfo, "series playlist generated",
+			slog.Int("episode_count", count),
+			slog.String("output_path", path))
+	}
+
+	result.Message = fmt.Sprintf("Generated VOD playlists with %d entries", result.RecordsProcessed)
+	return result, nil
+}
+
+// writePlaylist creates an M3U file and writes its entries with write,
+// returning the number of entries.
+func (s *Stage) writePlaylist(ctx context.Context, path string, write func(*m3u.Writer) (int, error)) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		s.log(ctx, slog.LevelError, "failed to create VOD playlist",
+			slog.String("output_path", path),
+			slog.String("error", err.Error()))
+		return 0, fmt.Errorf("creating file: %w", err)
+	}
+	defer file.Close()
+
+	writer := m3u.NewWriter(file)
+	if err := writer.WriteHeader(); err != nil {
+		return 0, fmt.Errorf("writing header: %w", err)
+	}
+	return write(writer)
+}
+
+// writeMovies writes an entry for each movie that passes the filters.
+func (s *Stage) writeMovies(ctx context.Context, writer *m3u.Writer, sourceIDs []models.ULID, sources map[models.ULID]*models.StreamSource, filters []*vodFilter) (int, error) {
+	count := 0
+	err := s.movies.GetBySourceIDs(ctx, sourceIDs, func(item *models.VodItem) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		evalCtx := expression.NewVodEvalContext(map[string]string{
+			"vod_title":   item.Name,
+			"vod_type":    string(models.VodTypeMovie),
+			"vod_year":    strconv.Itoa(item.Year),
+			"vod_rating":  strconv.FormatFloat(item.Rating, 'f', -1, 64),
+			"group_title": item.GroupTitle,
+			"is_adult":    strconv.FormatBool(item.IsAdult),
+		})
+		setSourceMetadata(evalCtx, sources[item.SourceID])
+		if !passes(filters, evalCtx) {
+			return nil
+		}
+
+		if err := writer.WriteEntry(&m3u.Entry{
+			Duration:   -1,
+			TvgName:    item.Name,
+			TvgLogo:    item.Logo,
+			GroupTitle: item.GroupTitle,
+			Title:      item.Name,
+			URL:        item.StreamURL,
+			Extra: attrs(
+				AttrID, item.ExtID,
+				AttrContainer, item.ContainerExtension,
+				AttrYear, formatNonZero(item.Year),
+				AttrRating, formatRating(item.Rating),
+				AttrAdult, formatFlag(item.IsAdult),
+			),
+		}); err != nil {
+			return fmt.Errorf("writing movie %s: %w", item.Name, err)
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// writeSeries writes an entry for each episode of the series that pass the
+// filters.
+func (s *Stage) writeSeries(ctx context.Context, writer *m3u.Writer, sourceIDs []models.ULID, sources map[models.ULID]*models.StreamSource, filters []*vodFilter) (int, error) {
+	count := 0
+	err := s.series.GetBySourceIDs(ctx, sourceIDs, func(series *models.Series) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		evalCtx := expression.NewVodEvalContext(map[string]string{
+			"vod_title":   series.Name,
+			"vod_type":    string(models.VodTypeSeries),
+			"vod_genre":   series.Genre,
+			"vod_year":    strconv.Itoa(series.Year),
+			"vod_rating":  strconv.FormatFloat(series.Rating, 'f', -1, 64),
+			"group_title": series.GroupTitle,
+		})
+		setSourceMetadata(evalCtx, sources[series.SourceID])
+		if !passes(filters, evalCtx) {
+			return nil
+		}
+
+		for _, ep := range series.Episodes {
+			title := episodeTitle(series, ep)
+			if err := writer.WriteEntry(&m3u.Entry{
+				Duration:   -1,
+				TvgName:    title,
+				TvgLogo:    series.Cover,
+				GroupTitle: series.Name,
+				Title:      title,
+				URL:        ep.StreamURL,
+				Extra: attrs(
+					AttrID, ep.ExtID,
+					AttrSeriesID, series.ExtID,
+					AttrCategory, series.GroupTitle,
+					AttrSeason, strconv.Itoa(ep.Season),
+					AttrEpisode, strconv.Itoa(ep.EpisodeNum),
+					AttrEpisodeTitle, ep.Title,
+					AttrContainer, ep.ContainerExtension,
+					AttrYear, formatNonZero(series.Year),
+					AttrRating, formatRating(series.Rating),
+					AttrGenre, series.Genre,
+				),
+			}); err != nil {
+				return fmt.Errorf("writing episode %s: %w", title, err)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// compileFilters compiles the proxy's active VOD filters in priority order.
+func (s *Stage) compileFilters(ctx context.Context, proxy *models.StreamProxy) ([]*vodFilter, error) {
+	proxyFilters := make([]models.ProxyFilter, len(proxy.Filters))
+	copy(proxyFilters, proxy.Filters)
+	sort.Slice(proxyFilters, func(i, j int) bool {
+		return proxyFilters[i].Priority < proxyFilters[j].Priority
+	})
+
+	var filters []*vodFilter
+	for _, pf := range proxyFilters {
+		if pf.IsActive != nil && !*pf.IsActive {
+			continue
+		}
+		f := pf.Filter
+		if f == nil || f.SourceType != models.FilterSourceTypeVOD {
+			continue
+		}
+		if f.Action != models.FilterActionInclude && f.Action != models.FilterActionExclude {
+			s.log(ctx, slog.LevelWarn, "unknown filter action",
+				slog.String("filter_id", f.ID.String()),
+				slog.String("action", string(f.Action)))
+			continue
+		}
+
+		parsed, err := expression.PreprocessAndParse(f.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("parsing VOD filter %s: %w", f.ID, err)
+		}
+		if parsed == nil {
+			continue
+		}
+
+		evaluator := expression.NewEvaluator()
+		evaluator.SetCaseSensitive(false)
+		filters = append(filters, &vodFilter{
+			action:    f.Action,
+			parsed:    parsed,
+			evaluator: evaluator,
+		})
+	}
+	return filters, nil
+}
+
+// passes reports whether an item is included by the filters. Without filters
+// everything is included; otherwise items start excluded, include filters add
+// matching items and exclude filters remove them, in order.
+func passes(filters []*vodFilter, evalCtx expression.FieldValueAccessor) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	included := false
+	for _, f := range filters {
+		if included == (f.action == models.FilterActionInclude) {
+			continue
+		}
+		evalResult, err := f.evaluator.Evaluate(f.parsed, evalCtx)
+		if err == nil && evalResult.Matches {
+			included = !included
+		}
+	}
+	return included
+}
+
+// setSourceMetadata exposes the item's source to filter expressions.
+func setSourceMetadata(evalCtx *expression.VodEvalContext, source *models.StreamSource) {
+	if source != nil {
+		evalCtx.SetSourceMetadata(source.Name, string(source.Type), source.URL)
+	}
+}
+
+// episodeTitle returns the display title of an episode, e.g. "The Wire S01E02 -
+// The Target". Episode titles that already include the series name are not
+// appended.
+func episodeTitle(series *models.Series, ep *models.SeriesEpisode) string {
+	title := fmt.Sprintf("%s S%02dE%02d", series.Name, ep.Season, ep.EpisodeNum)
+	if ep.Title != "" && !strings.Contains(ep.Title, series.Name) {
+		title += " - " + ep.Title
+	}
+	return title
+}
+
+// attrs builds the extra attributes of an entry from key/value pairs,
+// leaving out empty values.
+func attrs(pairs ...string) map[string]string {
+	extra := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			extra[pairs[i]] = pairs[i+1]
+		}
+	}
+	return extra
+}
+
+// formatNonZero formats n, or returns "" for zero (unknown).
+func formatNonZero(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// formatRating formats a rating, or returns "" for unrated.
+func formatRating(rating float64) string {
+	if rating == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(rating, 'f', -1, 64)
+}
+
+// formatFlag returns "1" if set, or "".
+func formatFlag(set bool) string {
+	if set {
+		return "1"
+	}
+	return ""
+}
+
+// log logs a message if the logger is set.
+func (s *Stage) log(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+	if s.logger != nil {
+		s.logger.Log(ctx, level, msg, attrs...)
+	}
+}
+
+// Ensure Stage implements core.Stage.
+var _ core.Stage = (*Stage)(nil)
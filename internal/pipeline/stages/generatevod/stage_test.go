@@ -0,0 +1,142 @@
+package generatevod
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/pipeline/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMovieStore struct {
+	movies []*models.VodItem
+}
+
+func (m *mockMovieStore) GetBySourceIDs(_ context.Context, _ []models.ULID, callback func(*models.VodItem) error) error {
+	for _, item := range m.movies {
+		if err := callback(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type mockSeriesStore struct {
+	series []*models.Series
+}
+
+func (m *mockSeriesStore) GetBySourceIDs(_ context.Context, _ []models.ULID, callback func(*models.Series) error) error {
+	for _, s := range m.series {
+		if err := callback(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testSetup creates a state for a proxy with VOD output over a single source.
+func testSetup(t *testing.T, filters ...*models.Filter) (*core.State, *models.StreamSource) {
+	proxy := &models.StreamProxy{IncludeVod: true, IncludeSeries: true}
+	proxy.ID = models.NewULID()
+	for i, f := range filters {
+		f.ID = models.NewULID()
+		proxy.Filters = append(proxy.Filters, models.ProxyFilter{FilterID: f.ID, Filter: f, Priority: i})
+	}
+	source := &models.StreamSource{BaseModel: models.BaseModel{ID: models.NewULID()}, Name: "provider"}
+
+	state := core.NewState(proxy)
+	state.Sources = []*models.StreamSource{source}
+	state.TempDir = t.TempDir()
+	return state, source
+}
+
+// playlist reads a generated playlist from the state metadata.
+func playlist(t *testing.T, state *core.State, key string) string {
+	path, ok := state.GetMetadata(key)
+	require.True(t, ok, "missing metadata %s", key)
+	data, err := os.ReadFile(path.(string))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestStage_Disabled(t *testing.T) {
+	state, _ := testSetup(t)
+	state.Proxy.IncludeVod = false
+	state.Proxy.IncludeSeries = false
+
+	result, err := New(&mockMovieStore{}, &mockSeriesStore{}).Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	assert.Equal(t, "VOD output disabled", result.Message)
+	_, ok := state.GetMetadata(MetadataKeyMoviesTempPath)
+	assert.False(t, ok)
+}
+
+func TestStage_WritesPlaylists(t *testing.T) {
+	state, source := testSetup(t)
+	movies := &mockMovieStore{movies: []*models.VodItem{
+		{SourceID: source.ID, ExtID: "10", Name: "Heat", GroupTitle: "Action", Year: 1995, ContainerExtension: "mkv", StreamURL: "http://example.com/movie/u/p/10.mkv"},
+	}}
+	series := &mockSeriesStore{series: []*models.Series{{
+		SourceID:   source.ID,
+		ExtID:      "70",
+		Name:       "The Wire",
+		GroupTitle: "Drama",
+		Episodes: []*models.SeriesEpisode{
+			{ExtID: "701", Season: 1, EpisodeNum: 1, Title: "The Target", StreamURL: "http://example.com/series/u/p/701.mp4"},
+			{Season: 1, EpisodeNum: 2, Title: "The Wire - S01E02", StreamURL: "http://example.com/series/u/p/702.mp4"},
+		},
+	}}}
+
+	result, err := New(movies, series).Execute(context.Background(), state)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.RecordsProcessed)
+
+	vod := playlist(t, state, MetadataKeyMoviesTempPath)
+	assert.Contains(t, vod, `group-title="Action" tvarr-container="mkv"`)
+	assert.Contains(t, vod, ",Heat\n")
+	assert.Contains(t, vod, "http://example.com/movie/u/p/10.mkv")
+	assert.Contains(t, vod, `tvarr-container="mkv" tvarr-id="10" tvarr-year="1995"`)
+	assert.NotContains(t, vod, AttrRating, "unknown values are left out")
+
+	episodes := playlist(t, state, MetadataKeySeriesTempPath)
+	assert.Contains(t, episodes, ",The Wire S01E01 - The Target\n")
+	assert.Contains(t, episodes, ",The Wire S01E02\n")
+	assert.Contains(t, episodes, "http://example.com/series/u/p/702.mp4")
+	assert.Contains(t, episodes, `tvarr-category="Drama" tvarr-episode="1" tvarr-episode-title="The Target" tvarr-id="701" tvarr-season="1" tvarr-series-id="70"`)
+}
+
+func TestStage_Filters(t *testing.T) {
+	state, source := testSetup(t,
+		&models.Filter{SourceType: models.FilterSourceTypeVOD, Action: models.FilterActionInclude, Expression: `vod_year >= 2000`},
+		&models.Filter{SourceType: models.FilterSourceTypeVOD, Action: models.FilterActionExclude, Expression: `group_title equals "Kids"`},
+		&models.Filter{SourceType: models.FilterSourceTypeStream, Action: models.FilterActionExclude, Expression: `channel_name contains "Heat"`},
+	)
+	movies := &mockMovieStore{movies: []*models.VodItem{
+		{SourceID: source.ID, Name: "Heat", Year: 1995, StreamURL: "http://example.com/1.mp4"},
+		{SourceID: source.ID, Name: "Inception", Year: 2010, StreamURL: "http://example.com/2.mp4"},
+		{SourceID: source.ID, Name: "Frozen", Year: 2013, GroupTitle: "Kids", StreamURL: "http://example.com/3.mp4"},
+	}}
+	series := &mockSeriesStore{series: []*models.Series{
+		{SourceID: source.ID, Name: "Lost", Year: 2004, Episodes: []*models.SeriesEpisode{
+			{Season: 1, EpisodeNum: 1, StreamURL: "http://example.com/4.mp4"},
+		}},
+	}}
+
+	_, err := New(movies, series).Execute(context.Background(), state)
+	require.NoError(t, err)
+
+	vod := playlist(t, state, MetadataKeyMoviesTempPath)
+	assert.Contains(t, vod, "Inception")
+	assert.NotContains(t, vod, "Heat")
+	assert.NotContains(t, vod, "Frozen")
+
+	assert.Contains(t, playlist(t, state, MetadataKeySeriesTempPath), "Lost S01E01")
+}
+
+func TestPasses_NoFilters(t *testing.T) {
+	assert.True(t, passes(nil, nil))
+}
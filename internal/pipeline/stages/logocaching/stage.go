@@ -10,6 +10,7 @@ import (
 	"sync/atomic"
 
 	"github.com/jmylchreest/tvarr/internal/expression/helpers"
+	"github.com/jmylchreest/tvarr/internal/models"
 	"github.com/jmylchreest/tvarr/internal/pipeline/core"
 	"github.com/jmylchreest/tvarr/internal/pipeline/shared"
 	"github.com/jmylchreest/tvarr/internal/storage"
@@ -40,9 +41,18 @@ type ConcurrentLogoCacher interface {
 	Concurrency() int
 }
 
+// ProxyLogoCacher extends LogoCacher with downloads through a source's proxy.
+type ProxyLogoCacher interface {
+	LogoCacher
+	// CacheLogoWithProxy is CacheLogo, downloading through proxyURL when it is
+	// non-empty.
+	CacheLogoWithProxy(ctx context.Context, logoURL, proxyURL string) (*storage.CachedLogoMetadata, error)
+}
+
 // logoJob represents a single logo download job for the worker pool.
 type logoJob struct {
-	url string
+	url      string
+	proxyURL string // Proxy of the source the logo came from (empty = direct)
 }
 
 // logoResult represents the result of a logo download job.
@@ -244,8 +254,13 @@ func (s *Stage) Execute(ctx context.Context, state *core.State) (*core.StageResu
 
 // cacheChannelLogos processes and caches logos from channel TvgLogo fields.
 func (s *Stage) cacheChannelLogos(ctx context.Context, state *core.State, batchSize int, processedItems *int, totalItems int) error {
-	// Collect unique channel logo URLs
-	logoURLs := make(map[string]struct{})
+	proxies := make(map[models.ULID]string, len(state.Sources))
+	for _, source := range state.Sources {
+		proxies[source.ID] = source.ProxyURL
+	}
+
+	// Collect unique channel logo URLs with the proxy of their source
+	logoURLs := make(map[string]string)
 	for _, ch := range state.Channels {
 		select {
 		case <-ctx.Done():
@@ -256,7 +271,9 @@ func (s *Stage) cacheChannelLogos(ctx context.Context, state *core.State, batchS
 		s.stats.ChannelsProcessed++
 		if ch.TvgLogo != "" {
 			s.stats.ChannelsWithLogos++
-			logoURLs[ch.TvgLogo] = struct{}{}
+			if _, ok := logoURLs[ch.TvgLogo]; !ok {
+				logoURLs[ch.TvgLogo] = proxies[ch.SourceID]
+			}
 		}
 
 		// Report progress for scanning phase
@@ -285,8 +302,8 @@ func (s *Stage) cacheChannelLogos(ctx context.Context, state *core.State, batchS
 	}
 
 	// Filter URLs that need fetching vs those we can skip
-	urlsToFetch := make([]string, 0, len(logoURLs))
-	for logoURL := range logoURLs {
+	urlsToFetch := make([]logoJob, 0, len(logoURLs))
+	for logoURL, proxyURL := range logoURLs {
 		// Skip unfetchable logo URLs (relative paths without scheme, @logo: refs)
 		if isUnfetchableLogoURL(logoURL) {
 			s.stats.ChannelLogosLocalSkip++
@@ -314,7 +331,7 @@ func (s *Stage) cacheChannelLogos(ctx context.Context, state *core.State, batchS
 			continue
 		}
 
-		urlsToFetch = append(urlsToFetch, logoURL)
+		urlsToFetch = append(urlsToFetch, logoJob{url: logoURL, proxyURL: proxyURL})
 	}
 
 	// If nothing to fetch, we're done
@@ -348,7 +365,7 @@ func (s *Stage) cacheChannelLogos(ctx context.Context, state *core.State, batchS
 				}
 
 				// Cache the logo
-				meta, err := s.cacher.CacheLogo(ctx, job.url)
+				meta, err := s.cacheLogo(ctx, job)
 				results <- logoResult{
 					url:  job.url,
 					meta: meta,
@@ -360,9 +377,9 @@ func (s *Stage) cacheChannelLogos(ctx context.Context, state *core.State, batchS
 
 	// Send jobs to workers
 	go func() {
-		for _, url := range urlsToFetch {
+		for _, job := range urlsToFetch {
 			select {
-			case jobs <- logoJob{url: url}:
+			case jobs <- job:
 			case <-ctx.Done():
 				close(jobs)
 				return
@@ -413,8 +430,13 @@ func (s *Stage) cacheChannelLogos(ctx context.Context, state *core.State, batchS
 
 // cacheProgramLogos processes and caches logos from program Icon fields.
 func (s *Stage) cacheProgramLogos(ctx context.Context, state *core.State, batchSize int, processedItems *int, totalItems int) error {
-	// Collect unique program logo URLs
-	logoURLs := make(map[string]struct{})
+	proxies := make(map[models.ULID]string, len(state.EpgSources))
+	for _, source := range state.EpgSources {
+		proxies[source.ID] = source.ProxyURL
+	}
+
+	// Collect unique program logo URLs with the proxy of their source
+	logoURLs := make(map[string]string)
 	for _, prog := range state.Programs {
 		select {
 		case <-ctx.Done():
@@ -425,7 +447,9 @@ func (s *Stage) cacheProgramLogos(ctx context.Context, state *core.State, batchS
 		s.stats.ProgramsProcessed++
 		if prog.Icon != "" {
 			s.stats.ProgramsWithLogos++
-			logoURLs[prog.Icon] = struct{}{}
+			if _, ok := logoURLs[prog.Icon]; !ok {
+				logoURLs[prog.Icon] = proxies[prog.SourceID]
+			}
 		}
 
 		// Report progress for scanning phase
@@ -454,8 +478,8 @@ func (s *Stage) cacheProgramLogos(ctx context.Context, state *core.State, batchS
 	}
 
 	// Filter URLs that need fetching vs those we can skip
-	urlsToFetch := make([]string, 0, len(logoURLs))
-	for logoURL := range logoURLs {
+	urlsToFetch := make([]logoJob, 0, len(logoURLs))
+	for logoURL, proxyURL := range logoURLs {
 		// Skip unfetchable logo URLs (relative paths, deferred references, @logo: refs)
 		if isUnfetchableLogoURL(logoURL) {
 			s.stats.ProgramLogosLocalSkip++
@@ -483,7 +507,7 @@ func (s *Stage) cacheProgramLogos(ctx context.Context, state *core.State, batchS
 			continue
 		}
 
-		urlsToFetch = append(urlsToFetch, logoURL)
+		urlsToFetch = append(urlsToFetch, logoJob{url: logoURL, proxyURL: proxyURL})
 	}
 
 	// If nothing to fetch, we're done
@@ -517,7 +541,7 @@ func (s *Stage) cacheProgramLogos(ctx context.Context, state *core.State, batchS
 				}
 
 				// Cache the logo
-				meta, err := s.cacher.CacheLogo(ctx, job.url)
+				meta, err := s.cacheLogo(ctx, job)
 				results <- logoResult{
 					url:  job.url,
 					meta: meta,
@@ -529,9 +553,9 @@ func (s *Stage) cacheProgramLogos(ctx context.Context, state *core.State, batchS
 
 	// Send jobs to workers
 	go func() {
-		for _, url := range urlsToFetch {
+		for _, job := range urlsToFetch {
 			select {
-			case jobs <- logoJob{url: url}:
+			case jobs <- job:
 			case <-ctx.Done():
 				close(jobs)
 				return
@@ -637,6 +661,15 @@ func isUnfetchableLogoURL(url string) bool {
 	return !urlutil.IsRemoteURL(url)
 }
 
+// cacheLogo caches a single logo, through its source's proxy when the cacher
+// supports it.
+func (s *Stage) cacheLogo(ctx context.Context, job logoJob) (*storage.CachedLogoMetadata, error) {
+	if pc, ok := s.cacher.(ProxyLogoCacher); ok && job.proxyURL != "" {
+		return pc.CacheLogoWithProxy(ctx, job.url, job.proxyURL)
+	}
+	return s.cacher.CacheLogo(ctx, job.url)
+}
+
 // isLocalLogoURL checks if a URL points to a logo served by tvarr itself.
 // These URLs contain /api/v1/logos/ and should not be cached since they're already local.
 func (s *Stage) isLocalLogoURL(url string) bool {
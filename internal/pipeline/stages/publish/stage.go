@@ -12,6 +12,7 @@ import (
 	"github.com/jmylchreest/tvarr/internal/pipeline/core"
 	"github.com/jmylchreest/tvarr/internal/pipeline/shared"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/generatem3u"
+	"github.com/jmylchreest/tvarr/internal/pipeline/stages/generatevod"
 	"github.com/jmylchreest/tvarr/internal/pipeline/stages/generatexmltv"
 	"github.com/jmylchreest/tvarr/internal/storage"
 )
@@ -106,6 +107,38 @@ func (s *Stage) Execute(ctx context.Context, state *core.State) (*core.StageResu
 		result.Artifacts = append(result.Artifacts, artifact)
 	}
 
+	// Publish VOD playlists if generated, removing those no longer enabled
+	vodFiles := []struct {
+		key      string
+		destName string
+	}{
+		{generatevod.MetadataKeyMoviesTempPath, fmt.Sprintf("%s.vod.m3u", state.ProxyID)},
+		{generatevod.MetadataKeySeriesTempPath, fmt.Sprintf("%s.series.m3u", state.ProxyID)},
+	}
+	for _, vf := range vodFiles {
+		vodPath, ok := state.GetMetadata(vf.key)
+		if !ok {
+			if err := os.Remove(filepath.Join(state.OutputDir, vf.destName)); err != nil && !os.IsNotExist(err) {
+				s.log(slog.LevelWarn, "failed to remove stale VOD playlist",
+					slog.String("dest_name", vf.destName),
+					slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if err := s.publishFile(ctx, vodPath.(string), state.OutputDir, vf.destName); err != nil {
+			s.log(slog.LevelError, "failed to publish VOD playlist",
+				slog.String("src_path", vodPath.(string)),
+				slog.String("dest_name", vf.destName),
+				slog.String("error", err.Error()))
+			return result, fmt.Errorf("publishing VOD playlist: %w", err)
+		}
+		filesPublished++
+
+		artifact := core.NewArtifact(core.ArtifactTypeM3U, core.ProcessingStagePublished, StageID).
+			WithFilePath(filepath.Join(state.OutputDir, vf.destName))
+		result.Artifacts = append(result.Artifacts, artifact)
+	}
+
 	result.RecordsProcessed = filesPublished
 	result.Message = fmt.Sprintf("Published %d files to %s", filesPublished, state.OutputDir)
 
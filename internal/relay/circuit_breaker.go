@@ -219,9 +219,10 @@ type CircuitStats struct {
 
 // CircuitBreakerRegistry manages circuit breakers for multiple endpoints.
 type CircuitBreakerRegistry struct {
-	config CircuitBreakerConfig
-	mu     sync.RWMutex
-	cbs    map[string]*CircuitBreaker
+	config        CircuitBreakerConfig
+	mu            sync.RWMutex
+	cbs           map[string]*CircuitBreaker
+	onStateChange func(key string, from, to CircuitState)
 }
 
 // NewCircuitBreakerRegistry creates a new registry.
@@ -250,11 +251,29 @@ func (r *CircuitBreakerRegistry) Get(key string) *CircuitBreaker {
 		return cb
 	}
 
-	cb = NewCircuitBreaker(r.config)
+	config := r.config
+	if onStateChange := r.onStateChange; onStateChange != nil {
+		configured := config.OnStateChange
+		config.OnStateChange = func(from, to CircuitState) {
+			if configured != nil {
+				configured(from, to)
+			}
+			onStateChange(key, from, to)
+		}
+	}
+	cb = NewCircuitBreaker(config)
 	r.cbs[key] = cb
 	return cb
 }
 
+// OnStateChange sets a function called, with the breaker's key, when any
+// circuit breaker created afterwards changes state.
+func (r *CircuitBreakerRegistry) OnStateChange(fn func(key string, from, to CircuitState)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onStateChange = fn
+}
+
 // Remove removes a circuit breaker from the registry.
 func (r *CircuitBreakerRegistry) Remove(key string) {
 	r.mu.Lock()
@@ -11,10 +11,16 @@ import (
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/types"
 )
 
+// DaemonStateChangeFunc is called when a daemon misses its heartbeats or recovers.
+type DaemonStateChangeFunc func(id types.DaemonID, name string, state types.DaemonState)
+
 // DaemonRegistry manages registered ffmpegd daemons.
 type DaemonRegistry struct {
 	logger *slog.Logger
 
+	// onStateChange is called without the lock held, from its own goroutine
+	onStateChange DaemonStateChangeFunc
+
 	mu      sync.RWMutex
 	daemons map[types.DaemonID]*types.Daemon
 
@@ -54,6 +60,21 @@ func (r *DaemonRegistry) WithCleanupInterval(interval time.Duration) *DaemonRegi
 	return r
 }
 
+// WithStateChangeHook sets a function called when a daemon becomes unhealthy
+// after missing heartbeats, or recovers. Daemons that unregister are not reported.
+func (r *DaemonRegistry) WithStateChangeHook(fn DaemonStateChangeFunc) *DaemonRegistry {
+	r.onStateChange = fn
+	return r
+}
+
+// notifyStateChange reports a daemon state change to the hook, if any.
+// Must be called with r.mu held.
+func (r *DaemonRegistry) notifyStateChange(daemon *types.Daemon) {
+	if r.onStateChange != nil {
+		go r.onStateChange(daemon.ID, daemon.Name, daemon.State)
+	}
+}
+
 // Start starts the daemon registry cleanup goroutine.
 func (r *DaemonRegistry) Start(ctx context.Context) {
 	cleanupCtx, cancel := context.WithCancel(ctx)
@@ -79,6 +100,8 @@ func (r *DaemonRegistry) Register(req *proto.RegisterRequest) (*types.Daemon, er
 
 	// Check if daemon already exists
 	if existing, ok := r.daemons[daemonID]; ok {
+		wasUnhealthy := existing.State == types.DaemonStateUnhealthy
+
 		// Update existing daemon
 		existing.Name = req.DaemonName
 		existing.Version = req.Version
@@ -91,6 +114,9 @@ func (r *DaemonRegistry) Register(req *proto.RegisterRequest) (*types.Daemon, er
 			slog.String("daemon_id", string(daemonID)),
 			slog.String("name", req.DaemonName),
 		)
+		if wasUnhealthy {
+			r.notifyStateChange(existing)
+		}
 
 		return existing, nil
 	}
@@ -140,6 +166,7 @@ func (r *DaemonRegistry) HandleHeartbeat(req *proto.HeartbeatRequest) (*types.Da
 		r.logger.Info("daemon recovered",
 			slog.String("daemon_id", string(daemonID)),
 		)
+		r.notifyStateChange(daemon)
 	}
 
 	// Update system stats
@@ -410,6 +437,7 @@ func (r *DaemonRegistry) checkHeartbeats() {
 					slog.String("name", daemon.Name),
 					slog.Duration("since_heartbeat", timeSinceHeartbeat),
 				)
+				r.notifyStateChange(daemon)
 			}
 		}
 	}
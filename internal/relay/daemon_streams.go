@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/proto"
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/types"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
 )
 
@@ -366,7 +369,7 @@ func (s *DaemonStream) Close() {
 
 // Probe sends a probe request to the daemon and waits for the response.
 // Uses the stream URL as the request ID for matching responses.
-func (s *DaemonStream) Probe(ctx context.Context, streamURL string, timeoutMs int32) (*proto.ProbeResponse, error) {
+func (s *DaemonStream) Probe(ctx context.Context, streamURL string, opts UpstreamOptions, timeoutMs int32) (*proto.ProbeResponse, error) {
 	s.mu.Lock()
 	if s.closed {
 		s.mu.Unlock()
@@ -389,11 +392,16 @@ func (s *DaemonStream) Probe(ctx context.Context, streamURL string, timeoutMs in
 	}()
 
 	// Send probe request
+	var headers strings.Builder
+	_ = opts.RequestHeaders().Write(&headers)
 	err := s.Stream.Send(&proto.TranscodeMessage{
 		Payload: &proto.TranscodeMessage_ProbeRequest{
 			ProbeRequest: &proto.ProbeRequest{
-				StreamUrl: streamURL,
-				TimeoutMs: timeoutMs,
+				StreamUrl:    streamURL,
+				TimeoutMs:    timeoutMs,
+				ProxyUrl:     opts.ProxyURL,
+				Headers:      headers.String(),
+				TraceContext: observability.InjectTraceContext(ctx),
 			},
 		},
 	})
@@ -710,8 +718,9 @@ func (m *DaemonStreamManager) WaitForStream(ctx context.Context, daemonID types.
 
 // Probe sends a probe request to a daemon stream selected via strategy.
 // Uses the configured probe strategy (defaults to LeastLoaded) to select
-// the most appropriate daemon for probing.
-func (m *DaemonStreamManager) Probe(ctx context.Context, streamURL string, timeoutMs int32) (*proto.ProbeResponse, error) {
+// the most appropriate daemon for probing. The daemon fetches the stream
+// through the proxy and with the request headers of opts.
+func (m *DaemonStreamManager) Probe(ctx context.Context, streamURL string, opts UpstreamOptions, timeoutMs int32) (*proto.ProbeResponse, error) {
 	var selectedDaemonID types.DaemonID
 	var stream *DaemonStream
 
@@ -747,7 +756,11 @@ func (m *DaemonStreamManager) Probe(ctx context.Context, streamURL string, timeo
 		return nil, errors.New("no daemon streams available for probing")
 	}
 
-	return stream.Probe(ctx, streamURL, timeoutMs)
+	ctx, span := observability.StartSpan(ctx, "relay.ffmpegd.probe",
+		attribute.String("tvarr.daemon.id", string(stream.DaemonID)))
+	resp, err := stream.Probe(ctx, streamURL, opts, timeoutMs)
+	observability.EndSpan(span, err)
+	return resp, err
 }
 
 // Count returns the number of active streams.
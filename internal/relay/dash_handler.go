@@ -4,11 +4,15 @@ package relay
 import (
 	"context"
 	"fmt"
+	"html"
 	"log/slog"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/jmylchreest/tvarr/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // DASHHandler handles DASH output.
@@ -111,7 +115,11 @@ func (d *DASHHandler) ServePlaylistWithContext(ctx context.Context, w http.Respo
 			waitCtx, cancel := context.WithTimeout(ctx, SegmentWaitTimeout)
 			defer cancel()
 
-			if err := waiter.WaitForSegments(waitCtx, minSegmentsForDASH); err != nil {
+			_, span := observability.StartSpan(waitCtx, "relay.segments.wait",
+				attribute.Int("tvarr.segments.min", minSegmentsForDASH))
+			err := waiter.WaitForSegments(waitCtx, minSegmentsForDASH)
+			observability.EndSpan(span, err)
+			if err != nil {
 				http.Error(w, "No segments available yet, please retry", http.StatusServiceUnavailable)
 				return fmt.Errorf("waiting for segments: %w", err)
 			}
@@ -397,6 +405,8 @@ func (d *DASHHandler) GenerateManifest(baseURL string) string {
 
 	// Ensure baseURL doesn't have trailing slash
 	baseURL = strings.TrimSuffix(baseURL, "/")
+	// The prefix is escaped because it is written into XML attributes.
+	urlPrefix := html.EscapeString(segmentURLPrefix(baseURL))
 
 	// Calculate timing
 	var availabilityStartTime time.Time
@@ -434,6 +444,10 @@ func (d *DASHHandler) GenerateManifest(baseURL string) string {
 	timeShiftBuffer := max(segmentCount*targetDuration,
 		// Minimum 3 segments worth
 		targetDuration*3)
+	// With timeshift, clients can seek back through the whole DVR window
+	if tsProvider, ok := d.provider.(TimeshiftProvider); ok && tsProvider.TimeshiftWindow() > 0 {
+		timeShiftBuffer = int(tsProvider.TimeshiftWindow().Seconds())
+	}
 
 	sb.WriteString(fmt.Sprintf(`<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" `+
 		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" `+
@@ -504,20 +518,20 @@ func (d *DASHHandler) GenerateManifest(baseURL string) string {
 		// Video SegmentTemplate - use track=video for video-only init and segments
 		if hasVideoInit {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`initialization="%s?%s=%s&amp;%s=1&amp;track=video" `+
-				`media="%s?%s=%s&amp;%s=$Number$&amp;track=video" `+
+				`initialization="%s%s=%s&amp;%s=1&amp;track=video" `+
+				`media="%s%s=%s&amp;%s=$Number$&amp;track=video" `+
 				`timescale="90000" `+
 				`startNumber="%d">`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamInit,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamInit,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				firstSegment,
 			))
 		} else {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`media="%s?%s=%s&amp;%s=$Number$" `+
+				`media="%s%s=%s&amp;%s=$Number$" `+
 				`timescale="90000" `+
 				`startNumber="%d">`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				firstSegment,
 			))
 		}
@@ -546,20 +560,20 @@ func (d *DASHHandler) GenerateManifest(baseURL string) string {
 		// Audio SegmentTemplate - use track=audio for audio-only init and segments
 		if hasAudioInit {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`initialization="%s?%s=%s&amp;%s=1&amp;track=audio" `+
-				`media="%s?%s=%s&amp;%s=$Number$&amp;track=audio" `+
+				`initialization="%s%s=%s&amp;%s=1&amp;track=audio" `+
+				`media="%s%s=%s&amp;%s=$Number$&amp;track=audio" `+
 				`timescale="90000" `+
 				`startNumber="%d">`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamInit,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamInit,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				firstSegment,
 			))
 		} else {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`media="%s?%s=%s&amp;%s=$Number$" `+
+				`media="%s%s=%s&amp;%s=$Number$" `+
 				`timescale="90000" `+
 				`startNumber="%d">`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				firstSegment,
 			))
 		}
@@ -586,23 +600,23 @@ func (d *DASHHandler) GenerateManifest(baseURL string) string {
 		// Video SegmentTemplate
 		if hasVideoInit {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`initialization="%s?%s=%s&amp;%s=v" `+
-				`media="%s?%s=%s&amp;%s=$Number$" `+
+				`initialization="%s%s=%s&amp;%s=v" `+
+				`media="%s%s=%s&amp;%s=$Number$" `+
 				`timescale="1" `+
 				`duration="%d" `+
 				`startNumber="%d"/>`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamInit,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamInit,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				targetDuration,
 				firstSegment,
 			))
 		} else {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`media="%s?%s=%s&amp;%s=$Number$" `+
+				`media="%s%s=%s&amp;%s=$Number$" `+
 				`timescale="1" `+
 				`duration="%d" `+
 				`startNumber="%d"/>`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				targetDuration,
 				firstSegment,
 			))
@@ -630,23 +644,23 @@ func (d *DASHHandler) GenerateManifest(baseURL string) string {
 		// Audio SegmentTemplate
 		if hasAudioInit {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`initialization="%s?%s=%s&amp;%s=a" `+
-				`media="%s?%s=%s&amp;%s=$Number$" `+
+				`initialization="%s%s=%s&amp;%s=a" `+
+				`media="%s%s=%s&amp;%s=$Number$" `+
 				`timescale="1" `+
 				`duration="%d" `+
 				`startNumber="%d"/>`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamInit,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamInit,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				targetDuration,
 				firstSegment,
 			))
 		} else {
 			sb.WriteString(fmt.Sprintf(`      <SegmentTemplate `+
-				`media="%s?%s=%s&amp;%s=$Number$" `+
+				`media="%s%s=%s&amp;%s=$Number$" `+
 				`timescale="1" `+
 				`duration="%d" `+
 				`startNumber="%d"/>`,
-				baseURL, QueryParamFormat, FormatValueDASH, QueryParamSegment,
+				urlPrefix, QueryParamFormat, FormatValueDASH, QueryParamSegment,
 				targetDuration,
 				firstSegment,
 			))
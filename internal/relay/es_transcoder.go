@@ -287,7 +287,9 @@ func (t *ESTranscoder) Start(ctx context.Context) error {
 // startLocal spawns a local ffmpegd subprocess and starts a transcode job.
 func (t *ESTranscoder) startLocal(sourceKey CodecVariant) (*DaemonStream, error) {
 	// Spawn ffmpegd subprocess
+	_, spawnSpan := observability.StartSpan(t.ctx, "relay.ffmpegd.spawn")
 	daemonID, cleanup, err := t.spawner.SpawnForJob(t.ctx, t.id)
+	observability.EndSpan(spawnSpan, err)
 	if err != nil {
 		return nil, fmt.Errorf("spawning ffmpegd subprocess: %w", err)
 	}
@@ -298,7 +300,9 @@ func (t *ESTranscoder) startLocal(sourceKey CodecVariant) (*DaemonStream, error)
 	streamCtx, streamCancel := context.WithTimeout(t.ctx, 30*time.Second)
 	defer streamCancel()
 
+	_, streamSpan := observability.StartSpan(streamCtx, "relay.ffmpegd.stream_wait")
 	stream, err := t.streamMgr.WaitForStream(streamCtx, daemonID)
+	observability.EndSpan(streamSpan, err)
 	if err != nil {
 		t.cleanup()
 		return nil, fmt.Errorf("waiting for daemon stream: %w", err)
@@ -363,6 +367,7 @@ func (t *ESTranscoder) startLocal(sourceKey CodecVariant) (*DaemonStream, error)
 		OutputFlags:           t.config.OutputFlags,
 		EncoderOverrides:      t.config.EncoderOverrides,
 		OutputContainerFormat: t.config.OutputFormat,
+		TraceContext:          observability.InjectTraceContext(t.ctx),
 	}
 
 	// Log encoder overrides being sent to daemon
@@ -488,6 +493,7 @@ func (t *ESTranscoder) startRemote(sourceKey CodecVariant) (*DaemonStream, error
 				OutputFlags:           t.config.OutputFlags,
 				EncoderOverrides:      t.config.EncoderOverrides,
 				OutputContainerFormat: t.config.OutputFormat,
+				TraceContext:          observability.InjectTraceContext(t.ctx),
 			},
 		},
 	}
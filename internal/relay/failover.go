@@ -0,0 +1,285 @@
+// Package relay provides streaming relay functionality for tvarr.
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// UpstreamOptions are the source settings applied to every request for an
+// upstream stream: classification, probing, ingest and HLS collapsing.
+type UpstreamOptions struct {
+	Source    string      // Name of the stream source, for logs
+	UserAgent string      // Empty = tvarr default
+	ProxyURL  string      // Empty = direct connection
+	Headers   http.Header // Extra request headers from the playlist and source
+}
+
+// RequestHeaders returns the headers to send upstream. The source User-Agent
+// takes precedence over one in Headers. Returns nil if there are none.
+func (o UpstreamOptions) RequestHeaders() http.Header {
+	headers := o.Headers.Clone()
+	if o.UserAgent != "" {
+		if headers == nil {
+			headers = make(http.Header)
+		}
+		headers.Set("User-Agent", o.UserAgent)
+	}
+	return headers
+}
+
+// Upstream is one source a relay session can ingest a channel from.
+// A session's primary upstream is the channel it was started for; alternates
+// are the same channel in other stream sources, in failover order.
+type Upstream struct {
+	UpstreamOptions
+	SourceID             models.ULID
+	SourceName           string
+	StreamURL            string
+	MaxConcurrentStreams int // 0 = unlimited
+
+	// Accounts pools several accounts of the source; a session connects with
+	// the first that has a free connection. Empty for single-account sources.
+	Accounts []UpstreamAccount
+	// Account is the name of the account in use, empty for single-account sources.
+	Account string
+
+	// LimitPolicy decides what a new session does when the source is at its
+	// connection limit; empty leaves the limit to the provider. Only the
+	// primary's policy applies.
+	LimitPolicy models.ConnectionLimitPolicy
+	// Priority is the priority of the proxy the session is started for, ranking
+	// sessions for the preempt_lowest_priority policy.
+	Priority int
+}
+
+// UpstreamAccount is one account of a source with pooled accounts.
+type UpstreamAccount struct {
+	Name                 string // Shown in session stats, e.g. the username
+	StreamURL            string // The channel's stream URL with this account's credentials
+	MaxConcurrentStreams int    // 0 = unlimited
+}
+
+// sessionUpstream is an upstream with the classification it is ingested with.
+type sessionUpstream struct {
+	Upstream
+	classification ClassificationResult
+}
+
+// inputURL returns the URL the ingest loop reads from.
+func (u *sessionUpstream) inputURL() string {
+	if u.classification.SelectedMediaPlaylist != "" {
+		return u.classification.SelectedMediaPlaylist
+	}
+	return u.StreamURL
+}
+
+// ActiveUpstream returns the upstream the session is currently ingesting from.
+func (s *RelaySession) ActiveUpstream() Upstream {
+	return s.activeSessionUpstream().Upstream
+}
+
+// FailoverCount returns how many times the session has switched upstream.
+func (s *RelaySession) FailoverCount() int {
+	s.upstreamMu.RLock()
+	defer s.upstreamMu.RUnlock()
+	return s.failovers
+}
+
+// activeSessionUpstream returns a copy of the active upstream with its classification.
+func (s *RelaySession) activeSessionUpstream() *sessionUpstream {
+	s.upstreamMu.RLock()
+	defer s.upstreamMu.RUnlock()
+	if len(s.upstreams) == 0 {
+		return &sessionUpstream{Upstream: s.primaryUpstream(), classification: s.Classification}
+	}
+	up := s.upstreams[s.activeUpstream]
+	return &up
+}
+
+// primaryUpstream returns the upstream the session was created for.
+func (s *RelaySession) primaryUpstream() Upstream {
+	return Upstream{
+		UpstreamOptions:      s.SourceOptions,
+		SourceID:             s.SourceID,
+		SourceName:           s.StreamSourceName,
+		StreamURL:            s.StreamURL,
+		MaxConcurrentStreams: s.SourceMaxConcurrentStreams,
+	}
+}
+
+// ingestWithFailover runs ingest and, when it fails with alternates left, switches
+// to the next usable upstream and keeps feeding the same ES buffer. Processors and
+// clients stay attached; the switch shows up as a discontinuity. Returns the last
+// error once no alternate is usable, so the caller falls back to the slate.
+func (s *RelaySession) ingestWithFailover(ingest func() error) error {
+	for {
+		err := ingest()
+		if s.Preempted() && s.ctx.Err() == nil {
+			return s.showPreemptionSlate()
+		}
+		if err == nil || s.ctx.Err() != nil || errors.Is(err, context.Canceled) {
+			return err
+		}
+
+		next := s.failover(err)
+		if next == nil {
+			return err
+		}
+
+		demuxer := s.resetDemuxer()
+		ingest = func() error { return s.ingestFrom(next, demuxer) }
+	}
+}
+
+// failover records the failure of the active upstream and switches to the next
+// alternate whose circuit breaker allows a connection and whose source has a free
+// stream. Alternates are tried once, in order. Returns nil if none is usable.
+func (s *RelaySession) failover(cause error) *sessionUpstream {
+	s.upstreamMu.RLock()
+	if len(s.upstreams) == 0 {
+		s.upstreamMu.RUnlock()
+		return nil
+	}
+	failed := s.upstreams[s.activeUpstream]
+	start := s.activeUpstream + 1
+	candidates := append([]sessionUpstream(nil), s.upstreams[start:]...)
+	s.upstreamMu.RUnlock()
+
+	s.manager.circuitBreakers.Get(failed.StreamURL).RecordFailure()
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		selected, free, release := s.manager.reserveAccount(candidate.Upstream)
+		if !free || !s.manager.upstreamUsable(selected) {
+			release()
+			continue
+		}
+		candidate.Upstream = selected
+		candidate.classification = s.manager.classify(s.ctx, candidate.Upstream)
+
+		s.upstreamMu.Lock()
+		s.upstreams[start+i] = *candidate
+		s.activeUpstream = start + i
+		s.failovers++
+		s.upstreamMu.Unlock()
+		// The session now counts against the account it switched to
+		release()
+
+		if s.esBuffer != nil {
+			s.esBuffer.MarkDiscontinuity()
+		}
+
+		slog.Warn("Relay upstream failed, switching to alternate source",
+			slog.String("session_id", s.ID.String()),
+			slog.String("channel", s.ChannelName),
+			slog.String("failed_source", failed.SourceName),
+			slog.String("next_source", candidate.SourceName),
+			slog.String("error", cause.Error()))
+		return candidate
+	}
+
+	// Nothing left to switch to
+	return nil
+}
+
+// resumePrimaryUpstream makes the primary the active upstream again after the
+// session recovered from fallback, reclassifying it as the session may have
+// started on an alternate.
+func (s *RelaySession) resumePrimaryUpstream() {
+	classification := s.manager.classify(s.ctx, s.primaryUpstream())
+
+	s.upstreamMu.Lock()
+	defer s.upstreamMu.Unlock()
+	if len(s.upstreams) == 0 {
+		return
+	}
+	s.upstreams[0].classification = classification
+	s.activeUpstream = 0
+}
+
+// resetDemuxer closes the session's TS demuxer and replaces it with a new one
+// feeding the same ES buffer, as the next upstream starts a new transport stream.
+func (s *RelaySession) resetDemuxer() *TSDemuxer {
+	demuxerConfig := TSDemuxerConfig{
+		Logger: slog.Default(),
+	}
+	if s.CachedCodecInfo != nil && s.CachedCodecInfo.AudioCodec != "" {
+		demuxerConfig.ProbeOverrideAudioCodec = s.CachedCodecInfo.AudioCodec
+	}
+	demuxer := NewTSDemuxer(s.esBuffer, demuxerConfig)
+
+	s.upstreamMu.Lock()
+	previous := s.tsDemuxer
+	s.tsDemuxer = demuxer
+	s.upstreamMu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return demuxer
+}
+
+// ingestFrom ingests an upstream into demuxer, collapsing HLS if it needs it.
+func (s *RelaySession) ingestFrom(up *sessionUpstream, demuxer *TSDemuxer) error {
+	if up.classification.Mode != StreamModeCollapsedHLS {
+		return s.runIngestLoop(up.inputURL(), demuxer)
+	}
+
+	collapser := NewHLSCollapser(s.manager.UpstreamClient(up.UpstreamOptions), up.inputURL())
+	if err := s.startHLSCollapser(collapser); err != nil {
+		return fmt.Errorf("starting HLS collapser: %w", err)
+	}
+	defer collapser.Stop()
+	return s.readCollapser(collapser, demuxer)
+}
+
+// readCollapser feeds a running HLS collapser's MPEG-TS output into demuxer.
+func (s *RelaySession) readCollapser(collapser *HLSCollapser, demuxer *TSDemuxer) error {
+	buf := make([]byte, 64*1024)
+	for {
+		select {
+		case <-s.ctx.Done():
+			collapser.Stop()
+			demuxer.Flush()
+			return s.ctx.Err()
+		default:
+		}
+
+		n, err := collapser.Read(buf)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, ErrCollapserAborted) {
+				demuxer.Flush()
+				return nil
+			}
+			return err
+		}
+
+		if n > 0 {
+			// Track bytes ingested from origin (HLS collapse pipeline)
+			s.edgeBandwidth.OriginToBuffer.Add(uint64(n))
+
+			if err := demuxer.Write(buf[:n]); err != nil {
+				slog.Warn("Demuxer error", slog.String("error", err.Error()))
+			}
+			// Use atomic store to avoid blocking stats collection with mutex
+			s.lastActivity.Store(time.Now())
+		}
+	}
+}
+
+// upstreamUsable reports whether a session may switch to up: its circuit breaker
+// allows a connection and its source is below its concurrent stream limit.
+func (m *Manager) upstreamUsable(up Upstream) bool {
+	if !m.circuitBreakers.Get(up.StreamURL).Allow() {
+		return false
+	}
+	return !m.sourceAtLimit(up)
+}
@@ -0,0 +1,202 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFailoverTestSession(t *testing.T, m *Manager, upstreams ...Upstream) *RelaySession {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	session := &RelaySession{
+		ID:      models.NewULID(),
+		manager: m,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for _, up := range upstreams {
+		session.upstreams = append(session.upstreams, sessionUpstream{Upstream: up})
+	}
+	return session
+}
+
+func TestRelaySession_FailoverSkipsOpenCircuits(t *testing.T) {
+	config := DefaultManagerConfig()
+	manager := NewManager(config)
+	defer manager.Close()
+
+	// .ts URLs classify by extension, without network access
+	primary := Upstream{SourceName: "primary", StreamURL: "http://primary.example/live/1.ts"}
+	broken := Upstream{SourceName: "broken", StreamURL: "http://broken.example/live/1.ts"}
+	backup := Upstream{SourceName: "backup", StreamURL: "http://backup.example/live/1.ts"}
+
+	for range config.CircuitBreakerConfig.FailureThreshold {
+		manager.circuitBreakers.Get(broken.StreamURL).RecordFailure()
+	}
+
+	session := newFailoverTestSession(t, manager, primary, broken, backup)
+
+	next := session.failover(errors.New("unexpected EOF"))
+	require.NotNil(t, next)
+	assert.Equal(t, "backup", next.SourceName)
+	assert.Equal(t, StreamModePassthroughRawTS, next.classification.Mode)
+	assert.Equal(t, "backup", session.ActiveUpstream().SourceName)
+	assert.Equal(t, 1, session.FailoverCount())
+
+	// Alternates are tried once; nothing is left after the last one
+	assert.Nil(t, session.failover(errors.New("unexpected EOF")))
+	assert.Equal(t, "backup", session.ActiveUpstream().SourceName)
+}
+
+func TestRelaySession_FailoverWithoutAlternates(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig())
+	defer manager.Close()
+
+	session := newFailoverTestSession(t, manager,
+		Upstream{SourceName: "primary", StreamURL: "http://primary.example/live/1.ts"})
+
+	assert.Nil(t, session.failover(errors.New("unexpected EOF")))
+	assert.Equal(t, 0, session.FailoverCount())
+}
+
+func TestManager_UpstreamUsable(t *testing.T) {
+	config := DefaultManagerConfig()
+	manager := NewManager(config)
+	defer manager.Close()
+
+	up := Upstream{SourceID: models.NewULID(), StreamURL: "http://source.example/live/1.ts", MaxConcurrentStreams: 1}
+	assert.True(t, manager.upstreamUsable(up))
+
+	for range config.CircuitBreakerConfig.FailureThreshold {
+		manager.circuitBreakers.Get(up.StreamURL).RecordFailure()
+	}
+	assert.False(t, manager.upstreamUsable(up))
+}
+
+func TestManager_UpstreamUsableCountsExternalConnections(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig())
+	defer manager.Close()
+
+	up := Upstream{SourceID: models.NewULID(), StreamURL: "http://source.example/live/1.ts", MaxConcurrentStreams: 2}
+	manager.SetExternalConnections(up.SourceID, 1)
+	assert.Equal(t, 1, manager.SourceConnections(up.SourceID))
+	assert.True(t, manager.upstreamUsable(up))
+
+	// Other players on the same account take the remaining connections
+	manager.SetExternalConnections(up.SourceID, 2)
+	assert.False(t, manager.upstreamUsable(up))
+
+	manager.SetExternalConnections(up.SourceID, 0)
+	assert.Equal(t, 0, manager.SourceConnections(up.SourceID))
+	assert.True(t, manager.upstreamUsable(up))
+}
+
+func TestManager_ReserveAccount(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig())
+	defer manager.Close()
+
+	sourceID := models.NewULID()
+	up := Upstream{
+		SourceID:             sourceID,
+		SourceName:           "provider",
+		StreamURL:            "http://provider.example/live/first/pass/1.ts",
+		MaxConcurrentStreams: 2,
+		Accounts: []UpstreamAccount{
+			{Name: "first", StreamURL: "http://provider.example/live/first/pass/1.ts", MaxConcurrentStreams: 1},
+			{Name: "second", StreamURL: "http://provider.example/live/second/secret/1.ts", MaxConcurrentStreams: 1},
+		},
+	}
+
+	selected, free, release := manager.reserveAccount(up)
+	assert.True(t, free)
+	assert.Equal(t, "first", selected.Account)
+
+	// A reserved connection is taken until the session is registered
+	_, free, releaseSecond := manager.reserveAccount(up)
+	assert.True(t, free)
+	_, free, releaseNone := manager.reserveAccount(up)
+	assert.False(t, free)
+	releaseNone()
+	releaseSecond()
+
+	// A session on the first account moves the next one to the second
+	first := newFailoverTestSession(t, manager, selected)
+	manager.sessions[first.ID] = first
+	release()
+	release() // Releasing again is a no-op
+	selected, free, release = manager.reserveAccount(up)
+	assert.True(t, free)
+	assert.Equal(t, "second", selected.Account)
+	assert.Equal(t, "http://provider.example/live/second/secret/1.ts", selected.StreamURL)
+
+	second := newFailoverTestSession(t, manager, selected)
+	manager.sessions[second.ID] = second
+	release()
+	selected, free, release = manager.reserveAccount(up)
+	assert.False(t, free)
+	assert.Equal(t, "first", selected.Account)
+	release()
+	assert.Empty(t, manager.accountReservations)
+
+	// The test sessions have no pipeline for the manager to close
+	delete(manager.sessions, first.ID)
+	delete(manager.sessions, second.ID)
+
+	usage := accountUsage([]*RelaySession{first, second})
+	require.Len(t, usage, 2)
+	assert.Equal(t, AccountUsage{SourceID: sourceID.String(), SourceName: "provider", Account: "first", Sessions: 1, MaxConcurrentStreams: 1}, usage[0])
+	assert.Equal(t, 1, usage[1].Sessions)
+
+	// Sources without pooled accounts are used as they are
+	single := Upstream{SourceID: models.NewULID(), StreamURL: "http://other.example/1.ts"}
+	selected, free, release = manager.reserveAccount(single)
+	release()
+	assert.True(t, free)
+	assert.Equal(t, single, selected)
+
+	// Connections used outside tvarr count against the primary account
+	manager.SetExternalConnections(sourceID, 1)
+	selected, free, release = manager.reserveAccount(up)
+	release()
+	assert.True(t, free)
+	assert.Equal(t, "second", selected.Account)
+}
+
+func TestSharedESBuffer_MarkDiscontinuity(t *testing.T) {
+	buffer := NewSharedESBuffer("channel", "session", DefaultSharedESBufferConfig())
+	buffer.CreateSourceVariant("h264", "aac")
+
+	buffer.WriteVideo(90000, 90000, []byte{1}, true)
+	buffer.WriteVideo(93600, 93600, []byte{2}, false)
+	buffer.WriteAudio(92000, []byte{3})
+
+	// The next upstream starts its timestamps from scratch
+	buffer.MarkDiscontinuity()
+	buffer.WriteVideo(1000, 1000, []byte{4}, true)
+	buffer.WriteAudio(1500, []byte{5})
+	buffer.WriteVideo(4600, 4600, []byte{6}, false)
+
+	assert.Equal(t, uint64(1), buffer.Discontinuities())
+
+	source := buffer.GetSourceVariant()
+	video := source.VideoTrack().ReadFrom(0, 10)
+	require.Len(t, video, 4)
+	assert.False(t, video[1].Discontinuity)
+	assert.True(t, video[2].Discontinuity)
+	assert.Equal(t, int64(93600+discontinuityGap), video[2].PTS)
+	assert.Equal(t, int64(93600+discontinuityGap), video[2].DTS)
+	assert.False(t, video[3].Discontinuity)
+	assert.Equal(t, int64(93600+discontinuityGap+3600), video[3].PTS)
+
+	audio := source.AudioTrack().ReadFrom(0, 10)
+	require.Len(t, audio, 2)
+	assert.True(t, audio[1].Discontinuity)
+	assert.Equal(t, int64(93600+discontinuityGap+500), audio[1].PTS)
+}
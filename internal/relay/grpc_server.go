@@ -126,6 +126,7 @@ func (s *GRPCServer) Start(ctx context.Context) error {
 	s.server = grpc.NewServer(
 		grpc.UnaryInterceptor(s.unaryInterceptor),
 		grpc.StreamInterceptor(s.streamInterceptor),
+		grpc.StatsHandler(observability.GRPCServerHandler()),
 	)
 	proto.RegisterFFmpegDaemonServer(s.server, s)
 
@@ -175,6 +176,7 @@ func (s *GRPCServer) ServeWithListener(ctx context.Context, listener net.Listene
 	s.server = grpc.NewServer(
 		grpc.UnaryInterceptor(s.unaryInterceptor),
 		grpc.StreamInterceptor(s.streamInterceptor),
+		grpc.StatsHandler(observability.GRPCServerHandler()),
 	)
 	proto.RegisterFFmpegDaemonServer(s.server, s)
 	s.started = true
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/jmylchreest/tvarr/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // SegmentWaiter is an optional interface that SegmentProviders can implement
@@ -113,7 +116,11 @@ func (h *HLSHandler) ServePlaylistWithContext(ctx context.Context, w http.Respon
 			waitCtx, cancel := context.WithTimeout(ctx, SegmentWaitTimeout)
 			defer cancel()
 
-			if err := waiter.WaitForSegments(waitCtx, 1); err != nil {
+			_, span := observability.StartSpan(waitCtx, "relay.segments.wait",
+				attribute.Int("tvarr.segments.min", 1))
+			err := waiter.WaitForSegments(waitCtx, 1)
+			observability.EndSpan(span, err)
+			if err != nil {
 				http.Error(w, "No segments available yet, please retry", http.StatusServiceUnavailable)
 				return fmt.Errorf("waiting for segments: %w", err)
 			}
@@ -229,8 +236,17 @@ func (h *HLSHandler) GeneratePlaylist(baseURL string) string {
 	sb.WriteString(fmt.Sprintf("#EXT-X-TARGETDURATION:%d\n", targetDuration))
 	sb.WriteString(fmt.Sprintf("#EXT-X-MEDIA-SEQUENCE:%d\n", mediaSequence))
 
+	// With timeshift the playlist covers the whole DVR window, which players can
+	// seek within. It stays a live playlist rather than EVENT, since the window
+	// slides and EVENT playlists must never drop segments (RFC 8216 4.3.3.5).
+	// EXT-X-START keeps new viewers starting near the live edge.
+	if tsProvider, ok := h.provider.(TimeshiftProvider); ok && tsProvider.TimeshiftWindow() > 0 {
+		sb.WriteString(fmt.Sprintf("#EXT-X-START:TIME-OFFSET=-%d,PRECISE=NO\n", targetDuration*3))
+	}
+
 	// Ensure baseURL doesn't have trailing slash
 	baseURL = strings.TrimSuffix(baseURL, "/")
+	urlPrefix := segmentURLPrefix(baseURL)
 
 	// Determine the correct format value for segment URLs
 	// Use hls-fmp4 when in fMP4 mode to ensure proper routing
@@ -248,8 +264,8 @@ func (h *HLSHandler) GeneratePlaylist(baseURL string) string {
 	// For fMP4 mode, add EXT-X-MAP pointing to the initialization segment
 	// This tells players where to get the ftyp+moov boxes before any media segments
 	if isFMP4Mode && hasInitSegment {
-		sb.WriteString(fmt.Sprintf("#EXT-X-MAP:URI=\"%s?%s=%s&%s=1%s\"\n",
-			baseURL,
+		sb.WriteString(fmt.Sprintf("#EXT-X-MAP:URI=\"%s%s=%s&%s=1%s\"\n",
+			urlPrefix,
 			QueryParamFormat, formatValue,
 			QueryParamInit,
 			variantParam,
@@ -271,8 +287,8 @@ func (h *HLSHandler) GeneratePlaylist(baseURL string) string {
 
 		// Write segment info
 		sb.WriteString(fmt.Sprintf("#EXTINF:%.3f,\n", seg.Duration))
-		sb.WriteString(fmt.Sprintf("%s?%s=%s&%s=%d%s\n",
-			baseURL,
+		sb.WriteString(fmt.Sprintf("%s%s=%s&%s=%d%s\n",
+			urlPrefix,
 			QueryParamFormat, formatValue,
 			QueryParamSegment, seg.Sequence,
 			variantParam,
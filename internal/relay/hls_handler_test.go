@@ -0,0 +1,61 @@
+package relay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSegmentProvider serves a fixed list of segment infos.
+type stubSegmentProvider struct {
+	infos []SegmentInfo
+}
+
+func (p *stubSegmentProvider) GetSegmentInfos() []SegmentInfo { return p.infos }
+
+func (p *stubSegmentProvider) GetSegment(uint64) (*Segment, error) { return nil, ErrSegmentNotFound }
+
+func (p *stubSegmentProvider) TargetDuration() int { return 4 }
+
+func TestHLSHandler_GeneratePlaylist_SegmentURLs(t *testing.T) {
+	provider := &stubSegmentProvider{infos: []SegmentInfo{
+		{Sequence: 7, Duration: 4},
+		{Sequence: 8, Duration: 4},
+	}}
+	h := NewHLSHandlerWithVariant(provider, "h264/aac")
+
+	playlist := h.GeneratePlaylist("http://tvarr:8080/proxy/p/c")
+	assert.Contains(t, playlist, "http://tvarr:8080/proxy/p/c?format=hls&seg=7&variant=h264/aac\n")
+	assert.Contains(t, playlist, "#EXT-X-MEDIA-SEQUENCE:7\n")
+
+	// A base URL that already carries a query (the viewer credential) keeps it.
+	playlist = h.GeneratePlaylist("http://tvarr:8080/proxy/p/c?token=abc")
+	assert.Contains(t, playlist, "http://tvarr:8080/proxy/p/c?token=abc&format=hls&seg=8&variant=h264/aac\n")
+}
+
+// stubTimeshiftProvider is a stubSegmentProvider with a DVR window.
+type stubTimeshiftProvider struct {
+	stubSegmentProvider
+	window time.Duration
+}
+
+func (p *stubTimeshiftProvider) TimeshiftWindow() time.Duration { return p.window }
+
+func TestHLSHandler_GeneratePlaylist_Timeshift(t *testing.T) {
+	infos := []SegmentInfo{{Sequence: 1, Duration: 4}, {Sequence: 2, Duration: 4}}
+
+	playlist := NewHLSHandler(&stubSegmentProvider{infos: infos}).GeneratePlaylist("http://x/p")
+	assert.NotContains(t, playlist, "#EXT-X-PLAYLIST-TYPE")
+
+	provider := &stubTimeshiftProvider{stubSegmentProvider: stubSegmentProvider{infos: infos}, window: time.Hour}
+	playlist = NewHLSHandler(provider).GeneratePlaylist("http://x/p")
+	assert.NotContains(t, playlist, "#EXT-X-PLAYLIST-TYPE", "the DVR window slides, so it is not an EVENT playlist")
+	assert.Contains(t, playlist, "#EXT-X-START:TIME-OFFSET=-12,PRECISE=NO\n")
+	assert.NotContains(t, playlist, "#EXT-X-ENDLIST")
+}
+
+func TestSegmentURLPrefix(t *testing.T) {
+	assert.Equal(t, "http://x/proxy/a/b?", segmentURLPrefix("http://x/proxy/a/b"))
+	assert.Equal(t, "http://x/proxy/a/b?token=t&", segmentURLPrefix("http://x/proxy/a/b?token=t"))
+}
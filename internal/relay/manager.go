@@ -1,13 +1,16 @@
 package relay
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmylchreest/tvarr/internal/ffmpeg"
@@ -15,6 +18,9 @@ import (
 	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/repository"
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/proto"
+	"github.com/jmylchreest/tvarr/pkg/httpclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ErrSessionNotFound is returned when a relay session is not found.
@@ -84,6 +90,8 @@ type ManagerConfig struct {
 	ConnectionPoolConfig ConnectionPoolConfig
 	// FallbackConfig for fallback stream generation.
 	FallbackConfig FallbackConfig
+	// PreemptionConfig for sessions preempted at a source's connection limit.
+	PreemptionConfig PreemptionConfig
 	// HTTPClient for upstream requests.
 	HTTPClient *http.Client
 	// CodecRepo for caching stream codec information.
@@ -122,6 +130,8 @@ type HLSConfig struct {
 	MaxSegments int
 	// PlaylistSegments is the number of segments in playlists for new clients.
 	PlaylistSegments int
+	// Timeshift keeps HLS and DASH segments on disk for a pause/rewind window.
+	Timeshift TimeshiftConfig
 }
 
 // DefaultManagerConfig returns sensible defaults for the relay manager.
@@ -134,6 +144,7 @@ func DefaultManagerConfig() ManagerConfig {
 		CircuitBreakerConfig: DefaultCircuitBreakerConfig(),
 		ConnectionPoolConfig: DefaultConnectionPoolConfig(),
 		FallbackConfig:       DefaultFallbackConfig(),
+		PreemptionConfig:     DefaultPreemptionConfig(),
 		// For streaming, we use a transport with connection timeouts but no overall
 		// request timeout. The Timeout field on http.Client applies to the entire
 		// request including reading the body, which would cut off long-running streams.
@@ -169,20 +180,38 @@ type Manager struct {
 	classifier *StreamClassifier
 	logger     *slog.Logger
 
+	// proxyClients caches upstream HTTP clients by proxy URL
+	proxyClientsMu sync.Mutex
+	proxyClients   map[string]*http.Client
+
+	// externalConnections holds, per source, the upstream connections in use
+	// by clients other than tvarr, as last reported by the provider
+	externalMu          sync.RWMutex
+	externalConnections map[models.ULID]int
+
 	mu       sync.RWMutex
 	sessions map[models.ULID]*RelaySession
+	// accountReservations counts, per pooled account, the connections selected
+	// for sessions that are not yet registered or switched to it
+	accountReservations map[accountKey]int
 	// channelSessions maps channel IDs to session IDs for reuse
 	channelSessions map[models.ULID]models.ULID
+	// retiredBytes are the bytes transferred by removed sessions
+	retiredBytes edgeBytes
 
 	circuitBreakers          *CircuitBreakerRegistry
 	connectionPool           *ConnectionPool
 	fallbackGenerator        *FallbackGenerator
+	preemptionGenerator      *FallbackGenerator // Slate for clients of preempted sessions
 	daemonRegistry           *DaemonRegistry
 	daemonStreamMgr          *DaemonStreamManager
 	activeJobMgr             *ActiveJobManager
 	ffmpegDSpawner           *FFmpegDSpawner // For local transcoding via tvarr-ffmpegd subprocess
 	encoderOverridesProvider EncoderOverridesProvider
 
+	// onViewingEnded receives clients leaving sessions; see OnViewingEnded
+	onViewingEnded atomic.Pointer[ViewingFunc]
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
@@ -226,17 +255,36 @@ func NewManager(config ManagerConfig) *Manager {
 		logger.Debug("tvarr-ffmpegd binary not found - local subprocess transcoding unavailable")
 	}
 
+	// Segments from a previous process are unreachable: session IDs are never reused
+	if config.HLSConfig.Timeshift.Enabled() {
+		if err := CleanTimeshiftDirectory(config.HLSConfig.Timeshift.Directory); err != nil {
+			logger.Warn("Failed to clean timeshift directory",
+				slog.String("directory", config.HLSConfig.Timeshift.Directory),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	// Preempted sessions show the fallback slate with their own message
+	preemptionSlate := config.FallbackConfig
+	if config.PreemptionConfig.Message != "" {
+		preemptionSlate.Message = config.PreemptionConfig.Message
+	}
+
 	m := &Manager{
 		config:                   config,
 		ffmpegBin:                ffmpegBin,
 		prober:                   prober,
 		classifier:               NewStreamClassifier(config.HTTPClient),
 		logger:                   logger,
+		proxyClients:             make(map[string]*http.Client),
+		externalConnections:      make(map[models.ULID]int),
+		accountReservations:      make(map[accountKey]int),
 		sessions:                 make(map[models.ULID]*RelaySession),
 		channelSessions:          make(map[models.ULID]models.ULID),
 		circuitBreakers:          NewCircuitBreakerRegistry(config.CircuitBreakerConfig),
 		connectionPool:           NewConnectionPool(config.ConnectionPoolConfig),
 		fallbackGenerator:        NewFallbackGenerator(config.FallbackConfig, logger),
+		preemptionGenerator:      NewFallbackGenerator(preemptionSlate, logger),
 		daemonRegistry:           config.DaemonRegistry,
 		daemonStreamMgr:          config.DaemonStreamManager,
 		activeJobMgr:             config.ActiveJobManager,
@@ -263,6 +311,12 @@ func (m *Manager) FallbackGenerator() *FallbackGenerator {
 	return m.fallbackGenerator
 }
 
+// OnCircuitStateChange sets a function called when an upstream circuit breaker
+// changes state. Breakers are keyed by upstream stream URL.
+func (m *Manager) OnCircuitStateChange(fn func(streamURL string, from, to CircuitState)) {
+	m.circuitBreakers.OnStateChange(fn)
+}
+
 // DaemonRegistry returns the manager's daemon registry for distributed transcoding.
 func (m *Manager) DaemonRegistry() *DaemonRegistry {
 	return m.daemonRegistry
@@ -294,11 +348,15 @@ func (m *Manager) EncoderOverridesProvider() EncoderOverridesProvider {
 }
 
 // GetOrCreateSession gets an existing session for the channel or creates a new one.
+// primary is the channel's own upstream. Alternates are the same channel in other
+// stream sources, in failover order; the session switches to them when the primary fails.
 //
 // This function is carefully designed to avoid holding the manager lock during slow
 // operations (stream classification, codec probing) to prevent blocking API requests
 // like /api/v1/relay/sessions while a new session is being created.
-func (m *Manager) GetOrCreateSession(ctx context.Context, channelID models.ULID, channelName string, sourceID models.ULID, streamSourceName string, streamURL string, sourceMaxConcurrentStreams int, sourceUserAgent string, profile *models.EncodingProfile) (*RelaySession, error) {
+func (m *Manager) GetOrCreateSession(ctx context.Context, channelID models.ULID, channelName string, primary Upstream, profile *models.EncodingProfile, alternates []Upstream) (*RelaySession, error) {
+	streamURL := primary.StreamURL
+
 	// First, check if session already exists (fast path with read lock)
 	m.mu.RLock()
 	// TRACE level: frequent session lookups (one per playlist request)
@@ -307,7 +365,8 @@ func (m *Manager) GetOrCreateSession(ctx context.Context, channelID models.ULID,
 		slog.Int("active_sessions", len(m.sessions)))
 	if sessionID, ok := m.channelSessions[channelID]; ok {
 		if session, ok := m.sessions[sessionID]; ok {
-			isClosed := session.IsClosed()
+			// A preempted session is ending; its channel needs a new one
+			isClosed := session.IsClosed() || session.Preempted()
 			ingestCompleted := session.IngestCompleted()
 			if !isClosed {
 				// Session is not closed - check if we can reuse it
@@ -353,10 +412,16 @@ func (m *Manager) GetOrCreateSession(ctx context.Context, channelID models.ULID,
 
 	// Perform slow operations (classify, probe) WITHOUT holding the manager lock
 	// This prevents blocking Stats() and other operations during session creation
-	session, err := m.createSession(ctx, channelID, channelName, sourceID, streamSourceName, streamURL, sourceMaxConcurrentStreams, sourceUserAgent, profile)
+	ctx, span := observability.StartSpan(ctx, "relay.session.create",
+		attribute.String("tvarr.channel.id", channelID.String()))
+	session, release, err := m.createSession(ctx, channelID, channelName, primary, profile, alternates)
+	observability.EndSpan(span, err)
 	if err != nil {
 		return nil, err
 	}
+	// The session's account connection stays reserved until it is registered
+	// and counted as active; deferred first, it is released after the unlock
+	defer release()
 
 	// Now acquire the write lock to register the session
 	m.mu.Lock()
@@ -365,7 +430,7 @@ func (m *Manager) GetOrCreateSession(ctx context.Context, channelID models.ULID,
 	// Double-check: another goroutine might have created a session while we were classifying/probing
 	if existingSessionID, ok := m.channelSessions[channelID]; ok {
 		if existingSession, ok := m.sessions[existingSessionID]; ok {
-			if !existingSession.IsClosed() {
+			if !existingSession.IsClosed() && !existingSession.Preempted() {
 				// Check if we should reuse the existing session
 				// Only reuse if URL matches - different stream URL means we need a new session
 				urlMatches := existingSession.StreamURL == streamURL
@@ -384,6 +449,7 @@ func (m *Manager) GetOrCreateSession(ctx context.Context, channelID models.ULID,
 				slog.Bool("was_closed", existingSession.IsClosed()),
 				slog.Bool("url_matches", existingSession.StreamURL == streamURL))
 			existingSession.Close()
+			m.retireSession(existingSession)
 			delete(m.sessions, existingSessionID)
 			delete(m.channelSessions, channelID)
 		}
@@ -451,13 +517,118 @@ func (m *Manager) CountActiveSessionsForSource(sourceID models.ULID) int {
 
 	count := 0
 	for _, session := range m.sessions {
-		if !session.IsClosed() && session.SourceID == sourceID {
+		// Sessions count against the source they currently ingest from; a
+		// preempted session has released its connection
+		if !session.IsClosed() && !session.Preempted() && session.ActiveUpstream().SourceID == sourceID {
+			count++
+		}
+	}
+	return count
+}
+
+// CountActiveSessionsForAccount counts the active sessions connected with one
+// account of a source whose accounts are pooled.
+func (m *Manager) CountActiveSessionsForAccount(sourceID models.ULID, account string) int {
+	if sourceID.IsZero() {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.countSessionsForAccountLocked(sourceID, account)
+}
+
+// countSessionsForAccountLocked is CountActiveSessionsForAccount for callers
+// holding m.mu.
+func (m *Manager) countSessionsForAccountLocked(sourceID models.ULID, account string) int {
+	count := 0
+	for _, session := range m.sessions {
+		up := session.ActiveUpstream()
+		if !session.IsClosed() && !session.Preempted() && up.SourceID == sourceID && up.Account == account {
 			count++
 		}
 	}
 	return count
 }
 
+// accountKey identifies one account of a source whose accounts are pooled.
+type accountKey struct {
+	sourceID models.ULID
+	account  string
+}
+
+// reserveAccount returns up connecting with the first of its accounts that has a
+// free connection, and reserves that connection until release is called.
+// Selection and reservation happen under the manager lock, so concurrent
+// sessions cannot both take an account's last connection. Connections in use
+// outside tvarr count against the first (primary) account, whose usage the
+// provider reports. Upstreams without pooled accounts are returned unchanged.
+// If every account is at its limit, the first account is used and false is
+// returned.
+func (m *Manager) reserveAccount(up Upstream) (selected Upstream, free bool, release func()) {
+	if len(up.Accounts) == 0 {
+		return up, true, func() {}
+	}
+	m.externalMu.RLock()
+	external := m.externalConnections[up.SourceID]
+	m.externalMu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chosen := 0
+	for i, account := range up.Accounts {
+		used := m.countSessionsForAccountLocked(up.SourceID, account.Name) +
+			m.accountReservations[accountKey{up.SourceID, account.Name}]
+		if i == 0 {
+			used += external
+		}
+		if account.MaxConcurrentStreams == 0 || used < account.MaxConcurrentStreams {
+			chosen, free = i, true
+			break
+		}
+	}
+	up.Account = up.Accounts[chosen].Name
+	up.StreamURL = up.Accounts[chosen].StreamURL
+
+	key := accountKey{up.SourceID, up.Account}
+	m.accountReservations[key]++
+	var once sync.Once
+	return up, free, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if m.accountReservations[key]--; m.accountReservations[key] <= 0 {
+				delete(m.accountReservations, key)
+			}
+		})
+	}
+}
+
+// SetExternalConnections records how many upstream connections of a source are
+// in use outside tvarr, e.g. by other players sharing the same account. They
+// count against the source's max_concurrent_streams limit.
+func (m *Manager) SetExternalConnections(sourceID models.ULID, count int) {
+	m.externalMu.Lock()
+	defer m.externalMu.Unlock()
+	if count <= 0 {
+		delete(m.externalConnections, sourceID)
+		return
+	}
+	m.externalConnections[sourceID] = count
+}
+
+// SourceConnections returns the number of upstream connections in use for a
+// source: tvarr's active sessions plus connections used outside tvarr.
+func (m *Manager) SourceConnections(sourceID models.ULID) int {
+	if sourceID.IsZero() {
+		return 0
+	}
+	m.externalMu.RLock()
+	external := m.externalConnections[sourceID]
+	m.externalMu.RUnlock()
+	return m.CountActiveSessionsForSource(sourceID) + external
+}
+
 // CloseSession closes a specific session.
 func (m *Manager) CloseSession(sessionID models.ULID) error {
 	m.mu.Lock()
@@ -466,6 +637,7 @@ func (m *Manager) CloseSession(sessionID models.ULID) error {
 		m.mu.Unlock()
 		return ErrSessionNotFound
 	}
+	m.retireSession(session)
 	delete(m.sessions, sessionID)
 	delete(m.channelSessions, session.ChannelID)
 	m.mu.Unlock()
@@ -515,6 +687,7 @@ func (m *Manager) Stats() ManagerStats {
 		ActiveSessions:  sessionCount,
 		MaxSessions:     maxSessions,
 		Sessions:        sessions,
+		Accounts:        accountUsage(sessionList),
 		ConnectionPool:  m.connectionPool.Stats(),
 		CircuitBreakers: m.circuitBreakers.AllStats(),
 	}
@@ -528,6 +701,67 @@ func (m *Manager) HTTPClient() *http.Client {
 	return http.DefaultClient
 }
 
+// UpstreamClient returns the HTTP client for requests to an upstream, which
+// connects through its proxy and sends its request headers.
+func (m *Manager) UpstreamClient(opts UpstreamOptions) *http.Client {
+	return httpclient.WithHeaders(m.proxyHTTPClient(opts.ProxyURL), opts.RequestHeaders())
+}
+
+// proxyHTTPClient returns the HTTP client for upstream requests through
+// proxyURL, or HTTPClient if proxyURL is empty. Proxied clients share the
+// settings of HTTPClient's transport and are reused per proxy.
+func (m *Manager) proxyHTTPClient(proxyURL string) *http.Client {
+	base := m.HTTPClient()
+	if proxyURL == "" {
+		return base
+	}
+
+	m.proxyClientsMu.Lock()
+	defer m.proxyClientsMu.Unlock()
+	if client, ok := m.proxyClients[proxyURL]; ok {
+		return client
+	}
+
+	transport, _ := base.Transport.(*http.Transport)
+	client := &http.Client{
+		Transport:     httpclient.NewProxyTransport(transport, proxyURL),
+		CheckRedirect: base.CheckRedirect,
+		Jar:           base.Jar,
+		Timeout:       base.Timeout,
+	}
+	m.proxyClients[proxyURL] = client
+	return client
+}
+
+// classify classifies an upstream's stream with its upstream options.
+func (m *Manager) classify(ctx context.Context, up Upstream) ClassificationResult {
+	ctx, span := observability.StartSpan(ctx, "relay.classify")
+	defer span.End()
+
+	classifier := m.classifier
+	if up.ProxyURL != "" || len(up.RequestHeaders()) > 0 {
+		classifier = NewStreamClassifier(m.UpstreamClient(up.UpstreamOptions))
+	}
+	result := classifier.Classify(ctx, up.StreamURL)
+	span.SetAttributes(attribute.String("tvarr.stream.mode", result.Mode.String()))
+	return result
+}
+
+// localProbe probes a stream with the local ffprobe through the proxy and with
+// the request headers of opts. ffprobe cannot use SOCKS5 or HTTPS proxies, so
+// streams of sources behind one are not probed; that is logged as a warning
+// naming the source rather than left to the debug log of failed probes.
+func (m *Manager) localProbe(ctx context.Context, streamURL string, opts UpstreamOptions) (*ffmpeg.StreamInfo, error) {
+	info, err := m.prober.WithProxy(opts.ProxyURL).WithHeaders(opts.RequestHeaders()).QuickProbe(ctx, streamURL)
+	if errors.Is(err, ffmpeg.ErrProxyUnsupported) {
+		m.logger.Warn("Skipping probe: ffprobe only supports HTTP proxies",
+			slog.String("source", opts.Source),
+			slog.String("stream_url", streamURL),
+			slog.String("error", err.Error()))
+	}
+	return info, err
+}
+
 // ProbeAndStoreCodecInfo always probes the stream fresh and stores the result.
 // The stored result is used by the channel UI to display codec information.
 // Returns nil if probing is not available or fails (non-fatal).
@@ -535,12 +769,21 @@ func (m *Manager) HTTPClient() *http.Client {
 // Probing strategy (controlled by PreferRemoteProbe config):
 // - If PreferRemoteProbe is false (default): Use local ffprobe if available, fall back to remote
 // - If PreferRemoteProbe is true: Use remote daemons if available, fall back to local
-func (m *Manager) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string) *models.LastKnownCodec {
+//
+// Both probe paths fetch the stream through the proxy and with the request
+// headers of opts.
+func (m *Manager) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string, opts UpstreamOptions) *models.LastKnownCodec {
 	var codecInfo *models.LastKnownCodec
 	var probeMs int64
 	var probeErr error
 	var probeSource string
 
+	ctx, span := observability.StartSpan(ctx, "relay.probe")
+	defer func() {
+		span.SetAttributes(attribute.String("tvarr.probe.source", probeSource))
+		observability.EndSpan(span, probeErr)
+	}()
+
 	start := time.Now()
 
 	hasLocalProber := m.prober != nil
@@ -555,7 +798,7 @@ func (m *Manager) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string)
 		m.logger.Debug("Attempting remote probe via daemon (preferred)",
 			slog.String("stream_url", streamURL))
 
-		probeResp, err := m.daemonStreamMgr.Probe(ctx, streamURL, 10000)
+		probeResp, err := m.daemonStreamMgr.Probe(ctx, streamURL, opts, 10000)
 		probeMs = time.Since(start).Milliseconds()
 
 		if err != nil {
@@ -574,7 +817,7 @@ func (m *Manager) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string)
 
 			start = time.Now()
 			probeSource = "local"
-			streamInfo, err := m.prober.QuickProbe(ctx, streamURL)
+			streamInfo, err := m.localProbe(ctx, streamURL, opts)
 			probeMs = time.Since(start).Milliseconds()
 
 			if err != nil {
@@ -587,7 +830,7 @@ func (m *Manager) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string)
 	} else if hasLocalProber {
 		// Prefer local probing (default)
 		probeSource = "local"
-		streamInfo, err := m.prober.QuickProbe(ctx, streamURL)
+		streamInfo, err := m.localProbe(ctx, streamURL, opts)
 		probeMs = time.Since(start).Milliseconds()
 
 		if err != nil {
@@ -601,7 +844,7 @@ func (m *Manager) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string)
 		m.logger.Debug("No local ffprobe, attempting remote probe via daemon",
 			slog.String("stream_url", streamURL))
 
-		probeResp, err := m.daemonStreamMgr.Probe(ctx, streamURL, 10000)
+		probeResp, err := m.daemonStreamMgr.Probe(ctx, streamURL, opts, 10000)
 		probeMs = time.Since(start).Milliseconds()
 
 		if err != nil {
@@ -726,10 +969,21 @@ func (m *Manager) probeResponseToCodecInfo(streamURL string, resp *proto.ProbeRe
 // 4. Otherwise, return cached database info (may be stale or nil)
 //
 // This prevents probing from consuming extra connections when a stream is already active.
-func (m *Manager) GetOrProbeCodecInfo(ctx context.Context, channelID models.ULID, streamURL string) *models.LastKnownCodec {
+// Probes are made with the given upstream options.
+func (m *Manager) GetOrProbeCodecInfo(ctx context.Context, channelID models.ULID, streamURL string, opts UpstreamOptions) *models.LastKnownCodec {
 	var result *models.LastKnownCodec
 	var source string
 
+	ctx, span := observability.StartSpan(ctx, "relay.codec_info",
+		attribute.String("tvarr.channel.id", channelID.String()))
+	defer func() {
+		span.SetAttributes(
+			attribute.String("tvarr.codec.source", source),
+			attribute.Bool("tvarr.codec.found", result != nil),
+		)
+		span.End()
+	}()
+
 	// Priority 1: Check for active session - this is the fastest path and doesn't require a network call
 	session := m.GetSessionForChannel(channelID)
 	if session != nil && session.CachedCodecInfo != nil {
@@ -767,7 +1021,7 @@ func (m *Manager) GetOrProbeCodecInfo(ctx context.Context, channelID models.ULID
 	}
 
 	// Priority 3: We have capacity - probe fresh
-	result = m.ProbeAndStoreCodecInfo(ctx, streamURL)
+	result = m.ProbeAndStoreCodecInfo(ctx, streamURL, opts)
 	source = "probe"
 	m.logCodecResolution(channelID, source, result, "")
 	return result
@@ -814,19 +1068,99 @@ func (m *Manager) getCachedCodecInfo(ctx context.Context, streamURL string) *mod
 	return nil
 }
 
-// createSession creates a new relay session.
-func (m *Manager) createSession(ctx context.Context, channelID models.ULID, channelName string, sourceID models.ULID, streamSourceName string, streamURL string, sourceMaxConcurrentStreams int, sourceUserAgent string, profile *models.EncodingProfile) (*RelaySession, error) {
-	// Check circuit breaker
-	cb := m.circuitBreakers.Get(streamURL)
-	if !cb.Allow() {
-		return nil, fmt.Errorf("%w: circuit breaker open for %s", ErrUpstreamFailed, streamURL)
+// createSession creates a new relay session. The caller must call release once
+// the session is registered, freeing the account connection reserved for it.
+func (m *Manager) createSession(ctx context.Context, channelID models.ULID, channelName string, primary Upstream, profile *models.EncodingProfile, alternates []Upstream) (session *RelaySession, release func(), err error) {
+	streamURL := primary.StreamURL
+	streamSourceName := primary.SourceName
+
+	sessionID := models.NewULID()
+
+	// Connect with a free account of the primary's source. At the source's
+	// connection limit, its policy may preempt another session to free one.
+	selected, free, unreserve := m.reserveAccount(primary)
+	defer func() {
+		if err != nil {
+			unreserve()
+		}
+	}()
+	atLimit := !free || m.sourceAtLimit(selected)
+	var preempted *PreemptionInfo
+	if atLimit && primary.LimitPolicy != "" {
+		if preempted = m.preemptForConnection(sessionID, channelName, primary); preempted != nil {
+			unreserve()
+			selected, free, unreserve = m.reserveAccount(primary)
+			atLimit = false
+		}
 	}
+	if !free && primary.LimitPolicy == "" {
+		m.logger.Warn("All accounts of source at their connection limit, using the first",
+			slog.String("channel", channelName),
+			slog.String("source", streamSourceName),
+			slog.String("account", selected.Account))
+	}
+	// With a policy, a source at its limit is not connected to at all
+	blocked := atLimit && primary.LimitPolicy != ""
+	if blocked {
+		unreserve()
+		unreserve = func() {}
+	}
+
+	upstreams := make([]sessionUpstream, 0, 1+len(alternates))
+	upstreams = append(upstreams, sessionUpstream{Upstream: selected})
+	for _, alt := range alternates {
+		upstreams = append(upstreams, sessionUpstream{Upstream: alt})
+	}
+
+	// Check circuit breakers, starting on the first alternate if the primary's is
+	// open. A blocked primary starts on the first alternate with a free connection.
+	active := -1
+	for i := range upstreams {
+		if blocked {
+			if i == 0 {
+				continue
+			}
+			alt, free, altRelease := m.reserveAccount(upstreams[i].Upstream)
+			if !free || !m.upstreamUsable(alt) {
+				altRelease()
+				continue
+			}
+			upstreams[i].Upstream = alt
+			unreserve = altRelease
+			active = i
+			break
+		}
+		if m.circuitBreakers.Get(upstreams[i].StreamURL).Allow() {
+			active = i
+			break
+		}
+	}
+	if active < 0 && blocked {
+		return nil, nil, fmt.Errorf("%w: %s", ErrConnectionLimitReached, streamSourceName)
+	}
+	if active < 0 {
+		return nil, nil, fmt.Errorf("%w: circuit breaker open for %s", ErrUpstreamFailed, streamURL)
+	}
+	if blocked {
+		m.logger.Warn("Source at its connection limit, starting on alternate source",
+			slog.String("channel", channelName),
+			slog.String("source", streamSourceName),
+			slog.String("alternate_source", upstreams[active].SourceName))
+	} else if active > 0 {
+		m.logger.Warn("Primary upstream circuit breaker open, starting on alternate source",
+			slog.String("channel", channelName),
+			slog.String("source", streamSourceName),
+			slog.String("alternate_source", upstreams[active].SourceName))
+	}
+	activeURL := upstreams[active].StreamURL
+	cb := m.circuitBreakers.Get(activeURL)
 
 	// Classify stream
-	classification := m.classifier.Classify(ctx, streamURL)
+	classification := m.classify(ctx, upstreams[active].Upstream)
+	upstreams[active].classification = classification
 
 	// Determine the actual input URL (HLS may use a media playlist)
-	probeURL := streamURL
+	probeURL := activeURL
 	if classification.SelectedMediaPlaylist != "" {
 		probeURL = classification.SelectedMediaPlaylist
 	}
@@ -845,7 +1179,7 @@ func (m *Manager) createSession(ctx context.Context, channelID models.ULID, chan
 
 	// Only probe fresh if no recent cache
 	if codecInfo == nil {
-		codecInfo = m.ProbeAndStoreCodecInfo(ctx, probeURL)
+		codecInfo = m.ProbeAndStoreCodecInfo(ctx, probeURL, upstreams[active].UpstreamOptions)
 		codecSource = "probe"
 	}
 
@@ -867,19 +1201,21 @@ func (m *Manager) createSession(ctx context.Context, channelID models.ULID, chan
 		gracePeriods = DefaultProcessorIdleGracePeriods()
 	}
 
-	session := &RelaySession{
-		ID:                         models.NewULID(),
+	session = &RelaySession{
+		ID:                         sessionID,
 		ChannelID:                  channelID,
 		ChannelName:                channelName,
-		SourceID:                   sourceID,
+		SourceID:                   primary.SourceID,
 		StreamSourceName:           streamSourceName,
 		StreamURL:                  streamURL,
-		SourceMaxConcurrentStreams: sourceMaxConcurrentStreams,
-		SourceUserAgent:            sourceUserAgent,
+		SourceMaxConcurrentStreams: primary.MaxConcurrentStreams,
+		SourceOptions:              primary.UpstreamOptions,
 		EncodingProfile:            profile,
 		Classification:             classification,
 		CachedCodecInfo:            codecInfo,
 		StartedAt:                  time.Now(),
+		Priority:                   primary.Priority,
+		preempted:                  preempted,
 		manager:                    m,
 		ctx:                        sessionCtx,
 		cancel:                     sessionCancel,
@@ -887,6 +1223,9 @@ func (m *Manager) createSession(ctx context.Context, channelID models.ULID, chan
 		resourceHistory:            NewResourceHistory(),
 		edgeBandwidth:              NewEdgeBandwidthTrackers(),
 		processorIdleGracePeriods:  gracePeriods,
+		upstreams:                  upstreams,
+		activeUpstream:             active,
+		spanContext:                trace.SpanContextFromContext(ctx),
 	}
 
 	// Initialize atomic values for frequently updated fields
@@ -911,11 +1250,11 @@ func (m *Manager) createSession(ctx context.Context, channelID models.ULID, chan
 	if err := session.start(session.ctx); err != nil {
 		session.Close()
 		cb.RecordFailure()
-		return nil, err
+		return nil, nil, err
 	}
 
 	cb.RecordSuccess()
-	return session, nil
+	return session, unreserve, nil
 }
 
 // cleanupLoop periodically cleans up stale sessions.
@@ -1025,6 +1364,7 @@ func (m *Manager) cleanupStaleSessions() {
 			m.logger.Info("Removing session from manager",
 				slog.String("session_id", id.String()),
 				slog.String("channel_id", session.ChannelID.String()))
+			m.retireSession(session)
 			delete(m.sessions, id)
 			delete(m.channelSessions, session.ChannelID)
 			go session.Close()
@@ -1038,6 +1378,52 @@ type ManagerStats struct {
 	ActiveSessions  int                     `json:"active_sessions"`
 	MaxSessions     int                     `json:"max_sessions"`
 	Sessions        []SessionStats          `json:"sessions,omitempty"`
+	Accounts        []AccountUsage          `json:"accounts,omitempty"`
 	ConnectionPool  ConnectionPoolStats     `json:"connection_pool"`
 	CircuitBreakers map[string]CircuitStats `json:"circuit_breakers,omitempty"`
 }
+
+// AccountUsage is the number of sessions connected with one account of a source
+// whose accounts are pooled.
+type AccountUsage struct {
+	SourceID             string `json:"source_id"`
+	SourceName           string `json:"source_name"`
+	Account              string `json:"account"`
+	Sessions             int    `json:"sessions"`
+	MaxConcurrentStreams int    `json:"max_concurrent_streams"` // 0 = unlimited
+}
+
+// accountUsage returns the usage of every account of the pooled sources that
+// sessions are connected to, in source and account order.
+func accountUsage(sessions []*RelaySession) []AccountUsage {
+	var usage []AccountUsage
+	index := make(map[string]int)
+	for _, session := range sessions {
+		if session.IsClosed() || session.Preempted() {
+			continue
+		}
+		up := session.ActiveUpstream()
+		if len(up.Accounts) == 0 {
+			continue
+		}
+		for _, account := range up.Accounts {
+			key := up.SourceID.String() + "/" + account.Name
+			if _, ok := index[key]; !ok {
+				index[key] = len(usage)
+				usage = append(usage, AccountUsage{
+					SourceID:             up.SourceID.String(),
+					SourceName:           up.SourceName,
+					Account:              account.Name,
+					MaxConcurrentStreams: account.MaxConcurrentStreams,
+				})
+			}
+		}
+		if i, ok := index[up.SourceID.String()+"/"+up.Account]; ok {
+			usage[i].Sessions++
+		}
+	}
+	slices.SortStableFunc(usage, func(a, b AccountUsage) int {
+		return cmp.Compare(a.SourceName, b.SourceName)
+	})
+	return usage
+}
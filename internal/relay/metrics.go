@@ -0,0 +1,227 @@
+package relay
+
+import (
+	"strconv"
+
+	"github.com/jmylchreest/tvarr/internal/observability"
+)
+
+// Bandwidth edges reported by the tvarr_relay_bytes_total metric.
+const (
+	edgeOriginToBuffer     = "origin_to_buffer"
+	edgeBufferToTranscoder = "buffer_to_transcoder"
+	edgeTranscoderToBuffer = "transcoder_to_buffer"
+	edgeBufferToProcessor  = "buffer_to_processor"
+)
+
+// edgeBytes is the number of bytes transferred over each bandwidth edge, with
+// buffer to processor edges by format.
+type edgeBytes struct {
+	originToBuffer     uint64
+	bufferToTranscoder uint64
+	transcoderToBuffer uint64
+	bufferToProcessor  map[string]uint64
+}
+
+// add adds the bytes transferred over a session's edges.
+func (b *edgeBytes) add(e *EdgeBandwidthTrackers) {
+	if e == nil {
+		return
+	}
+	b.originToBuffer += e.OriginToBuffer.TotalBytes()
+	b.bufferToTranscoder += e.BufferToTranscoder.TotalBytes()
+	b.transcoderToBuffer += e.TranscoderToBuffer.TotalBytes()
+
+	e.bufferToProcessorMu.RLock()
+	defer e.bufferToProcessorMu.RUnlock()
+	for format, tracker := range e.BufferToProcessor {
+		if b.bufferToProcessor == nil {
+			b.bufferToProcessor = make(map[string]uint64)
+		}
+		b.bufferToProcessor[format] += tracker.TotalBytes()
+	}
+}
+
+// emit reports the bytes of each edge.
+func (b *edgeBytes) emit(emit observability.EmitFunc) {
+	emit(float64(b.originToBuffer), edgeOriginToBuffer, "")
+	emit(float64(b.bufferToTranscoder), edgeBufferToTranscoder, "")
+	emit(float64(b.transcoderToBuffer), edgeTranscoderToBuffer, "")
+	for format, n := range b.bufferToProcessor {
+		emit(float64(n), edgeBufferToProcessor, format)
+	}
+}
+
+// retireSession records the bytes transferred by a session that is being
+// removed, so that the byte counters never decrease. Must be called with m.mu
+// held for writing.
+func (m *Manager) retireSession(session *RelaySession) {
+	m.retiredBytes.add(session.edgeBandwidth)
+}
+
+// bytesTransferred returns the bytes transferred by all sessions, current and removed.
+func (m *Manager) bytesTransferred() edgeBytes {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total := edgeBytes{
+		originToBuffer:     m.retiredBytes.originToBuffer,
+		bufferToTranscoder: m.retiredBytes.bufferToTranscoder,
+		transcoderToBuffer: m.retiredBytes.transcoderToBuffer,
+		bufferToProcessor:  make(map[string]uint64, len(m.retiredBytes.bufferToProcessor)),
+	}
+	for format, n := range m.retiredBytes.bufferToProcessor {
+		total.bufferToProcessor[format] = n
+	}
+	for _, session := range m.sessions {
+		total.add(session.edgeBandwidth)
+	}
+	return total
+}
+
+// RegisterMetrics registers the manager's session, client, bandwidth and
+// circuit breaker metrics, which are collected on each scrape.
+func (m *Manager) RegisterMetrics(r *observability.MetricsRegistry) {
+	r.NewGaugeFunc("tvarr_relay_sessions",
+		"Relay sessions by state (active, preempted).",
+		[]string{"state"}, func(emit observability.EmitFunc) {
+			var active, preempted int
+			for _, session := range m.sessionList() {
+				switch {
+				case session.IsClosed():
+				case session.Preempted():
+					preempted++
+				default:
+					active++
+				}
+			}
+			emit(float64(active), "active")
+			emit(float64(preempted), "preempted")
+		})
+
+	r.NewGaugeFunc("tvarr_relay_sessions_max",
+		"Maximum number of concurrent relay sessions.",
+		nil, func(emit observability.EmitFunc) {
+			emit(float64(m.config.MaxSessions))
+		})
+
+	r.NewGaugeFunc("tvarr_relay_clients",
+		"Clients connected to relay sessions by output format.",
+		[]string{"format"}, func(emit observability.EmitFunc) {
+			for _, format := range []string{"hls", "dash", "mpegts"} {
+				emit(0, format)
+			}
+			for _, session := range m.sessionList() {
+				for format, n := range session.clientCountsByFormat() {
+					emit(float64(n), format)
+				}
+			}
+		})
+
+	r.NewCounterFunc("tvarr_relay_bytes_total",
+		"Bytes transferred by relay sessions over each pipeline edge; buffer_to_processor edges are by output format.",
+		[]string{"edge", "format"}, func(emit observability.EmitFunc) {
+			total := m.bytesTransferred()
+			total.emit(emit)
+		})
+
+	r.NewGaugeFunc("tvarr_relay_circuit_breakers",
+		"Upstream circuit breakers by state.",
+		[]string{"state"}, func(emit observability.EmitFunc) {
+			for _, state := range []CircuitState{CircuitClosed, CircuitOpen, CircuitHalfOpen} {
+				emit(0, state.String())
+			}
+			for _, stats := range m.circuitBreakers.AllStats() {
+				emit(1, stats.State)
+			}
+		})
+}
+
+// sessionList returns the manager's current sessions.
+func (m *Manager) sessionList() []*RelaySession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sessions := make([]*RelaySession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// RegisterMetrics registers the load and GPU session usage of the registered
+// daemons, which are collected on each scrape. Daemons are labelled by name.
+func (r *DaemonRegistry) RegisterMetrics(registry *observability.MetricsRegistry) {
+	registry.NewGaugeFunc("tvarr_ffmpegd_daemons",
+		"Registered ffmpegd daemons by state.",
+		[]string{"state"}, func(emit observability.EmitFunc) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			for _, daemon := range r.daemons {
+				emit(1, daemon.State.String())
+			}
+		})
+
+	registry.NewGaugeFunc("tvarr_ffmpegd_active_jobs",
+		"Jobs running on each ffmpegd daemon.",
+		[]string{"daemon"}, func(emit observability.EmitFunc) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			for _, daemon := range r.daemons {
+				emit(float64(daemon.ActiveJobs), daemon.Name)
+			}
+		})
+
+	registry.NewGaugeFunc("tvarr_ffmpegd_max_jobs",
+		"Maximum concurrent jobs of each ffmpegd daemon.",
+		[]string{"daemon"}, func(emit observability.EmitFunc) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			for _, daemon := range r.daemons {
+				if daemon.Capabilities != nil {
+					emit(float64(daemon.Capabilities.MaxConcurrentJobs), daemon.Name)
+				}
+			}
+		})
+
+	registry.NewGaugeFunc("tvarr_ffmpegd_cpu_percent",
+		"CPU usage of each ffmpegd daemon's host, as last reported.",
+		[]string{"daemon"}, func(emit observability.EmitFunc) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			for _, daemon := range r.daemons {
+				if daemon.SystemStats != nil {
+					emit(daemon.SystemStats.CPUPercent, daemon.Name)
+				}
+			}
+		})
+
+	registry.NewGaugeFunc("tvarr_ffmpegd_gpu_encode_sessions",
+		"Active encode sessions on each GPU of each ffmpegd daemon.",
+		[]string{"daemon", "gpu"}, func(emit observability.EmitFunc) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			for _, daemon := range r.daemons {
+				if daemon.Capabilities == nil {
+					continue
+				}
+				for _, gpu := range daemon.Capabilities.GPUs {
+					emit(float64(gpu.ActiveEncodeSessions), daemon.Name, strconv.Itoa(gpu.Index))
+				}
+			}
+		})
+
+	registry.NewGaugeFunc("tvarr_ffmpegd_gpu_encode_sessions_max",
+		"Maximum encode sessions of each GPU of each ffmpegd daemon (0 = unlimited).",
+		[]string{"daemon", "gpu"}, func(emit observability.EmitFunc) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			for _, daemon := range r.daemons {
+				if daemon.Capabilities == nil {
+					continue
+				}
+				for _, gpu := range daemon.Capabilities.GPUs {
+					emit(float64(gpu.MaxEncodeSessions), daemon.Name, strconv.Itoa(gpu.Index))
+				}
+			}
+		})
+}
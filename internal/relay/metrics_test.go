@@ -0,0 +1,56 @@
+package relay
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_RegisterMetrics(t *testing.T) {
+	config := DefaultManagerConfig()
+	manager := NewManager(config)
+	defer manager.Close()
+
+	session := newFailoverTestSession(t, manager)
+	session.edgeBandwidth = NewEdgeBandwidthTrackers()
+	session.edgeBandwidth.OriginToBuffer.Add(1000)
+	session.edgeBandwidth.GetOrCreateProcessorTracker("hls").Add(400)
+	manager.sessions[session.ID] = session
+	// The test session has no pipeline for the manager to close
+	defer delete(manager.sessions, session.ID)
+
+	for range config.CircuitBreakerConfig.FailureThreshold {
+		manager.circuitBreakers.Get("http://broken.example/live/1.ts").RecordFailure()
+	}
+	manager.circuitBreakers.Get("http://working.example/live/1.ts")
+
+	registry := observability.NewMetricsRegistry()
+	manager.RegisterMetrics(registry)
+	scrape := func() string {
+		var buf strings.Builder
+		require.NoError(t, registry.Write(&buf))
+		return buf.String()
+	}
+
+	output := scrape()
+	assert.Contains(t, output, `tvarr_relay_sessions{state="active"} 1`)
+	assert.Contains(t, output, `tvarr_relay_clients{format="hls"} 0`)
+	assert.Contains(t, output, `tvarr_relay_bytes_total{edge="origin_to_buffer",format=""} 1000`)
+	assert.Contains(t, output, `tvarr_relay_bytes_total{edge="buffer_to_processor",format="hls"} 400`)
+	assert.Contains(t, output, `tvarr_relay_circuit_breakers{state="open"} 1`)
+	assert.Contains(t, output, `tvarr_relay_circuit_breakers{state="closed"} 1`)
+
+	// Bytes of removed sessions are still counted
+	manager.mu.Lock()
+	manager.retireSession(session)
+	delete(manager.sessions, session.ID)
+	manager.mu.Unlock()
+
+	output = scrape()
+	assert.Contains(t, output, `tvarr_relay_sessions{state="active"} 0`)
+	assert.Contains(t, output, `tvarr_relay_bytes_total{edge="origin_to_buffer",format=""} 1000`)
+	assert.Contains(t, output, `tvarr_relay_bytes_total{edge="buffer_to_processor",format="hls"} 400`)
+}
@@ -4,6 +4,7 @@ package relay
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -94,3 +95,12 @@ func NewOutputHandlerBase(provider SegmentProvider) OutputHandlerBase {
 func (b *OutputHandlerBase) Provider() SegmentProvider {
 	return b.provider
 }
+
+// segmentURLPrefix returns baseURL followed by the separator for appending segment
+// query parameters. baseURL may already carry a query string (e.g. a viewer token).
+func segmentURLPrefix(baseURL string) string {
+	if strings.Contains(baseURL, "?") {
+		return baseURL + "&"
+	}
+	return baseURL + "?"
+}
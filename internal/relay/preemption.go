@@ -0,0 +1,222 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// ErrConnectionLimitReached is returned when a session cannot start because its
+// source is at its connection limit and its policy frees no connection.
+var ErrConnectionLimitReached = errors.New("source connection limit reached")
+
+// ErrSessionPreempted ends a session whose connection was given to another session.
+var ErrSessionPreempted = errors.New("relay session preempted")
+
+// PreemptionConfig holds configuration for preempting sessions at a source's
+// connection limit.
+type PreemptionConfig struct {
+	// Message to display on the slate shown to clients of a preempted session.
+	Message string
+	// SlateDuration is how long the slate is shown before the session ends.
+	SlateDuration time.Duration
+}
+
+// DefaultPreemptionConfig returns sensible defaults for preemption.
+func DefaultPreemptionConfig() PreemptionConfig {
+	return PreemptionConfig{
+		Message:       "Stream stopped - connection needed by another viewer",
+		SlateDuration: 30 * time.Second,
+	}
+}
+
+// PreemptionInfo records a connection limit decision: a session that was
+// preempted, or the session that preempted it.
+type PreemptionInfo struct {
+	Policy    models.ConnectionLimitPolicy `json:"policy"`
+	SessionID string                       `json:"session_id"` // The other session
+	Channel   string                       `json:"channel"`    // The other session's channel
+	At        time.Time                    `json:"at"`
+}
+
+// sourceAtLimit reports whether up's source has no free connection.
+func (m *Manager) sourceAtLimit(up Upstream) bool {
+	return up.MaxConcurrentStreams > 0 && m.SourceConnections(up.SourceID) >= up.MaxConcurrentStreams
+}
+
+// preemptForConnection frees a connection of up's source for a new session by
+// preempting the source's session chosen by up's policy. Returns the decision
+// for the new session, or nil if the policy rejects or no session qualifies.
+func (m *Manager) preemptForConnection(sessionID models.ULID, channelName string, up Upstream) *PreemptionInfo {
+	if up.LimitPolicy == "" || up.LimitPolicy == models.ConnectionLimitPolicyReject {
+		return nil
+	}
+
+	m.mu.RLock()
+	candidates := make([]*RelaySession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		if !session.IsClosed() && !session.Preempted() && session.ActiveUpstream().SourceID == up.SourceID {
+			candidates = append(candidates, session)
+		}
+	}
+	m.mu.RUnlock()
+
+	victim := selectPreemptionVictim(up.LimitPolicy, up.Priority, candidates)
+	if victim == nil {
+		return nil
+	}
+
+	now := time.Now()
+	victim.preempt(PreemptionInfo{
+		Policy:    up.LimitPolicy,
+		SessionID: sessionID.String(),
+		Channel:   channelName,
+		At:        now,
+	})
+
+	m.logger.Warn("Source at its connection limit, preempting relay session",
+		slog.String("source", up.SourceName),
+		slog.String("policy", string(up.LimitPolicy)),
+		slog.String("channel", channelName),
+		slog.String("preempted_session_id", victim.ID.String()),
+		slog.String("preempted_channel", victim.ChannelName),
+		slog.Int("preempted_clients", victim.ClientCount()))
+
+	return &PreemptionInfo{
+		Policy:    up.LimitPolicy,
+		SessionID: victim.ID.String(),
+		Channel:   victim.ChannelName,
+		At:        now,
+	}
+}
+
+// selectPreemptionVictim returns the candidate a policy preempts for a new
+// session started for a proxy of the given priority, or nil if there is none.
+func selectPreemptionVictim(policy models.ConnectionLimitPolicy, priority int, candidates []*RelaySession) *RelaySession {
+	var victim *RelaySession
+	for _, candidate := range candidates {
+		// A preempted recording would lose the rest of its programme
+		if candidate.Recording() {
+			continue
+		}
+		switch policy {
+		case models.ConnectionLimitPolicyPreemptIdlest:
+			if victim == nil || idler(candidate, victim) {
+				victim = candidate
+			}
+		case models.ConnectionLimitPolicyPreemptLowestPriority:
+			// Sessions of the same or a higher priority are never preempted
+			if candidate.Priority >= priority {
+				continue
+			}
+			if victim == nil || candidate.Priority < victim.Priority ||
+				(candidate.Priority == victim.Priority && idler(candidate, victim)) {
+				victim = candidate
+			}
+		case models.ConnectionLimitPolicyPreemptOldest:
+			if victim == nil || candidate.StartedAt.Before(victim.StartedAt) {
+				victim = candidate
+			}
+		}
+	}
+	return victim
+}
+
+// idler reports whether session a is idler than b: it has fewer clients, or as
+// many and has been idle for longer.
+func idler(a, b *RelaySession) bool {
+	if ac, bc := a.ClientCount(), b.ClientCount(); ac != bc {
+		return ac < bc
+	}
+	ai, bi := a.IdleSince(), b.IdleSince()
+	if ai.IsZero() || bi.IsZero() {
+		return !ai.IsZero()
+	}
+	return ai.Before(bi)
+}
+
+// HoldForRecording marks the session as captured by a recording until release
+// is called. Sessions being recorded are never preempted.
+func (s *RelaySession) HoldForRecording() (release func()) {
+	s.recordings.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.recordings.Add(-1) })
+	}
+}
+
+// Recording returns true if a recording is capturing the session.
+func (s *RelaySession) Recording() bool {
+	return s.recordings.Load() > 0
+}
+
+// Preempted returns true if the session's connection was given to another session.
+func (s *RelaySession) Preempted() bool {
+	return s.preemptedBy.Load() != nil
+}
+
+// preempt stops the session's upstream so that another session can use its
+// connection. Clients are shown the preemption slate before the session ends.
+func (s *RelaySession) preempt(info PreemptionInfo) {
+	if !s.preemptedBy.CompareAndSwap(nil, &info) {
+		return
+	}
+	s.stopInput()
+}
+
+// showPreemptionSlate feeds the preemption slate to the session's clients for
+// the configured duration, then ends the session with ErrSessionPreempted.
+func (s *RelaySession) showPreemptionSlate() error {
+	duration := s.manager.config.PreemptionConfig.SlateDuration
+	slate := s.manager.preemptionSlate(s.ctx)
+	if slate == nil || duration <= 0 || s.esBuffer == nil {
+		return ErrSessionPreempted
+	}
+	segment, err := slate.GetSegment()
+	if err != nil {
+		return ErrSessionPreempted
+	}
+
+	interval := time.Duration(slate.config.SegmentDuration * float64(time.Second))
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(duration)
+	for {
+		// Each repetition is a new transport stream whose timestamps restart
+		demuxer := s.resetDemuxer()
+		s.esBuffer.MarkDiscontinuity()
+		if err := demuxer.Write(segment); err != nil {
+			slog.Warn("Demuxer error", slog.String("error", err.Error()))
+		}
+		demuxer.Flush()
+		s.lastActivity.Store(time.Now())
+
+		if !time.Now().Before(deadline) {
+			return ErrSessionPreempted
+		}
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// preemptionSlate returns the generator of the preemption slate, generating the
+// slate on first use. Returns nil if it cannot be generated.
+func (m *Manager) preemptionSlate(ctx context.Context) *FallbackGenerator {
+	if err := m.preemptionGenerator.Initialize(ctx); err != nil {
+		m.logger.Warn("Failed to generate preemption slate",
+			slog.String("error", err.Error()))
+		return nil
+	}
+	return m.preemptionGenerator
+}
@@ -0,0 +1,133 @@
+package relay
+
+import (
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPreemptionVictim(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig())
+	defer manager.Close()
+
+	now := time.Now()
+	newSession := func(priority int, startedAt time.Time, idleFor time.Duration) *RelaySession {
+		session := newFailoverTestSession(t, manager)
+		session.Priority = priority
+		session.StartedAt = startedAt
+		if idleFor > 0 {
+			session.idleSince.Store(now.Add(-idleFor))
+		}
+		return session
+	}
+
+	oldest := newSession(5, now.Add(-time.Hour), 0)
+	lowPriority := newSession(1, now.Add(-time.Minute), 10*time.Second)
+	idlest := newSession(5, now.Add(-30*time.Minute), time.Minute)
+	candidates := []*RelaySession{oldest, lowPriority, idlest}
+
+	assert.Same(t, idlest, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptIdlest, 0, candidates))
+	assert.Same(t, oldest, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptOldest, 0, candidates))
+	assert.Same(t, lowPriority, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptLowestPriority, 3, candidates))
+
+	// Sessions of the same or a higher priority are kept
+	assert.Nil(t, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptLowestPriority, 1, candidates))
+	assert.Nil(t, selectPreemptionVictim(models.ConnectionLimitPolicyReject, 0, candidates))
+
+	// Sessions being recorded are never preempted
+	release := idlest.HoldForRecording()
+	assert.Same(t, lowPriority, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptIdlest, 0, candidates))
+	releaseOldest := oldest.HoldForRecording()
+	assert.Same(t, lowPriority, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptOldest, 0, candidates))
+	releaseLow := lowPriority.HoldForRecording()
+	assert.Nil(t, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptLowestPriority, 3, candidates))
+	release()
+	release() // Releasing again is a no-op
+	releaseOldest()
+	releaseLow()
+	assert.False(t, idlest.Recording())
+	assert.Same(t, idlest, selectPreemptionVictim(models.ConnectionLimitPolicyPreemptIdlest, 0, candidates))
+}
+
+func TestManager_PreemptForConnection(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig())
+	defer manager.Close()
+
+	up := Upstream{
+		SourceID:             models.NewULID(),
+		SourceName:           "provider",
+		StreamURL:            "http://provider.example/live/1.ts",
+		MaxConcurrentStreams: 1,
+		LimitPolicy:          models.ConnectionLimitPolicyReject,
+	}
+	running := newFailoverTestSession(t, manager, up)
+	running.ChannelName = "News"
+	manager.sessions[running.ID] = running
+	// The test session has no pipeline for the manager to close
+	defer delete(manager.sessions, running.ID)
+
+	require.True(t, manager.sourceAtLimit(up))
+	assert.Nil(t, manager.preemptForConnection(models.NewULID(), "Sport", up))
+	assert.False(t, running.Preempted())
+
+	up.LimitPolicy = models.ConnectionLimitPolicyPreemptOldest
+	sessionID := models.NewULID()
+	decision := manager.preemptForConnection(sessionID, "Sport", up)
+	require.NotNil(t, decision)
+	assert.Equal(t, running.ID.String(), decision.SessionID)
+	assert.Equal(t, "News", decision.Channel)
+	assert.Equal(t, models.ConnectionLimitPolicyPreemptOldest, decision.Policy)
+
+	require.True(t, running.Preempted())
+	preemptedBy := running.preemptedBy.Load()
+	assert.Equal(t, sessionID.String(), preemptedBy.SessionID)
+	assert.Equal(t, "Sport", preemptedBy.Channel)
+
+	// The preempted session no longer holds the source's connection
+	assert.False(t, manager.sourceAtLimit(up))
+	assert.Nil(t, manager.preemptForConnection(models.NewULID(), "Films", up))
+}
+
+// closeRecorder is an io.ReadCloser that records whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func TestRelaySession_PreemptStopsInput(t *testing.T) {
+	manager := NewManager(DefaultManagerConfig())
+	defer manager.Close()
+
+	t.Run("closes the input being ingested", func(t *testing.T) {
+		session := newFailoverTestSession(t, manager)
+		reader := &closeRecorder{Reader: strings.NewReader("")}
+		session.setInputReader(reader)
+		assert.False(t, reader.closed.Load())
+
+		session.preempt(PreemptionInfo{Policy: models.ConnectionLimitPolicyPreemptOldest})
+		assert.True(t, reader.closed.Load())
+	})
+
+	t.Run("closes input connected after preemption", func(t *testing.T) {
+		session := newFailoverTestSession(t, manager)
+		session.preempt(PreemptionInfo{Policy: models.ConnectionLimitPolicyPreemptOldest})
+
+		reader := &closeRecorder{Reader: strings.NewReader("")}
+		session.setInputReader(reader)
+		assert.True(t, reader.closed.Load())
+
+		collapser := NewHLSCollapser(nil, "http://example.com/live.m3u8")
+		assert.ErrorIs(t, session.startHLSCollapser(collapser), ErrSessionPreempted)
+	})
+}
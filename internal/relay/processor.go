@@ -89,6 +89,7 @@ type ProcessorClient struct {
 	ConnectedAt  time.Time
 	LastActivity time.Time // For request-based protocols like HLS
 	BytesRead    atomic.Uint64
+	Viewing      *ViewingInfo // Who the client is, if registered by the stream handler
 	writer       io.Writer
 	flusher      http.Flusher
 	done         chan struct{}
@@ -105,6 +106,7 @@ func NewProcessorClient(id string, w http.ResponseWriter, r *http.Request) *Proc
 		RemoteAddr:   r.RemoteAddr,
 		ConnectedAt:  now,
 		LastActivity: now,
+		Viewing:      viewingInfoFromContext(r.Context()),
 		writer:       w,
 		flusher:      flusher,
 		done:         make(chan struct{}),
@@ -163,6 +165,9 @@ type BaseProcessor struct {
 
 	// Bandwidth tracking for buffer-to-processor edge
 	bandwidthTracker *BandwidthTracker
+
+	// onClientRemoved is called with each client removed, and when it left
+	onClientRemoved func(client *ProcessorClient, endedAt time.Time)
 }
 
 // NewBaseProcessor creates a new base processor.
@@ -197,6 +202,23 @@ func (p *BaseProcessor) SetBandwidthTracker(tracker *BandwidthTracker) {
 	p.bandwidthTracker = tracker
 }
 
+// SetClientRemovedHook sets a function called, outside the processor's locks,
+// for each client removed by unregistering, inactivity cleanup or Close.
+func (p *BaseProcessor) SetClientRemovedHook(fn func(client *ProcessorClient, endedAt time.Time)) {
+	p.onClientRemoved = fn
+}
+
+// clientsRemoved calls the client removed hook for each client. The hook is
+// set before the processor is published, so it is only read once clients exist.
+func (p *BaseProcessor) clientsRemoved(clients []*ProcessorClient, endedAt func(*ProcessorClient) time.Time) {
+	if len(clients) == 0 || p.onClientRemoved == nil {
+		return
+	}
+	for _, client := range clients {
+		p.onClientRemoved(client, endedAt(client))
+	}
+}
+
 // TrackBytesFromBuffer records bytes read from the ES buffer.
 // This should be called after reading samples from the buffer.
 func (p *BaseProcessor) TrackBytesFromBuffer(bytes uint64) {
@@ -274,13 +296,18 @@ func (p *BaseProcessor) RegisterClientBase(clientID string, w http.ResponseWrite
 // UnregisterClientBase removes a client.
 func (p *BaseProcessor) UnregisterClientBase(clientID string) {
 	p.clientsMu.Lock()
-	if client, exists := p.clients[clientID]; exists {
+	client, exists := p.clients[clientID]
+	if exists {
 		client.Close()
 		delete(p.clients, clientID)
 	}
 	p.clientsMu.Unlock()
 
-	p.lastActivity.Store(time.Now())
+	now := time.Now()
+	p.lastActivity.Store(now)
+	if exists {
+		p.clientsRemoved([]*ProcessorClient{client}, func(*ProcessorClient) time.Time { return now })
+	}
 }
 
 // CleanupInactiveClients removes clients that haven't had activity within the timeout duration.
@@ -288,10 +315,8 @@ func (p *BaseProcessor) UnregisterClientBase(clientID string) {
 // but don't maintain a persistent connection.
 func (p *BaseProcessor) CleanupInactiveClients(timeout time.Duration) int {
 	p.clientsMu.Lock()
-	defer p.clientsMu.Unlock()
-
 	now := time.Now()
-	removed := 0
+	var removed []*ProcessorClient
 
 	for id, client := range p.clients {
 		client.mu.Lock()
@@ -307,11 +332,18 @@ func (p *BaseProcessor) CleanupInactiveClients(timeout time.Duration) int {
 				slog.Duration("timeout", timeout))
 			client.Close()
 			delete(p.clients, id)
-			removed++
+			removed = append(removed, client)
 		}
 	}
+	p.clientsMu.Unlock()
 
-	return removed
+	// Inactive clients left after their last request
+	p.clientsRemoved(removed, func(c *ProcessorClient) time.Time {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.LastActivity
+	})
+	return len(removed)
 }
 
 // ClientCount returns the number of connected clients.
@@ -378,11 +410,16 @@ func (p *BaseProcessor) Close() {
 
 		// Close all clients
 		p.clientsMu.Lock()
+		removed := make([]*ProcessorClient, 0, len(p.clients))
 		for _, client := range p.clients {
 			client.Close()
+			removed = append(removed, client)
 		}
 		p.clients = make(map[string]*ProcessorClient)
 		p.clientsMu.Unlock()
+
+		now := time.Now()
+		p.clientsRemoved(removed, func(*ProcessorClient) time.Time { return now })
 	}
 }
 
@@ -42,6 +42,9 @@ type DASHProcessorConfig struct {
 	// MinBufferTime in seconds for the MPD.
 	MinBufferTime float64
 
+	// Timeshift keeps segments on disk for a DVR window. Disabled if zero.
+	Timeshift TimeshiftConfig
+
 	// Logger for structured logging.
 	Logger *slog.Logger
 }
@@ -84,6 +87,9 @@ type DASHProcessor struct {
 	nextSequence  uint64
 	segmentNotify chan struct{} // Notifies waiters when new segment is added
 
+	// Disk-backed DVR window, set by Start and read by client handlers (nil if timeshift is disabled)
+	timeshift atomic.Pointer[TimeshiftStore]
+
 	// Current segment accumulator
 	currentSegment struct {
 		startPTS     int64
@@ -205,6 +211,8 @@ func (p *DASHProcessor) Start(ctx context.Context) error {
 	// Initialize segment accumulator
 	p.initNewSegment()
 
+	p.timeshift.Store(openTimeshift(p.config.Timeshift, p.config.Logger, p.id))
+
 	p.config.Logger.Debug("Starting DASH processor",
 		slog.String("id", p.id),
 		slog.String("variant", p.Variant().String()))
@@ -220,6 +228,7 @@ func (p *DASHProcessor) Start(ctx context.Context) error {
 // Stop stops the processor and cleans up resources.
 func (p *DASHProcessor) Stop() {
 	p.StopES()
+	closeTimeshift(p.timeshift.Load(), p.config.Logger, p.id)
 }
 
 // RegisterClient adds a client to receive output from this processor.
@@ -516,9 +525,14 @@ func (p *DASHProcessor) GetFilteredInitSegment(trackType string) ([]byte, error)
 }
 
 // GetSegmentInfos implements SegmentProvider.
-// Returns only the latest PlaylistSegments segments so new clients start near live edge.
+// Returns only the latest PlaylistSegments segments so new clients start near live edge,
+// or the whole DVR window if timeshift is enabled.
 // If we don't have enough real segments yet, returns placeholder segment info to allow manifest generation.
 func (p *DASHProcessor) GetSegmentInfos() []SegmentInfo {
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.SegmentInfos()
+	}
+
 	p.segmentsMu.RLock()
 	segmentCount := len(p.segments)
 	p.segmentsMu.RUnlock()
@@ -574,6 +588,14 @@ func (p *DASHProcessor) getSegmentWithWait(sequence uint64, maxWait time.Duratio
 		return seg, nil
 	}
 
+	// Segments evicted from memory may still be in the DVR window
+	if ts := p.timeshift.Load(); ts != nil {
+		if seg, err := ts.Get(sequence); err == nil {
+			seg.IsFragmented = true
+			return seg, nil
+		}
+	}
+
 	// Get buffer state for decision making
 	p.segmentsMu.RLock()
 	bufferCount := len(p.segments)
@@ -703,6 +725,14 @@ func (p *DASHProcessor) isSegmentInExpectedRange(sequence, minSeq, maxSeq, nextS
 	return true
 }
 
+// TimeshiftWindow implements TimeshiftProvider.
+func (p *DASHProcessor) TimeshiftWindow() time.Duration {
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.Window()
+	}
+	return 0
+}
+
 // TargetDuration implements SegmentProvider.
 func (p *DASHProcessor) TargetDuration() int {
 	return int(p.config.TargetSegmentDuration + 0.5)
@@ -1136,6 +1166,16 @@ func (p *DASHProcessor) flushSegment(videoSamples, audioSamples []ESSample) {
 	}
 	p.segmentsMu.Unlock()
 
+	if ts := p.timeshift.Load(); ts != nil {
+		appendTimeshift(ts, p.config.Logger, p.id, &Segment{
+			Sequence:     seg.sequence,
+			Duration:     seg.duration,
+			Data:         seg.data,
+			Timestamp:    seg.createdAt,
+			IsFragmented: true,
+		})
+	}
+
 	// Notify waiters that a new segment is available
 	select {
 	case p.segmentNotify <- struct{}{}:
@@ -41,6 +41,9 @@ type HLSfMP4ProcessorConfig struct {
 	// PlaylistType is the HLS playlist type (EVENT or VOD, empty for live).
 	PlaylistType string
 
+	// Timeshift keeps segments on disk for a DVR window. Disabled if zero.
+	Timeshift TimeshiftConfig
+
 	// Logger for structured logging.
 	Logger *slog.Logger
 }
@@ -84,6 +87,9 @@ type HLSfMP4Processor struct {
 	nextSequence  uint64
 	segmentNotify chan struct{} // Notifies waiters when new segment is added
 
+	// Disk-backed DVR window, set by Start and read by client handlers (nil if timeshift is disabled)
+	timeshift atomic.Pointer[TimeshiftStore]
+
 	// Playlist activity tracking - used to determine if clients are still watching.
 	// HLS clients poll the playlist periodically; if no polls for a while, they've left.
 	lastPlaylistRequest atomic.Value // time.Time
@@ -97,6 +103,8 @@ type HLSfMP4Processor struct {
 		hasAudio  bool
 		startTime time.Time
 		samples   int // Number of samples in current segment
+
+		discontinuity bool // Segment follows an upstream switch
 	}
 
 	// fMP4 muxer using mediacommon
@@ -198,6 +206,8 @@ func (p *HLSfMP4Processor) Start(ctx context.Context) error {
 	// Initialize segment accumulator
 	p.initNewSegment()
 
+	p.timeshift.Store(openTimeshift(p.config.Timeshift, p.config.Logger, p.id))
+
 	p.config.Logger.Debug("Starting HLS-fMP4 processor",
 		slog.String("id", p.id),
 		slog.String("variant", p.Variant().String()))
@@ -213,6 +223,7 @@ func (p *HLSfMP4Processor) Start(ctx context.Context) error {
 // Stop stops the processor and cleans up resources.
 func (p *HLSfMP4Processor) Stop() {
 	p.StopES()
+	closeTimeshift(p.timeshift.Load(), p.config.Logger, p.id)
 }
 
 // RegisterClient adds a client to receive output from this processor.
@@ -413,8 +424,13 @@ func (p *HLSfMP4Processor) GetFilteredInitSegment(trackType string) ([]byte, err
 }
 
 // GetSegmentInfos implements SegmentProvider.
-// Returns only the latest PlaylistSegments segments so new clients start near live edge.
+// Returns only the latest PlaylistSegments segments so new clients start near live edge,
+// or the whole DVR window if timeshift is enabled.
 func (p *HLSfMP4Processor) GetSegmentInfos() []SegmentInfo {
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.SegmentInfos()
+	}
+
 	p.segmentsMu.RLock()
 	defer p.segmentsMu.RUnlock()
 
@@ -439,9 +455,10 @@ func (p *HLSfMP4Processor) GetSegmentInfos() []SegmentInfo {
 	var playlistSeqs []uint64
 	for i, seg := range latestSegments {
 		infos[i] = SegmentInfo{
-			Sequence:  seg.sequence,
-			Duration:  seg.duration,
-			Timestamp: seg.createdAt,
+			Sequence:      seg.sequence,
+			Duration:      seg.duration,
+			Timestamp:     seg.createdAt,
+			Discontinuity: seg.discontinue,
 		}
 		playlistSeqs = append(playlistSeqs, seg.sequence)
 	}
@@ -464,21 +481,18 @@ func (p *HLSfMP4Processor) GetSegmentInfos() []SegmentInfo {
 }
 
 // GetSegment implements SegmentProvider.
+// Segments evicted from memory are read from the timeshift store if enabled.
 func (p *HLSfMP4Processor) GetSegment(sequence uint64) (*Segment, error) {
+	if seg := p.findSegment(sequence); seg != nil {
+		return seg, nil
+	}
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.Get(sequence)
+	}
+
 	p.segmentsMu.RLock()
 	defer p.segmentsMu.RUnlock()
 
-	for _, seg := range p.segments {
-		if seg.sequence == sequence {
-			return &Segment{
-				Sequence:  seg.sequence,
-				Duration:  seg.duration,
-				Data:      seg.data,
-				Timestamp: seg.createdAt,
-			}, nil
-		}
-	}
-
 	// Log buffer state when segment not found for debugging
 	var availableSeqs []uint64
 	for _, seg := range p.segments {
@@ -493,6 +507,32 @@ func (p *HLSfMP4Processor) GetSegment(sequence uint64) (*Segment, error) {
 	return nil, ErrSegmentNotFound
 }
 
+// findSegment looks up an in-memory segment by sequence number.
+func (p *HLSfMP4Processor) findSegment(sequence uint64) *Segment {
+	p.segmentsMu.RLock()
+	defer p.segmentsMu.RUnlock()
+
+	for _, seg := range p.segments {
+		if seg.sequence == sequence {
+			return &Segment{
+				Sequence:  seg.sequence,
+				Duration:  seg.duration,
+				Data:      seg.data,
+				Timestamp: seg.createdAt,
+			}
+		}
+	}
+	return nil
+}
+
+// TimeshiftWindow implements TimeshiftProvider.
+func (p *HLSfMP4Processor) TimeshiftWindow() time.Duration {
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.Window()
+	}
+	return 0
+}
+
 // TargetDuration implements SegmentProvider.
 func (p *HLSfMP4Processor) TargetDuration() int {
 	return int(p.config.TargetSegmentDuration + 0.5)
@@ -507,6 +547,7 @@ func (p *HLSfMP4Processor) initNewSegment() {
 	p.currentSegment.hasAudio = false
 	p.currentSegment.startTime = time.Now()
 	p.currentSegment.samples = 0
+	p.currentSegment.discontinuity = false
 }
 
 // runProcessingLoop is the main processing loop.
@@ -599,6 +640,15 @@ func (p *HLSfMP4Processor) runProcessingLoop(esVariant *ESVariant) {
 			var bytesRead uint64
 			for _, sample := range newVideoSamples {
 				bytesRead += uint64(len(sample.Data))
+				// An upstream switch always starts a new, discontinuous segment
+				if sample.Discontinuity {
+					if len(videoSamples) > 0 && p.flushSegment(videoSamples, audioSamples) {
+						p.initNewSegment()
+						videoSamples = nil
+						audioSamples = nil
+					}
+					p.currentSegment.discontinuity = true
+				}
 				// Check if this keyframe should trigger a new segment
 				if sample.IsKeyframe && len(videoSamples) > 0 && p.hasEnoughContent() {
 					// Only reset samples if flush succeeded; if deferred, keep accumulating
@@ -622,6 +672,9 @@ func (p *HLSfMP4Processor) runProcessingLoop(esVariant *ESVariant) {
 			newAudioSamples := audioTrack.ReadFrom(p.LastAudioSeq(), 200)
 			for _, sample := range newAudioSamples {
 				bytesRead += uint64(len(sample.Data))
+				if sample.Discontinuity && !p.currentSegment.hasVideo {
+					p.currentSegment.discontinuity = true
+				}
 				audioSamples = append(audioSamples, sample)
 				p.SetLastAudioSeq(sample.Sequence)
 				p.currentSegment.hasAudio = true
@@ -771,12 +824,13 @@ func (p *HLSfMP4Processor) flushSegment(videoSamples, audioSamples []ESSample) b
 
 	// Create segment
 	seg := &hlsFMP4Segment{
-		sequence:  p.nextSequence,
-		duration:  duration,
-		data:      fragmentData,
-		ptsStart:  p.currentSegment.startPTS,
-		ptsEnd:    p.currentSegment.endPTS,
-		createdAt: time.Now(),
+		sequence:    p.nextSequence,
+		duration:    duration,
+		data:        fragmentData,
+		ptsStart:    p.currentSegment.startPTS,
+		ptsEnd:      p.currentSegment.endPTS,
+		discontinue: p.currentSegment.discontinuity,
+		createdAt:   time.Now(),
 	}
 	p.nextSequence++
 
@@ -807,6 +861,16 @@ func (p *HLSfMP4Processor) flushSegment(videoSamples, audioSamples []ESSample) b
 	bufferSize := len(p.segments)
 	p.segmentsMu.Unlock()
 
+	if ts := p.timeshift.Load(); ts != nil {
+		appendTimeshift(ts, p.config.Logger, p.id, &Segment{
+			Sequence:      seg.sequence,
+			Duration:      seg.duration,
+			Data:          seg.data,
+			Timestamp:     seg.createdAt,
+			Discontinuity: seg.discontinue,
+		})
+	}
+
 	// Notify waiters that a new segment is available
 	select {
 	case p.segmentNotify <- struct{}{}:
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -36,6 +37,9 @@ type HLSTSProcessorConfig struct {
 	// PlaylistType is the HLS playlist type (EVENT or VOD, empty for live).
 	PlaylistType string
 
+	// Timeshift keeps segments on disk for a DVR window. Disabled if zero.
+	Timeshift TimeshiftConfig
+
 	// Logger for structured logging.
 	Logger *slog.Logger
 }
@@ -74,17 +78,21 @@ type HLSTSProcessor struct {
 	nextSequence  uint64
 	segmentNotify chan struct{} // Notifies waiters when new segment is added
 
+	// Disk-backed DVR window, set by Start and read by client handlers (nil if timeshift is disabled)
+	timeshift atomic.Pointer[TimeshiftStore]
+
 	// Persistent muxer - shared across all segments to maintain continuity counters
 	muxer           *TSMuxer
 	swappableWriter *SwappableWriter
 
 	// Current segment accumulator
 	currentSegment struct {
-		buf       bytes.Buffer
-		startPTS  int64
-		hasVideo  bool
-		hasAudio  bool
-		startTime time.Time
+		buf           bytes.Buffer
+		startPTS      int64
+		hasVideo      bool
+		hasAudio      bool
+		startTime     time.Time
+		discontinuity bool // Segment follows an upstream switch
 	}
 
 	// Video parameter helper - persists across segments to retain SPS/PPS
@@ -134,6 +142,8 @@ func (p *HLSTSProcessor) Start(ctx context.Context) error {
 	// Initialize TS muxer for current segment
 	p.initNewSegment()
 
+	p.timeshift.Store(openTimeshift(p.config.Timeshift, p.config.Logger, p.id))
+
 	p.config.Logger.Debug("Starting HLS-TS processor",
 		slog.String("id", p.id),
 		slog.String("requested_variant", p.Variant().String()),
@@ -153,6 +163,7 @@ func (p *HLSTSProcessor) Start(ctx context.Context) error {
 // Stop stops the processor and cleans up resources.
 func (p *HLSTSProcessor) Stop() {
 	p.StopES()
+	closeTimeshift(p.timeshift.Load(), p.config.Logger, p.id)
 }
 
 // RegisterClient adds a client to receive output from this processor.
@@ -301,8 +312,13 @@ func (p *HLSTSProcessor) ServeSegment(w http.ResponseWriter, r *http.Request, se
 }
 
 // GetSegmentInfos implements SegmentProvider.
-// Returns only the latest PlaylistSegments segments so new clients start near live edge.
+// Returns only the latest PlaylistSegments segments so new clients start near live edge,
+// or the whole DVR window if timeshift is enabled.
 func (p *HLSTSProcessor) GetSegmentInfos() []SegmentInfo {
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.SegmentInfos()
+	}
+
 	p.segmentsMu.RLock()
 	defer p.segmentsMu.RUnlock()
 
@@ -326,21 +342,22 @@ func (p *HLSTSProcessor) GetSegmentInfos() []SegmentInfo {
 	infos := make([]SegmentInfo, len(latestSegments))
 	for i, seg := range latestSegments {
 		infos[i] = SegmentInfo{
-			Sequence:  seg.sequence,
-			Duration:  seg.duration,
-			Timestamp: seg.createdAt,
+			Sequence:      seg.sequence,
+			Duration:      seg.duration,
+			Timestamp:     seg.createdAt,
+			Discontinuity: seg.discontinue,
 		}
 	}
 	return infos
 }
 
 // GetSegment implements SegmentProvider.
+// Segments evicted from memory are read from the timeshift store if enabled.
 func (p *HLSTSProcessor) GetSegment(sequence uint64) (*Segment, error) {
 	p.segmentsMu.RLock()
-	defer p.segmentsMu.RUnlock()
-
 	for _, seg := range p.segments {
 		if seg.sequence == sequence {
+			p.segmentsMu.RUnlock()
 			return &Segment{
 				Sequence:  seg.sequence,
 				Duration:  seg.duration,
@@ -349,10 +366,22 @@ func (p *HLSTSProcessor) GetSegment(sequence uint64) (*Segment, error) {
 			}, nil
 		}
 	}
+	p.segmentsMu.RUnlock()
 
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.Get(sequence)
+	}
 	return nil, ErrSegmentNotFound
 }
 
+// TimeshiftWindow implements TimeshiftProvider.
+func (p *HLSTSProcessor) TimeshiftWindow() time.Duration {
+	if ts := p.timeshift.Load(); ts != nil {
+		return ts.Window()
+	}
+	return 0
+}
+
 // TargetDuration implements SegmentProvider.
 func (p *HLSTSProcessor) TargetDuration() int {
 	return int(p.config.TargetSegmentDuration + 0.5)
@@ -365,6 +394,7 @@ func (p *HLSTSProcessor) initNewSegment() {
 	p.currentSegment.hasVideo = false
 	p.currentSegment.hasAudio = false
 	p.currentSegment.startTime = time.Now()
+	p.currentSegment.discontinuity = false
 
 	// Create the persistent muxer on first call, reuse thereafter
 	// This maintains continuity counters across segments
@@ -447,8 +477,21 @@ func (p *HLSTSProcessor) processAvailableSamples(videoTrack, audioTrack *ESTrack
 	}
 }
 
+// startDiscontinuity cuts the current segment at an upstream switch so the
+// next segment can be flagged as discontinuous.
+func (p *HLSTSProcessor) startDiscontinuity() {
+	if p.currentSegment.hasVideo || p.currentSegment.hasAudio {
+		p.flushSegment()
+		p.initNewSegment()
+	}
+	p.currentSegment.discontinuity = true
+}
+
 // processVideoSample processes a single video sample.
 func (p *HLSTSProcessor) processVideoSample(sample ESSample) {
+	if sample.Discontinuity {
+		p.startDiscontinuity()
+	}
 	if p.currentSegment.startPTS < 0 {
 		p.currentSegment.startPTS = sample.PTS
 	}
@@ -470,6 +513,9 @@ func (p *HLSTSProcessor) processVideoSample(sample ESSample) {
 
 // processAudioSample processes a single audio sample.
 func (p *HLSTSProcessor) processAudioSample(sample ESSample) {
+	if sample.Discontinuity {
+		p.startDiscontinuity()
+	}
 	if p.currentSegment.startPTS < 0 {
 		p.currentSegment.startPTS = sample.PTS
 	}
@@ -520,11 +566,12 @@ func (p *HLSTSProcessor) flushSegment() {
 
 	// Create segment
 	seg := &hlsTSSegment{
-		sequence:  p.nextSequence,
-		duration:  duration,
-		data:      append([]byte(nil), p.currentSegment.buf.Bytes()...), // Copy data
-		ptsStart:  p.currentSegment.startPTS,
-		createdAt: time.Now(),
+		sequence:    p.nextSequence,
+		duration:    duration,
+		data:        append([]byte(nil), p.currentSegment.buf.Bytes()...), // Copy data
+		ptsStart:    p.currentSegment.startPTS,
+		discontinue: p.currentSegment.discontinuity,
+		createdAt:   time.Now(),
 	}
 	p.nextSequence++
 
@@ -537,6 +584,16 @@ func (p *HLSTSProcessor) flushSegment() {
 	}
 	p.segmentsMu.Unlock()
 
+	if ts := p.timeshift.Load(); ts != nil {
+		appendTimeshift(ts, p.config.Logger, p.id, &Segment{
+			Sequence:      seg.sequence,
+			Duration:      seg.duration,
+			Data:          seg.data,
+			Timestamp:     seg.createdAt,
+			Discontinuity: seg.discontinue,
+		})
+	}
+
 	// Notify waiters that a new segment is available
 	select {
 	case p.segmentNotify <- struct{}{}:
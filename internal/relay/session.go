@@ -17,6 +17,8 @@ import (
 	"github.com/jmylchreest/tvarr/internal/models"
 	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Variant cleanup configuration
@@ -161,6 +163,7 @@ type ProcessorConfig struct {
 	TargetSegmentDuration float64
 	MaxSegments           int
 	PlaylistSegments      int // Number of segments in playlist for new clients (near live edge)
+	Timeshift             TimeshiftConfig
 }
 
 // RelaySession represents an active relay session.
@@ -171,12 +174,20 @@ type RelaySession struct {
 	SourceID                   models.ULID // ID of the stream source (for connection tracking)
 	StreamSourceName           string      // Name of the stream source (e.g., "s8k")
 	StreamURL                  string
-	SourceMaxConcurrentStreams int    // Max concurrent streams for the source (0 = unlimited)
-	SourceUserAgent            string // User-Agent to use for upstream requests (empty = tvarr default)
+	SourceMaxConcurrentStreams int             // Max concurrent streams for the source (0 = unlimited)
+	SourceOptions              UpstreamOptions // User-Agent, proxy and headers for upstream requests
 	EncodingProfile            *models.EncodingProfile
 	Classification             ClassificationResult
 	CachedCodecInfo            *models.LastKnownCodec // Pre-probed codec info for faster startup
 	StartedAt                  time.Time
+	Priority                   int // Priority of the proxy the session was started for
+
+	// Connection limit decisions: the session this one preempted when it
+	// started, and the session that preempted this one
+	preempted   *PreemptionInfo
+	preemptedBy atomic.Pointer[PreemptionInfo]
+	// Recordings capturing the session, which keep it from being preempted
+	recordings atomic.Int32
 
 	// Use atomic values for frequently updated fields to avoid mutex contention
 	// These are updated by the ingest loop on every read, which would block stats collection
@@ -227,21 +238,45 @@ type RelaySession struct {
 	// Processor lifecycle configuration
 	processorIdleGracePeriods ProcessorIdleGracePeriods
 
+	// Upstream failover: the primary upstream first, then alternates in failover
+	// order. StreamURL stays the primary's URL and identifies the session.
+	upstreams      []sessionUpstream
+	activeUpstream int
+	failovers      int
+	upstreamMu     sync.RWMutex // Also guards tsDemuxer, which failover replaces
+
 	// Legacy fields - set once during pipeline init, read-only afterward
 	// Protected by readyCh synchronization (readers wait for ready before accessing)
-	ffmpegCmd    *ffmpeg.Command // Running FFmpeg command for stats access
+	ffmpegCmd *ffmpeg.Command // Running FFmpeg command for stats access
+
+	// The upstream input being ingested. Preemption and Close stop it from
+	// other goroutines while the ingest goroutine replaces it.
+	inputMu      sync.Mutex
 	hlsCollapser *HLSCollapser
 	inputReader  io.ReadCloser
 
 	// Pipeline readiness signaling
 	readyCh   chan struct{} // Closed when pipeline is ready for clients
 	readyOnce sync.Once     // Ensures readyCh is closed only once
+
+	// spanContext is the span that created the session. Startup work that runs
+	// on the session's own context, such as starting transcoders, is traced as
+	// part of the request that created it.
+	spanContext trace.SpanContext
+}
+
+// traceContext returns the session's context with the span that created the
+// session, for spans of work started on the session's behalf.
+func (s *RelaySession) traceContext() context.Context {
+	return trace.ContextWithSpanContext(s.ctx, s.spanContext)
 }
 
 // start begins the relay session.
 func (s *RelaySession) start(ctx context.Context) error {
 	// Acquire connection slot
+	_, span := observability.StartSpan(trace.ContextWithSpanContext(ctx, s.spanContext), "relay.connection.acquire")
 	release, err := s.manager.connectionPool.Acquire(ctx, s.StreamURL)
+	observability.EndSpan(span, err)
 	if err != nil {
 		return fmt.Errorf("acquiring connection: %w", err)
 	}
@@ -295,6 +330,11 @@ func (s *RelaySession) runPipeline() {
 			return
 		}
 
+		// A preempted session ends once its clients were shown the slate
+		if errors.Is(err, ErrSessionPreempted) {
+			return
+		}
+
 		// If error occurred and fallback is configured, switch to fallback
 		if err != nil && s.fallbackController != nil {
 			if s.fallbackController.CheckError(err.Error()) {
@@ -305,7 +345,7 @@ func (s *RelaySession) runPipeline() {
 
 		// No error or no fallback configured - exit
 		if err != nil {
-			cb := s.manager.circuitBreakers.Get(s.StreamURL)
+			cb := s.manager.circuitBreakers.Get(s.ActiveUpstream().StreamURL)
 			cb.RecordFailure()
 		}
 		return
@@ -324,7 +364,7 @@ func (s *RelaySession) runNormalPipeline() error {
 	s.logPipelineDecision()
 
 	// Handle special source format cases
-	switch s.Classification.Mode {
+	switch s.activeSessionUpstream().classification.Mode {
 	case StreamModeCollapsedHLS:
 		return s.runHLSCollapsePipeline()
 	case StreamModePassthroughHLS, StreamModeTransparentHLS, StreamModePassthroughDASH:
@@ -463,6 +503,9 @@ func (s *RelaySession) runRecoveryLoop(done <-chan bool) {
 
 			// Create a new context for the resumed pipeline
 			s.ctx, s.cancel = context.WithCancel(s.manager.ctx)
+
+			// Recovery was checked against the primary, so resume from it
+			s.resumePrimaryUpstream()
 			return
 		}
 
@@ -480,7 +523,7 @@ func (s *RelaySession) testUpstreamRecovery() bool {
 		return false
 	}
 
-	resp, err := s.manager.config.HTTPClient.Do(req)
+	resp, err := s.manager.UpstreamClient(s.SourceOptions).Do(req)
 	if err != nil {
 		return false
 	}
@@ -500,15 +543,11 @@ func (s *RelaySession) testUpstreamRecovery() bool {
 // runHLSCollapsePipeline runs the HLS collapsing pipeline.
 // This uses the ES pipeline to demux collapsed HLS content and remux for output.
 func (s *RelaySession) runHLSCollapsePipeline() error {
-	playlistURL := s.StreamURL
-	if s.Classification.SelectedMediaPlaylist != "" {
-		playlistURL = s.Classification.SelectedMediaPlaylist
-	}
+	upstream := s.activeSessionUpstream()
+	playlistURL := upstream.inputURL()
 
-	collapser := NewHLSCollapser(s.manager.config.HTTPClient, playlistURL)
-	s.hlsCollapser = collapser
-
-	if err := collapser.Start(s.ctx); err != nil {
+	collapser := NewHLSCollapser(s.manager.UpstreamClient(upstream.UpstreamOptions), playlistURL)
+	if err := s.startHLSCollapser(collapser); err != nil {
 		return fmt.Errorf("starting HLS collapser: %w", err)
 	}
 
@@ -561,6 +600,7 @@ func (s *RelaySession) runHLSCollapsePipeline() error {
 	hlsTSConfig.TargetSegmentDuration = targetSegmentDuration
 	hlsTSConfig.MaxSegments = maxSegments
 	hlsTSConfig.PlaylistSegments = playlistSegments
+	hlsTSConfig.Timeshift = s.manager.config.HLSConfig.Timeshift
 
 	hlsTSProcessor := NewHLSTSProcessor(
 		fmt.Sprintf("hls-ts-%s-%s", s.ID.String(), VariantSource.String()),
@@ -571,7 +611,7 @@ func (s *RelaySession) runHLSCollapsePipeline() error {
 	if err := hlsTSProcessor.Start(s.ctx); err != nil {
 		return fmt.Errorf("starting HLS-TS processor: %w", err)
 	}
-	s.configureProcessorStreamContext(hlsTSProcessor.BaseProcessor)
+	s.configureProcessorStreamContext(hlsTSProcessor.ESProcessorBase)
 	hlsTSProcessor.SetBandwidthTracker(s.edgeBandwidth.GetOrCreateProcessorTracker("hls"))
 	s.hlsTSProcessors.Store(VariantSource, hlsTSProcessor)
 
@@ -587,7 +627,7 @@ func (s *RelaySession) runHLSCollapsePipeline() error {
 	if err := mpegtsProcessor.Start(s.ctx); err != nil {
 		slog.Warn("Failed to start MPEG-TS processor", slog.String("error", err.Error()))
 	} else {
-		s.configureProcessorStreamContext(mpegtsProcessor.BaseProcessor)
+		s.configureProcessorStreamContext(mpegtsProcessor.ESProcessorBase)
 		mpegtsProcessor.SetBandwidthTracker(s.edgeBandwidth.GetOrCreateProcessorTracker("mpegts"))
 		s.mpegtsProcessors.Store(VariantSource, mpegtsProcessor)
 	}
@@ -606,37 +646,11 @@ func (s *RelaySession) runHLSCollapsePipeline() error {
 		slog.String("session_id", s.ID.String()),
 		slog.String("playlist_url", playlistURL))
 
-	// Read from collapser and feed to demuxer
-	buf := make([]byte, 64*1024)
-	for {
-		select {
-		case <-s.ctx.Done():
-			collapser.Stop()
-			s.tsDemuxer.Flush()
-			return s.ctx.Err()
-		default:
-		}
-
-		n, err := collapser.Read(buf)
-		if err != nil {
-			if errors.Is(err, io.EOF) || errors.Is(err, ErrCollapserAborted) {
-				s.tsDemuxer.Flush()
-				return nil
-			}
-			return err
-		}
-
-		if n > 0 {
-			// Track bytes ingested from origin (HLS collapse pipeline)
-			s.edgeBandwidth.OriginToBuffer.Add(uint64(n))
-
-			if err := s.tsDemuxer.Write(buf[:n]); err != nil {
-				slog.Warn("Demuxer error", slog.String("error", err.Error()))
-			}
-			// Use atomic store to avoid blocking stats collection with mutex
-			s.lastActivity.Store(time.Now())
-		}
-	}
+	// Read from collapser and feed to demuxer, switching upstream on failure
+	demuxer := s.tsDemuxer
+	return s.ingestWithFailover(func() error {
+		return s.readCollapser(collapser, demuxer)
+	})
 }
 
 // getTargetVariant returns the target codec variant based on the profile settings.
@@ -749,10 +763,8 @@ func (s *RelaySession) runESPipeline() error {
 	s.tsDemuxer = NewTSDemuxer(s.esBuffer, demuxerConfig)
 
 	// Determine the source URL
-	inputURL := s.StreamURL
-	if s.Classification.SelectedMediaPlaylist != "" {
-		inputURL = s.Classification.SelectedMediaPlaylist
-	}
+	inputURL := s.activeSessionUpstream().inputURL()
+	demuxer := s.tsDemuxer
 
 	// Use HLS config from manager for segment settings
 	targetSegmentDuration := s.manager.config.HLSConfig.TargetSegmentDuration
@@ -767,7 +779,9 @@ func (s *RelaySession) runESPipeline() error {
 	// which will detect codecs and create the source variant
 	ingestErrCh := make(chan error, 1)
 	go func() {
-		ingestErrCh <- s.runIngestLoop(inputURL, s.tsDemuxer)
+		ingestErrCh <- s.ingestWithFailover(func() error {
+			return s.runIngestLoop(inputURL, demuxer)
+		})
 	}()
 
 	// Wait for EITHER the source variant to be ready OR ingest to fail
@@ -841,6 +855,7 @@ func (s *RelaySession) runESPipeline() error {
 		TargetSegmentDuration: targetSegmentDuration,
 		MaxSegments:           maxSegments,
 		PlaylistSegments:      playlistSegments,
+		Timeshift:             s.manager.config.HLSConfig.Timeshift,
 	}
 
 	// Set up format router WITHOUT pre-created processors
@@ -885,14 +900,12 @@ func (s *RelaySession) runIngestLoop(inputURL string, demuxer *TSDemuxer) error
 		return err
 	}
 
-	// Set User-Agent header - use source-specific UA if configured, otherwise tvarr default
-	if s.SourceUserAgent != "" {
-		req.Header.Set("User-Agent", s.SourceUserAgent)
-	} else {
-		req.Header.Set("User-Agent", version.UserAgent())
-	}
+	// Set the tvarr User-Agent; the upstream client replaces it with the source's
+	// User-Agent or the stream's headers when configured
+	req.Header.Set("User-Agent", version.UserAgent())
 
-	resp, err := s.manager.config.HTTPClient.Do(req)
+	upstream := s.ActiveUpstream()
+	resp, err := s.manager.UpstreamClient(upstream.UpstreamOptions).Do(req)
 	if err != nil {
 		slog.Error("Ingest loop: HTTP request failed",
 			slog.String("session_id", s.ID.String()),
@@ -930,7 +943,7 @@ func (s *RelaySession) runIngestLoop(inputURL string, demuxer *TSDemuxer) error
 			slog.Int64("content_length", resp.ContentLength))
 	}
 
-	s.inputReader = resp.Body
+	s.setInputReader(resp.Body)
 
 	// Update last activity using atomic to avoid blocking stats collection
 	s.lastActivity.Store(time.Now())
@@ -1175,7 +1188,7 @@ func (s *RelaySession) stopTranscodersForVariants(variants map[CodecVariant]bool
 
 // handleVariantRequest is called when a processor requests a codec variant that doesn't exist.
 // It spawns a transcoder via ffmpegd (either remote daemon or local subprocess) to produce the requested variant.
-func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
+func (s *RelaySession) handleVariantRequest(source, target CodecVariant) (err error) {
 	slog.Default().Log(context.Background(), observability.LevelTrace, "handleVariantRequest called",
 		slog.String("session_id", s.ID.String()),
 		slog.String("source_variant", source.String()),
@@ -1201,6 +1214,13 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 	}
 	s.esTranscodersMu.Unlock()
 
+	ctx, span := observability.StartSpan(s.traceContext(), "relay.transcoder.start",
+		attribute.String("tvarr.session.id", s.ID.String()),
+		attribute.String("tvarr.codec.source_variant", source.String()),
+		attribute.String("tvarr.codec.target_variant", target.String()),
+	)
+	defer func() { observability.EndSpan(span, err) }()
+
 	// Initialize transcoder factory if needed
 	if s.transcoderFactory == nil {
 		// Create factory with daemon registry and stream/job managers for distributed transcoding
@@ -1221,12 +1241,10 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 	sourceAudioCodec := source.AudioCodec()
 	sourceVideoCodec := source.VideoCodec()
 	useDirectInput := false
-	sourceURL := s.StreamURL
 
-	// Use selected media playlist if available (for HLS sources)
-	if s.Classification.SelectedMediaPlaylist != "" {
-		sourceURL = s.Classification.SelectedMediaPlaylist
-	}
+	// Read from the active upstream, using its selected media playlist for HLS sources
+	upstream := s.activeSessionUpstream()
+	sourceURL := upstream.inputURL()
 
 	// Also check CachedCodecInfo - the source variant may have been created before
 	// audio was detected, so source.AudioCodec() may be empty even though we know the codec
@@ -1259,14 +1277,24 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 		useDirectInput = true
 	}
 
+	// Direct input has FFmpeg connect to the origin itself, which would bypass the
+	// source's proxy, so refuse it rather than leak the connection
+	if useDirectInput && upstream.ProxyURL != "" {
+		slog.Warn("Direct URL input would bypass the source proxy, refusing transcode",
+			slog.String("session_id", s.ID.String()),
+			slog.String("source_id", upstream.SourceID.String()),
+			slog.String("source_url", sourceURL))
+		return fmt.Errorf("codec cannot be demuxed and direct input would bypass the proxy configured for source %s", upstream.SourceID)
+	}
+
 	// If useDirectInput is needed, check if opening another connection would breach
 	// the source's max_concurrent_streams limit. Each useDirectInput transcoder needs
 	// its own connection to the source URL.
-	if useDirectInput && s.SourceMaxConcurrentStreams > 0 {
-		// Count existing active sessions for this source
-		currentConnections := s.manager.CountActiveSessionsForSource(s.SourceID)
+	if useDirectInput && upstream.MaxConcurrentStreams > 0 {
+		// Count existing connections to this source, including those used outside tvarr
+		currentConnections := s.manager.SourceConnections(upstream.SourceID)
 		// +1 for the new FFmpeg connection (useDirectInput mode)
-		if currentConnections+1 > s.SourceMaxConcurrentStreams {
+		if currentConnections+1 > upstream.MaxConcurrentStreams {
 			unsupportedCodec := ""
 			if sourceAudioCodec != "" && !codec.IsAudioDemuxable(sourceAudioCodec) {
 				unsupportedCodec = sourceAudioCodec
@@ -1274,7 +1302,7 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 				unsupportedCodec = sourceVideoCodec
 			}
 			return fmt.Errorf("UseDirectInput would connect to the origin and breach max concurrent streams (current: %d, limit: %d). Codec %s is unsupported and cannot be demuxed",
-				currentConnections, s.SourceMaxConcurrentStreams, unsupportedCodec)
+				currentConnections, upstream.MaxConcurrentStreams, unsupportedCodec)
 		}
 	}
 
@@ -1285,7 +1313,6 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 	}
 
 	var transcoder Transcoder
-	var err error
 
 	// Try to create transcoder from profile if available, otherwise use variant directly
 	// Transcoder ID includes both source and target variants for clarity:
@@ -1296,6 +1323,7 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 		// Use profile for full configuration (bitrate, preset, hwaccel, etc.)
 		// Pass target variant which may override profile's target codecs (e.g., from client detection)
 		transcoder, err = s.transcoderFactory.CreateTranscoderFromProfile(
+			ctx,
 			transcoderID,
 			s.esBuffer,
 			source,
@@ -1314,6 +1342,7 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 			slog.String("target", target.String()))
 
 		transcoder, err = s.transcoderFactory.CreateTranscoderFromVariant(
+			ctx,
 			transcoderID,
 			s.esBuffer,
 			source,
@@ -1325,8 +1354,8 @@ func (s *RelaySession) handleVariantRequest(source, target CodecVariant) error {
 		}
 	}
 
-	// Start the transcoder
-	if err := transcoder.Start(s.ctx); err != nil {
+	// Start the transcoder. ctx shares the session context's lifetime.
+	if err := transcoder.Start(ctx); err != nil {
 		return fmt.Errorf("starting transcoder: %w", err)
 	}
 
@@ -1351,12 +1380,16 @@ func (s *RelaySession) GetESBuffer() *SharedESBuffer {
 
 // configureProcessorStreamContext sets the X-Stream headers context on a processor.
 // This enables processors to include mode, decision, and version headers in responses.
-func (s *RelaySession) configureProcessorStreamContext(p *BaseProcessor) {
+// Clients leaving the processor are reported as viewings.
+func (s *RelaySession) configureProcessorStreamContext(p *ESProcessorBase) {
 	ctx := StreamContext{
 		ProxyMode: "smart", // Sessions are only used in smart mode
 		Version:   version.Version,
 	}
 	p.SetStreamContext(ctx)
+	p.SetClientRemovedHook(func(client *ProcessorClient, endedAt time.Time) {
+		s.reportViewing(p, client, endedAt)
+	})
 }
 
 // GetHLSTSProcessor returns the HLS-TS processor for the default variant if it exists.
@@ -1404,6 +1437,7 @@ func (s *RelaySession) GetOrCreateHLSTSProcessorForVariant(variant CodecVariant)
 	if s.processorConfig.PlaylistSegments > 0 {
 		config.PlaylistSegments = s.processorConfig.PlaylistSegments
 	}
+	config.Timeshift = s.processorConfig.Timeshift
 
 	processor := NewHLSTSProcessor(
 		fmt.Sprintf("hls-ts-%s-%s", s.ID.String(), variant.String()),
@@ -1415,7 +1449,7 @@ func (s *RelaySession) GetOrCreateHLSTSProcessorForVariant(variant CodecVariant)
 	if err := processor.Start(s.ctx); err != nil {
 		return nil, fmt.Errorf("starting HLS-TS processor for variant %s: %w", variant.String(), err)
 	}
-	s.configureProcessorStreamContext(processor.BaseProcessor)
+	s.configureProcessorStreamContext(processor.ESProcessorBase)
 	processor.SetBandwidthTracker(s.edgeBandwidth.GetOrCreateProcessorTracker("hls"))
 
 	// Atomically store or get existing - if another goroutine won the race, stop our duplicate
@@ -1465,6 +1499,7 @@ func (s *RelaySession) GetOrCreateHLSfMP4ProcessorForVariant(variant CodecVarian
 	if s.processorConfig.PlaylistSegments > 0 {
 		config.PlaylistSegments = s.processorConfig.PlaylistSegments
 	}
+	config.Timeshift = s.processorConfig.Timeshift
 
 	processor := NewHLSfMP4Processor(
 		fmt.Sprintf("hls-fmp4-%s-%s", s.ID.String(), variant.String()),
@@ -1476,7 +1511,7 @@ func (s *RelaySession) GetOrCreateHLSfMP4ProcessorForVariant(variant CodecVarian
 	if err := processor.Start(s.ctx); err != nil {
 		return nil, fmt.Errorf("starting HLS-fMP4 processor for variant %s: %w", variant.String(), err)
 	}
-	s.configureProcessorStreamContext(processor.BaseProcessor)
+	s.configureProcessorStreamContext(processor.ESProcessorBase)
 	processor.SetBandwidthTracker(s.edgeBandwidth.GetOrCreateProcessorTracker("hls"))
 
 	// Atomically store or get existing - if another goroutine won the race, stop our duplicate
@@ -1526,6 +1561,7 @@ func (s *RelaySession) GetOrCreateDASHProcessorForVariant(variant CodecVariant)
 	if s.processorConfig.PlaylistSegments > 0 {
 		config.PlaylistSegments = s.processorConfig.PlaylistSegments
 	}
+	config.Timeshift = s.processorConfig.Timeshift
 
 	processor := NewDASHProcessor(
 		fmt.Sprintf("dash-%s-%s", s.ID.String(), variant.String()),
@@ -1537,7 +1573,7 @@ func (s *RelaySession) GetOrCreateDASHProcessorForVariant(variant CodecVariant)
 	if err := processor.Start(s.ctx); err != nil {
 		return nil, fmt.Errorf("starting DASH processor for variant %s: %w", variant.String(), err)
 	}
-	s.configureProcessorStreamContext(processor.BaseProcessor)
+	s.configureProcessorStreamContext(processor.ESProcessorBase)
 	processor.SetBandwidthTracker(s.edgeBandwidth.GetOrCreateProcessorTracker("dash"))
 
 	// Atomically store or get existing - if another goroutine won the race, stop our duplicate
@@ -1614,7 +1650,7 @@ func (s *RelaySession) GetOrCreateMPEGTSProcessorForVariant(variant CodecVariant
 	if err := processor.Start(s.ctx); err != nil {
 		return nil, fmt.Errorf("starting MPEG-TS processor for variant %s: %w", variant.String(), err)
 	}
-	s.configureProcessorStreamContext(processor.BaseProcessor)
+	s.configureProcessorStreamContext(processor.ESProcessorBase)
 	processor.SetBandwidthTracker(s.edgeBandwidth.GetOrCreateProcessorTracker("mpegts"))
 
 	// Atomically store or get existing - if another goroutine won the race, stop our duplicate
@@ -1665,6 +1701,29 @@ func (s *RelaySession) ClientCount() int {
 	return count
 }
 
+// clientCountsByFormat returns the number of connected clients by output
+// format (hls, dash, mpegts).
+func (s *RelaySession) clientCountsByFormat() map[string]int {
+	counts := make(map[string]int)
+	s.hlsTSProcessors.Range(func(_ CodecVariant, p *HLSTSProcessor) bool {
+		counts["hls"] += p.ClientCount()
+		return true
+	})
+	s.hlsFMP4Processors.Range(func(_ CodecVariant, p *HLSfMP4Processor) bool {
+		counts["hls"] += p.ClientCount()
+		return true
+	})
+	s.dashProcessors.Range(func(_ CodecVariant, p *DASHProcessor) bool {
+		counts["dash"] += p.ClientCount()
+		return true
+	})
+	s.mpegtsProcessors.Range(func(_ CodecVariant, p *MPEGTSProcessor) bool {
+		counts["mpegts"] += p.ClientCount()
+		return true
+	})
+	return counts
+}
+
 // State returns the current session state.
 func (s *RelaySession) State() SessionState {
 	return SessionState(s.state.Load())
@@ -1896,6 +1955,41 @@ func (s *RelaySession) StopProcessorIfIdle(processorType string) {
 	s.UpdateIdleState()
 }
 
+// setInputReader records the upstream response body being ingested. The body
+// is closed at once if the session was preempted in the meantime.
+func (s *RelaySession) setInputReader(reader io.ReadCloser) {
+	s.inputMu.Lock()
+	defer s.inputMu.Unlock()
+	s.inputReader = reader
+	if s.Preempted() {
+		_ = reader.Close()
+	}
+}
+
+// startHLSCollapser starts the HLS collapser to ingest, unless the session was
+// preempted in the meantime.
+func (s *RelaySession) startHLSCollapser(collapser *HLSCollapser) error {
+	s.inputMu.Lock()
+	defer s.inputMu.Unlock()
+	if s.Preempted() {
+		return ErrSessionPreempted
+	}
+	s.hlsCollapser = collapser
+	return collapser.Start(s.ctx)
+}
+
+// stopInput closes the upstream input being ingested, ending the ingest loop.
+func (s *RelaySession) stopInput() {
+	s.inputMu.Lock()
+	defer s.inputMu.Unlock()
+	if s.hlsCollapser != nil {
+		s.hlsCollapser.Stop()
+	}
+	if s.inputReader != nil {
+		_ = s.inputReader.Close()
+	}
+}
+
 // Close closes the session and releases resources.
 func (s *RelaySession) Close() {
 	// Transition to Closing state - if already Closing or Closed, return
@@ -1917,14 +2011,7 @@ func (s *RelaySession) Close() {
 	}
 
 	s.cancel()
-
-	if s.hlsCollapser != nil {
-		s.hlsCollapser.Stop()
-	}
-
-	if s.inputReader != nil {
-		_ = s.inputReader.Close()
-	}
+	s.stopInput()
 
 	// Collect transcoders to stop WITHOUT holding the lock during Stop calls
 	// This prevents blocking other goroutines that need the lock
@@ -1960,8 +2047,11 @@ func (s *RelaySession) Close() {
 	}
 
 	// Close the TS demuxer to stop its reader goroutine
-	if s.tsDemuxer != nil {
-		s.tsDemuxer.Close()
+	s.upstreamMu.RLock()
+	demuxer := s.tsDemuxer
+	s.upstreamMu.RUnlock()
+	if demuxer != nil {
+		demuxer.Close()
 	}
 
 	if s.esBuffer != nil {
@@ -2121,11 +2211,15 @@ func (s *RelaySession) Stats() SessionStats {
 	ingestCompleted := s.IngestCompleted()
 	originConnected := !ingestCompleted && !closed
 
+	activeUpstream := s.ActiveUpstream()
 	stats := SessionStats{
 		ID:                s.ID.String(),
 		ChannelID:         s.ChannelID.String(),
 		ChannelName:       s.ChannelName,
 		StreamSourceName:  s.StreamSourceName,
+		ActiveSourceName:  activeUpstream.SourceName,
+		Account:           activeUpstream.Account,
+		FailoverCount:     s.FailoverCount(),
 		ProfileName:       profileName,
 		StreamURL:         s.StreamURL,
 		Classification:    classification.Mode.String(),
@@ -2140,6 +2234,8 @@ func (s *RelaySession) Stats() SessionStats {
 		OriginConnected:   originConnected,
 		Error:             errStr,
 		InFallback:        inFallback,
+		Preempted:         s.preempted,
+		PreemptedBy:       s.preemptedBy.Load(),
 		Clients:           clients,
 	}
 
@@ -2354,6 +2450,9 @@ type SessionStats struct {
 	ChannelID         string    `json:"channel_id"`
 	ChannelName       string    `json:"channel_name,omitempty"`
 	StreamSourceName  string    `json:"stream_source_name,omitempty"` // Name of the stream source (e.g., "s8k")
+	ActiveSourceName  string    `json:"active_source_name,omitempty"` // Source currently ingested from (differs after failover)
+	FailoverCount     int       `json:"failover_count,omitempty"`     // Upstream switches since the session started
+	Account           string    `json:"account,omitempty"`            // Account of the active source in use, if its accounts are pooled
 	ProfileName       string    `json:"profile_name,omitempty"`
 	StreamURL         string    `json:"stream_url"`
 	Classification    string    `json:"classification"`
@@ -2382,6 +2481,9 @@ type SessionStats struct {
 	FallbackEnabled          bool `json:"fallback_enabled"`
 	FallbackErrorCount       int  `json:"fallback_error_count,omitempty"`
 	FallbackRecoveryAttempts int  `json:"fallback_recovery_attempts,omitempty"`
+	// Connection limit decisions
+	Preempted   *PreemptionInfo `json:"preempted,omitempty"`    // Session stopped to free a connection for this one
+	PreemptedBy *PreemptionInfo `json:"preempted_by,omitempty"` // Session this one was stopped for
 	// FFmpeg process stats (only present when FFmpeg is running)
 	FFmpegStats *FFmpegProcessStats `json:"ffmpeg_stats,omitempty"`
 	// ES transcoder stats (present when ES-based transcoders are running)
@@ -2576,7 +2678,14 @@ func (s *RelaySession) markReady() {
 
 // WaitReady blocks until the session pipeline is ready or the context is canceled.
 // Returns nil if ready, context error if canceled, or ErrSessionClosed if session closed.
-func (s *RelaySession) WaitReady(ctx context.Context) error {
+func (s *RelaySession) WaitReady(ctx context.Context) (err error) {
+	if s.IsReady() {
+		return nil
+	}
+	_, span := observability.StartSpan(ctx, "relay.session.wait_ready",
+		attribute.String("tvarr.session.id", s.ID.String()))
+	defer func() { observability.EndSpan(span, err) }()
+
 	select {
 	case <-s.readyCh:
 		return nil
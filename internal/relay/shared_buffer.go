@@ -31,6 +31,10 @@ type ESSample struct {
 	IsKeyframe bool      // For video: IDR frame
 	Sequence   uint64    // Monotonic sequence number for ordering
 	Timestamp  time.Time // Wall clock time when sample was received
+
+	// Discontinuity marks the first sample after an upstream switch.
+	// Segment processors start a new segment flagged as discontinuous.
+	Discontinuity bool
 }
 
 // ESTrack stores elementary stream samples for a single track (video or audio).
@@ -44,6 +48,8 @@ type ESTrack struct {
 
 	lastSeq uint64 // Last sequence number assigned
 
+	discontinuityPending bool // Flag the next written sample as a discontinuity
+
 	// Byte-based size tracking - atomic for lock-free reads by eviction logic
 	currentBytes atomic.Uint64 // Current total bytes in buffer
 
@@ -102,6 +108,13 @@ func (t *ESTrack) SetCodec(codec string) {
 	t.codec = codec
 }
 
+// MarkDiscontinuity flags the next sample written to the track as a discontinuity.
+func (t *ESTrack) MarkDiscontinuity() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.discontinuityPending = true
+}
+
 // Write adds a new sample to the track.
 // The track grows dynamically - eviction is controlled externally by the variant.
 func (t *ESTrack) Write(pts, dts int64, data []byte, isKeyframe bool) uint64 {
@@ -118,13 +131,15 @@ func (t *ESTrack) Write(pts, dts int64, data []byte, isKeyframe bool) uint64 {
 	sampleSize := uint64(len(dataCopy))
 
 	sample := ESSample{
-		PTS:        pts,
-		DTS:        dts,
-		Data:       dataCopy,
-		IsKeyframe: isKeyframe,
-		Sequence:   seq,
-		Timestamp:  time.Now(),
+		PTS:           pts,
+		DTS:           dts,
+		Data:          dataCopy,
+		IsKeyframe:    isKeyframe,
+		Sequence:      seq,
+		Timestamp:     time.Now(),
+		Discontinuity: t.discontinuityPending,
 	}
+	t.discontinuityPending = false
 
 	// Append to dynamic slice
 	t.samples = append(t.samples, sample)
@@ -1001,8 +1016,20 @@ type SharedESBuffer struct {
 	closedCh          chan struct{}
 	sourceCompleted   atomic.Bool   // True when source ingest has finished (EOF received)
 	sourceCompletedCh chan struct{} // Closed when source ingest completes
+
+	// Upstream failover: source timestamps are rebased after a switch so they
+	// continue from the previous upstream instead of jumping.
+	rebaseMu        sync.Mutex
+	rebasePending   bool  // Compute a new offset from the next source sample
+	rebaseOffset    int64 // Added to source PTS/DTS (90kHz)
+	lastSourceDTS   int64 // Highest rebased source DTS written
+	discontinuities atomic.Uint64
 }
 
+// discontinuityGap is the timestamp gap left between the last sample of the
+// previous upstream and the first sample of the next (40ms at 90kHz).
+const discontinuityGap int64 = 3600
+
 // NewSharedESBuffer creates a new shared elementary stream buffer.
 // If ExpectedVideoCodec and ExpectedAudioCodec hints are set in the config,
 // the source variant is pre-created with both codecs immediately, avoiding
@@ -1384,6 +1411,7 @@ func (b *SharedESBuffer) WriteVideo(pts, dts int64, data []byte, isKeyframe bool
 	if source == nil {
 		return 0
 	}
+	pts, dts = b.rebaseTimestamps(pts, dts)
 	return source.WriteVideo(pts, dts, data, isKeyframe)
 }
 
@@ -1393,9 +1421,49 @@ func (b *SharedESBuffer) WriteAudio(pts int64, data []byte) uint64 {
 	if source == nil {
 		return 0
 	}
+	pts, _ = b.rebaseTimestamps(pts, pts)
 	return source.WriteAudio(pts, data)
 }
 
+// MarkDiscontinuity signals that the source is switching to another upstream.
+// The next source samples are flagged as a discontinuity, and their timestamps
+// are rebased to continue just after the last sample already written, so
+// muxers see a monotonic timeline across the switch.
+func (b *SharedESBuffer) MarkDiscontinuity() {
+	b.rebaseMu.Lock()
+	b.rebasePending = true
+	b.rebaseMu.Unlock()
+
+	if source := b.GetSourceVariant(); source != nil {
+		source.videoTrack.MarkDiscontinuity()
+		source.audioTrack.MarkDiscontinuity()
+	}
+	b.discontinuities.Add(1)
+}
+
+// Discontinuities returns how many upstream switches the source has seen.
+func (b *SharedESBuffer) Discontinuities() uint64 {
+	return b.discontinuities.Load()
+}
+
+// rebaseTimestamps applies the failover offset to a source sample's timestamps.
+// The offset is recomputed from the first sample after MarkDiscontinuity.
+func (b *SharedESBuffer) rebaseTimestamps(pts, dts int64) (int64, int64) {
+	b.rebaseMu.Lock()
+	defer b.rebaseMu.Unlock()
+
+	if b.rebasePending {
+		b.rebaseOffset = b.lastSourceDTS + discontinuityGap - dts
+		b.rebasePending = false
+	}
+	pts += b.rebaseOffset
+	dts += b.rebaseOffset
+	if dts > b.lastSourceDTS {
+		b.lastSourceDTS = dts
+	}
+	return pts, dts
+}
+
 // WriteVideoToVariant writes a video sample to a specific codec variant.
 // If the variant doesn't exist, the sample is dropped.
 func (b *SharedESBuffer) WriteVideoToVariant(variant CodecVariant, pts, dts int64, data []byte, isKeyframe bool) uint64 {
@@ -0,0 +1,292 @@
+// Package relay provides streaming relay functionality for tvarr.
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTimeshiftStoreClosed is returned when appending to a closed timeshift store.
+var ErrTimeshiftStoreClosed = errors.New("timeshift store closed")
+
+// timeshiftDirPrefixes are the processor ID prefixes of per-processor timeshift
+// directories. Only these are removed when cleaning up after an unclean shutdown.
+var timeshiftDirPrefixes = []string{"hls-ts-", "hls-fmp4-", "dash-"}
+
+// TimeshiftConfig configures the disk-backed timeshift window of segment processors.
+type TimeshiftConfig struct {
+	// Directory is the base directory. Each processor writes to its own subdirectory.
+	Directory string
+	// Window is how much media is kept on disk. Zero disables timeshift.
+	Window time.Duration
+}
+
+// Enabled reports whether timeshift is configured.
+func (c TimeshiftConfig) Enabled() bool {
+	return c.Window > 0 && c.Directory != ""
+}
+
+// TimeshiftProvider is an optional interface that SegmentProviders implement when
+// their segment list covers a seekable DVR window rather than just the live edge.
+type TimeshiftProvider interface {
+	// TimeshiftWindow returns the configured DVR window, or 0 if timeshift is disabled.
+	TimeshiftWindow() time.Duration
+}
+
+// timeshiftEntry is the index entry of a segment stored on disk.
+type timeshiftEntry struct {
+	sequence      uint64
+	duration      float64
+	size          int64
+	discontinuity bool
+	createdAt     time.Time
+}
+
+// TimeshiftStore keeps a processor's segments on disk for a sliding time window,
+// so viewers can pause and rewind live output without holding it in memory.
+// Segments are appended in sequence order and the oldest are deleted once the
+// total duration exceeds the window.
+type TimeshiftStore struct {
+	dir    string
+	window time.Duration
+
+	mu       sync.RWMutex
+	entries  []timeshiftEntry
+	duration float64 // Total duration of entries in seconds
+	size     int64   // Total bytes on disk
+	closed   bool
+}
+
+// NewTimeshiftStore creates a store writing to its own directory under cfg.Directory.
+func NewTimeshiftStore(cfg TimeshiftConfig, processorID string) (*TimeshiftStore, error) {
+	// Variant names contain slashes (e.g. "h264/aac")
+	dir := filepath.Join(cfg.Directory, strings.ReplaceAll(processorID, "/", "_"))
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("clearing timeshift directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating timeshift directory: %w", err)
+	}
+	return &TimeshiftStore{dir: dir, window: cfg.Window}, nil
+}
+
+// CleanTimeshiftDirectory removes timeshift directories left behind by a previous
+// process. Other files in dir are left alone.
+func CleanTimeshiftDirectory(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading timeshift directory: %w", err)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() || !hasTimeshiftDirPrefix(entry.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func hasTimeshiftDirPrefix(name string) bool {
+	for _, prefix := range timeshiftDirPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Window returns the configured window.
+func (s *TimeshiftStore) Window() time.Duration {
+	return s.window
+}
+
+// Append writes a segment to disk and evicts segments that fell out of the window.
+func (s *TimeshiftStore) Append(seg *Segment) error {
+	s.mu.RLock()
+	closed := s.closed
+	s.mu.RUnlock()
+	if closed {
+		return ErrTimeshiftStoreClosed
+	}
+
+	// Write before indexing so readers never see a partial file
+	if err := os.WriteFile(s.segmentPath(seg.Sequence), seg.Data, 0o640); err != nil {
+		return fmt.Errorf("writing timeshift segment %d: %w", seg.Sequence, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		_ = os.Remove(s.segmentPath(seg.Sequence))
+		return ErrTimeshiftStoreClosed
+	}
+
+	s.entries = append(s.entries, timeshiftEntry{
+		sequence:      seg.Sequence,
+		duration:      seg.Duration,
+		size:          int64(len(seg.Data)),
+		discontinuity: seg.Discontinuity,
+		createdAt:     seg.Timestamp,
+	})
+	s.duration += seg.Duration
+	s.size += int64(len(seg.Data))
+
+	// Keep at least the newest segment even if it alone exceeds the window
+	window := s.window.Seconds()
+	for len(s.entries) > 1 && s.duration-s.entries[0].duration >= window {
+		oldest := s.entries[0]
+		s.entries = s.entries[1:]
+		s.duration -= oldest.duration
+		s.size -= oldest.size
+		_ = os.Remove(s.segmentPath(oldest.sequence))
+	}
+	return nil
+}
+
+// Get reads a segment from disk. Returns ErrSegmentNotFound if it is not in the window.
+func (s *TimeshiftStore) Get(sequence uint64) (*Segment, error) {
+	s.mu.RLock()
+	entry, ok := s.find(sequence)
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrSegmentNotFound
+	}
+
+	data, err := os.ReadFile(s.segmentPath(sequence))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Evicted between the lookup and the read
+			return nil, ErrSegmentNotFound
+		}
+		return nil, fmt.Errorf("reading timeshift segment %d: %w", sequence, err)
+	}
+
+	return &Segment{
+		Sequence:      entry.sequence,
+		Duration:      entry.duration,
+		Data:          data,
+		Timestamp:     entry.createdAt,
+		Discontinuity: entry.discontinuity,
+	}, nil
+}
+
+// SegmentInfos returns metadata for every segment in the window, oldest first.
+func (s *TimeshiftStore) SegmentInfos() []SegmentInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.entries) == 0 {
+		return nil
+	}
+	infos := make([]SegmentInfo, len(s.entries))
+	for i, entry := range s.entries {
+		infos[i] = SegmentInfo{
+			Sequence:      entry.sequence,
+			Duration:      entry.duration,
+			Timestamp:     entry.createdAt,
+			Discontinuity: entry.discontinuity,
+		}
+	}
+	return infos
+}
+
+// Duration returns the total duration of stored segments.
+func (s *TimeshiftStore) Duration() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Duration(s.duration * float64(time.Second))
+}
+
+// Size returns the number of bytes stored on disk.
+func (s *TimeshiftStore) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.size
+}
+
+// Close deletes the store's directory. Further appends fail with ErrTimeshiftStoreClosed.
+func (s *TimeshiftStore) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.entries = nil
+	s.duration = 0
+	s.size = 0
+	s.mu.Unlock()
+
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("removing timeshift directory: %w", err)
+	}
+	return nil
+}
+
+// find returns the entry for sequence. Callers must hold s.mu.
+func (s *TimeshiftStore) find(sequence uint64) (timeshiftEntry, bool) {
+	i := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].sequence >= sequence
+	})
+	if i < len(s.entries) && s.entries[i].sequence == sequence {
+		return s.entries[i], true
+	}
+	return timeshiftEntry{}, false
+}
+
+// openTimeshift creates a processor's store if timeshift is enabled. Failures are
+// logged and the processor runs without a DVR window.
+func openTimeshift(cfg TimeshiftConfig, logger *slog.Logger, processorID string) *TimeshiftStore {
+	if !cfg.Enabled() {
+		return nil
+	}
+	store, err := NewTimeshiftStore(cfg, processorID)
+	if err != nil {
+		logger.Warn("Timeshift disabled for processor",
+			slog.String("id", processorID),
+			slog.String("error", err.Error()))
+		return nil
+	}
+	return store
+}
+
+// appendTimeshift appends a flushed segment to a processor's store. Write failures
+// are logged rather than returned: the live edge keeps working from memory.
+func appendTimeshift(store *TimeshiftStore, logger *slog.Logger, processorID string, seg *Segment) {
+	if err := store.Append(seg); err != nil && !errors.Is(err, ErrTimeshiftStoreClosed) {
+		logger.Warn("Failed to write timeshift segment",
+			slog.String("id", processorID),
+			slog.Uint64("sequence", seg.Sequence),
+			slog.String("error", err.Error()))
+	}
+}
+
+// closeTimeshift closes a processor's store, if any, deleting its segments.
+func closeTimeshift(store *TimeshiftStore, logger *slog.Logger, processorID string) {
+	if store == nil {
+		return
+	}
+	if err := store.Close(); err != nil {
+		logger.Warn("Failed to remove timeshift segments",
+			slog.String("id", processorID),
+			slog.String("error", err.Error()))
+	}
+}
+
+func (s *TimeshiftStore) segmentPath(sequence uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d.seg", sequence))
+}
@@ -0,0 +1,97 @@
+package relay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendTestSegment(t *testing.T, store *TimeshiftStore, seq uint64, duration float64) {
+	t.Helper()
+	require.NoError(t, store.Append(&Segment{
+		Sequence:  seq,
+		Duration:  duration,
+		Data:      []byte{byte(seq), byte(seq), byte(seq)},
+		Timestamp: time.Now(),
+	}))
+}
+
+func TestTimeshiftStore_AppendAndGet(t *testing.T) {
+	store, err := NewTimeshiftStore(TimeshiftConfig{Directory: t.TempDir(), Window: time.Minute}, "hls-ts-abc-h264/aac")
+	require.NoError(t, err)
+	defer store.Close()
+
+	appendTestSegment(t, store, 5, 4)
+	appendTestSegment(t, store, 6, 4)
+
+	seg, err := store.Get(6)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), seg.Sequence)
+	assert.Equal(t, 4.0, seg.Duration)
+	assert.Equal(t, []byte{6, 6, 6}, seg.Data)
+
+	_, err = store.Get(7)
+	assert.ErrorIs(t, err, ErrSegmentNotFound)
+
+	infos := store.SegmentInfos()
+	require.Len(t, infos, 2)
+	assert.Equal(t, uint64(5), infos[0].Sequence)
+	assert.Equal(t, 8*time.Second, store.Duration())
+	assert.Equal(t, int64(6), store.Size())
+}
+
+func TestTimeshiftStore_EvictsOutsideWindow(t *testing.T) {
+	store, err := NewTimeshiftStore(TimeshiftConfig{Directory: t.TempDir(), Window: 10 * time.Second}, "dash-abc")
+	require.NoError(t, err)
+	defer store.Close()
+
+	for seq := uint64(0); seq < 5; seq++ {
+		appendTestSegment(t, store, seq, 4)
+	}
+
+	// 10s window with 4s segments keeps the newest three (12s)
+	infos := store.SegmentInfos()
+	require.Len(t, infos, 3)
+	assert.Equal(t, uint64(2), infos[0].Sequence)
+	assert.Equal(t, uint64(4), infos[2].Sequence)
+
+	_, err = store.Get(1)
+	assert.ErrorIs(t, err, ErrSegmentNotFound)
+	_, err = os.Stat(store.segmentPath(1))
+	assert.True(t, os.IsNotExist(err), "evicted segment file should be deleted")
+}
+
+func TestTimeshiftStore_Close(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewTimeshiftStore(TimeshiftConfig{Directory: dir, Window: time.Minute}, "hls-fmp4-abc")
+	require.NoError(t, err)
+	appendTestSegment(t, store, 1, 4)
+
+	require.NoError(t, store.Close())
+	_, err = os.Stat(filepath.Join(dir, "hls-fmp4-abc"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.ErrorIs(t, store.Append(&Segment{Sequence: 2, Duration: 4}), ErrTimeshiftStoreClosed)
+	assert.Empty(t, store.SegmentInfos())
+	assert.NoError(t, store.Close())
+}
+
+func TestCleanTimeshiftDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "hls-ts-old", "x"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dash-old"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "unrelated"), 0o750))
+
+	require.NoError(t, CleanTimeshiftDirectory(dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "unrelated", entries[0].Name())
+
+	assert.NoError(t, CleanTimeshiftDirectory(filepath.Join(dir, "missing")))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/proto"
 	"github.com/jmylchreest/tvarr/pkg/ffmpegd/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Transcoder is the interface for codec transcoding.
@@ -189,18 +190,29 @@ func (f *TranscoderFactory) ShouldUseRemote() bool {
 // sourceCodec and targetCodec are normalized codec names (e.g., "h264", "hevc", "vp9").
 // The daemon selection will prefer daemons with HW encoders for the target codec.
 // Returns nil if no suitable daemon is available.
-func (f *TranscoderFactory) SelectRemoteDaemon(sourceCodec, targetCodec string, requireGPU bool) *types.Daemon {
+func (f *TranscoderFactory) SelectRemoteDaemon(ctx context.Context, sourceCodec, targetCodec string, requireGPU bool) *types.Daemon {
 	if f.DaemonRegistry == nil {
 		return nil
 	}
 
+	_, span := observability.StartSpan(ctx, "relay.daemon.select",
+		attribute.String("tvarr.codec.source", sourceCodec),
+		attribute.String("tvarr.codec.target", targetCodec),
+		attribute.Bool("tvarr.daemon.require_gpu", requireGPU),
+	)
+	defer span.End()
+
 	criteria := SelectionCriteria{
 		SourceCodec: sourceCodec,
 		TargetCodec: targetCodec,
 		RequireGPU:  requireGPU,
 	}
 
-	return f.DaemonRegistry.SelectDaemon(f.SelectionStrategy, criteria)
+	daemon := f.DaemonRegistry.SelectDaemon(f.SelectionStrategy, criteria)
+	if daemon != nil {
+		span.SetAttributes(attribute.String("tvarr.daemon.name", daemon.Name))
+	}
+	return daemon
 }
 
 // CreateTranscoderOptions contains options for creating a transcoder.
@@ -231,6 +243,7 @@ type CreateTranscoderOptions struct {
 // 1. Remote daemon (if PreferRemote and suitable daemon available)
 // 2. Local ffmpegd subprocess (fallback)
 func (f *TranscoderFactory) CreateTranscoderFromProfile(
+	ctx context.Context,
 	id string,
 	buffer *SharedESBuffer,
 	sourceVariant CodecVariant,
@@ -332,7 +345,7 @@ func (f *TranscoderFactory) CreateTranscoderFromProfile(
 				)
 			}
 		}
-		daemon := f.SelectRemoteDaemon(sourceVariant.VideoCodec(), targetVariant.VideoCodec(), requireGPU)
+		daemon := f.SelectRemoteDaemon(ctx, sourceVariant.VideoCodec(), targetVariant.VideoCodec(), requireGPU)
 		if daemon != nil {
 			f.Logger.Info("Selected remote daemon for transcoding",
 				slog.String("id", id),
@@ -391,6 +404,7 @@ func (f *TranscoderFactory) CreateTranscoderFromProfile(
 // 1. Remote daemon (if PreferRemote and suitable daemon available)
 // 2. Local ffmpegd subprocess (fallback)
 func (f *TranscoderFactory) CreateTranscoderFromVariant(
+	ctx context.Context,
 	id string,
 	buffer *SharedESBuffer,
 	sourceVariant CodecVariant,
@@ -404,7 +418,7 @@ func (f *TranscoderFactory) CreateTranscoderFromVariant(
 	// 1. Try remote daemon first if preferred
 	// Use codec-level selection so daemons with HW encoders are found
 	if f.ShouldUseRemote() {
-		daemon := f.SelectRemoteDaemon(sourceVariant.VideoCodec(), targetVariant.VideoCodec(), requireGPU)
+		daemon := f.SelectRemoteDaemon(ctx, sourceVariant.VideoCodec(), targetVariant.VideoCodec(), requireGPU)
 		if daemon != nil {
 			f.Logger.Log(context.Background(), observability.LevelTrace, "Selected remote daemon for transcoding",
 				slog.String("id", id),
@@ -0,0 +1,88 @@
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// ViewingInfo describes who a relay client is and how its stream is delivered.
+// The stream handler attaches it to the request that registers the client;
+// clients registered without it, such as recordings, are not reported as viewings.
+type ViewingInfo struct {
+	ViewerID    *models.ULID // Authenticated viewer, when stream authentication is enabled
+	ViewerName  string
+	ClientIP    string
+	ClientRule  string // Matched client detection rule
+	ProxyID     models.ULID
+	ProxyName   string
+	Route       string // passthrough, repackage or transcode
+	ProfileName string // Encoding profile, when transcoding
+}
+
+type viewingInfoKey struct{}
+
+// WithViewingInfo returns a context carrying viewing info for the client
+// registered with a request using it.
+func WithViewingInfo(ctx context.Context, info *ViewingInfo) context.Context {
+	return context.WithValue(ctx, viewingInfoKey{}, info)
+}
+
+// viewingInfoFromContext returns the viewing info attached to a context, or nil.
+func viewingInfoFromContext(ctx context.Context) *ViewingInfo {
+	info, _ := ctx.Value(viewingInfoKey{}).(*ViewingInfo)
+	return info
+}
+
+// Viewing is a client's finished stay on a relay session, reported when the
+// client disconnects, times out or its processor stops.
+type Viewing struct {
+	ViewingInfo
+	SessionID   models.ULID
+	ChannelID   models.ULID
+	ChannelName string
+	SourceID    models.ULID // Source the session was ingesting from when the client left
+	SourceName  string
+	Format      OutputFormat
+	Variant     CodecVariant
+	UserAgent   string
+	StartedAt   time.Time
+	EndedAt     time.Time
+	BytesSent   uint64
+}
+
+// ViewingFunc receives finished viewings. It is called on relay goroutines
+// and must not block.
+type ViewingFunc func(Viewing)
+
+// OnViewingEnded sets a function called when a client leaves any relay session.
+func (m *Manager) OnViewingEnded(fn ViewingFunc) {
+	m.onViewingEnded.Store(&fn)
+}
+
+// reportViewing reports a client removed from one of the session's processors.
+func (s *RelaySession) reportViewing(p *ESProcessorBase, client *ProcessorClient, endedAt time.Time) {
+	if client.Viewing == nil || s.manager == nil {
+		return
+	}
+	fn := s.manager.onViewingEnded.Load()
+	if fn == nil {
+		return
+	}
+	upstream := s.ActiveUpstream()
+	(*fn)(Viewing{
+		ViewingInfo: *client.Viewing,
+		SessionID:   s.ID,
+		ChannelID:   s.ChannelID,
+		ChannelName: s.ChannelName,
+		SourceID:    upstream.SourceID,
+		SourceName:  upstream.SourceName,
+		Format:      p.Format(),
+		Variant:     p.Variant(),
+		UserAgent:   client.UserAgent,
+		StartedAt:   client.ConnectedAt,
+		EndedAt:     endedAt,
+		BytesSent:   client.BytesRead.Load(),
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// apiKeyRepository implements APIKeyRepository using GORM.
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository.
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create creates a new API key.
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	if err := key.Validate(); err != nil {
+		return fmt.Errorf("validating api key: %w", err)
+	}
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetByID retrieves an API key by ID.
+func (r *apiKeyRepository) GetByID(ctx context.Context, id models.ULID) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByKeyHash retrieves an API key by its hash, including the owning user.
+func (r *apiKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		First(&key, "key_hash = ?", keyHash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetAll retrieves all API keys ordered by creation time.
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// TouchLastUsed records that a key was used without bumping updated_at.
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id models.ULID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
+
+// Delete hard-deletes an API key by ID.
+func (r *apiKeyRepository) Delete(ctx context.Context, id models.ULID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.APIKey{}, "id = ?", id).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// auditEventRepository implements AuditEventRepository using GORM.
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditEventRepository creates a new AuditEventRepository.
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+// Create records an audit event.
+func (r *auditEventRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// GetByID retrieves an audit event by ID.
+func (r *auditEventRepository) GetByID(ctx context.Context, id models.ULID) (*models.AuditEvent, error) {
+	var event models.AuditEvent
+	if err := r.db.WithContext(ctx).First(&event, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// List retrieves audit events matching the filter, newest first.
+func (r *auditEventRepository) List(ctx context.Context, filter AuditEventFilter, offset, limit int) ([]*models.AuditEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.AuditEvent{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor_name = ? OR actor_id = ?", filter.Actor, filter.Actor)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// IDs are ULIDs, so they break ties between events recorded in the same instant.
+	var events []*models.AuditEvent
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// authSessionRepository implements AuthSessionRepository using GORM.
+type authSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthSessionRepository creates a new AuthSessionRepository.
+func NewAuthSessionRepository(db *gorm.DB) AuthSessionRepository {
+	return &authSessionRepository{db: db}
+}
+
+// Create creates a new session.
+func (r *authSessionRepository) Create(ctx context.Context, session *models.AuthSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetByTokenHash retrieves a session by its token hash, including the user.
+func (r *authSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.AuthSession, error) {
+	var session models.AuthSession
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		First(&session, "token_hash = ?", tokenHash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// DeleteByTokenHash hard-deletes a session by its token hash.
+func (r *authSessionRepository) DeleteByTokenHash(ctx context.Context, tokenHash string) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Where("token_hash = ?", tokenHash).
+		Delete(&models.AuthSession{}).Error
+}
+
+// DeleteByUserID hard-deletes all sessions for a user.
+func (r *authSessionRepository) DeleteByUserID(ctx context.Context, userID models.ULID) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&models.AuthSession{}).Error
+}
+
+// DeleteExpired hard-deletes sessions that expired before the given time.
+func (r *authSessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("expires_at < ?", before).
+		Delete(&models.AuthSession{})
+	return result.RowsAffected, result.Error
+}
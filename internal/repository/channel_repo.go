@@ -58,7 +58,8 @@ func (r *channelRepo) UpsertBatch(ctx context.Context, channels []*models.Channe
 				DoUpdates: clause.AssignmentColumns([]string{
 					"tvg_id", "tvg_name", "tvg_logo", "group_title", "channel_name",
 					"channel_number", "stream_url", "stream_type", "language",
-					"country", "is_adult", "extra", "updated_at",
+					"country", "is_adult", "catchup_days", "catchup_source",
+					"catchup_timezone", "stream_headers", "extra", "updated_at",
 				}),
 			}).Create(channels).Error; err != nil {
 				return fmt.Errorf("upserting channel batch: %w", err)
@@ -248,7 +249,8 @@ func (r *channelRepo) UpsertBySourceAndExtID(ctx context.Context, channel *model
 		DoUpdates: clause.AssignmentColumns([]string{
 			"tvg_id", "tvg_name", "tvg_logo", "group_title", "channel_name",
 			"channel_number", "stream_url", "stream_type", "language",
-			"country", "is_adult", "extra", "updated_at",
+			"country", "is_adult", "catchup_days", "catchup_source",
+			"catchup_timezone", "stream_headers", "extra", "updated_at",
 		}),
 	}).Create(channel).Error; err != nil {
 		return fmt.Errorf("upserting channel: %w", err)
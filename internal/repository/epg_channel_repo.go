@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// epgChannelQueryChunk bounds the number of IDs in a single IN clause.
+const epgChannelQueryChunk = 500
+
+// epgChannelRepository implements EpgChannelRepository using GORM.
+type epgChannelRepository struct {
+	db *gorm.DB
+}
+
+// NewEpgChannelRepository creates a new EpgChannelRepository.
+func NewEpgChannelRepository(db *gorm.DB) EpgChannelRepository {
+	return &epgChannelRepository{db: db}
+}
+
+// ReplaceBySourceID replaces all channels of an EPG source.
+func (r *epgChannelRepository) ReplaceBySourceID(ctx context.Context, sourceID models.ULID, channels []*models.EpgChannel) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("source_id = ?", sourceID).Delete(&models.EpgChannel{}).Error; err != nil {
+			return fmt.Errorf("deleting EPG channels: %w", err)
+		}
+		if len(channels) == 0 {
+			return nil
+		}
+		for _, ch := range channels {
+			ch.SourceID = sourceID
+		}
+		if err := tx.CreateInBatches(channels, epgChannelQueryChunk).Error; err != nil {
+			return fmt.Errorf("creating EPG channels: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetBySourceIDs retrieves the channels of the given EPG sources.
+func (r *epgChannelRepository) GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID) ([]*models.EpgChannel, error) {
+	if len(sourceIDs) == 0 {
+		return nil, nil
+	}
+	var channels []*models.EpgChannel
+	if err := r.db.WithContext(ctx).
+		Where("source_id IN ?", sourceIDs).
+		Order("channel_id ASC").
+		Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// DeleteBySourceID deletes all channels of an EPG source.
+func (r *epgChannelRepository) DeleteBySourceID(ctx context.Context, sourceID models.ULID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("source_id = ?", sourceID).Delete(&models.EpgChannel{}).Error
+}
+
+// GetMatchesByChannelIDs retrieves the matches of the given stream channels.
+func (r *epgChannelRepository) GetMatchesByChannelIDs(ctx context.Context, channelIDs []models.ULID) ([]*models.EpgChannelMatch, error) {
+	var matches []*models.EpgChannelMatch
+	for start := 0; start < len(channelIDs); start += epgChannelQueryChunk {
+		end := min(start+epgChannelQueryChunk, len(channelIDs))
+		var chunk []*models.EpgChannelMatch
+		if err := r.db.WithContext(ctx).Where("channel_id IN ?", channelIDs[start:end]).Find(&chunk).Error; err != nil {
+			return nil, err
+		}
+		matches = append(matches, chunk...)
+	}
+	return matches, nil
+}
+
+// SaveMatches creates or replaces matches, keyed by stream channel.
+func (r *epgChannelRepository) SaveMatches(ctx context.Context, matches []*models.EpgChannelMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	for _, m := range matches {
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("validating EPG channel match: %w", err)
+		}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "channel_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"channel_name", "epg_source_id", "tvg_id", "epg_display_name",
+			"confidence", "status", "updated_at",
+		}),
+	}).CreateInBatches(matches, epgChannelQueryChunk).Error
+}
+
+// GetMatchByID retrieves a match by ID.
+func (r *epgChannelRepository) GetMatchByID(ctx context.Context, id models.ULID) (*models.EpgChannelMatch, error) {
+	var match models.EpgChannelMatch
+	if err := r.db.WithContext(ctx).First(&match, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &match, nil
+}
+
+// ListMatches retrieves matches with the given status (all when empty), lowest
+// confidence first.
+func (r *epgChannelRepository) ListMatches(ctx context.Context, status models.EpgMatchStatus) ([]*models.EpgChannelMatch, error) {
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var matches []*models.EpgChannelMatch
+	if err := query.Order("confidence ASC").Order("channel_name ASC").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// UpdateMatch updates an existing match.
+func (r *epgChannelRepository) UpdateMatch(ctx context.Context, match *models.EpgChannelMatch) error {
+	if err := match.Validate(); err != nil {
+		return fmt.Errorf("validating EPG channel match: %w", err)
+	}
+	return r.db.WithContext(ctx).Save(match).Error
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupEpgChannelTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.EpgChannel{}, &models.EpgChannelMatch{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestEpgChannelRepo_ReplaceBySourceID(t *testing.T) {
+	db := setupEpgChannelTestDB(t)
+	repo := NewEpgChannelRepository(db)
+	ctx := context.Background()
+
+	sourceA := models.NewULID()
+	sourceB := models.NewULID()
+
+	bbc := &models.EpgChannel{ChannelID: "bbc1.uk"}
+	bbc.SetNames([]string{"BBC One", "BBC 1"})
+	require.NoError(t, repo.ReplaceBySourceID(ctx, sourceA, []*models.EpgChannel{bbc, {ChannelID: "itv.uk", DisplayName: "ITV"}}))
+	require.NoError(t, repo.ReplaceBySourceID(ctx, sourceB, []*models.EpgChannel{{ChannelID: "cnn.us", DisplayName: "CNN"}}))
+
+	channels, err := repo.GetBySourceIDs(ctx, []models.ULID{sourceA})
+	require.NoError(t, err)
+	require.Len(t, channels, 2)
+	assert.Equal(t, "bbc1.uk", channels[0].ChannelID)
+	assert.Equal(t, []string{"BBC One", "BBC 1"}, channels[0].Names())
+
+	// Re-ingestion replaces the source's channels
+	require.NoError(t, repo.ReplaceBySourceID(ctx, sourceA, []*models.EpgChannel{{ChannelID: "bbc2.uk", DisplayName: "BBC Two"}}))
+	channels, err = repo.GetBySourceIDs(ctx, []models.ULID{sourceA, sourceB})
+	require.NoError(t, err)
+	require.Len(t, channels, 2)
+	assert.Equal(t, "bbc2.uk", channels[0].ChannelID)
+	assert.Equal(t, "cnn.us", channels[1].ChannelID)
+
+	require.NoError(t, repo.DeleteBySourceID(ctx, sourceB))
+	channels, err = repo.GetBySourceIDs(ctx, []models.ULID{sourceB})
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+}
+
+func TestEpgChannelRepo_Matches(t *testing.T) {
+	db := setupEpgChannelTestDB(t)
+	repo := NewEpgChannelRepository(db)
+	ctx := context.Background()
+
+	channelA := models.NewULID()
+	channelB := models.NewULID()
+	source := models.NewULID()
+
+	require.NoError(t, repo.SaveMatches(ctx, []*models.EpgChannelMatch{
+		{ChannelID: channelA, ChannelName: "BBC One HD", EpgSourceID: source, TvgID: "bbc1.uk", Confidence: 1, Status: models.EpgMatchStatusAuto},
+		{ChannelID: channelB, ChannelName: "Sky Sport Main", EpgSourceID: source, TvgID: "skymain.uk", Confidence: 0.7, Status: models.EpgMatchStatusPending},
+	}))
+
+	pending, err := repo.ListMatches(ctx, models.EpgMatchStatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, channelB, pending[0].ChannelID)
+
+	// Saving again replaces the channel's match rather than adding one
+	require.NoError(t, repo.SaveMatches(ctx, []*models.EpgChannelMatch{
+		{ChannelID: channelB, ChannelName: "Sky Sport Main", EpgSourceID: source, TvgID: "skymainevent.uk", Confidence: 0.8, Status: models.EpgMatchStatusPending},
+	}))
+	all, err := repo.ListMatches(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "skymainevent.uk", all[0].TvgID)
+
+	match, err := repo.GetMatchByID(ctx, pending[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, match)
+	match.Status = models.EpgMatchStatusConfirmed
+	require.NoError(t, repo.UpdateMatch(ctx, match))
+
+	matches, err := repo.GetMatchesByChannelIDs(ctx, []models.ULID{channelB})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, models.EpgMatchStatusConfirmed, matches[0].Status)
+
+	missing, err := repo.GetMatchByID(ctx, models.NewULID())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -36,6 +36,9 @@ type StreamSourceRepository interface {
 	GetByURL(ctx context.Context, url string) (*models.StreamSource, error)
 	// UpdateLastIngestion updates the last ingestion timestamp and status.
 	UpdateLastIngestion(ctx context.Context, id models.ULID, status string, channelCount int) error
+	// UpdateAccountStatus updates the Xtream account columns and the concurrent
+	// stream limit of a stream source.
+	UpdateAccountStatus(ctx context.Context, source *models.StreamSource) error
 }
 
 // ChannelRepository defines operations for channel persistence.
@@ -71,6 +74,8 @@ type ChannelRepository interface {
 	CountBySourceID(ctx context.Context, sourceID models.ULID) (int64, error)
 	// GetByExtID retrieves a channel by source ID and external ID.
 	GetByExtID(ctx context.Context, sourceID models.ULID, extID string) (*models.Channel, error)
+	// GetByTvgID retrieves all channels with the given EPG ID, across sources.
+	GetByTvgID(ctx context.Context, tvgID string) ([]*models.Channel, error)
 	// GetDistinctFieldValues returns distinct values for a channel field with occurrence counts.
 	// The field parameter must be one of the allowed fields (group_title, channel_name, tvg_id, country).
 	// Results are filtered by the query parameter (case-insensitive contains) and limited.
@@ -155,6 +160,64 @@ type EpgProgramRepository interface {
 	CountBySourceID(ctx context.Context, sourceID models.ULID) (int64, error)
 }
 
+// EpgChannelRepository defines operations for EPG channel definitions and the
+// name matches linking stream channels to them.
+type EpgChannelRepository interface {
+	// ReplaceBySourceID replaces all channels of an EPG source.
+	ReplaceBySourceID(ctx context.Context, sourceID models.ULID, channels []*models.EpgChannel) error
+	// GetBySourceIDs retrieves the channels of the given EPG sources.
+	GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID) ([]*models.EpgChannel, error)
+	// DeleteBySourceID deletes all channels of an EPG source.
+	DeleteBySourceID(ctx context.Context, sourceID models.ULID) error
+	// GetMatchesByChannelIDs retrieves the matches of the given stream channels.
+	GetMatchesByChannelIDs(ctx context.Context, channelIDs []models.ULID) ([]*models.EpgChannelMatch, error)
+	// SaveMatches creates or replaces matches, keyed by stream channel.
+	SaveMatches(ctx context.Context, matches []*models.EpgChannelMatch) error
+	// GetMatchByID retrieves a match by ID.
+	GetMatchByID(ctx context.Context, id models.ULID) (*models.EpgChannelMatch, error)
+	// ListMatches retrieves matches with the given status (all when empty),
+	// lowest confidence first.
+	ListMatches(ctx context.Context, status models.EpgMatchStatus) ([]*models.EpgChannelMatch, error)
+	// UpdateMatch updates an existing match.
+	UpdateMatch(ctx context.Context, match *models.EpgChannelMatch) error
+}
+
+// VodRepository defines operations for the movies of stream sources' video on
+// demand catalogues.
+type VodRepository interface {
+	// UpsertBatch creates or updates movies, keyed by source and external ID.
+	UpsertBatch(ctx context.Context, items []*models.VodItem) error
+	// GetBySourceIDs streams the movies of the given sources, ordered by category then name.
+	GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID, callback func(*models.VodItem) error) error
+	// DeleteStaleBySourceID deletes movies of a source not updated since olderThan.
+	DeleteStaleBySourceID(ctx context.Context, sourceID models.ULID, olderThan time.Time) (int64, error)
+	// DeleteBySourceID deletes all movies of a source.
+	DeleteBySourceID(ctx context.Context, sourceID models.ULID) error
+	// CountBySourceID returns the number of movies of a source.
+	CountBySourceID(ctx context.Context, sourceID models.ULID) (int64, error)
+}
+
+// SeriesRepository defines operations for the series and episodes of stream
+// sources' video on demand catalogues.
+type SeriesRepository interface {
+	// GetLastModifiedBySourceID returns the provider modification timestamp of
+	// each stored series of a source, keyed by external ID.
+	GetLastModifiedBySourceID(ctx context.Context, sourceID models.ULID) (map[string]int64, error)
+	// UpsertBatch creates or updates series, keyed by source and external ID.
+	// The episodes of series with Episodes set are replaced; other series keep theirs.
+	UpsertBatch(ctx context.Context, series []*models.Series) error
+	// GetBySourceIDs streams the series of the given sources with their episodes,
+	// ordered by category then name.
+	GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID, callback func(*models.Series) error) error
+	// DeleteStaleBySourceID deletes series of a source not updated since
+	// olderThan, with their episodes.
+	DeleteStaleBySourceID(ctx context.Context, sourceID models.ULID, olderThan time.Time) (int64, error)
+	// DeleteBySourceID deletes all series of a source with their episodes.
+	DeleteBySourceID(ctx context.Context, sourceID models.ULID) error
+	// CountBySourceID returns the number of series of a source.
+	CountBySourceID(ctx context.Context, sourceID models.ULID) (int64, error)
+}
+
 // StreamProxyRepository defines operations for stream proxy persistence.
 type StreamProxyRepository interface {
 	// Create creates a new stream proxy.
@@ -190,6 +253,10 @@ type StreamProxyRepository interface {
 	SetFilters(ctx context.Context, proxyID models.ULID, filterIDs []models.ULID, orders map[models.ULID]int, isActive map[models.ULID]bool) error
 	// GetFilters retrieves the filters for a proxy with order.
 	GetFilters(ctx context.Context, proxyID models.ULID) ([]*models.Filter, error)
+	// SetChannelAlternates sets the deduplicated channel alternates for a proxy (replaces existing).
+	SetChannelAlternates(ctx context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error
+	// GetChannelAlternates retrieves the alternates of a proxy channel in priority order.
+	GetChannelAlternates(ctx context.Context, proxyID, channelID models.ULID) ([]*models.ProxyChannelAlternate, error)
 	// GetBySourceID retrieves all proxies that use a specific stream source.
 	// Used for auto-regeneration when a source is updated.
 	GetBySourceID(ctx context.Context, sourceID models.ULID) ([]*models.StreamProxy, error)
@@ -461,3 +528,198 @@ type EncoderOverrideRepository interface {
 	// Reorder updates priorities for multiple overrides in a single transaction.
 	Reorder(ctx context.Context, reorders []ReorderRequest) error
 }
+
+// UserRepository defines operations for local user persistence.
+type UserRepository interface {
+	// Create creates a new user.
+	Create(ctx context.Context, user *models.User) error
+	// GetByID retrieves a user by ID.
+	GetByID(ctx context.Context, id models.ULID) (*models.User, error)
+	// GetByUsername retrieves a user by username.
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	// GetAll retrieves all users ordered by username.
+	GetAll(ctx context.Context) ([]*models.User, error)
+	// Update updates an existing user.
+	Update(ctx context.Context, user *models.User) error
+	// Delete deletes a user by ID.
+	Delete(ctx context.Context, id models.ULID) error
+	// Count returns the total number of users.
+	Count(ctx context.Context) (int64, error)
+}
+
+// AuthSessionRepository defines operations for UI session persistence.
+type AuthSessionRepository interface {
+	// Create creates a new session.
+	Create(ctx context.Context, session *models.AuthSession) error
+	// GetByTokenHash retrieves a session by its token hash, including the user.
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.AuthSession, error)
+	// DeleteByTokenHash deletes a session by its token hash.
+	DeleteByTokenHash(ctx context.Context, tokenHash string) error
+	// DeleteByUserID deletes all sessions for a user.
+	DeleteByUserID(ctx context.Context, userID models.ULID) error
+	// DeleteExpired deletes sessions that expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// APIKeyRepository defines operations for API key persistence.
+type APIKeyRepository interface {
+	// Create creates a new API key.
+	Create(ctx context.Context, key *models.APIKey) error
+	// GetByID retrieves an API key by ID.
+	GetByID(ctx context.Context, id models.ULID) (*models.APIKey, error)
+	// GetByKeyHash retrieves an API key by its hash, including the owning user.
+	GetByKeyHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	// GetAll retrieves all API keys ordered by creation time.
+	GetAll(ctx context.Context) ([]*models.APIKey, error)
+	// TouchLastUsed records that a key was used.
+	TouchLastUsed(ctx context.Context, id models.ULID, at time.Time) error
+	// Delete deletes an API key by ID.
+	Delete(ctx context.Context, id models.ULID) error
+}
+
+// ViewerRepository defines operations for playback viewer persistence.
+type ViewerRepository interface {
+	// Create creates a new viewer.
+	Create(ctx context.Context, viewer *models.Viewer) error
+	// GetByID retrieves a viewer by ID.
+	GetByID(ctx context.Context, id models.ULID) (*models.Viewer, error)
+	// GetByName retrieves a viewer by name.
+	GetByName(ctx context.Context, name string) (*models.Viewer, error)
+	// GetByToken retrieves a viewer by stream token.
+	GetByToken(ctx context.Context, token string) (*models.Viewer, error)
+	// GetAll retrieves all viewers ordered by name.
+	GetAll(ctx context.Context) ([]*models.Viewer, error)
+	// Update updates an existing viewer.
+	Update(ctx context.Context, viewer *models.Viewer) error
+	// TouchLastSeen records when a viewer was last seen.
+	TouchLastSeen(ctx context.Context, id models.ULID, at time.Time) error
+	// Delete deletes a viewer by ID.
+	Delete(ctx context.Context, id models.ULID) error
+}
+
+// RecordingRepository defines operations for DVR recording persistence.
+type RecordingRepository interface {
+	// Create creates a new recording.
+	Create(ctx context.Context, recording *models.Recording) error
+	// GetByID retrieves a recording by ID.
+	GetByID(ctx context.Context, id models.ULID) (*models.Recording, error)
+	// GetAll retrieves all recordings, newest start time first.
+	GetAll(ctx context.Context) ([]*models.Recording, error)
+	// GetByStatus retrieves recordings in any of the given statuses ordered by start time.
+	GetByStatus(ctx context.Context, statuses ...models.RecordingStatus) ([]*models.Recording, error)
+	// Update updates an existing recording.
+	Update(ctx context.Context, recording *models.Recording) error
+	// Delete deletes a recording by ID.
+	Delete(ctx context.Context, id models.ULID) error
+}
+
+// RecordingRuleRepository defines operations for series recording rule persistence.
+type RecordingRuleRepository interface {
+	// Create creates a new recording rule.
+	Create(ctx context.Context, rule *models.RecordingRule) error
+	// GetByID retrieves a recording rule by ID.
+	GetByID(ctx context.Context, id models.ULID) (*models.RecordingRule, error)
+	// GetAll retrieves all recording rules ordered by creation time.
+	GetAll(ctx context.Context) ([]*models.RecordingRule, error)
+	// GetEnabled retrieves enabled recording rules ordered by creation time.
+	GetEnabled(ctx context.Context) ([]*models.RecordingRule, error)
+	// Update updates an existing recording rule.
+	Update(ctx context.Context, rule *models.RecordingRule) error
+	// Delete deletes a recording rule by ID.
+	Delete(ctx context.Context, id models.ULID) error
+}
+
+// NotificationRepository defines operations for notification target and delivery log persistence.
+type NotificationRepository interface {
+	// CreateTarget creates a new notification target.
+	CreateTarget(ctx context.Context, target *models.NotificationTarget) error
+	// GetTargetByID retrieves a notification target by ID.
+	GetTargetByID(ctx context.Context, id models.ULID) (*models.NotificationTarget, error)
+	// GetTargetByName retrieves a notification target by name.
+	GetTargetByName(ctx context.Context, name string) (*models.NotificationTarget, error)
+	// GetAllTargets retrieves all notification targets ordered by name.
+	GetAllTargets(ctx context.Context) ([]*models.NotificationTarget, error)
+	// GetEnabledTargets retrieves enabled notification targets ordered by name.
+	GetEnabledTargets(ctx context.Context) ([]*models.NotificationTarget, error)
+	// UpdateTarget updates an existing notification target.
+	UpdateTarget(ctx context.Context, target *models.NotificationTarget) error
+	// DeleteTarget deletes a notification target and its delivery log.
+	DeleteTarget(ctx context.Context, id models.ULID) error
+	// CreateDelivery records a delivery.
+	CreateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	// GetDeliveries retrieves deliveries, newest first, optionally for one target.
+	GetDeliveries(ctx context.Context, targetID *models.ULID, offset, limit int) ([]*models.NotificationDelivery, int64, error)
+	// DeleteDeliveries deletes deliveries created before the given time.
+	DeleteDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ViewingSessionFilter narrows the viewing sessions returned by GetSessions.
+// Zero fields match everything.
+type ViewingSessionFilter struct {
+	ChannelID *models.ULID
+	ProxyID   *models.ULID
+	ViewerID  *models.ULID
+	Since     time.Time // Started at or after
+	Until     time.Time // Started before
+}
+
+// ViewingSessionRepository defines operations for viewing session persistence and analytics.
+type ViewingSessionRepository interface {
+	// Create records a finished viewing session.
+	Create(ctx context.Context, session *models.ViewingSession) error
+	// GetSessions retrieves viewing sessions matching the filter, most recent first.
+	GetSessions(ctx context.Context, filter ViewingSessionFilter, offset, limit int) ([]*models.ViewingSession, int64, error)
+	// GetTopChannels summarizes viewings started in [since, until) by channel,
+	// ordered by total viewing time.
+	GetTopChannels(ctx context.Context, since, until time.Time, limit int) ([]*models.ChannelViewingStats, error)
+	// GetTranscodeStats summarizes transcoded viewings started in [since, until) by encoding profile.
+	GetTranscodeStats(ctx context.Context, since, until time.Time) ([]*models.ProfileTranscodeStats, error)
+	// GetOverlapping retrieves the source and times of viewing sessions overlapping [since, until).
+	GetOverlapping(ctx context.Context, since, until time.Time) ([]*models.ViewingSession, error)
+	// DeleteBefore deletes viewing sessions that ended before the given time.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LogEntryFilter narrows the log entries returned by Query. Zero fields match everything.
+type LogEntryFilter struct {
+	Since     time.Time // Logged at or after
+	Until     time.Time // Logged before
+	Levels    []string  // Any of these levels
+	Module    string
+	RequestID string
+	SessionID string
+	ChannelID string
+	Search    string // Case-insensitive text in the message or fields
+}
+
+// LogEntryRepository defines operations for the persistent log store.
+type LogEntryRepository interface {
+	// CreateBatch stores log entries.
+	CreateBatch(ctx context.Context, entries []*models.LogEntry) error
+	// Query retrieves log entries matching the filter, newest first.
+	Query(ctx context.Context, filter LogEntryFilter, offset, limit int) ([]*models.LogEntry, int64, error)
+	// DeleteBefore deletes log entries logged before the given time.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+	// DeleteExcess deletes the log entries older than the newest keep entries.
+	DeleteExcess(ctx context.Context, keep int) (int64, error)
+}
+
+// AuditEventFilter narrows the audit events returned by List. Zero fields match everything.
+type AuditEventFilter struct {
+	EntityType models.AuditEntityType
+	EntityID   *models.ULID
+	Action     models.AuditAction
+	Actor      string    // Actor name or ID
+	Since      time.Time // Recorded at or after
+	Until      time.Time // Recorded before
+}
+
+// AuditEventRepository defines operations for the configuration audit trail.
+type AuditEventRepository interface {
+	// Create records an audit event.
+	Create(ctx context.Context, event *models.AuditEvent) error
+	// GetByID retrieves an audit event by ID.
+	GetByID(ctx context.Context, id models.ULID) (*models.AuditEvent, error)
+	// List retrieves audit events matching the filter, newest first.
+	List(ctx context.Context, filter AuditEventFilter, offset, limit int) ([]*models.AuditEvent, int64, error)
+}
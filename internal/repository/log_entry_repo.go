@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// logEntryBatchSize is the number of log entries inserted per statement.
+const logEntryBatchSize = 500
+
+// likeEscaper escapes LIKE wildcards, using '!' as the escape character,
+// which unlike backslash has no special meaning in any supported database.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// logEntryRepository implements LogEntryRepository using GORM.
+type logEntryRepository struct {
+	db *gorm.DB
+}
+
+// NewLogEntryRepository creates a new LogEntryRepository.
+func NewLogEntryRepository(db *gorm.DB) LogEntryRepository {
+	return &logEntryRepository{db: db}
+}
+
+// CreateBatch stores log entries.
+func (r *logEntryRepository) CreateBatch(ctx context.Context, entries []*models.LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(entries, logEntryBatchSize).Error
+}
+
+// Query retrieves log entries matching the filter, newest first.
+func (r *logEntryRepository) Query(ctx context.Context, filter LogEntryFilter, offset, limit int) ([]*models.LogEntry, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.LogEntry{})
+	if !filter.Since.IsZero() {
+		query = query.Where("timestamp >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("timestamp < ?", filter.Until)
+	}
+	if len(filter.Levels) > 0 {
+		query = query.Where("level IN ?", filter.Levels)
+	}
+	for _, field := range []struct{ column, value string }{
+		{"module", filter.Module},
+		{"request_id", filter.RequestID},
+		{"session_id", filter.SessionID},
+		{"channel_id", filter.ChannelID},
+	} {
+		if field.value != "" {
+			query = query.Where(field.column+" = ?", field.value)
+		}
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(filter.Search)) + "%"
+		query = query.Where("LOWER(message) LIKE ? ESCAPE '!' OR LOWER(fields) LIKE ? ESCAPE '!'", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []*models.LogEntry
+	if err := query.Order("timestamp DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// DeleteBefore deletes log entries logged before the given time.
+func (r *logEntryRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("timestamp < ?", before).Delete(&models.LogEntry{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExcess deletes the log entries older than the newest keep entries.
+// Entries logged at the same instant as the oldest kept entry are kept too.
+func (r *logEntryRepository) DeleteExcess(ctx context.Context, keep int) (int64, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	var oldestKept []models.LogEntry
+	err := r.db.WithContext(ctx).Select("timestamp").
+		Order("timestamp DESC").
+		Offset(keep - 1).
+		Limit(1).
+		Find(&oldestKept).Error
+	if err != nil || len(oldestKept) == 0 {
+		return 0, err
+	}
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("timestamp < ?", oldestKept[0].Timestamp).
+		Delete(&models.LogEntry{})
+	return result.RowsAffected, result.Error
+}
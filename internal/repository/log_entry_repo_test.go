@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupLogEntryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.LogEntry{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func seedLogEntries(t *testing.T, repo LogEntryRepository, base time.Time) {
+	t.Helper()
+	entries := []*models.LogEntry{
+		{Timestamp: base, Level: "info", Message: "server started", Module: "server"},
+		{Timestamp: base.Add(time.Minute), Level: "debug", Message: "request completed", Module: "http", RequestID: "req-1"},
+		{Timestamp: base.Add(2 * time.Minute), Level: "warn", Message: "upstream slow", Module: "relay",
+			SessionID: "sess-1", ChannelID: "chan-1", Fields: `{"upstream":"provider_a"}`},
+		{Timestamp: base.Add(3 * time.Minute), Level: "error", Message: "upstream failed: 100% loss", Module: "relay",
+			SessionID: "sess-1", ChannelID: "chan-1"},
+		{Timestamp: base.Add(4 * time.Minute), Level: "info", Message: "session closed", Module: "relay", SessionID: "sess-2"},
+	}
+	require.NoError(t, repo.CreateBatch(context.Background(), entries))
+}
+
+func TestLogEntryRepo_Query(t *testing.T) {
+	repo := NewLogEntryRepository(setupLogEntryTestDB(t))
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	seedLogEntries(t, repo, base)
+
+	tests := []struct {
+		name     string
+		filter   LogEntryFilter
+		messages []string
+	}{
+		{"all newest first", LogEntryFilter{}, []string{"session closed", "upstream failed: 100% loss", "upstream slow", "request completed", "server started"}},
+		{"time range", LogEntryFilter{Since: base.Add(time.Minute), Until: base.Add(3 * time.Minute)}, []string{"upstream slow", "request completed"}},
+		{"levels", LogEntryFilter{Levels: []string{"warn", "error"}}, []string{"upstream failed: 100% loss", "upstream slow"}},
+		{"module", LogEntryFilter{Module: "http"}, []string{"request completed"}},
+		{"request", LogEntryFilter{RequestID: "req-1"}, []string{"request completed"}},
+		{"session and channel", LogEntryFilter{SessionID: "sess-1", ChannelID: "chan-1"}, []string{"upstream failed: 100% loss", "upstream slow"}},
+		{"search message", LogEntryFilter{Search: "SESSION"}, []string{"session closed"}},
+		{"search fields", LogEntryFilter{Search: "provider_a"}, []string{"upstream slow"}},
+		{"search wildcard is literal", LogEntryFilter{Search: "100%"}, []string{"upstream failed: 100% loss"}},
+		{"search underscore is literal", LogEntryFilter{Search: "r_a"}, []string{"upstream slow"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, total, err := repo.Query(context.Background(), tt.filter, 0, 100)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.messages)), total)
+			messages := make([]string, 0, len(entries))
+			for _, e := range entries {
+				messages = append(messages, e.Message)
+			}
+			assert.Equal(t, tt.messages, messages)
+		})
+	}
+
+	entries, total, err := repo.Query(context.Background(), LogEntryFilter{}, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "upstream failed: 100% loss", entries[0].Message)
+}
+
+func TestLogEntryRepo_Delete(t *testing.T) {
+	repo := NewLogEntryRepository(setupLogEntryTestDB(t))
+	ctx := context.Background()
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	seedLogEntries(t, repo, base)
+
+	deleted, err := repo.DeleteBefore(ctx, base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = repo.DeleteExcess(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	entries, total, err := repo.Query(ctx, LogEntryFilter{}, 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "session closed", entries[0].Message)
+	assert.Equal(t, "upstream failed: 100% loss", entries[1].Message)
+
+	deleted, err = repo.DeleteExcess(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
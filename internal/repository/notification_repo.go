@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// notificationRepository implements NotificationRepository using GORM.
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository.
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// CreateTarget creates a new notification target.
+func (r *notificationRepository) CreateTarget(ctx context.Context, target *models.NotificationTarget) error {
+	if err := target.Validate(); err != nil {
+		return fmt.Errorf("validating notification target: %w", err)
+	}
+	return r.db.WithContext(ctx).Create(target).Error
+}
+
+// GetTargetByID retrieves a notification target by ID.
+func (r *notificationRepository) GetTargetByID(ctx context.Context, id models.ULID) (*models.NotificationTarget, error) {
+	var target models.NotificationTarget
+	if err := r.db.WithContext(ctx).First(&target, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &target, nil
+}
+
+// GetTargetByName retrieves a notification target by name.
+func (r *notificationRepository) GetTargetByName(ctx context.Context, name string) (*models.NotificationTarget, error) {
+	var target models.NotificationTarget
+	if err := r.db.WithContext(ctx).First(&target, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &target, nil
+}
+
+// GetAllTargets retrieves all notification targets ordered by name.
+func (r *notificationRepository) GetAllTargets(ctx context.Context) ([]*models.NotificationTarget, error) {
+	var targets []*models.NotificationTarget
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&targets).Error; err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// GetEnabledTargets retrieves enabled notification targets ordered by name.
+func (r *notificationRepository) GetEnabledTargets(ctx context.Context) ([]*models.NotificationTarget, error) {
+	var targets []*models.NotificationTarget
+	if err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("name ASC").
+		Find(&targets).Error; err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// UpdateTarget updates an existing notification target.
+func (r *notificationRepository) UpdateTarget(ctx context.Context, target *models.NotificationTarget) error {
+	if err := target.Validate(); err != nil {
+		return fmt.Errorf("validating notification target: %w", err)
+	}
+	return r.db.WithContext(ctx).Save(target).Error
+}
+
+// DeleteTarget hard-deletes a notification target and its delivery log, so the name can be reused.
+func (r *notificationRepository) DeleteTarget(ctx context.Context, id models.ULID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&models.NotificationDelivery{}, "target_id = ?", id).Error; err != nil {
+			return fmt.Errorf("deleting notification deliveries: %w", err)
+		}
+		return tx.Unscoped().Delete(&models.NotificationTarget{}, "id = ?", id).Error
+	})
+}
+
+// CreateDelivery records a delivery.
+func (r *notificationRepository) CreateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	if err := r.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return fmt.Errorf("creating notification delivery: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveries retrieves deliveries with pagination, newest first.
+func (r *notificationRepository) GetDeliveries(ctx context.Context, targetID *models.ULID, offset, limit int) ([]*models.NotificationDelivery, int64, error) {
+	var deliveries []*models.NotificationDelivery
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.NotificationDelivery{})
+	if targetID != nil {
+		query = query.Where("target_id = ?", *targetID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("counting notification deliveries: %w", err)
+	}
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("getting notification deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
+
+// DeleteDeliveries hard-deletes deliveries created before the given time.
+func (r *notificationRepository) DeleteDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("created_at < ?", before).
+		Delete(&models.NotificationDelivery{})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("deleting notification deliveries: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// recordingRepository implements RecordingRepository using GORM.
+type recordingRepository struct {
+	db *gorm.DB
+}
+
+// NewRecordingRepository creates a new RecordingRepository.
+func NewRecordingRepository(db *gorm.DB) RecordingRepository {
+	return &recordingRepository{db: db}
+}
+
+// Create creates a new recording.
+func (r *recordingRepository) Create(ctx context.Context, recording *models.Recording) error {
+	if err := recording.Validate(); err != nil {
+		return fmt.Errorf("validating recording: %w", err)
+	}
+	return r.db.WithContext(ctx).Create(recording).Error
+}
+
+// GetByID retrieves a recording by ID.
+func (r *recordingRepository) GetByID(ctx context.Context, id models.ULID) (*models.Recording, error) {
+	var recording models.Recording
+	if err := r.db.WithContext(ctx).First(&recording, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recording, nil
+}
+
+// GetAll retrieves all recordings, newest start time first.
+func (r *recordingRepository) GetAll(ctx context.Context) ([]*models.Recording, error) {
+	var recordings []*models.Recording
+	if err := r.db.WithContext(ctx).Order("start_time DESC").Find(&recordings).Error; err != nil {
+		return nil, err
+	}
+	return recordings, nil
+}
+
+// GetByStatus retrieves recordings in any of the given statuses ordered by start time.
+func (r *recordingRepository) GetByStatus(ctx context.Context, statuses ...models.RecordingStatus) ([]*models.Recording, error) {
+	var recordings []*models.Recording
+	if err := r.db.WithContext(ctx).
+		Where("status IN ?", statuses).
+		Order("start_time ASC").
+		Find(&recordings).Error; err != nil {
+		return nil, err
+	}
+	return recordings, nil
+}
+
+// Update updates an existing recording.
+func (r *recordingRepository) Update(ctx context.Context, recording *models.Recording) error {
+	if err := recording.Validate(); err != nil {
+		return fmt.Errorf("validating recording: %w", err)
+	}
+	return r.db.WithContext(ctx).Save(recording).Error
+}
+
+// Delete hard-deletes a recording by ID.
+func (r *recordingRepository) Delete(ctx context.Context, id models.ULID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.Recording{}, "id = ?", id).Error
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRecordingTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.Recording{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func newTestRecording(title string, start time.Time, status models.RecordingStatus) *models.Recording {
+	return &models.Recording{
+		ChannelID: models.NewULID(),
+		Title:     title,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Format:    models.RecordingFormatMPEGTS,
+		Status:    status,
+	}
+}
+
+func TestRecordingRepo_CRUD(t *testing.T) {
+	db := setupRecordingTestDB(t)
+	repo := NewRecordingRepository(db)
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	rec := newTestRecording("Film", start, models.RecordingStatusScheduled)
+	rec.PaddingBefore = 60
+	require.NoError(t, repo.Create(ctx, rec))
+	assert.False(t, rec.ID.IsZero())
+
+	found, err := repo.GetByID(ctx, rec.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "Film", found.Title)
+	assert.Equal(t, 60, found.PaddingBefore)
+	assert.True(t, found.StartTime.Equal(start))
+
+	found.Status = models.RecordingStatusRecording
+	require.NoError(t, repo.Update(ctx, found))
+
+	found, err = repo.GetByID(ctx, rec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RecordingStatusRecording, found.Status)
+
+	require.NoError(t, repo.Delete(ctx, rec.ID))
+	found, err = repo.GetByID(ctx, rec.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestRecordingRepo_CreateValidates(t *testing.T) {
+	db := setupRecordingTestDB(t)
+	repo := NewRecordingRepository(db)
+
+	rec := newTestRecording("", time.Now(), models.RecordingStatusScheduled)
+	err := repo.Create(context.Background(), rec)
+	var ve models.ValidationError
+	assert.ErrorAs(t, err, &ve)
+}
+
+func TestRecordingRepo_GetByStatus(t *testing.T) {
+	db := setupRecordingTestDB(t)
+	repo := NewRecordingRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, repo.Create(ctx, newTestRecording("later", now.Add(2*time.Hour), models.RecordingStatusScheduled)))
+	require.NoError(t, repo.Create(ctx, newTestRecording("now", now, models.RecordingStatusRecording)))
+	require.NoError(t, repo.Create(ctx, newTestRecording("done", now.Add(-2*time.Hour), models.RecordingStatusCompleted)))
+
+	active, err := repo.GetByStatus(ctx, models.RecordingStatusScheduled, models.RecordingStatusRecording)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, "now", active[0].Title)
+	assert.Equal(t, "later", active[1].Title)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "later", all[0].Title)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// recordingRuleRepository implements RecordingRuleRepository using GORM.
+type recordingRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewRecordingRuleRepository creates a new RecordingRuleRepository.
+func NewRecordingRuleRepository(db *gorm.DB) RecordingRuleRepository {
+	return &recordingRuleRepository{db: db}
+}
+
+// Create creates a new recording rule.
+func (r *recordingRuleRepository) Create(ctx context.Context, rule *models.RecordingRule) error {
+	if err := rule.Validate(); err != nil {
+		return fmt.Errorf("validating recording rule: %w", err)
+	}
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// GetByID retrieves a recording rule by ID.
+func (r *recordingRuleRepository) GetByID(ctx context.Context, id models.ULID) (*models.RecordingRule, error) {
+	var rule models.RecordingRule
+	if err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetAll retrieves all recording rules ordered by creation time.
+func (r *recordingRuleRepository) GetAll(ctx context.Context) ([]*models.RecordingRule, error) {
+	var rules []*models.RecordingRule
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetEnabled retrieves enabled recording rules ordered by creation time.
+func (r *recordingRuleRepository) GetEnabled(ctx context.Context) ([]*models.RecordingRule, error) {
+	var rules []*models.RecordingRule
+	if err := r.db.WithContext(ctx).
+		Where("is_enabled = ?", true).
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Update updates an existing recording rule.
+func (r *recordingRuleRepository) Update(ctx context.Context, rule *models.RecordingRule) error {
+	if err := rule.Validate(); err != nil {
+		return fmt.Errorf("validating recording rule: %w", err)
+	}
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// Delete hard-deletes a recording rule by ID.
+func (r *recordingRuleRepository) Delete(ctx context.Context, id models.ULID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.RecordingRule{}, "id = ?", id).Error
+}
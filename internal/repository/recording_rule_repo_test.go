@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRecordingRuleTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.RecordingRule{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestRecordingRuleRepo_CRUD(t *testing.T) {
+	db := setupRecordingRuleTestDB(t)
+	repo := NewRecordingRuleRepository(db)
+	ctx := context.Background()
+
+	padding := 600
+	rule := &models.RecordingRule{
+		Name:         "Match of the Day",
+		Expression:   `programme_title matches "^Match of the Day"`,
+		PaddingAfter: &padding,
+	}
+	require.NoError(t, repo.Create(ctx, rule))
+	assert.False(t, rule.ID.IsZero())
+
+	found, err := repo.GetByID(ctx, rule.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, rule.Expression, found.Expression)
+	assert.True(t, models.BoolVal(found.IsEnabled))
+	require.NotNil(t, found.PaddingAfter)
+	assert.Equal(t, 600, *found.PaddingAfter)
+	assert.Nil(t, found.PaddingBefore)
+
+	found.Name = "MOTD"
+	require.NoError(t, repo.Update(ctx, found))
+	found, err = repo.GetByID(ctx, rule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "MOTD", found.Name)
+
+	require.NoError(t, repo.Delete(ctx, rule.ID))
+	found, err = repo.GetByID(ctx, rule.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestRecordingRuleRepo_GetEnabled(t *testing.T) {
+	db := setupRecordingRuleTestDB(t)
+	repo := NewRecordingRuleRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &models.RecordingRule{Name: "on", Expression: `title contains "a"`}))
+	require.NoError(t, repo.Create(ctx, &models.RecordingRule{Name: "off", Expression: `title contains "b"`, IsEnabled: new(false)}))
+
+	enabled, err := repo.GetEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 1)
+	assert.Equal(t, "on", enabled[0].Name)
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/database"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seriesQueryChunk bounds the number of series loaded, and IDs in a single IN
+// clause, per query.
+const seriesQueryChunk = 500
+
+// seriesRepository implements SeriesRepository using GORM.
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+// NewSeriesRepository creates a new SeriesRepository.
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+// GetLastModifiedBySourceID returns the provider modification timestamp of each
+// stored series of a source, keyed by external ID.
+func (r *seriesRepository) GetLastModifiedBySourceID(ctx context.Context, sourceID models.ULID) (map[string]int64, error) {
+	var rows []struct {
+		ExtID        string
+		LastModified int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.Series{}).
+		Select("ext_id", "last_modified").
+		Where("source_id = ?", sourceID).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("querying series: %w", err)
+	}
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.ExtID] = row.LastModified
+	}
+	return result, nil
+}
+
+// UpsertBatch creates or updates series, keyed by source and external ID. The
+// episodes of series with Episodes set are replaced; other series keep theirs.
+func (r *seriesRepository) UpsertBatch(ctx context.Context, series []*models.Series) error {
+	if len(series) == 0 {
+		return nil
+	}
+	for _, s := range series {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("validating series %q: %w", s.Name, err)
+		}
+	}
+
+	return database.WithRetry(ctx, database.DefaultRetryConfig, nil, "UpsertSeriesBatch", func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "source_id"}, {Name: "ext_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"name", "group_title", "cover", "genre", "rating", "year",
+					"last_modified", "updated_at",
+				}),
+			}).Create(series).Error; err != nil {
+				return fmt.Errorf("upserting series batch: %w", err)
+			}
+			return r.replaceEpisodes(tx, series)
+		})
+	})
+}
+
+// replaceEpisodes replaces the stored episodes of the series that carry them.
+// Upserted series keep their stored IDs, so the IDs are looked up again.
+func (r *seriesRepository) replaceEpisodes(tx *gorm.DB, series []*models.Series) error {
+	bySource := make(map[models.ULID][]string)
+	for _, s := range series {
+		if s.Episodes != nil {
+			bySource[s.SourceID] = append(bySource[s.SourceID], s.ExtID)
+		}
+	}
+
+	for sourceID, extIDs := range bySource {
+		var stored []*models.Series
+		if err := tx.Select("id", "ext_id").
+			Where("source_id = ? AND ext_id IN ?", sourceID, extIDs).
+			Find(&stored).Error; err != nil {
+			return fmt.Errorf("querying series IDs: %w", err)
+		}
+		ids := make(map[string]models.ULID, len(stored))
+		seriesIDs := make([]models.ULID, 0, len(stored))
+		for _, s := range stored {
+			ids[s.ExtID] = s.ID
+			seriesIDs = append(seriesIDs, s.ID)
+		}
+
+		if err := tx.Unscoped().Where("series_id IN ?", seriesIDs).Delete(&models.SeriesEpisode{}).Error; err != nil {
+			return fmt.Errorf("deleting episodes: %w", err)
+		}
+
+		var episodes []*models.SeriesEpisode
+		for _, s := range series {
+			if s.Episodes == nil || s.SourceID != sourceID {
+				continue
+			}
+			s.ID = ids[s.ExtID]
+			for _, ep := range s.Episodes {
+				ep.SeriesID = s.ID
+				ep.SourceID = sourceID
+				episodes = append(episodes, ep)
+			}
+		}
+		if len(episodes) > 0 {
+			if err := tx.CreateInBatches(episodes, seriesQueryChunk).Error; err != nil {
+				return fmt.Errorf("creating episodes: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetBySourceIDs streams the series of the given sources with their episodes,
+// ordered by category then name. Episodes are ordered by season and number.
+func (r *seriesRepository) GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID, callback func(*models.Series) error) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	for offset := 0; ; offset += seriesQueryChunk {
+		var page []*models.Series
+		if err := r.db.WithContext(ctx).
+			Where("source_id IN ?", sourceIDs).
+			Order("group_title ASC").
+			Order("name ASC").
+			Order("id ASC").
+			Offset(offset).
+			Limit(seriesQueryChunk).
+			Find(&page).Error; err != nil {
+			return fmt.Errorf("querying series: %w", err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		ids := make([]models.ULID, len(page))
+		byID := make(map[models.ULID]*models.Series, len(page))
+		for i, s := range page {
+			ids[i] = s.ID
+			byID[s.ID] = s
+		}
+		var episodes []*models.SeriesEpisode
+		if err := r.db.WithContext(ctx).
+			Where("series_id IN ?", ids).
+			Order("season ASC").
+			Order("episode_num ASC").
+			Find(&episodes).Error; err != nil {
+			return fmt.Errorf("querying episodes: %w", err)
+		}
+		for _, ep := range episodes {
+			if s := byID[ep.SeriesID]; s != nil {
+				s.Episodes = append(s.Episodes, ep)
+			}
+		}
+
+		for _, s := range page {
+			if err := callback(s); err != nil {
+				return err
+			}
+		}
+		if len(page) < seriesQueryChunk {
+			return nil
+		}
+	}
+}
+
+// DeleteStaleBySourceID deletes series of a source not updated since olderThan,
+// with their episodes.
+func (r *seriesRepository) DeleteStaleBySourceID(ctx context.Context, sourceID models.ULID, olderThan time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&models.Series{}).Select("id").Where("source_id = ? AND updated_at < ?", sourceID, olderThan)
+		if err := tx.Unscoped().Where("series_id IN (?)", stale).Delete(&models.SeriesEpisode{}).Error; err != nil {
+			return fmt.Errorf("deleting stale episodes: %w", err)
+		}
+		result := tx.Unscoped().Where("source_id = ? AND updated_at < ?", sourceID, olderThan).Delete(&models.Series{})
+		if result.Error != nil {
+			return fmt.Errorf("deleting stale series: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	return deleted, err
+}
+
+// DeleteBySourceID deletes all series of a source with their episodes.
+func (r *seriesRepository) DeleteBySourceID(ctx context.Context, sourceID models.ULID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("source_id = ?", sourceID).Delete(&models.SeriesEpisode{}).Error; err != nil {
+			return fmt.Errorf("deleting episodes: %w", err)
+		}
+		if err := tx.Unscoped().Where("source_id = ?", sourceID).Delete(&models.Series{}).Error; err != nil {
+			return fmt.Errorf("deleting series: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountBySourceID returns the number of series of a source.
+func (r *seriesRepository) CountBySourceID(ctx context.Context, sourceID models.ULID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Series{}).Where("source_id = ?", sourceID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("counting series: %w", err)
+	}
+	return count, nil
+}
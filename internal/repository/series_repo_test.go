@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSeries creates a series of a source with the given episode titles in season 1.
+func testSeries(sourceID models.ULID, extID, name string, lastModified int64, titles ...string) *models.Series {
+	s := &models.Series{
+		SourceID:     sourceID,
+		ExtID:        extID,
+		Name:         name,
+		LastModified: lastModified,
+	}
+	if titles != nil {
+		s.Episodes = []*models.SeriesEpisode{}
+	}
+	for i, title := range titles {
+		s.Episodes = append(s.Episodes, &models.SeriesEpisode{
+			ExtID:      extID + "-" + title,
+			Season:     1,
+			EpisodeNum: i + 1,
+			Title:      title,
+			StreamURL:  "http://example.com/series/" + title + ".mp4",
+		})
+	}
+	return s
+}
+
+// episodeTitles returns the stored episode titles of each series, by name.
+func episodeTitles(t *testing.T, repo SeriesRepository, sourceIDs ...models.ULID) map[string][]string {
+	result := make(map[string][]string)
+	require.NoError(t, repo.GetBySourceIDs(context.Background(), sourceIDs, func(s *models.Series) error {
+		titles := []string{}
+		for _, ep := range s.Episodes {
+			titles = append(titles, ep.Title)
+		}
+		result[s.Name] = titles
+		return nil
+	}))
+	return result
+}
+
+func TestSeriesRepo_UpsertBatch_Episodes(t *testing.T) {
+	db := setupVodTestDB(t)
+	repo := NewSeriesRepository(db)
+	ctx := context.Background()
+	source := models.NewULID()
+
+	require.NoError(t, repo.UpsertBatch(ctx, []*models.Series{
+		testSeries(source, "7", "The Wire", 100, "Pilot", "Second"),
+		testSeries(source, "8", "Lost", 100, "Pilot"),
+	}))
+
+	// Series without episodes keep their stored ones; others are replaced
+	require.NoError(t, repo.UpsertBatch(ctx, []*models.Series{
+		testSeries(source, "7", "The Wire", 100),
+		testSeries(source, "8", "Lost", 200, "Pilot", "Tabula Rasa"),
+	}))
+
+	assert.Equal(t, map[string][]string{
+		"The Wire": {"Pilot", "Second"},
+		"Lost":     {"Pilot", "Tabula Rasa"},
+	}, episodeTitles(t, repo, source))
+
+	modified, err := repo.GetLastModifiedBySourceID(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"7": 100, "8": 200}, modified)
+}
+
+func TestSeriesRepo_DeleteStaleBySourceID(t *testing.T) {
+	db := setupVodTestDB(t)
+	repo := NewSeriesRepository(db)
+	ctx := context.Background()
+	source := models.NewULID()
+	other := models.NewULID()
+
+	require.NoError(t, repo.UpsertBatch(ctx, []*models.Series{
+		testSeries(source, "7", "The Wire", 100, "Pilot"),
+		testSeries(source, "8", "Lost", 100, "Pilot"),
+		testSeries(other, "7", "Other", 100, "Pilot"),
+	}))
+
+	time.Sleep(10 * time.Millisecond)
+	start := time.Now()
+	require.NoError(t, repo.UpsertBatch(ctx, []*models.Series{testSeries(source, "7", "The Wire", 100)}))
+
+	deleted, err := repo.DeleteStaleBySourceID(ctx, source, start)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	var episodes int64
+	require.NoError(t, db.Model(&models.SeriesEpisode{}).Count(&episodes).Error)
+	assert.Equal(t, int64(2), episodes)
+
+	require.NoError(t, repo.DeleteBySourceID(ctx, source))
+	assert.Equal(t, map[string][]string{"Other": {"Pilot"}}, episodeTitles(t, repo, source, other))
+
+	count, err := repo.CountBySourceID(ctx, source)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
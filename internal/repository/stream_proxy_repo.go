@@ -103,6 +103,9 @@ func (r *streamProxyRepo) Delete(ctx context.Context, id models.ULID) error {
 		if err := tx.Unscoped().Where("proxy_id = ?", id).Delete(&models.ProxyEpgSource{}).Error; err != nil {
 			return fmt.Errorf("deleting proxy epg sources: %w", err)
 		}
+		if err := tx.Unscoped().Where("proxy_id = ?", id).Delete(&models.ProxyChannelAlternate{}).Error; err != nil {
+			return fmt.Errorf("deleting proxy channel alternates: %w", err)
+		}
 		// Delete the proxy itself
 		if err := tx.Unscoped().Where("id = ?", id).Delete(&models.StreamProxy{}).Error; err != nil {
 			return fmt.Errorf("deleting stream proxy: %w", err)
@@ -290,6 +293,38 @@ func (r *streamProxyRepo) GetFilters(ctx context.Context, proxyID models.ULID) (
 	return filters, nil
 }
 
+// SetChannelAlternates sets the deduplicated channel alternates for a proxy (replaces existing).
+func (r *streamProxyRepo) SetChannelAlternates(ctx context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("proxy_id = ?", proxyID).Delete(&models.ProxyChannelAlternate{}).Error; err != nil {
+			return fmt.Errorf("clearing existing channel alternates: %w", err)
+		}
+		if len(alternates) == 0 {
+			return nil
+		}
+
+		for _, alt := range alternates {
+			alt.ProxyID = proxyID
+		}
+		if err := tx.CreateInBatches(alternates, 500).Error; err != nil {
+			return fmt.Errorf("adding channel alternates: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetChannelAlternates retrieves the alternates of a proxy channel in priority order.
+func (r *streamProxyRepo) GetChannelAlternates(ctx context.Context, proxyID, channelID models.ULID) ([]*models.ProxyChannelAlternate, error) {
+	var alternates []*models.ProxyChannelAlternate
+	if err := r.db.WithContext(ctx).
+		Where("proxy_id = ? AND channel_id = ?", proxyID, channelID).
+		Order("priority ASC").
+		Find(&alternates).Error; err != nil {
+		return nil, fmt.Errorf("getting channel alternates: %w", err)
+	}
+	return alternates, nil
+}
+
 // GetBySourceID retrieves all proxies that use a specific stream source.
 // Used for auto-regeneration when a source is updated.
 func (r *streamProxyRepo) GetBySourceID(ctx context.Context, sourceID models.ULID) ([]*models.StreamProxy, error) {
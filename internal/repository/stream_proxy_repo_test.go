@@ -31,6 +31,7 @@ func setupProxyTestDB(t *testing.T) *gorm.DB {
 		&models.ProxyFilter{},
 		&models.DataMappingRule{},
 		&models.ProxyMappingRule{},
+		&models.ProxyChannelAlternate{},
 	)
 	require.NoError(t, err)
 
@@ -433,6 +434,37 @@ func TestStreamProxyRepo_SetEpgSources(t *testing.T) {
 	assert.Equal(t, epg1.ID, sources[1].ID)
 }
 
+func TestStreamProxyRepo_SetChannelAlternates(t *testing.T) {
+	db := setupProxyTestDB(t)
+	repo := NewStreamProxyRepository(db)
+	ctx := context.Background()
+
+	proxy := &models.StreamProxy{Name: "Dedup Proxy"}
+	require.NoError(t, repo.Create(ctx, proxy))
+
+	kept := models.NewULID()
+	first := models.NewULID()
+	second := models.NewULID()
+
+	err := repo.SetChannelAlternates(ctx, proxy.ID, []*models.ProxyChannelAlternate{
+		{ChannelID: kept, AlternateChannelID: second, Priority: 1},
+		{ChannelID: kept, AlternateChannelID: first, Priority: 0},
+	})
+	require.NoError(t, err)
+
+	alternates, err := repo.GetChannelAlternates(ctx, proxy.ID, kept)
+	require.NoError(t, err)
+	require.Len(t, alternates, 2)
+	assert.Equal(t, first, alternates[0].AlternateChannelID)
+	assert.Equal(t, second, alternates[1].AlternateChannelID)
+
+	// Replacing with nothing clears the proxy's alternates
+	require.NoError(t, repo.SetChannelAlternates(ctx, proxy.ID, nil))
+	alternates, err = repo.GetChannelAlternates(ctx, proxy.ID, kept)
+	require.NoError(t, err)
+	assert.Empty(t, alternates)
+}
+
 func TestStreamProxyRepo_GetSources_Priority(t *testing.T) {
 	db := setupProxyTestDB(t)
 	repo := NewStreamProxyRepository(db)
@@ -131,5 +131,24 @@ func (r *streamSourceRepo) UpdateStatus(ctx context.Context, id models.ULID, sta
 	return nil
 }
 
+// UpdateAccountStatus updates the Xtream account columns and the concurrent
+// stream limit of a stream source.
+func (r *streamSourceRepo) UpdateAccountStatus(ctx context.Context, source *models.StreamSource) error {
+	updates := map[string]any{
+		"account_status":             source.AccountStatus,
+		"account_expires_at":         source.AccountExpiresAt,
+		"account_max_connections":    source.AccountMaxConnections,
+		"account_active_connections": source.AccountActiveConnections,
+		"account_checked_at":         source.AccountCheckedAt,
+		"max_concurrent_streams":     source.MaxConcurrentStreams,
+	}
+
+	// SkipHooks: same rationale as UpdateLastIngestion — bare Model() + targeted Updates().
+	if err := r.db.WithContext(ctx).Session(&gorm.Session{SkipHooks: true}).Model(&models.StreamSource{}).Where("id = ?", source.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("updating account status: %w", err)
+	}
+	return nil
+}
+
 // Ensure streamSourceRepo implements StreamSourceRepository at compile time.
 var _ StreamSourceRepository = (*streamSourceRepo)(nil)
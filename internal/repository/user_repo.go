@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// userRepository implements UserRepository using GORM.
+type userRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new UserRepository.
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create creates a new user.
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	if err := user.Validate(); err != nil {
+		return fmt.Errorf("validating user: %w", err)
+	}
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// GetByID retrieves a user by ID.
+func (r *userRepository) GetByID(ctx context.Context, id models.ULID) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByUsername retrieves a user by username.
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "username = ?", username).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetAll retrieves all users ordered by username.
+func (r *userRepository) GetAll(ctx context.Context) ([]*models.User, error) {
+	var users []*models.User
+	if err := r.db.WithContext(ctx).Order("username ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Update updates an existing user.
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	if err := user.Validate(); err != nil {
+		return fmt.Errorf("validating user: %w", err)
+	}
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+// Delete hard-deletes a user by ID so the username can be reused.
+func (r *userRepository) Delete(ctx context.Context, id models.ULID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.User{}, "id = ?", id).Error
+}
+
+// Count returns the total number of users.
+func (r *userRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// viewerRepository implements ViewerRepository using GORM.
+type viewerRepository struct {
+	db *gorm.DB
+}
+
+// NewViewerRepository creates a new ViewerRepository.
+func NewViewerRepository(db *gorm.DB) ViewerRepository {
+	return &viewerRepository{db: db}
+}
+
+// Create creates a new viewer.
+func (r *viewerRepository) Create(ctx context.Context, viewer *models.Viewer) error {
+	if err := viewer.Validate(); err != nil {
+		return fmt.Errorf("validating viewer: %w", err)
+	}
+	return r.db.WithContext(ctx).Create(viewer).Error
+}
+
+// GetByID retrieves a viewer by ID.
+func (r *viewerRepository) GetByID(ctx context.Context, id models.ULID) (*models.Viewer, error) {
+	var viewer models.Viewer
+	if err := r.db.WithContext(ctx).First(&viewer, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &viewer, nil
+}
+
+// GetByName retrieves a viewer by name.
+func (r *viewerRepository) GetByName(ctx context.Context, name string) (*models.Viewer, error) {
+	var viewer models.Viewer
+	if err := r.db.WithContext(ctx).First(&viewer, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &viewer, nil
+}
+
+// GetByToken retrieves a viewer by stream token.
+func (r *viewerRepository) GetByToken(ctx context.Context, token string) (*models.Viewer, error) {
+	var viewer models.Viewer
+	if err := r.db.WithContext(ctx).First(&viewer, "token = ?", token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &viewer, nil
+}
+
+// GetAll retrieves all viewers ordered by name.
+func (r *viewerRepository) GetAll(ctx context.Context) ([]*models.Viewer, error) {
+	var viewers []*models.Viewer
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&viewers).Error; err != nil {
+		return nil, err
+	}
+	return viewers, nil
+}
+
+// Update updates an existing viewer.
+func (r *viewerRepository) Update(ctx context.Context, viewer *models.Viewer) error {
+	if err := viewer.Validate(); err != nil {
+		return fmt.Errorf("validating viewer: %w", err)
+	}
+	return r.db.WithContext(ctx).Save(viewer).Error
+}
+
+// TouchLastSeen records when a viewer was last seen without changing updated_at.
+func (r *viewerRepository) TouchLastSeen(ctx context.Context, id models.ULID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Viewer{}).
+		Where("id = ?", id).
+		UpdateColumn("last_seen_at", at).Error
+}
+
+// Delete hard-deletes a viewer by ID so the name can be reused.
+func (r *viewerRepository) Delete(ctx context.Context, id models.ULID) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&models.Viewer{}, "id = ?", id).Error
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+)
+
+// viewingSessionRepository implements ViewingSessionRepository using GORM.
+type viewingSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewViewingSessionRepository creates a new ViewingSessionRepository.
+func NewViewingSessionRepository(db *gorm.DB) ViewingSessionRepository {
+	return &viewingSessionRepository{db: db}
+}
+
+// Create records a finished viewing session.
+func (r *viewingSessionRepository) Create(ctx context.Context, session *models.ViewingSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetSessions retrieves viewing sessions matching the filter, most recent first.
+func (r *viewingSessionRepository) GetSessions(ctx context.Context, filter ViewingSessionFilter, offset, limit int) ([]*models.ViewingSession, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ViewingSession{})
+	if filter.ChannelID != nil {
+		query = query.Where("channel_id = ?", *filter.ChannelID)
+	}
+	if filter.ProxyID != nil {
+		query = query.Where("proxy_id = ?", *filter.ProxyID)
+	}
+	if filter.ViewerID != nil {
+		query = query.Where("viewer_id = ?", *filter.ViewerID)
+	}
+	query = startedBetween(query, filter.Since, filter.Until)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var sessions []*models.ViewingSession
+	if err := query.Order("started_at DESC").Offset(offset).Limit(limit).Find(&sessions).Error; err != nil {
+		return nil, 0, err
+	}
+	return sessions, total, nil
+}
+
+// GetTopChannels summarizes viewings started in [since, until) by channel,
+// ordered by total viewing time.
+func (r *viewingSessionRepository) GetTopChannels(ctx context.Context, since, until time.Time, limit int) ([]*models.ChannelViewingStats, error) {
+	var stats []*models.ChannelViewingStats
+	query := r.db.WithContext(ctx).Model(&models.ViewingSession{}).
+		Select("channel_id, MAX(channel_name) AS channel_name, COUNT(*) AS viewings, " +
+			"COUNT(DISTINCT client_ip) AS unique_clients, " +
+			"SUM(duration_ms) AS duration_ms, SUM(bytes_sent) AS bytes_sent")
+	err := startedBetween(query, since, until).
+		Group("channel_id").
+		Order("SUM(duration_ms) DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetTranscodeStats summarizes transcoded viewings started in [since, until) by encoding profile.
+func (r *viewingSessionRepository) GetTranscodeStats(ctx context.Context, since, until time.Time) ([]*models.ProfileTranscodeStats, error) {
+	var stats []*models.ProfileTranscodeStats
+	query := r.db.WithContext(ctx).Model(&models.ViewingSession{}).
+		Select("encoding_profile_name, COUNT(*) AS viewings, SUM(duration_ms) AS duration_ms").
+		Where("delivery_route = ?", "transcode")
+	err := startedBetween(query, since, until).
+		Group("encoding_profile_name").
+		Order("SUM(duration_ms) DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetOverlapping retrieves the source and times of viewing sessions overlapping [since, until).
+func (r *viewingSessionRepository) GetOverlapping(ctx context.Context, since, until time.Time) ([]*models.ViewingSession, error) {
+	var sessions []*models.ViewingSession
+	err := r.db.WithContext(ctx).
+		Select("source_id", "source_name", "started_at", "ended_at").
+		Where("started_at < ? AND ended_at > ?", until, since).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// DeleteBefore deletes viewing sessions that ended before the given time.
+func (r *viewingSessionRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("ended_at < ?", before).Delete(&models.ViewingSession{})
+	return result.RowsAffected, result.Error
+}
+
+// startedBetween limits a viewing session query to sessions started in [since, until).
+// A zero bound is not applied.
+func startedBetween(query *gorm.DB, since, until time.Time) *gorm.DB {
+	if !since.IsZero() {
+		query = query.Where("started_at >= ?", since)
+	}
+	if !until.IsZero() {
+		query = query.Where("started_at < ?", until)
+	}
+	return query
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/database"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// vodRepository implements VodRepository using GORM.
+type vodRepository struct {
+	db *gorm.DB
+}
+
+// NewVodRepository creates a new VodRepository.
+func NewVodRepository(db *gorm.DB) VodRepository {
+	return &vodRepository{db: db}
+}
+
+// UpsertBatch creates or updates movies, keyed by source and external ID.
+func (r *vodRepository) UpsertBatch(ctx context.Context, items []*models.VodItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("validating movie %q: %w", item.Name, err)
+		}
+	}
+
+	return database.WithRetry(ctx, database.DefaultRetryConfig, nil, "UpsertVodBatch", func() error {
+		if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "source_id"}, {Name: "ext_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"name", "group_title", "logo", "stream_url", "container_extension",
+				"rating", "year", "is_adult", "updated_at",
+			}),
+		}).Create(items).Error; err != nil {
+			return fmt.Errorf("upserting movie batch: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetBySourceIDs streams the movies of the given sources, ordered by category
+// then name.
+func (r *vodRepository) GetBySourceIDs(ctx context.Context, sourceIDs []models.ULID, callback func(*models.VodItem) error) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+	rows, err := r.db.WithContext(ctx).
+		Model(&models.VodItem{}).
+		Where("source_id IN ?", sourceIDs).
+		Order("group_title ASC").
+		Order("name ASC").
+		Rows()
+	if err != nil {
+		return fmt.Errorf("querying movies: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.VodItem
+		if err := r.db.ScanRows(rows, &item); err != nil {
+			return fmt.Errorf("scanning movie row: %w", err)
+		}
+		if err := callback(&item); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating movies: %w", err)
+	}
+	return nil
+}
+
+// DeleteStaleBySourceID deletes movies of a source not updated since olderThan.
+func (r *vodRepository) DeleteStaleBySourceID(ctx context.Context, sourceID models.ULID, olderThan time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("source_id = ? AND updated_at < ?", sourceID, olderThan).
+		Delete(&models.VodItem{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("deleting stale movies: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteBySourceID deletes all movies of a source.
+func (r *vodRepository) DeleteBySourceID(ctx context.Context, sourceID models.ULID) error {
+	if err := r.db.WithContext(ctx).Unscoped().Where("source_id = ?", sourceID).Delete(&models.VodItem{}).Error; err != nil {
+		return fmt.Errorf("deleting movies: %w", err)
+	}
+	return nil
+}
+
+// CountBySourceID returns the number of movies of a source.
+func (r *vodRepository) CountBySourceID(ctx context.Context, sourceID models.ULID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.VodItem{}).Where("source_id = ?", sourceID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("counting movies: %w", err)
+	}
+	return count, nil
+}
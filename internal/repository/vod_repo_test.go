@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupVodTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&models.VodItem{}, &models.Series{}, &models.SeriesEpisode{})
+	require.NoError(t, err)
+
+	return db
+}
+
+// testMovie creates a movie of a source.
+func testMovie(sourceID models.ULID, extID, name, group string) *models.VodItem {
+	return &models.VodItem{
+		SourceID:   sourceID,
+		ExtID:      extID,
+		Name:       name,
+		GroupTitle: group,
+		StreamURL:  "http://example.com/movie/" + extID + ".mp4",
+	}
+}
+
+func TestVodRepo_UpsertAndSweep(t *testing.T) {
+	db := setupVodTestDB(t)
+	repo := NewVodRepository(db)
+	ctx := context.Background()
+
+	sourceA := models.NewULID()
+	sourceB := models.NewULID()
+
+	require.NoError(t, repo.UpsertBatch(ctx, []*models.VodItem{
+		testMovie(sourceA, "1", "Heat", "Action"),
+		testMovie(sourceA, "2", "Alien", "Horror"),
+		testMovie(sourceB, "1", "Up", "Family"),
+	}))
+
+	// Re-ingest source A: one movie changes, one disappears
+	time.Sleep(10 * time.Millisecond)
+	start := time.Now()
+	updated := testMovie(sourceA, "1", "Heat (1995)", "Action")
+	updated.Year = 1995
+	require.NoError(t, repo.UpsertBatch(ctx, []*models.VodItem{updated}))
+
+	deleted, err := repo.DeleteStaleBySourceID(ctx, sourceA, start)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	var names []string
+	require.NoError(t, repo.GetBySourceIDs(ctx, []models.ULID{sourceA, sourceB}, func(item *models.VodItem) error {
+		names = append(names, item.Name)
+		return nil
+	}))
+	assert.Equal(t, []string{"Heat (1995)", "Up"}, names)
+
+	count, err := repo.CountBySourceID(ctx, sourceA)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, repo.DeleteBySourceID(ctx, sourceB))
+	count, err = repo.CountBySourceID(ctx, sourceB)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestVodRepo_UpsertBatch_Invalid(t *testing.T) {
+	db := setupVodTestDB(t)
+	repo := NewVodRepository(db)
+
+	err := repo.UpsertBatch(context.Background(), []*models.VodItem{{Name: "No source", StreamURL: "http://x"}})
+	assert.ErrorIs(t, err, models.ErrSourceIDRequired)
+}
@@ -2,11 +2,15 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
+	"github.com/jmylchreest/tvarr/internal/ingestor"
 	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/repository"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // JobHandler defines the interface for handling specific job types.
@@ -22,11 +26,15 @@ type AutoRegenerationTrigger interface {
 }
 
 // SourceIngestService defines the service interface for stream ingestion.
+// Ingest returns ingestor.ErrSourceUnchanged when the source content has not
+// changed since the last ingestion.
 type SourceIngestService interface {
 	Ingest(ctx context.Context, sourceID models.ULID) error
 }
 
 // EpgIngestService defines the service interface for EPG ingestion.
+// Ingest returns ingestor.ErrSourceUnchanged when the source content has not
+// changed since the last ingestion.
 type EpgIngestService interface {
 	Ingest(ctx context.Context, sourceID models.ULID) error
 }
@@ -60,6 +68,25 @@ type BackupCreateService interface {
 	CleanupOldBackups(ctx context.Context) (deleted int, err error)
 }
 
+// AccountCheckService defines the service interface for checking Xtream accounts.
+type AccountCheckService interface {
+	// RefreshAccounts checks the account of every enabled Xtream source.
+	RefreshAccounts(ctx context.Context) (checked, failed int, err error)
+}
+
+// RecordingStarter defines the service interface for starting scheduled recordings.
+type RecordingStarter interface {
+	// StartRecording begins capturing a recording in the background and returns a result message.
+	StartRecording(ctx context.Context, recordingID models.ULID) (string, error)
+}
+
+// RecordingRuleEvaluator schedules recordings for programmes matched by series recording rules.
+type RecordingRuleEvaluator interface {
+	// EvaluateForEpgSource matches recording rules against an EPG source's programmes
+	// and returns the number of recordings scheduled.
+	EvaluateForEpgSource(ctx context.Context, epgSourceID models.ULID) (int, error)
+}
+
 // StreamIngestionHandler handles stream source ingestion jobs.
 type StreamIngestionHandler struct {
 	sourceService    SourceIngestService
@@ -90,6 +117,10 @@ func (h *StreamIngestionHandler) WithLogger(logger *slog.Logger) *StreamIngestio
 // Execute runs a stream ingestion job.
 func (h *StreamIngestionHandler) Execute(ctx context.Context, job *models.Job) (string, error) {
 	if err := h.sourceService.Ingest(ctx, job.TargetID); err != nil {
+		// Nothing changed, so proxies using the source are already current
+		if errors.Is(err, ingestor.ErrSourceUnchanged) {
+			return fmt.Sprintf("source %s unchanged", job.TargetName), nil
+		}
 		return "", err
 	}
 
@@ -110,6 +141,7 @@ func (h *StreamIngestionHandler) Execute(ctx context.Context, job *models.Job) (
 type EpgIngestionHandler struct {
 	epgService       EpgIngestService
 	autoRegenTrigger AutoRegenerationTrigger
+	recordingRules   RecordingRuleEvaluator
 	logger           *slog.Logger
 }
 
@@ -127,6 +159,12 @@ func (h *EpgIngestionHandler) WithAutoRegeneration(trigger AutoRegenerationTrigg
 	return h
 }
 
+// WithRecordingRules sets the evaluator run against newly ingested programmes.
+func (h *EpgIngestionHandler) WithRecordingRules(evaluator RecordingRuleEvaluator) *EpgIngestionHandler {
+	h.recordingRules = evaluator
+	return h
+}
+
 // WithLogger sets the logger.
 func (h *EpgIngestionHandler) WithLogger(logger *slog.Logger) *EpgIngestionHandler {
 	h.logger = logger
@@ -136,6 +174,10 @@ func (h *EpgIngestionHandler) WithLogger(logger *slog.Logger) *EpgIngestionHandl
 // Execute runs an EPG ingestion job.
 func (h *EpgIngestionHandler) Execute(ctx context.Context, job *models.Job) (string, error) {
 	if err := h.epgService.Ingest(ctx, job.TargetID); err != nil {
+		// Nothing changed, so proxies and recordings are already current
+		if errors.Is(err, ingestor.ErrSourceUnchanged) {
+			return fmt.Sprintf("EPG source %s unchanged", job.TargetName), nil
+		}
 		return "", err
 	}
 
@@ -149,6 +191,19 @@ func (h *EpgIngestionHandler) Execute(ctx context.Context, job *models.Job) (str
 		}
 	}
 
+	// Schedule recordings for newly listed programmes matching series rules
+	if h.recordingRules != nil {
+		scheduled, err := h.recordingRules.EvaluateForEpgSource(ctx, job.TargetID)
+		if err != nil {
+			// Log but don't fail the job - ingestion succeeded
+			h.logger.Warn("failed to evaluate recording rules after EPG ingestion",
+				slog.String("source_id", job.TargetID.String()),
+				slog.Any("error", err))
+		} else if scheduled > 0 {
+			return fmt.Sprintf("ingested EPG source %s, scheduled %d recordings", job.TargetName, scheduled), nil
+		}
+	}
+
 	return fmt.Sprintf("ingested EPG source %s", job.TargetName), nil
 }
 
@@ -203,6 +258,28 @@ func (h *LogoMaintenanceHandler) Execute(ctx context.Context, job *models.Job) (
 	return fmt.Sprintf("scanned %d logos, pruned %d stale", scanned, pruned), nil
 }
 
+// AccountCheckHandler handles Xtream account check jobs.
+type AccountCheckHandler struct {
+	accountService AccountCheckService
+}
+
+// NewAccountCheckHandler creates a new handler for account check jobs.
+func NewAccountCheckHandler(service AccountCheckService) *AccountCheckHandler {
+	return &AccountCheckHandler{accountService: service}
+}
+
+// Execute runs an account check job.
+func (h *AccountCheckHandler) Execute(ctx context.Context, job *models.Job) (string, error) {
+	checked, failed, err := h.accountService.RefreshAccounts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("account check failed: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Sprintf("checked %d accounts, %d failed", checked, failed), nil
+	}
+	return fmt.Sprintf("checked %d accounts", checked), nil
+}
+
 // BackupJobHandler handles scheduled database backup jobs.
 type BackupJobHandler struct {
 	backupService BackupCreateService
@@ -248,11 +325,32 @@ func (h *BackupJobHandler) Execute(ctx context.Context, job *models.Job) (string
 	return fmt.Sprintf("created backup %s", result.GetFilename()), nil
 }
 
+// RecordingJobHandler handles jobs that start scheduled recordings.
+// The job only starts the capture; the recording service runs it until the
+// programme's padded end time, so long recordings do not hold a worker.
+type RecordingJobHandler struct {
+	recordingService RecordingStarter
+}
+
+// NewRecordingJobHandler creates a new handler for recording jobs.
+func NewRecordingJobHandler(service RecordingStarter) *RecordingJobHandler {
+	return &RecordingJobHandler{recordingService: service}
+}
+
+// Execute runs a recording job.
+func (h *RecordingJobHandler) Execute(ctx context.Context, job *models.Job) (string, error) {
+	return h.recordingService.StartRecording(ctx, job.TargetID)
+}
+
+// JobFinishedFunc is called after a job completes, or fails with no retry scheduled.
+type JobFinishedFunc func(ctx context.Context, job *models.Job)
+
 // Executor dispatches jobs to the appropriate handlers.
 type Executor struct {
-	handlers map[models.JobType]JobHandler
-	jobRepo  repository.JobRepository
-	logger   *slog.Logger
+	handlers   map[models.JobType]JobHandler
+	jobRepo    repository.JobRepository
+	logger     *slog.Logger
+	onFinished []JobFinishedFunc
 }
 
 // NewExecutor creates a new job executor.
@@ -270,6 +368,13 @@ func (e *Executor) WithLogger(logger *slog.Logger) *Executor {
 	return e
 }
 
+// OnJobFinished adds a function called after each job completes, or fails with
+// no retry scheduled. Functions run synchronously on the worker, after the job's
+// history is recorded.
+func (e *Executor) OnJobFinished(fn JobFinishedFunc) {
+	e.onFinished = append(e.onFinished, fn)
+}
+
 // RegisterHandler registers a handler for a job type.
 func (e *Executor) RegisterHandler(jobType models.JobType, handler JobHandler) {
 	e.handlers[jobType] = handler
@@ -288,7 +393,12 @@ func (e *Executor) Execute(ctx context.Context, job *models.Job) error {
 		slog.String("target", job.TargetName))
 
 	// Execute the job
-	result, err := handler.Execute(ctx, job)
+	spanCtx, span := observability.StartSpan(ctx, "job "+string(job.Type),
+		attribute.String("tvarr.job.id", job.ID.String()),
+		attribute.String("tvarr.job.type", string(job.Type)),
+	)
+	result, err := handler.Execute(spanCtx, job)
+	observability.EndSpan(span, err)
 
 	if err != nil {
 		e.logger.Error("job failed",
@@ -315,6 +425,8 @@ func (e *Executor) Execute(ctx context.Context, job *models.Job) error {
 		job.MarkCompleted(result)
 	}
 
+	observability.JobDuration.Observe(float64(job.DurationMs)/1000, string(job.Type), outcome(err))
+
 	// Save job status
 	if err := e.jobRepo.Update(ctx, job); err != nil {
 		e.logger.Error("failed to update job status",
@@ -326,11 +438,23 @@ func (e *Executor) Execute(ctx context.Context, job *models.Job) error {
 	// Create history record for completed/failed jobs
 	if job.IsFinished() {
 		e.createHistoryRecord(ctx, job)
+		for _, fn := range e.onFinished {
+			fn(ctx, job)
+		}
 	}
 
 	return nil
 }
 
+// outcome returns the status of a job run for metrics. Failed jobs that are
+// retried are rescheduled, so their status is not used.
+func outcome(err error) string {
+	if err != nil {
+		return string(models.JobStatusFailed)
+	}
+	return string(models.JobStatusCompleted)
+}
+
 // createHistoryRecord creates a job history record.
 func (e *Executor) createHistoryRecord(ctx context.Context, job *models.Job) {
 	history := &models.JobHistory{
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/jmylchreest/tvarr/internal/ingestor"
 	"github.com/jmylchreest/tvarr/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -105,6 +106,11 @@ func TestExecutor_Execute_Failure(t *testing.T) {
 	handler := &mockJobHandler{executeErr: errors.New("ingestion failed")}
 	executor.RegisterHandler(models.JobTypeStreamIngestion, handler)
 
+	var finished []*models.Job
+	executor.OnJobFinished(func(_ context.Context, job *models.Job) {
+		finished = append(finished, job)
+	})
+
 	now := models.Now()
 	job := &models.Job{
 		Type:         models.JobTypeStreamIngestion,
@@ -130,6 +136,10 @@ func TestExecutor_Execute_Failure(t *testing.T) {
 	// History should be created
 	assert.Len(t, jobRepo.history, 1)
 	assert.Equal(t, models.JobStatusFailed, jobRepo.history[0].Status)
+
+	// Finished hooks see the final failure
+	require.Len(t, finished, 1)
+	assert.Equal(t, models.JobStatusFailed, finished[0].Status)
 }
 
 func TestExecutor_Execute_FailureWithRetry(t *testing.T) {
@@ -139,6 +149,9 @@ func TestExecutor_Execute_FailureWithRetry(t *testing.T) {
 	handler := &mockJobHandler{executeErr: errors.New("temporary error")}
 	executor.RegisterHandler(models.JobTypeStreamIngestion, handler)
 
+	finished := false
+	executor.OnJobFinished(func(context.Context, *models.Job) { finished = true })
+
 	now := models.Now()
 	job := &models.Job{
 		Type:           models.JobTypeStreamIngestion,
@@ -160,6 +173,7 @@ func TestExecutor_Execute_FailureWithRetry(t *testing.T) {
 	// Should be scheduled for retry
 	assert.Equal(t, models.JobStatusScheduled, job.Status)
 	assert.NotNil(t, job.NextRunAt)
+	assert.False(t, finished, "jobs scheduled for retry have not finished")
 }
 
 func TestExecutor_Execute_NoHandler(t *testing.T) {
@@ -207,6 +221,16 @@ func TestStreamIngestionHandler(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, "connection error", err.Error())
 	})
+
+	t.Run("unchanged source skips auto-regeneration", func(t *testing.T) {
+		trigger := &mockAutoRegenTrigger{}
+		handler := NewStreamIngestionHandler(&mockSourceService{ingestErr: ingestor.ErrSourceUnchanged}).
+			WithAutoRegeneration(trigger)
+		result, err := handler.Execute(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, "source Test Source unchanged", result)
+		assert.Zero(t, trigger.calls)
+	})
 }
 
 func TestEpgIngestionHandler(t *testing.T) {
@@ -235,6 +259,77 @@ func TestEpgIngestionHandler(t *testing.T) {
 		_, err := handler.Execute(ctx, job)
 		assert.Error(t, err)
 	})
+
+	t.Run("unchanged source skips auto-regeneration", func(t *testing.T) {
+		trigger := &mockAutoRegenTrigger{}
+		evaluator := &mockRecordingRuleEvaluator{}
+		handler := NewEpgIngestionHandler(&mockEpgService{ingestErr: ingestor.ErrSourceUnchanged}).
+			WithAutoRegeneration(trigger).
+			WithRecordingRules(evaluator)
+		result, err := handler.Execute(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, "EPG source Test EPG unchanged", result)
+		assert.Zero(t, trigger.calls)
+		assert.True(t, evaluator.sourceID.IsZero())
+	})
+}
+
+// mockAutoRegenTrigger implements AutoRegenerationTrigger for testing.
+type mockAutoRegenTrigger struct {
+	calls int
+}
+
+func (m *mockAutoRegenTrigger) TriggerAutoRegeneration(ctx context.Context, sourceID models.ULID, sourceType string) error {
+	m.calls++
+	return nil
+}
+
+// mockRecordingRuleEvaluator implements RecordingRuleEvaluator for testing.
+type mockRecordingRuleEvaluator struct {
+	sourceID  models.ULID
+	scheduled int
+	err       error
+}
+
+func (m *mockRecordingRuleEvaluator) EvaluateForEpgSource(ctx context.Context, epgSourceID models.ULID) (int, error) {
+	m.sourceID = epgSourceID
+	return m.scheduled, m.err
+}
+
+func TestEpgIngestionHandler_RecordingRules(t *testing.T) {
+	job := &models.Job{
+		Type:       models.JobTypeEpgIngestion,
+		TargetID:   models.NewULID(),
+		TargetName: "Test EPG",
+	}
+	job.ID = models.NewULID()
+
+	ctx := context.Background()
+
+	t.Run("scheduled recordings are reported", func(t *testing.T) {
+		evaluator := &mockRecordingRuleEvaluator{scheduled: 2}
+		handler := NewEpgIngestionHandler(&mockEpgService{}).WithRecordingRules(evaluator)
+		result, err := handler.Execute(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, job.TargetID, evaluator.sourceID)
+		assert.Contains(t, result, "scheduled 2 recordings")
+	})
+
+	t.Run("evaluation failure does not fail ingestion", func(t *testing.T) {
+		evaluator := &mockRecordingRuleEvaluator{err: errors.New("database locked")}
+		handler := NewEpgIngestionHandler(&mockEpgService{}).WithRecordingRules(evaluator)
+		result, err := handler.Execute(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, "ingested EPG source Test EPG", result)
+	})
+
+	t.Run("not evaluated when ingestion fails", func(t *testing.T) {
+		evaluator := &mockRecordingRuleEvaluator{}
+		handler := NewEpgIngestionHandler(&mockEpgService{ingestErr: errors.New("parse error")}).WithRecordingRules(evaluator)
+		_, err := handler.Execute(ctx, job)
+		require.Error(t, err)
+		assert.True(t, evaluator.sourceID.IsZero())
+	})
 }
 
 func TestProxyGenerationHandler(t *testing.T) {
@@ -363,3 +458,42 @@ func TestBackupJobHandler(t *testing.T) {
 		assert.Contains(t, result, "created backup")
 	})
 }
+
+// mockRecordingStarter implements RecordingStarter for testing.
+type mockRecordingStarter struct {
+	startedID models.ULID
+	err       error
+}
+
+func (m *mockRecordingStarter) StartRecording(ctx context.Context, recordingID models.ULID) (string, error) {
+	m.startedID = recordingID
+	if m.err != nil {
+		return "", m.err
+	}
+	return "started recording", nil
+}
+
+func TestRecordingJobHandler(t *testing.T) {
+	job := &models.Job{
+		Type:       models.JobTypeRecording,
+		TargetID:   models.NewULID(),
+		TargetName: "Evening News",
+	}
+	job.ID = models.NewULID()
+
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		service := &mockRecordingStarter{}
+		result, err := NewRecordingJobHandler(service).Execute(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, job.TargetID, service.startedID)
+		assert.Equal(t, "started recording", result)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		service := &mockRecordingStarter{err: errors.New("relay unavailable")}
+		_, err := NewRecordingJobHandler(service).Execute(ctx, job)
+		assert.EqualError(t, err, "relay unavailable")
+	})
+}
@@ -34,6 +34,11 @@ type InternalJobConfig struct {
 // Format: sec min hour dom month dow (6-field)
 const DefaultLogoScanSchedule = "0 0 */2 * * *"
 
+// DefaultAccountCheckSchedule is the default cron schedule for Xtream account checks.
+// Runs every 30 minutes at second 0.
+// Format: sec min hour dom month dow (6-field)
+const DefaultAccountCheckSchedule = "0 */30 * * * *"
+
 // NormalizeCronExpression normalizes a cron expression to 6-field format.
 // It accepts both 6-field (default) and 7-field (legacy with year) formats.
 //
@@ -264,6 +264,10 @@ func (m *mockStreamSourceRepo) UpdateLastIngestion(ctx context.Context, id model
 	return nil
 }
 
+func (m *mockStreamSourceRepo) UpdateAccountStatus(ctx context.Context, source *models.StreamSource) error {
+	return nil
+}
+
 // mockEpgSourceRepo implements repository.EpgSourceRepository for testing.
 type mockEpgSourceRepo struct {
 	sources []*models.EpgSource
@@ -426,6 +430,14 @@ func (m *mockProxyRepo) GetProxyNamesByFilterID(ctx context.Context, filterID mo
 	return nil, nil
 }
 
+func (m *mockProxyRepo) SetChannelAlternates(ctx context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error {
+	return nil
+}
+
+func (m *mockProxyRepo) GetChannelAlternates(ctx context.Context, proxyID, channelID models.ULID) ([]*models.ProxyChannelAlternate, error) {
+	return nil, nil
+}
+
 func (m *mockProxyRepo) GetProxyNamesByStreamSourceID(ctx context.Context, sourceID models.ULID) ([]string, error) {
 	return nil, nil
 }
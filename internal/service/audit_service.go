@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jmylchreest/tvarr/internal/auth"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/observability"
+	"github.com/jmylchreest/tvarr/internal/repository"
+)
+
+// Service-level errors for the audit trail.
+var (
+	// ErrAuditEventNotFound is returned when an audit event is not found.
+	ErrAuditEventNotFound = errors.New("audit event not found")
+
+	// ErrAuditRestoreUnsupported is returned when an event's entity type cannot be restored.
+	ErrAuditRestoreUnsupported = errors.New("entity type cannot be restored")
+
+	// ErrAuditRestoreNoState is returned when an event recorded no state to restore.
+	ErrAuditRestoreNoState = errors.New("audit event has no state to restore")
+)
+
+const (
+	// auditRedacted replaces secret values in recorded states.
+	auditRedacted = "[REDACTED]"
+
+	// auditAnonymousActor names the actor of changes made without authentication.
+	auditAnonymousActor = "anonymous"
+)
+
+// auditSecretKeys are substrings of state keys whose values are never
+// recorded. Extra accounts and custom headers can hold credentials too.
+var auditSecretKeys = []string{"password", "secret", "token", "api_key", "credential", "extra_accounts", "headers"}
+
+// auditIgnoredKeys are state keys that change on every write and are left out of diffs.
+var auditIgnoredKeys = map[string]bool{"updated_at": true}
+
+// AuditEntity reads and writes one entity type on behalf of the audit trail.
+type AuditEntity interface {
+	// Snapshot returns the entity's current state, or nil when it does not
+	// exist. Secrets are redacted when the state is recorded, not here, so
+	// that changing a secret still shows up as a change.
+	Snapshot(ctx context.Context, id models.ULID) (map[string]any, error)
+	// Restore writes a recorded state back to the entity, recreating it if it
+	// was deleted, and returns the ID of the restored entity.
+	Restore(ctx context.Context, id models.ULID, state map[string]any) (models.ULID, error)
+}
+
+// AuditChange is a field that differs between two recorded states.
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// AuditService records create, update and delete events for configuration
+// entities, with the acting principal and the entity's state before and
+// after, and can revert an entity to a recorded state.
+type AuditService struct {
+	repo     repository.AuditEventRepository
+	entities map[models.AuditEntityType]AuditEntity
+	logger   *slog.Logger
+}
+
+// NewAuditService creates a new audit service.
+func NewAuditService(repo repository.AuditEventRepository) *AuditService {
+	return &AuditService{
+		repo:     repo,
+		entities: make(map[models.AuditEntityType]AuditEntity),
+		logger:   slog.Default(),
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *AuditService) WithLogger(logger *slog.Logger) *AuditService {
+	s.logger = logger
+	return s
+}
+
+// Register sets how an entity type is snapshotted and restored.
+func (s *AuditService) Register(entityType models.AuditEntityType, entity AuditEntity) *AuditService {
+	s.entities[entityType] = entity
+	return s
+}
+
+// Capture returns the entity's current state, to pass to Record once the
+// entity has changed. It returns nil when the state cannot be read.
+func (s *AuditService) Capture(ctx context.Context, entityType models.AuditEntityType, id models.ULID) map[string]any {
+	entity, ok := s.entities[entityType]
+	if !ok || id.IsZero() {
+		return nil
+	}
+	state, err := entity.Snapshot(ctx, id)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to capture audit state",
+			slog.String("entity_type", string(entityType)),
+			slog.String("entity_id", id.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	return state
+}
+
+// Record records a change to an entity made by the request's principal,
+// comparing the captured before state with the entity's current state.
+// Updates that changed nothing are not recorded. A failure to record is
+// logged rather than returned, so it never fails the change itself.
+func (s *AuditService) Record(ctx context.Context, entityType models.AuditEntityType, action models.AuditAction, id models.ULID, before map[string]any) {
+	if _, err := s.record(ctx, entityType, action, id, before, nil); err != nil {
+		s.logger.WarnContext(ctx, "failed to record audit event",
+			slog.String("entity_type", string(entityType)),
+			slog.String("entity_id", id.String()),
+			slog.String("action", string(action)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// record builds and stores an audit event. It returns nil when there was nothing to record.
+func (s *AuditService) record(ctx context.Context, entityType models.AuditEntityType, action models.AuditAction, id models.ULID, before map[string]any, restoredFrom *models.ULID) (*models.AuditEvent, error) {
+	var after map[string]any
+	if action != models.AuditActionDelete {
+		after = s.Capture(ctx, entityType, id)
+	}
+
+	event := &models.AuditEvent{
+		EntityType:   entityType,
+		EntityID:     id,
+		EntityName:   auditStateName(after, before),
+		Action:       action,
+		RestoredFrom: restoredFrom,
+	}
+	event.ActorType, event.ActorID, event.ActorName = auditActor(ctx)
+
+	if action == models.AuditActionUpdate || action == models.AuditActionRestore {
+		changes := diffAuditStates(before, after)
+		if len(changes) == 0 && action == models.AuditActionUpdate {
+			return nil, nil
+		}
+		for key, change := range changes {
+			changes[key] = AuditChange{
+				From: redactAuditValue(key, change.From),
+				To:   redactAuditValue(key, change.To),
+			}
+		}
+		data, err := json.Marshal(changes)
+		if err != nil {
+			return nil, fmt.Errorf("encoding audit changes: %w", err)
+		}
+		event.Changes = string(data)
+	}
+
+	var err error
+	if event.Before, err = encodeAuditState(before); err != nil {
+		return nil, err
+	}
+	if event.After, err = encodeAuditState(after); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, event); err != nil {
+		return nil, fmt.Errorf("creating audit event: %w", err)
+	}
+	return event, nil
+}
+
+// List returns audit events matching the filter, newest first.
+func (s *AuditService) List(ctx context.Context, filter repository.AuditEventFilter, offset, limit int) ([]*models.AuditEvent, int64, error) {
+	return s.repo.List(ctx, filter, offset, limit)
+}
+
+// GetByID returns an audit event by ID.
+func (s *AuditService) GetByID(ctx context.Context, id models.ULID) (*models.AuditEvent, error) {
+	event, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting audit event: %w", err)
+	}
+	if event == nil {
+		return nil, ErrAuditEventNotFound
+	}
+	return event, nil
+}
+
+// Restore reverts an entity to the state recorded by an audit event: the
+// state after the change, or for a delete the state before it, recreating
+// the entity. Secrets are not recorded, so restoring keeps the entity's
+// current secrets. The restore is itself recorded and returned.
+func (s *AuditService) Restore(ctx context.Context, eventID models.ULID) (*models.AuditEvent, error) {
+	source, err := s.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	entity, ok := s.entities[source.EntityType]
+	if !ok {
+		return nil, ErrAuditRestoreUnsupported
+	}
+
+	encoded := source.After
+	if encoded == "" {
+		encoded = source.Before
+	}
+	if encoded == "" {
+		return nil, ErrAuditRestoreNoState
+	}
+	var state map[string]any
+	if err := json.Unmarshal([]byte(encoded), &state); err != nil {
+		return nil, fmt.Errorf("decoding audit state: %w", err)
+	}
+
+	before := s.Capture(ctx, source.EntityType, source.EntityID)
+	id, err := entity.Restore(ctx, source.EntityID, restorableAuditState(state))
+	if err != nil {
+		return nil, err
+	}
+	if id != source.EntityID {
+		before = nil
+	}
+
+	event, err := s.record(ctx, source.EntityType, models.AuditActionRestore, id, before, &source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "restored entity from audit event",
+		slog.String("entity_type", string(source.EntityType)),
+		slog.String("entity_id", id.String()),
+		slog.String("audit_event_id", source.ID.String()),
+		slog.String("actor", event.ActorName),
+	)
+	return event, nil
+}
+
+// auditActor returns the type, ID and name of the principal behind the request.
+func auditActor(ctx context.Context) (actorType, actorID, actorName string) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return auditAnonymousActor, "", auditAnonymousActor
+	}
+	actorID = p.UserID
+	if p.Kind == auth.PrincipalKindAPIKey {
+		actorID = p.APIKeyID
+	}
+	return string(p.Kind), actorID, p.Name()
+}
+
+// auditState converts an entity to a state: its JSON object without the given keys.
+func auditState(v any, omit ...string) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit state: %w", err)
+	}
+	var state map[string]any
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decoding audit state: %w", err)
+	}
+	delete(state, "deleted_at")
+	for _, key := range omit {
+		delete(state, key)
+	}
+	return state, nil
+}
+
+// redactAuditValue replaces secret values and credentials in URLs, recursing
+// into nested objects and lists. Objects are redacted in place.
+func redactAuditValue(key string, value any) any {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return v
+		}
+		if isAuditSecretKey(key) {
+			return auditRedacted
+		}
+		return observability.RedactAttr(slog.String(key, v)).Value.String()
+	case map[string]any:
+		for k, item := range v {
+			v[k] = redactAuditValue(k, item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactAuditValue(key, item)
+		}
+	}
+	return value
+}
+
+// isAuditSecretKey reports whether a state key holds a secret.
+func isAuditSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range auditSecretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// restorableAuditState returns a copy of a recorded state without redacted
+// values and timestamps, so that writing it back keeps the entity's secrets
+// and lets the database set the update time.
+func restorableAuditState(state map[string]any) map[string]any {
+	restorable := maps.Clone(state)
+	delete(restorable, "updated_at")
+	for key, value := range restorable {
+		if s, ok := value.(string); ok && strings.Contains(s, auditRedacted) {
+			delete(restorable, key)
+		}
+	}
+	return restorable
+}
+
+// diffAuditStates returns the top-level fields that differ between two states.
+func diffAuditStates(before, after map[string]any) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	for key, from := range before {
+		if auditIgnoredKeys[key] {
+			continue
+		}
+		if to := after[key]; !reflect.DeepEqual(from, to) {
+			changes[key] = AuditChange{From: from, To: to}
+		}
+	}
+	for key, to := range after {
+		if _, ok := before[key]; !ok && !auditIgnoredKeys[key] && to != nil {
+			changes[key] = AuditChange{To: to}
+		}
+	}
+	return changes
+}
+
+// encodeAuditState redacts and encodes a state for storage, returning "" for no state.
+func encodeAuditState(state map[string]any) (string, error) {
+	if state == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(redactAuditValue("", state))
+	if err != nil {
+		return "", fmt.Errorf("encoding audit state: %w", err)
+	}
+	return string(data), nil
+}
+
+// auditStateName returns the "name" field of the first state that has one.
+func auditStateName(states ...map[string]any) string {
+	for _, state := range states {
+		if name, ok := state["name"].(string); ok && name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// AuditEntityFuncs adapts an entity's service or repository methods to AuditEntity.
+type AuditEntityFuncs[T any] struct {
+	// Get returns the entity, or nil when it does not exist.
+	Get func(ctx context.Context, id models.ULID) (*T, error)
+	// Create and Update write the entity, applying the usual validation.
+	Create func(ctx context.Context, entity *T) error
+	Update func(ctx context.Context, entity *T) error
+	// Relations lists relation keys left out of recorded states. They are
+	// cleared on restore, since GORM would otherwise save loaded relations
+	// over the restored foreign keys.
+	Relations []string
+	// Volatile lists keys maintained by the application rather than users,
+	// such as ingestion status. They are left out of recorded states and
+	// kept as they are on restore.
+	Volatile []string
+	// SoftDeleted is set when Delete keeps the row, so a deleted entity
+	// cannot be recreated under its old ID and gets a new one.
+	SoftDeleted bool
+}
+
+// Snapshot implements AuditEntity.
+func (f AuditEntityFuncs[T]) Snapshot(ctx context.Context, id models.ULID) (map[string]any, error) {
+	entity, err := f.Get(ctx, id)
+	if err != nil || entity == nil {
+		return nil, err
+	}
+	return auditState(entity, slices.Concat(f.Relations, f.Volatile)...)
+}
+
+// Restore implements AuditEntity. The state is applied over the current
+// entity, so fields it does not hold, such as secrets, are kept.
+func (f AuditEntityFuncs[T]) Restore(ctx context.Context, id models.ULID, state map[string]any) (models.ULID, error) {
+	entity, err := f.Get(ctx, id)
+	if err != nil {
+		return models.ULID{}, err
+	}
+	exists := entity != nil
+	state = maps.Clone(state)
+	if !exists {
+		entity = new(T)
+		if f.SoftDeleted {
+			delete(state, "id")
+		}
+	}
+	for _, key := range f.Relations {
+		state[key] = nil
+	}
+
+	if err := applyAuditState(entity, state); err != nil {
+		return models.ULID{}, err
+	}
+
+	if exists {
+		err = f.Update(ctx, entity)
+	} else {
+		err = f.Create(ctx, entity)
+	}
+	if err != nil {
+		return models.ULID{}, err
+	}
+	return auditEntityID(entity)
+}
+
+// applyAuditState decodes a recorded state onto an entity.
+func applyAuditState(entity any, state map[string]any) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding audit state: %w", err)
+	}
+	if err := json.Unmarshal(data, entity); err != nil {
+		return fmt.Errorf("applying audit state: %w", err)
+	}
+	return nil
+}
+
+// auditEntityID returns the ID of an entity embedding models.BaseModel.
+func auditEntityID(entity any) (models.ULID, error) {
+	identified, ok := entity.(interface{ GetID() models.ULID })
+	if !ok {
+		return models.ULID{}, fmt.Errorf("%T has no ID", entity)
+	}
+	return identified.GetID(), nil
+}
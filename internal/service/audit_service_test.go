@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/jmylchreest/tvarr/internal/auth"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAuditEventRepo is an in-memory AuditEventRepository.
+type mockAuditEventRepo struct {
+	mu     sync.Mutex
+	events []*models.AuditEvent
+}
+
+func (m *mockAuditEventRepo) Create(_ context.Context, event *models.AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if event.ID.IsZero() {
+		event.ID = models.NewULID()
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockAuditEventRepo) GetByID(_ context.Context, id models.ULID) (*models.AuditEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.events {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAuditEventRepo) List(_ context.Context, filter repository.AuditEventFilter, offset, limit int) ([]*models.AuditEvent, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var events []*models.AuditEvent
+	for i := len(m.events) - 1; i >= 0; i-- {
+		e := m.events[i]
+		if filter.EntityID != nil && e.EntityID != *filter.EntityID {
+			continue
+		}
+		events = append(events, e)
+	}
+	total := int64(len(events))
+	events = events[min(offset, len(events)):min(offset+limit, len(events))]
+	return events, total, nil
+}
+
+// auditTestStore holds stream sources for an AuditEntityFuncs under test.
+type auditTestStore struct {
+	sources map[models.ULID]*models.StreamSource
+}
+
+func newAuditTestStore() *auditTestStore {
+	return &auditTestStore{sources: make(map[models.ULID]*models.StreamSource)}
+}
+
+func (s *auditTestStore) get(_ context.Context, id models.ULID) (*models.StreamSource, error) {
+	src, ok := s.sources[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *src
+	return &clone, nil
+}
+
+func (s *auditTestStore) save(_ context.Context, src *models.StreamSource) error {
+	if src.ID.IsZero() {
+		src.ID = models.NewULID()
+	}
+	clone := *src
+	s.sources[src.ID] = &clone
+	return nil
+}
+
+func (s *auditTestStore) entity(softDeleted bool) AuditEntityFuncs[models.StreamSource] {
+	return AuditEntityFuncs[models.StreamSource]{
+		Get:         s.get,
+		Create:      s.save,
+		Update:      s.save,
+		Relations:   []string{"channels"},
+		Volatile:    []string{"status", "channel_count"},
+		SoftDeleted: softDeleted,
+	}
+}
+
+func newTestAuditService(store *auditTestStore, softDeleted bool) (*AuditService, *mockAuditEventRepo) {
+	repo := &mockAuditEventRepo{}
+	svc := NewAuditService(repo).Register(models.AuditEntityStreamSource, store.entity(softDeleted))
+	return svc, repo
+}
+
+func decodeAuditJSON(t *testing.T, data string) map[string]any {
+	t.Helper()
+	var v map[string]any
+	require.NoError(t, json.Unmarshal([]byte(data), &v))
+	return v
+}
+
+func TestAuditService_RecordCreate(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Kind:       auth.PrincipalKindAPIKey,
+		UserID:     "user-1",
+		Username:   "alice",
+		APIKeyID:   "key-1",
+		APIKeyName: "ci",
+	})
+	store := newAuditTestStore()
+	svc, repo := newTestAuditService(store, false)
+
+	src := &models.StreamSource{Name: "Provider", URL: "http://example.com/get.php?username=bob&password=hunter2", Password: "hunter2", ChannelCount: 10}
+	require.NoError(t, store.save(ctx, src))
+	svc.Record(ctx, models.AuditEntityStreamSource, models.AuditActionCreate, src.ID, nil)
+
+	require.Len(t, repo.events, 1)
+	event := repo.events[0]
+	assert.Equal(t, models.AuditActionCreate, event.Action)
+	assert.Equal(t, src.ID, event.EntityID)
+	assert.Equal(t, "Provider", event.EntityName)
+	assert.Equal(t, "api_key", event.ActorType)
+	assert.Equal(t, "key-1", event.ActorID)
+	assert.Equal(t, "api-key:ci", event.ActorName)
+	assert.Empty(t, event.Before)
+	assert.Empty(t, event.Changes)
+
+	after := decodeAuditJSON(t, event.After)
+	assert.Equal(t, "Provider", after["name"])
+	assert.Equal(t, auditRedacted, after["password"])
+	assert.NotContains(t, after["url"], "hunter2")
+	assert.NotContains(t, after, "channel_count", "volatile fields are not recorded")
+	assert.NotContains(t, event.After, "hunter2")
+}
+
+func TestAuditService_RecordUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := newAuditTestStore()
+	svc, repo := newTestAuditService(store, false)
+
+	src := &models.StreamSource{Name: "Provider", URL: "http://example.com/a.m3u", Password: "old"}
+	require.NoError(t, store.save(ctx, src))
+
+	t.Run("records changed fields", func(t *testing.T) {
+		before := svc.Capture(ctx, models.AuditEntityStreamSource, src.ID)
+		src.URL = "http://example.com/b.m3u"
+		src.Password = "new"
+		require.NoError(t, store.save(ctx, src))
+		svc.Record(ctx, models.AuditEntityStreamSource, models.AuditActionUpdate, src.ID, before)
+
+		require.Len(t, repo.events, 1)
+		event := repo.events[0]
+		assert.Equal(t, "anonymous", event.ActorType)
+		assert.Equal(t, "anonymous", event.ActorName)
+
+		changes := decodeAuditJSON(t, event.Changes)
+		assert.Len(t, changes, 2)
+		assert.Equal(t, map[string]any{"from": "http://example.com/a.m3u", "to": "http://example.com/b.m3u"}, changes["url"])
+		assert.Equal(t, map[string]any{"from": auditRedacted, "to": auditRedacted}, changes["password"],
+			"a changed secret is recorded without its value")
+	})
+
+	t.Run("skips updates that change nothing", func(t *testing.T) {
+		before := svc.Capture(ctx, models.AuditEntityStreamSource, src.ID)
+		src.ChannelCount = 42
+		require.NoError(t, store.save(ctx, src))
+		svc.Record(ctx, models.AuditEntityStreamSource, models.AuditActionUpdate, src.ID, before)
+
+		assert.Len(t, repo.events, 1)
+	})
+}
+
+func TestAuditService_Restore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reverts an update keeping secrets", func(t *testing.T) {
+		store := newAuditTestStore()
+		svc, repo := newTestAuditService(store, false)
+
+		src := &models.StreamSource{Name: "Provider", URL: "http://example.com/a.m3u", Password: "secret"}
+		require.NoError(t, store.save(ctx, src))
+		svc.Record(ctx, models.AuditEntityStreamSource, models.AuditActionCreate, src.ID, nil)
+
+		before := svc.Capture(ctx, models.AuditEntityStreamSource, src.ID)
+		src.URL = "http://example.com/b.m3u"
+		src.ChannelCount = 7
+		require.NoError(t, store.save(ctx, src))
+		svc.Record(ctx, models.AuditEntityStreamSource, models.AuditActionUpdate, src.ID, before)
+
+		event, err := svc.Restore(ctx, repo.events[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.AuditActionRestore, event.Action)
+		require.NotNil(t, event.RestoredFrom)
+		assert.Equal(t, repo.events[0].ID, *event.RestoredFrom)
+		assert.Contains(t, decodeAuditJSON(t, event.Changes), "url")
+
+		restored := store.sources[src.ID]
+		assert.Equal(t, "http://example.com/a.m3u", restored.URL)
+		assert.Equal(t, "secret", restored.Password)
+		assert.Equal(t, 7, restored.ChannelCount)
+	})
+
+	t.Run("recreates a deleted entity", func(t *testing.T) {
+		store := newAuditTestStore()
+		svc, repo := newTestAuditService(store, false)
+
+		src := &models.StreamSource{Name: "Provider", URL: "http://example.com/a.m3u"}
+		require.NoError(t, store.save(ctx, src))
+		before := svc.Capture(ctx, models.AuditEntityStreamSource, src.ID)
+		delete(store.sources, src.ID)
+		svc.Record(ctx, models.AuditEntityStreamSource, models.AuditActionDelete, src.ID, before)
+		require.Empty(t, repo.events[0].After)
+
+		event, err := svc.Restore(ctx, repo.events[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, src.ID, event.EntityID)
+		require.Contains(t, store.sources, src.ID)
+		assert.Equal(t, "Provider", store.sources[src.ID].Name)
+	})
+
+	t.Run("recreates a soft-deleted entity under a new ID", func(t *testing.T) {
+		store := newAuditTestStore()
+		svc, repo := newTestAuditService(store, true)
+
+		src := &models.StreamSource{Name: "Provider", URL: "http://example.com/a.m3u"}
+		require.NoError(t, store.save(ctx, src))
+		before := svc.Capture(ctx, models.AuditEntityStreamSource, src.ID)
+		delete(store.sources, src.ID)
+		svc.Record(ctx, models.AuditEntityStreamSource, models.AuditActionDelete, src.ID, before)
+
+		event, err := svc.Restore(ctx, repo.events[0].ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, src.ID, event.EntityID)
+		assert.Empty(t, event.Before)
+		require.Contains(t, store.sources, event.EntityID)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		store := newAuditTestStore()
+		svc, repo := newTestAuditService(store, false)
+
+		_, err := svc.Restore(ctx, models.NewULID())
+		assert.ErrorIs(t, err, ErrAuditEventNotFound)
+
+		require.NoError(t, repo.Create(ctx, &models.AuditEvent{EntityType: models.AuditEntityFilter, EntityID: models.NewULID(), Action: models.AuditActionCreate, After: "{}"}))
+		_, err = svc.Restore(ctx, repo.events[0].ID)
+		assert.ErrorIs(t, err, ErrAuditRestoreUnsupported)
+
+		require.NoError(t, repo.Create(ctx, &models.AuditEvent{EntityType: models.AuditEntityStreamSource, EntityID: models.NewULID(), Action: models.AuditActionDelete}))
+		_, err = svc.Restore(ctx, repo.events[1].ID)
+		assert.ErrorIs(t, err, ErrAuditRestoreNoState)
+	})
+}
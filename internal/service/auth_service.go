@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/auth"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+)
+
+// Service-level errors for authentication.
+var (
+	// ErrInvalidCredentials is returned when a username/password pair is rejected.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+
+	// ErrUserNotFound is returned when a user is not found.
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUserExists is returned when creating a user with a taken username.
+	ErrUserExists = errors.New("username already exists")
+
+	// ErrCannotDeleteLastUser is returned when deleting the only remaining user.
+	ErrCannotDeleteLastUser = errors.New("cannot delete the last user")
+
+	// ErrAPIKeyNotFound is returned when an API key is not found.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// defaultSessionTTL is used when no session TTL is configured.
+const defaultSessionTTL = 7 * 24 * time.Hour
+
+// apiKeyTouchInterval limits how often an API key's last_used_at is written.
+const apiKeyTouchInterval = time.Minute
+
+// AuthService provides business logic for users, UI sessions and API keys.
+type AuthService struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.AuthSessionRepository
+	apiKeyRepo  repository.APIKeyRepository
+	sessionTTL  time.Duration
+	logger      *slog.Logger
+}
+
+// NewAuthService creates a new auth service.
+func NewAuthService(
+	userRepo repository.UserRepository,
+	sessionRepo repository.AuthSessionRepository,
+	apiKeyRepo repository.APIKeyRepository,
+) *AuthService {
+	return &AuthService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		apiKeyRepo:  apiKeyRepo,
+		sessionTTL:  defaultSessionTTL,
+		logger:      slog.Default(),
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *AuthService) WithLogger(logger *slog.Logger) *AuthService {
+	s.logger = logger
+	return s
+}
+
+// WithSessionTTL sets how long UI sessions remain valid.
+func (s *AuthService) WithSessionTTL(ttl time.Duration) *AuthService {
+	if ttl > 0 {
+		s.sessionTTL = ttl
+	}
+	return s
+}
+
+// SessionTTL returns how long UI sessions remain valid.
+func (s *AuthService) SessionTTL() time.Duration {
+	return s.sessionTTL
+}
+
+// EnsureAdminUser creates the initial admin user when no users exist.
+// If password is empty a random one is generated and returned so the caller can
+// show it once; otherwise the returned password is empty.
+func (s *AuthService) EnsureAdminUser(ctx context.Context, username, password string) (string, error) {
+	count, err := s.userRepo.Count(ctx)
+	if err != nil {
+		return "", fmt.Errorf("counting users: %w", err)
+	}
+	if count > 0 {
+		return "", nil
+	}
+
+	generated := ""
+	if password == "" {
+		token, err := auth.GenerateSessionToken()
+		if err != nil {
+			return "", err
+		}
+		generated = token[:20]
+		password = generated
+	}
+
+	if _, err := s.CreateUser(ctx, username, password); err != nil {
+		return "", fmt.Errorf("creating initial admin user: %w", err)
+	}
+
+	s.logger.Info("created initial admin user", slog.String("username", username))
+	return generated, nil
+}
+
+// Login verifies a username and password and starts a new session.
+// It returns the session token, which is only ever available at this point.
+func (s *AuthService) Login(ctx context.Context, username, password, userAgent, remoteAddr string) (string, *models.AuthSession, error) {
+	user, err := s.userRepo.GetByUsername(ctx, strings.TrimSpace(username))
+	if err != nil {
+		return "", nil, fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil || !models.BoolVal(user.IsActive) || !auth.VerifyPassword(user.PasswordHash, password) {
+		return "", nil, ErrInvalidCredentials
+	}
+
+	token, err := auth.GenerateSessionToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	session := &models.AuthSession{
+		UserID:     user.ID,
+		TokenHash:  auth.HashToken(token),
+		ExpiresAt:  now.Add(s.sessionTTL),
+		UserAgent:  truncateString(userAgent, 512),
+		RemoteAddr: truncateString(remoteAddr, 255),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return "", nil, fmt.Errorf("creating session: %w", err)
+	}
+
+	user.LastLoginAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Warn("failed to record last login", slog.String("username", user.Username), slog.String("error", err.Error()))
+	}
+
+	// Opportunistically prune expired sessions; a failure here is not fatal.
+	if n, err := s.sessionRepo.DeleteExpired(ctx, now); err != nil {
+		s.logger.Warn("failed to prune expired sessions", slog.String("error", err.Error()))
+	} else if n > 0 {
+		s.logger.Debug("pruned expired sessions", slog.Int64("count", n))
+	}
+
+	session.User = user
+	return token, session, nil
+}
+
+// Logout ends the session identified by token.
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.sessionRepo.DeleteByTokenHash(ctx, auth.HashToken(token))
+}
+
+// AuthenticateSession resolves a session token to a principal.
+func (s *AuthService) AuthenticateSession(ctx context.Context, token string) (*auth.Principal, error) {
+	session, err := s.sessionRepo.GetByTokenHash(ctx, auth.HashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("getting session: %w", err)
+	}
+	if session == nil || session.IsExpired(time.Now()) || session.User == nil || !models.BoolVal(session.User.IsActive) {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	return &auth.Principal{
+		Kind:      auth.PrincipalKindSession,
+		UserID:    session.UserID.String(),
+		Username:  session.User.Username,
+		SessionID: session.ID.String(),
+		Scopes:    auth.AllScopes(),
+	}, nil
+}
+
+// AuthenticateAPIKey resolves an API key to a principal.
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	apiKey, err := s.apiKeyRepo.GetByKeyHash(ctx, auth.HashToken(key))
+	if err != nil {
+		return nil, fmt.Errorf("getting api key: %w", err)
+	}
+	now := time.Now()
+	if apiKey == nil || apiKey.IsExpired(now) || apiKey.User == nil || !models.BoolVal(apiKey.User.IsActive) {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	scopes, err := auth.ParseScopes(apiKey.Scopes)
+	if err != nil {
+		s.logger.Warn("api key has invalid scopes", slog.String("api_key", apiKey.Name), slog.String("error", err.Error()))
+		return nil, auth.ErrUnauthenticated
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			s.logger.Debug("failed to record api key usage", slog.String("error", err.Error()))
+		}
+	}
+
+	return &auth.Principal{
+		Kind:       auth.PrincipalKindAPIKey,
+		UserID:     apiKey.UserID.String(),
+		Username:   apiKey.User.Username,
+		APIKeyID:   apiKey.ID.String(),
+		APIKeyName: apiKey.Name,
+		Scopes:     scopes,
+	}, nil
+}
+
+// ListUsers returns all users.
+func (s *AuthService) ListUsers(ctx context.Context) ([]*models.User, error) {
+	return s.userRepo.GetAll(ctx)
+}
+
+// CreateUser creates a new active user with the given password.
+func (s *AuthService) CreateUser(ctx context.Context, username, password string) (*models.User, error) {
+	username = strings.TrimSpace(username)
+	existing, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("checking username: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrUserExists
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:     username,
+		PasswordHash: hash,
+		IsActive:     new(true),
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetPassword replaces a user's password and ends all of their sessions.
+func (s *AuthService) SetPassword(ctx context.Context, id models.ULID, password string) error {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("updating user: %w", err)
+	}
+
+	return s.sessionRepo.DeleteByUserID(ctx, id)
+}
+
+// DeleteUser deletes a user and their sessions. The last user cannot be deleted.
+func (s *AuthService) DeleteUser(ctx context.Context, id models.ULID) error {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("getting user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	count, err := s.userRepo.Count(ctx)
+	if err != nil {
+		return fmt.Errorf("counting users: %w", err)
+	}
+	if count <= 1 {
+		return ErrCannotDeleteLastUser
+	}
+
+	if err := s.sessionRepo.DeleteByUserID(ctx, id); err != nil {
+		return fmt.Errorf("deleting sessions: %w", err)
+	}
+	return s.userRepo.Delete(ctx, id)
+}
+
+// ListAPIKeys returns all API keys.
+func (s *AuthService) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return s.apiKeyRepo.GetAll(ctx)
+}
+
+// CreateAPIKey creates a new API key owned by userID.
+// It returns the plaintext key, which is only ever available at this point.
+func (s *AuthService) CreateAPIKey(ctx context.Context, userID models.ULID, name string, scopes auth.Scopes, expiresAt *time.Time) (string, *models.APIKey, error) {
+	if len(scopes) == 0 {
+		return "", nil, models.ValidationError{Field: "scopes", Message: "at least one scope is required"}
+	}
+
+	key, err := auth.GenerateAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	apiKey := &models.APIKey{
+		Name:      strings.TrimSpace(name),
+		UserID:    userID,
+		Prefix:    auth.APIKeyDisplayPrefix(key),
+		KeyHash:   auth.HashToken(key),
+		Scopes:    scopes.String(),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return "", nil, err
+	}
+	return key, apiKey, nil
+}
+
+// RevokeAPIKey deletes an API key.
+func (s *AuthService) RevokeAPIKey(ctx context.Context, id models.ULID) error {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("getting api key: %w", err)
+	}
+	if apiKey == nil {
+		return ErrAPIKeyNotFound
+	}
+	return s.apiKeyRepo.Delete(ctx, id)
+}
+
+// truncateString limits s to at most n bytes.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+)
+
+// EPG match service errors.
+var (
+	ErrEpgMatchNotFound = errors.New("EPG channel match not found")
+)
+
+// EpgMatchService lists the name matches made by the EPG matching pipeline
+// stage and records user decisions on them. Decisions take effect when a
+// proxy is next generated.
+type EpgMatchService struct {
+	repo   repository.EpgChannelRepository
+	logger *slog.Logger
+}
+
+// NewEpgMatchService creates a new EPG match service.
+func NewEpgMatchService(repo repository.EpgChannelRepository) *EpgMatchService {
+	return &EpgMatchService{
+		repo:   repo,
+		logger: slog.Default(),
+	}
+}
+
+// WithLogger sets the logger.
+func (s *EpgMatchService) WithLogger(logger *slog.Logger) *EpgMatchService {
+	s.logger = logger
+	return s
+}
+
+// List returns matches with the given status, or all matches when empty.
+func (s *EpgMatchService) List(ctx context.Context, status models.EpgMatchStatus) ([]*models.EpgChannelMatch, error) {
+	if status != "" && !status.IsValid() {
+		return nil, models.ValidationError{Field: "status", Message: "status must be auto, pending, confirmed or rejected"}
+	}
+	matches, err := s.repo.ListMatches(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("listing EPG channel matches: %w", err)
+	}
+	return matches, nil
+}
+
+// GetByID returns a match by ID.
+func (s *EpgMatchService) GetByID(ctx context.Context, id models.ULID) (*models.EpgChannelMatch, error) {
+	match, err := s.repo.GetMatchByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting EPG channel match: %w", err)
+	}
+	if match == nil {
+		return nil, ErrEpgMatchNotFound
+	}
+	return match, nil
+}
+
+// Confirm marks a match as confirmed so it is always applied. A non-empty
+// tvgID replaces the matched tvg-id, correcting a wrong suggestion.
+func (s *EpgMatchService) Confirm(ctx context.Context, id models.ULID, tvgID string) (*models.EpgChannelMatch, error) {
+	match, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tvgID = strings.TrimSpace(tvgID); tvgID != "" && tvgID != match.TvgID {
+		match.TvgID = tvgID
+		match.EpgDisplayName = ""
+	}
+	match.Status = models.EpgMatchStatusConfirmed
+	if err := s.repo.UpdateMatch(ctx, match); err != nil {
+		return nil, fmt.Errorf("confirming EPG channel match: %w", err)
+	}
+
+	s.logger.Info("confirmed EPG channel match",
+		slog.String("channel_id", match.ChannelID.String()),
+		slog.String("tvg_id", match.TvgID))
+	return match, nil
+}
+
+// Reject marks a match as rejected; the channel is no longer matched by name.
+func (s *EpgMatchService) Reject(ctx context.Context, id models.ULID) (*models.EpgChannelMatch, error) {
+	match, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	match.Status = models.EpgMatchStatusRejected
+	if err := s.repo.UpdateMatch(ctx, match); err != nil {
+		return nil, fmt.Errorf("rejecting EPG channel match: %w", err)
+	}
+
+	s.logger.Info("rejected EPG channel match",
+		slog.String("channel_id", match.ChannelID.String()),
+		slog.String("tvg_id", match.TvgID))
+	return match, nil
+}
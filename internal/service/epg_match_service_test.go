@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestEpgMatchService(t *testing.T) (*EpgMatchService, repository.EpgChannelRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.EpgChannel{}, &models.EpgChannelMatch{}))
+
+	repo := repository.NewEpgChannelRepository(db)
+	return NewEpgMatchService(repo), repo
+}
+
+func TestEpgMatchService_ConfirmAndReject(t *testing.T) {
+	svc, repo := newTestEpgMatchService(t)
+	ctx := context.Background()
+
+	source := models.NewULID()
+	require.NoError(t, repo.SaveMatches(ctx, []*models.EpgChannelMatch{
+		{ChannelID: models.NewULID(), ChannelName: "Discovery", EpgSourceID: source, TvgID: "discovery.uk", EpgDisplayName: "Discovery Channel", Confidence: 0.69, Status: models.EpgMatchStatusPending},
+		{ChannelID: models.NewULID(), ChannelName: "Eurosport 1", EpgSourceID: source, TvgID: "eurosport2.uk", EpgDisplayName: "Eurosport 2", Confidence: 0.82, Status: models.EpgMatchStatusPending},
+	}))
+
+	pending, err := svc.List(ctx, models.EpgMatchStatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	confirmed, err := svc.Confirm(ctx, pending[0].ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.EpgMatchStatusConfirmed, confirmed.Status)
+	assert.Equal(t, "discovery.uk", confirmed.TvgID)
+
+	// Confirming with a tvg-id corrects a wrong suggestion
+	corrected, err := svc.Confirm(ctx, pending[1].ID, "eurosport1.uk")
+	require.NoError(t, err)
+	assert.Equal(t, "eurosport1.uk", corrected.TvgID)
+	assert.Empty(t, corrected.EpgDisplayName)
+
+	rejected, err := svc.Reject(ctx, pending[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.EpgMatchStatusRejected, rejected.Status)
+
+	pending, err = svc.List(ctx, models.EpgMatchStatusPending)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	_, err = svc.Confirm(ctx, models.NewULID(), "")
+	assert.ErrorIs(t, err, ErrEpgMatchNotFound)
+
+	var ve models.ValidationError
+	_, err = svc.List(ctx, "maybe")
+	assert.ErrorAs(t, err, &ve)
+}
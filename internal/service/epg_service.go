@@ -3,14 +3,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/jmylchreest/tvarr/internal/ingestor"
 	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/repository"
 	"github.com/jmylchreest/tvarr/internal/service/progress"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // EpgService provides business logic for EPG source management.
@@ -18,6 +21,7 @@ type EpgService struct {
 	epgSourceRepo   repository.EpgSourceRepository
 	epgProgramRepo  repository.EpgProgramRepository
 	sourceRepo      repository.StreamSourceRepository
+	epgChannelRepo  repository.EpgChannelRepository
 	factory         *ingestor.EpgHandlerFactory
 	stateManager    *ingestor.StateManager
 	progressService *progress.Service
@@ -58,6 +62,13 @@ func (s *EpgService) WithStreamSourceRepo(repo repository.StreamSourceRepository
 	return s
 }
 
+// WithEpgChannelRepo sets the repository storing EPG channel definitions,
+// which are used to match stream channels to EPG channels by name.
+func (s *EpgService) WithEpgChannelRepo(repo repository.EpgChannelRepository) *EpgService {
+	s.epgChannelRepo = repo
+	return s
+}
+
 // getEpgIngestionStages returns the standard stages for EPG source ingestion.
 // EPG ingestion uses 3 stages:
 // - connect: Delete existing programs and prepare for ingestion
@@ -154,6 +165,10 @@ func (s *EpgService) Update(ctx context.Context, source *models.EpgSource) error
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Settings such as the time shift change what ingestion produces, so the
+	// next ingestion processes the content in full.
+	source.ClearContentVersion()
+
 	if err := s.epgSourceRepo.Update(ctx, source); err != nil {
 		return fmt.Errorf("updating EPG source: %w", err)
 	}
@@ -173,6 +188,12 @@ func (s *EpgService) Delete(ctx context.Context, id models.ULID) error {
 		return fmt.Errorf("deleting programs: %w", err)
 	}
 
+	if s.epgChannelRepo != nil {
+		if err := s.epgChannelRepo.DeleteBySourceID(ctx, id); err != nil {
+			return fmt.Errorf("deleting EPG channels: %w", err)
+		}
+	}
+
 	// Then delete the source
 	if err := s.epgSourceRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("deleting EPG source: %w", err)
@@ -219,8 +240,19 @@ func (s *EpgService) ListEnabled(ctx context.Context) ([]*models.EpgSource, erro
 	return sources, nil
 }
 
-// Ingest triggers ingestion for an EPG source.
+// Ingest triggers ingestion for an EPG source. It returns
+// ingestor.ErrSourceUnchanged, without writing any programs, when the source
+// content is unchanged since the last ingestion.
 func (s *EpgService) Ingest(ctx context.Context, id models.ULID) error {
+	ctx, span := observability.StartSpan(ctx, "ingest.epg_source",
+		attribute.String("tvarr.source.id", id.String()))
+	err := s.ingest(ctx, id)
+	endIngestSpan(span, err)
+	return err
+}
+
+// ingest performs the ingestion of Ingest.
+func (s *EpgService) ingest(ctx context.Context, id models.ULID) error {
 	// Get the source
 	source, err := s.epgSourceRepo.GetByID(ctx, id)
 	if err != nil {
@@ -312,7 +344,8 @@ func (s *EpgService) Ingest(ctx context.Context, id models.ULID) error {
 	var batchPrograms []*models.EpgProgram
 	const batchSize = 1000
 
-	err = handler.Ingest(ctx, source, func(program *models.EpgProgram) error {
+	var epgChannels []*models.EpgChannel
+	err = s.ingestWithChannels(ctx, handler, source, &epgChannels, func(program *models.EpgProgram) error {
 		batchPrograms = append(batchPrograms, program)
 		programCount++
 
@@ -334,6 +367,10 @@ func (s *EpgService) Ingest(ctx context.Context, id models.ULID) error {
 
 		return nil
 	})
+	if errors.Is(err, ingestor.ErrSourceUnchanged) {
+		s.completeUnchanged(ctx, source, progressMgr)
+		return err
+	}
 
 	// Stage 3: Finalize - flush remaining programs and cleanup stale data
 	if progressMgr != nil && downloadStage != nil {
@@ -382,8 +419,11 @@ func (s *EpgService) Ingest(ctx context.Context, id models.ULID) error {
 		)
 	}
 
+	s.storeEpgChannels(ctx, source, epgChannels)
+
 	// Mark success
 	source.MarkSuccess(programCount)
+	observability.IngestedRows.Add(float64(programCount), "programs", string(source.Type))
 	if err := s.epgSourceRepo.Update(ctx, source); err != nil {
 		s.logger.Error("failed to update EPG source status",
 			"source_id", id.String(),
@@ -407,6 +447,29 @@ func (s *EpgService) Ingest(ctx context.Context, id models.ULID) error {
 	return nil
 }
 
+// completeUnchanged records an ingestion that found the source content unchanged
+// since the last one. The stored programs are kept as they are.
+func (s *EpgService) completeUnchanged(ctx context.Context, source *models.EpgSource, progressMgr *progress.OperationManager) {
+	source.MarkSuccess(source.ProgramCount)
+	if err := s.epgSourceRepo.Update(ctx, source); err != nil {
+		s.logger.Error("failed to update EPG source status",
+			"source_id", source.ID.String(),
+			"error", err,
+		)
+	}
+
+	s.stateManager.Complete(source.ID, source.ProgramCount)
+
+	if progressMgr != nil {
+		progressMgr.Complete(fmt.Sprintf("%s is unchanged", source.Name))
+	}
+
+	s.logger.Info("EPG source unchanged, skipped ingestion",
+		"source_id", source.ID.String(),
+		"source_name", source.Name,
+	)
+}
+
 // IngestAsync triggers EPG ingestion asynchronously.
 func (s *EpgService) IngestAsync(ctx context.Context, id models.ULID) error {
 	// Verify source exists
@@ -427,7 +490,7 @@ func (s *EpgService) IngestAsync(ctx context.Context, id models.ULID) error {
 
 	// Run ingestion in background
 	go func() {
-		bgCtx := context.Background()
+		bgCtx := observability.DetachedContext(ctx)
 		s.performIngestion(bgCtx, source)
 	}()
 
@@ -438,6 +501,10 @@ func (s *EpgService) IngestAsync(ctx context.Context, id models.ULID) error {
 func (s *EpgService) performIngestion(ctx context.Context, source *models.EpgSource) {
 	id := source.ID
 
+	ctx, span := observability.StartSpan(ctx, "ingest.epg_source",
+		attribute.String("tvarr.source.id", id.String()))
+	defer span.End()
+
 	// Get the appropriate handler
 	handler, err := s.factory.GetForSource(source)
 	if err != nil {
@@ -513,7 +580,8 @@ func (s *EpgService) performIngestion(ctx context.Context, source *models.EpgSou
 	var batchPrograms []*models.EpgProgram
 	const batchSize = 1000
 
-	err = handler.Ingest(ctx, source, func(program *models.EpgProgram) error {
+	var epgChannels []*models.EpgChannel
+	err = s.ingestWithChannels(ctx, handler, source, &epgChannels, func(program *models.EpgProgram) error {
 		batchPrograms = append(batchPrograms, program)
 		programCount++
 
@@ -533,6 +601,10 @@ func (s *EpgService) performIngestion(ctx context.Context, source *models.EpgSou
 
 		return nil
 	})
+	if errors.Is(err, ingestor.ErrSourceUnchanged) {
+		s.completeUnchanged(ctx, source, progressMgr)
+		return
+	}
 
 	// Stage 3: Finalize - flush remaining programs and cleanup stale data
 	if progressMgr != nil && downloadStage != nil {
@@ -579,7 +651,10 @@ func (s *EpgService) performIngestion(ctx context.Context, source *models.EpgSou
 		)
 	}
 
+	s.storeEpgChannels(ctx, source, epgChannels)
+
 	source.MarkSuccess(programCount)
+	observability.IngestedRows.Add(float64(programCount), "programs", string(source.Type))
 	_ = s.epgSourceRepo.Update(ctx, source)
 	s.stateManager.Complete(id, programCount)
 
@@ -594,6 +669,39 @@ func (s *EpgService) performIngestion(ctx context.Context, source *models.EpgSou
 	)
 }
 
+// ingestWithChannels runs the handler, collecting the source's channel
+// definitions into channels when the handler provides them and an EPG channel
+// repository is configured.
+func (s *EpgService) ingestWithChannels(ctx context.Context, handler ingestor.EpgHandler, source *models.EpgSource, channels *[]*models.EpgChannel, callback ingestor.ProgramCallback) (err error) {
+	ctx, span := observability.StartSpan(ctx, "ingest.fetch",
+		attribute.String("tvarr.source.type", string(source.Type)))
+	defer func() { endIngestSpan(span, err) }()
+
+	channelHandler, ok := handler.(ingestor.EpgChannelHandler)
+	if !ok || s.epgChannelRepo == nil {
+		return handler.Ingest(ctx, source, callback)
+	}
+	return channelHandler.IngestWithChannels(ctx, source, func(channel *models.EpgChannel) error {
+		*channels = append(*channels, channel)
+		return nil
+	}, callback)
+}
+
+// storeEpgChannels replaces the stored channel definitions of a source after a
+// successful ingestion. Sources whose handler yields no channels are left as is.
+func (s *EpgService) storeEpgChannels(ctx context.Context, source *models.EpgSource, channels []*models.EpgChannel) {
+	if s.epgChannelRepo == nil || len(channels) == 0 {
+		return
+	}
+	if err := s.epgChannelRepo.ReplaceBySourceID(ctx, source.ID, channels); err != nil {
+		// Don't fail the whole ingestion; matching uses the previous channels
+		s.logger.Error("failed to store EPG channels",
+			"source_id", source.ID.String(),
+			"error", err,
+		)
+	}
+}
+
 // GetIngestionState returns the current ingestion state for an EPG source.
 func (s *EpgService) GetIngestionState(id models.ULID) (*ingestor.IngestionState, bool) {
 	return s.stateManager.GetState(id)
@@ -316,6 +316,10 @@ func (m *jobMockStreamSourceRepo) UpdateLastIngestion(ctx context.Context, id mo
 	return nil
 }
 
+func (m *jobMockStreamSourceRepo) UpdateAccountStatus(ctx context.Context, source *models.StreamSource) error {
+	return nil
+}
+
 // jobMockEpgSourceRepo implements repository.EpgSourceRepository for testing.
 type jobMockEpgSourceRepo struct {
 	sources map[models.ULID]*models.EpgSource
@@ -486,6 +490,14 @@ func (m *jobMockProxyRepo) GetProxyNamesByFilterID(ctx context.Context, filterID
 	return nil, nil
 }
 
+func (m *jobMockProxyRepo) SetChannelAlternates(ctx context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error {
+	return nil
+}
+
+func (m *jobMockProxyRepo) GetChannelAlternates(ctx context.Context, proxyID, channelID models.ULID) ([]*models.ProxyChannelAlternate, error) {
+	return nil, nil
+}
+
 func (m *jobMockProxyRepo) GetProxyNamesByStreamSourceID(ctx context.Context, sourceID models.ULID) ([]string, error) {
 	return nil, nil
 }
@@ -8,8 +8,10 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/storage"
 	"github.com/jmylchreest/tvarr/pkg/duration"
 	"github.com/jmylchreest/tvarr/pkg/httpclient"
@@ -64,6 +66,13 @@ type LogoService struct {
 	converter          *ImageConverter
 	stalenessThreshold time.Duration // For pruning during maintenance
 	config             LogoServiceConfig
+
+	// Clients for sources with an upstream proxy, keyed by proxy URL. They share
+	// the configuration and circuit breaker of the direct client.
+	clientConfig   httpclient.Config
+	breaker        *httpclient.CircuitBreaker
+	proxyClientsMu sync.Mutex
+	proxyClients   map[string]HTTPClient
 }
 
 // NewLogoService creates a new LogoService with default configuration.
@@ -109,6 +118,9 @@ func NewLogoServiceWithConfig(cache *storage.LogoCache, cfg LogoServiceConfig) *
 		converter:          NewImageConverter(),
 		stalenessThreshold: 7 * 24 * time.Hour, // Default: 7 days
 		config:             cfg,
+		clientConfig:       clientConfig,
+		breaker:            breaker,
+		proxyClients:       make(map[string]HTTPClient),
 	}
 }
 
@@ -162,6 +174,12 @@ func (s *LogoService) LoadIndexWithOptions(ctx context.Context, opts LogoIndexer
 // The logo ID is deterministic - same normalized URL always produces same ID,
 // ensuring logos shared across multiple channels are only downloaded once.
 func (s *LogoService) CacheLogo(ctx context.Context, logoURL string) (*storage.CachedLogoMetadata, error) {
+	return s.CacheLogoWithProxy(ctx, logoURL, "")
+}
+
+// CacheLogoWithProxy is CacheLogo, downloading through the given upstream proxy
+// when it is non-empty. Used for logos from sources configured with a proxy.
+func (s *LogoService) CacheLogoWithProxy(ctx context.Context, logoURL, proxyURL string) (*storage.CachedLogoMetadata, error) {
 	// Check if already cached
 	if existing := s.indexer.GetByURL(logoURL); existing != nil {
 		// Touch to update LastSeenAt (for freshness-based pruning)
@@ -194,7 +212,7 @@ func (s *LogoService) CacheLogo(ctx context.Context, logoURL string) (*storage.C
 	}
 
 	// Download the logo
-	meta, err := s.downloadAndStore(ctx, logoURL)
+	meta, err := s.downloadAndStore(ctx, logoURL, proxyURL)
 	if err != nil {
 		return nil, fmt.Errorf("downloading logo: %w", err)
 	}
@@ -216,11 +234,29 @@ func (s *LogoService) TouchLogo(meta *storage.CachedLogoMetadata) error {
 	return s.cache.TouchMetadata(meta)
 }
 
+// clientFor returns the HTTP client to download through the given proxy.
+func (s *LogoService) clientFor(proxyURL string) HTTPClient {
+	if proxyURL == "" {
+		return s.httpClient
+	}
+
+	s.proxyClientsMu.Lock()
+	defer s.proxyClientsMu.Unlock()
+	if client, ok := s.proxyClients[proxyURL]; ok {
+		return client
+	}
+	cfg := s.clientConfig
+	cfg.ProxyURL = proxyURL
+	client := httpclient.NewWithBreaker(cfg, s.breaker).StandardClient()
+	s.proxyClients[proxyURL] = client
+	return client
+}
+
 // downloadAndStore downloads a logo and stores it in the cache.
 // Raster images (JPEG, GIF, WebP) are converted to PNG for consistent display.
 // The original image is also stored alongside the converted version.
 // SVG images are stored as-is since they are vector graphics.
-func (s *LogoService) downloadAndStore(ctx context.Context, logoURL string) (*storage.CachedLogoMetadata, error) {
+func (s *LogoService) downloadAndStore(ctx context.Context, logoURL, proxyURL string) (*storage.CachedLogoMetadata, error) {
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logoURL, nil)
 	if err != nil {
@@ -228,7 +264,7 @@ func (s *LogoService) downloadAndStore(ctx context.Context, logoURL string) (*st
 	}
 
 	// Execute request
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.clientFor(proxyURL).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("downloading: %w", err)
 	}
@@ -418,6 +454,25 @@ func (s *LogoService) GetStats() *LogoStats {
 	}
 }
 
+// RegisterMetrics registers the logo cache's size metrics with r, which are
+// collected on each scrape.
+func (s *LogoService) RegisterMetrics(r *observability.MetricsRegistry) {
+	r.NewGaugeFunc("tvarr_logo_cache_logos",
+		"Logos in the logo cache by origin (cached, uploaded).",
+		[]string{"origin"}, func(emit observability.EmitFunc) {
+			stats := s.GetStats()
+			emit(float64(stats.CachedLogos), "cached")
+			emit(float64(stats.UploadedLogos), "uploaded")
+		})
+	r.NewGaugeFunc("tvarr_logo_cache_bytes",
+		"Size of the logo cache in bytes by origin (cached, uploaded).",
+		[]string{"origin"}, func(emit observability.EmitFunc) {
+			stats := s.GetStats()
+			emit(float64(stats.CachedSize), "cached")
+			emit(float64(stats.UploadedSize), "uploaded")
+		})
+}
+
 // GetAllLogos returns all cached logo metadata.
 func (s *LogoService) GetAllLogos() []*storage.CachedLogoMetadata {
 	return s.indexer.GetAll()
@@ -1,4 +1,4 @@
-// Package logs provides log streaming and statistics services for tvarr.
+// Package logs provides log streaming, statistics and persistent storage services for tvarr.
 package logs
 
 import (
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"maps"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -67,6 +68,8 @@ type Service struct {
 	maxErrors      int
 	startTime      time.Time
 	wrappedHandler slog.Handler
+	redact         func(slog.Attr) slog.Attr
+	store          atomic.Pointer[store]
 }
 
 // New creates a new logs service.
@@ -94,8 +97,21 @@ func (s *Service) WrapHandler(handler slog.Handler) slog.Handler {
 	}
 }
 
-// AddLog adds a log entry and broadcasts it to subscribers.
+// WithRedactor sets a function applied to each captured attribute, so that
+// streamed and stored logs are redacted like the logger's own output.
+func (s *Service) WithRedactor(redact func(slog.Attr) slog.Attr) *Service {
+	s.redact = redact
+	return s
+}
+
+// AddLog adds a log entry, broadcasts it to subscribers and, if the log store
+// is started, queues it to be stored.
 func (s *Service) AddLog(entry LogEntry) {
+	s.addLog(entry, true)
+}
+
+// addLog adds a log entry, queueing it to be stored if persist is set.
+func (s *Service) addLog(entry LogEntry, persist bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -133,6 +149,12 @@ func (s *Service) AddLog(entry LogEntry) {
 			// Subscriber buffer full, skip
 		}
 	}
+
+	if persist {
+		if st := s.store.Load(); st != nil {
+			st.enqueue(entry)
+		}
+	}
 }
 
 // Subscribe creates a new subscriber for log events.
@@ -262,13 +284,14 @@ func (h *logsHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 // Handle handles the Record.
 func (h *logsHandler) Handle(ctx context.Context, r slog.Record) error {
-	// Fast path: skip log capture if no subscribers and not an error
+	// Fast path: skip log capture if no subscribers, not stored and not an error
 	// This avoids expensive ULID generation and map allocations during bulk operations
 	hasSubscribers := h.service.HasSubscribers()
 	isError := r.Level >= slog.LevelError
+	persist := h.service.persists(ctx, r.Level)
 
-	// Only capture logs if someone is listening or it's an error (for recent errors tracking)
-	if hasSubscribers || isError {
+	// Only capture logs if someone is listening, they are stored or it's an error (for recent errors tracking)
+	if hasSubscribers || persist || isError {
 		// Convert slog record to LogEntry
 		entry := LogEntry{
 			ID:        ulid.Make().String(),
@@ -290,7 +313,7 @@ func (h *logsHandler) Handle(ctx context.Context, r slog.Record) error {
 		})
 
 		// Send to service
-		h.service.AddLog(entry)
+		h.service.addLog(entry, persist)
 	}
 
 	// Pass through to wrapped handler
@@ -325,6 +348,9 @@ func (h *logsHandler) WithGroup(name string) slog.Handler {
 
 // addAttr adds an attribute to the log entry.
 func (h *logsHandler) addAttr(entry *LogEntry, attr slog.Attr) {
+	if h.service.redact != nil {
+		attr = h.service.redact(attr)
+	}
 	key := attr.Key
 	value := attr.Value.Any()
 
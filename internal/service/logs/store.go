@@ -0,0 +1,413 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+)
+
+const (
+	// storeQueueSize bounds entries waiting to be written; further entries are dropped.
+	storeQueueSize = 4096
+	// storeBatchSize is the most entries written at once.
+	storeBatchSize = 500
+	// storeFlushInterval is how often queued entries are written.
+	storeFlushInterval = time.Second
+	// storePruneInterval is how often expired and excess entries are deleted.
+	storePruneInterval = time.Hour
+)
+
+// levels orders the log levels from least to most severe.
+var levels = []string{"trace", "debug", "info", "warn", "error"}
+
+// levelRank returns the position of a level in levels, or -1 if unknown.
+func levelRank(level string) int {
+	return slices.Index(levels, level)
+}
+
+// StoreConfig configures the persistent log store.
+type StoreConfig struct {
+	// Level is the minimum level stored.
+	Level string
+	// Retention is how long entries are kept; 0 keeps them until MaxEntries is reached.
+	Retention time.Duration
+	// MaxEntries is the most entries kept, deleting the oldest; 0 is unlimited.
+	MaxEntries int
+}
+
+// Query selects log entries. Zero fields match everything.
+type Query struct {
+	Since     time.Time // Logged at or after
+	Until     time.Time // Logged before
+	Level     string    // Minimum level
+	Module    string
+	RequestID string
+	SessionID string
+	ChannelID string
+	Search    string // Case-insensitive text in the message or fields
+	Offset    int
+	Limit     int
+}
+
+// levels returns the levels at or above the query's minimum level.
+func (q Query) levels() []string {
+	if rank := levelRank(q.Level); rank > 0 {
+		return levels[rank:]
+	}
+	return nil
+}
+
+// matches reports whether an in-memory entry matches the query.
+func (q Query) matches(entry LogEntry) bool {
+	switch {
+	case !q.Since.IsZero() && entry.Timestamp.Before(q.Since),
+		!q.Until.IsZero() && !entry.Timestamp.Before(q.Until),
+		q.Level != "" && levelRank(entry.Level) < levelRank(q.Level),
+		q.Module != "" && entry.Module != q.Module,
+		q.RequestID != "" && contextString(entry.Context, "request_id") != q.RequestID,
+		q.SessionID != "" && contextString(entry.Fields, "session_id") != q.SessionID,
+		q.ChannelID != "" && contextString(entry.Fields, "channel_id") != q.ChannelID:
+		return false
+	}
+	if q.Search == "" {
+		return true
+	}
+	search := strings.ToLower(q.Search)
+	if strings.Contains(strings.ToLower(entry.Message), search) {
+		return true
+	}
+	fields, _ := json.Marshal(jsonSafe(entry.Fields))
+	return strings.Contains(strings.ToLower(string(fields)), search)
+}
+
+// storeWriteKey marks the store's own database calls, so that records logged
+// while writing entries are not themselves stored.
+type storeWriteKey struct{}
+
+// store writes captured log entries to the database in batches and prunes
+// them by age and count.
+type store struct {
+	repo     repository.LogEntryRepository
+	cfg      StoreConfig
+	minLevel slog.Level
+	minRank  int
+	logger   *slog.Logger // Bypasses the service, so store errors are never stored
+
+	queue   chan LogEntry
+	dropped atomic.Int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// StartStore starts writing captured log entries at or above the configured
+// level to repo, and answering queries from it. Entries are written in the
+// background; call Close to write those still queued.
+func (s *Service) StartStore(repo repository.LogEntryRepository, cfg StoreConfig) error {
+	minRank := levelRank(cfg.Level)
+	if minRank < 0 {
+		return fmt.Errorf("unknown log store level %q", cfg.Level)
+	}
+
+	handler := s.wrappedHandler
+	if handler == nil {
+		handler = slog.DiscardHandler
+	}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), storeWriteKey{}, true))
+	st := &store{
+		repo:     repo,
+		cfg:      cfg,
+		minLevel: stringToLevel(cfg.Level),
+		minRank:  minRank,
+		logger:   slog.New(handler).With(slog.String("component", "log_store")),
+		queue:    make(chan LogEntry, storeQueueSize),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if !s.store.CompareAndSwap(nil, st) {
+		cancel()
+		return fmt.Errorf("log store already started")
+	}
+	st.wg.Add(1)
+	go st.run()
+	return nil
+}
+
+// Close stops the log store, if started, after writing the entries still queued.
+func (s *Service) Close() {
+	if st := s.store.Swap(nil); st != nil {
+		st.cancel()
+		st.wg.Wait()
+	}
+}
+
+// Persistent reports whether captured logs are kept in the log store.
+func (s *Service) Persistent() bool {
+	return s.store.Load() != nil
+}
+
+// Query returns the log entries matching q, newest first, and the total
+// matching. With the log store started it is queried; otherwise the
+// in-memory buffer is.
+func (s *Service) Query(ctx context.Context, q Query) ([]LogEntry, int64, error) {
+	if st := s.store.Load(); st != nil {
+		return st.query(ctx, q)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []LogEntry
+	for i := len(s.logs) - 1; i >= 0; i-- {
+		if q.matches(s.logs[i]) {
+			matched = append(matched, s.logs[i])
+		}
+	}
+	total := int64(len(matched))
+	matched = matched[min(q.Offset, len(matched)):]
+	return matched[:min(q.Limit, len(matched))], total, nil
+}
+
+// persists reports whether records at level are written to the log store.
+func (s *Service) persists(ctx context.Context, level slog.Level) bool {
+	st := s.store.Load()
+	return st != nil && level >= st.minLevel && ctx.Value(storeWriteKey{}) == nil
+}
+
+// enqueue queues an entry to be written, dropping it if the queue is full.
+func (st *store) enqueue(entry LogEntry) {
+	if levelRank(entry.Level) < st.minRank {
+		return
+	}
+	select {
+	case st.queue <- entry:
+	default:
+		st.dropped.Add(1)
+	}
+}
+
+// run writes queued entries and prunes the store until Close.
+func (st *store) run() {
+	defer st.wg.Done()
+
+	flush := time.NewTicker(storeFlushInterval)
+	defer flush.Stop()
+	prune := time.NewTicker(storePruneInterval)
+	defer prune.Stop()
+	st.prune()
+
+	batch := make([]*models.LogEntry, 0, storeBatchSize)
+	write := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := st.repo.CreateBatch(ctx, batch); err != nil {
+			st.logger.Error("failed to write log entries",
+				slog.Int("entries", len(batch)),
+				slog.Any("error", err))
+		}
+		batch = batch[:0]
+		if dropped := st.dropped.Swap(0); dropped > 0 {
+			st.logger.Warn("log store queue full, dropped entries", slog.Int64("dropped", dropped))
+		}
+	}
+
+	for {
+		select {
+		case <-st.ctx.Done():
+			// Write what is still queued, even though the store is closing
+			ctx := context.WithoutCancel(st.ctx)
+			for {
+				select {
+				case entry := <-st.queue:
+					batch = append(batch, toModel(entry))
+					if len(batch) >= storeBatchSize {
+						write(ctx)
+					}
+				default:
+					write(ctx)
+					return
+				}
+			}
+		case entry := <-st.queue:
+			batch = append(batch, toModel(entry))
+			if len(batch) >= storeBatchSize {
+				write(st.ctx)
+			}
+		case <-flush.C:
+			write(st.ctx)
+		case <-prune.C:
+			st.prune()
+		}
+	}
+}
+
+// prune deletes entries older than the retention period and beyond the entry limit.
+func (st *store) prune() {
+	var deleted int64
+	if st.cfg.Retention > 0 {
+		n, err := st.repo.DeleteBefore(st.ctx, time.Now().Add(-st.cfg.Retention))
+		if err != nil {
+			st.logger.Warn("failed to prune expired log entries", slog.Any("error", err))
+		}
+		deleted += n
+	}
+	if st.cfg.MaxEntries > 0 {
+		n, err := st.repo.DeleteExcess(st.ctx, st.cfg.MaxEntries)
+		if err != nil {
+			st.logger.Warn("failed to prune excess log entries", slog.Any("error", err))
+		}
+		deleted += n
+	}
+	if deleted > 0 {
+		st.logger.Debug("pruned log entries", slog.Int64("deleted", deleted))
+	}
+}
+
+// query retrieves stored entries matching q.
+func (st *store) query(ctx context.Context, q Query) ([]LogEntry, int64, error) {
+	records, total, err := st.repo.Query(context.WithValue(ctx, storeWriteKey{}, true), repository.LogEntryFilter{
+		Since:     q.Since,
+		Until:     q.Until,
+		Levels:    q.levels(),
+		Module:    q.Module,
+		RequestID: q.RequestID,
+		SessionID: q.SessionID,
+		ChannelID: q.ChannelID,
+		Search:    q.Search,
+	}, q.Offset, q.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := make([]LogEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, fromModel(record))
+	}
+	return entries, total, nil
+}
+
+// toModel converts a log entry to its stored form.
+func toModel(entry LogEntry) *models.LogEntry {
+	record := &models.LogEntry{
+		Timestamp: entry.Timestamp,
+		Level:     entry.Level,
+		Message:   entry.Message,
+		Module:    entry.Module,
+		Target:    entry.Target,
+		File:      entry.File,
+		Line:      entry.Line,
+		RequestID: contextString(entry.Context, "request_id"),
+		SessionID: contextString(entry.Fields, "session_id"),
+		ChannelID: contextString(entry.Fields, "channel_id"),
+		Fields:    marshalFields(entry.Fields),
+		Context:   marshalFields(entry.Context),
+	}
+	if id, err := models.ParseULID(entry.ID); err == nil {
+		record.ID = id
+	}
+	return record
+}
+
+// fromModel converts a stored log entry back to a log entry.
+func fromModel(record *models.LogEntry) LogEntry {
+	entry := LogEntry{
+		ID:        record.ID.String(),
+		Timestamp: record.Timestamp,
+		Level:     record.Level,
+		Message:   record.Message,
+		Module:    record.Module,
+		Target:    record.Target,
+		File:      record.File,
+		Line:      record.Line,
+	}
+	if record.Fields != "" {
+		_ = json.Unmarshal([]byte(record.Fields), &entry.Fields)
+	}
+	if record.Context != "" {
+		_ = json.Unmarshal([]byte(record.Context), &entry.Context)
+	}
+	return entry
+}
+
+// contextString returns a string attribute value, or "" if absent.
+func contextString(attrs map[string]any, key string) string {
+	switch v := attrs[key].(type) {
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return ""
+	}
+}
+
+// marshalFields encodes attributes as a JSON object, or "" if there are none.
+func marshalFields(fields map[string]any) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(jsonSafe(fields))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// jsonSafe returns attributes with values that don't encode usefully as JSON,
+// such as errors and groups, converted.
+func jsonSafe(fields map[string]any) map[string]any {
+	safe := make(map[string]any, len(fields))
+	for key, value := range fields {
+		safe[key] = jsonValue(value)
+	}
+	return safe
+}
+
+// jsonValue converts an attribute value for JSON encoding.
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case []slog.Attr:
+		group := make(map[string]any, len(v))
+		for _, attr := range v {
+			group[attr.Key] = jsonValue(attr.Value.Resolve().Any())
+		}
+		return group
+	case time.Duration:
+		return v.String()
+	case json.Marshaler, string, bool, int, int64, uint64, float64, time.Time, nil:
+		return v
+	default:
+		if _, err := json.Marshal(v); err != nil {
+			return fmt.Sprint(v)
+		}
+		return v
+	}
+}
+
+// stringToLevel converts a level name to a slog.Level, the inverse of levelToString.
+func stringToLevel(level string) slog.Level {
+	switch level {
+	case "trace":
+		return slog.LevelDebug - 4
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,170 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogEntryRepo records stored entries and answers queries by level only.
+type fakeLogEntryRepo struct {
+	mu      sync.Mutex
+	entries []*models.LogEntry
+	filters []repository.LogEntryFilter
+}
+
+func (f *fakeLogEntryRepo) CreateBatch(_ context.Context, entries []*models.LogEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+func (f *fakeLogEntryRepo) Query(_ context.Context, filter repository.LogEntryFilter, _, _ int) ([]*models.LogEntry, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters = append(f.filters, filter)
+	var entries []*models.LogEntry
+	for _, e := range f.entries {
+		if len(filter.Levels) == 0 || slices.Contains(filter.Levels, e.Level) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, int64(len(entries)), nil
+}
+
+func (f *fakeLogEntryRepo) DeleteBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeLogEntryRepo) DeleteExcess(_ context.Context, _ int) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeLogEntryRepo) stored() []*models.LogEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.entries)
+}
+
+func TestService_Store(t *testing.T) {
+	svc := New().WithRedactor(func(a slog.Attr) slog.Attr {
+		if a.Key == "password" {
+			return slog.String(a.Key, "[REDACTED]")
+		}
+		return a
+	})
+	logger := slog.New(svc.WrapHandler(slog.NewTextHandler(&strings.Builder{}, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	repo := &fakeLogEntryRepo{}
+	require.NoError(t, svc.StartStore(repo, StoreConfig{Level: "info"}))
+	assert.True(t, svc.Persistent())
+
+	logger.Debug("not stored")
+	logger.Info("stream started",
+		slog.String("component", "relay"),
+		slog.String("session_id", "01J9Z6M7Q8R9S0T1V2W3X4Y5Z6"),
+		slog.String("channel_id", "01J9Z6M7Q8R9S0T1V2W3X4Y5Z7"),
+		slog.String("password", "hunter2"))
+	logger.Error("upstream failed", slog.String("request_id", "req-1"), slog.Any("error", errors.New("connection reset")))
+	logger.InfoContext(context.WithValue(context.Background(), storeWriteKey{}, true), "written by the store")
+	svc.Close()
+	assert.False(t, svc.Persistent())
+
+	stored := repo.stored()
+	require.Len(t, stored, 2)
+	info := stored[0]
+	assert.Equal(t, "stream started", info.Message)
+	assert.Equal(t, "relay", info.Module)
+	assert.Equal(t, "01J9Z6M7Q8R9S0T1V2W3X4Y5Z6", info.SessionID)
+	assert.Equal(t, "01J9Z6M7Q8R9S0T1V2W3X4Y5Z7", info.ChannelID)
+	assert.NotContains(t, info.Fields, "hunter2")
+	assert.False(t, info.ID.IsZero())
+
+	failed := stored[1]
+	assert.Equal(t, "error", failed.Level)
+	assert.Equal(t, "req-1", failed.RequestID)
+	assert.Contains(t, failed.Fields, "connection reset")
+}
+
+func TestService_QueryStore(t *testing.T) {
+	svc := New()
+	repo := &fakeLogEntryRepo{entries: []*models.LogEntry{
+		{BaseModel: models.BaseModel{ID: models.NewULID()}, Level: "warn", Message: "slow", Fields: `{"elapsed":"2s"}`},
+		{BaseModel: models.BaseModel{ID: models.NewULID()}, Level: "info", Message: "ok"},
+	}}
+	require.NoError(t, svc.StartStore(repo, StoreConfig{Level: "debug"}))
+	defer svc.Close()
+
+	entries, total, err := svc.Query(context.Background(), Query{Level: "warn", Module: "relay", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "slow", entries[0].Message)
+	assert.Equal(t, "2s", entries[0].Fields["elapsed"])
+	assert.Equal(t, repo.entries[0].ID.String(), entries[0].ID)
+
+	filter := repo.filters[0]
+	assert.Equal(t, []string{"warn", "error"}, filter.Levels)
+	assert.Equal(t, "relay", filter.Module)
+}
+
+func TestService_QueryMemory(t *testing.T) {
+	svc := New()
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.AddLog(LogEntry{Timestamp: base, Level: "info", Message: "server started", Module: "server"})
+	svc.AddLog(LogEntry{Timestamp: base.Add(time.Minute), Level: "warn", Message: "upstream slow", Module: "relay",
+		Fields: map[string]any{"session_id": "sess-1", "upstream": "provider_a"}})
+	svc.AddLog(LogEntry{Timestamp: base.Add(2 * time.Minute), Level: "error", Message: "upstream failed", Module: "relay",
+		Fields: map[string]any{"session_id": "sess-1"}, Context: map[string]any{"request_id": "req-1"}})
+
+	tests := []struct {
+		name     string
+		query    Query
+		messages []string
+	}{
+		{"all newest first", Query{}, []string{"upstream failed", "upstream slow", "server started"}},
+		{"since", Query{Since: base.Add(time.Minute)}, []string{"upstream failed", "upstream slow"}},
+		{"until", Query{Until: base.Add(time.Minute)}, []string{"server started"}},
+		{"minimum level", Query{Level: "warn"}, []string{"upstream failed", "upstream slow"}},
+		{"module", Query{Module: "server"}, []string{"server started"}},
+		{"session", Query{SessionID: "sess-1"}, []string{"upstream failed", "upstream slow"}},
+		{"request", Query{RequestID: "req-1"}, []string{"upstream failed"}},
+		{"search message", Query{Search: "STARTED"}, []string{"server started"}},
+		{"search fields", Query{Search: "provider_a"}, []string{"upstream slow"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Limit = 10
+			entries, total, err := svc.Query(context.Background(), tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.messages)), total)
+			messages := make([]string, 0, len(entries))
+			for _, e := range entries {
+				messages = append(messages, e.Message)
+			}
+			assert.Equal(t, tt.messages, messages)
+		})
+	}
+
+	entries, total, err := svc.Query(context.Background(), Query{Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "upstream slow", entries[0].Message)
+}
+
+func TestService_StartStoreInvalidLevel(t *testing.T) {
+	err := New().StartStore(&fakeLogEntryRepo{}, StoreConfig{Level: "verbose"})
+	assert.ErrorContains(t, err, "verbose")
+}
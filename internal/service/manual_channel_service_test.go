@@ -171,6 +171,10 @@ func (r *mockSourceRepoForManual) UpdateLastIngestion(ctx context.Context, id mo
 	return nil
 }
 
+func (r *mockSourceRepoForManual) UpdateAccountStatus(ctx context.Context, source *models.StreamSource) error {
+	return nil
+}
+
 func TestManualChannelService_ListBySourceID(t *testing.T) {
 	ctx := context.Background()
 	channelRepo := newMockManualChannelRepo()
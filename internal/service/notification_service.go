@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/config"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/notify"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/jmylchreest/tvarr/internal/service/progress"
+	"github.com/jmylchreest/tvarr/pkg/ffmpegd/types"
+)
+
+// Service-level errors for notification targets.
+var (
+	// ErrNotificationTargetNotFound is returned when a notification target is not found.
+	ErrNotificationTargetNotFound = errors.New("notification target not found")
+
+	// ErrNotificationTargetExists is returned when creating a target with a taken name.
+	ErrNotificationTargetExists = errors.New("notification target name already exists")
+)
+
+const (
+	// notificationQueueSize bounds events waiting for delivery; further events are dropped.
+	notificationQueueSize = 256
+
+	// notificationPruneInterval is how often the delivery log and cooldowns are pruned.
+	notificationPruneInterval = time.Hour
+)
+
+// NotificationSender delivers a single attempt of an event to a target.
+type NotificationSender interface {
+	Send(ctx context.Context, target *models.NotificationTarget, event notify.Event) (int, error)
+}
+
+// NotificationService manages notification targets and delivers operational
+// events to them in the background, retrying failed deliveries and recording
+// each outcome in the delivery log.
+type NotificationService struct {
+	repo   repository.NotificationRepository
+	sender NotificationSender
+	logger *slog.Logger
+
+	maxAttempts int
+	retryDelay  time.Duration
+	cooldown    time.Duration
+	retention   time.Duration
+
+	queue chan notify.Event
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time // event type and subject -> last published
+
+	// baseCtx is cancelled on Close, abandoning pending retries.
+	baseCtx    context.Context
+	baseCancel context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewNotificationService creates a new notification service.
+func NewNotificationService(repo repository.NotificationRepository, sender NotificationSender) *NotificationService {
+	baseCtx, baseCancel := context.WithCancel(context.Background())
+	return &NotificationService{
+		repo:        repo,
+		sender:      sender,
+		logger:      slog.Default(),
+		maxAttempts: 3,
+		retryDelay:  10 * time.Second,
+		cooldown:    15 * time.Minute,
+		retention:   7 * 24 * time.Hour,
+		queue:       make(chan notify.Event, notificationQueueSize),
+		lastSent:    make(map[string]time.Time),
+		baseCtx:     baseCtx,
+		baseCancel:  baseCancel,
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *NotificationService) WithLogger(logger *slog.Logger) *NotificationService {
+	s.logger = logger
+	return s
+}
+
+// WithConfig applies the retry, cooldown and delivery log retention settings.
+func (s *NotificationService) WithConfig(cfg config.NotificationsConfig) *NotificationService {
+	if cfg.MaxAttempts > 0 {
+		s.maxAttempts = cfg.MaxAttempts
+	}
+	s.retryDelay = cfg.RetryDelay
+	s.cooldown = cfg.Cooldown
+	if cfg.DeliveryRetention > 0 {
+		s.retention = cfg.DeliveryRetention
+	}
+	return s
+}
+
+// List returns all notification targets.
+func (s *NotificationService) List(ctx context.Context) ([]*models.NotificationTarget, error) {
+	return s.repo.GetAllTargets(ctx)
+}
+
+// GetByID returns a notification target by ID.
+func (s *NotificationService) GetByID(ctx context.Context, id models.ULID) (*models.NotificationTarget, error) {
+	target, err := s.repo.GetTargetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting notification target: %w", err)
+	}
+	if target == nil {
+		return nil, ErrNotificationTargetNotFound
+	}
+	return target, nil
+}
+
+// Create creates a new notification target, enabled unless specified otherwise.
+func (s *NotificationService) Create(ctx context.Context, target *models.NotificationTarget) error {
+	target.Name = strings.TrimSpace(target.Name)
+	existing, err := s.repo.GetTargetByName(ctx, target.Name)
+	if err != nil {
+		return fmt.Errorf("checking notification target name: %w", err)
+	}
+	if existing != nil {
+		return ErrNotificationTargetExists
+	}
+	if target.Enabled == nil {
+		target.Enabled = new(true)
+	}
+	return s.repo.CreateTarget(ctx, target)
+}
+
+// Update saves changes to a notification target.
+func (s *NotificationService) Update(ctx context.Context, target *models.NotificationTarget) error {
+	target.Name = strings.TrimSpace(target.Name)
+	existing, err := s.repo.GetTargetByName(ctx, target.Name)
+	if err != nil {
+		return fmt.Errorf("checking notification target name: %w", err)
+	}
+	if existing != nil && existing.ID != target.ID {
+		return ErrNotificationTargetExists
+	}
+	return s.repo.UpdateTarget(ctx, target)
+}
+
+// Delete deletes a notification target and its delivery log.
+func (s *NotificationService) Delete(ctx context.Context, id models.ULID) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteTarget(ctx, id)
+}
+
+// GetDeliveries returns the delivery log, newest first, optionally for one target.
+func (s *NotificationService) GetDeliveries(ctx context.Context, targetID *models.ULID, offset, limit int) ([]*models.NotificationDelivery, int64, error) {
+	return s.repo.GetDeliveries(ctx, targetID, offset, limit)
+}
+
+// Test makes a single attempt to send a test event to a target, whether or not
+// it is enabled, and returns the logged delivery.
+func (s *NotificationService) Test(ctx context.Context, id models.ULID) (*models.NotificationDelivery, error) {
+	target, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	event := notify.NewEvent(models.NotificationEventTest, notify.SeverityInfo, target.Name,
+		"Test notification", fmt.Sprintf("This is a test notification for the %s target %q.", target.Type, target.Name))
+	return s.deliver(ctx, target, event, 1), nil
+}
+
+// Publish queues an event for delivery to the targets subscribed to it. Events
+// repeating the type and subject of one published within the cooldown are
+// dropped, as are events published while the queue is full.
+func (s *NotificationService) Publish(event notify.Event) {
+	key := string(event.Type) + "|" + event.Subject
+	now := time.Now()
+
+	s.mu.Lock()
+	if last, ok := s.lastSent[key]; ok && now.Sub(last) < s.cooldown {
+		s.mu.Unlock()
+		s.logger.Debug("notification suppressed by cooldown",
+			slog.String("event_type", string(event.Type)),
+			slog.String("subject", event.Subject))
+		return
+	}
+	s.lastSent[key] = now
+	s.mu.Unlock()
+
+	select {
+	case s.queue <- event:
+	default:
+		s.logger.Warn("notification queue full, dropping event",
+			slog.String("event_type", string(event.Type)),
+			slog.String("subject", event.Subject))
+	}
+}
+
+// Start begins delivering published events and pruning the delivery log.
+func (s *NotificationService) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Close stops delivery, abandoning queued events and pending retries.
+func (s *NotificationService) Close() {
+	s.baseCancel()
+	s.wg.Wait()
+}
+
+// run dispatches queued events and prunes the delivery log until Close.
+func (s *NotificationService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(notificationPruneInterval)
+	defer ticker.Stop()
+	s.prune()
+
+	for {
+		select {
+		case <-s.baseCtx.Done():
+			return
+		case event := <-s.queue:
+			s.dispatch(event)
+		case <-ticker.C:
+			s.prune()
+		}
+	}
+}
+
+// dispatch delivers an event to each enabled target subscribed to it. Each
+// target is delivered to concurrently, so a slow or failing target does not
+// delay the others.
+func (s *NotificationService) dispatch(event notify.Event) {
+	targets, err := s.repo.GetEnabledTargets(s.baseCtx)
+	if err != nil {
+		s.logger.Error("failed to load notification targets", slog.Any("error", err))
+		return
+	}
+	for _, target := range targets {
+		if !target.Subscribes(event.Type) {
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.deliver(s.baseCtx, target, event, s.maxAttempts)
+		}()
+	}
+}
+
+// deliver sends an event to a target, making up to maxAttempts attempts with
+// exponential backoff, and records the outcome in the delivery log.
+func (s *NotificationService) deliver(ctx context.Context, target *models.NotificationTarget, event notify.Event, maxAttempts int) *models.NotificationDelivery {
+	start := time.Now()
+	delivery := &models.NotificationDelivery{
+		TargetID:   target.ID,
+		TargetName: target.Name,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Title:      event.Title,
+		Status:     models.NotificationDelivered,
+	}
+
+	var err error
+	delay := s.retryDelay
+attempts:
+	for {
+		delivery.Attempts++
+		delivery.StatusCode, err = s.sender.Send(ctx, target, event)
+		if err == nil || delivery.Attempts >= maxAttempts || !retryable(err) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break attempts
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	if err != nil {
+		delivery.Status = models.NotificationFailed
+		delivery.Error = err.Error()
+	}
+	delivery.DurationMs = time.Since(start).Milliseconds()
+
+	if delivery.Status == models.NotificationFailed {
+		s.logger.Warn("notification delivery failed",
+			slog.String("target", target.Name),
+			slog.String("event_type", string(event.Type)),
+			slog.Int("attempts", delivery.Attempts),
+			slog.String("error", delivery.Error))
+	} else {
+		s.logger.Debug("notification delivered",
+			slog.String("target", target.Name),
+			slog.String("event_type", string(event.Type)),
+			slog.Int("attempts", delivery.Attempts))
+	}
+
+	// Record the outcome even if delivery was abandoned on shutdown
+	if err := s.repo.CreateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		s.logger.Error("failed to record notification delivery",
+			slog.String("target", target.Name),
+			slog.Any("error", err))
+	}
+	return delivery
+}
+
+// retryable reports whether a failed delivery may succeed if repeated.
+func retryable(err error) bool {
+	var statusErr *notify.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Retryable()
+	}
+	return true
+}
+
+// prune deletes expired delivery log entries and cooldowns.
+func (s *NotificationService) prune() {
+	now := time.Now()
+	s.mu.Lock()
+	for key, last := range s.lastSent {
+		if now.Sub(last) >= s.cooldown {
+			delete(s.lastSent, key)
+		}
+	}
+	s.mu.Unlock()
+
+	deleted, err := s.repo.DeleteDeliveries(s.baseCtx, now.Add(-s.retention))
+	if err != nil {
+		s.logger.Warn("failed to prune notification deliveries", slog.Any("error", err))
+	} else if deleted > 0 {
+		s.logger.Debug("pruned notification deliveries", slog.Int64("deleted", deleted))
+	}
+}
+
+// WatchProgress publishes failed ingestion, proxy generation and backup
+// operations reported to the progress service, until Close.
+func (s *NotificationService) WatchProgress(progressService *progress.Service) {
+	sub := progressService.Subscribe(&progress.OperationFilter{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer progressService.Unsubscribe(sub.ID)
+		for {
+			select {
+			case <-s.baseCtx.Done():
+				return
+			case ev, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if event, ok := progressEvent(ev); ok {
+					s.Publish(event)
+				}
+			}
+		}
+	}()
+}
+
+// progressEvent converts a failed operation into a notification event.
+func progressEvent(ev *progress.ProgressEvent) (notify.Event, bool) {
+	if ev.EventType != progress.EventTypeError || ev.Progress == nil {
+		return notify.Event{}, false
+	}
+	op := ev.Progress
+
+	var event notify.Event
+	switch op.OperationType {
+	case progress.OpStreamIngestion, progress.OpEpgIngestion:
+		event = notify.NewEvent(models.NotificationEventIngestionFailed, notify.SeverityError, op.OwnerName,
+			"Ingestion failed: "+op.OwnerName, op.Error).
+			WithData("source_id", op.OwnerID.String()).
+			WithData("source_type", op.OwnerType)
+	case progress.OpProxyRegeneration:
+		event = notify.NewEvent(models.NotificationEventProxyGenerationFailed, notify.SeverityError, op.OwnerName,
+			"Proxy generation failed: "+op.OwnerName, op.Error).
+			WithData("proxy_id", op.OwnerID.String())
+	case progress.OpBackup:
+		event = notify.NewEvent(models.NotificationEventBackupFailed, notify.SeverityError, "",
+			"Database backup failed", op.Error)
+	default:
+		return notify.Event{}, false
+	}
+	if op.ErrorDetail != nil && op.ErrorDetail.Stage != "" {
+		event = event.WithData("stage", op.ErrorDetail.Stage)
+	}
+	return event, true
+}
+
+// HandleJobFinished publishes scheduled jobs that failed with no retry left.
+// It is registered with the scheduler's executor.
+func (s *NotificationService) HandleJobFinished(_ context.Context, job *models.Job) {
+	if job.Status != models.JobStatusFailed {
+		return
+	}
+	name := string(job.Type)
+	if job.TargetName != "" {
+		name += " " + job.TargetName
+	}
+	s.Publish(notify.NewEvent(models.NotificationEventJobFailed, notify.SeverityError, name,
+		"Job failed: "+name,
+		fmt.Sprintf("The job failed after %d attempts: %s", job.AttemptCount, job.LastError)).
+		WithData("job_id", job.ID.String()).
+		WithData("job_type", string(job.Type)).
+		WithData("attempts", job.AttemptCount))
+}
+
+// HandleCircuitStateChange publishes upstream circuit breakers opening and
+// closing again. Only the upstream host is reported, as stream URLs often
+// embed account credentials.
+func (s *NotificationService) HandleCircuitStateChange(streamURL string, from, to relay.CircuitState) {
+	host := "upstream"
+	if u, err := url.Parse(streamURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	switch {
+	case to == relay.CircuitOpen && from == relay.CircuitClosed:
+		s.Publish(notify.NewEvent(models.NotificationEventCircuitOpened, notify.SeverityWarning, host,
+			"Upstream circuit opened: "+host,
+			fmt.Sprintf("Streams from %s failed repeatedly, so the relay has stopped connecting to them for now.", host)).
+			WithData("upstream_host", host))
+	case to == relay.CircuitClosed:
+		s.Publish(notify.NewEvent(models.NotificationEventCircuitClosed, notify.SeverityInfo, host,
+			"Upstream circuit closed: "+host,
+			fmt.Sprintf("Streams from %s are working again.", host)).
+			WithData("upstream_host", host))
+	}
+}
+
+// HandleDaemonStateChange publishes ffmpegd daemons missing their heartbeats
+// and recovering. It is registered with the daemon registry.
+func (s *NotificationService) HandleDaemonStateChange(id types.DaemonID, name string, state types.DaemonState) {
+	switch state {
+	case types.DaemonStateUnhealthy:
+		s.Publish(notify.NewEvent(models.NotificationEventDaemonOffline, notify.SeverityError, string(id),
+			"Transcoder offline: "+name,
+			fmt.Sprintf("The ffmpegd daemon %s has stopped sending heartbeats and will not be given new jobs.", name)).
+			WithData("daemon_id", string(id)))
+	case types.DaemonStateConnected:
+		s.Publish(notify.NewEvent(models.NotificationEventDaemonRecovered, notify.SeverityInfo, string(id),
+			"Transcoder recovered: "+name,
+			fmt.Sprintf("The ffmpegd daemon %s is sending heartbeats again.", name)).
+			WithData("daemon_id", string(id)))
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// proxyAuditRelations are the proxy relation keys replaced by proxyAuditAssignments in audit states.
+var proxyAuditRelations = []string{"sources", "epg_sources", "filters", "mapping_rules"}
+
+// proxyAuditVolatile are the proxy keys maintained by generation.
+var proxyAuditVolatile = []string{"status", "last_generated_at", "last_error", "channel_count", "program_count"}
+
+// proxyAuditAssignments is the part of a proxy's audit state that records
+// which sources and filters it uses, by ID, in priority order.
+type proxyAuditAssignments struct {
+	Sources    []proxyAuditSource    `json:"sources"`
+	EpgSources []proxyAuditEpgSource `json:"epg_sources"`
+	Filters    []proxyAuditFilter    `json:"filters"`
+}
+
+type proxyAuditSource struct {
+	SourceID models.ULID `json:"source_id"`
+	Priority int         `json:"priority"`
+}
+
+type proxyAuditEpgSource struct {
+	EpgSourceID models.ULID `json:"epg_source_id"`
+	Priority    int         `json:"priority"`
+}
+
+type proxyAuditFilter struct {
+	FilterID models.ULID `json:"filter_id"`
+	Priority int         `json:"priority"`
+	IsActive bool        `json:"is_active"`
+}
+
+// proxyAuditEntity records and restores proxies together with their source
+// and filter assignments, since those determine the proxy's output.
+type proxyAuditEntity struct {
+	proxies *ProxyService
+}
+
+// AuditEntity returns the audit trail's view of stream proxies.
+func (s *ProxyService) AuditEntity() AuditEntity {
+	return proxyAuditEntity{proxies: s}
+}
+
+// Snapshot implements AuditEntity.
+func (e proxyAuditEntity) Snapshot(ctx context.Context, id models.ULID) (map[string]any, error) {
+	proxy, err := e.proxies.GetByIDWithRelations(ctx, id)
+	if err != nil || proxy == nil {
+		return nil, err
+	}
+	state, err := auditState(proxy, slices.Concat(proxyAuditRelations, proxyAuditVolatile)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments proxyAuditAssignments
+	for _, ps := range proxy.Sources {
+		assignments.Sources = append(assignments.Sources, proxyAuditSource{ps.SourceID, ps.Priority})
+	}
+	for _, pes := range proxy.EpgSources {
+		assignments.EpgSources = append(assignments.EpgSources, proxyAuditEpgSource{pes.EpgSourceID, pes.Priority})
+	}
+	for _, pf := range proxy.Filters {
+		assignments.Filters = append(assignments.Filters, proxyAuditFilter{pf.FilterID, pf.Priority, pf.IsActive == nil || *pf.IsActive})
+	}
+	assignmentState, err := auditState(assignments)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(state, assignmentState)
+	return state, nil
+}
+
+// Restore implements AuditEntity, restoring the proxy's settings and then
+// replacing its source and filter assignments with the recorded ones.
+func (e proxyAuditEntity) Restore(ctx context.Context, id models.ULID, state map[string]any) (models.ULID, error) {
+	var assignments proxyAuditAssignments
+	if err := applyAuditState(&assignments, state); err != nil {
+		return models.ULID{}, err
+	}
+
+	id, err := AuditEntityFuncs[models.StreamProxy]{
+		Get:       e.proxies.GetByID,
+		Create:    e.proxies.Create,
+		Update:    e.proxies.Update,
+		Relations: proxyAuditRelations,
+		Volatile:  proxyAuditVolatile,
+	}.Restore(ctx, id, state)
+	if err != nil {
+		return models.ULID{}, err
+	}
+
+	sourceIDs := make([]models.ULID, 0, len(assignments.Sources))
+	sourcePriorities := make(map[models.ULID]int, len(assignments.Sources))
+	for _, ps := range assignments.Sources {
+		sourceIDs = append(sourceIDs, ps.SourceID)
+		sourcePriorities[ps.SourceID] = ps.Priority
+	}
+	if err := e.proxies.SetSources(ctx, id, sourceIDs, sourcePriorities); err != nil {
+		return models.ULID{}, fmt.Errorf("restoring proxy sources: %w", err)
+	}
+
+	epgSourceIDs := make([]models.ULID, 0, len(assignments.EpgSources))
+	epgSourcePriorities := make(map[models.ULID]int, len(assignments.EpgSources))
+	for _, pes := range assignments.EpgSources {
+		epgSourceIDs = append(epgSourceIDs, pes.EpgSourceID)
+		epgSourcePriorities[pes.EpgSourceID] = pes.Priority
+	}
+	if err := e.proxies.SetEpgSources(ctx, id, epgSourceIDs, epgSourcePriorities); err != nil {
+		return models.ULID{}, fmt.Errorf("restoring proxy EPG sources: %w", err)
+	}
+
+	filterIDs := make([]models.ULID, 0, len(assignments.Filters))
+	filterPriorities := make(map[models.ULID]int, len(assignments.Filters))
+	filterActive := make(map[models.ULID]bool, len(assignments.Filters))
+	for _, pf := range assignments.Filters {
+		filterIDs = append(filterIDs, pf.FilterID)
+		filterPriorities[pf.FilterID] = pf.Priority
+		filterActive[pf.FilterID] = pf.IsActive
+	}
+	if err := e.proxies.SetFilters(ctx, id, filterIDs, filterPriorities, filterActive); err != nil {
+		return models.ULID{}, fmt.Errorf("restoring proxy filters: %w", err)
+	}
+
+	return id, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/jmylchreest/tvarr/internal/expression"
 	"github.com/jmylchreest/tvarr/internal/models"
 	"github.com/jmylchreest/tvarr/internal/pipeline"
 	"github.com/jmylchreest/tvarr/internal/pipeline/core"
@@ -50,6 +51,9 @@ func (s *ProxyService) Create(ctx context.Context, proxy *models.StreamProxy) er
 	if err := proxy.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if err := validateDedupExpression(proxy); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
 
 	if err := s.proxyRepo.Create(ctx, proxy); err != nil {
 		return fmt.Errorf("creating proxy: %w", err)
@@ -69,6 +73,9 @@ func (s *ProxyService) Update(ctx context.Context, proxy *models.StreamProxy) er
 	if err := proxy.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if err := validateDedupExpression(proxy); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
 
 	if err := s.proxyRepo.Update(ctx, proxy); err != nil {
 		return fmt.Errorf("updating proxy: %w", err)
@@ -82,6 +89,17 @@ func (s *ProxyService) Update(ctx context.Context, proxy *models.StreamProxy) er
 	return nil
 }
 
+// validateDedupExpression checks the syntax of a proxy's dedup expression.
+func validateDedupExpression(proxy *models.StreamProxy) error {
+	if proxy.DedupIdentity != models.DedupIdentityExpression || proxy.DedupExpression == "" {
+		return nil
+	}
+	if _, err := expression.PreprocessAndParse(proxy.DedupExpression); err != nil {
+		return models.ValidationError{Field: "dedup_expression", Message: err.Error()}
+	}
+	return nil
+}
+
 // Delete deletes a stream proxy by ID.
 func (s *ProxyService) Delete(ctx context.Context, id models.ULID) error {
 	if err := s.proxyRepo.Delete(ctx, id); err != nil {
@@ -231,6 +231,14 @@ func (m *mockProxyRepo) GetProxyNamesByFilterID(ctx context.Context, filterID mo
 	return nil, nil
 }
 
+func (m *mockProxyRepo) SetChannelAlternates(ctx context.Context, proxyID models.ULID, alternates []*models.ProxyChannelAlternate) error {
+	return nil
+}
+
+func (m *mockProxyRepo) GetChannelAlternates(ctx context.Context, proxyID, channelID models.ULID) ([]*models.ProxyChannelAlternate, error) {
+	return nil, nil
+}
+
 func (m *mockProxyRepo) GetProxyNamesByStreamSourceID(ctx context.Context, sourceID models.ULID) ([]string, error) {
 	return nil, nil
 }
@@ -272,6 +280,24 @@ func TestProxyService_Create_ValidationError(t *testing.T) {
 	assert.Contains(t, err.Error(), "validation failed")
 }
 
+func TestProxyService_Create_InvalidDedupExpression(t *testing.T) {
+	repo := newMockProxyRepo()
+	svc := NewProxyService(repo, nil)
+	ctx := context.Background()
+
+	proxy := &models.StreamProxy{
+		Name:            "Dedup Proxy",
+		DedupMode:       models.DedupModeCollapse,
+		DedupIdentity:   models.DedupIdentityExpression,
+		DedupExpression: `channel_name matches`,
+	}
+
+	err := svc.Create(ctx, proxy)
+	var ve models.ValidationError
+	require.ErrorAs(t, err, &ve)
+	assert.Equal(t, "dedup_expression", ve.Field)
+}
+
 func TestProxyService_Update(t *testing.T) {
 	repo := newMockProxyRepo()
 	svc := NewProxyService(repo, nil)
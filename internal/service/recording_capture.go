@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/internal/version"
+)
+
+// recordingSegmentPollInterval is how often the fMP4 recorder checks for new segments.
+const recordingSegmentPollInterval = time.Second
+
+// captureFromRelay joins (or starts) the channel's relay session as a consumer
+// and writes its passthrough output to w in the recording's format.
+func (s *RecordingService) captureFromRelay(ctx context.Context, rec *models.Recording, w io.Writer) error {
+	session, err := s.relay.StartRelayWithProfile(ctx, rec.ChannelID, nil)
+	if err != nil {
+		return fmt.Errorf("starting relay session: %w", err)
+	}
+	// Connection limit policies must not preempt the session while it is recorded
+	release := session.HoldForRecording()
+	defer release()
+
+	if err := session.WaitReady(ctx); err != nil {
+		return fmt.Errorf("waiting for relay session: %w", err)
+	}
+	session.ClearIdleState()
+
+	if rec.Format == models.RecordingFormatFMP4 {
+		return captureFMP4(ctx, session, w)
+	}
+	return captureMPEGTS(ctx, session, rec, w)
+}
+
+// captureMPEGTS registers the recorder as an MPEG-TS client of the session.
+// It returns when ctx is done or the session stops serving the stream.
+func captureMPEGTS(ctx context.Context, session *relay.RelaySession, rec *models.Recording, w io.Writer) error {
+	processor, err := session.GetOrCreateMPEGTSProcessorForVariant(relay.VariantSource)
+	if err != nil {
+		return fmt.Errorf("creating MPEG-TS processor: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/recordings/"+rec.ID.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.RemoteAddr = "recorder"
+	req.Header.Set("User-Agent", "tvarr-recorder/"+version.Version)
+
+	return processor.ServeStream(&recordingWriter{w: w}, req, "recording-"+rec.ID.String())
+}
+
+// captureFMP4 writes the session's fMP4 init segment followed by each new media
+// segment. A capture retried or resumed into a file that already has data only
+// appends media segments, so the file keeps a single init segment. It returns
+// when ctx is done or the processor stops.
+func captureFMP4(ctx context.Context, session *relay.RelaySession, w io.Writer) error {
+	processor, err := session.GetOrCreateHLSfMP4ProcessorForVariant(relay.VariantSource)
+	if err != nil {
+		return fmt.Errorf("creating fMP4 processor: %w", err)
+	}
+
+	ticker := time.NewTicker(recordingSegmentPollInterval)
+	defer ticker.Stop()
+
+	wroteInit := hasData(w)
+	var lastSeq uint64
+	haveSeq := false
+	for {
+		// Counts as playlist activity so the processor is not stopped as idle.
+		processor.RecordPlaylistRequest()
+
+		if !wroteInit && processor.HasInitSegment() {
+			if _, err := w.Write(processor.GetInitSegment().Data); err != nil {
+				return fmt.Errorf("writing init segment: %w", err)
+			}
+			wroteInit = true
+		}
+		if wroteInit {
+			for _, info := range processor.GetSegmentInfos() {
+				if haveSeq && info.Sequence <= lastSeq {
+					continue
+				}
+				seg, err := processor.GetSegment(info.Sequence)
+				if err != nil {
+					continue
+				}
+				if _, err := w.Write(seg.Data); err != nil {
+					return fmt.Errorf("writing segment: %w", err)
+				}
+				lastSeq, haveSeq = info.Sequence, true
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-processor.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// hasData reports whether w is a file that already has data.
+func hasData(w io.Writer) bool {
+	file, ok := w.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Size() > 0
+}
+
+// recordingWriter adapts a file to the http.ResponseWriter and http.Flusher
+// interfaces that relay processors write client streams to.
+type recordingWriter struct {
+	w      io.Writer
+	header http.Header
+}
+
+// Header returns a header map that is never sent.
+func (r *recordingWriter) Header() http.Header {
+	if r.header == nil {
+		r.header = make(http.Header)
+	}
+	return r.header
+}
+
+// Write writes stream data to the underlying file.
+func (r *recordingWriter) Write(p []byte) (int, error) {
+	return r.w.Write(p)
+}
+
+// WriteHeader is a no-op; there is no HTTP response.
+func (r *recordingWriter) WriteHeader(int) {}
+
+// Flush is a no-op; writes go straight to the file.
+func (r *recordingWriter) Flush() {}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/expression"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+)
+
+// Recording rule service errors.
+var (
+	ErrRecordingRuleNotFound = errors.New("recording rule not found")
+)
+
+// compiledRecordingRule is an enabled rule with its parsed expression.
+type compiledRecordingRule struct {
+	rule   *models.RecordingRule
+	parsed *expression.ParsedExpression
+}
+
+// ruleCandidate is a programme airing matched by a rule.
+type ruleCandidate struct {
+	rule    *models.RecordingRule
+	program *models.EpgProgram
+	channel *models.Channel
+}
+
+// RecordingRuleService manages series recording rules and schedules the
+// programmes they match.
+type RecordingRuleService struct {
+	ruleRepo         repository.RecordingRuleRepository
+	recordingService *RecordingService
+	channelRepo      repository.ChannelRepository
+	programRepo      repository.EpgProgramRepository
+	streamSourceRepo repository.StreamSourceRepository
+	epgSourceRepo    repository.EpgSourceRepository
+	validator        *expression.Validator
+	logger           *slog.Logger
+}
+
+// NewRecordingRuleService creates a new recording rule service.
+func NewRecordingRuleService(
+	ruleRepo repository.RecordingRuleRepository,
+	recordingService *RecordingService,
+	channelRepo repository.ChannelRepository,
+	programRepo repository.EpgProgramRepository,
+	streamSourceRepo repository.StreamSourceRepository,
+	epgSourceRepo repository.EpgSourceRepository,
+) *RecordingRuleService {
+	return &RecordingRuleService{
+		ruleRepo:         ruleRepo,
+		recordingService: recordingService,
+		channelRepo:      channelRepo,
+		programRepo:      programRepo,
+		streamSourceRepo: streamSourceRepo,
+		epgSourceRepo:    epgSourceRepo,
+		validator:        expression.NewValidator(nil),
+		logger:           slog.Default(),
+	}
+}
+
+// WithLogger sets the logger.
+func (s *RecordingRuleService) WithLogger(logger *slog.Logger) *RecordingRuleService {
+	s.logger = logger
+	return s
+}
+
+// List returns all recording rules.
+func (s *RecordingRuleService) List(ctx context.Context) ([]*models.RecordingRule, error) {
+	return s.ruleRepo.GetAll(ctx)
+}
+
+// GetByID returns a recording rule by ID.
+func (s *RecordingRuleService) GetByID(ctx context.Context, id models.ULID) (*models.RecordingRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting recording rule: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrRecordingRuleNotFound
+	}
+	return rule, nil
+}
+
+// Create validates and stores a new recording rule.
+func (s *RecordingRuleService) Create(ctx context.Context, rule *models.RecordingRule) error {
+	if err := s.validate(rule); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return fmt.Errorf("creating recording rule: %w", err)
+	}
+	return nil
+}
+
+// Update validates and stores changes to a recording rule. Recordings already
+// scheduled by the rule are left unchanged.
+func (s *RecordingRuleService) Update(ctx context.Context, rule *models.RecordingRule) error {
+	if _, err := s.GetByID(ctx, rule.ID); err != nil {
+		return err
+	}
+	if err := s.validate(rule); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return fmt.Errorf("updating recording rule: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a recording rule. Recordings it scheduled are kept.
+func (s *RecordingRuleService) Delete(ctx context.Context, id models.ULID) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(ctx, id)
+}
+
+// validate checks the rule's fields and that its expression only uses fields
+// available to recording rules.
+func (s *RecordingRuleService) validate(rule *models.RecordingRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	result := s.validator.Validate(rule.Expression, expression.DomainRecordingRule)
+	if !result.IsValid {
+		msg := "invalid expression"
+		if len(result.Errors) > 0 {
+			msg = result.Errors[0].Message
+			if result.Errors[0].Details != "" {
+				msg = result.Errors[0].Details
+			}
+		}
+		return models.ValidationError{Field: "expression", Message: msg}
+	}
+	return nil
+}
+
+// ApplyAll evaluates enabled rules against the programmes of every enabled EPG
+// source and returns the number of recordings scheduled.
+func (s *RecordingRuleService) ApplyAll(ctx context.Context) (int, error) {
+	sources, err := s.epgSourceRepo.GetEnabled(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing EPG sources: %w", err)
+	}
+	total := 0
+	for _, source := range sources {
+		scheduled, err := s.EvaluateForEpgSource(ctx, source.ID)
+		total += scheduled
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// EvaluateForEpgSource matches enabled rules against the upcoming programmes of
+// an EPG source and schedules a recording for each new match. Each programme is
+// recorded at most once, on the first channel (by source, then ID) whose fields
+// satisfy a rule. Episodes already recorded or scheduled are skipped.
+func (s *RecordingRuleService) EvaluateForEpgSource(ctx context.Context, epgSourceID models.ULID) (int, error) {
+	rules, err := s.compileRules(ctx)
+	if err != nil || len(rules) == 0 {
+		return 0, err
+	}
+
+	channelsByTvgID, err := s.channelsByTvgID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(channelsByTvgID) == 0 {
+		return 0, nil
+	}
+
+	sourceNames := make(map[models.ULID]string)
+	sources, err := s.streamSourceRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing stream sources: %w", err)
+	}
+	for _, source := range sources {
+		sourceNames[source.ID] = source.Name
+	}
+
+	evaluator := expression.NewEvaluator()
+	// Case-insensitive by default, as for filters; the case_sensitive modifier overrides it.
+	evaluator.SetCaseSensitive(false)
+
+	// Rows stay open while the callback runs, so matches are collected and
+	// scheduled afterwards.
+	now := time.Now()
+	var candidates []ruleCandidate
+	err = s.programRepo.GetBySourceID(ctx, epgSourceID, func(prog *models.EpgProgram) error {
+		if !prog.Start.After(now) {
+			return nil
+		}
+		channels := channelsByTvgID[prog.ChannelID]
+		if len(channels) == 0 {
+			return nil
+		}
+		for _, ch := range channels {
+			evalCtx := expression.NewProgramEvalContext(recordingRuleFields(prog, ch, sourceNames[ch.SourceID]))
+			for _, r := range rules {
+				result, err := evaluator.Evaluate(r.parsed, evalCtx)
+				if err != nil || !result.Matches {
+					continue
+				}
+				candidates = append(candidates, ruleCandidate{rule: r.rule, program: prog, channel: ch})
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("reading EPG programmes: %w", err)
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	// Earliest airings first, so repeats of an episode are the ones skipped.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].program.Start.Before(candidates[j].program.Start)
+	})
+
+	recordings, err := s.recordingService.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing recordings: %w", err)
+	}
+
+	scheduled := 0
+	for _, c := range candidates {
+		if isDuplicateRecording(recordings, c.program, c.channel) {
+			continue
+		}
+		rec, err := s.recordingService.Schedule(ctx, s.scheduleRequest(c))
+		if err != nil {
+			if errors.Is(err, ErrRecordingExists) {
+				continue
+			}
+			s.logger.Warn("recording rule could not schedule programme",
+				slog.String("rule_id", c.rule.ID.String()),
+				slog.String("rule", c.rule.Name),
+				slog.String("title", c.program.Title),
+				slog.String("channel", c.channel.ChannelName),
+				slog.Time("start", c.program.Start),
+				slog.String("error", err.Error()))
+			continue
+		}
+		recordings = append(recordings, rec)
+		scheduled++
+	}
+
+	if scheduled > 0 {
+		s.logger.Info("recording rules scheduled recordings",
+			slog.String("epg_source_id", epgSourceID.String()),
+			slog.Int("scheduled", scheduled))
+	}
+	return scheduled, nil
+}
+
+// compileRules parses the enabled rules, skipping any that no longer parse.
+func (s *RecordingRuleService) compileRules(ctx context.Context) ([]compiledRecordingRule, error) {
+	rules, err := s.ruleRepo.GetEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing recording rules: %w", err)
+	}
+	compiled := make([]compiledRecordingRule, 0, len(rules))
+	for _, rule := range rules {
+		parsed, err := expression.PreprocessAndParse(rule.Expression)
+		if err != nil || parsed == nil {
+			s.logger.Warn("skipping recording rule with invalid expression",
+				slog.String("rule_id", rule.ID.String()),
+				slog.String("rule", rule.Name))
+			continue
+		}
+		compiled = append(compiled, compiledRecordingRule{rule: rule, parsed: parsed})
+	}
+	return compiled, nil
+}
+
+// channelsByTvgID indexes all channels with an EPG identifier.
+func (s *RecordingRuleService) channelsByTvgID(ctx context.Context) (map[string][]*models.Channel, error) {
+	index := make(map[string][]*models.Channel)
+	err := s.channelRepo.GetAllStreaming(ctx, func(ch *models.Channel) error {
+		if ch.TvgID != "" {
+			index[ch.TvgID] = append(index[ch.TvgID], ch)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing channels: %w", err)
+	}
+	return index, nil
+}
+
+// scheduleRequest builds the recording request for a rule match.
+func (s *RecordingRuleService) scheduleRequest(c ruleCandidate) ScheduleRecordingRequest {
+	req := ScheduleRecordingRequest{
+		ChannelID: c.channel.ID,
+		ProgramID: &c.program.ID,
+		Format:    c.rule.Format,
+		RuleID:    &c.rule.ID,
+	}
+	if c.rule.PaddingBefore != nil {
+		d := time.Duration(*c.rule.PaddingBefore) * time.Second
+		req.PaddingBefore = &d
+	}
+	if c.rule.PaddingAfter != nil {
+		d := time.Duration(*c.rule.PaddingAfter) * time.Second
+		req.PaddingAfter = &d
+	}
+	return req
+}
+
+// isDuplicateRecording reports whether the programme airing on ch is already
+// covered by an existing recording. A recording of the same airing counts
+// whatever its status, so cancelled rule recordings are not rescheduled;
+// otherwise only scheduled, in-progress and completed recordings of the same
+// episode count, so a failed recording can be retried at the next airing.
+func isDuplicateRecording(recordings []*models.Recording, prog *models.EpgProgram, ch *models.Channel) bool {
+	for _, rec := range recordings {
+		if rec.ChannelID == ch.ID && rec.StartTime.Equal(prog.Start) {
+			return true
+		}
+		if rec.Status == models.RecordingStatusFailed || rec.Status == models.RecordingStatusCancelled {
+			continue
+		}
+		if rec.IsSameEpisode(prog) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordingRuleFields returns the expression fields for a programme airing on a channel.
+func recordingRuleFields(prog *models.EpgProgram, ch *models.Channel, sourceName string) map[string]string {
+	fields := map[string]string{
+		"programme_title":       prog.Title,
+		"programme_description": prog.Description,
+		"programme_category":    prog.Category,
+		"programme_start":       prog.Start.Format(time.RFC3339),
+		"programme_stop":        prog.Stop.Format(time.RFC3339),
+		"channel_name":          ch.ChannelName,
+		"tvg_id":                ch.TvgID,
+		"tvg_name":              ch.TvgName,
+		"group_title":           ch.GroupTitle,
+		"source_name":           sourceName,
+	}
+	if prog.SeasonNumber > 0 {
+		fields["programme_season"] = strconv.Itoa(prog.SeasonNumber)
+	}
+	if prog.EpisodeNumber > 0 {
+		fields["programme_episode"] = strconv.Itoa(prog.EpisodeNumber)
+	}
+	if ch.ChannelNumber > 0 {
+		fields["channel_number"] = strconv.Itoa(ch.ChannelNumber)
+	}
+	return fields
+}
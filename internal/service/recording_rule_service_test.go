@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecordingRuleService(env *recordingTestEnv) *RecordingRuleService {
+	return NewRecordingRuleService(
+		repository.NewRecordingRuleRepository(env.db),
+		env.svc,
+		repository.NewChannelRepository(env.db),
+		repository.NewEpgProgramRepository(env.db),
+		repository.NewStreamSourceRepository(env.db),
+		repository.NewEpgSourceRepository(env.db),
+	)
+}
+
+func TestRecordingRuleService_CreateValidatesExpression(t *testing.T) {
+	env := setupRecordingTestEnv(t, 0)
+	svc := newTestRecordingRuleService(env)
+	ctx := context.Background()
+
+	var ve models.ValidationError
+	err := svc.Create(ctx, &models.RecordingRule{Name: "broken", Expression: `programme_title matches`})
+	require.ErrorAs(t, err, &ve)
+	assert.Equal(t, "expression", ve.Field)
+
+	err = svc.Create(ctx, &models.RecordingRule{Name: "wrong domain", Expression: `user_agent contains "VLC"`})
+	require.ErrorAs(t, err, &ve)
+
+	rule := &models.RecordingRule{Name: "news", Expression: `programme_title contains "News" AND channel_name contains "BBC"`}
+	require.NoError(t, svc.Create(ctx, rule))
+
+	_, err = svc.GetByID(ctx, models.NewULID())
+	assert.ErrorIs(t, err, ErrRecordingRuleNotFound)
+}
+
+func TestRecordingRuleService_EvaluateForEpgSource(t *testing.T) {
+	env := setupRecordingTestEnv(t, 0)
+	svc := newTestRecordingRuleService(env)
+	ctx := context.Background()
+
+	var channels []*models.Channel
+	for _, name := range []string{"BBC One", "BBC One HD"} {
+		ch := &models.Channel{
+			SourceID:    env.source.ID,
+			ExtID:       name,
+			TvgID:       "bbc1.uk",
+			ChannelName: name,
+			StreamURL:   "http://example.com/" + name,
+		}
+		require.NoError(t, env.db.Create(ch).Error)
+		channels = append(channels, ch)
+	}
+
+	epgSource := &models.EpgSource{Name: "guide", Type: models.EpgSourceTypeXMLTV, URL: "http://example.com/guide.xml"}
+	require.NoError(t, env.db.Create(epgSource).Error)
+
+	base := time.Now().Truncate(time.Second)
+	addProgram := func(title string, start time.Time, season, episode int) *models.EpgProgram {
+		p := &models.EpgProgram{
+			SourceID:      epgSource.ID,
+			ChannelID:     "bbc1.uk",
+			Start:         start,
+			Stop:          start.Add(90 * time.Minute),
+			Title:         title,
+			SeasonNumber:  season,
+			EpisodeNumber: episode,
+		}
+		require.NoError(t, env.db.Create(p).Error)
+		return p
+	}
+	first := addProgram("Match of the Day", base.Add(time.Hour), 3, 7)
+	addProgram("Match of the Day", base.Add(24*time.Hour), 3, 7) // repeat
+	next := addProgram("Match of the Day", base.Add(48*time.Hour), 3, 8)
+	addProgram("Football Focus", base.Add(3*time.Hour), 0, 0)
+	addProgram("Match of the Day", base.Add(-10*time.Minute), 3, 6) // already on air
+
+	padding := 600
+	rule := &models.RecordingRule{
+		Name:         "MOTD",
+		Expression:   `programme_title matches "^Match of the Day" AND channel_name contains "BBC One"`,
+		PaddingAfter: &padding,
+	}
+	require.NoError(t, svc.Create(ctx, rule))
+
+	scheduled, err := svc.EvaluateForEpgSource(ctx, epgSource.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, scheduled)
+
+	recordings, err := env.svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, recordings, 2)
+	byProgram := map[models.ULID]*models.Recording{}
+	for _, rec := range recordings {
+		require.NotNil(t, rec.ProgramID)
+		byProgram[*rec.ProgramID] = rec
+		require.NotNil(t, rec.RuleID)
+		assert.Equal(t, rule.ID, *rec.RuleID)
+		assert.Equal(t, channels[0].ID, rec.ChannelID)
+		assert.Equal(t, 600, rec.PaddingAfter)
+		assert.Equal(t, 120, rec.PaddingBefore)
+	}
+	require.Contains(t, byProgram, first.ID)
+	require.Contains(t, byProgram, next.ID)
+	assert.Equal(t, 7, byProgram[first.ID].EpisodeNumber)
+
+	// Re-evaluating schedules nothing new.
+	scheduled, err = svc.EvaluateForEpgSource(ctx, epgSource.ID)
+	require.NoError(t, err)
+	assert.Zero(t, scheduled)
+
+	// A cancelled airing is not rescheduled, but its episode is still unrecorded,
+	// so the repeat is scheduled instead.
+	_, err = env.svc.Stop(ctx, byProgram[first.ID].ID)
+	require.NoError(t, err)
+	scheduled, err = svc.EvaluateForEpgSource(ctx, epgSource.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, scheduled)
+
+	recordings, err = env.svc.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, recordings, 3)
+}
+
+func TestRecordingRuleService_DisabledRule(t *testing.T) {
+	env := setupRecordingTestEnv(t, 0)
+	svc := newTestRecordingRuleService(env)
+	ctx := context.Background()
+
+	ch := &models.Channel{SourceID: env.source.ID, ExtID: "news", TvgID: "news.uk", ChannelName: "News", StreamURL: "http://example.com/news"}
+	require.NoError(t, env.db.Create(ch).Error)
+	epgSource := &models.EpgSource{Name: "guide", Type: models.EpgSourceTypeXMLTV, URL: "http://example.com/guide.xml"}
+	require.NoError(t, env.db.Create(epgSource).Error)
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, env.db.Create(&models.EpgProgram{
+		SourceID: epgSource.ID, ChannelID: "news.uk", Start: start, Stop: start.Add(time.Hour), Title: "Evening News",
+	}).Error)
+
+	require.NoError(t, svc.Create(ctx, &models.RecordingRule{Name: "news", Expression: `title contains "news"`, IsEnabled: new(false)}))
+
+	scheduled, err := svc.ApplyAll(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, scheduled)
+}
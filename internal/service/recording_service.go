@@ -0,0 +1,650 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/config"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/jmylchreest/tvarr/internal/storage"
+)
+
+// Service-level errors for recordings.
+var (
+	// ErrRecordingNotFound is returned when a recording is not found.
+	ErrRecordingNotFound = errors.New("recording not found")
+
+	// ErrRecordingExists is returned when the programme is already scheduled on the channel.
+	ErrRecordingExists = errors.New("programme is already scheduled for recording")
+
+	// ErrRecordingConflict is returned when a recording would exceed its source's
+	// concurrent stream limit.
+	ErrRecordingConflict = errors.New("recording conflicts with the source's concurrent stream limit")
+
+	// ErrRecordingNotActive is returned when stopping a recording that is not scheduled or in progress.
+	ErrRecordingNotActive = errors.New("recording is not scheduled or in progress")
+
+	// ErrRecordingFileUnavailable is returned when a recording has no file to download.
+	ErrRecordingFileUnavailable = errors.New("recording file is not available")
+
+	// ErrProgramNotFound is returned when scheduling from an unknown EPG programme.
+	ErrProgramNotFound = errors.New("EPG programme not found")
+)
+
+// recordingRetryInterval is how long the recorder waits before rejoining the
+// relay after the upstream stream ends mid-recording.
+const recordingRetryInterval = 5 * time.Second
+
+// RecordingConflictError reports the recordings that would share a source with a
+// new recording beyond the source's MaxConcurrentStreams.
+type RecordingConflictError struct {
+	SourceName string
+	Limit      int
+	Conflicts  []*models.Recording
+}
+
+// Error implements error.
+func (e *RecordingConflictError) Error() string {
+	titles := make([]string, 0, len(e.Conflicts))
+	for _, r := range e.Conflicts {
+		titles = append(titles, fmt.Sprintf("%q on %s", r.Title, r.ChannelName))
+	}
+	return fmt.Sprintf("%s: source %q allows %d concurrent streams; overlapping recordings: %s",
+		ErrRecordingConflict, e.SourceName, e.Limit, strings.Join(titles, ", "))
+}
+
+// Unwrap lets errors.Is match ErrRecordingConflict.
+func (e *RecordingConflictError) Unwrap() error {
+	return ErrRecordingConflict
+}
+
+// RecordingRelay starts relay sessions for the recorder.
+type RecordingRelay interface {
+	StartRelayWithProfile(ctx context.Context, channelID models.ULID, profile *models.EncodingProfile) (*relay.RelaySession, error)
+}
+
+// captureFunc writes a channel's stream to w until ctx is done or the stream ends.
+type captureFunc func(ctx context.Context, rec *models.Recording, w io.Writer) error
+
+// ScheduleRecordingRequest describes a recording to schedule. Either ProgramID or
+// Title, Start and End must be set; nil paddings and an empty format use the
+// configured defaults.
+type ScheduleRecordingRequest struct {
+	ChannelID     models.ULID
+	ProgramID     *models.ULID
+	Title         string
+	Start         time.Time
+	End           time.Time
+	PaddingBefore *time.Duration
+	PaddingAfter  *time.Duration
+	Format        models.RecordingFormat
+	RuleID        *models.ULID
+}
+
+// activeRecording tracks a running capture.
+type activeRecording struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	stopped bool
+}
+
+// RecordingService schedules DVR recordings and captures them from relay sessions
+// into the storage sandbox.
+type RecordingService struct {
+	recordingRepo repository.RecordingRepository
+	channelRepo   repository.ChannelRepository
+	programRepo   repository.EpgProgramRepository
+	jobRepo       repository.JobRepository
+	sandbox       *storage.Sandbox
+	relay         RecordingRelay
+	capture       captureFunc
+	logger        *slog.Logger
+
+	directory     string
+	paddingBefore time.Duration
+	paddingAfter  time.Duration
+	format        models.RecordingFormat
+	retryInterval time.Duration
+
+	// baseCtx outlives the job that starts a recording; it is cancelled on shutdown.
+	baseCtx    context.Context
+	baseCancel context.CancelFunc
+
+	mu     sync.Mutex
+	active map[models.ULID]*activeRecording
+	wg     sync.WaitGroup
+}
+
+// NewRecordingService creates a new recording service.
+func NewRecordingService(
+	recordingRepo repository.RecordingRepository,
+	channelRepo repository.ChannelRepository,
+	programRepo repository.EpgProgramRepository,
+	jobRepo repository.JobRepository,
+	sandbox *storage.Sandbox,
+) *RecordingService {
+	baseCtx, baseCancel := context.WithCancel(context.Background())
+	s := &RecordingService{
+		recordingRepo: recordingRepo,
+		channelRepo:   channelRepo,
+		programRepo:   programRepo,
+		jobRepo:       jobRepo,
+		sandbox:       sandbox,
+		logger:        slog.Default(),
+		directory:     "recordings",
+		format:        models.RecordingFormatMPEGTS,
+		retryInterval: recordingRetryInterval,
+		baseCtx:       baseCtx,
+		baseCancel:    baseCancel,
+		active:        make(map[models.ULID]*activeRecording),
+	}
+	s.capture = s.captureFromRelay
+	return s
+}
+
+// WithLogger sets the logger for the service.
+func (s *RecordingService) WithLogger(logger *slog.Logger) *RecordingService {
+	s.logger = logger
+	return s
+}
+
+// WithRelay sets the relay used to capture channels.
+func (s *RecordingService) WithRelay(r RecordingRelay) *RecordingService {
+	s.relay = r
+	return s
+}
+
+// WithConfig applies the recording directory and default padding and format.
+func (s *RecordingService) WithConfig(cfg config.RecordingConfig) *RecordingService {
+	if cfg.Directory != "" {
+		s.directory = cfg.Directory
+	}
+	s.paddingBefore = cfg.PaddingBefore
+	s.paddingAfter = cfg.PaddingAfter
+	if format := models.RecordingFormat(cfg.Format); format.IsValid() {
+		s.format = format
+	}
+	return s
+}
+
+// List returns all recordings, newest first.
+func (s *RecordingService) List(ctx context.Context) ([]*models.Recording, error) {
+	return s.recordingRepo.GetAll(ctx)
+}
+
+// GetByID returns a recording by ID.
+func (s *RecordingService) GetByID(ctx context.Context, id models.ULID) (*models.Recording, error) {
+	rec, err := s.recordingRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting recording: %w", err)
+	}
+	if rec == nil {
+		return nil, ErrRecordingNotFound
+	}
+	return rec, nil
+}
+
+// Schedule validates a recording request, checks it against the source's
+// concurrent stream limit and queues a recording job for its padded start time.
+func (s *RecordingService) Schedule(ctx context.Context, req ScheduleRecordingRequest) (*models.Recording, error) {
+	channel, err := s.channelRepo.GetByIDWithSource(ctx, req.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("getting channel: %w", err)
+	}
+	if channel == nil {
+		return nil, ErrChannelNotFound
+	}
+
+	rec := &models.Recording{
+		ChannelID:     channel.ID,
+		ChannelName:   channel.ChannelName,
+		SourceID:      channel.SourceID,
+		Title:         strings.TrimSpace(req.Title),
+		StartTime:     req.Start,
+		EndTime:       req.End,
+		PaddingBefore: int(s.paddingBefore / time.Second),
+		PaddingAfter:  int(s.paddingAfter / time.Second),
+		Format:        s.format,
+		Status:        models.RecordingStatusScheduled,
+		RuleID:        req.RuleID,
+	}
+	if req.ProgramID != nil {
+		program, err := s.programRepo.GetByID(ctx, *req.ProgramID)
+		if err != nil {
+			return nil, fmt.Errorf("getting EPG programme: %w", err)
+		}
+		if program == nil {
+			return nil, ErrProgramNotFound
+		}
+		rec.ProgramID = &program.ID
+		rec.Title = program.Title
+		rec.SubTitle = program.SubTitle
+		rec.Description = program.Description
+		rec.StartTime = program.Start
+		rec.EndTime = program.Stop
+		rec.SeasonNumber = program.SeasonNumber
+		rec.EpisodeNumber = program.EpisodeNumber
+		rec.EpisodeID = program.ProgramID
+	}
+	if req.PaddingBefore != nil {
+		rec.PaddingBefore = int(*req.PaddingBefore / time.Second)
+	}
+	if req.PaddingAfter != nil {
+		rec.PaddingAfter = int(*req.PaddingAfter / time.Second)
+	}
+	if req.Format != "" {
+		rec.Format = req.Format
+	}
+
+	if err := rec.Validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !rec.RecordEnd().After(now) {
+		return nil, models.ValidationError{Field: "end_time", Message: "recording window has already ended"}
+	}
+
+	active, err := s.recordingRepo.GetByStatus(ctx, models.RecordingStatusScheduled, models.RecordingStatusRecording)
+	if err != nil {
+		return nil, fmt.Errorf("listing active recordings: %w", err)
+	}
+	if rec.ProgramID != nil {
+		for _, other := range active {
+			if other.ChannelID == rec.ChannelID && other.ProgramID != nil && *other.ProgramID == *rec.ProgramID {
+				return nil, ErrRecordingExists
+			}
+		}
+	}
+	if channel.Source != nil {
+		if conflicts := findRecordingConflicts(rec, active, channel.Source.ConnectionLimit()); conflicts != nil {
+			return nil, &RecordingConflictError{
+				SourceName: channel.Source.Name,
+				Limit:      channel.Source.ConnectionLimit(),
+				Conflicts:  conflicts,
+			}
+		}
+	}
+
+	if err := s.recordingRepo.Create(ctx, rec); err != nil {
+		return nil, fmt.Errorf("creating recording: %w", err)
+	}
+
+	runAt := rec.RecordStart()
+	if runAt.Before(now) {
+		runAt = now
+	}
+	if err := s.queueJob(ctx, rec, runAt); err != nil {
+		_ = s.recordingRepo.Delete(ctx, rec.ID)
+		return nil, err
+	}
+
+	s.logger.Info("recording scheduled",
+		slog.String("recording_id", rec.ID.String()),
+		slog.String("title", rec.Title),
+		slog.String("channel", rec.ChannelName),
+		slog.Time("start", rec.RecordStart()),
+		slog.Time("end", rec.RecordEnd()))
+
+	return rec, nil
+}
+
+// findRecordingConflicts returns the overlapping recordings on rec's source if
+// adding rec would need more than limit concurrent streams from it at any point
+// in its window. Recordings of the same channel share one relay session, so
+// streams are counted per channel. A limit of zero means unlimited.
+func findRecordingConflicts(rec *models.Recording, active []*models.Recording, limit int) []*models.Recording {
+	if limit <= 0 {
+		return nil
+	}
+
+	start, end := rec.RecordStart(), rec.RecordEnd()
+	var overlapping []*models.Recording
+	for _, other := range active {
+		if other.ID == rec.ID || other.SourceID != rec.SourceID || !other.IsActive() {
+			continue
+		}
+		if other.Overlaps(start, end) {
+			overlapping = append(overlapping, other)
+		}
+	}
+
+	// Concurrency only increases when a recording starts, so checking rec's
+	// start and every overlapping start inside its window finds the peak.
+	instants := []time.Time{start}
+	for _, other := range overlapping {
+		if other.RecordStart().After(start) {
+			instants = append(instants, other.RecordStart())
+		}
+	}
+	for _, at := range instants {
+		channels := map[models.ULID]bool{rec.ChannelID: true}
+		for _, other := range overlapping {
+			if !other.RecordStart().After(at) && at.Before(other.RecordEnd()) {
+				channels[other.ChannelID] = true
+			}
+		}
+		if len(channels) > limit {
+			return overlapping
+		}
+	}
+	return nil
+}
+
+// queueJob creates the scheduler job that starts the recording at runAt.
+func (s *RecordingService) queueJob(ctx context.Context, rec *models.Recording, runAt time.Time) error {
+	job := &models.Job{
+		Type:       models.JobTypeRecording,
+		TargetID:   rec.ID,
+		TargetName: rec.Title,
+		Status:     models.JobStatusScheduled,
+		NextRunAt:  &runAt,
+		Priority:   models.JobPriorityRecording,
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return fmt.Errorf("creating recording job: %w", err)
+	}
+	return nil
+}
+
+// cancelJobs cancels the recording's jobs that have not run yet.
+func (s *RecordingService) cancelJobs(ctx context.Context, id models.ULID) error {
+	jobs, err := s.jobRepo.GetByTargetID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("getting recording jobs: %w", err)
+	}
+	for _, job := range jobs {
+		if job.Type != models.JobTypeRecording || !job.IsPending() {
+			continue
+		}
+		job.MarkCancelled()
+		if err := s.jobRepo.Update(ctx, job); err != nil {
+			return fmt.Errorf("cancelling recording job: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartRecording begins capturing a scheduled recording in the background and
+// returns immediately. It is called by the recording job at the padded start time.
+func (s *RecordingService) StartRecording(ctx context.Context, id models.ULID) (string, error) {
+	rec, err := s.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !rec.IsActive() {
+		return fmt.Sprintf("recording %s is %s, nothing to do", rec.Title, rec.Status), nil
+	}
+
+	now := time.Now()
+	if !now.Before(rec.RecordEnd()) {
+		rec.Status = models.RecordingStatusFailed
+		rec.LastError = "recording window passed before capture started"
+		rec.CompletedAt = &now
+		if err := s.recordingRepo.Update(ctx, rec); err != nil {
+			return "", fmt.Errorf("updating recording: %w", err)
+		}
+		return fmt.Sprintf("missed recording %s", rec.Title), nil
+	}
+	if s.relay == nil {
+		return "", errors.New("recording relay is not configured")
+	}
+
+	s.mu.Lock()
+	if _, running := s.active[id]; running {
+		s.mu.Unlock()
+		return fmt.Sprintf("recording %s already in progress", rec.Title), nil
+	}
+	captureCtx, cancel := context.WithDeadline(s.baseCtx, rec.RecordEnd())
+	entry := &activeRecording{cancel: cancel, done: make(chan struct{})}
+	s.active[id] = entry
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	if rec.FilePath == "" {
+		rec.FilePath = path.Join(s.directory, rec.ID.String()+rec.Format.Extension())
+	}
+	rec.Status = models.RecordingStatusRecording
+	if rec.StartedAt == nil {
+		rec.StartedAt = &now
+	}
+	if err := s.recordingRepo.Update(ctx, rec); err != nil {
+		cancel()
+		s.mu.Lock()
+		delete(s.active, id)
+		s.mu.Unlock()
+		close(entry.done)
+		s.wg.Done()
+		return "", fmt.Errorf("updating recording: %w", err)
+	}
+
+	go s.record(captureCtx, entry, rec)
+
+	return fmt.Sprintf("started recording %s until %s", rec.Title, rec.RecordEnd().UTC().Format(time.RFC3339)), nil
+}
+
+// record captures the recording until its window ends, rejoining the relay if
+// the upstream stream drops, then finalises the recording's status.
+func (s *RecordingService) record(ctx context.Context, entry *activeRecording, rec *models.Recording) {
+	defer s.wg.Done()
+	defer close(entry.done)
+	defer entry.cancel()
+
+	logger := s.logger.With(
+		slog.String("recording_id", rec.ID.String()),
+		slog.String("title", rec.Title))
+	logger.Info("recording started", slog.Time("until", rec.RecordEnd()))
+
+	var lastErr error
+	file, err := s.sandbox.OpenFile(rec.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		lastErr = err
+	} else {
+		for {
+			if err := s.capture(ctx, rec, file); err != nil && ctx.Err() == nil {
+				lastErr = err
+				logger.Warn("recording capture interrupted", slog.Any("error", err))
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.retryInterval):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if err := file.Close(); err != nil && lastErr == nil {
+			lastErr = err
+		}
+	}
+
+	s.mu.Lock()
+	stopped := entry.stopped
+	delete(s.active, rec.ID)
+	s.mu.Unlock()
+
+	s.finish(rec, stopped, lastErr)
+	logger.Info("recording finished", slog.String("status", string(rec.Status)), slog.Int64("bytes", rec.FileSize))
+}
+
+// finish records the outcome of a capture. On shutdown the recording is left in
+// the recording state so RecoverInterrupted can resume it.
+func (s *RecordingService) finish(rec *models.Recording, stopped bool, lastErr error) {
+	ctx := context.Background()
+
+	current, err := s.recordingRepo.GetByID(ctx, rec.ID)
+	if err != nil || current == nil {
+		// Deleted while recording.
+		return
+	}
+	*rec = *current
+
+	if size, err := s.sandbox.Size(rec.FilePath); err == nil {
+		rec.FileSize = size
+	}
+	if lastErr != nil {
+		rec.LastError = lastErr.Error()
+	}
+
+	if s.baseCtx.Err() == nil {
+		now := time.Now()
+		rec.CompletedAt = &now
+		switch {
+		case stopped:
+			rec.Status = models.RecordingStatusCancelled
+		case rec.FileSize > 0:
+			rec.Status = models.RecordingStatusCompleted
+		default:
+			rec.Status = models.RecordingStatusFailed
+			if rec.LastError == "" {
+				rec.LastError = "no data received from stream"
+			}
+		}
+	}
+
+	if err := s.recordingRepo.Update(ctx, rec); err != nil {
+		s.logger.Error("failed to update recording",
+			slog.String("recording_id", rec.ID.String()),
+			slog.Any("error", err))
+	}
+}
+
+// stopCapture cancels a running capture and waits for it to finish. It reports
+// whether a capture was running.
+func (s *RecordingService) stopCapture(id models.ULID) bool {
+	s.mu.Lock()
+	entry, running := s.active[id]
+	if running {
+		entry.stopped = true
+		entry.cancel()
+	}
+	s.mu.Unlock()
+
+	if running {
+		<-entry.done
+	}
+	return running
+}
+
+// Stop ends a scheduled or in-progress recording early. Anything captured so far
+// is kept and the recording is marked cancelled.
+func (s *RecordingService) Stop(ctx context.Context, id models.ULID) (*models.Recording, error) {
+	rec, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !rec.IsActive() {
+		return nil, ErrRecordingNotActive
+	}
+
+	if err := s.cancelJobs(ctx, id); err != nil {
+		return nil, err
+	}
+	if s.stopCapture(id) {
+		return s.GetByID(ctx, id)
+	}
+
+	now := time.Now()
+	rec.Status = models.RecordingStatusCancelled
+	rec.CompletedAt = &now
+	if err := s.recordingRepo.Update(ctx, rec); err != nil {
+		return nil, fmt.Errorf("updating recording: %w", err)
+	}
+	return rec, nil
+}
+
+// Delete stops a recording if needed and removes it and its file.
+func (s *RecordingService) Delete(ctx context.Context, id models.ULID) error {
+	rec, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cancelJobs(ctx, id); err != nil {
+		return err
+	}
+	s.stopCapture(id)
+
+	if rec.FilePath != "" {
+		if err := s.sandbox.Remove(rec.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing recording file: %w", err)
+		}
+	}
+	return s.recordingRepo.Delete(ctx, id)
+}
+
+// Open returns a recording and its file for download. The caller must close the file.
+func (s *RecordingService) Open(ctx context.Context, id models.ULID) (*models.Recording, *os.File, error) {
+	rec, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rec.FilePath == "" {
+		return nil, nil, ErrRecordingFileUnavailable
+	}
+	file, err := s.sandbox.OpenFile(rec.FilePath, os.O_RDONLY, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrRecordingFileUnavailable
+		}
+		return nil, nil, err
+	}
+	return rec, file, nil
+}
+
+// RecoverInterrupted handles recordings left in progress by a previous shutdown:
+// those whose window is still open are resumed, the rest are finalised.
+func (s *RecordingService) RecoverInterrupted(ctx context.Context) (resumed, finalised int, err error) {
+	recs, err := s.recordingRepo.GetByStatus(ctx, models.RecordingStatusRecording)
+	if err != nil {
+		return 0, 0, fmt.Errorf("listing interrupted recordings: %w", err)
+	}
+
+	now := time.Now()
+	for _, rec := range recs {
+		if now.Before(rec.RecordEnd()) {
+			if err := s.queueJob(ctx, rec, now); err != nil {
+				return resumed, finalised, err
+			}
+			resumed++
+			continue
+		}
+
+		// FileSize is only recorded when a capture finishes, so use the file on disk
+		rec.CompletedAt = &now
+		if rec.FilePath != "" {
+			if size, err := s.sandbox.Size(rec.FilePath); err == nil {
+				rec.FileSize = size
+			}
+		}
+		if rec.FileSize > 0 {
+			rec.Status = models.RecordingStatusCompleted
+		} else {
+			rec.Status = models.RecordingStatusFailed
+			rec.LastError = "interrupted by shutdown"
+		}
+		if err := s.recordingRepo.Update(ctx, rec); err != nil {
+			return resumed, finalised, fmt.Errorf("updating recording: %w", err)
+		}
+		finalised++
+	}
+	return resumed, finalised, nil
+}
+
+// Close stops all running captures, leaving them to be resumed on the next start.
+func (s *RecordingService) Close() {
+	s.baseCancel()
+	s.wg.Wait()
+}
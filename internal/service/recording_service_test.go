@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jmylchreest/tvarr/internal/config"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/jmylchreest/tvarr/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type recordingTestEnv struct {
+	db      *gorm.DB
+	svc     *RecordingService
+	jobRepo repository.JobRepository
+	source  *models.StreamSource
+}
+
+func setupRecordingTestEnv(t *testing.T, maxStreams int) *recordingTestEnv {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.StreamSource{}, &models.Channel{},
+		&models.EpgSource{}, &models.EpgProgram{},
+		&models.Job{}, &models.Recording{}, &models.RecordingRule{},
+	))
+
+	source := &models.StreamSource{
+		Name:    "provider",
+		Type:    models.SourceTypeM3U,
+		URL:     "http://example.com/provider.m3u",
+		Enabled: new(true),
+	}
+	require.NoError(t, db.Create(source).Error)
+	require.NoError(t, db.Model(source).Update("max_concurrent_streams", maxStreams).Error)
+
+	sandbox, err := storage.NewSandbox(t.TempDir())
+	require.NoError(t, err)
+
+	jobRepo := repository.NewJobRepository(db)
+	svc := NewRecordingService(
+		repository.NewRecordingRepository(db),
+		repository.NewChannelRepository(db),
+		repository.NewEpgProgramRepository(db),
+		jobRepo,
+		sandbox,
+	).WithConfig(config.RecordingConfig{
+		PaddingBefore: 2 * time.Minute,
+		PaddingAfter:  5 * time.Minute,
+		Format:        "mpegts",
+	})
+	svc.retryInterval = 10 * time.Millisecond
+	t.Cleanup(svc.Close)
+
+	return &recordingTestEnv{db: db, svc: svc, jobRepo: jobRepo, source: source}
+}
+
+func (e *recordingTestEnv) createChannel(t *testing.T, name string) *models.Channel {
+	t.Helper()
+	channel := &models.Channel{
+		SourceID:    e.source.ID,
+		ExtID:       name,
+		ChannelName: name,
+		StreamURL:   "http://example.com/" + name,
+	}
+	require.NoError(t, e.db.Create(channel).Error)
+	return channel
+}
+
+func manualRecording(channelID models.ULID, title string, start time.Time, length time.Duration) ScheduleRecordingRequest {
+	none := time.Duration(0)
+	return ScheduleRecordingRequest{
+		ChannelID:     channelID,
+		Title:         title,
+		Start:         start,
+		End:           start.Add(length),
+		PaddingBefore: &none,
+		PaddingAfter:  &none,
+	}
+}
+
+func TestRecordingService_ScheduleFromProgram(t *testing.T) {
+	env := setupRecordingTestEnv(t, 1)
+	ctx := context.Background()
+	channel := env.createChannel(t, "bbc1")
+
+	epgSource := &models.EpgSource{Name: "guide", Type: models.EpgSourceTypeXMLTV, URL: "http://example.com/guide.xml"}
+	require.NoError(t, env.db.Create(epgSource).Error)
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	program := &models.EpgProgram{
+		SourceID:  epgSource.ID,
+		ChannelID: "bbc1.uk",
+		Start:     start,
+		Stop:      start.Add(30 * time.Minute),
+		Title:     "Evening News",
+		SubTitle:  "Headlines",
+	}
+	require.NoError(t, env.db.Create(program).Error)
+
+	rec, err := env.svc.Schedule(ctx, ScheduleRecordingRequest{ChannelID: channel.ID, ProgramID: &program.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "Evening News", rec.Title)
+	assert.Equal(t, "Headlines", rec.SubTitle)
+	assert.Equal(t, "bbc1", rec.ChannelName)
+	assert.Equal(t, env.source.ID, rec.SourceID)
+	assert.Equal(t, 120, rec.PaddingBefore)
+	assert.Equal(t, 300, rec.PaddingAfter)
+	assert.Equal(t, models.RecordingFormatMPEGTS, rec.Format)
+	assert.Equal(t, models.RecordingStatusScheduled, rec.Status)
+
+	jobs, err := env.jobRepo.GetByTargetID(ctx, rec.ID)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, models.JobTypeRecording, jobs[0].Type)
+	assert.Equal(t, models.JobStatusScheduled, jobs[0].Status)
+	assert.True(t, jobs[0].NextRunAt.Equal(start.Add(-2*time.Minute)))
+
+	_, err = env.svc.Schedule(ctx, ScheduleRecordingRequest{ChannelID: channel.ID, ProgramID: &program.ID})
+	assert.ErrorIs(t, err, ErrRecordingExists)
+
+	missing := models.NewULID()
+	_, err = env.svc.Schedule(ctx, ScheduleRecordingRequest{ChannelID: channel.ID, ProgramID: &missing})
+	assert.ErrorIs(t, err, ErrProgramNotFound)
+}
+
+func TestRecordingService_ScheduleValidation(t *testing.T) {
+	env := setupRecordingTestEnv(t, 1)
+	ctx := context.Background()
+	channel := env.createChannel(t, "bbc1")
+
+	_, err := env.svc.Schedule(ctx, manualRecording(models.NewULID(), "x", time.Now().Add(time.Hour), time.Hour))
+	assert.ErrorIs(t, err, ErrChannelNotFound)
+
+	var ve models.ValidationError
+	_, err = env.svc.Schedule(ctx, manualRecording(channel.ID, "", time.Now().Add(time.Hour), time.Hour))
+	assert.ErrorAs(t, err, &ve)
+
+	_, err = env.svc.Schedule(ctx, manualRecording(channel.ID, "past", time.Now().Add(-2*time.Hour), time.Hour))
+	require.ErrorAs(t, err, &ve)
+	assert.Equal(t, "end_time", ve.Field)
+}
+
+func TestRecordingService_ScheduleConflicts(t *testing.T) {
+	env := setupRecordingTestEnv(t, 1)
+	ctx := context.Background()
+	chA := env.createChannel(t, "a")
+	chB := env.createChannel(t, "b")
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	_, err := env.svc.Schedule(ctx, manualRecording(chA.ID, "first", start, time.Hour))
+	require.NoError(t, err)
+
+	// The same channel shares one relay session, so it does not add a stream.
+	_, err = env.svc.Schedule(ctx, manualRecording(chA.ID, "same channel", start.Add(30*time.Minute), time.Hour))
+	require.NoError(t, err)
+
+	_, err = env.svc.Schedule(ctx, manualRecording(chB.ID, "second", start.Add(30*time.Minute), time.Hour))
+	var conflict *RecordingConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.ErrorIs(t, err, ErrRecordingConflict)
+	assert.Equal(t, 1, conflict.Limit)
+	assert.Len(t, conflict.Conflicts, 2)
+
+	// Back-to-back recordings do not overlap.
+	_, err = env.svc.Schedule(ctx, manualRecording(chB.ID, "after", start.Add(90*time.Minute), time.Hour))
+	require.NoError(t, err)
+}
+
+func TestFindRecordingConflicts_PeakConcurrency(t *testing.T) {
+	source := models.NewULID()
+	start := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	rec := func(channel models.ULID, from, to time.Duration) *models.Recording {
+		r := &models.Recording{
+			ChannelID: channel,
+			SourceID:  source,
+			StartTime: start.Add(from),
+			EndTime:   start.Add(to),
+			Status:    models.RecordingStatusScheduled,
+		}
+		r.ID = models.NewULID()
+		return r
+	}
+
+	newRec := rec(models.NewULID(), 0, time.Hour)
+	early := rec(models.NewULID(), -time.Hour, 20*time.Minute)
+	late := rec(models.NewULID(), 40*time.Minute, 2*time.Hour)
+
+	// Two other channels overlap the new recording, but never at the same time.
+	assert.Nil(t, findRecordingConflicts(newRec, []*models.Recording{early, late}, 2))
+
+	middle := rec(models.NewULID(), 10*time.Minute, 30*time.Minute)
+	assert.Len(t, findRecordingConflicts(newRec, []*models.Recording{early, late, middle}, 2), 3)
+
+	// Unlimited sources never conflict.
+	assert.Nil(t, findRecordingConflicts(newRec, []*models.Recording{early, late, middle}, 0))
+}
+
+func TestRecordingService_RecordAndDownload(t *testing.T) {
+	env := setupRecordingTestEnv(t, 1)
+	ctx := context.Background()
+	channel := env.createChannel(t, "bbc1")
+
+	env.svc.WithRelay(&RelayService{})
+	env.svc.capture = func(ctx context.Context, rec *models.Recording, w io.Writer) error {
+		_, err := w.Write([]byte("mpegts-data"))
+		return err
+	}
+
+	rec, err := env.svc.Schedule(ctx, manualRecording(channel.ID, "Short", time.Now().Add(-time.Second), 1500*time.Millisecond))
+	require.NoError(t, err)
+
+	result, err := env.svc.StartRecording(ctx, rec.ID)
+	require.NoError(t, err)
+	assert.Contains(t, result, "started recording Short")
+
+	require.Eventually(t, func() bool {
+		got, err := env.svc.GetByID(ctx, rec.ID)
+		return err == nil && got.Status == models.RecordingStatusCompleted
+	}, 5*time.Second, 20*time.Millisecond)
+
+	got, file, err := env.svc.Open(ctx, rec.ID)
+	require.NoError(t, err)
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "mpegts-data")
+	assert.Equal(t, int64(len(data)), got.FileSize)
+	assert.NotNil(t, got.StartedAt)
+	assert.NotNil(t, got.CompletedAt)
+
+	require.NoError(t, env.svc.Delete(ctx, rec.ID))
+	_, _, err = env.svc.Open(ctx, rec.ID)
+	assert.ErrorIs(t, err, ErrRecordingNotFound)
+	exists, err := env.svc.sandbox.Exists(got.FilePath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRecordingService_StopAndFailure(t *testing.T) {
+	env := setupRecordingTestEnv(t, 0)
+	ctx := context.Background()
+	channel := env.createChannel(t, "bbc1")
+	env.svc.WithRelay(&RelayService{})
+
+	t.Run("stop keeps captured data", func(t *testing.T) {
+		env.svc.capture = func(ctx context.Context, rec *models.Recording, w io.Writer) error {
+			_, _ = w.Write([]byte("partial"))
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		rec, err := env.svc.Schedule(ctx, manualRecording(channel.ID, "Long", time.Now(), time.Hour))
+		require.NoError(t, err)
+		_, err = env.svc.StartRecording(ctx, rec.ID)
+		require.NoError(t, err)
+
+		stopped, err := env.svc.Stop(ctx, rec.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RecordingStatusCancelled, stopped.Status)
+		assert.Equal(t, int64(len("partial")), stopped.FileSize)
+
+		_, err = env.svc.Stop(ctx, rec.ID)
+		assert.ErrorIs(t, err, ErrRecordingNotActive)
+	})
+
+	t.Run("no data fails", func(t *testing.T) {
+		env.svc.capture = func(ctx context.Context, rec *models.Recording, w io.Writer) error {
+			return errors.New("upstream refused")
+		}
+		rec, err := env.svc.Schedule(ctx, manualRecording(channel.ID, "Empty", time.Now(), 200*time.Millisecond))
+		require.NoError(t, err)
+		_, err = env.svc.StartRecording(ctx, rec.ID)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			got, err := env.svc.GetByID(ctx, rec.ID)
+			return err == nil && got.Status == models.RecordingStatusFailed && got.LastError == "upstream refused"
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("scheduled recording stop cancels job", func(t *testing.T) {
+		rec, err := env.svc.Schedule(ctx, manualRecording(channel.ID, "Tomorrow", time.Now().Add(24*time.Hour), time.Hour))
+		require.NoError(t, err)
+
+		stopped, err := env.svc.Stop(ctx, rec.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RecordingStatusCancelled, stopped.Status)
+
+		jobs, err := env.jobRepo.GetByTargetID(ctx, rec.ID)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, models.JobStatusCancelled, jobs[0].Status)
+	})
+}
+
+func TestRecordingService_RecoverInterrupted(t *testing.T) {
+	env := setupRecordingTestEnv(t, 0)
+	ctx := context.Background()
+	repo := repository.NewRecordingRepository(env.db)
+	now := time.Now()
+
+	ongoing := &models.Recording{
+		ChannelID: models.NewULID(), Title: "ongoing", Format: models.RecordingFormatMPEGTS,
+		StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour),
+		Status: models.RecordingStatusRecording, FileSize: 100,
+	}
+	// Cut off by shutdown: the size is not recorded yet, but data is on disk
+	ended := &models.Recording{
+		ChannelID: models.NewULID(), Title: "ended", Format: models.RecordingFormatMPEGTS,
+		StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour),
+		Status: models.RecordingStatusRecording, FilePath: "recordings/ended.ts",
+	}
+	empty := &models.Recording{
+		ChannelID: models.NewULID(), Title: "empty", Format: models.RecordingFormatMPEGTS,
+		StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour),
+		Status: models.RecordingStatusRecording, FilePath: "recordings/empty.ts",
+	}
+	require.NoError(t, repo.Create(ctx, ongoing))
+	require.NoError(t, repo.Create(ctx, ended))
+	require.NoError(t, repo.Create(ctx, empty))
+	require.NoError(t, env.svc.sandbox.WriteFile(ended.FilePath, []byte("partial")))
+
+	resumed, finalised, err := env.svc.RecoverInterrupted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed)
+	assert.Equal(t, 2, finalised)
+
+	jobs, err := env.jobRepo.GetByTargetID(ctx, ongoing.ID)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+
+	got, err := repo.GetByID(ctx, ended.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RecordingStatusCompleted, got.Status)
+	assert.Equal(t, int64(len("partial")), got.FileSize)
+
+	got, err = repo.GetByID(ctx, empty.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RecordingStatusFailed, got.Status)
+}
+
+func TestHasData(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "recording-*.mp4")
+	require.NoError(t, err)
+	defer file.Close()
+
+	assert.False(t, hasData(file), "a new file needs the init segment")
+	_, err = file.Write([]byte("moov"))
+	require.NoError(t, err)
+	assert.True(t, hasData(file), "a resumed file already has it")
+	assert.False(t, hasData(io.Discard))
+}
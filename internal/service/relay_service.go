@@ -6,13 +6,18 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/jmylchreest/tvarr/internal/config"
 	"github.com/jmylchreest/tvarr/internal/ffmpeg"
 	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/relay"
 	"github.com/jmylchreest/tvarr/internal/repository"
 	"github.com/jmylchreest/tvarr/internal/services"
+	"github.com/jmylchreest/tvarr/pkg/httpclient"
+	"github.com/jmylchreest/tvarr/pkg/m3u"
+	"github.com/jmylchreest/tvarr/pkg/xtream"
 )
 
 // ErrEncodingProfileNotFound is returned when an encoding profile is not found.
@@ -24,6 +29,18 @@ var ErrChannelNotFound = errors.New("channel not found")
 // ErrProxyNotFound is returned when a stream proxy is not found.
 var ErrProxyNotFound = errors.New("stream proxy not found")
 
+// ErrCatchupUnavailable is returned when a channel has no catch-up archive.
+var ErrCatchupUnavailable = errors.New("catch-up is not available for this channel")
+
+// ErrCatchupOutOfRange is returned when a catch-up request falls outside the archive window.
+var ErrCatchupOutOfRange = errors.New("programme is outside the catch-up window")
+
+// ErrStreamUnavailable is returned when a channel's playable stream URL cannot be resolved.
+var ErrStreamUnavailable = errors.New("stream link could not be resolved")
+
+// maxCatchupDuration bounds the length of a single catch-up request.
+const maxCatchupDuration = 24 * time.Hour
+
 // RelayService provides business logic for stream relay functionality.
 type RelayService struct {
 	encodingProfileRepo      repository.EncodingProfileRepository
@@ -36,6 +53,27 @@ type RelayService struct {
 	prober                   *ffmpeg.Prober
 	logger                   *slog.Logger
 	encoderOverridesProvider relay.EncoderOverridesProvider
+	streamAuthenticator      StreamAuthenticator
+	streamResolver           StreamURLResolver
+	failover                 bool
+}
+
+// StreamURLResolver resolves stored channel stream URLs to playable URLs at play
+// time, for sources whose links expire.
+type StreamURLResolver interface {
+	// ResolveStreamURL returns a playable URL for a stored channel stream URL,
+	// or the URL unchanged if the source's URLs are directly playable.
+	ResolveStreamURL(ctx context.Context, source *models.StreamSource, streamURL string) (string, error)
+}
+
+// StreamAuthenticator validates the viewer credential carried on a relay URL.
+type StreamAuthenticator interface {
+	// AuthenticateStream resolves a credential for one channel of a proxy.
+	// It returns ErrStreamUnauthorized if the credential is missing or rejected.
+	AuthenticateStream(ctx context.Context, credential string, proxyID, channelID models.ULID) (*models.Viewer, error)
+	// AuthenticateViewer resolves a viewer token that is not tied to a proxy.
+	// It returns ErrStreamUnauthorized if the token is missing or rejected.
+	AuthenticateViewer(ctx context.Context, token string) (*models.Viewer, error)
 }
 
 // NewRelayService creates a new relay service.
@@ -121,6 +159,10 @@ func (s *RelayService) WithHLSConfig(hlsCfg config.HLSConfig) *RelayService {
 		TargetSegmentDuration: hlsCfg.TargetSegmentDuration,
 		MaxSegments:           hlsCfg.MaxSegments,
 		PlaylistSegments:      hlsCfg.PlaylistSegments,
+		Timeshift: relay.TimeshiftConfig{
+			Directory: hlsCfg.Timeshift.Directory,
+			Window:    hlsCfg.Timeshift.Window,
+		},
 	}
 
 	s.relayManager.Close()
@@ -130,6 +172,7 @@ func (s *RelayService) WithHLSConfig(hlsCfg config.HLSConfig) *RelayService {
 		"target_segment_duration", hlsCfg.TargetSegmentDuration,
 		"max_segments", hlsCfg.MaxSegments,
 		"playlist_segments", hlsCfg.PlaylistSegments,
+		"timeshift_window", hlsCfg.Timeshift.Window,
 	)
 
 	return s
@@ -184,6 +227,27 @@ func (s *RelayService) WithEncoderOverridesProvider(provider relay.EncoderOverri
 	return s
 }
 
+// WithStreamAuthenticator requires a valid viewer credential for every proxy stream.
+// When unset, proxy streams are open to anyone who knows the URL.
+func (s *RelayService) WithStreamAuthenticator(authenticator StreamAuthenticator) *RelayService {
+	s.streamAuthenticator = authenticator
+	return s
+}
+
+// WithStreamURLResolver resolves channel stream URLs immediately before playback.
+// When unset, stored stream URLs are played as-is.
+func (s *RelayService) WithStreamURLResolver(resolver StreamURLResolver) *RelayService {
+	s.streamResolver = resolver
+	return s
+}
+
+// WithFailover enables switching relay sessions to the same channel in a proxy's
+// other stream sources when the upstream fails.
+func (s *RelayService) WithFailover(enabled bool) *RelayService {
+	s.failover = enabled
+	return s
+}
+
 // Close shuts down the relay service and all active sessions.
 func (s *RelayService) Close() {
 	if s.relayManager != nil {
@@ -200,8 +264,8 @@ func (s *RelayService) GetLastKnownCodec(ctx context.Context, streamURL string)
 
 // ProbeStream probes a stream URL for codec information.
 // Returns LastKnownCodec with the selected/primary track info (cached to database).
-func (s *RelayService) ProbeStream(ctx context.Context, streamURL string) (*models.LastKnownCodec, error) {
-	streamInfo, err := s.ProbeStreamFull(ctx, streamURL)
+func (s *RelayService) ProbeStream(ctx context.Context, streamURL string, opts relay.UpstreamOptions) (*models.LastKnownCodec, error) {
+	streamInfo, err := s.ProbeStreamFull(ctx, streamURL, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -240,8 +304,9 @@ func (s *RelayService) ProbeStream(ctx context.Context, streamURL string) (*mode
 }
 
 // ProbeStreamFull probes a stream URL and returns full stream info including all tracks.
-// Use this when you need track lists for UI display or track selection.
-func (s *RelayService) ProbeStreamFull(ctx context.Context, streamURL string) (*ffmpeg.StreamInfo, error) {
+// Use this when you need track lists for UI display or track selection. The stream
+// is fetched through the proxy and with the request headers of opts.
+func (s *RelayService) ProbeStreamFull(ctx context.Context, streamURL string, opts relay.UpstreamOptions) (*ffmpeg.StreamInfo, error) {
 	binInfo, err := s.ffmpegDetector.Detect(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("detecting FFmpeg: %w", err)
@@ -252,7 +317,8 @@ func (s *RelayService) ProbeStreamFull(ctx context.Context, streamURL string) (*
 	}
 
 	// Use QuickProbe for stream info
-	streamInfo, err := s.prober.QuickProbe(ctx, streamURL)
+	prober := s.prober.WithProxy(opts.ProxyURL).WithHeaders(opts.RequestHeaders())
+	streamInfo, err := prober.QuickProbe(ctx, streamURL)
 	if err != nil {
 		return nil, fmt.Errorf("probing stream: %w", err)
 	}
@@ -284,13 +350,10 @@ func (s *RelayService) ClearAllCodecCache(ctx context.Context) (int64, error) {
 
 // StartRelay starts a relay session for a channel.
 func (s *RelayService) StartRelay(ctx context.Context, channelID models.ULID, profileID *models.ULID) (*relay.RelaySession, error) {
-	// Get channel with source preloaded
-	channel, err := s.channelRepo.GetByIDWithSource(ctx, channelID)
+	// Get channel with source preloaded and a playable stream URL
+	channel, err := s.getPlayableChannel(ctx, channelID)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrChannelNotFound, err)
-	}
-	if channel == nil {
-		return nil, ErrChannelNotFound
+		return nil, err
 	}
 
 	// Get encoding profile (use default if not specified)
@@ -311,20 +374,8 @@ func (s *RelayService) StartRelay(ctx context.Context, channelID models.ULID, pr
 		// profile can be nil if no default is set (use passthrough)
 	}
 
-	// Extract stream source info if available
-	var sourceID models.ULID
-	var streamSourceName string
-	var sourceMaxConcurrentStreams int
-	var sourceUserAgent string
-	if channel.Source != nil {
-		sourceID = channel.Source.ID
-		streamSourceName = channel.Source.Name
-		sourceMaxConcurrentStreams = channel.Source.MaxConcurrentStreams
-		sourceUserAgent = channel.Source.UserAgent
-	}
-
 	// Start the relay session
-	session, err := s.relayManager.GetOrCreateSession(ctx, channelID, channel.ChannelName, sourceID, streamSourceName, channel.StreamURL, sourceMaxConcurrentStreams, sourceUserAgent, profile)
+	session, err := s.relayManager.GetOrCreateSession(ctx, channelID, channel.ChannelName, channelUpstream(channel), profile, nil)
 	if err != nil {
 		return nil, fmt.Errorf("starting relay session: %w", err)
 	}
@@ -337,29 +388,34 @@ func (s *RelayService) StartRelay(ctx context.Context, channelID models.ULID, pr
 // StartRelayWithProfile starts a relay session for a channel using a specific profile.
 // This is used when the profile has been pre-resolved (e.g., auto codecs resolved).
 func (s *RelayService) StartRelayWithProfile(ctx context.Context, channelID models.ULID, profile *models.EncodingProfile) (*relay.RelaySession, error) {
-	// Get channel with source preloaded
-	channel, err := s.channelRepo.GetByIDWithSource(ctx, channelID)
+	return s.startRelayWithProfile(ctx, channelID, profile, nil)
+}
+
+// StartProxyRelayWithProfile starts a relay session for a channel served by a proxy.
+// With failover enabled, the session can switch to the same channel in the proxy's
+// other stream sources if the upstream fails.
+func (s *RelayService) StartProxyRelayWithProfile(ctx context.Context, proxyID, channelID models.ULID, profile *models.EncodingProfile) (*relay.RelaySession, error) {
+	return s.startRelayWithProfile(ctx, channelID, profile, &proxyID)
+}
+
+// startRelayWithProfile starts a relay session, with failover alternates from the
+// proxy's sources if proxyID is set.
+func (s *RelayService) startRelayWithProfile(ctx context.Context, channelID models.ULID, profile *models.EncodingProfile, proxyID *models.ULID) (*relay.RelaySession, error) {
+	// Get channel with source preloaded and a playable stream URL
+	channel, err := s.getPlayableChannel(ctx, channelID)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrChannelNotFound, err)
-	}
-	if channel == nil {
-		return nil, ErrChannelNotFound
+		return nil, err
 	}
 
-	// Extract stream source info if available
-	var sourceID models.ULID
-	var streamSourceName string
-	var sourceMaxConcurrentStreams int
-	var sourceUserAgent string
-	if channel.Source != nil {
-		sourceID = channel.Source.ID
-		streamSourceName = channel.Source.Name
-		sourceMaxConcurrentStreams = channel.Source.MaxConcurrentStreams
-		sourceUserAgent = channel.Source.UserAgent
+	primary := channelUpstream(channel)
+	var alternates []relay.Upstream
+	if proxyID != nil {
+		s.applyProxyLimitPolicy(ctx, *proxyID, &primary)
+		alternates = s.failoverUpstreams(ctx, *proxyID, channel)
 	}
 
 	// Start the relay session
-	session, err := s.relayManager.GetOrCreateSession(ctx, channelID, channel.ChannelName, sourceID, streamSourceName, channel.StreamURL, sourceMaxConcurrentStreams, sourceUserAgent, profile)
+	session, err := s.relayManager.GetOrCreateSession(ctx, channelID, channel.ChannelName, primary, profile, alternates)
 	if err != nil {
 		return nil, fmt.Errorf("starting relay session: %w", err)
 	}
@@ -369,6 +425,179 @@ func (s *RelayService) StartRelayWithProfile(ctx context.Context, channelID mode
 	return session, nil
 }
 
+// applyProxyLimitPolicy applies a proxy's connection limit policy, if it has
+// one, and its priority to the primary upstream of a session started for it.
+// Lookup failures keep the source's policy.
+func (s *RelayService) applyProxyLimitPolicy(ctx context.Context, proxyID models.ULID, primary *relay.Upstream) {
+	proxy, err := s.streamProxyRepo.GetByID(ctx, proxyID)
+	if err != nil || proxy == nil {
+		s.logger.Warn("failed to load proxy for relay connection limit policy",
+			"proxy_id", proxyID,
+			"error", err)
+		return
+	}
+	if proxy.ConnectionLimitPolicy != "" {
+		primary.LimitPolicy = proxy.ConnectionLimitPolicy
+	}
+	primary.Priority = proxy.Priority
+}
+
+// failoverUpstreams returns the alternates a channel's relay session can switch to.
+// Channels the proxy collapsed into this one during deduplication come first, in
+// the order the dedup stage ranked them; otherwise channels with the same tvg-id
+// in the proxy's other enabled stream sources, in source priority order.
+// Lookup failures only disable failover.
+func (s *RelayService) failoverUpstreams(ctx context.Context, proxyID models.ULID, channel *models.Channel) []relay.Upstream {
+	if !s.failover {
+		return nil
+	}
+
+	sources, err := s.streamProxyRepo.GetSources(ctx, proxyID)
+	if err != nil {
+		s.logger.Warn("failed to load proxy sources for relay failover",
+			"proxy_id", proxyID,
+			"error", err)
+		return nil
+	}
+	enabled := make(map[models.ULID]*models.StreamSource, len(sources))
+	for _, source := range sources {
+		if models.BoolVal(source.Enabled) {
+			enabled[source.ID] = source
+		}
+	}
+
+	if alternates := s.dedupUpstreams(ctx, proxyID, channel, enabled); len(alternates) > 0 {
+		return alternates
+	}
+
+	if channel.TvgID == "" || len(sources) < 2 {
+		return nil
+	}
+
+	matches, err := s.channelRepo.GetByTvgID(ctx, channel.TvgID)
+	if err != nil {
+		s.logger.Warn("failed to load alternate channels for relay failover",
+			"channel_id", channel.ID,
+			"tvg_id", channel.TvgID,
+			"error", err)
+		return nil
+	}
+
+	// One alternate per source; the first match wins
+	bySource := make(map[models.ULID]*models.Channel, len(matches))
+	for _, match := range matches {
+		if match.StreamURL == "" || match.StreamURL == channel.StreamURL {
+			continue
+		}
+		if _, ok := bySource[match.SourceID]; !ok {
+			bySource[match.SourceID] = match
+		}
+	}
+
+	var alternates []relay.Upstream
+	for _, source := range sources {
+		if source.ID == channel.SourceID || enabled[source.ID] == nil {
+			continue
+		}
+		match, ok := bySource[source.ID]
+		if !ok {
+			continue
+		}
+		if upstream, ok := s.sourceUpstream(ctx, source, match); ok {
+			alternates = append(alternates, upstream)
+		}
+	}
+	return alternates
+}
+
+// dedupUpstreams returns the alternates recorded for a channel by the proxy's
+// deduplication stage, skipping channels that no longer exist or whose source
+// is disabled or no longer part of the proxy.
+func (s *RelayService) dedupUpstreams(ctx context.Context, proxyID models.ULID, channel *models.Channel, sources map[models.ULID]*models.StreamSource) []relay.Upstream {
+	recorded, err := s.streamProxyRepo.GetChannelAlternates(ctx, proxyID, channel.ID)
+	if err != nil {
+		s.logger.Warn("failed to load channel alternates for relay failover",
+			"proxy_id", proxyID,
+			"channel_id", channel.ID,
+			"error", err)
+		return nil
+	}
+
+	var alternates []relay.Upstream
+	for _, alt := range recorded {
+		match, err := s.channelRepo.GetByID(ctx, alt.AlternateChannelID)
+		if err != nil || match == nil || match.StreamURL == "" || match.StreamURL == channel.StreamURL {
+			continue
+		}
+		source, ok := sources[match.SourceID]
+		if !ok {
+			continue
+		}
+		if upstream, ok := s.sourceUpstream(ctx, source, match); ok {
+			alternates = append(alternates, upstream)
+		}
+	}
+	return alternates
+}
+
+// sourceUpstream builds a relay upstream for a channel of a stream source. It
+// returns false if the channel's stream URL cannot be resolved.
+func (s *RelayService) sourceUpstream(ctx context.Context, source *models.StreamSource, channel *models.Channel) (relay.Upstream, bool) {
+	streamURL, err := s.resolveStreamURL(ctx, source, channel)
+	if err != nil {
+		return relay.Upstream{}, false
+	}
+	withSource := *channel
+	withSource.Source = source
+	withSource.StreamURL = streamURL
+	return channelUpstream(&withSource), true
+}
+
+// channelUpstream builds the relay upstream for a channel with a playable stream
+// URL. The accounts of an Xtream source with extra accounts are pooled, each
+// with the stream URL rewritten to its credentials.
+func channelUpstream(channel *models.Channel) relay.Upstream {
+	up := relay.Upstream{
+		UpstreamOptions: ChannelUpstreamOptions(channel),
+		StreamURL:       channel.StreamURL,
+	}
+	source := channel.Source
+	if source == nil {
+		return up
+	}
+	up.SourceID = source.ID
+	up.SourceName = source.Name
+	up.MaxConcurrentStreams = source.ConnectionLimit()
+	up.LimitPolicy = source.ConnectionLimitPolicy
+
+	for _, account := range source.Accounts() {
+		accountURL, ok := xtream.ReplaceCredentials(channel.StreamURL, source.Username, source.Password, account.Username, account.Password)
+		if !ok {
+			// Not a URL of this account's server; connect as ingested
+			up.Accounts = nil
+			break
+		}
+		up.Accounts = append(up.Accounts, relay.UpstreamAccount{
+			Name:                 account.Username,
+			StreamURL:            accountURL,
+			MaxConcurrentStreams: account.MaxConcurrentStreams,
+		})
+	}
+	return up
+}
+
+// ChannelUpstreamOptions returns the options for requests to a channel's stream:
+// the User-Agent and proxy of its source, and the channel's request headers.
+func ChannelUpstreamOptions(channel *models.Channel) relay.UpstreamOptions {
+	opts := relay.UpstreamOptions{Headers: channel.RequestHeaders()}
+	if channel.Source != nil {
+		opts.Source = channel.Source.Name
+		opts.UserAgent = channel.Source.UserAgent
+		opts.ProxyURL = channel.Source.ProxyURL
+	}
+	return opts
+}
+
 // logSessionStart logs session start with detailed profile information
 func (s *RelayService) logSessionStart(session *relay.RelaySession, channelID models.ULID, streamURL string, profile *models.EncodingProfile) {
 	attrs := []any{
@@ -409,11 +638,44 @@ func (s *RelayService) HasSessionForChannel(channelID models.ULID) bool {
 	return s.GetSessionForChannel(channelID) != nil
 }
 
+// CountActiveSessionsForSource returns the number of active relay sessions
+// ingesting from a stream source.
+func (s *RelayService) CountActiveSessionsForSource(sourceID models.ULID) int {
+	return s.relayManager.CountActiveSessionsForSource(sourceID)
+}
+
+// CountActiveSessionsForAccount returns the number of active relay sessions
+// connected with one account of a stream source whose accounts are pooled.
+func (s *RelayService) CountActiveSessionsForAccount(sourceID models.ULID, account string) int {
+	return s.relayManager.CountActiveSessionsForAccount(sourceID, account)
+}
+
+// SetExternalConnections records the upstream connections of a stream source
+// in use outside tvarr, which count against the source's connection limit.
+func (s *RelayService) SetExternalConnections(sourceID models.ULID, count int) {
+	s.relayManager.SetExternalConnections(sourceID, count)
+}
+
 // GetRelayStats returns relay manager statistics.
 func (s *RelayService) GetRelayStats() relay.ManagerStats {
 	return s.relayManager.Stats()
 }
 
+// RegisterMetrics registers the relay manager's metrics with r.
+func (s *RelayService) RegisterMetrics(r *observability.MetricsRegistry) {
+	s.relayManager.RegisterMetrics(r)
+}
+
+// OnCircuitStateChange sets a function called when an upstream circuit breaker changes state.
+func (s *RelayService) OnCircuitStateChange(fn func(streamURL string, from, to relay.CircuitState)) {
+	s.relayManager.OnCircuitStateChange(fn)
+}
+
+// OnViewingEnded sets a function called when a client leaves a relay session.
+func (s *RelayService) OnViewingEnded(fn relay.ViewingFunc) {
+	s.relayManager.OnViewingEnded(fn)
+}
+
 // GetFFmpegInfo returns information about the detected FFmpeg installation.
 func (s *RelayService) GetFFmpegInfo(ctx context.Context) (*ffmpeg.BinaryInfo, error) {
 	return s.ffmpegDetector.Detect(ctx)
@@ -422,31 +684,37 @@ func (s *RelayService) GetFFmpegInfo(ctx context.Context) (*ffmpeg.BinaryInfo, e
 // ClassificationResult is an alias for relay.ClassificationResult for external use.
 type ClassificationResult = relay.ClassificationResult
 
-// ClassifyStream classifies a stream URL.
-func (s *RelayService) ClassifyStream(ctx context.Context, streamURL string) ClassificationResult {
-	classifier := relay.NewStreamClassifier(s.GetHTTPClient())
+// ClassifyStream classifies a stream URL with the given upstream options.
+func (s *RelayService) ClassifyStream(ctx context.Context, streamURL string, opts relay.UpstreamOptions) ClassificationResult {
+	classifier := relay.NewStreamClassifier(s.GetHTTPClient(opts))
 	return classifier.Classify(ctx, streamURL)
 }
 
-// CreateHLSCollapser creates an HLS collapser for the given playlist URL.
-func (s *RelayService) CreateHLSCollapser(playlistURL string) *relay.HLSCollapser {
-	return relay.NewHLSCollapser(s.GetHTTPClient(), playlistURL)
+// CreateHLSCollapser creates an HLS collapser for the given playlist URL with
+// the given upstream options.
+func (s *RelayService) CreateHLSCollapser(playlistURL string, opts relay.UpstreamOptions) *relay.HLSCollapser {
+	return relay.NewHLSCollapser(s.GetHTTPClient(opts), playlistURL)
 }
 
-// GetHTTPClient returns the HTTP client used by the relay manager.
-func (s *RelayService) GetHTTPClient() *http.Client {
+// GetHTTPClient returns the relay manager's HTTP client for upstream requests,
+// connecting through the proxy and sending the request headers of opts.
+func (s *RelayService) GetHTTPClient(opts relay.UpstreamOptions) *http.Client {
 	if s.relayManager != nil {
-		return s.relayManager.HTTPClient()
+		return s.relayManager.UpstreamClient(opts)
+	}
+	client := http.DefaultClient
+	if opts.ProxyURL != "" {
+		client = &http.Client{Transport: httpclient.NewProxyTransport(nil, opts.ProxyURL)}
 	}
-	return http.DefaultClient
+	return httpclient.WithHeaders(client, opts.RequestHeaders())
 }
 
 // ProbeAndStoreCodecInfo always probes the stream fresh and stores the result.
 // The stored result is used by the channel UI to display codec information.
 // Returns nil if probing is not available or fails (non-fatal).
-func (s *RelayService) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string) *models.LastKnownCodec {
+func (s *RelayService) ProbeAndStoreCodecInfo(ctx context.Context, streamURL string, opts relay.UpstreamOptions) *models.LastKnownCodec {
 	if s.relayManager != nil {
-		return s.relayManager.ProbeAndStoreCodecInfo(ctx, streamURL)
+		return s.relayManager.ProbeAndStoreCodecInfo(ctx, streamURL, opts)
 	}
 	return nil
 }
@@ -458,11 +726,11 @@ func (s *RelayService) ProbeAndStoreCodecInfo(ctx context.Context, streamURL str
 //
 // This is the preferred method for getting codec info before stream delivery,
 // as it avoids consuming extra connections when a stream is already active.
-func (s *RelayService) GetOrProbeCodecInfo(ctx context.Context, channelID models.ULID, streamURL string) *models.LastKnownCodec {
+func (s *RelayService) GetOrProbeCodecInfo(ctx context.Context, channelID models.ULID, streamURL string, opts relay.UpstreamOptions) *models.LastKnownCodec {
 	if s.relayManager == nil {
 		return nil
 	}
-	return s.relayManager.GetOrProbeCodecInfo(ctx, channelID, streamURL)
+	return s.relayManager.GetOrProbeCodecInfo(ctx, channelID, streamURL, opts)
 }
 
 // StreamInfo contains the information needed to stream a channel through a proxy.
@@ -470,11 +738,36 @@ type StreamInfo struct {
 	Proxy           *models.StreamProxy
 	Channel         *models.Channel
 	EncodingProfile *models.EncodingProfile
+	// Viewer is the authenticated viewer, when stream authentication is enabled.
+	Viewer *models.Viewer
+}
+
+// AuthenticatePreview checks the credential on a channel preview URL. Previews
+// are not tied to a proxy, so when a stream authenticator is configured the
+// credential must be a viewer token; signed stream tokens are rejected.
+func (s *RelayService) AuthenticatePreview(ctx context.Context, credential string) error {
+	if s.streamAuthenticator == nil {
+		return nil
+	}
+	_, err := s.streamAuthenticator.AuthenticateViewer(ctx, credential)
+	return err
 }
 
 // GetStreamInfo retrieves the proxy, channel, and optional relay profile for streaming.
 // This is used by the stream handler to determine the delivery mode (redirect/proxy/relay).
-func (s *RelayService) GetStreamInfo(ctx context.Context, proxyID, channelID models.ULID) (*StreamInfo, error) {
+// When a stream authenticator is configured, credential must be a valid viewer token or
+// signed stream token for the channel, otherwise ErrStreamUnauthorized is returned.
+func (s *RelayService) GetStreamInfo(ctx context.Context, proxyID, channelID models.ULID, credential string) (*StreamInfo, error) {
+	// Authenticate before any lookups so unauthenticated callers cannot probe IDs.
+	var viewer *models.Viewer
+	if s.streamAuthenticator != nil {
+		var err error
+		viewer, err = s.streamAuthenticator.AuthenticateStream(ctx, credential, proxyID, channelID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get the stream proxy
 	proxy, err := s.streamProxyRepo.GetByID(ctx, proxyID)
 	if err != nil {
@@ -485,17 +778,15 @@ func (s *RelayService) GetStreamInfo(ctx context.Context, proxyID, channelID mod
 	}
 
 	// Get the channel
-	channel, err := s.channelRepo.GetByID(ctx, channelID)
+	channel, err := s.getPlayableChannel(ctx, channelID)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrChannelNotFound, err)
-	}
-	if channel == nil {
-		return nil, ErrChannelNotFound
+		return nil, err
 	}
 
 	info := &StreamInfo{
 		Proxy:   proxy,
 		Channel: channel,
+		Viewer:  viewer,
 	}
 
 	// Load profile for smart mode which needs it to determine if transcoding is required
@@ -518,6 +809,35 @@ func (s *RelayService) GetStreamInfo(ctx context.Context, proxyID, channelID mod
 	return info, nil
 }
 
+// CatchupURL returns the upstream archive URL for a programme on a channel that
+// starts at start and lasts duration. The programme must have started within the
+// channel's catch-up window.
+func (s *RelayService) CatchupURL(channel *models.Channel, start time.Time, duration time.Duration, now time.Time) (string, error) {
+	if !channel.HasCatchup() {
+		return "", ErrCatchupUnavailable
+	}
+	if duration <= 0 || duration > maxCatchupDuration {
+		return "", fmt.Errorf("%w: invalid duration %s", ErrCatchupOutOfRange, duration)
+	}
+	windowStart := now.Add(-time.Duration(channel.CatchupDays) * 24 * time.Hour)
+	if start.Before(windowStart) || !start.Before(now) {
+		return "", ErrCatchupOutOfRange
+	}
+
+	loc := time.UTC
+	if channel.CatchupTimezone != "" {
+		if l, err := time.LoadLocation(channel.CatchupTimezone); err == nil {
+			loc = l
+		} else {
+			s.logger.Warn("invalid catch-up timezone, using UTC",
+				"channel_id", channel.ID,
+				"timezone", channel.CatchupTimezone,
+			)
+		}
+	}
+	return m3u.ExpandCatchupSource(channel.CatchupSource, start, duration, loc), nil
+}
+
 // GetProxy returns a stream proxy by ID.
 func (s *RelayService) GetProxy(ctx context.Context, id models.ULID) (*models.StreamProxy, error) {
 	proxy, err := s.streamProxyRepo.GetByID(ctx, id)
@@ -530,18 +850,47 @@ func (s *RelayService) GetProxy(ctx context.Context, id models.ULID) (*models.St
 	return proxy, nil
 }
 
-// GetChannel returns a channel by ID.
+// GetChannel returns a channel by ID, with a playable stream URL.
 func (s *RelayService) GetChannel(ctx context.Context, id models.ULID) (*models.Channel, error) {
-	channel, err := s.channelRepo.GetByID(ctx, id)
+	return s.getPlayableChannel(ctx, id)
+}
+
+// getPlayableChannel returns a channel by ID with its source preloaded and its
+// stream URL resolved for playback.
+func (s *RelayService) getPlayableChannel(ctx context.Context, id models.ULID) (*models.Channel, error) {
+	channel, err := s.channelRepo.GetByIDWithSource(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrChannelNotFound, err)
 	}
 	if channel == nil {
 		return nil, ErrChannelNotFound
 	}
+	if channel.Source != nil {
+		streamURL, err := s.resolveStreamURL(ctx, channel.Source, channel)
+		if err != nil {
+			return nil, err
+		}
+		channel.StreamURL = streamURL
+	}
 	return channel, nil
 }
 
+// resolveStreamURL returns the playable stream URL of a channel of a source.
+func (s *RelayService) resolveStreamURL(ctx context.Context, source *models.StreamSource, channel *models.Channel) (string, error) {
+	if s.streamResolver == nil {
+		return channel.StreamURL, nil
+	}
+	streamURL, err := s.streamResolver.ResolveStreamURL(ctx, source, channel.StreamURL)
+	if err != nil {
+		s.logger.Warn("failed to resolve stream URL",
+			"channel_id", channel.ID,
+			"source_id", source.ID,
+			"error", err)
+		return "", fmt.Errorf("%w: %v", ErrStreamUnavailable, err)
+	}
+	return streamURL, nil
+}
+
 // GetHardwareCapabilities returns the cached hardware capabilities, detecting if not already cached.
 func (s *RelayService) GetHardwareCapabilities(ctx context.Context) (*services.HardwareCapabilities, error) {
 	if s.hardwareDetector == nil {
@@ -1,7 +1,11 @@
 package service_test
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/glebarez/sqlite"
 	"github.com/jmylchreest/tvarr/internal/models"
@@ -15,6 +19,12 @@ import (
 
 func setupRelayServiceTest(t *testing.T) *service.RelayService {
 	t.Helper()
+	svc, _ := setupRelayServiceTestDB(t)
+	return svc
+}
+
+func setupRelayServiceTestDB(t *testing.T) (*service.RelayService, *gorm.DB) {
+	t.Helper()
 
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
@@ -37,7 +47,7 @@ func setupRelayServiceTest(t *testing.T) *service.RelayService {
 	streamProxyRepo := repository.NewStreamProxyRepository(db)
 
 	svc := service.NewRelayService(encodingProfileRepo, lastKnownCodecRepo, channelRepo, streamProxyRepo)
-	return svc
+	return svc, db
 }
 
 func TestRelayService_RelayStats(t *testing.T) {
@@ -58,3 +68,97 @@ func TestRelayService_Close(t *testing.T) {
 		svc.Close() // Should not panic
 	})
 }
+
+func TestRelayService_CatchupURL(t *testing.T) {
+	svc := setupRelayServiceTest(t)
+	defer svc.Close()
+
+	now := time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)
+	channel := &models.Channel{
+		CatchupDays:   7,
+		CatchupSource: "http://upstream/timeshift.php?stream=1&start={Y}-{m}-{d}:{H}-{M}&duration={duration:60}",
+	}
+
+	t.Run("expands template", func(t *testing.T) {
+		got, err := svc.CatchupURL(channel, now.Add(-2*time.Hour), time.Hour, now)
+		require.NoError(t, err)
+		assert.Equal(t, "http://upstream/timeshift.php?stream=1&start=2024-06-08:10-00&duration=60", got)
+	})
+
+	t.Run("uses channel timezone", func(t *testing.T) {
+		if _, err := time.LoadLocation("Europe/London"); err != nil {
+			t.Skip("timezone data unavailable")
+		}
+		withZone := *channel
+		withZone.CatchupTimezone = "Europe/London"
+		got, err := svc.CatchupURL(&withZone, now.Add(-2*time.Hour), time.Hour, now)
+		require.NoError(t, err)
+		assert.Contains(t, got, "start=2024-06-08:11-00")
+	})
+
+	t.Run("rejects programmes outside the window", func(t *testing.T) {
+		_, err := svc.CatchupURL(channel, now.Add(-8*24*time.Hour), time.Hour, now)
+		assert.ErrorIs(t, err, service.ErrCatchupOutOfRange)
+
+		_, err = svc.CatchupURL(channel, now.Add(time.Hour), time.Hour, now)
+		assert.ErrorIs(t, err, service.ErrCatchupOutOfRange)
+
+		_, err = svc.CatchupURL(channel, now.Add(-time.Hour), 0, now)
+		assert.ErrorIs(t, err, service.ErrCatchupOutOfRange)
+	})
+
+	t.Run("rejects channels without archive", func(t *testing.T) {
+		_, err := svc.CatchupURL(&models.Channel{}, now.Add(-time.Hour), time.Hour, now)
+		assert.ErrorIs(t, err, service.ErrCatchupUnavailable)
+	})
+}
+
+// prefixResolver resolves stream URLs of stalker sources by prefixing them.
+type prefixResolver struct {
+	err error
+}
+
+func (r *prefixResolver) ResolveStreamURL(_ context.Context, source *models.StreamSource, streamURL string) (string, error) {
+	if source.Type != models.SourceTypeStalker {
+		return streamURL, nil
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+	return "http://cdn.example.com/" + strings.TrimPrefix(streamURL, "ffrt "), nil
+}
+
+func TestRelayService_GetChannelResolvesStreamURL(t *testing.T) {
+	svc, db := setupRelayServiceTestDB(t)
+	defer svc.Close()
+	ctx := context.Background()
+
+	source := &models.StreamSource{
+		Name:       "portal",
+		Type:       models.SourceTypeStalker,
+		URL:        "http://portal.example.com/c/",
+		MacAddress: "00:1A:79:00:00:01",
+	}
+	require.NoError(t, db.Create(source).Error)
+	channel := &models.Channel{SourceID: source.ID, ChannelName: "News", StreamURL: "ffrt ch/10"}
+	require.NoError(t, db.Create(channel).Error)
+
+	t.Run("stored URL without resolver", func(t *testing.T) {
+		got, err := svc.GetChannel(ctx, channel.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "ffrt ch/10", got.StreamURL)
+	})
+
+	t.Run("resolved URL with resolver", func(t *testing.T) {
+		svc.WithStreamURLResolver(&prefixResolver{})
+		got, err := svc.GetChannel(ctx, channel.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "http://cdn.example.com/ch/10", got.StreamURL)
+	})
+
+	t.Run("resolution failure", func(t *testing.T) {
+		svc.WithStreamURLResolver(&prefixResolver{err: errors.New("portal down")})
+		_, err := svc.GetChannel(ctx, channel.ID)
+		assert.ErrorIs(t, err, service.ErrStreamUnavailable)
+	})
+}
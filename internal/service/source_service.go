@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -11,9 +12,13 @@ import (
 
 	"github.com/jmylchreest/tvarr/internal/ingestor"
 	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/observability"
 	"github.com/jmylchreest/tvarr/internal/repository"
 	"github.com/jmylchreest/tvarr/internal/service/progress"
+	"github.com/jmylchreest/tvarr/pkg/httpclient"
 	"github.com/jmylchreest/tvarr/pkg/xtream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Global type-level write mutexes to prevent concurrent writes to the same table.
@@ -33,8 +38,9 @@ var (
 
 // EPGChecker checks EPG availability for Xtream sources.
 type EPGChecker interface {
-	// CheckEPGAvailability checks if an Xtream server provides EPG data.
-	CheckEPGAvailability(ctx context.Context, baseURL, username, password string) (bool, error)
+	// CheckEPGAvailability checks if an Xtream server provides EPG data,
+	// connecting through proxyURL when it is non-empty.
+	CheckEPGAvailability(ctx context.Context, baseURL, username, password, proxyURL string) (bool, error)
 }
 
 // DefaultEPGChecker implements EPGChecker using an HTTP HEAD request.
@@ -53,8 +59,16 @@ func NewDefaultEPGChecker() *DefaultEPGChecker {
 
 // CheckEPGAvailability checks if an Xtream server provides EPG data.
 // Tries HEAD first (lightweight), falls back to GET if the server doesn't support HEAD.
-func (c *DefaultEPGChecker) CheckEPGAvailability(ctx context.Context, baseURL, username, password string) (bool, error) {
-	client := xtream.NewClient(baseURL, username, password, xtream.WithHTTPClient(c.httpClient))
+func (c *DefaultEPGChecker) CheckEPGAvailability(ctx context.Context, baseURL, username, password, proxyURL string) (bool, error) {
+	httpClient := c.httpClient
+	if proxyURL != "" {
+		httpClient = &http.Client{
+			Timeout:   c.httpClient.Timeout,
+			Transport: httpclient.NewProxyTransport(nil, proxyURL),
+		}
+	}
+
+	client := xtream.NewClient(baseURL, username, password, xtream.WithHTTPClient(httpClient))
 	xmltvURL := client.GetXMLTVURL()
 
 	// Try HEAD first (many XC servers behind Cloudflare reject HEAD with 520)
@@ -63,7 +77,7 @@ func (c *DefaultEPGChecker) CheckEPGAvailability(ctx context.Context, baseURL, u
 		return false, fmt.Errorf("creating request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err == nil {
 		_ = resp.Body.Close()
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
@@ -81,7 +95,7 @@ func (c *DefaultEPGChecker) CheckEPGAvailability(ctx context.Context, baseURL, u
 	}
 	req.Header.Set("Range", "bytes=0-0")
 
-	resp, err = c.httpClient.Do(req)
+	resp, err = httpClient.Do(req)
 	if err != nil {
 		return false, fmt.Errorf("checking EPG availability: %w", err)
 	}
@@ -96,6 +110,8 @@ type SourceService struct {
 	sourceRepo      repository.StreamSourceRepository
 	channelRepo     repository.ChannelRepository
 	epgSourceRepo   repository.EpgSourceRepository
+	vodRepo         repository.VodRepository
+	seriesRepo      repository.SeriesRepository
 	factory         *ingestor.HandlerFactory
 	stateManager    *ingestor.StateManager
 	progressService *progress.Service
@@ -144,6 +160,13 @@ func (s *SourceService) WithEPGChecker(checker EPGChecker) *SourceService {
 	return s
 }
 
+// WithVodRepos sets the movie and series repositories for VOD catalogue ingestion.
+func (s *SourceService) WithVodRepos(vodRepo repository.VodRepository, seriesRepo repository.SeriesRepository) *SourceService {
+	s.vodRepo = vodRepo
+	s.seriesRepo = seriesRepo
+	return s
+}
+
 // getIngestionStages returns the standard stages for stream source ingestion.
 // Stream ingestion uses 3 stages:
 // - connect: Delete existing channels and prepare for ingestion
@@ -209,7 +232,7 @@ func (s *SourceService) tryAutoCreateEPGSource(ctx context.Context, streamSource
 	}
 
 	// Check EPG availability
-	available, err := s.epgChecker.CheckEPGAvailability(ctx, streamSource.URL, streamSource.Username, streamSource.Password)
+	available, err := s.epgChecker.CheckEPGAvailability(ctx, streamSource.URL, streamSource.Username, streamSource.Password, streamSource.ProxyURL)
 	if err != nil {
 		s.logger.Warn("failed to check EPG availability",
 			"stream_source_id", streamSource.ID.String(),
@@ -232,6 +255,7 @@ func (s *SourceService) tryAutoCreateEPGSource(ctx context.Context, streamSource
 		Username:  streamSource.Username,
 		Password:  streamSource.Password,
 		UserAgent: streamSource.UserAgent,
+		ProxyURL:  streamSource.ProxyURL,
 		Enabled:   streamSource.Enabled,
 		Priority:  streamSource.Priority,
 	}
@@ -257,6 +281,9 @@ func (s *SourceService) Update(ctx context.Context, source *models.StreamSource)
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	// The next ingestion processes the content in full with the new settings.
+	source.ClearContentVersion()
+
 	if err := s.sourceRepo.Update(ctx, source); err != nil {
 		return fmt.Errorf("updating source: %w", err)
 	}
@@ -269,13 +296,23 @@ func (s *SourceService) Update(ctx context.Context, source *models.StreamSource)
 	return nil
 }
 
-// Delete deletes a stream source and all its channels.
+// Delete deletes a stream source with all its channels and VOD content.
 func (s *SourceService) Delete(ctx context.Context, id models.ULID) error {
 	// First delete all channels for this source
 	if err := s.channelRepo.DeleteBySourceID(ctx, id); err != nil {
 		return fmt.Errorf("deleting channels: %w", err)
 	}
 
+	// Delete the VOD catalogue if enabled
+	if s.vodRepo != nil && s.seriesRepo != nil {
+		if err := s.vodRepo.DeleteBySourceID(ctx, id); err != nil {
+			return fmt.Errorf("deleting movies: %w", err)
+		}
+		if err := s.seriesRepo.DeleteBySourceID(ctx, id); err != nil {
+			return fmt.Errorf("deleting series: %w", err)
+		}
+	}
+
 	// Then delete the source
 	if err := s.sourceRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("deleting source: %w", err)
@@ -322,8 +359,19 @@ func (s *SourceService) ListEnabled(ctx context.Context) ([]*models.StreamSource
 	return sources, nil
 }
 
-// Ingest triggers ingestion for a stream source.
+// Ingest triggers ingestion for a stream source. It returns
+// ingestor.ErrSourceUnchanged, without writing any channels, when the source
+// content is unchanged since the last ingestion.
 func (s *SourceService) Ingest(ctx context.Context, id models.ULID) error {
+	ctx, span := observability.StartSpan(ctx, "ingest.stream_source",
+		attribute.String("tvarr.source.id", id.String()))
+	err := s.ingest(ctx, id)
+	endIngestSpan(span, err)
+	return err
+}
+
+// ingest performs the ingestion of Ingest.
+func (s *SourceService) ingest(ctx context.Context, id models.ULID) error {
 	// Get the source
 	source, err := s.sourceRepo.GetByID(ctx, id)
 	if err != nil {
@@ -409,11 +457,15 @@ func (s *SourceService) Ingest(ctx context.Context, id models.ULID) error {
 	}
 
 	// Perform ingestion - download and parse happens here (no DB transaction held)
-	if err := handler.Ingest(ctx, source, func(channel *models.Channel) error {
+	if err := s.fetchChannels(ctx, handler, source, func(channel *models.Channel) error {
 		allChannels = append(allChannels, channel)
 		channelCount++
 		return nil
 	}); err != nil {
+		if errors.Is(err, ingestor.ErrSourceUnchanged) {
+			s.completeUnchanged(ctx, source, progressMgr)
+			return err
+		}
 		if progressMgr != nil {
 			progressMgr.Fail(err)
 		}
@@ -423,6 +475,9 @@ func (s *SourceService) Ingest(ctx context.Context, id models.ULID) error {
 		return fmt.Errorf("ingesting channels: %w", err)
 	}
 
+	// Refresh the VOD catalogue before taking the write lock for channels
+	s.ingestVod(ctx, source, handler)
+
 	// Stage 3: Finalize - batch insert and cleanup (with shorter transactions)
 	if progressMgr != nil && downloadStage != nil {
 		downloadStage.Complete()
@@ -474,6 +529,7 @@ func (s *SourceService) Ingest(ctx context.Context, id models.ULID) error {
 
 	// Mark success
 	source.MarkSuccess(channelCount)
+	observability.IngestedRows.Add(float64(channelCount), "channels", string(source.Type))
 	if err := s.sourceRepo.Update(ctx, source); err != nil {
 		s.logger.Error("failed to update source status",
 			"source_id", id.String(),
@@ -501,6 +557,48 @@ func (s *SourceService) Ingest(ctx context.Context, id models.ULID) error {
 	return nil
 }
 
+// fetchChannels runs the source's handler, which downloads and parses the
+// source and yields its channels to callback.
+func (s *SourceService) fetchChannels(ctx context.Context, handler ingestor.SourceHandler, source *models.StreamSource, callback ingestor.ChannelCallback) error {
+	ctx, span := observability.StartSpan(ctx, "ingest.fetch",
+		attribute.String("tvarr.source.type", string(source.Type)))
+	err := handler.Ingest(ctx, source, callback)
+	endIngestSpan(span, err)
+	return err
+}
+
+// endIngestSpan ends an ingestion span. Unchanged sources are not failures.
+func endIngestSpan(span trace.Span, err error) {
+	if errors.Is(err, ingestor.ErrSourceUnchanged) {
+		span.SetAttributes(attribute.Bool("tvarr.ingest.unchanged", true))
+		err = nil
+	}
+	observability.EndSpan(span, err)
+}
+
+// completeUnchanged records an ingestion that found the source content unchanged
+// since the last one. The stored channels are kept as they are.
+func (s *SourceService) completeUnchanged(ctx context.Context, source *models.StreamSource, progressMgr *progress.OperationManager) {
+	source.MarkSuccess(source.ChannelCount)
+	if err := s.sourceRepo.Update(ctx, source); err != nil {
+		s.logger.Error("failed to update source status",
+			"source_id", source.ID.String(),
+			"error", err,
+		)
+	}
+
+	s.stateManager.Complete(source.ID, source.ChannelCount)
+
+	if progressMgr != nil {
+		progressMgr.Complete(fmt.Sprintf("%s is unchanged", source.Name))
+	}
+
+	s.logger.Info("source unchanged, skipped ingestion",
+		"source_id", source.ID.String(),
+		"source_name", source.Name,
+	)
+}
+
 // IngestAsync triggers ingestion asynchronously.
 func (s *SourceService) IngestAsync(ctx context.Context, id models.ULID) error {
 	// Verify source exists
@@ -532,8 +630,8 @@ func (s *SourceService) IngestAsync(ctx context.Context, id models.ULID) error {
 		// Ensure we release the lock when done
 		defer s.ingestionLocks.Delete(id)
 
-		// Create a new context that isn't tied to the request
-		bgCtx := context.Background()
+		// Create a new context that isn't tied to the request, continuing its trace
+		bgCtx := observability.DetachedContext(ctx)
 
 		// Perform the actual ingestion (state already started)
 		s.performIngestion(bgCtx, source)
@@ -547,6 +645,10 @@ func (s *SourceService) IngestAsync(ctx context.Context, id models.ULID) error {
 func (s *SourceService) performIngestion(ctx context.Context, source *models.StreamSource) {
 	id := source.ID
 
+	ctx, span := observability.StartSpan(ctx, "ingest.stream_source",
+		attribute.String("tvarr.source.id", id.String()))
+	defer span.End()
+
 	// Get the appropriate handler
 	handler, err := s.factory.GetForSource(source)
 	if err != nil {
@@ -619,7 +721,7 @@ func (s *SourceService) performIngestion(ctx context.Context, source *models.Str
 		var batchChannels []*models.Channel
 
 		// Perform ingestion with callback - download, parse, and batch-insert channels
-		if err := handler.Ingest(ctx, source, func(channel *models.Channel) error {
+		if err := s.fetchChannels(ctx, handler, source, func(channel *models.Channel) error {
 			batchChannels = append(batchChannels, channel)
 			channelCount++
 
@@ -671,6 +773,10 @@ func (s *SourceService) performIngestion(ctx context.Context, source *models.Str
 		return nil
 	})
 
+	if errors.Is(err, ingestor.ErrSourceUnchanged) {
+		s.completeUnchanged(ctx, source, progressMgr)
+		return
+	}
 	if err != nil {
 		if progressMgr != nil {
 			progressMgr.Fail(err)
@@ -685,7 +791,10 @@ func (s *SourceService) performIngestion(ctx context.Context, source *models.Str
 		return
 	}
 
+	s.ingestVod(ctx, source, handler)
+
 	source.MarkSuccess(channelCount)
+	observability.IngestedRows.Add(float64(channelCount), "channels", string(source.Type))
 	_ = s.sourceRepo.Update(ctx, source)
 	s.stateManager.Complete(id, channelCount)
 
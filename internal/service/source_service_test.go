@@ -89,6 +89,10 @@ func (r *mockStreamSourceRepo) UpdateLastIngestion(ctx context.Context, id model
 	return nil
 }
 
+func (r *mockStreamSourceRepo) UpdateAccountStatus(ctx context.Context, source *models.StreamSource) error {
+	return nil
+}
+
 // mockChannelRepo is a mock implementation of ChannelRepository
 type mockChannelRepo struct {
 	channels    map[models.ULID]*models.Channel
@@ -250,6 +254,16 @@ func (r *mockChannelRepo) Transaction(ctx context.Context, fn func(repository.Ch
 	return fn(r)
 }
 
+func (r *mockChannelRepo) GetByTvgID(ctx context.Context, tvgID string) ([]*models.Channel, error) {
+	var result []*models.Channel
+	for _, ch := range r.channels {
+		if ch.TvgID == tvgID {
+			result = append(result, ch)
+		}
+	}
+	return result, nil
+}
+
 func (r *mockChannelRepo) GetDistinctFieldValues(ctx context.Context, field string, query string, limit int) ([]repository.FieldValueResult, error) {
 	// Mock implementation - return empty results
 	return []repository.FieldValueResult{}, nil
@@ -476,7 +490,7 @@ type mockEPGChecker struct {
 	err       error
 }
 
-func (m *mockEPGChecker) CheckEPGAvailability(ctx context.Context, baseURL, username, password string) (bool, error) {
+func (m *mockEPGChecker) CheckEPGAvailability(ctx context.Context, baseURL, username, password, proxyURL string) (bool, error) {
 	return m.available, m.err
 }
 
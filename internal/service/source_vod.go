@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/ingestor"
+	"github.com/jmylchreest/tvarr/internal/models"
+)
+
+// vodBatchSize is the number of movies or series written per upsert.
+const vodBatchSize = 1000
+
+// ingestVod refreshes the movie and series catalogues of a source whose handler
+// supports them, and removes catalogues the source no longer ingests. Failures
+// are logged and leave the previous catalogue in place; they do not fail the
+// channel ingestion. The counts are saved with the source by the caller.
+func (s *SourceService) ingestVod(ctx context.Context, source *models.StreamSource, handler ingestor.SourceHandler) {
+	if s.vodRepo == nil || s.seriesRepo == nil {
+		return
+	}
+	vodHandler, supported := handler.(ingestor.VodHandler)
+
+	if source.IngestVod && supported {
+		count, err := s.ingestMovies(ctx, source, vodHandler)
+		if err != nil {
+			s.logger.Warn("movie ingestion failed",
+				"source_id", source.ID.String(),
+				"error", err,
+			)
+		} else {
+			source.VodCount = count
+		}
+	} else if source.VodCount > 0 {
+		if err := s.vodRepo.DeleteBySourceID(ctx, source.ID); err != nil {
+			s.logger.Warn("failed to delete movies", "source_id", source.ID.String(), "error", err)
+		} else {
+			source.VodCount = 0
+		}
+	}
+
+	if source.IngestSeries && supported {
+		count, err := s.ingestSeries(ctx, source, vodHandler)
+		if err != nil {
+			s.logger.Warn("series ingestion failed",
+				"source_id", source.ID.String(),
+				"error", err,
+			)
+		} else {
+			source.SeriesCount = count
+		}
+	} else if source.SeriesCount > 0 {
+		if err := s.seriesRepo.DeleteBySourceID(ctx, source.ID); err != nil {
+			s.logger.Warn("failed to delete series", "source_id", source.ID.String(), "error", err)
+		} else {
+			source.SeriesCount = 0
+		}
+	}
+}
+
+// ingestMovies downloads the movie catalogue of a source and replaces the
+// stored one, returning the number of movies.
+func (s *SourceService) ingestMovies(ctx context.Context, source *models.StreamSource, handler ingestor.VodHandler) (int, error) {
+	startTime := time.Now()
+
+	var items []*models.VodItem
+	if err := handler.IngestMovies(ctx, source, func(item *models.VodItem) error {
+		items = append(items, item)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	streamWriteMutex.Lock()
+	defer streamWriteMutex.Unlock()
+
+	for i := 0; i < len(items); i += vodBatchSize {
+		end := min(i+vodBatchSize, len(items))
+		if err := s.vodRepo.UpsertBatch(ctx, items[i:end]); err != nil {
+			return 0, fmt.Errorf("batch insert: %w", err)
+		}
+	}
+
+	staleCount, err := s.vodRepo.DeleteStaleBySourceID(ctx, source.ID, startTime)
+	if err != nil {
+		return 0, fmt.Errorf("cleaning up stale movies: %w", err)
+	}
+
+	s.logger.Info("ingested movies",
+		"source_id", source.ID.String(),
+		"count", len(items),
+		"removed", staleCount,
+	)
+	return len(items), nil
+}
+
+// ingestSeries downloads the series catalogue of a source and replaces the
+// stored one, returning the number of series. Episodes are only fetched for
+// series that are new or have changed since the last ingestion.
+func (s *SourceService) ingestSeries(ctx context.Context, source *models.StreamSource, handler ingestor.VodHandler) (int, error) {
+	startTime := time.Now()
+
+	stored, err := s.seriesRepo.GetLastModifiedBySourceID(ctx, source.ID)
+	if err != nil {
+		return 0, err
+	}
+	needEpisodes := func(series *models.Series) bool {
+		lastModified, ok := stored[series.ExtID]
+		return !ok || lastModified == 0 || lastModified != series.LastModified
+	}
+
+	var series []*models.Series
+	if err := handler.IngestSeries(ctx, source, needEpisodes, func(item *models.Series) error {
+		series = append(series, item)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	streamWriteMutex.Lock()
+	defer streamWriteMutex.Unlock()
+
+	for i := 0; i < len(series); i += vodBatchSize {
+		end := min(i+vodBatchSize, len(series))
+		if err := s.seriesRepo.UpsertBatch(ctx, series[i:end]); err != nil {
+			return 0, fmt.Errorf("batch insert: %w", err)
+		}
+	}
+
+	staleCount, err := s.seriesRepo.DeleteStaleBySourceID(ctx, source.ID, startTime)
+	if err != nil {
+		return 0, fmt.Errorf("cleaning up stale series: %w", err)
+	}
+
+	s.logger.Info("ingested series",
+		"source_id", source.ID.String(),
+		"count", len(series),
+		"removed", staleCount,
+	)
+	return len(series), nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/auth"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+)
+
+// Service-level errors for viewers.
+var (
+	// ErrViewerNotFound is returned when a viewer is not found.
+	ErrViewerNotFound = errors.New("viewer not found")
+
+	// ErrViewerExists is returned when creating a viewer with a taken name.
+	ErrViewerExists = errors.New("viewer name already exists")
+
+	// ErrStreamUnauthorized is returned when a playback request carries no valid
+	// viewer credential.
+	ErrStreamUnauthorized = errors.New("valid viewer credential required")
+)
+
+// viewerTouchInterval limits how often a viewer's last_seen_at is written.
+const viewerTouchInterval = time.Minute
+
+// ViewerService provides business logic for playback viewers and their stream credentials.
+type ViewerService struct {
+	viewerRepo   repository.ViewerRepository
+	signingKey   []byte
+	signURLs     bool
+	signedURLTTL time.Duration
+	logger       *slog.Logger
+}
+
+// NewViewerService creates a new viewer service.
+func NewViewerService(viewerRepo repository.ViewerRepository) *ViewerService {
+	return &ViewerService{
+		viewerRepo:   viewerRepo,
+		signedURLTTL: 24 * time.Hour,
+		logger:       slog.Default(),
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *ViewerService) WithLogger(logger *slog.Logger) *ViewerService {
+	s.logger = logger
+	return s
+}
+
+// WithSigning enables signed stream URLs. When signURLs is true, playlists carry
+// per-channel tokens that expire after ttl instead of the viewer's token.
+// Signed tokens are always accepted once a key is set.
+func (s *ViewerService) WithSigning(key []byte, signURLs bool, ttl time.Duration) *ViewerService {
+	s.signingKey = key
+	s.signURLs = signURLs && len(key) > 0
+	if ttl > 0 {
+		s.signedURLTTL = ttl
+	}
+	return s
+}
+
+// List returns all viewers.
+func (s *ViewerService) List(ctx context.Context) ([]*models.Viewer, error) {
+	return s.viewerRepo.GetAll(ctx)
+}
+
+// GetByID returns a viewer by ID.
+func (s *ViewerService) GetByID(ctx context.Context, id models.ULID) (*models.Viewer, error) {
+	viewer, err := s.viewerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting viewer: %w", err)
+	}
+	if viewer == nil {
+		return nil, ErrViewerNotFound
+	}
+	return viewer, nil
+}
+
+// Create creates a new active viewer with a random token.
+func (s *ViewerService) Create(ctx context.Context, viewer *models.Viewer) error {
+	viewer.Name = strings.TrimSpace(viewer.Name)
+	existing, err := s.viewerRepo.GetByName(ctx, viewer.Name)
+	if err != nil {
+		return fmt.Errorf("checking viewer name: %w", err)
+	}
+	if existing != nil {
+		return ErrViewerExists
+	}
+
+	token, err := auth.GenerateSessionToken()
+	if err != nil {
+		return err
+	}
+	viewer.Token = token
+	if viewer.IsActive == nil {
+		viewer.IsActive = new(true)
+	}
+	return s.viewerRepo.Create(ctx, viewer)
+}
+
+// Update saves changes to a viewer's name, description, status or expiry.
+func (s *ViewerService) Update(ctx context.Context, viewer *models.Viewer) error {
+	viewer.Name = strings.TrimSpace(viewer.Name)
+	existing, err := s.viewerRepo.GetByName(ctx, viewer.Name)
+	if err != nil {
+		return fmt.Errorf("checking viewer name: %w", err)
+	}
+	if existing != nil && existing.ID != viewer.ID {
+		return ErrViewerExists
+	}
+	return s.viewerRepo.Update(ctx, viewer)
+}
+
+// RegenerateToken replaces a viewer's token, revoking every URL issued with the old one.
+// Signed URLs are not affected, as they carry the viewer ID rather than the token;
+// deactivate the viewer to revoke those as well.
+func (s *ViewerService) RegenerateToken(ctx context.Context, id models.ULID) (*models.Viewer, error) {
+	viewer, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	token, err := auth.GenerateSessionToken()
+	if err != nil {
+		return nil, err
+	}
+	viewer.Token = token
+	if err := s.viewerRepo.Update(ctx, viewer); err != nil {
+		return nil, fmt.Errorf("updating viewer: %w", err)
+	}
+	return viewer, nil
+}
+
+// Delete deletes a viewer.
+func (s *ViewerService) Delete(ctx context.Context, id models.ULID) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.viewerRepo.Delete(ctx, id)
+}
+
+// AuthenticateViewer resolves a viewer token, as used on playlist and tuner URLs.
+// Returns ErrStreamUnauthorized if the token is unknown or the viewer cannot play.
+func (s *ViewerService) AuthenticateViewer(ctx context.Context, token string) (*models.Viewer, error) {
+	if token == "" || auth.IsSignedStreamToken(token) {
+		return nil, ErrStreamUnauthorized
+	}
+	viewer, err := s.viewerRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("getting viewer: %w", err)
+	}
+	return s.checkViewer(ctx, viewer)
+}
+
+// AuthenticateStream resolves the credential on a relay URL for one channel of a proxy.
+// The credential is either a viewer token or a signed stream token for that channel.
+func (s *ViewerService) AuthenticateStream(ctx context.Context, credential string, proxyID, channelID models.ULID) (*models.Viewer, error) {
+	if !auth.IsSignedStreamToken(credential) {
+		return s.AuthenticateViewer(ctx, credential)
+	}
+	if len(s.signingKey) == 0 {
+		return nil, ErrStreamUnauthorized
+	}
+
+	viewerID, err := auth.VerifyStreamToken(s.signingKey, credential, proxyID.String(), channelID.String(), time.Now())
+	if err != nil {
+		s.logger.Debug("rejected signed stream token",
+			slog.String("proxy_id", proxyID.String()),
+			slog.String("channel_id", channelID.String()),
+			slog.String("error", err.Error()),
+		)
+		return nil, ErrStreamUnauthorized
+	}
+	id, err := models.ParseULID(viewerID)
+	if err != nil {
+		return nil, ErrStreamUnauthorized
+	}
+	viewer, err := s.viewerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting viewer: %w", err)
+	}
+	return s.checkViewer(ctx, viewer)
+}
+
+// StreamCredential returns the credential to embed in a relay URL for a viewer:
+// a signed per-channel token when URL signing is enabled, otherwise the viewer's token.
+func (s *ViewerService) StreamCredential(viewer *models.Viewer, proxyID, channelID models.ULID) string {
+	if !s.signURLs {
+		return viewer.Token
+	}
+	return auth.SignStreamToken(s.signingKey, viewer.ID.String(), proxyID.String(), channelID.String(), time.Now().Add(s.signedURLTTL))
+}
+
+// checkViewer verifies a looked-up viewer may play and records that it was seen.
+func (s *ViewerService) checkViewer(ctx context.Context, viewer *models.Viewer) (*models.Viewer, error) {
+	now := time.Now()
+	if viewer == nil || !viewer.CanPlay(now) {
+		return nil, ErrStreamUnauthorized
+	}
+
+	if viewer.LastSeenAt == nil || now.Sub(*viewer.LastSeenAt) > viewerTouchInterval {
+		if err := s.viewerRepo.TouchLastSeen(ctx, viewer.ID, now); err != nil {
+			s.logger.Debug("failed to record viewer activity", slog.String("error", err.Error()))
+		}
+	}
+	return viewer, nil
+}
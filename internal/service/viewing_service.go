@@ -0,0 +1,244 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/config"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/internal/repository"
+)
+
+const (
+	// viewingQueueSize bounds finished viewings waiting to be recorded; further viewings are dropped.
+	viewingQueueSize = 1024
+
+	// viewingPruneInterval is how often expired viewing sessions are purged.
+	viewingPruneInterval = time.Hour
+)
+
+// ViewingService records finished relay viewings as viewing sessions in the
+// background and answers analytics queries over them.
+type ViewingService struct {
+	repo      repository.ViewingSessionRepository
+	logger    *slog.Logger
+	retention time.Duration
+
+	queue chan *models.ViewingSession
+
+	baseCtx    context.Context
+	baseCancel context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewViewingService creates a new viewing service.
+func NewViewingService(repo repository.ViewingSessionRepository) *ViewingService {
+	baseCtx, baseCancel := context.WithCancel(context.Background())
+	return &ViewingService{
+		repo:       repo,
+		logger:     slog.Default(),
+		queue:      make(chan *models.ViewingSession, viewingQueueSize),
+		baseCtx:    baseCtx,
+		baseCancel: baseCancel,
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *ViewingService) WithLogger(logger *slog.Logger) *ViewingService {
+	s.logger = logger
+	return s
+}
+
+// WithConfig applies the retention setting.
+func (s *ViewingService) WithConfig(cfg config.AnalyticsConfig) *ViewingService {
+	s.retention = cfg.Retention
+	return s
+}
+
+// HandleViewingEnded queues a finished relay viewing to be recorded. It does
+// not block; viewings ended while the queue is full are dropped.
+func (s *ViewingService) HandleViewingEnded(v relay.Viewing) {
+	session := viewingSession(v)
+	if session.DurationMs <= 0 {
+		return
+	}
+	select {
+	case s.queue <- session:
+	default:
+		s.logger.Warn("viewing session queue full, dropping session",
+			slog.String("channel_id", v.ChannelID.String()),
+			slog.String("client_ip", v.ClientIP))
+	}
+}
+
+// viewingSession converts a finished relay viewing to a viewing session.
+func viewingSession(v relay.Viewing) *models.ViewingSession {
+	return &models.ViewingSession{
+		ViewerID:            v.ViewerID,
+		ViewerName:          v.ViewerName,
+		ClientIP:            v.ClientIP,
+		UserAgent:           v.UserAgent,
+		ClientRule:          v.ClientRule,
+		ChannelID:           v.ChannelID,
+		ChannelName:         v.ChannelName,
+		ProxyID:             v.ProxyID,
+		ProxyName:           v.ProxyName,
+		SourceID:            v.SourceID,
+		SourceName:          v.SourceName,
+		RelaySessionID:      v.SessionID.String(),
+		DeliveryRoute:       v.Route,
+		OutputFormat:        string(v.Format),
+		CodecVariant:        v.Variant.String(),
+		EncodingProfileName: v.ProfileName,
+		StartedAt:           v.StartedAt,
+		EndedAt:             v.EndedAt,
+		DurationMs:          v.EndedAt.Sub(v.StartedAt).Milliseconds(),
+		BytesSent:           int64(min(v.BytesSent, uint64(1<<63-1))),
+	}
+}
+
+// Start begins recording queued viewings and purging expired ones.
+func (s *ViewingService) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Close stops the service after recording the viewings already queued.
+// Stop the relay first so the viewings of clients it disconnects are recorded.
+func (s *ViewingService) Close() {
+	s.baseCancel()
+	s.wg.Wait()
+}
+
+// run records queued viewings and purges expired ones until Close.
+func (s *ViewingService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(viewingPruneInterval)
+	defer ticker.Stop()
+	s.prune()
+
+	for {
+		select {
+		case <-s.baseCtx.Done():
+			s.drain()
+			return
+		case session := <-s.queue:
+			s.record(s.baseCtx, session)
+		case <-ticker.C:
+			s.prune()
+		}
+	}
+}
+
+// drain records the viewings still queued on Close.
+func (s *ViewingService) drain() {
+	ctx := context.WithoutCancel(s.baseCtx)
+	for {
+		select {
+		case session := <-s.queue:
+			s.record(ctx, session)
+		default:
+			return
+		}
+	}
+}
+
+// record saves a viewing session.
+func (s *ViewingService) record(ctx context.Context, session *models.ViewingSession) {
+	if err := s.repo.Create(ctx, session); err != nil {
+		s.logger.Error("failed to record viewing session",
+			slog.String("channel_id", session.ChannelID.String()),
+			slog.Any("error", err))
+	}
+}
+
+// prune deletes viewing sessions older than the retention period.
+func (s *ViewingService) prune() {
+	if s.retention <= 0 {
+		return
+	}
+	deleted, err := s.repo.DeleteBefore(s.baseCtx, time.Now().Add(-s.retention))
+	if err != nil {
+		s.logger.Warn("failed to prune viewing sessions", slog.Any("error", err))
+	} else if deleted > 0 {
+		s.logger.Debug("pruned viewing sessions", slog.Int64("deleted", deleted))
+	}
+}
+
+// GetSessions returns viewing sessions matching the filter, most recent first.
+func (s *ViewingService) GetSessions(ctx context.Context, filter repository.ViewingSessionFilter, offset, limit int) ([]*models.ViewingSession, int64, error) {
+	return s.repo.GetSessions(ctx, filter, offset, limit)
+}
+
+// TopChannels returns the channels with the most viewing time in [since, until).
+func (s *ViewingService) TopChannels(ctx context.Context, since, until time.Time, limit int) ([]*models.ChannelViewingStats, error) {
+	return s.repo.GetTopChannels(ctx, since, until, limit)
+}
+
+// TranscodeStats returns transcoded viewing time per encoding profile in [since, until).
+func (s *ViewingService) TranscodeStats(ctx context.Context, since, until time.Time) ([]*models.ProfileTranscodeStats, error) {
+	return s.repo.GetTranscodeStats(ctx, since, until)
+}
+
+// PeakConcurrency returns, for each stream source, the most viewing sessions
+// open at once during [since, until), highest first.
+func (s *ViewingService) PeakConcurrency(ctx context.Context, since, until time.Time) ([]*models.SourceConcurrencyStats, error) {
+	sessions, err := s.repo.GetOverlapping(ctx, since, until)
+	if err != nil {
+		return nil, err
+	}
+	return peakConcurrency(sessions, since, until), nil
+}
+
+// peakConcurrency sweeps the start and end of each session, clipped to
+// [since, until), to find the peak number open at once per source.
+func peakConcurrency(sessions []*models.ViewingSession, since, until time.Time) []*models.SourceConcurrencyStats {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	edges := make(map[models.ULID][]edge)
+	names := make(map[models.ULID]string)
+	for _, session := range sessions {
+		start, end := session.StartedAt, session.EndedAt
+		if start.Before(since) {
+			start = since
+		}
+		if !until.IsZero() && end.After(until) {
+			end = until
+		}
+		if !end.After(start) {
+			continue
+		}
+		edges[session.SourceID] = append(edges[session.SourceID], edge{start, 1}, edge{end, -1})
+		names[session.SourceID] = session.SourceName
+	}
+
+	stats := make([]*models.SourceConcurrencyStats, 0, len(edges))
+	for sourceID, sourceEdges := range edges {
+		// Ends sort before starts at the same instant: back-to-back sessions don't overlap
+		slices.SortFunc(sourceEdges, func(a, b edge) int {
+			return cmp.Or(a.at.Compare(b.at), cmp.Compare(a.delta, b.delta))
+		})
+		stat := &models.SourceConcurrencyStats{SourceID: sourceID, SourceName: names[sourceID]}
+		open := 0
+		for _, e := range sourceEdges {
+			open += e.delta
+			if open > stat.Peak {
+				stat.Peak = open
+				stat.PeakAt = e.at
+			}
+		}
+		stats = append(stats, stat)
+	}
+	slices.SortFunc(stats, func(a, b *models.SourceConcurrencyStats) int {
+		return cmp.Or(cmp.Compare(b.Peak, a.Peak), cmp.Compare(a.SourceName, b.SourceName))
+	})
+	return stats
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/relay"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockViewingSessionRepo is an in-memory ViewingSessionRepository.
+type mockViewingSessionRepo struct {
+	mu       sync.Mutex
+	sessions []*models.ViewingSession
+}
+
+func (m *mockViewingSessionRepo) Create(_ context.Context, session *models.ViewingSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions = append(m.sessions, session)
+	return nil
+}
+
+func (m *mockViewingSessionRepo) GetSessions(_ context.Context, _ repository.ViewingSessionFilter, _, _ int) ([]*models.ViewingSession, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sessions, int64(len(m.sessions)), nil
+}
+
+func (m *mockViewingSessionRepo) GetTopChannels(_ context.Context, _, _ time.Time, _ int) ([]*models.ChannelViewingStats, error) {
+	return nil, nil
+}
+
+func (m *mockViewingSessionRepo) GetTranscodeStats(_ context.Context, _, _ time.Time) ([]*models.ProfileTranscodeStats, error) {
+	return nil, nil
+}
+
+func (m *mockViewingSessionRepo) GetOverlapping(_ context.Context, since, until time.Time) ([]*models.ViewingSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sessions []*models.ViewingSession
+	for _, s := range m.sessions {
+		if s.StartedAt.Before(until) && s.EndedAt.After(since) {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *mockViewingSessionRepo) DeleteBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockViewingSessionRepo) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+func TestViewingService_HandleViewingEnded(t *testing.T) {
+	repo := &mockViewingSessionRepo{}
+	svc := NewViewingService(repo)
+	start := time.Now().Add(-90 * time.Second)
+
+	svc.Start()
+	svc.HandleViewingEnded(relay.Viewing{
+		ViewingInfo: relay.ViewingInfo{
+			ClientIP:    "192.168.1.20",
+			ClientRule:  "VLC",
+			ProxyID:     models.NewULID(),
+			ProxyName:   "Living room",
+			Route:       "transcode",
+			ProfileName: "h264-720p",
+		},
+		SessionID:   models.NewULID(),
+		ChannelID:   models.NewULID(),
+		ChannelName: "News",
+		Format:      relay.OutputFormatHLSTS,
+		Variant:     relay.CodecVariant("h264/aac"),
+		UserAgent:   "VLC/3.0.20",
+		StartedAt:   start,
+		EndedAt:     start.Add(90 * time.Second),
+		BytesSent:   1 << 20,
+	})
+	svc.HandleViewingEnded(relay.Viewing{ChannelID: models.NewULID(), StartedAt: start, EndedAt: start})
+	svc.Close()
+
+	require.Equal(t, 1, repo.count(), "zero-length viewings are not recorded")
+	session := repo.sessions[0]
+	assert.Equal(t, "News", session.ChannelName)
+	assert.Equal(t, "VLC", session.ClientRule)
+	assert.Equal(t, "transcode", session.DeliveryRoute)
+	assert.Equal(t, "h264-720p", session.EncodingProfileName)
+	assert.Equal(t, "h264/aac", session.CodecVariant)
+	assert.Equal(t, int64(90_000), session.DurationMs)
+	assert.Equal(t, int64(1<<20), session.BytesSent)
+}
+
+func TestViewingService_PeakConcurrency(t *testing.T) {
+	base := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	sourceA, sourceB := models.NewULID(), models.NewULID()
+	session := func(source models.ULID, name string, from, to time.Duration) *models.ViewingSession {
+		return &models.ViewingSession{SourceID: source, SourceName: name, StartedAt: base.Add(from), EndedAt: base.Add(to)}
+	}
+
+	repo := &mockViewingSessionRepo{sessions: []*models.ViewingSession{
+		session(sourceA, "Provider A", -time.Hour, 30*time.Minute), // starts before the window
+		session(sourceA, "Provider A", 10*time.Minute, 20*time.Minute),
+		session(sourceA, "Provider A", 15*time.Minute, 40*time.Minute),
+		session(sourceA, "Provider A", 40*time.Minute, 50*time.Minute), // back-to-back, not overlapping
+		session(sourceB, "Provider B", 0, time.Hour),
+	}}
+	svc := NewViewingService(repo)
+
+	stats, err := svc.PeakConcurrency(context.Background(), base, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, sourceA, stats[0].SourceID)
+	assert.Equal(t, 3, stats[0].Peak)
+	assert.Equal(t, base.Add(15*time.Minute), stats[0].PeakAt)
+
+	assert.Equal(t, sourceB, stats[1].SourceID)
+	assert.Equal(t, 1, stats[1].Peak)
+	assert.Equal(t, base, stats[1].PeakAt)
+}
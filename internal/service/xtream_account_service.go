@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/ingestor"
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/jmylchreest/tvarr/internal/repository"
+	"github.com/jmylchreest/tvarr/pkg/xtream"
+)
+
+// DefaultAccountExpiryWarning is how long before an Xtream account expires that
+// warnings start being raised.
+const DefaultAccountExpiryWarning = 7 * 24 * time.Hour
+
+// accountStatusActive is the status Xtream servers report for usable accounts.
+const accountStatusActive = "Active"
+
+// XtreamAccountFetcher fetches the account information of an Xtream source.
+type XtreamAccountFetcher interface {
+	AccountInfo(ctx context.Context, source *models.StreamSource) (*xtream.UserInfo, error)
+}
+
+// SourceConnectionTracker tracks upstream connections per stream source.
+type SourceConnectionTracker interface {
+	// CountActiveSessionsForSource returns tvarr's active sessions for a source.
+	CountActiveSessionsForSource(sourceID models.ULID) int
+	// CountActiveSessionsForAccount returns tvarr's active sessions with one
+	// account of a source whose accounts are pooled.
+	CountActiveSessionsForAccount(sourceID models.ULID, account string) int
+	// SetExternalConnections records connections in use outside tvarr.
+	SetExternalConnections(sourceID models.ULID, count int)
+}
+
+// XtreamAccountService polls the account information of Xtream sources. It
+// stores the account status on each source, keeps the source's concurrent
+// stream limit in line with the account's connection limit, and tells the
+// relay about connections the account has open outside tvarr. Only a source's
+// primary account is checked; extra accounts keep their configured limits.
+type XtreamAccountService struct {
+	sourceRepo    repository.StreamSourceRepository
+	fetcher       XtreamAccountFetcher
+	connections   SourceConnectionTracker
+	expiryWarning time.Duration
+	logger        *slog.Logger
+}
+
+// NewXtreamAccountService creates a new Xtream account service.
+func NewXtreamAccountService(sourceRepo repository.StreamSourceRepository) *XtreamAccountService {
+	return &XtreamAccountService{
+		sourceRepo:    sourceRepo,
+		fetcher:       ingestor.NewXtreamHandler(),
+		expiryWarning: DefaultAccountExpiryWarning,
+		logger:        slog.Default(),
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *XtreamAccountService) WithLogger(logger *slog.Logger) *XtreamAccountService {
+	s.logger = logger
+	return s
+}
+
+// WithConnectionTracker sets the tracker told about connections used outside tvarr.
+func (s *XtreamAccountService) WithConnectionTracker(tracker SourceConnectionTracker) *XtreamAccountService {
+	s.connections = tracker
+	return s
+}
+
+// WithExpiryWarning sets how long before expiry warnings are raised.
+func (s *XtreamAccountService) WithExpiryWarning(d time.Duration) *XtreamAccountService {
+	if d > 0 {
+		s.expiryWarning = d
+	}
+	return s
+}
+
+// RefreshAccounts checks the account of every enabled Xtream source. A source
+// whose check fails is logged and counted, and does not stop the others.
+func (s *XtreamAccountService) RefreshAccounts(ctx context.Context) (checked, failed int, err error) {
+	sources, err := s.sourceRepo.GetEnabled(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("getting stream sources: %w", err)
+	}
+
+	for _, source := range sources {
+		if !source.IsXtream() {
+			continue
+		}
+		if err := s.RefreshAccount(ctx, source); err != nil {
+			failed++
+			s.logger.Warn("failed to check xtream account",
+				slog.String("source_id", source.ID.String()),
+				slog.String("source_name", source.Name),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		checked++
+	}
+	return checked, failed, nil
+}
+
+// RefreshAccount checks the account of an Xtream source and stores the result.
+func (s *XtreamAccountService) RefreshAccount(ctx context.Context, source *models.StreamSource) error {
+	info, err := s.fetcher.AccountInfo(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	applyAccountInfo(source, info)
+	if err := s.sourceRepo.UpdateAccountStatus(ctx, source); err != nil {
+		return err
+	}
+
+	external := 0
+	if s.connections != nil {
+		// The account information is the primary account's, so only sessions
+		// connected with it are tvarr's own
+		own := s.connections.CountActiveSessionsForSource(source.ID)
+		if len(source.Accounts()) > 0 {
+			own = s.connections.CountActiveSessionsForAccount(source.ID, source.Username)
+		}
+		external = max(source.AccountActiveConnections-own, 0)
+		s.connections.SetExternalConnections(source.ID, external)
+	}
+
+	s.warnAccount(source, external)
+	return nil
+}
+
+// applyAccountInfo copies account information onto a source. A reported
+// connection limit replaces the source's concurrent stream limit.
+func applyAccountInfo(source *models.StreamSource, info *xtream.UserInfo) {
+	now := models.Now()
+	source.AccountStatus = info.Status
+	source.AccountExpiresAt = nil
+	if exp := info.ExpirationTime(); !exp.IsZero() {
+		source.AccountExpiresAt = &exp
+	}
+	source.AccountMaxConnections = int(info.MaxConnections.Int())
+	source.AccountActiveConnections = int(info.ActiveConnections.Int())
+	source.AccountCheckedAt = &now
+
+	if source.AccountMaxConnections > 0 {
+		source.MaxConcurrentStreams = source.AccountMaxConnections
+	}
+}
+
+// warnAccount logs warnings for accounts that are unusable, about to expire,
+// or whose connections are all taken by other clients.
+func (s *XtreamAccountService) warnAccount(source *models.StreamSource, external int) {
+	attrs := []any{
+		slog.String("source_id", source.ID.String()),
+		slog.String("source_name", source.Name),
+	}
+
+	switch {
+	case source.AccountStatus != "" && source.AccountStatus != accountStatusActive:
+		s.logger.Warn("xtream account is not active",
+			append(attrs, slog.String("status", source.AccountStatus))...)
+	case source.AccountExpiresWithin(0):
+		s.logger.Warn("xtream account has expired",
+			append(attrs, slog.Time("expires_at", *source.AccountExpiresAt))...)
+	case source.AccountExpiresWithin(s.expiryWarning):
+		s.logger.Warn("xtream account expires soon",
+			append(attrs,
+				slog.Time("expires_at", *source.AccountExpiresAt),
+				slog.Duration("remaining", time.Until(*source.AccountExpiresAt).Round(time.Hour)),
+			)...)
+	}
+
+	if external > 0 && source.MaxConcurrentStreams > 0 && external >= source.MaxConcurrentStreams {
+		s.logger.Warn("all xtream account connections are in use outside tvarr",
+			append(attrs,
+				slog.Int("active_connections", source.AccountActiveConnections),
+				slog.Int("max_connections", source.MaxConcurrentStreams),
+			)...)
+	}
+}
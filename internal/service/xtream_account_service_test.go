@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/tvarr/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConnectionTracker records the external connections set per source.
+type fakeConnectionTracker struct {
+	sessions int
+	external map[models.ULID]int
+}
+
+func (f *fakeConnectionTracker) CountActiveSessionsForSource(sourceID models.ULID) int {
+	return f.sessions
+}
+
+func (f *fakeConnectionTracker) CountActiveSessionsForAccount(sourceID models.ULID, account string) int {
+	return f.sessions
+}
+
+func (f *fakeConnectionTracker) SetExternalConnections(sourceID models.ULID, count int) {
+	f.external[sourceID] = count
+}
+
+func TestXtreamAccountService_RefreshAccounts(t *testing.T) {
+	expires := time.Now().Add(72 * time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/player_api.php", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user_info":{"auth":1,"status":"Active","exp_date":"` + strconv.FormatInt(expires, 10) +
+			`","max_connections":"3","active_cons":"2"}}`))
+	}))
+	defer server.Close()
+
+	repo := newMockStreamSourceRepo()
+	xtreamSource := &models.StreamSource{
+		Name:                 "provider",
+		Type:                 models.SourceTypeXtream,
+		URL:                  server.URL,
+		Username:             "user",
+		Password:             "pass",
+		Enabled:              models.BoolPtr(true),
+		MaxConcurrentStreams: 1,
+	}
+	m3uSource := &models.StreamSource{
+		Name:                 "playlist",
+		Type:                 models.SourceTypeM3U,
+		URL:                  server.URL + "/list.m3u",
+		Enabled:              models.BoolPtr(true),
+		MaxConcurrentStreams: 1,
+	}
+	require.NoError(t, repo.Create(context.Background(), xtreamSource))
+	require.NoError(t, repo.Create(context.Background(), m3uSource))
+
+	tracker := &fakeConnectionTracker{sessions: 1, external: make(map[models.ULID]int)}
+	svc := NewXtreamAccountService(repo).WithConnectionTracker(tracker)
+
+	checked, failed, err := svc.RefreshAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, checked)
+	assert.Equal(t, 0, failed)
+
+	assert.Equal(t, "Active", xtreamSource.AccountStatus)
+	assert.Equal(t, 3, xtreamSource.AccountMaxConnections)
+	assert.Equal(t, 2, xtreamSource.AccountActiveConnections)
+	require.NotNil(t, xtreamSource.AccountExpiresAt)
+	assert.Equal(t, expires, xtreamSource.AccountExpiresAt.Unix())
+	require.NotNil(t, xtreamSource.AccountCheckedAt)
+	assert.True(t, xtreamSource.AccountExpiresWithin(DefaultAccountExpiryWarning))
+
+	// The account's limit replaces the source's, and the connection tvarr does
+	// not hold is reported as external
+	assert.Equal(t, 3, xtreamSource.MaxConcurrentStreams)
+	assert.Equal(t, 1, tracker.external[xtreamSource.ID])
+
+	// Non-Xtream sources are left alone
+	assert.Nil(t, m3uSource.AccountCheckedAt)
+	assert.Equal(t, 1, m3uSource.MaxConcurrentStreams)
+}
+
+func TestXtreamAccountService_RefreshAccountsCountsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	repo := newMockStreamSourceRepo()
+	source := &models.StreamSource{
+		Name:                 "provider",
+		Type:                 models.SourceTypeXtream,
+		URL:                  server.URL,
+		Username:             "user",
+		Password:             "pass",
+		Enabled:              models.BoolPtr(true),
+		MaxConcurrentStreams: 2,
+	}
+	require.NoError(t, repo.Create(context.Background(), source))
+
+	checked, failed, err := NewXtreamAccountService(repo).RefreshAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, checked)
+	assert.Equal(t, 1, failed)
+	assert.Nil(t, source.AccountCheckedAt)
+	assert.Equal(t, 2, source.MaxConcurrentStreams)
+}
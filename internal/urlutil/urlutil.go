@@ -3,12 +3,14 @@ package urlutil
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/jmylchreest/tvarr/pkg/httpclient"
 )
@@ -156,49 +158,101 @@ func NewDefaultResourceFetcher() *ResourceFetcher {
 	}
 }
 
+// ErrNotModified is returned by FetchIfModified when the resource has not
+// changed since the given validators were recorded.
+var ErrNotModified = errors.New("resource not modified")
+
+// Validators identify the fetched version of a resource for conditional
+// requests. For file:// URLs, LastModified holds the file's modification time.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
 // Fetch retrieves content from a URL (http://, https://, or file://).
 // Returns an io.ReadCloser that must be closed by the caller.
 func (f *ResourceFetcher) Fetch(ctx context.Context, u string) (io.ReadCloser, error) {
+	body, _, err := f.FetchIfModified(ctx, u, Validators{})
+	return body, err
+}
+
+// FetchIfModified retrieves content like Fetch, but only if it changed since
+// prev was recorded: HTTP requests carry If-None-Match and If-Modified-Since,
+// and files are compared by modification time. Returns ErrNotModified if the
+// resource is unchanged, otherwise the body and the validators of the fetched
+// version.
+func (f *ResourceFetcher) FetchIfModified(ctx context.Context, u string, prev Validators) (io.ReadCloser, Validators, error) {
 	scheme := GetScheme(u)
 
 	switch scheme {
 	case SchemeHTTP, SchemeHTTPS:
-		return f.fetchHTTP(ctx, u)
+		return f.fetchHTTP(ctx, u, prev)
 	case SchemeFile:
-		return f.fetchFile(u)
+		return f.fetchFile(u, prev)
 	default:
-		return nil, fmt.Errorf("unsupported URL scheme: %s (URL: %s)", scheme, u)
+		return nil, Validators{}, fmt.Errorf("unsupported URL scheme: %s (URL: %s)", scheme, u)
 	}
 }
 
 // fetchHTTP fetches content from an HTTP/HTTPS URL.
-func (f *ResourceFetcher) fetchHTTP(ctx context.Context, u string) (io.ReadCloser, error) {
-	resp, err := f.httpClient.Get(ctx, u)
+func (f *ResourceFetcher) fetchHTTP(ctx context.Context, u string, prev Validators) (io.ReadCloser, Validators, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, Validators{}, fmt.Errorf("creating request: %w", err)
+	}
+	if prev.ETag != "" {
+		req.Header.Set("If-None-Match", prev.ETag)
+	}
+	if prev.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.LastModified)
+	}
+
+	resp, err := f.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+		return nil, Validators{}, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		return nil, prev, ErrNotModified
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		_ = resp.Body.Close()
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, Validators{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return resp.Body, Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
 }
 
 // fetchFile fetches content from a file:// URL.
-func (f *ResourceFetcher) fetchFile(u string) (io.ReadCloser, error) {
+func (f *ResourceFetcher) fetchFile(u string, prev Validators) (io.ReadCloser, Validators, error) {
 	path, err := FilePathFromURL(u)
 	if err != nil {
-		return nil, err
+		return nil, Validators{}, err
 	}
 
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, Validators{}, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, Validators{}, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	current := Validators{LastModified: info.ModTime().UTC().Format(time.RFC3339Nano)}
+	if prev.LastModified == current.LastModified {
+		_ = file.Close()
+		return nil, prev, ErrNotModified
 	}
 
-	return file, nil
+	return file, current, nil
 }
 
 // ValidateURL checks if a URL is valid and uses a supported scheme.
@@ -2,9 +2,13 @@ package urlutil
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -204,6 +208,70 @@ func TestResourceFetcher_FetchFile(t *testing.T) {
 	})
 }
 
+func TestResourceFetcher_FetchIfModified_HTTP(t *testing.T) {
+	const etag = `"v1"`
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write([]byte("#EXTM3U\n"))
+	}))
+	defer server.Close()
+
+	fetcher := NewDefaultResourceFetcher()
+
+	t.Run("first fetch returns validators", func(t *testing.T) {
+		reader, validators, err := fetcher.FetchIfModified(context.Background(), server.URL, Validators{})
+		require.NoError(t, err)
+		defer reader.Close()
+
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "#EXTM3U\n", string(content))
+		assert.Equal(t, Validators{ETag: etag, LastModified: lastModified}, validators)
+	})
+
+	t.Run("matching etag is not modified", func(t *testing.T) {
+		prev := Validators{ETag: etag, LastModified: lastModified}
+		_, validators, err := fetcher.FetchIfModified(context.Background(), server.URL, prev)
+		assert.ErrorIs(t, err, ErrNotModified)
+		assert.Equal(t, prev, validators)
+	})
+
+	t.Run("stale etag is fetched", func(t *testing.T) {
+		reader, _, err := fetcher.FetchIfModified(context.Background(), server.URL, Validators{ETag: `"v0"`})
+		require.NoError(t, err)
+		reader.Close()
+	})
+}
+
+func TestResourceFetcher_FetchIfModified_File(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "guide.xml")
+	require.NoError(t, os.WriteFile(testFile, []byte("<tv/>"), 0644))
+	fileURL := "file://" + testFile
+
+	fetcher := NewDefaultResourceFetcher()
+
+	reader, validators, err := fetcher.FetchIfModified(context.Background(), fileURL, Validators{})
+	require.NoError(t, err)
+	reader.Close()
+	assert.NotEmpty(t, validators.LastModified)
+
+	_, _, err = fetcher.FetchIfModified(context.Background(), fileURL, validators)
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(testFile, later, later))
+	reader, _, err = fetcher.FetchIfModified(context.Background(), fileURL, validators)
+	require.NoError(t, err)
+	reader.Close()
+}
+
 func TestValidateURL(t *testing.T) {
 	// Create a temporary file for file:// URL testing
 	tmpDir := t.TempDir()
@@ -1,9 +1,14 @@
 package ffmpeg
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -164,10 +169,16 @@ type StreamInfo struct {
 	SelectedAudioTrack int `json:"selected_audio_track"`
 }
 
+// ErrProxyUnsupported is returned when probing a stream through a proxy that
+// ffprobe cannot use. Only HTTP proxies are supported.
+var ErrProxyUnsupported = errors.New("ffprobe only supports HTTP proxies")
+
 // Prober handles ffprobe operations.
 type Prober struct {
 	ffprobePath string
 	timeout     time.Duration
+	proxyURL    string
+	headers     http.Header
 }
 
 // NewProber creates a new stream prober.
@@ -184,6 +195,86 @@ func (p *Prober) WithTimeout(timeout time.Duration) *Prober {
 	return p
 }
 
+// WithProxy returns a copy of the prober that fetches network streams through
+// an HTTP proxy. An empty proxyURL connects directly.
+func (p *Prober) WithProxy(proxyURL string) *Prober {
+	clone := *p
+	clone.proxyURL = proxyURL
+	return &clone
+}
+
+// WithHeaders returns a copy of the prober that sends extra HTTP request
+// headers when fetching network streams.
+func (p *Prober) WithHeaders(headers http.Header) *Prober {
+	clone := *p
+	clone.headers = headers.Clone()
+	return &clone
+}
+
+// HeaderArgs returns the ffmpeg/ffprobe input options that send the given HTTP
+// request headers: User-Agent through -user_agent, the rest through -headers.
+func HeaderArgs(headers http.Header) []string {
+	if len(headers) == 0 {
+		return nil
+	}
+	rest := headers.Clone()
+	var args []string
+	if userAgent := rest.Get("User-Agent"); userAgent != "" {
+		args = append(args, "-user_agent", userAgent)
+	}
+	rest.Del("User-Agent")
+	if len(rest) > 0 {
+		var b strings.Builder
+		_ = rest.Write(&b)
+		args = append(args, "-headers", b.String())
+	}
+	return args
+}
+
+// ParseHeaderLines parses headers in the "Name: value" line format written by
+// http.Header.Write, as used to pass probe headers to remote daemons.
+func ParseHeaderLines(s string) http.Header {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	header, _ := textproto.NewReader(bufio.NewReader(strings.NewReader(s + "\r\n"))).ReadMIMEHeader()
+	if len(header) == 0 {
+		return nil
+	}
+	return http.Header(header)
+}
+
+// networkHeaderArgs returns the header options for a network stream.
+func (p *Prober) networkHeaderArgs(streamURL string) []string {
+	if !(strings.HasPrefix(streamURL, "http://") || strings.HasPrefix(streamURL, "https://")) {
+		return nil
+	}
+	return HeaderArgs(p.headers)
+}
+
+// proxyArgs returns the ffprobe options that route a network stream through
+// the prober's proxy. Streams are never fetched directly when a proxy is set
+// that ffprobe cannot use.
+func (p *Prober) proxyArgs(streamURL string) ([]string, error) {
+	if p.proxyURL == "" || !(strings.HasPrefix(streamURL, "http://") || strings.HasPrefix(streamURL, "https://")) {
+		return nil, nil
+	}
+	u, err := url.Parse(p.proxyURL)
+	if err != nil || u.Scheme != "http" {
+		return nil, fmt.Errorf("%w: %s", ErrProxyUnsupported, redactProxyURL(p.proxyURL))
+	}
+	return []string{"-http_proxy", p.proxyURL}, nil
+}
+
+// redactProxyURL hides the password in a proxy URL for error messages.
+func redactProxyURL(proxyURL string) string {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return "invalid proxy URL"
+	}
+	return u.Redacted()
+}
+
 // Probe probes a stream URL and returns detailed information.
 func (p *Prober) Probe(ctx context.Context, url string) (*ProbeResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
@@ -206,6 +297,13 @@ func (p *Prober) Probe(ctx context.Context, url string) (*ProbeResult, error) {
 		)
 	}
 
+	proxyArgs, err := p.proxyArgs(url)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, proxyArgs...)
+	args = append(args, p.networkHeaderArgs(url)...)
+
 	args = append(args, url)
 
 	cmd := exec.CommandContext(ctx, p.ffprobePath, args...)
@@ -448,6 +546,13 @@ func (p *Prober) QuickProbe(ctx context.Context, url string) (*StreamInfo, error
 		args = append(args, "-reconnect", "1")
 	}
 
+	proxyArgs, err := p.proxyArgs(url)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, proxyArgs...)
+	args = append(args, p.networkHeaderArgs(url)...)
+
 	args = append(args, url)
 
 	cmd := exec.CommandContext(ctx, p.ffprobePath, args...)
@@ -481,6 +586,13 @@ func (p *Prober) CheckStreamHealth(ctx context.Context, url string) error {
 		args = append(args, "-reconnect", "1")
 	}
 
+	proxyArgs, err := p.proxyArgs(url)
+	if err != nil {
+		return err
+	}
+	args = append(args, proxyArgs...)
+	args = append(args, p.networkHeaderArgs(url)...)
+
 	args = append(args, url)
 
 	cmd := exec.CommandContext(ctx, p.ffprobePath, args...)
@@ -2090,8 +2090,11 @@ type TranscodeStart struct {
 	// Default: auto-select based on target codec (fmp4 for av1/vp9, mpegts for h264/h265)
 	// This determines the daemon's FFmpeg output format and demuxer selection.
 	OutputContainerFormat string `protobuf:"bytes,28,opt,name=output_container_format,json=outputContainerFormat,proto3" json:"output_container_format,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+	// W3C trace context of the coordinator span that started the job
+	// (traceparent, tracestate), so daemon spans join the same trace
+	TraceContext  map[string]string `protobuf:"bytes,29,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TranscodeStart) Reset() {
@@ -2306,6 +2309,13 @@ func (x *TranscodeStart) GetOutputContainerFormat() string {
 	return ""
 }
 
+func (x *TranscodeStart) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
 // EncoderOverride allows forcing specific encoders when conditions match.
 // This is used to work around hardware encoder bugs (e.g., AMD hevc_vaapi with Mesa 21.1+).
 type EncoderOverride struct {
@@ -3126,7 +3136,13 @@ type ProbeRequest struct {
 	// Stream URL to probe
 	StreamUrl string `protobuf:"bytes,1,opt,name=stream_url,json=streamUrl,proto3" json:"stream_url,omitempty"`
 	// Timeout in milliseconds (0 = use default)
-	TimeoutMs     int32 `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	TimeoutMs int32 `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Upstream proxy to fetch the stream through (empty = direct)
+	ProxyUrl string `protobuf:"bytes,3,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+	// Extra HTTP request headers, one "Name: value" per line (empty = none)
+	Headers string `protobuf:"bytes,4,opt,name=headers,proto3" json:"headers,omitempty"`
+	// W3C trace context of the coordinator span that requested the probe
+	TraceContext  map[string]string `protobuf:"bytes,5,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -3175,6 +3191,27 @@ func (x *ProbeRequest) GetTimeoutMs() int32 {
 	return 0
 }
 
+func (x *ProbeRequest) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+func (x *ProbeRequest) GetHeaders() string {
+	if x != nil {
+		return x.Headers
+	}
+	return ""
+}
+
+func (x *ProbeRequest) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
 // ProbeResponse contains codec information from ffprobe.
 type ProbeResponse struct {
 	state   protoimpl.MessageState `protogen:"open.v1"`
@@ -3519,7 +3556,8 @@ const file_pkg_ffmpegd_proto_ffmpegd_proto_rawDesc = "" +
 	"\x0einput_complete\x18\a \x01(\v2\x1f.ffmpegd.TranscodeInputCompleteH\x00R\rinputComplete\x12<\n" +
 	"\rprobe_request\x18\b \x01(\v2\x15.ffmpegd.ProbeRequestH\x00R\fprobeRequest\x12?\n" +
 	"\x0eprobe_response\x18\t \x01(\v2\x16.ffmpegd.ProbeResponseH\x00R\rprobeResponseB\t\n" +
-	"\apayload\"\x84\t\n" +
+	"\apayload\"\x95\n" +
+	"\n" +
 	"\x0eTranscodeStart\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1d\n" +
 	"\n" +
@@ -3552,9 +3590,13 @@ const file_pkg_ffmpegd_proto_ffmpegd_proto_rawDesc = "" +
 	"\foutput_flags\x18\x19 \x01(\tR\voutputFlags\x12!\n" +
 	"\fglobal_flags\x18\x1a \x01(\tR\vglobalFlags\x12E\n" +
 	"\x11encoder_overrides\x18\x1b \x03(\v2\x18.ffmpegd.EncoderOverrideR\x10encoderOverrides\x126\n" +
-	"\x17output_container_format\x18\x1c \x01(\tR\x15outputContainerFormat\x1a?\n" +
+	"\x17output_container_format\x18\x1c \x01(\tR\x15outputContainerFormat\x12N\n" +
+	"\rtrace_context\x18\x1d \x03(\v2).ffmpegd.TranscodeStart.TraceContextEntryR\ftraceContext\x1a?\n" +
 	"\x11ExtraOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b\v\x10\fJ\x04\b\f\x10\r\"\xd9\x01\n" +
 	"\x0fEncoderOverride\x12\x1d\n" +
 	"\n" +
@@ -3635,12 +3677,18 @@ const file_pkg_ffmpegd_proto_ffmpegd_proto_rawDesc = "" +
 	"\x14total_jobs_completed\x18\x04 \x01(\x04R\x12totalJobsCompleted\x12*\n" +
 	"\x11total_jobs_failed\x18\x05 \x01(\x04R\x0ftotalJobsFailed\x122\n" +
 	"\x15total_bytes_processed\x18\x06 \x01(\x04R\x13totalBytesProcessed\x12I\n" +
-	"\x13total_encoding_time\x18\a \x01(\v2\x19.google.protobuf.DurationR\x11totalEncodingTime\"L\n" +
+	"\x13total_encoding_time\x18\a \x01(\v2\x19.google.protobuf.DurationR\x11totalEncodingTime\"\x92\x02\n" +
 	"\fProbeRequest\x12\x1d\n" +
 	"\n" +
 	"stream_url\x18\x01 \x01(\tR\tstreamUrl\x12\x1d\n" +
 	"\n" +
-	"timeout_ms\x18\x02 \x01(\x05R\ttimeoutMs\"\xbb\x04\n" +
+	"timeout_ms\x18\x02 \x01(\x05R\ttimeoutMs\x12\x1b\n" +
+	"\tproxy_url\x18\x03 \x01(\tR\bproxyUrl\x12\x18\n" +
+	"\aheaders\x18\x04 \x01(\tR\aheaders\x12L\n" +
+	"\rtrace_context\x18\x05 \x03(\v2'.ffmpegd.ProbeRequest.TraceContextEntryR\ftraceContext\x1a?\n" +
+	"\x11TraceContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbb\x04\n" +
 	"\rProbeResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1f\n" +
@@ -3688,7 +3736,7 @@ func file_pkg_ffmpegd_proto_ffmpegd_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_ffmpegd_proto_ffmpegd_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_pkg_ffmpegd_proto_ffmpegd_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_pkg_ffmpegd_proto_ffmpegd_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_pkg_ffmpegd_proto_ffmpegd_proto_goTypes = []any{
 	(GPUClass)(0),                  // 0: ffmpegd.GPUClass
 	(DaemonCommand_CommandType)(0), // 1: ffmpegd.DaemonCommand.CommandType
@@ -3725,17 +3773,19 @@ var file_pkg_ffmpegd_proto_ffmpegd_proto_goTypes = []any{
 	(*ProbeRequest)(nil),           // 32: ffmpegd.ProbeRequest
 	(*ProbeResponse)(nil),          // 33: ffmpegd.ProbeResponse
 	nil,                            // 34: ffmpegd.TranscodeStart.ExtraOptionsEntry
-	(*durationpb.Duration)(nil),    // 35: google.protobuf.Duration
+	nil,                            // 35: ffmpegd.TranscodeStart.TraceContextEntry
+	nil,                            // 36: ffmpegd.ProbeRequest.TraceContextEntry
+	(*durationpb.Duration)(nil),    // 37: google.protobuf.Duration
 }
 var file_pkg_ffmpegd_proto_ffmpegd_proto_depIdxs = []int32{
 	12, // 0: ffmpegd.RegisterRequest.capabilities:type_name -> ffmpegd.Capabilities
-	35, // 1: ffmpegd.RegisterResponse.heartbeat_interval:type_name -> google.protobuf.Duration
+	37, // 1: ffmpegd.RegisterResponse.heartbeat_interval:type_name -> google.protobuf.Duration
 	17, // 2: ffmpegd.HeartbeatRequest.system_stats:type_name -> ffmpegd.SystemStats
 	11, // 3: ffmpegd.HeartbeatRequest.active_jobs:type_name -> ffmpegd.JobStatus
 	9,  // 4: ffmpegd.HeartbeatResponse.commands:type_name -> ffmpegd.DaemonCommand
 	1,  // 5: ffmpegd.DaemonCommand.type:type_name -> ffmpegd.DaemonCommand.CommandType
 	26, // 6: ffmpegd.JobStatus.stats:type_name -> ffmpegd.TranscodeStats
-	35, // 7: ffmpegd.JobStatus.running_time:type_name -> google.protobuf.Duration
+	37, // 7: ffmpegd.JobStatus.running_time:type_name -> google.protobuf.Duration
 	13, // 8: ffmpegd.Capabilities.hw_accels:type_name -> ffmpegd.HWAccelInfo
 	15, // 9: ffmpegd.Capabilities.gpus:type_name -> ffmpegd.GPUInfo
 	16, // 10: ffmpegd.Capabilities.performance:type_name -> ffmpegd.PerformanceMetrics
@@ -3757,29 +3807,31 @@ var file_pkg_ffmpegd_proto_ffmpegd_proto_depIdxs = []int32{
 	33, // 26: ffmpegd.TranscodeMessage.probe_response:type_name -> ffmpegd.ProbeResponse
 	34, // 27: ffmpegd.TranscodeStart.extra_options:type_name -> ffmpegd.TranscodeStart.ExtraOptionsEntry
 	22, // 28: ffmpegd.TranscodeStart.encoder_overrides:type_name -> ffmpegd.EncoderOverride
-	25, // 29: ffmpegd.ESSampleBatch.video_samples:type_name -> ffmpegd.ESSample
-	25, // 30: ffmpegd.ESSampleBatch.audio_samples:type_name -> ffmpegd.ESSample
-	35, // 31: ffmpegd.TranscodeStats.running_time:type_name -> google.protobuf.Duration
-	2,  // 32: ffmpegd.TranscodeError.code:type_name -> ffmpegd.TranscodeError.ErrorCode
-	12, // 33: ffmpegd.GetStatsResponse.capabilities:type_name -> ffmpegd.Capabilities
-	17, // 34: ffmpegd.GetStatsResponse.system_stats:type_name -> ffmpegd.SystemStats
-	11, // 35: ffmpegd.GetStatsResponse.active_jobs:type_name -> ffmpegd.JobStatus
-	35, // 36: ffmpegd.GetStatsResponse.total_encoding_time:type_name -> google.protobuf.Duration
-	3,  // 37: ffmpegd.FFmpegDaemon.Register:input_type -> ffmpegd.RegisterRequest
-	7,  // 38: ffmpegd.FFmpegDaemon.Heartbeat:input_type -> ffmpegd.HeartbeatRequest
-	5,  // 39: ffmpegd.FFmpegDaemon.Unregister:input_type -> ffmpegd.UnregisterRequest
-	20, // 40: ffmpegd.FFmpegDaemon.Transcode:input_type -> ffmpegd.TranscodeMessage
-	30, // 41: ffmpegd.FFmpegDaemon.GetStats:input_type -> ffmpegd.GetStatsRequest
-	4,  // 42: ffmpegd.FFmpegDaemon.Register:output_type -> ffmpegd.RegisterResponse
-	8,  // 43: ffmpegd.FFmpegDaemon.Heartbeat:output_type -> ffmpegd.HeartbeatResponse
-	6,  // 44: ffmpegd.FFmpegDaemon.Unregister:output_type -> ffmpegd.UnregisterResponse
-	20, // 45: ffmpegd.FFmpegDaemon.Transcode:output_type -> ffmpegd.TranscodeMessage
-	31, // 46: ffmpegd.FFmpegDaemon.GetStats:output_type -> ffmpegd.GetStatsResponse
-	42, // [42:47] is the sub-list for method output_type
-	37, // [37:42] is the sub-list for method input_type
-	37, // [37:37] is the sub-list for extension type_name
-	37, // [37:37] is the sub-list for extension extendee
-	0,  // [0:37] is the sub-list for field type_name
+	35, // 29: ffmpegd.TranscodeStart.trace_context:type_name -> ffmpegd.TranscodeStart.TraceContextEntry
+	25, // 30: ffmpegd.ESSampleBatch.video_samples:type_name -> ffmpegd.ESSample
+	25, // 31: ffmpegd.ESSampleBatch.audio_samples:type_name -> ffmpegd.ESSample
+	37, // 32: ffmpegd.TranscodeStats.running_time:type_name -> google.protobuf.Duration
+	2,  // 33: ffmpegd.TranscodeError.code:type_name -> ffmpegd.TranscodeError.ErrorCode
+	12, // 34: ffmpegd.GetStatsResponse.capabilities:type_name -> ffmpegd.Capabilities
+	17, // 35: ffmpegd.GetStatsResponse.system_stats:type_name -> ffmpegd.SystemStats
+	11, // 36: ffmpegd.GetStatsResponse.active_jobs:type_name -> ffmpegd.JobStatus
+	37, // 37: ffmpegd.GetStatsResponse.total_encoding_time:type_name -> google.protobuf.Duration
+	36, // 38: ffmpegd.ProbeRequest.trace_context:type_name -> ffmpegd.ProbeRequest.TraceContextEntry
+	3,  // 39: ffmpegd.FFmpegDaemon.Register:input_type -> ffmpegd.RegisterRequest
+	7,  // 40: ffmpegd.FFmpegDaemon.Heartbeat:input_type -> ffmpegd.HeartbeatRequest
+	5,  // 41: ffmpegd.FFmpegDaemon.Unregister:input_type -> ffmpegd.UnregisterRequest
+	20, // 42: ffmpegd.FFmpegDaemon.Transcode:input_type -> ffmpegd.TranscodeMessage
+	30, // 43: ffmpegd.FFmpegDaemon.GetStats:input_type -> ffmpegd.GetStatsRequest
+	4,  // 44: ffmpegd.FFmpegDaemon.Register:output_type -> ffmpegd.RegisterResponse
+	8,  // 45: ffmpegd.FFmpegDaemon.Heartbeat:output_type -> ffmpegd.HeartbeatResponse
+	6,  // 46: ffmpegd.FFmpegDaemon.Unregister:output_type -> ffmpegd.UnregisterResponse
+	20, // 47: ffmpegd.FFmpegDaemon.Transcode:output_type -> ffmpegd.TranscodeMessage
+	31, // 48: ffmpegd.FFmpegDaemon.GetStats:output_type -> ffmpegd.GetStatsResponse
+	44, // [44:49] is the sub-list for method output_type
+	39, // [39:44] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_pkg_ffmpegd_proto_ffmpegd_proto_init() }
@@ -3804,7 +3856,7 @@ func file_pkg_ffmpegd_proto_ffmpegd_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_ffmpegd_proto_ffmpegd_proto_rawDesc), len(file_pkg_ffmpegd_proto_ffmpegd_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   32,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
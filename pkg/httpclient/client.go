@@ -116,6 +116,10 @@ type Config struct {
 	// regardless of this setting. This only affects circuit breaker failure tracking.
 	AcceptableStatusCodes *StatusCodeSet
 
+	// ProxyURL routes requests through an HTTP(S) or SOCKS5 proxy, e.g.
+	// "socks5://vpn:1080". Ignored when BaseClient is set.
+	ProxyURL string
+
 	// BaseClient is the underlying http.Client to use.
 	// If nil, a default client is created.
 	BaseClient *http.Client
@@ -155,9 +159,7 @@ func New(cfg Config) *Client {
 
 	baseClient := cfg.BaseClient
 	if baseClient == nil {
-		baseClient = &http.Client{
-			Timeout: cfg.Timeout,
-		}
+		baseClient = newBaseClient(cfg)
 	}
 
 	return &Client{
@@ -168,6 +170,17 @@ func New(cfg Config) *Client {
 	}
 }
 
+// newBaseClient creates the underlying http.Client for a config.
+func newBaseClient(cfg Config) *http.Client {
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+	}
+	if cfg.ProxyURL != "" {
+		client.Transport = NewProxyTransport(nil, cfg.ProxyURL)
+	}
+	return client
+}
+
 // newWithDefaults creates a new client with default configuration.
 func newWithDefaults() *Client {
 	return New(DefaultConfig())
@@ -183,9 +196,7 @@ func NewWithBreaker(cfg Config, breaker *CircuitBreaker) *Client {
 
 	baseClient := cfg.BaseClient
 	if baseClient == nil {
-		baseClient = &http.Client{
-			Timeout: cfg.Timeout,
-		}
+		baseClient = newBaseClient(cfg)
 	}
 
 	// Use provided breaker or create new one
@@ -476,15 +487,16 @@ func isRetryableStatus(code int) bool {
 // If AcceptableStatusCodes is configured (non-nil/non-empty), ONLY those codes are acceptable.
 // This allows full control, including making 2xx codes unacceptable if needed.
 //
-// If AcceptableStatusCodes is nil/empty, defaults to accepting all 2xx status codes.
+// If AcceptableStatusCodes is nil/empty, defaults to accepting all 2xx status codes
+// and 304 Not Modified, the expected answer to a conditional request.
 func (c *Client) isAcceptableStatus(code int) bool {
 	// If explicitly configured, use only the configured codes
 	if !c.config.AcceptableStatusCodes.IsEmpty() {
 		return c.config.AcceptableStatusCodes.Contains(code)
 	}
 
-	// Default behavior: 2xx status codes are acceptable
-	return code >= 200 && code < 300
+	// Default behavior: 2xx and 304 status codes are acceptable
+	return (code >= 200 && code < 300) || code == http.StatusNotModified
 }
 
 // CircuitState represents the state of a circuit breaker.
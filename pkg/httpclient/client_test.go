@@ -404,6 +404,11 @@ func TestClient_IsAcceptableStatus(t *testing.T) {
 		}
 	})
 
+	t.Run("304 is acceptable by default", func(t *testing.T) {
+		client := newWithDefaults()
+		assert.True(t, client.isAcceptableStatus(http.StatusNotModified))
+	})
+
 	t.Run("4xx and 5xx codes are not acceptable by default", func(t *testing.T) {
 		client := newWithDefaults()
 
@@ -0,0 +1,34 @@
+package httpclient
+
+import "net/http"
+
+// WithHeaders returns a copy of client that sends the given headers with every
+// request, replacing any the request already has. The client is returned as is
+// when there are no headers.
+func WithHeaders(client *http.Client, headers http.Header) *http.Client {
+	if len(headers) == 0 {
+		return client
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	clone := *client
+	clone.Transport = &headerTransport{base: base, headers: headers.Clone()}
+	return &clone
+}
+
+// headerTransport sets fixed headers on each request.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
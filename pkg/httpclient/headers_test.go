@@ -0,0 +1,44 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	base := &http.Client{}
+	assert.Same(t, base, WithHeaders(base, nil))
+
+	client := WithHeaders(base, http.Header{
+		"Referer":    {"https://example.com/"},
+		"User-Agent": {"CustomAgent/1.0"},
+	})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "tvarr")
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "https://example.com/", received.Get("Referer"))
+	assert.Equal(t, "CustomAgent/1.0", received.Get("User-Agent"))
+	assert.Equal(t, "*/*", received.Get("Accept"))
+
+	// The caller's request is left untouched
+	assert.Equal(t, "tvarr", req.Header.Get("User-Agent"))
+	assert.Nil(t, base.Transport)
+}
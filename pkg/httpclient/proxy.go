@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrInvalidProxyURL is returned for proxy URLs that cannot be used.
+var ErrInvalidProxyURL = errors.New("invalid proxy URL")
+
+// ParseProxyURL parses an upstream proxy URL. The http, https, socks5 and
+// socks5h schemes are supported; credentials may be given as user info.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidProxyURL, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: host is required", ErrInvalidProxyURL)
+	}
+	return u, nil
+}
+
+// NewProxyTransport returns a copy of base, or of http.DefaultTransport if base
+// is nil, that sends all requests through the given proxy. An invalid proxy URL
+// fails every request rather than silently connecting directly.
+func NewProxyTransport(base *http.Transport, proxyURL string) *http.Transport {
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	u, err := ParseProxyURL(proxyURL)
+	if err != nil {
+		transport.Proxy = func(*http.Request) (*url.URL, error) {
+			return nil, err
+		}
+		return transport
+	}
+	transport.Proxy = http.ProxyURL(u)
+	return transport
+}
@@ -0,0 +1,89 @@
+package m3u
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Catch-up modes used in the catchup attribute.
+const (
+	// CatchupModeDefault means catchup-source is a complete archive URL template.
+	CatchupModeDefault = "default"
+	// CatchupModeAppend means catchup-source is appended to the stream URL.
+	CatchupModeAppend = "append"
+)
+
+// catchupPlaceholderRegex matches catch-up placeholders such as {utc}, ${start}
+// and {duration:60}.
+var catchupPlaceholderRegex = regexp.MustCompile(`\$?\{([A-Za-z]+)(?::(\d+))?\}`)
+
+// ResolveCatchupSource returns the full archive URL template for an entry's
+// catch-up attributes, or "" if the mode is not supported.
+func ResolveCatchupSource(mode, source, streamURL string) string {
+	if source == "" {
+		return ""
+	}
+	switch strings.ToLower(mode) {
+	case "", CatchupModeDefault:
+		return source
+	case CatchupModeAppend:
+		return streamURL + source
+	default:
+		return ""
+	}
+}
+
+// ExpandCatchupSource fills in the placeholders of a catch-up URL template for a
+// programme starting at start and lasting duration. It understands the
+// placeholders used by Kodi and TiviMate:
+//
+//   - {utc}, ${start}: start as a Unix timestamp
+//   - {utcend}, ${end}: end as a Unix timestamp
+//   - {lutc}, ${now}, ${timestamp}: the current Unix timestamp
+//   - {duration}, ${duration}: duration in seconds; {duration:N} divides by N, rounding up
+//   - {Y}, {m}, {d}, {H}, {M}, {S}: start date and time fields in loc
+//
+// Unknown placeholders are left unchanged. A nil loc means UTC.
+func ExpandCatchupSource(template string, start time.Time, duration time.Duration, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := start.In(loc)
+	seconds := int64(duration / time.Second)
+
+	return catchupPlaceholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		match := catchupPlaceholderRegex.FindStringSubmatch(placeholder)
+		name, arg := match[1], match[2]
+
+		switch name {
+		case "utc", "start":
+			return strconv.FormatInt(start.Unix(), 10)
+		case "utcend", "end":
+			return strconv.FormatInt(start.Add(duration).Unix(), 10)
+		case "lutc", "now", "timestamp":
+			return strconv.FormatInt(time.Now().Unix(), 10)
+		case "duration":
+			divisor, _ := strconv.ParseInt(arg, 10, 64)
+			if divisor <= 1 {
+				return strconv.FormatInt(seconds, 10)
+			}
+			return strconv.FormatInt((seconds+divisor-1)/divisor, 10)
+		case "Y":
+			return local.Format("2006")
+		case "m":
+			return local.Format("01")
+		case "d":
+			return local.Format("02")
+		case "H":
+			return local.Format("15")
+		case "M":
+			return local.Format("04")
+		case "S":
+			return local.Format("05")
+		default:
+			return placeholder
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package m3u
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandCatchupSource(t *testing.T) {
+	start := time.Date(2024, 6, 1, 20, 30, 0, 0, time.UTC)
+	duration := 90*time.Minute + 20*time.Second
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		loc      *time.Location
+		expected string
+	}{
+		{
+			name:     "unix placeholders",
+			template: "http://x/catchup?start={utc}&end={utcend}&duration={duration}",
+			expected: "http://x/catchup?start=1717273800&end=1717279220&duration=5420",
+		},
+		{
+			name:     "dollar placeholders",
+			template: "http://x/?s=${start}&e=${end}&d=${duration}",
+			expected: "http://x/?s=1717273800&e=1717279220&d=5420",
+		},
+		{
+			name:     "duration in minutes rounds up",
+			template: "{duration:60}",
+			expected: "91",
+		},
+		{
+			name:     "date fields in location",
+			template: "start={Y}-{m}-{d}:{H}-{M}-{S}",
+			loc:      london,
+			expected: "start=2024-06-01:21-30-00",
+		},
+		{
+			name:     "date fields default to UTC",
+			template: "{H}:{M}",
+			expected: "20:30",
+		},
+		{
+			name:     "unknown placeholders kept",
+			template: "http://x/{channel}/{utc}",
+			expected: "http://x/{channel}/1717273800",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExpandCatchupSource(tt.template, start, duration, tt.loc)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestResolveCatchupSource(t *testing.T) {
+	stream := "http://x/live/1.ts"
+
+	if got := ResolveCatchupSource("default", "http://x/archive?s={utc}", stream); got != "http://x/archive?s={utc}" {
+		t.Errorf("default mode: got %q", got)
+	}
+	if got := ResolveCatchupSource("", "http://x/archive", stream); got != "http://x/archive" {
+		t.Errorf("empty mode: got %q", got)
+	}
+	if got := ResolveCatchupSource("append", "?utc={utc}", stream); got != stream+"?utc={utc}" {
+		t.Errorf("append mode: got %q", got)
+	}
+	if got := ResolveCatchupSource("flussonic", "", stream); got != "" {
+		t.Errorf("unsupported mode: got %q", got)
+	}
+}
+
+func TestCatchupAttributes_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewWriter(&buf).WriteEntry(&Entry{
+		Title:         "News",
+		URL:           "http://x/live/1.ts",
+		Catchup:       CatchupModeDefault,
+		CatchupDays:   7,
+		CatchupSource: "http://x/catchup?start={utc}&duration={duration}",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `catchup="default" catchup-days="7" catchup-source="http://x/catchup?start={utc}&duration={duration}"`) {
+		t.Errorf("catch-up attributes missing from %q", buf.String())
+	}
+
+	var entries []*Entry
+	p := &Parser{OnEntry: func(e *Entry) error {
+		entries = append(entries, e)
+		return nil
+	}}
+	if err := p.Parse(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Catchup != CatchupModeDefault || e.CatchupDays != 7 || e.CatchupSource != "http://x/catchup?start={utc}&duration={duration}" {
+		t.Errorf("unexpected catch-up fields: %q %d %q", e.Catchup, e.CatchupDays, e.CatchupSource)
+	}
+	if len(e.Extra) != 0 {
+		t.Errorf("expected no extra attributes, got %v", e.Extra)
+	}
+}
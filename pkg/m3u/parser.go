@@ -6,8 +6,11 @@ import (
 	"bufio"
 	"compress/bzip2"
 	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/textproto"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -41,6 +44,19 @@ type Entry struct {
 	// URL is the stream URL.
 	URL string
 
+	// Catchup is the catch-up mode from the catchup attribute (e.g. "default", "append").
+	Catchup string
+
+	// CatchupDays is the number of days of archive from the catchup-days attribute.
+	CatchupDays int
+
+	// CatchupSource is the archive URL template from the catchup-source attribute.
+	CatchupSource string
+
+	// Headers are HTTP request headers the stream requires, keyed by canonical
+	// header name, from #EXTVLCOPT, #KODIPROP and #EXTHTTP lines.
+	Headers map[string]string
+
 	// Extra contains any additional attributes not explicitly parsed.
 	Extra map[string]string
 }
@@ -78,6 +94,7 @@ func (p *Parser) Parse(r io.Reader) error {
 	scanner.Buffer(buf, maxLineSize)
 
 	var currentEntry *Entry
+	var pendingHeaders map[string]string // Option lines seen before an entry's EXTINF
 	lineNum := 0
 	isExtM3U := false
 
@@ -103,10 +120,22 @@ func (p *Parser) Parse(r io.Reader) error {
 				p.handleError(lineNum, err)
 				continue
 			}
+			entry.Headers = pendingHeaders
+			pendingHeaders = nil
 			currentEntry = entry
 			continue
 		}
 
+		// Collect request headers from player option lines
+		if headers := parseHeaderLine(line); len(headers) > 0 {
+			if currentEntry != nil {
+				currentEntry.Headers = mergeHeaders(currentEntry.Headers, headers)
+			} else {
+				pendingHeaders = mergeHeaders(pendingHeaders, headers)
+			}
+			continue
+		}
+
 		// Skip other comment lines
 		if strings.HasPrefix(line, "#") {
 			continue
@@ -125,11 +154,13 @@ func (p *Parser) Parse(r io.Reader) error {
 				Duration: -1,
 				URL:      line,
 				Title:    extractTitleFromURL(line),
+				Headers:  pendingHeaders,
 			}
 			if err := p.OnEntry(entry); err != nil {
 				return fmt.Errorf("callback error at line %d: %w", lineNum, err)
 			}
 		}
+		pendingHeaders = nil
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -222,6 +253,12 @@ func (p *Parser) parseExtinf(line string) (*Entry, error) {
 			entry.GroupTitle = value
 		case "tvg-chno":
 			entry.ChannelNumber, _ = strconv.Atoi(value)
+		case "catchup":
+			entry.Catchup = value
+		case "catchup-days":
+			entry.CatchupDays, _ = strconv.Atoi(value)
+		case "catchup-source":
+			entry.CatchupSource = value
 		default:
 			entry.Extra[key] = value
 		}
@@ -230,6 +267,86 @@ func (p *Parser) parseExtinf(line string) (*Entry, error) {
 	return entry, nil
 }
 
+// vlcHeaderOptions maps the #EXTVLCOPT options that set request headers to the
+// header names.
+var vlcHeaderOptions = map[string]string{
+	"http-referrer":   "Referer",
+	"http-referer":    "Referer",
+	"http-user-agent": "User-Agent",
+}
+
+// kodiHeaderProperties are the #KODIPROP properties that hold request headers
+// as a URL-encoded query string.
+var kodiHeaderProperties = map[string]bool{
+	"inputstream.adaptive.stream_headers":   true,
+	"inputstream.adaptive.manifest_headers": true,
+	"inputstream.adaptive.common_headers":   true,
+}
+
+// parseHeaderLine extracts request headers from a player option line:
+//
+//	#EXTVLCOPT:http-referrer=https://example.com/
+//	#KODIPROP:inputstream.adaptive.stream_headers=Referer=https%3A%2F%2Fexample.com%2F&Origin=...
+//	#EXTHTTP:{"cookie":"session=abc"}
+//
+// It returns nil for other lines and options.
+func parseHeaderLine(line string) map[string]string {
+	headers := make(map[string]string)
+	switch {
+	case strings.HasPrefix(line, "#EXTVLCOPT:"):
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "#EXTVLCOPT:"), "=")
+		if name, known := vlcHeaderOptions[strings.ToLower(strings.TrimSpace(key))]; ok && known {
+			headers[name] = strings.TrimSpace(value)
+		}
+
+	case strings.HasPrefix(line, "#KODIPROP:"):
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "#KODIPROP:"), "=")
+		if !ok || !kodiHeaderProperties[strings.ToLower(strings.TrimSpace(key))] {
+			return nil
+		}
+		query, err := url.ParseQuery(strings.TrimSpace(value))
+		if err != nil {
+			return nil
+		}
+		for name, values := range query {
+			headers[name] = values[0]
+		}
+
+	case strings.HasPrefix(line, "#EXTHTTP:"):
+		var values map[string]string
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "#EXTHTTP:")), &values); err != nil {
+			return nil
+		}
+		for name, value := range values {
+			headers[name] = value
+		}
+	}
+
+	canonical := make(map[string]string, len(headers))
+	for name, value := range headers {
+		name = strings.TrimSpace(name)
+		if name == "" || value == "" {
+			continue
+		}
+		canonical[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+	if len(canonical) == 0 {
+		return nil
+	}
+	return canonical
+}
+
+// mergeHeaders adds headers to dst, allocating it if needed. Later values win.
+func mergeHeaders(dst, headers map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string, len(headers))
+	}
+	for name, value := range headers {
+		dst[name] = value
+	}
+	return dst
+}
+
 // findTitleStart finds the index of the comma that separates attributes from title.
 // It handles commas inside quoted values.
 func findTitleStart(s string) int {
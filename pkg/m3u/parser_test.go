@@ -130,6 +130,58 @@ http://example.com/stream.m3u8
 	}
 }
 
+func TestParser_RequestHeaders(t *testing.T) {
+	content := `#EXTM3U
+#EXTINF:-1 tvg-id="ch1",VLC Channel
+#EXTVLCOPT:http-referrer=https://example.com/player
+#EXTVLCOPT:http-user-agent=Mozilla/5.0
+#EXTVLCOPT:network-caching=1000
+http://example.com/ch1.m3u8
+#KODIPROP:inputstream.adaptive.stream_headers=origin=https%3A%2F%2Fexample.com&Referer=https%3A%2F%2Fexample.com%2F
+#EXTINF:-1 tvg-id="ch2",Kodi Channel
+http://example.com/ch2.m3u8
+#EXTINF:-1 tvg-id="ch3",HTTP Channel
+#EXTHTTP:{"cookie":"session=abc"}
+http://example.com/ch3.m3u8
+#EXTINF:-1 tvg-id="ch4",Plain Channel
+http://example.com/ch4.m3u8
+`
+
+	var entries []*Entry
+	p := &Parser{
+		OnEntry: func(entry *Entry) error {
+			entries = append(entries, entry)
+			return nil
+		},
+	}
+
+	if err := p.Parse(strings.NewReader(content)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	vlc := entries[0].Headers
+	if len(vlc) != 2 || vlc["Referer"] != "https://example.com/player" || vlc["User-Agent"] != "Mozilla/5.0" {
+		t.Errorf("unexpected VLC headers: %v", vlc)
+	}
+
+	// Option lines before an EXTINF belong to the entry that follows
+	kodi := entries[1].Headers
+	if len(kodi) != 2 || kodi["Origin"] != "https://example.com" || kodi["Referer"] != "https://example.com/" {
+		t.Errorf("unexpected Kodi headers: %v", kodi)
+	}
+
+	if cookie := entries[2].Headers["Cookie"]; cookie != "session=abc" {
+		t.Errorf("expected cookie 'session=abc', got %q", cookie)
+	}
+
+	if entries[3].Headers != nil {
+		t.Errorf("expected no headers, got %v", entries[3].Headers)
+	}
+}
+
 func TestParser_PositiveDuration(t *testing.T) {
 	content := `#EXTM3U
 #EXTINF:180 tvg-id="song1",Song Title
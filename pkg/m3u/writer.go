@@ -3,6 +3,8 @@ package m3u
 import (
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -55,10 +57,19 @@ func (w *Writer) WriteEntry(entry *Entry) error {
 	if entry.ChannelNumber > 0 {
 		attrs = append(attrs, fmt.Sprintf(`tvg-chno="%d"`, entry.ChannelNumber))
 	}
+	if entry.Catchup != "" {
+		attrs = append(attrs, fmt.Sprintf(`catchup="%s"`, escapeQuotes(entry.Catchup)))
+	}
+	if entry.CatchupDays > 0 {
+		attrs = append(attrs, fmt.Sprintf(`catchup-days="%d"`, entry.CatchupDays))
+	}
+	if entry.CatchupSource != "" {
+		attrs = append(attrs, fmt.Sprintf(`catchup-source="%s"`, escapeQuotes(entry.CatchupSource)))
+	}
 
-	// Add any extra attributes
-	for k, v := range entry.Extra {
-		attrs = append(attrs, fmt.Sprintf(`%s="%s"`, k, escapeQuotes(v)))
+	// Add any extra attributes, in a stable order
+	for _, k := range slices.Sorted(maps.Keys(entry.Extra)) {
+		attrs = append(attrs, fmt.Sprintf(`%s="%s"`, k, escapeQuotes(entry.Extra[k])))
 	}
 
 	// Build the EXTINF line
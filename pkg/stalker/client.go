@@ -0,0 +1,368 @@
+package stalker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default configuration values.
+const (
+	DefaultTimeout = 2 * time.Minute
+
+	// DefaultUserAgent identifies the client as a MAG set-top box; many portals
+	// reject other user agents.
+	DefaultUserAgent = "Mozilla/5.0 (QtEmbedded; U; Linux; C) AppleWebKit/533.3 (KHTML, like Gecko) MAG200 stbapp ver: 2 rev: 250 Safari/533.3"
+
+	// DefaultTimezone is sent to the portal in the session cookie.
+	DefaultTimezone = "UTC"
+
+	// Portal endpoint paths.
+	pathPortal       = "/portal.php"
+	pathStalkerLoad  = "/server/load.php"
+	pathStalkerRoot  = "/stalker_portal"
+	pathClientSuffix = "/c"
+
+	// Request types.
+	typeSTB = "stb"
+	typeITV = "itv"
+
+	// Actions.
+	actionHandshake      = "handshake"
+	actionGetProfile     = "get_profile"
+	actionGetGenres      = "get_genres"
+	actionGetAllChannels = "get_all_channels"
+	actionCreateLink     = "create_link"
+	actionGetEPGInfo     = "get_epg_info"
+
+	// authFailedBody is returned (with status 200) when the token is invalid.
+	authFailedBody = "Authorization failed"
+
+	maxErrorBodyReadSize = 1024
+)
+
+// HTTP header constants.
+const (
+	headerUserAgent     = "User-Agent"
+	headerXUserAgent    = "X-User-Agent"
+	headerAuthorization = "Authorization"
+	headerCookie        = "Cookie"
+	xUserAgentValue     = "Model: MAG250; Link: WiFi"
+)
+
+// Client errors.
+var (
+	// ErrNoToken indicates the portal did not return a token from the handshake.
+	ErrNoToken = errors.New("portal returned no token")
+
+	// ErrAuthFailed indicates the portal rejected the session.
+	ErrAuthFailed = errors.New("portal authorization failed")
+
+	// ErrNoLink indicates the portal did not return a stream URL.
+	ErrNoLink = errors.New("portal returned no stream link")
+)
+
+// Client is a Stalker / Ministra portal client. It is safe for concurrent use.
+type Client struct {
+	// Endpoint is the portal API endpoint (e.g., "http://example.com/portal.php").
+	Endpoint string
+
+	// MAC is the device MAC address used to authenticate.
+	MAC string
+
+	// Timezone is the timezone reported to the portal.
+	Timezone string
+
+	// HTTPClient is the standard HTTP client used for requests.
+	// If nil, a default client with DefaultTimeout is used.
+	HTTPClient *http.Client
+
+	// UserAgent is the User-Agent header sent with requests.
+	UserAgent string
+
+	mu    sync.Mutex
+	token string
+}
+
+// ClientOption is a function that configures a Client.
+type ClientOption func(*Client)
+
+// NewClient creates a new portal client for a portal URL and MAC address.
+// The portal URL may be the address shown on the device (e.g.,
+// "http://example.com/c/" or "http://example.com/stalker_portal/c/") or the
+// API endpoint itself.
+func NewClient(portalURL, mac string, opts ...ClientOption) *Client {
+	c := &Client{
+		Endpoint: ResolveEndpoint(portalURL),
+		MAC:      strings.ToUpper(strings.TrimSpace(mac)),
+		Timezone: DefaultTimezone,
+		HTTPClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+		UserAgent: DefaultUserAgent,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithHTTPClient sets a custom standard library HTTP client.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = client
+	}
+}
+
+// WithUserAgent sets a custom User-Agent header.
+func WithUserAgent(ua string) ClientOption {
+	return func(c *Client) {
+		c.UserAgent = ua
+	}
+}
+
+// WithTimezone sets the timezone reported to the portal.
+func WithTimezone(tz string) ClientOption {
+	return func(c *Client) {
+		c.Timezone = tz
+	}
+}
+
+// ResolveEndpoint returns the API endpoint for a portal URL.
+func ResolveEndpoint(portalURL string) string {
+	base := strings.TrimSpace(portalURL)
+	if strings.HasSuffix(base, ".php") {
+		return base
+	}
+	base = strings.TrimSuffix(base, "/")
+	base = strings.TrimSuffix(base, pathClientSuffix)
+	if strings.HasSuffix(base, pathStalkerRoot) {
+		return base + pathStalkerLoad
+	}
+	return base + pathPortal
+}
+
+// requestURL builds the endpoint URL for a request type, action and parameters.
+func (c *Client) requestURL(reqType, action string, params url.Values) string {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	q.Set("type", reqType)
+	q.Set("action", action)
+	q.Set("JsHttpRequest", "1-xml")
+	return c.Endpoint + "?" + q.Encode()
+}
+
+// get performs a request with the given token and returns the response body.
+func (c *Client) get(ctx context.Context, requestURL, token string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	if c.UserAgent != "" {
+		req.Header.Set(headerUserAgent, c.UserAgent)
+	}
+	req.Header.Set(headerXUserAgent, xUserAgentValue)
+	req.Header.Set(headerCookie, fmt.Sprintf("mac=%s; stb_lang=en; timezone=%s",
+		url.QueryEscape(c.MAC), url.QueryEscape(c.Timezone)))
+	if token != "" {
+		req.Header.Set(headerAuthorization, "Bearer "+token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, ErrAuthFailed
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyReadSize))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte(authFailedBody)) {
+		return nil, ErrAuthFailed
+	}
+	return body, nil
+}
+
+// Authenticate performs the handshake and activates the session by reading the
+// device profile. Other methods authenticate automatically when needed.
+func (c *Client) Authenticate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authenticateLocked(ctx)
+}
+
+// authenticateLocked obtains a new token. c.mu must be held.
+func (c *Client) authenticateLocked(ctx context.Context) error {
+	c.token = ""
+
+	body, err := c.get(ctx, c.requestURL(typeSTB, actionHandshake, url.Values{"token": {""}}), "")
+	if err != nil {
+		return fmt.Errorf("handshake: %w", err)
+	}
+	var hs response[handshakeResult]
+	if err := json.Unmarshal(body, &hs); err != nil {
+		return fmt.Errorf("decoding handshake: %w", err)
+	}
+	if hs.JS.Token == "" {
+		return ErrNoToken
+	}
+
+	// The profile request activates the token on most portals.
+	if _, err := c.get(ctx, c.requestURL(typeSTB, actionGetProfile, url.Values{"hd": {"1"}}), hs.JS.Token); err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+
+	c.token = hs.JS.Token
+	return nil
+}
+
+// currentToken returns the session token, authenticating first if there is none.
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == "" {
+		if err := c.authenticateLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return c.token, nil
+}
+
+// refreshToken authenticates again unless another request already replaced the
+// stale token.
+func (c *Client) refreshToken(ctx context.Context, stale string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != stale && c.token != "" {
+		return c.token, nil
+	}
+	if err := c.authenticateLocked(ctx); err != nil {
+		return "", err
+	}
+	return c.token, nil
+}
+
+// doRequest performs an authenticated request and decodes the "js" field of the
+// response into target. A rejected token is renewed once.
+func (c *Client) doRequest(ctx context.Context, reqType, action string, params url.Values, target any) error {
+	token, err := c.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	requestURL := c.requestURL(reqType, action, params)
+	body, err := c.get(ctx, requestURL, token)
+	if errors.Is(err, ErrAuthFailed) {
+		if token, err = c.refreshToken(ctx, token); err != nil {
+			return err
+		}
+		body, err = c.get(ctx, requestURL, token)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, &response[any]{JS: target}); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// GetProfile retrieves the device profile.
+func (c *Client) GetProfile(ctx context.Context) (*Profile, error) {
+	var profile Profile
+	if err := c.doRequest(ctx, typeSTB, actionGetProfile, url.Values{"hd": {"1"}}, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetGenres retrieves the channel genres.
+func (c *Client) GetGenres(ctx context.Context) ([]Genre, error) {
+	var genres []Genre
+	if err := c.doRequest(ctx, typeITV, actionGetGenres, nil, &genres); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// GetAllChannels retrieves all live channels.
+func (c *Client) GetAllChannels(ctx context.Context) ([]Channel, error) {
+	var list channelList
+	if err := c.doRequest(ctx, typeITV, actionGetAllChannels, nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Data, nil
+}
+
+// CreateLink resolves a channel command to a playable stream URL. Links are
+// short-lived and should be resolved immediately before playback.
+func (c *Client) CreateLink(ctx context.Context, cmd string) (string, error) {
+	params := url.Values{
+		"cmd":            {cmd},
+		"series":         {""},
+		"forced_storage": {"0"},
+		"disable_ad":     {"0"},
+		"download":       {"0"},
+	}
+	var link linkResult
+	if err := c.doRequest(ctx, typeITV, actionCreateLink, params, &link); err != nil {
+		return "", err
+	}
+	if link.Error != "" {
+		return "", fmt.Errorf("%w: %s", ErrNoLink, link.Error)
+	}
+	streamURL := StreamURLFromCmd(link.Cmd)
+	if streamURL == "" {
+		return "", ErrNoLink
+	}
+	return streamURL, nil
+}
+
+// GetEPGInfo retrieves the EPG of all channels for the given number of hours,
+// keyed by channel ID.
+func (c *Client) GetEPGInfo(ctx context.Context, periodHours int) (map[string][]EPGEntry, error) {
+	var info epgInfo
+	params := url.Values{"period": {strconv.Itoa(periodHours)}}
+	if err := c.doRequest(ctx, typeITV, actionGetEPGInfo, params, &info); err != nil {
+		return nil, err
+	}
+
+	data := bytes.TrimSpace(info.Data)
+	if len(data) == 0 || data[0] != '{' {
+		return map[string][]EPGEntry{}, nil
+	}
+	var epg map[string][]EPGEntry
+	if err := json.Unmarshal(data, &epg); err != nil {
+		return nil, fmt.Errorf("decoding EPG: %w", err)
+	}
+	return epg, nil
+}
@@ -0,0 +1,200 @@
+package stalker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// fakePortal is a minimal portal that issues numbered tokens.
+type fakePortal struct {
+	t          *testing.T
+	handshakes atomic.Int32
+	rejectNext atomic.Bool
+}
+
+func (p *fakePortal) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if cookie := r.Header.Get("Cookie"); cookie != "mac=00%3A1A%3A79%3A12%3A34%3A56; stb_lang=en; timezone=UTC" {
+		p.t.Errorf("unexpected cookie: %q", cookie)
+	}
+
+	if q.Get("type") == "stb" && q.Get("action") == "handshake" {
+		n := p.handshakes.Add(1)
+		fmt.Fprintf(w, `{"js":{"token":"token-%d","random":"x"}}`, n)
+		return
+	}
+
+	want := fmt.Sprintf("Bearer token-%d", p.handshakes.Load())
+	if r.Header.Get("Authorization") != want || p.rejectNext.CompareAndSwap(true, false) {
+		w.Write([]byte("Authorization failed."))
+		return
+	}
+
+	switch q.Get("action") {
+	case "get_profile":
+		w.Write([]byte(`{"js":{"id":7,"name":"box","status":0}}`))
+	case "get_genres":
+		w.Write([]byte(`{"js":[{"id":"*","title":"All"},{"id":3,"title":"News"}]}`))
+	case "get_all_channels":
+		w.Write([]byte(`{"js":{"total_items":2,"data":[
+			{"id":"101","name":"News One","number":"1","cmd":"ffrt http://localhost/ch/101_","logo":"http://logos/1.png","tv_genre_id":"3","xmltv_id":"news1.uk","censored":0},
+			{"id":102,"name":"Late","number":2,"cmd":"ffmpeg http://localhost/ch/102_","tv_genre_id":"9","censored":"1"}
+		]}}`))
+	case "create_link":
+		if q.Get("cmd") != "ffrt http://localhost/ch/101_" {
+			w.Write([]byte(`{"js":{"cmd":"","error":"nothing_to_play"}}`))
+			return
+		}
+		w.Write([]byte(`{"js":{"id":"1","cmd":"ffrt http://cdn.example.com/live/101.ts?token=abc"}}`))
+	case "get_epg_info":
+		if q.Get("period") != "24" {
+			w.Write([]byte(`{"js":{"data":[]}}`))
+			return
+		}
+		w.Write([]byte(`{"js":{"data":{"101":[
+			{"id":"1","ch_id":"101","name":"Headlines","descr":"The news","start_timestamp":1700000000,"stop_timestamp":"1700001800"}
+		]}}}`))
+	default:
+		p.t.Errorf("unexpected action: %s", q.Get("action"))
+	}
+}
+
+func newTestClient(t *testing.T) (*Client, *fakePortal) {
+	t.Helper()
+	portal := &fakePortal{t: t}
+	server := httptest.NewServer(portal)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL+"/c/", "00:1a:79:12:34:56"), portal
+}
+
+func TestResolveEndpoint(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"http://example.com/c/", "http://example.com/portal.php"},
+		{"http://example.com:8080", "http://example.com:8080/portal.php"},
+		{"http://example.com/stalker_portal/c/", "http://example.com/stalker_portal/server/load.php"},
+		{"http://example.com/stalker_portal", "http://example.com/stalker_portal/server/load.php"},
+		{"http://example.com/custom/load.php", "http://example.com/custom/load.php"},
+	}
+	for _, tt := range tests {
+		if got := ResolveEndpoint(tt.in); got != tt.want {
+			t.Errorf("ResolveEndpoint(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStreamURLFromCmd(t *testing.T) {
+	tests := map[string]string{
+		"ffrt http://host/ch/1_":  "http://host/ch/1_",
+		"ffmpeg http://host/a.ts": "http://host/a.ts",
+		"http://host/direct.m3u8": "http://host/direct.m3u8",
+		"  ffrt  http://host/x  ": "http://host/x",
+		"":                        "",
+	}
+	for in, want := range tests {
+		if got := StreamURLFromCmd(in); got != want {
+			t.Errorf("StreamURLFromCmd(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestClient_GetAllChannels(t *testing.T) {
+	client, portal := newTestClient(t)
+
+	channels, err := client.GetAllChannels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if portal.handshakes.Load() != 1 {
+		t.Errorf("expected 1 handshake, got %d", portal.handshakes.Load())
+	}
+	if len(channels) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(channels))
+	}
+	if channels[0].ID.String() != "101" || channels[0].Number.Int() != 1 || channels[0].XMLTVID != "news1.uk" {
+		t.Errorf("unexpected first channel: %+v", channels[0])
+	}
+	if channels[0].IsAdult() || !channels[1].IsAdult() {
+		t.Error("expected only the second channel to be adult")
+	}
+	if channels[1].ID.String() != "102" || channels[1].Number.Int() != 2 {
+		t.Errorf("unexpected second channel: %+v", channels[1])
+	}
+}
+
+func TestClient_GetGenres(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	genres, err := client.GetGenres(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(genres) != 2 || genres[1].ID.String() != "3" || genres[1].Title != "News" {
+		t.Errorf("unexpected genres: %+v", genres)
+	}
+}
+
+func TestClient_CreateLink(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	streamURL, err := client.CreateLink(context.Background(), "ffrt http://localhost/ch/101_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if streamURL != "http://cdn.example.com/live/101.ts?token=abc" {
+		t.Errorf("unexpected stream URL: %q", streamURL)
+	}
+
+	_, err = client.CreateLink(context.Background(), "ffrt http://localhost/ch/999_")
+	if !errors.Is(err, ErrNoLink) {
+		t.Errorf("expected ErrNoLink, got %v", err)
+	}
+}
+
+func TestClient_ReauthenticatesOnRejectedToken(t *testing.T) {
+	client, portal := newTestClient(t)
+
+	if err := client.Authenticate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	portal.rejectNext.Store(true)
+
+	if _, err := client.GetGenres(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if portal.handshakes.Load() != 2 {
+		t.Errorf("expected 2 handshakes, got %d", portal.handshakes.Load())
+	}
+}
+
+func TestClient_GetEPGInfo(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	epg, err := client.GetEPGInfo(context.Background(), 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := epg["101"]
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Name != "Headlines" || entries[0].StartTime().Unix() != 1700000000 || entries[0].StopTime().Unix() != 1700001800 {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+
+	// Portals without EPG return an empty array
+	epg, err = client.GetEPGInfo(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(epg) != 0 {
+		t.Errorf("expected empty EPG, got %d channels", len(epg))
+	}
+}
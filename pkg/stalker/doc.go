@@ -0,0 +1,44 @@
+// Package stalker provides a Go client for Stalker / Ministra middleware portals.
+//
+// Stalker (now Ministra) portals serve set-top boxes such as the MAG series.
+// Devices authenticate by MAC address: a handshake returns a bearer token that
+// is sent with every subsequent request, alongside a cookie carrying the MAC.
+//
+// # Basic Usage
+//
+//	client := stalker.NewClient("http://portal.example.com/c/", "00:1A:79:12:34:56")
+//
+//	// Authenticate (performed automatically by the first request)
+//	err := client.Authenticate(ctx)
+//
+//	// List channel genres and channels
+//	genres, err := client.GetGenres(ctx)
+//	channels, err := client.GetAllChannels(ctx)
+//
+//	// Resolve a playable URL for a channel
+//	streamURL, err := client.CreateLink(ctx, channels[0].Cmd)
+//
+//	// Fetch the EPG for the next 24 hours, keyed by channel ID
+//	epg, err := client.GetEPGInfo(ctx, 24)
+//
+// # Stream Links
+//
+// Channel commands (the "cmd" field, e.g. "ffrt http://localhost/ch/1234_")
+// are not directly playable. Portals issue short-lived stream URLs through
+// create_link, so links should be resolved immediately before playback.
+//
+// # API Endpoints
+//
+// Portals expose a single endpoint, either {base}/portal.php or
+// {base}/stalker_portal/server/load.php, addressed by type and action:
+//
+//	{endpoint}?type={type}&action={action}&JsHttpRequest=1-xml
+//
+// Used actions:
+//   - type=stb&action=handshake: Obtain a session token
+//   - type=stb&action=get_profile: Activate the session and read the profile
+//   - type=itv&action=get_genres: List channel genres
+//   - type=itv&action=get_all_channels: List all channels
+//   - type=itv&action=create_link: Resolve a channel command to a stream URL
+//   - type=itv&action=get_epg_info: Get EPG for all channels (period in hours)
+package stalker
@@ -0,0 +1,154 @@
+package stalker
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// response is the envelope of all portal responses.
+type response[T any] struct {
+	JS T `json:"js"`
+}
+
+// handshakeResult is the result of the handshake action.
+type handshakeResult struct {
+	Token  string `json:"token"`
+	Random string `json:"random"`
+}
+
+// Profile contains the device profile returned by get_profile.
+type Profile struct {
+	ID       FlexString `json:"id"`
+	Name     string     `json:"name"`
+	Login    string     `json:"login"`
+	MAC      string     `json:"mac"`
+	Status   FlexInt    `json:"status"`
+	Blocked  FlexString `json:"blocked"`
+	Timezone string     `json:"default_timezone"`
+}
+
+// Genre is a channel category.
+type Genre struct {
+	ID    FlexString `json:"id"`
+	Title string     `json:"title"`
+}
+
+// channelList is the result of get_all_channels.
+type channelList struct {
+	Data []Channel `json:"data"`
+}
+
+// Channel is a live TV channel.
+type Channel struct {
+	ID                FlexString `json:"id"`
+	Name              string     `json:"name"`
+	Number            FlexInt    `json:"number"`
+	Cmd               string     `json:"cmd"`
+	Logo              string     `json:"logo"`
+	GenreID           FlexString `json:"tv_genre_id"`
+	XMLTVID           string     `json:"xmltv_id"`
+	Censored          FlexInt    `json:"censored"`
+	TVArchive         FlexInt    `json:"tv_archive"`
+	TVArchiveDuration FlexInt    `json:"tv_archive_duration"`
+}
+
+// IsAdult returns true if the portal marks the channel as censored.
+func (c *Channel) IsAdult() bool {
+	return c.Censored.Int() == 1
+}
+
+// linkResult is the result of create_link.
+type linkResult struct {
+	Cmd   string `json:"cmd"`
+	Error string `json:"error"`
+}
+
+// EPGEntry is a programme from get_epg_info.
+type EPGEntry struct {
+	ID             FlexString `json:"id"`
+	ChannelID      FlexString `json:"ch_id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"descr"`
+	Category       string     `json:"category"`
+	StartTimestamp FlexInt    `json:"start_timestamp"`
+	StopTimestamp  FlexInt    `json:"stop_timestamp"`
+}
+
+// StartTime returns the programme start time.
+func (e *EPGEntry) StartTime() time.Time {
+	return time.Unix(e.StartTimestamp.Int(), 0).UTC()
+}
+
+// StopTime returns the programme end time.
+func (e *EPGEntry) StopTime() time.Time {
+	return time.Unix(e.StopTimestamp.Int(), 0).UTC()
+}
+
+// epgInfo is the result of get_epg_info. Data is an object keyed by channel ID,
+// or an empty array when the portal has no EPG.
+type epgInfo struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// StreamURLFromCmd extracts the stream URL from a channel command, which may be
+// prefixed by a player name such as "ffmpeg " or "ffrt ".
+func StreamURLFromCmd(cmd string) string {
+	cmd = strings.TrimSpace(cmd)
+	if i := strings.LastIndex(cmd, " "); i >= 0 {
+		return cmd[i+1:]
+	}
+	return cmd
+}
+
+// FlexInt is an integer that may be encoded as a JSON number or string.
+type FlexInt int64
+
+// Int returns the value as int64.
+func (f FlexInt) Int() int64 {
+	return int64(f)
+}
+
+// UnmarshalJSON accepts numbers, numeric strings, empty strings and null.
+func (f *FlexInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*f = FlexInt(n)
+		return nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		*f = 0
+		return nil
+	}
+	*f = FlexInt(n)
+	return nil
+}
+
+// FlexString is a string that may be encoded as a JSON string or number.
+type FlexString string
+
+// String returns the value as string.
+func (f FlexString) String() string {
+	return string(f)
+}
+
+// UnmarshalJSON accepts strings, numbers and null.
+func (f *FlexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*f = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = FlexString(s)
+		return nil
+	}
+	*f = FlexString(strings.TrimSpace(string(data)))
+	return nil
+}
@@ -21,11 +21,19 @@ const (
 	pathPlayerAPI = "/player_api.php"
 	pathXMLTV     = "/xmltv.php"
 	pathLive      = "/live"
+	pathMovie     = "/movie"
+	pathSeries    = "/series"
+	pathTimeshift = "/streaming/timeshift.php"
 
 	// API actions.
-	actionGetLiveCategories  = "get_live_categories"
-	actionGetLiveStreams     = "get_live_streams"
-	actionGetSimpleDataTable = "get_simple_data_table"
+	actionGetLiveCategories   = "get_live_categories"
+	actionGetLiveStreams      = "get_live_streams"
+	actionGetVODCategories    = "get_vod_categories"
+	actionGetVODStreams       = "get_vod_streams"
+	actionGetSeriesCategories = "get_series_categories"
+	actionGetSeries           = "get_series"
+	actionGetSeriesInfo       = "get_series_info"
+	actionGetSimpleDataTable  = "get_simple_data_table"
 
 	// Query parameter names.
 	paramUsername   = "username"
@@ -33,9 +41,11 @@ const (
 	paramAction     = "action"
 	paramCategoryID = "category_id"
 	paramStreamID   = "stream_id"
+	paramSeriesID   = "series_id"
 
 	// Default values.
 	defaultExtensionTS   = "ts"
+	defaultExtensionMP4  = "mp4"
 	maxErrorBodyReadSize = 1024
 )
 
@@ -184,18 +194,69 @@ type StreamsOptions struct {
 
 // GetLiveStreams retrieves live streams, optionally filtered by category.
 func (c *Client) GetLiveStreams(ctx context.Context, opts *StreamsOptions) ([]Stream, error) {
-	params := make(map[string]string)
-	if opts != nil && opts.CategoryID != "" {
-		params[paramCategoryID] = opts.CategoryID
+	var streams []Stream
+	if err := c.doRequest(ctx, c.apiURL(actionGetLiveStreams, categoryParams(opts)), &streams); err != nil {
+		return nil, err
 	}
+	return streams, nil
+}
 
-	var streams []Stream
-	if err := c.doRequest(ctx, c.apiURL(actionGetLiveStreams, params), &streams); err != nil {
+// GetVODCategories retrieves all video on demand categories.
+func (c *Client) GetVODCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	if err := c.doRequest(ctx, c.apiURL(actionGetVODCategories, nil), &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetVODStreams retrieves video on demand items, optionally filtered by category.
+func (c *Client) GetVODStreams(ctx context.Context, opts *StreamsOptions) ([]VODStream, error) {
+	var streams []VODStream
+	if err := c.doRequest(ctx, c.apiURL(actionGetVODStreams, categoryParams(opts)), &streams); err != nil {
 		return nil, err
 	}
 	return streams, nil
 }
 
+// GetSeriesCategories retrieves all series categories.
+func (c *Client) GetSeriesCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	if err := c.doRequest(ctx, c.apiURL(actionGetSeriesCategories, nil), &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetSeries retrieves series, optionally filtered by category.
+func (c *Client) GetSeries(ctx context.Context, opts *StreamsOptions) ([]Series, error) {
+	var series []Series
+	if err := c.doRequest(ctx, c.apiURL(actionGetSeries, categoryParams(opts)), &series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// GetSeriesInfo retrieves the details, seasons and episodes of a series.
+func (c *Client) GetSeriesInfo(ctx context.Context, seriesID int) (*SeriesInfo, error) {
+	params := map[string]string{paramSeriesID: fmt.Sprintf("%d", seriesID)}
+
+	var info SeriesInfo
+	if err := c.doRequest(ctx, c.apiURL(actionGetSeriesInfo, params), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// categoryParams returns the query parameters for a category-filtered listing.
+func categoryParams(opts *StreamsOptions) map[string]string {
+	params := make(map[string]string)
+	if opts != nil && opts.CategoryID != "" {
+		params[paramCategoryID] = opts.CategoryID
+	}
+	return params
+}
+
 // GetFullEPG retrieves the full EPG data for a stream.
 func (c *Client) GetFullEPG(ctx context.Context, streamID int) ([]EPGListing, error) {
 	params := map[string]string{paramStreamID: fmt.Sprintf("%d", streamID)}
@@ -256,3 +317,75 @@ func (c *Client) GetLiveStreamURL(streamID int, extension string) string {
 	return fmt.Sprintf("%s%s/%s/%s/%d.%s",
 		c.BaseURL, pathLive, c.Username, c.Password, streamID, extension)
 }
+
+// GetVODStreamURL returns the URL for a video on demand item.
+// The extension is the item's container extension (e.g. mp4, mkv).
+func (c *Client) GetVODStreamURL(streamID int, extension string) string {
+	if extension == "" {
+		extension = defaultExtensionMP4
+	}
+	return fmt.Sprintf("%s%s/%s/%s/%d.%s",
+		c.BaseURL, pathMovie, c.Username, c.Password, streamID, extension)
+}
+
+// GetSeriesEpisodeURL returns the URL for a series episode.
+// The extension is the episode's container extension (e.g. mp4, mkv).
+func (c *Client) GetSeriesEpisodeURL(episodeID int, extension string) string {
+	if extension == "" {
+		extension = defaultExtensionMP4
+	}
+	return fmt.Sprintf("%s%s/%s/%s/%d.%s",
+		c.BaseURL, pathSeries, c.Username, c.Password, episodeID, extension)
+}
+
+// GetTimeshiftURLTemplate returns the catch-up URL template for a live stream's archive.
+// The programme start ({Y}-{m}-{d}:{H}-{M}, in the server's timezone) and duration
+// in minutes ({duration:60}) are placeholders filled in by m3u.ExpandCatchupSource.
+func (c *Client) GetTimeshiftURLTemplate(streamID int) string {
+	return fmt.Sprintf("%s%s?%s=%s&%s=%s&stream=%d&start={Y}-{m}-{d}:{H}-{M}&duration={duration:60}",
+		c.BaseURL, pathTimeshift,
+		paramUsername, url.QueryEscape(c.Username),
+		paramPassword, url.QueryEscape(c.Password),
+		streamID)
+}
+
+// ReplaceCredentials rewrites a live, VOD, series or timeshift URL built for one
+// account so it uses another account on the same server. It returns false, and
+// the URL unchanged, if the URL does not carry the original credentials.
+func ReplaceCredentials(streamURL, username, password, newUsername, newPassword string) (string, bool) {
+	if username == "" {
+		return streamURL, false
+	}
+	base, query, hasQuery := strings.Cut(streamURL, "?")
+
+	// Credentials as path segments: /live/{username}/{password}/{id}.{ext}
+	segments := "/" + username + "/" + password + "/"
+	if i := strings.LastIndex(base, segments); i >= 0 {
+		rewritten := base[:i] + "/" + newUsername + "/" + newPassword + "/" + base[i+len(segments):]
+		if hasQuery {
+			rewritten += "?" + query
+		}
+		return rewritten, true
+	}
+
+	// Credentials as query parameters, keeping the order and any placeholders
+	if !hasQuery {
+		return streamURL, false
+	}
+	params := strings.Split(query, "&")
+	replaced := 0
+	for i, param := range params {
+		switch param {
+		case paramUsername + "=" + url.QueryEscape(username):
+			params[i] = paramUsername + "=" + url.QueryEscape(newUsername)
+			replaced++
+		case paramPassword + "=" + url.QueryEscape(password):
+			params[i] = paramPassword + "=" + url.QueryEscape(newPassword)
+			replaced++
+		}
+	}
+	if replaced != 2 {
+		return streamURL, false
+	}
+	return base + "?" + strings.Join(params, "&"), true
+}
@@ -163,6 +163,106 @@ func TestClient_GetLiveStreams_WithCategory(t *testing.T) {
 	}
 }
 
+func TestClient_GetVODStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("action") {
+		case "get_vod_categories":
+			json.NewEncoder(w).Encode([]Category{{CategoryID: "10", CategoryName: "Action"}})
+		case "get_vod_streams":
+			if r.URL.Query().Get("category_id") != "10" {
+				t.Errorf("unexpected category_id: %s", r.URL.Query().Get("category_id"))
+			}
+			// Providers encode numbers inconsistently
+			w.Write([]byte(`[{"stream_id":"42","name":"Heat","rating":"7.9","category_id":10,"container_extension":"mkv"}]`))
+		default:
+			t.Errorf("unexpected action: %s", r.URL.Query().Get("action"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user", "pass")
+	categories, err := client.GetVODCategories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories) != 1 || categories[0].CategoryName != "Action" {
+		t.Errorf("unexpected categories: %+v", categories)
+	}
+
+	streams, err := client.GetVODStreams(context.Background(), &StreamsOptions{CategoryID: "10"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(streams) != 1 {
+		t.Fatalf("expected 1 stream, got %d", len(streams))
+	}
+	if streams[0].StreamID.Int() != 42 {
+		t.Errorf("expected stream ID 42, got %d", streams[0].StreamID.Int())
+	}
+	if streams[0].Rating.Float() != 7.9 {
+		t.Errorf("expected rating 7.9, got %v", streams[0].Rating.Float())
+	}
+	if streams[0].CategoryID.String() != "10" {
+		t.Errorf("expected category '10', got %q", streams[0].CategoryID.String())
+	}
+	if streams[0].ContainerExtension != "mkv" {
+		t.Errorf("expected extension 'mkv', got %q", streams[0].ContainerExtension)
+	}
+}
+
+func TestClient_GetSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("action") {
+		case "get_series_categories":
+			json.NewEncoder(w).Encode([]Category{{CategoryID: "20", CategoryName: "Drama"}})
+		case "get_series":
+			w.Write([]byte(`[{"series_id":7,"name":"The Wire","genre":"Crime","last_modified":"1700000000","category_id":"20"}]`))
+		case "get_series_info":
+			if r.URL.Query().Get("series_id") != "7" {
+				t.Errorf("unexpected series_id: %s", r.URL.Query().Get("series_id"))
+			}
+			w.Write([]byte(`{"info":{"name":"The Wire"},"episodes":{"1":[` +
+				`{"id":"701","episode_num":1,"title":"The Target","container_extension":"mp4","season":1},` +
+				`{"id":"702","episode_num":"2","title":"The Detail","container_extension":"mp4","season":"1"}]}}`))
+		default:
+			t.Errorf("unexpected action: %s", r.URL.Query().Get("action"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "user", "pass")
+	categories, err := client.GetSeriesCategories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories) != 1 || categories[0].CategoryName != "Drama" {
+		t.Errorf("unexpected categories: %+v", categories)
+	}
+
+	series, err := client.GetSeries(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(series) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(series))
+	}
+	if series[0].LastModified.Int() != 1700000000 {
+		t.Errorf("expected last_modified 1700000000, got %d", series[0].LastModified.Int())
+	}
+
+	info, err := client.GetSeriesInfo(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	episodes := info.Episodes["1"]
+	if len(episodes) != 2 {
+		t.Fatalf("expected 2 episodes in season 1, got %d", len(episodes))
+	}
+	if episodes[1].ID.Int() != 702 || episodes[1].EpisodeNum.Int() != 2 || episodes[1].Season.Int() != 1 {
+		t.Errorf("unexpected episode: %+v", episodes[1])
+	}
+}
+
 func TestClient_StreamURLs(t *testing.T) {
 	client := NewClient("http://example.com:8080", "user", "pass")
 
@@ -186,11 +286,31 @@ func TestClient_StreamURLs(t *testing.T) {
 			method:   func() string { return client.GetLiveStreamURL(123, "") },
 			expected: "http://example.com:8080/live/user/pass/123.ts",
 		},
+		{
+			name:     "VOD stream",
+			method:   func() string { return client.GetVODStreamURL(42, "mkv") },
+			expected: "http://example.com:8080/movie/user/pass/42.mkv",
+		},
+		{
+			name:     "VOD stream default ext",
+			method:   func() string { return client.GetVODStreamURL(42, "") },
+			expected: "http://example.com:8080/movie/user/pass/42.mp4",
+		},
+		{
+			name:     "series episode",
+			method:   func() string { return client.GetSeriesEpisodeURL(701, "mp4") },
+			expected: "http://example.com:8080/series/user/pass/701.mp4",
+		},
 		{
 			name:     "XMLTV URL",
 			method:   client.GetXMLTVURL,
 			expected: "http://example.com:8080/xmltv.php?username=user&password=pass",
 		},
+		{
+			name:     "timeshift template",
+			method:   func() string { return client.GetTimeshiftURLTemplate(123) },
+			expected: "http://example.com:8080/streaming/timeshift.php?username=user&password=pass&stream=123&start={Y}-{m}-{d}:{H}-{M}&duration={duration:60}",
+		},
 	}
 
 	for _, tt := range tests {
@@ -203,6 +323,57 @@ func TestClient_StreamURLs(t *testing.T) {
 	}
 }
 
+func TestReplaceCredentials(t *testing.T) {
+	client := NewClient("http://example.com:8080", "user", "pass")
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "live stream",
+			url:      client.GetLiveStreamURL(123, "ts"),
+			expected: "http://example.com:8080/live/second/secret/123.ts",
+			ok:       true,
+		},
+		{
+			name:     "series episode",
+			url:      client.GetSeriesEpisodeURL(701, "mp4"),
+			expected: "http://example.com:8080/series/second/secret/701.mp4",
+			ok:       true,
+		},
+		{
+			name:     "timeshift template",
+			url:      client.GetTimeshiftURLTemplate(123),
+			expected: "http://example.com:8080/streaming/timeshift.php?username=second&password=secret&stream=123&start={Y}-{m}-{d}:{H}-{M}&duration={duration:60}",
+			ok:       true,
+		},
+		{
+			name:     "other credentials",
+			url:      "http://example.com:8080/live/other/pass/123.ts",
+			expected: "http://example.com:8080/live/other/pass/123.ts",
+			ok:       false,
+		},
+		{
+			name:     "only username in query",
+			url:      "http://example.com:8080/get.php?username=user&password=other",
+			expected: "http://example.com:8080/get.php?username=user&password=other",
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := ReplaceCredentials(tt.url, "user", "pass", "second", "secret")
+			if ok != tt.ok || result != tt.expected {
+				t.Errorf("expected %q, %v; got %q, %v", tt.expected, tt.ok, result, ok)
+			}
+		})
+	}
+}
+
 func TestClient_ErrorHandling(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -36,6 +36,9 @@
 //	// Series episode URL
 //	url := client.GetSeriesStreamURL(11111, "mkv")
 //
+//	// Catch-up archive URL template for streams with tv_archive enabled
+//	tmpl := client.GetTimeshiftURLTemplate(12345)
+//
 // # API Reference
 //
 // This client is based on the Xtream Codes API documentation and implementations:
@@ -66,4 +69,5 @@
 //   - {baseURL}/live/{user}/{pass}/{streamID}.{ext}: Live stream
 //   - {baseURL}/movie/{user}/{pass}/{vodID}.{ext}: VOD stream
 //   - {baseURL}/series/{user}/{pass}/{episodeID}.{ext}: Series episode
+//   - {baseURL}/streaming/timeshift.php?username={user}&password={pass}&stream={streamID}&start={YYYY-MM-DD:HH-MM}&duration={minutes}: Catch-up archive
 package xtream
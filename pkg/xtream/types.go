@@ -88,6 +88,11 @@ type Stream struct {
 	ContainerExtension string     `json:"container_extension,omitempty"`
 }
 
+// HasArchive reports whether the stream has a catch-up archive on the server.
+func (s *Stream) HasArchive() bool {
+	return s.TVArchive.Int() == 1 && s.TVArchiveDays.Int() > 0
+}
+
 // AddedTime returns the time the stream was added.
 func (s *Stream) AddedTime() time.Time {
 	if s.Added.Int() == 0 {
@@ -299,6 +299,14 @@ func TestStream_JSON(t *testing.T) {
 	if stream.TVArchive.Int() != 1 {
 		t.Errorf("expected tv_archive 1, got %d", stream.TVArchive.Int())
 	}
+	if !stream.HasArchive() {
+		t.Error("expected HasArchive to be true")
+	}
+
+	stream.TVArchiveDays = 0
+	if stream.HasArchive() {
+		t.Error("expected HasArchive to be false without archive days")
+	}
 }
 
 func TestAuthInfo_JSON(t *testing.T) {